@@ -0,0 +1,559 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/domain/service"
+	"conciliacao-bancaria/internal/infrastructure/cli"
+	"conciliacao-bancaria/internal/infrastructure/database"
+	"conciliacao-bancaria/internal/infrastructure/database/repository"
+	"conciliacao-bancaria/internal/infrastructure/erp"
+	"conciliacao-bancaria/internal/infrastructure/export"
+	httpapi "conciliacao-bancaria/internal/infrastructure/http"
+	"conciliacao-bancaria/internal/infrastructure/http/handler"
+	"conciliacao-bancaria/internal/infrastructure/http/middleware"
+	"conciliacao-bancaria/internal/infrastructure/importer"
+	"conciliacao-bancaria/internal/infrastructure/notification"
+	"conciliacao-bancaria/internal/infrastructure/realtime"
+	"conciliacao-bancaria/internal/infrastructure/report"
+	"conciliacao-bancaria/internal/infrastructure/temporal"
+	"conciliacao-bancaria/internal/infrastructure/webhook"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Subcomandos executados sem servidor HTTP
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "reconcile":
+			if err := cli.RunReconcile(ctx, os.Args[2:], os.Stdin, os.Stdout); err != nil {
+				log.Fatalf("erro na conciliação em lote: %v", err)
+			}
+			return
+		case "loadtest":
+			if err := cli.RunLoadTest(ctx, os.Args[2:], os.Stdout); err != nil {
+				log.Fatalf("erro no teste de carga: %v", err)
+			}
+			return
+		case "selftest":
+			if err := cli.RunSelfTest(ctx, os.Args[2:], os.Stdout); err != nil {
+				log.Fatalf("selftest reprovado: %v", err)
+			}
+			return
+		case "worker":
+			runWorker()
+			return
+		case "consume":
+			runConsume(ctx, os.Args[2:])
+			return
+		case "migrate":
+			runMigrations(ctx, os.Args[2:])
+			return
+		case "serve":
+		default:
+			log.Fatalf("subcomando desconhecido: %s (use serve, reconcile, loadtest, selftest, worker, consume ou migrate)", os.Args[1])
+		}
+	}
+
+	serve(ctx)
+}
+
+// serve inicializa as dependências e sobe a API HTTP
+func serve(ctx context.Context) {
+	conn, err := database.NewConnection()
+	if err != nil {
+		log.Fatalf("erro ao conectar no banco de dados: %v", err)
+	}
+	defer conn.Close()
+
+	shards, err := database.NewShardRouterFromEnv(conn)
+	if err != nil {
+		log.Fatalf("erro ao configurar shards: %v", err)
+	}
+	defer shards.Close()
+
+	// Injeção de falhas nos repositórios, habilitada apenas quando FAULT_INJECTION estiver configurado
+	faults, err := repository.NewFaultInjectorFromEnv()
+	if err != nil {
+		log.Fatalf("erro ao configurar injeção de falhas: %v", err)
+	}
+
+	// Repositórios dos dados de cada tenant, roteados ao shard do tenant e restritos ao escopo de
+	// contas da API key de cada requisição. Boletos e pagamentos de contas inativas não podem ser registrados
+	accountRepo := repository.NewBankAccountRepository(shards)
+	billetRepo := repository.NewActiveAccountBilletRepository(repository.NewScopedBilletRepository(
+		repository.NewFaultyBilletRepository(repository.NewBilletRepository(shards), faults)), accountRepo)
+	paymentRepo := repository.NewActiveAccountPaymentRepository(repository.NewScopedPaymentRepository(
+		repository.NewFaultyPaymentRepository(repository.NewPaymentRepository(shards), faults)), accountRepo)
+	reconciliationRepo := repository.NewScopedReconciliationRepository(
+		repository.NewFaultyReconciliationRepository(repository.NewReconciliationRepository(shards), faults))
+	claimRepo := repository.NewBilletClaimRepository(shards)
+	closingRepo := repository.NewDailyClosingRepository(shards)
+	runRepo := repository.NewReconciliationRunRepository(shards)
+	pendingSnapshotRepo := repository.NewPendingSnapshotRepository(shards)
+	timelineRepo := repository.NewTimelineRepository(shards)
+	creditRepo := repository.NewUnappliedCreditRepository(shards)
+	rankerRepo := repository.NewRankerRepository(shards)
+	ruleSetRepo := repository.NewRuleSetRepository(shards)
+	matchReviewRepo := repository.NewMatchReviewRepository(shards)
+	importFileRepo := repository.NewImportFileRepository(shards)
+	erpDriftRepo := repository.NewERPDriftRepository(shards)
+	erpSyncRepo := repository.NewERPSyncRepository(shards)
+
+	// Repositórios compartilhados entre tenants (API keys, assinaturas, outbox, templates de notificação e
+	// relatórios da manutenção), no shard padrão
+	subscriptionRepo := repository.NewSubscriptionRepository(shards.Default())
+	notificationTemplateRepo := repository.NewNotificationTemplateRepository(shards.Default())
+	apiKeyRepo := repository.NewAPIKeyRepository(shards.Default())
+	deliveryRepo := repository.NewEventDeliveryRepository(shards.Default())
+	maintenanceReportRepo := repository.NewMaintenanceReportRepository(shards.Default())
+
+	// Serviços e casos de uso
+	bankRules := bankRulesFromEnv()
+	reconciliationService := reconciliationServiceFromEnv(bankRules)
+	billetUseCase := usecase.NewBilletUseCase(billetRepo, timelineRepo)
+	paymentUseCase := usecase.NewPaymentUseCase(paymentRepo, timelineRepo)
+	dispatcher := webhook.NewDispatcher(subscriptionRepo, deliveryRepo)
+	statsHub := realtime.NewStatsHub()
+	publishers := []service.EventPublisher{dispatcher, statsHub}
+
+	// Notificações por e-mail, Slack, webhook e SMS, habilitadas quando NOTIFICATIONS estiver configurado
+	if notifier := notification.NewRouterFromEnv(notificationTemplateRepo); notifier != nil {
+		defer notifier.Close()
+		publishers = append(publishers, notifier)
+	}
+
+	// Os eventos do ambiente sandbox não chegam aos assinantes, às notificações nem ao painel em tempo real
+	eventPublisher := service.NewProductionEventPublisher(service.NewMultiEventPublisher(publishers...))
+	exportUseCase := exportUseCaseFromEnv(shards)
+	shadowUseCase := shadowUseCaseFromEnv(shards, bankRules)
+	reconciliationUseCase := usecase.NewReconciliationUseCase(billetRepo, paymentRepo, reconciliationRepo, claimRepo, closingRepo, runRepo, timelineRepo, accountRepo, creditRepo, rankerRepo, ruleSetRepo, reconciliationService, bankRules, eventPublisher, exportUseCase, shadowUseCase)
+	closingUseCase := usecase.NewClosingUseCase(closingRepo, reconciliationRepo, eventPublisher)
+	lookupUseCase := usecase.NewLookupUseCase(billetRepo, paymentRepo)
+	bankAccountUseCase := usecase.NewBankAccountUseCase(accountRepo)
+	ruleSetUseCase := usecase.NewRuleSetUseCase(ruleSetRepo)
+	notificationTemplateUseCase := usecase.NewNotificationTemplateUseCase(notificationTemplateRepo)
+	workingPaperUseCase := usecase.NewWorkingPaperUseCase(billetRepo, paymentRepo, reconciliationRepo)
+	timelineUseCase := usecase.NewTimelineUseCase(billetRepo, paymentRepo, reconciliationRepo, deliveryRepo, timelineRepo)
+	pendingReviewUseCase := usecase.NewPendingReviewUseCase(reconciliationUseCase, billetRepo, pendingSnapshotRepo, eventPublisher)
+	subscriptionUseCase := usecase.NewSubscriptionUseCase(subscriptionRepo)
+	rankerUseCase := usecase.NewRankerUseCase(billetRepo, paymentRepo, matchReviewRepo, rankerRepo)
+	outboxUseCase := usecase.NewOutboxUseCase(deliveryRepo)
+	erpDriftUseCase := usecase.NewERPDriftUseCase(erpBilletSourceFromEnv(), billetRepo, erpDriftRepo, eventPublisher)
+	erpSyncUseCase := usecase.NewERPSyncUseCase(erpBilletFeedFromEnv(), billetRepo, timelineRepo, erpSyncRepo)
+	// A manutenção roda no worker; a API apenas consulta os relatórios gravados
+	maintenanceUseCase := usecase.NewMaintenanceUseCase(nil, maintenanceReportRepo, retentionPolicyFromEnv())
+	importUseCase := usecase.NewImportUseCase(paymentRepo, importFileRepo, timelineRepo, eventPublisher, importer.TotalsPolicyFromEnv())
+
+	// Envio em segundo plano das entregas de eventos gravadas no outbox
+	go dispatcher.Run(ctx)
+
+	// As chaves de API_KEYS dão o acesso inicial para cadastrar as chaves gerenciadas. A autenticação
+	// é sempre exigida; AUTH_DISABLED=true a desliga explicitamente, apenas para desenvolvimento local
+	staticAPIKeys := middleware.LoadAPIKeysFromEnv()
+	apiKeyUseCase := usecase.NewAPIKeyUseCase(apiKeyRepo, staticAPIKeys)
+
+	var apiKeyAuthenticator middleware.APIKeyAuthenticator = apiKeyUseCase
+	if disabled, _ := strconv.ParseBool(os.Getenv("AUTH_DISABLED")); disabled {
+		log.Println("AUTH_DISABLED habilitado: as rotas da API não exigem API key")
+		apiKeyAuthenticator = nil
+	}
+
+	// Layout do relatório regulatório, também usado no relatório dos fechamentos diários
+	reportTemplate := report.TemplateFromEnv()
+
+	// Handlers e rotas
+	router := httpapi.SetupRouter(
+		handler.NewBilletHandler(billetUseCase),
+		handler.NewPaymentHandler(paymentUseCase),
+		handler.NewReconciliationHandler(reconciliationUseCase),
+		handler.NewSubscriptionHandler(subscriptionUseCase),
+		handler.NewRankerHandler(rankerUseCase),
+		handler.NewAPIKeyHandler(apiKeyUseCase),
+		handler.NewImportHandler(importUseCase, importer.LimitsFromEnv()),
+		handler.NewOutboxHandler(outboxUseCase),
+		handler.NewStatsHandler(statsHub, middleware.LoadCORSConfigFromEnv()),
+		handler.NewReportHandler(reconciliationUseCase, reportTemplate),
+		handler.NewClosingHandler(closingUseCase, reportTemplate),
+		handler.NewPendingReviewHandler(pendingReviewUseCase),
+		handler.NewTimelineHandler(timelineUseCase),
+		handler.NewLookupHandler(lookupUseCase),
+		handler.NewBankAccountHandler(bankAccountUseCase),
+		handler.NewUnappliedCreditHandler(reconciliationUseCase),
+		handler.NewExportHandler(exportUseCase),
+		handler.NewRuleSetHandler(ruleSetUseCase),
+		handler.NewNotificationTemplateHandler(notificationTemplateUseCase),
+		handler.NewWorkingPaperHandler(workingPaperUseCase, report.WorkingPaperTemplateFromEnv(), report.SignerFromEnv()),
+		handler.NewSelfTestHandler(),
+		handler.NewERPDriftHandler(erpDriftUseCase),
+		handler.NewERPSyncHandler(erpSyncUseCase),
+		handler.NewMaintenanceHandler(maintenanceUseCase),
+		apiKeyAuthenticator,
+	)
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	// Listener separado com certificado de cliente obrigatório para integrações banco-a-banco
+	if mtlsConfig, enabled := httpapi.MTLSConfigFromEnv(); enabled {
+		mtlsServer, err := httpapi.NewMTLSServer(router, mtlsConfig)
+		if err != nil {
+			log.Fatalf("erro ao configurar listener mTLS: %v", err)
+		}
+
+		go func() {
+			log.Printf("listener mTLS ouvindo na porta %s", mtlsConfig.Port)
+			if err := mtlsServer.ListenAndServeTLS(mtlsConfig.CertFile, mtlsConfig.KeyFile); err != nil {
+				log.Fatalf("erro no listener mTLS: %v", err)
+			}
+		}()
+	}
+
+	log.Printf("API de conciliação ouvindo na porta %s", port)
+	if err := router.Run(":" + port); err != nil {
+		log.Fatalf("erro ao iniciar servidor HTTP: %v", err)
+	}
+}
+
+// runWorker inicializa as dependências e executa o worker Temporal do fluxo de conciliação
+func runWorker() {
+	conn, err := database.NewConnection()
+	if err != nil {
+		log.Fatalf("erro ao conectar no banco de dados: %v", err)
+	}
+	defer conn.Close()
+
+	shards, err := database.NewShardRouterFromEnv(conn)
+	if err != nil {
+		log.Fatalf("erro ao configurar shards: %v", err)
+	}
+	defer shards.Close()
+
+	faults, err := repository.NewFaultInjectorFromEnv()
+	if err != nil {
+		log.Fatalf("erro ao configurar injeção de falhas: %v", err)
+	}
+
+	bankRules := bankRulesFromEnv()
+	billetRepo := repository.NewFaultyBilletRepository(repository.NewBilletRepository(shards), faults)
+	publishers := []service.EventPublisher{
+		webhook.NewDispatcher(repository.NewSubscriptionRepository(shards.Default()), repository.NewEventDeliveryRepository(shards.Default())),
+	}
+
+	// Notificações dos relatórios gerados pelo worker, habilitadas quando NOTIFICATIONS estiver configurado
+	if notifier := notification.NewRouterFromEnv(repository.NewNotificationTemplateRepository(shards.Default())); notifier != nil {
+		defer notifier.Close()
+		publishers = append(publishers, notifier)
+	}
+
+	// Como na API, os eventos das execuções do ambiente sandbox não chegam aos assinantes nem às notificações
+	eventPublisher := service.NewProductionEventPublisher(service.NewMultiEventPublisher(publishers...))
+	reconciliationUseCase := usecase.NewReconciliationUseCase(
+		billetRepo,
+		repository.NewFaultyPaymentRepository(repository.NewPaymentRepository(shards), faults),
+		repository.NewFaultyReconciliationRepository(repository.NewReconciliationRepository(shards), faults),
+		repository.NewBilletClaimRepository(shards),
+		repository.NewDailyClosingRepository(shards),
+		repository.NewReconciliationRunRepository(shards),
+		repository.NewTimelineRepository(shards),
+		repository.NewBankAccountRepository(shards),
+		repository.NewUnappliedCreditRepository(shards),
+		repository.NewRankerRepository(shards),
+		repository.NewRuleSetRepository(shards),
+		reconciliationServiceFromEnv(bankRules),
+		bankRules,
+		eventPublisher,
+		exportUseCaseFromEnv(shards),
+		shadowUseCaseFromEnv(shards, bankRules),
+	)
+
+	activities := temporal.NewActivities(nil, nil, reconciliationUseCase)
+
+	// Relatório regulatório mensal, habilitado quando REGULATORY_REPORT_DIR estiver configurado
+	var reportActivities *temporal.ReportActivities
+	if dir := os.Getenv("REGULATORY_REPORT_DIR"); dir != "" {
+		reportActivities = temporal.NewReportActivities(reconciliationUseCase, report.TemplateFromEnv(), dir)
+	}
+
+	// Revisão noturna das pendências, agendada por PENDING_REVIEW_CRON (padrão 02:00)
+	pendingActivities := temporal.NewPendingReviewActivities(usecase.NewPendingReviewUseCase(
+		reconciliationUseCase, billetRepo, repository.NewPendingSnapshotRepository(shards), eventPublisher))
+
+	// Rotina de manutenção, agendada por MAINTENANCE_CRON (padrão 03:00). Cada shard é expurgado
+	// separadamente, pois a manutenção não é feita em nome de um tenant; os relatórios ficam no shard padrão
+	maintenanceRepos := make(map[string]domainRepo.MaintenanceRepository)
+	for _, shard := range shards.Shards() {
+		maintenanceRepos[shard.Name] = repository.NewMaintenanceRepository(shard)
+	}
+	maintenanceActivities := temporal.NewMaintenanceActivities(
+		usecase.NewMaintenanceUseCase(maintenanceRepos, repository.NewMaintenanceReportRepository(shards.Default()), retentionPolicyFromEnv()))
+
+	// Verificação de divergências entre os boletos em aberto do ERP e a base local, agendada por
+	// ERP_DRIFT_CRON (padrão a cada 6 horas) e habilitada quando ERP_OPEN_BILLETS_URL estiver configurado
+	var erpDriftActivities *temporal.ERPDriftActivities
+	if source := erpBilletSourceFromEnv(); source != nil {
+		erpDriftActivities = temporal.NewERPDriftActivities(usecase.NewERPDriftUseCase(
+			source, billetRepo, repository.NewERPDriftRepository(shards), eventPublisher))
+	}
+
+	// Importação delta dos boletos criados ou alterados no ERP, no intervalo de ERP_SYNC_INTERVAL (padrão
+	// 5 minutos) e habilitada quando ERP_CHANGED_BILLETS_URL estiver configurado
+	var erpSyncActivities *temporal.ERPSyncActivities
+	if feed := erpBilletFeedFromEnv(); feed != nil {
+		erpSyncActivities = temporal.NewERPSyncActivities(usecase.NewERPSyncUseCase(
+			feed, billetRepo, repository.NewTimelineRepository(shards), repository.NewERPSyncRepository(shards)))
+	}
+
+	if err := temporal.RunWorker(activities, reportActivities, pendingActivities, maintenanceActivities, erpDriftActivities, erpSyncActivities); err != nil {
+		log.Fatalf("erro no worker Temporal: %v", err)
+	}
+}
+
+// runConsume inicializa as dependências e consome as mensagens de pagamento da fila entregues pelo
+// adaptador do broker no stdin. Os pagamentos passam pelas mesmas regras da API: contas inativas e IDs
+// repetidos são rejeitados
+func runConsume(ctx context.Context, args []string) {
+	conn, err := database.NewConnection()
+	if err != nil {
+		log.Fatalf("erro ao conectar no banco de dados: %v", err)
+	}
+	defer conn.Close()
+
+	shards, err := database.NewShardRouterFromEnv(conn)
+	if err != nil {
+		log.Fatalf("erro ao configurar shards: %v", err)
+	}
+	defer shards.Close()
+
+	faults, err := repository.NewFaultInjectorFromEnv()
+	if err != nil {
+		log.Fatalf("erro ao configurar injeção de falhas: %v", err)
+	}
+
+	paymentRepo := repository.NewActiveAccountPaymentRepository(
+		repository.NewFaultyPaymentRepository(repository.NewPaymentRepository(shards), faults), repository.NewBankAccountRepository(shards))
+	paymentUseCase := usecase.NewPaymentUseCase(paymentRepo, repository.NewTimelineRepository(shards))
+
+	if err := cli.RunConsume(ctx, args, paymentUseCase, os.Stdin, os.Stdout); err != nil {
+		log.Fatalf("erro no consumo de mensagens de pagamento: %v", err)
+	}
+}
+
+// reconciliationServiceFromEnv cria o serviço de conciliação com o valor mínimo de pagamento da
+// conciliação automática lido de MIN_AUTO_RECONCILE_AMOUNT (padrão R$ 1,00), as regras por banco, as
+// regras por meio de pagamento e as contas conciliadas em paralelo de RECONCILE_CONCURRENCY
+func reconciliationServiceFromEnv(bankRules model.BankRules) service.ReconciliationService {
+	return service.NewReconciliationServiceWithMethodRules(service.TolerancePercentage, minAutoReconcileAmountFromEnv(),
+		bankRules, paymentMethodRulesFromEnv(), reconcileConcurrencyFromEnv())
+}
+
+// reconcileConcurrencyFromEnv lê de RECONCILE_CONCURRENCY quantas contas bancárias são conciliadas em
+// paralelo (padrão 1); acima de 1, cada conta é conciliada separadamente
+func reconcileConcurrencyFromEnv() int {
+	value := os.Getenv("RECONCILE_CONCURRENCY")
+	if value == "" {
+		return 1
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 1 {
+		log.Fatalf("RECONCILE_CONCURRENCY inválido: %q", value)
+	}
+	return parsed
+}
+
+// minAutoReconcileAmountFromEnv lê o valor mínimo de pagamento da conciliação automática de
+// MIN_AUTO_RECONCILE_AMOUNT, retornando o padrão quando ausente
+func minAutoReconcileAmountFromEnv() float64 {
+	value := os.Getenv("MIN_AUTO_RECONCILE_AMOUNT")
+	if value == "" {
+		return service.MinAutoReconcileAmount
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed < 0 {
+		log.Fatalf("MIN_AUTO_RECONCILE_AMOUNT inválido: %q", value)
+	}
+	return parsed
+}
+
+// shadowUseCaseFromEnv cria o shadow mode, habilitado quando SHADOW_MODE=true. O motor shadow, identificado
+// por SHADOW_ENGINE_VERSION (padrão v2), usa a tolerância de SHADOW_TOLERANCE e a ordem de estratégias de
+// SHADOW_STRATEGIES (lista separada por vírgula); sem elas, usa as mesmas da produção, assim como as contas
+// conciliadas em paralelo
+func shadowUseCaseFromEnv(db database.DB, bankRules model.BankRules) *usecase.ShadowUseCase {
+	shadowRepo := repository.NewShadowReconciliationRepository(db)
+
+	enabled, _ := strconv.ParseBool(os.Getenv("SHADOW_MODE"))
+	if !enabled {
+		return usecase.NewShadowUseCase(shadowRepo, nil, "", nil)
+	}
+
+	tolerance := service.TolerancePercentage
+	if value := os.Getenv("SHADOW_TOLERANCE"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 || parsed > 100 {
+			log.Fatalf("SHADOW_TOLERANCE inválido: %q", value)
+		}
+		tolerance = parsed
+	}
+
+	var strategies []model.StrategyConfig
+	for _, name := range strings.Split(os.Getenv("SHADOW_STRATEGIES"), ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if !model.IsAutomaticStrategy(model.ConciliationStrategy(name)) {
+			log.Fatalf("SHADOW_STRATEGIES inválido: estratégia desconhecida %q", name)
+		}
+		strategies = append(strategies, model.StrategyConfig{Strategy: model.ConciliationStrategy(name)})
+	}
+
+	engine := service.NewReconciliationServiceWithMethodRules(tolerance, minAutoReconcileAmountFromEnv(), bankRules, paymentMethodRulesFromEnv(),
+		reconcileConcurrencyFromEnv())
+	return usecase.NewShadowUseCase(shadowRepo, engine, os.Getenv("SHADOW_ENGINE_VERSION"), strategies)
+}
+
+// exportUseCaseFromEnv cria o envio dos arquivos de resultado das execuções no layout de RESULT_EXPORT_FORMAT
+// (csv ou cnab240, padrão csv). O envio automático ao SFTP do ERP é habilitado quando RESULT_EXPORT_SFTP_ADDRESS estiver configurado
+func exportUseCaseFromEnv(db database.DB) *usecase.ExportUseCase {
+	generator, err := export.GeneratorFromEnv()
+	if err != nil {
+		log.Fatalf("RESULT_EXPORT_FORMAT inválido: %v", err)
+	}
+
+	var sender service.FileSender
+	if sftpSender := export.NewSFTPSenderFromEnv(); sftpSender != nil {
+		sender = sftpSender
+	}
+
+	return usecase.NewExportUseCase(
+		repository.NewResultExportRepository(db),
+		repository.NewReconciliationRunRepository(db),
+		repository.NewBilletRepository(db),
+		repository.NewPaymentRepository(db),
+		generator,
+		sender,
+	)
+}
+
+// erpBilletSourceFromEnv cria a consulta aos boletos em aberto no ERP a partir de ERP_OPEN_BILLETS_URL.
+// Retorna nil quando a API do ERP não está configurada
+func erpBilletSourceFromEnv() service.ERPBilletSource {
+	if client := erp.ClientFromEnv(); client != nil {
+		return client
+	}
+	return nil
+}
+
+// erpBilletFeedFromEnv cria a consulta aos boletos criados ou alterados no ERP a partir de
+// ERP_CHANGED_BILLETS_URL. Retorna nil quando o endpoint não está configurado
+func erpBilletFeedFromEnv() service.ERPBilletFeed {
+	if client := erp.ChangesClientFromEnv(); client != nil {
+		return client
+	}
+	return nil
+}
+
+// bankRulesFromEnv lê as regras por banco de BANK_RULES, um JSON indexado pelo código do banco,
+// por exemplo {"341":{"credit_delay_days":1}}. Sem a variável, nenhum banco tem prazo de crédito
+func bankRulesFromEnv() model.BankRules {
+	value := os.Getenv("BANK_RULES")
+	if value == "" {
+		return nil
+	}
+
+	var rules model.BankRules
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		log.Fatalf("BANK_RULES inválido: %v", err)
+	}
+	return rules
+}
+
+// paymentMethodRulesFromEnv lê as regras por meio de pagamento de PAYMENT_METHOD_RULES, um JSON indexado
+// pelo meio de pagamento, por exemplo {"pix":{"tolerance":0,"max_days_diff":1}}. Cada meio informado
+// substitui a regra padrão; sem a variável, o PIX concilia apenas no mesmo dia e sem diferença de valor
+func paymentMethodRulesFromEnv() model.PaymentMethodRules {
+	rules := model.DefaultPaymentMethodRules()
+
+	value := os.Getenv("PAYMENT_METHOD_RULES")
+	if value == "" {
+		return rules
+	}
+
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		log.Fatalf("PAYMENT_METHOD_RULES inválido: %v", err)
+	}
+	if err := rules.Validate(); err != nil {
+		log.Fatalf("PAYMENT_METHOD_RULES inválido: %v", err)
+	}
+	return rules
+}
+
+// retentionPolicyFromEnv lê a política de retenção da manutenção de RETENTION_RUNS_DAYS (padrão 90),
+// RETENTION_IDEMPOTENCY_HOURS (padrão 168), RETENTION_DELIVERIES_DAYS (padrão 30), RETENTION_SNAPSHOTS_DAYS
+// (padrão 400), RETENTION_MESSAGES_DAYS (padrão 14), RETENTION_AUDIT_DAYS (padrão 1825) e
+// RETENTION_PAYER_DATA_DAYS (padrão 730, contados da quitação). Zero mantém os registros indefinidamente
+func retentionPolicyFromEnv() model.RetentionPolicy {
+	policy := model.DefaultRetentionPolicy()
+	policy.Runs = retentionFromEnv("RETENTION_RUNS_DAYS", 24*time.Hour, policy.Runs)
+	policy.IdempotencyKeys = retentionFromEnv("RETENTION_IDEMPOTENCY_HOURS", time.Hour, policy.IdempotencyKeys)
+	policy.EventDeliveries = retentionFromEnv("RETENTION_DELIVERIES_DAYS", 24*time.Hour, policy.EventDeliveries)
+	policy.PendingSnapshots = retentionFromEnv("RETENTION_SNAPSHOTS_DAYS", 24*time.Hour, policy.PendingSnapshots)
+	policy.ProcessedMessages = retentionFromEnv("RETENTION_MESSAGES_DAYS", 24*time.Hour, policy.ProcessedMessages)
+	policy.AuditTrail = retentionFromEnv("RETENTION_AUDIT_DAYS", 24*time.Hour, policy.AuditTrail)
+	policy.PayerData = retentionFromEnv("RETENTION_PAYER_DATA_DAYS", 24*time.Hour, policy.PayerData)
+	return policy
+}
+
+// retentionFromEnv lê uma retenção inteira na unidade informada, retornando o padrão quando ausente
+func retentionFromEnv(key string, unit, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		log.Fatalf("%s inválido: %q", key, value)
+	}
+	return time.Duration(parsed) * unit
+}
+
+// runMigrations aplica o script de schema (informado como argumento ou o padrão) em todos os shards
+func runMigrations(ctx context.Context, args []string) {
+	path := database.DefaultSchemaFile
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	conn, err := database.NewConnection()
+	if err != nil {
+		log.Fatalf("erro ao conectar no banco de dados: %v", err)
+	}
+	defer conn.Close()
+
+	shards, err := database.NewShardRouterFromEnv(conn)
+	if err != nil {
+		log.Fatalf("erro ao configurar shards: %v", err)
+	}
+	defer shards.Close()
+
+	if _, err := database.MigrateShardsFromFile(ctx, shards, path); err != nil {
+		log.Fatalf("erro na migração: %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// lastUsedResolution define o intervalo mínimo entre registros de último uso de uma mesma chave,
+// evitando uma escrita no banco a cada requisição
+const lastUsedResolution = time.Minute
+
+// APIKeyUseCase implementa os casos de uso de gerenciamento e autenticação de API keys
+type APIKeyUseCase struct {
+	apiKeyRepository repository.APIKeyRepository
+	staticKeys       map[string]model.AccessScope
+}
+
+// NewAPIKeyUseCase cria uma nova instância do APIKeyUseCase. As chaves estáticas (configuradas
+// por ambiente) continuam aceitas e servem de acesso inicial para cadastrar as chaves gerenciadas
+func NewAPIKeyUseCase(apiKeyRepo repository.APIKeyRepository, staticKeys map[string]model.AccessScope) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		apiKeyRepository: apiKeyRepo,
+		staticKeys:       staticKeys,
+	}
+}
+
+// CreateAPIKey cria uma API key vinculada ao tenant, retornando o segredo em texto claro, que não pode
+// ser recuperado depois
+func (uc *APIKeyUseCase) CreateAPIKey(ctx context.Context, tenant, name string, permissions []model.APIKeyPermission, bankAccounts []string, expiresAt *time.Time) (*model.APIKey, string, error) {
+	if tenant == "" {
+		return nil, "", errors.NewValidationError("tenant", "tenant da API key é obrigatório")
+	}
+
+	if name == "" {
+		return nil, "", errors.NewValidationError("name", "nome da API key é obrigatório")
+	}
+
+	if len(permissions) == 0 {
+		return nil, "", errors.NewValidationError("permissions", "informe ao menos um escopo")
+	}
+
+	for _, permission := range permissions {
+		if !model.IsKnownAPIKeyPermission(permission) {
+			return nil, "", errors.NewValidationError("permissions", fmt.Sprintf("escopo desconhecido: %s", permission))
+		}
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", errors.NewValidationError("expires_at", "data de expiração deve ser futura")
+	}
+
+	apiKey, secret, err := model.NewAPIKey(name, tenant, permissions, bankAccounts, expiresAt)
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao gerar API key: %w", err)
+	}
+
+	if err := uc.apiKeyRepository.Create(ctx, apiKey); err != nil {
+		return nil, "", errors.NewDatabaseError("criar API key", err)
+	}
+
+	return apiKey, secret, nil
+}
+
+// ListAPIKeys lista as API keys gerenciadas do tenant com o último uso de cada uma
+func (uc *APIKeyUseCase) ListAPIKeys(ctx context.Context, tenant string) ([]*model.APIKey, error) {
+	apiKeys, err := uc.apiKeyRepository.List(ctx, tenant)
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar API keys", err)
+	}
+
+	return apiKeys, nil
+}
+
+// GetAPIKey busca uma API key do tenant pelo ID; as chaves de outros tenants não são encontradas
+func (uc *APIKeyUseCase) GetAPIKey(ctx context.Context, tenant, id string) (*model.APIKey, error) {
+	if id == "" {
+		return nil, errors.NewValidationError("id", "ID da API key não pode ser vazio")
+	}
+
+	apiKey, err := uc.apiKeyRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKey.Tenant != tenant {
+		return nil, errors.NewNotFoundError("API key", id)
+	}
+
+	return apiKey, nil
+}
+
+// RevokeAPIKey revoga uma API key do tenant; a revogação vale a partir da próxima requisição
+func (uc *APIKeyUseCase) RevokeAPIKey(ctx context.Context, tenant, id string) error {
+	if _, err := uc.GetAPIKey(ctx, tenant, id); err != nil {
+		return err
+	}
+
+	return uc.apiKeyRepository.Revoke(ctx, id, time.Now())
+}
+
+// RotateAPIKey emite uma nova chave com o mesmo tenant, escopos, contas e expiração. A chave anterior
+// continua válida durante o período de carência informado, ou é revogada imediatamente
+func (uc *APIKeyUseCase) RotateAPIKey(ctx context.Context, tenant, id string, gracePeriod time.Duration) (*model.APIKey, string, error) {
+	if gracePeriod < 0 {
+		return nil, "", errors.NewValidationError("grace_period_seconds", "período de carência não pode ser negativo")
+	}
+
+	current, err := uc.GetAPIKey(ctx, tenant, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	if !current.IsActive(now) {
+		return nil, "", errors.NewValidationError("id", "API key revogada ou expirada não pode ser rotacionada")
+	}
+
+	rotated, secret, err := uc.CreateAPIKey(ctx, current.Tenant, current.Name, current.Permissions, current.BankAccounts, current.ExpiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if gracePeriod == 0 {
+		err = uc.apiKeyRepository.Revoke(ctx, current.ID, now)
+	} else if graceEnd := now.Add(gracePeriod); current.ExpiresAt == nil || graceEnd.Before(*current.ExpiresAt) {
+		err = uc.apiKeyRepository.UpdateExpiration(ctx, current.ID, graceEnd)
+	}
+	if err != nil {
+		return nil, "", errors.NewDatabaseError("encerrar API key rotacionada", err)
+	}
+
+	return rotated, secret, nil
+}
+
+// Authenticate valida a chave informada na requisição e retorna o escopo concedido.
+// Chaves revogadas ou expiradas são recusadas
+func (uc *APIKeyUseCase) Authenticate(ctx context.Context, key string) (*model.AccessScope, bool, error) {
+	if key == "" {
+		return nil, false, nil
+	}
+
+	if scope, ok := uc.lookupStaticKey(key); ok {
+		return scope, true, nil
+	}
+
+	apiKey, err := uc.apiKeyRepository.GetByHash(ctx, model.HashAPIKey(key))
+	if err != nil {
+		return nil, false, errors.NewDatabaseError("buscar API key", err)
+	}
+
+	now := time.Now()
+	if apiKey == nil || !apiKey.IsActive(now) {
+		return nil, false, nil
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedResolution {
+		if err := uc.apiKeyRepository.TouchLastUsed(ctx, apiKey.ID, now); err != nil {
+			log.Printf("erro ao registrar uso da API key %s: %v", apiKey.Prefix, err)
+		}
+	}
+
+	return apiKey.AccessScope(), true, nil
+}
+
+// lookupStaticKey procura a chave entre as configuradas por ambiente, comparando em tempo constante
+func (uc *APIKeyUseCase) lookupStaticKey(key string) (*model.AccessScope, bool) {
+	for staticKey, scope := range uc.staticKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(staticKey)) == 1 {
+			scope := scope
+			return &scope, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// BankAccountUseCase implementa os casos de uso do cadastro de contas bancárias. Boletos e pagamentos
+// de contas inativas ficam fora das execuções de conciliação e não podem mais ser registrados
+type BankAccountUseCase struct {
+	accountRepository repository.BankAccountRepository
+}
+
+// NewBankAccountUseCase cria uma nova instância do BankAccountUseCase
+func NewBankAccountUseCase(accountRepo repository.BankAccountRepository) *BankAccountUseCase {
+	return &BankAccountUseCase{
+		accountRepository: accountRepo,
+	}
+}
+
+// ListAccounts lista as contas cadastradas com a sua situação
+func (uc *BankAccountUseCase) ListAccounts(ctx context.Context) ([]*model.BankAccount, error) {
+	accounts, err := uc.accountRepository.GetAll(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar contas bancárias", err)
+	}
+	return accounts, nil
+}
+
+// DeactivateAccount inativa a conta, cadastrando-a quando ainda não existe. O motivo é obrigatório
+func (uc *BankAccountUseCase) DeactivateAccount(ctx context.Context, account, reason, actor string) (*model.BankAccount, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.NewValidationError("reason", "motivo da inativação é obrigatório")
+	}
+
+	bankAccount, err := uc.getOrNew(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	bankAccount.Deactivate(actor, reason)
+	if err := uc.accountRepository.Save(ctx, bankAccount); err != nil {
+		return nil, errors.NewDatabaseError("inativar conta bancária", err)
+	}
+
+	return bankAccount, nil
+}
+
+// ActivateAccount reativa a conta; os boletos e pagamentos dela voltam às próximas execuções
+func (uc *BankAccountUseCase) ActivateAccount(ctx context.Context, account, actor string) (*model.BankAccount, error) {
+	bankAccount, err := uc.getOrNew(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	bankAccount.Activate(actor)
+	if err := uc.accountRepository.Save(ctx, bankAccount); err != nil {
+		return nil, errors.NewDatabaseError("reativar conta bancária", err)
+	}
+
+	return bankAccount, nil
+}
+
+// getOrNew recupera o cadastro da conta ou cria um novo, ainda não gravado, para contas sem cadastro
+func (uc *BankAccountUseCase) getOrNew(ctx context.Context, account string) (*model.BankAccount, error) {
+	if account == "" {
+		return nil, errors.NewValidationError("bank_account", "conta bancária não pode ser vazia")
+	}
+
+	bankAccount, err := uc.accountRepository.GetByAccount(ctx, account)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar conta bancária", err)
+	}
+	if bankAccount == nil {
+		bankAccount = model.NewBankAccount(account)
+	}
+
+	return bankAccount, nil
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"conciliacao-bancaria/internal/domain/model"
@@ -12,20 +13,64 @@ import (
 
 // BilletUseCase implementa os casos de uso relacionados a boletos
 type BilletUseCase struct {
-	billetRepository repository.BilletRepository
+	billetRepository   repository.BilletRepository
+	timelineRepository repository.TimelineRepository
 }
 
 // NewBilletUseCase cria uma nova instância do BilletUseCase
-func NewBilletUseCase(billetRepo repository.BilletRepository) *BilletUseCase {
+func NewBilletUseCase(billetRepo repository.BilletRepository, timelineRepo repository.TimelineRepository) *BilletUseCase {
 	return &BilletUseCase{
-		billetRepository: billetRepo,
+		billetRepository:   billetRepo,
+		timelineRepository: timelineRepo,
 	}
 }
 
 // ImportResult representa o resultado de uma operação de importação em lote
 type ImportResult struct {
-	Imported int      `json:"imported"`
-	Errors   []string `json:"errors,omitempty"`
+	Mode     model.ImportMode        `json:"mode"`
+	Imported int                     `json:"imported"`
+	Failed   int                     `json:"failed"`
+	Aborted  bool                    `json:"aborted,omitempty"` // Importação all_or_nothing desfeita por algum item
+	Items    []model.BatchItemResult `json:"items"`
+}
+
+// newImportResult valida o modo de importação, que por padrão é best_effort nos lotes, e cria o resultado
+func newImportResult(mode model.ImportMode, size int) (*ImportResult, error) {
+	mode = mode.OrDefault(model.ImportModeBestEffort)
+	if !mode.IsValid() {
+		return nil, errors.NewValidationError("mode", "modo de importação deve ser all_or_nothing ou best_effort")
+	}
+
+	return &ImportResult{Mode: mode, Items: make([]model.BatchItemResult, 0, size)}, nil
+}
+
+// abort marca todos os itens do lote como não importados, mantendo o erro de cada item inválido
+// e atribuindo o motivo do aborto aos demais
+func (r *ImportResult) abort(ids []string, itemErrors map[int]error, reason error) {
+	r.Aborted = true
+	r.Imported = 0
+	r.Failed = 0
+	r.Items = r.Items[:0]
+
+	for i, id := range ids {
+		err, ok := itemErrors[i]
+		if !ok {
+			err = reason
+		}
+		r.addItem(i, id, err)
+	}
+}
+
+// addItem registra o resultado de um item do lote
+func (r *ImportResult) addItem(index int, id string, err error) {
+	item := model.BatchItemResult{Index: index, ID: id, Success: err == nil}
+	if err != nil {
+		item.Error = err.Error()
+		r.Failed++
+	} else {
+		r.Imported++
+	}
+	r.Items = append(r.Items, item)
 }
 
 // CreateBillet cria um novo boleto
@@ -35,18 +80,11 @@ func (uc *BilletUseCase) CreateBillet(ctx context.Context, billet *model.Billet)
 		return nil, err
 	}
 
-	// Verificar se já existe um boleto com o mesmo ID
-	existingBillet, err := uc.billetRepository.GetByID(ctx, billet.BilletID)
-	if err != nil && !errors.IsNotFoundError(err) {
-		return nil, errors.NewDatabaseError("verificar existência", err)
-	}
-
-	if existingBillet != nil {
-		return nil, errors.NewConflictError("boleto", billet.BilletID, "boleto com este ID já existe")
-	}
-
-	// Criar boleto no repositório
+	// Criar boleto no repositório; um ID já cadastrado retorna erro de conflito
 	createdBillet, err := uc.billetRepository.Create(ctx, billet)
+	if errors.IsConflictError(err) {
+		return nil, err
+	}
 	if err != nil {
 		return nil, errors.NewDatabaseError("criar", err)
 	}
@@ -82,52 +120,99 @@ func (uc *BilletUseCase) ListBillets(ctx context.Context, params map[string]stri
 	return billets, nil
 }
 
-// ImportBillets importa uma lista de boletos
-func (uc *BilletUseCase) ImportBillets(ctx context.Context, billetsData []interface{}) (*ImportResult, error) {
-	result := &ImportResult{
-		Imported: 0,
-		Errors:   []string{},
+// ImportBillets importa uma lista de boletos. No modo best_effort (padrão) cada item é gravado
+// individualmente e os inválidos ou que falharem são reportados pelo índice no lote; no modo
+// all_or_nothing os boletos são gravados em uma única transação, e qualquer erro aborta o lote inteiro
+func (uc *BilletUseCase) ImportBillets(ctx context.Context, billets []*model.Billet, mode model.ImportMode) (*ImportResult, error) {
+	result, err := newImportResult(mode, len(billets))
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Mode == model.ImportModeAllOrNothing {
+		result = uc.importBilletsAtomically(ctx, billets, result)
+		uc.recordImports(ctx, billets, result)
+		return result, nil
 	}
 
-	// Converter e validar cada boleto
-	billets := make([]*model.Billet, 0, len(billetsData))
-	for i, data := range billetsData {
-		billet, ok := data.(*model.Billet)
-		if !ok {
-			result.Errors = append(result.Errors,
-				"erro na conversão do item "+string(i)+": formato inválido")
+	for i, billet := range billets {
+		if err := validateBatchBillet(billet); err != nil {
+			result.addItem(i, billetID(billet), err)
 			continue
 		}
 
-		if err := validateBillet(billet); err != nil {
-			result.Errors = append(result.Errors,
-				"erro na validação do boleto "+billet.BilletID+": "+err.Error())
-			continue
+		_, err := uc.billetRepository.Create(ctx, billet)
+		if errors.IsConflictError(err) {
+			// Boletos duplicados são ignorados e reportados como erro do item
+			err = errors.NewConflictError("boleto", billet.ID, "boleto já existe e foi ignorado")
+		} else if err != nil {
+			err = errors.NewDatabaseError("salvar boleto", err)
 		}
+		result.addItem(i, billet.ID, err)
+	}
 
-		billets = append(billets, billet)
+	uc.recordImports(ctx, billets, result)
+
+	return result, nil
+}
+
+// recordImports registra na linha do tempo dos boletos gravados a importação em lote
+func (uc *BilletUseCase) recordImports(ctx context.Context, billets []*model.Billet, result *ImportResult) {
+	description := fmt.Sprintf("importado em lote de %d boletos (modo %s)", len(billets), result.Mode)
+
+	entries := make([]*model.TimelineEntry, 0, result.Imported)
+	for _, item := range result.Items {
+		if item.Success {
+			entries = append(entries, model.NewTimelineEntry(model.TimelineBillet, item.ID, model.TimelineImported, description, ""))
+		}
 	}
 
-	// Salvar boletos válidos no repositório
-	for _, billet := range billets {
-		_, err := uc.billetRepository.Create(ctx, billet)
-		if err != nil {
-			if errors.IsConflictError(err) {
-				// Caso já exista, apenas ignoramos ou atualizamos
-				// Neste caso, estamos decidindo por ignorar boletos duplicados
-				result.Errors = append(result.Errors,
-					"boleto "+billet.BilletID+" já existe e foi ignorado")
-			} else {
-				result.Errors = append(result.Errors,
-					"erro ao salvar boleto "+billet.BilletID+": "+err.Error())
-			}
-			continue
+	recordTimeline(ctx, uc.timelineRepository, entries...)
+}
+
+// importBilletsAtomically valida todos os boletos e os grava em uma única transação; qualquer item
+// inválido ou falha na gravação desfaz o lote inteiro
+func (uc *BilletUseCase) importBilletsAtomically(ctx context.Context, billets []*model.Billet, result *ImportResult) *ImportResult {
+	ids := make([]string, len(billets))
+	itemErrors := make(map[int]error)
+	for i, billet := range billets {
+		ids[i] = billetID(billet)
+		if err := validateBatchBillet(billet); err != nil {
+			itemErrors[i] = err
 		}
+	}
 
-		result.Imported++
+	if len(itemErrors) > 0 {
+		result.abort(ids, itemErrors, fmt.Errorf("lote abortado: %d item(ns) inválido(s)", len(itemErrors)))
+		return result
 	}
 
-	return result, nil
+	if err := uc.billetRepository.CreateMany(ctx, billets); err != nil {
+		result.abort(ids, itemErrors, errors.NewDatabaseError("salvar lote de boletos", err))
+		return result
+	}
+
+	for i, id := range ids {
+		result.addItem(i, id, nil)
+	}
+
+	return result
+}
+
+// validateBatchBillet valida um item de um lote de boletos, que pode vir nulo da requisição
+func validateBatchBillet(billet *model.Billet) error {
+	if billet == nil {
+		return errors.NewValidationError("", "boleto não pode ser nulo")
+	}
+	return validateBillet(billet)
+}
+
+// billetID retorna o ID de um item de lote, que pode ser nulo
+func billetID(billet *model.Billet) string {
+	if billet == nil {
+		return ""
+	}
+	return billet.ID
 }
 
 // UpdateBillet atualiza um boleto existente
@@ -144,7 +229,7 @@ func (uc *BilletUseCase) UpdateBillet(ctx context.Context, billet *model.Billet)
 	}
 
 	// Se o boleto já estiver conciliado, não pode ser alterado
-	if existingBillet.ReconciliationID != "" {
+	if existingBillet.IsReconciled() {
 		return nil, errors.NewValidationError("", "boleto já conciliado não pode ser alterado")
 	}
 
@@ -154,6 +239,10 @@ func (uc *BilletUseCase) UpdateBillet(ctx context.Context, billet *model.Billet)
 		return nil, errors.NewDatabaseError("atualizar", err)
 	}
 
+	if changes := model.BilletChanges(existingBillet, billet); len(changes) > 0 {
+		recordTimeline(ctx, uc.timelineRepository, model.NewTimelineEntry(model.TimelineBillet, billet.ID, model.TimelineUpdated, strings.Join(changes, "; "), ""))
+	}
+
 	return updatedBillet, nil
 }
 
@@ -170,7 +259,7 @@ func (uc *BilletUseCase) DeleteBillet(ctx context.Context, billetID string) erro
 	}
 
 	// Se o boleto já estiver conciliado, não pode ser excluído
-	if billet.ReconciliationID != "" {
+	if billet.IsReconciled() {
 		return errors.NewValidationError("", "boleto conciliado não pode ser excluído")
 	}
 
@@ -182,13 +271,47 @@ func (uc *BilletUseCase) DeleteBillet(ctx context.Context, billetID string) erro
 	return nil
 }
 
+// GetBilletsByContract lista os boletos de um contrato
+func (uc *BilletUseCase) GetBilletsByContract(ctx context.Context, contractID string) ([]*model.Billet, error) {
+	if contractID == "" {
+		return nil, errors.NewValidationError("contract_id", "ID do contrato não pode ser vazio")
+	}
+
+	billets, err := uc.billetRepository.GetByContractID(ctx, contractID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar por contrato", err)
+	}
+
+	return billets, nil
+}
+
+// GetContractStatistics calcula valor em aberto, valor pago e taxa de conciliação de um contrato
+func (uc *BilletUseCase) GetContractStatistics(ctx context.Context, contractID string) (*model.ContractStatistics, error) {
+	if contractID == "" {
+		return nil, errors.NewValidationError("contract_id", "ID do contrato não pode ser vazio")
+	}
+
+	stats, err := uc.billetRepository.GetContractStatistics(ctx, contractID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("calcular estatísticas do contrato", err)
+	}
+
+	if stats.TotalBillets == 0 {
+		return nil, errors.NewNotFoundError("contrato", contractID)
+	}
+
+	stats.ReconciliationRate = float64(stats.ReconciledBillets) / float64(stats.TotalBillets) * 100
+
+	return stats, nil
+}
+
 // validateBillet valida os dados de um boleto
 func validateBillet(billet *model.Billet) error {
 	if billet == nil {
 		return errors.NewValidationError("", "boleto não pode ser nulo")
 	}
 
-	if billet.BilletID == "" {
+	if billet.ID == "" {
 		return errors.NewValidationError("billet_id", "ID do boleto é obrigatório")
 	}
 
@@ -196,10 +319,32 @@ func validateBillet(billet *model.Billet) error {
 		return errors.NewValidationError("bank_account", "conta bancária é obrigatória")
 	}
 
-	if billet.Amount <= 0 {
+	if billet.OpenAmount && billet.Amount < 0 {
+		return errors.NewValidationError("amount", "valor não pode ser negativo")
+	}
+
+	if !billet.OpenAmount && billet.Amount <= 0 {
 		return errors.NewValidationError("amount", "valor deve ser maior que zero")
 	}
 
+	if billet.InstallmentNumber != nil && *billet.InstallmentNumber <= 0 {
+		return errors.NewValidationError("installment_number", "número da parcela deve ser maior que zero")
+	}
+
+	if err := validateBilletBarcode(billet); err != nil {
+		return err
+	}
+
+	if billet.PayerDocument != "" {
+		if err := model.ValidatePayerDocument(billet.PayerDocument); err != nil {
+			return errors.NewValidationError("payer_document", err.Error())
+		}
+	}
+
+	if err := validateBilletLateCharges(billet); err != nil {
+		return err
+	}
+
 	// Verificar se a data de emissão é válida (não nula e não futura)
 	if billet.IssuanceDate.IsZero() {
 		return errors.NewValidationError("issuance_date", "data de emissão é obrigatória")
@@ -213,9 +358,57 @@ func validateBillet(billet *model.Billet) error {
 	return nil
 }
 
+// validateBilletBarcode verifica o código de barras e a linha digitável do boleto e, quando ambos são
+// informados, se a linha digitável corresponde ao código de barras
+func validateBilletBarcode(billet *model.Billet) error {
+	if billet.BarCode != "" {
+		if err := model.ValidateBarcode(billet.BarCode); err != nil {
+			return errors.NewValidationError("bar_code", err.Error())
+		}
+	}
+
+	if billet.DigitableLine != "" {
+		barcode, err := model.BarcodeFromDigitableLine(billet.DigitableLine)
+		if err != nil {
+			return errors.NewValidationError("digitable_line", err.Error())
+		}
+		if billet.BarCode != "" && barcode != billet.BarCode {
+			return errors.NewValidationError("digitable_line", "linha digitável não corresponde ao código de barras")
+		}
+	}
+
+	return nil
+}
+
+// validateBilletLateCharges verifica o vencimento e os encargos de atraso: a multa e os juros não podem
+// ser negativos e só se aplicam a boletos com vencimento, a partir da emissão
+func validateBilletLateCharges(billet *model.Billet) error {
+	if billet.InterestRate < 0 || billet.InterestRate > 100 {
+		return errors.NewValidationError("interest_rate", "juros de mora devem estar entre 0 e 100% ao mês")
+	}
+
+	if billet.FinePercent < 0 || billet.FinePercent > 100 {
+		return errors.NewValidationError("fine_percent", "multa deve estar entre 0 e 100%")
+	}
+
+	if billet.DueDate == nil {
+		if billet.InterestRate > 0 || billet.FinePercent > 0 {
+			return errors.NewValidationError("due_date", "vencimento é obrigatório para cobrar multa ou juros")
+		}
+		return nil
+	}
+
+	// O vencimento é uma data: no mesmo dia da emissão, vale mesmo antes do horário da emissão
+	if billet.DueDate.Before(billet.IssuanceDate) && !model.WithinDays(*billet.DueDate, billet.IssuanceDate, 0) {
+		return errors.NewValidationError("due_date", "vencimento não pode ser anterior à emissão")
+	}
+
+	return nil
+}
+
 // createBilletFilter cria um filtro para busca de boletos com base nos parâmetros
-func createBilletFilter(params map[string]string) *model.BilletFilter {
-	filter := &model.BilletFilter{}
+func createBilletFilter(params map[string]string) model.BilletFilter {
+	filter := model.BilletFilter{}
 
 	// Aplicar filtros de parâmetros
 	if bankAccount, ok := params["bank_account"]; ok {
@@ -258,14 +451,14 @@ func createBilletFilter(params map[string]string) *model.BilletFilter {
 
 	// Filtros de paginação
 	if limitStr, ok := params["limit"]; ok {
-		var limit int64
+		var limit int
 		if _, err := fmt.Sscanf(limitStr, "%d", &limit); err == nil && limit > 0 {
 			filter.Limit = limit
 		}
 	}
 
 	if offsetStr, ok := params["offset"]; ok {
-		var offset int64
+		var offset int
 		if _, err := fmt.Sscanf(offsetStr, "%d", &offset); err == nil && offset >= 0 {
 			filter.Offset = offset
 		}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/domain/service"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// ClosingUseCase implementa os casos de uso do fechamento diário das conciliações
+type ClosingUseCase struct {
+	closingRepository        repository.DailyClosingRepository
+	reconciliationRepository repository.ReconciliationRepository
+	eventPublisher           service.EventPublisher
+}
+
+// NewClosingUseCase cria uma nova instância do ClosingUseCase
+func NewClosingUseCase(
+	closingRepo repository.DailyClosingRepository,
+	reconciliationRepo repository.ReconciliationRepository,
+	eventPublisher service.EventPublisher,
+) *ClosingUseCase {
+	return &ClosingUseCase{
+		closingRepository:        closingRepo,
+		reconciliationRepository: reconciliationRepo,
+		eventPublisher:           eventPublisher,
+	}
+}
+
+// OpenClosing abre o fechamento do dia (AAAA-MM-DD), agrupando as conciliações executadas nele
+// numa prévia que ainda acompanha novas execuções até a confirmação
+func (uc *ClosingUseCase) OpenClosing(ctx context.Context, date, actor string) (*model.DailyClosing, error) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil, errors.NewValidationError("date", "data do fechamento deve estar no formato AAAA-MM-DD")
+	}
+
+	if day.After(model.ClosingDay(time.Now())) {
+		return nil, errors.NewValidationError("date", "não é possível abrir o fechamento de um dia futuro")
+	}
+
+	closing := model.NewDailyClosing(day, actor)
+	if err := uc.refreshStatistics(ctx, closing); err != nil {
+		return nil, err
+	}
+
+	created, err := uc.closingRepository.Create(ctx, closing)
+	if err != nil {
+		return nil, errors.NewDatabaseError("criar fechamento diário", err)
+	}
+	if !created {
+		return nil, errors.NewConflictError("fechamento", date, "o dia já possui fechamento")
+	}
+
+	return closing, nil
+}
+
+// GetClosing recupera um fechamento. Fechamentos abertos têm a prévia dos totais atualizada
+func (uc *ClosingUseCase) GetClosing(ctx context.Context, id string) (*model.DailyClosing, error) {
+	closing, err := uc.getClosing(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if closing.IsConfirmed() {
+		return closing, nil
+	}
+
+	if err := uc.refreshStatistics(ctx, closing); err != nil {
+		return nil, err
+	}
+
+	if err := uc.closingRepository.UpdateStatistics(ctx, closing); err != nil {
+		return nil, errors.NewDatabaseError("atualizar prévia do fechamento", err)
+	}
+
+	return closing, nil
+}
+
+// ListClosings lista os fechamentos diários, do mais recente para o mais antigo
+func (uc *ClosingUseCase) ListClosings(ctx context.Context) ([]*model.DailyClosing, error) {
+	closings, err := uc.closingRepository.GetAll(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar fechamentos diários", err)
+	}
+
+	return closings, nil
+}
+
+// ConfirmClosing confirma o fechamento em nome do usuário autorizado, congelando os resultados do dia.
+// A confirmação não pode ser desfeita; os totais finais são gravados no fechamento e o evento de
+// fechamento é publicado aos assinantes
+func (uc *ClosingUseCase) ConfirmClosing(ctx context.Context, id, actor string) (*model.DailyClosing, error) {
+	if actor == "" {
+		return nil, errors.NewValidationError("actor", "usuário responsável pela confirmação é obrigatório")
+	}
+
+	closing, err := uc.getClosing(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if closing.IsConfirmed() {
+		return nil, errors.NewConflictError("fechamento", id, "fechamento já confirmado")
+	}
+
+	if err := uc.refreshStatistics(ctx, closing); err != nil {
+		return nil, err
+	}
+
+	closing.Confirm(actor)
+
+	confirmed, err := uc.closingRepository.Confirm(ctx, closing)
+	if err != nil {
+		return nil, errors.NewDatabaseError("confirmar fechamento diário", err)
+	}
+	if !confirmed {
+		return nil, errors.NewConflictError("fechamento", id, "fechamento já confirmado")
+	}
+
+	uc.publishClosingEvent(ctx, closing)
+
+	return closing, nil
+}
+
+// getClosing recupera um fechamento pelo ID, retornando erro quando não existe
+func (uc *ClosingUseCase) getClosing(ctx context.Context, id string) (*model.DailyClosing, error) {
+	if id == "" {
+		return nil, errors.NewValidationError("id", "ID do fechamento não pode ser vazio")
+	}
+
+	closing, err := uc.closingRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar fechamento diário", err)
+	}
+	if closing == nil {
+		return nil, errors.NewNotFoundError("fechamento", id)
+	}
+
+	return closing, nil
+}
+
+// refreshStatistics recalcula os totais por conta das conciliações executadas no dia do fechamento
+func (uc *ClosingUseCase) refreshStatistics(ctx context.Context, closing *model.DailyClosing) error {
+	statistics, err := uc.reconciliationRepository.GetDailyStatistics(ctx, model.DailyStatisticsFilter{
+		DateField: model.DateFieldReconciliation,
+		StartDate: &closing.Date,
+		EndDate:   &closing.Date,
+	})
+	if err != nil {
+		return errors.NewDatabaseError("calcular totais do fechamento", err)
+	}
+
+	closing.SetStatistics(statistics)
+	return nil
+}
+
+// publishClosingEvent publica o evento de fechamento confirmado; falhas de publicação não desfazem a confirmação
+func (uc *ClosingUseCase) publishClosingEvent(ctx context.Context, closing *model.DailyClosing) {
+	if uc.eventPublisher == nil {
+		return
+	}
+
+	event := model.NewEvent(model.EventDailyClosing, "", closing.TotalPaidAmount())
+	event.Description = fmt.Sprintf("fechamento de %s confirmado por %s com %d conciliações",
+		closing.Date.Format("2006-01-02"), closing.ConfirmedBy, closing.ReconciliationCount)
+
+	if err := uc.eventPublisher.Publish(ctx, []*model.Event{event}); err != nil {
+		log.Printf("erro ao publicar evento de fechamento: %v", err)
+	}
+}
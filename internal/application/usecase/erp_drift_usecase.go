@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/domain/service"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// DefaultERPDriftListLimit define quantos relatórios são listados quando nenhum limite é informado
+const DefaultERPDriftListLimit = 30
+
+// MaxERPDriftListLimit limita a quantidade de relatórios listados por consulta
+const MaxERPDriftListLimit = 500
+
+// ERPDriftUseCase implementa a reconciliação de cadastro: compara os boletos em aberto no ERP com os
+// boletos pendentes da base local e relata as divergências antes do fechamento
+type ERPDriftUseCase struct {
+	source           service.ERPBilletSource
+	billetRepository repository.BilletRepository
+	driftRepository  repository.ERPDriftRepository
+	eventPublisher   service.EventPublisher
+}
+
+// NewERPDriftUseCase cria uma nova instância do ERPDriftUseCase. Sem source, a API do ERP não está
+// configurada e apenas os relatórios já gravados podem ser consultados
+func NewERPDriftUseCase(
+	source service.ERPBilletSource,
+	billetRepo repository.BilletRepository,
+	driftRepo repository.ERPDriftRepository,
+	eventPublisher service.EventPublisher,
+) *ERPDriftUseCase {
+	return &ERPDriftUseCase{
+		source:           source,
+		billetRepository: billetRepo,
+		driftRepository:  driftRepo,
+		eventPublisher:   eventPublisher,
+	}
+}
+
+// Enabled verifica se a API do ERP está configurada
+func (uc *ERPDriftUseCase) Enabled() bool {
+	return uc.source != nil
+}
+
+// CheckDrift consulta os boletos em aberto no ERP, compara com os pendentes da base local, grava o
+// relatório e, havendo divergências, publica o evento com o resumo
+func (uc *ERPDriftUseCase) CheckDrift(ctx context.Context) (*model.ERPDriftReport, error) {
+	if uc.source == nil {
+		return nil, fmt.Errorf("API do ERP não configurada")
+	}
+
+	erpOpen, err := uc.source.ListOpenBillets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar boletos em aberto no ERP (%s): %w", uc.source.Source(), err)
+	}
+
+	localOpen, err := uc.billetRepository.FindNonReconciled(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar boletos pendentes", err)
+	}
+
+	// Os boletos do ERP fora das pendências locais são buscados pelo ID para separar os já conciliados
+	// localmente dos que não existem na base
+	pending := make(map[string]bool, len(localOpen))
+	for _, billet := range localOpen {
+		pending[billet.ID] = true
+	}
+
+	var unknownIDs []string
+	for _, billet := range erpOpen {
+		if !pending[billet.ID] {
+			unknownIDs = append(unknownIDs, billet.ID)
+		}
+	}
+
+	var known []*model.Billet
+	if len(unknownIDs) > 0 {
+		known, err = uc.billetRepository.GetByIDs(ctx, unknownIDs)
+		if err != nil {
+			return nil, errors.NewDatabaseError("buscar boletos do ERP na base local", err)
+		}
+	}
+
+	report := model.NewERPDriftReport(erpOpen, localOpen, known)
+
+	if err := uc.driftRepository.Save(ctx, report); err != nil {
+		return nil, errors.NewDatabaseError("gravar relatório de divergências com o ERP", err)
+	}
+
+	if report.HasDrifts() {
+		uc.publishDrift(ctx, report)
+	}
+
+	return report, nil
+}
+
+// GetLatest retorna o relatório da verificação mais recente, ou nil quando nenhuma verificação foi feita
+func (uc *ERPDriftUseCase) GetLatest(ctx context.Context) (*model.ERPDriftReport, error) {
+	report, err := uc.driftRepository.GetLatest(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar relatório de divergências com o ERP", err)
+	}
+
+	return report, nil
+}
+
+// ListReports lista os relatórios mais recentes, do mais novo para o mais antigo
+func (uc *ERPDriftUseCase) ListReports(ctx context.Context, limit int) ([]*model.ERPDriftReport, error) {
+	if limit <= 0 {
+		limit = DefaultERPDriftListLimit
+	}
+	if limit > MaxERPDriftListLimit {
+		return nil, errors.NewValidationError("limit", fmt.Sprintf("no máximo %d relatórios por consulta", MaxERPDriftListLimit))
+	}
+
+	reports, err := uc.driftRepository.List(ctx, limit)
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar relatórios de divergências com o ERP", err)
+	}
+
+	return reports, nil
+}
+
+// publishDrift publica o resumo das divergências; falhas de publicação não desfazem o relatório gravado
+func (uc *ERPDriftUseCase) publishDrift(ctx context.Context, report *model.ERPDriftReport) {
+	if uc.eventPublisher == nil {
+		return
+	}
+
+	event := model.NewEvent(model.EventERPDrift, "", 0)
+	event.Description = report.Summary()
+
+	if err := uc.eventPublisher.Publish(ctx, []*model.Event{event}); err != nil {
+		log.Printf("erro ao publicar divergências com o ERP: %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/domain/service"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// ERPSyncUseCase implementa a importação delta de boletos: busca na API do ERP os boletos criados ou
+// alterados desde o último cursor e os grava na base local, sem depender da importação em lote
+type ERPSyncUseCase struct {
+	feed               service.ERPBilletFeed
+	billetRepository   repository.BilletRepository
+	timelineRepository repository.TimelineRepository
+	syncRepository     repository.ERPSyncRepository
+}
+
+// NewERPSyncUseCase cria uma nova instância do ERPSyncUseCase. Sem feed, o endpoint do ERP não está
+// configurado e apenas o estado da última sincronização pode ser consultado
+func NewERPSyncUseCase(
+	feed service.ERPBilletFeed,
+	billetRepo repository.BilletRepository,
+	timelineRepo repository.TimelineRepository,
+	syncRepo repository.ERPSyncRepository,
+) *ERPSyncUseCase {
+	return &ERPSyncUseCase{
+		feed:               feed,
+		billetRepository:   billetRepo,
+		timelineRepository: timelineRepo,
+		syncRepository:     syncRepo,
+	}
+}
+
+// Enabled verifica se o endpoint de boletos alterados do ERP está configurado
+func (uc *ERPSyncUseCase) Enabled() bool {
+	return uc.feed != nil
+}
+
+// Sync busca os boletos criados ou alterados no ERP desde o cursor da última sincronização e os grava:
+// boletos novos são criados, os pendentes com dados diferentes são atualizados e os já conciliados na
+// base local são mantidos. Boletos inválidos são registrados no estado e não bloqueiam o cursor; uma falha
+// de gravação interrompe a sincronização, preservando o cursor dos boletos já gravados
+func (uc *ERPSyncUseCase) Sync(ctx context.Context) (*model.ERPSyncState, error) {
+	if uc.feed == nil {
+		return nil, fmt.Errorf("endpoint de boletos alterados do ERP não configurado")
+	}
+
+	source := uc.feed.Source()
+	previous, err := uc.syncRepository.Get(ctx, source)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar estado da sincronização com o ERP", err)
+	}
+
+	var since *time.Time
+	if previous != nil {
+		since = previous.Cursor
+	}
+
+	changes, err := uc.feed.ListChangedBillets(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar boletos alterados no ERP (%s): %w", source, err)
+	}
+
+	existing, err := uc.existingBillets(ctx, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	state := model.NewERPSyncState(source, since)
+	for _, change := range changes {
+		if err := uc.apply(ctx, change.Billet, existing, state); err != nil {
+			if saveErr := uc.syncRepository.Save(ctx, state); saveErr != nil {
+				return nil, errors.NewDatabaseError("gravar estado da sincronização com o ERP", saveErr)
+			}
+			return nil, err
+		}
+		state.Advance(change.UpdatedAt)
+	}
+
+	if err := uc.syncRepository.Save(ctx, state); err != nil {
+		return nil, errors.NewDatabaseError("gravar estado da sincronização com o ERP", err)
+	}
+
+	return state, nil
+}
+
+// GetState retorna o estado da última sincronização, ou nil quando nenhuma sincronização foi feita
+func (uc *ERPSyncUseCase) GetState(ctx context.Context) (*model.ERPSyncState, error) {
+	if uc.feed == nil {
+		return nil, nil
+	}
+
+	state, err := uc.syncRepository.Get(ctx, uc.feed.Source())
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar estado da sincronização com o ERP", err)
+	}
+
+	return state, nil
+}
+
+// existingBillets busca na base local os boletos recebidos do ERP, indexados pelo ID
+func (uc *ERPSyncUseCase) existingBillets(ctx context.Context, changes []*model.ERPBilletChange) (map[string]*model.Billet, error) {
+	existing := make(map[string]*model.Billet)
+	if len(changes) == 0 {
+		return existing, nil
+	}
+
+	ids := make([]string, 0, len(changes))
+	for _, change := range changes {
+		ids = append(ids, change.Billet.ID)
+	}
+
+	billets, err := uc.billetRepository.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar boletos do ERP na base local", err)
+	}
+	for _, billet := range billets {
+		existing[billet.ID] = billet
+	}
+
+	return existing, nil
+}
+
+// apply grava um boleto recebido do ERP, mantendo existing atualizado para as alterações seguintes do
+// mesmo boleto, e contabiliza o resultado no estado. Apenas falhas de gravação retornam erro. O contrato,
+// o cliente e a parcela não vêm do ERP e são mantidos
+func (uc *ERPSyncUseCase) apply(ctx context.Context, billet *model.Billet, existing map[string]*model.Billet, state *model.ERPSyncState) error {
+	if err := validateBillet(billet); err != nil {
+		state.Fail(billet.ID, err)
+		return nil
+	}
+
+	current := existing[billet.ID]
+	if current == nil {
+		if _, err := uc.billetRepository.Create(ctx, billet); err != nil {
+			return errors.NewDatabaseError("criar boleto do ERP", err)
+		}
+		existing[billet.ID] = billet
+		state.CreatedCount++
+		recordTimeline(ctx, uc.timelineRepository, model.NewTimelineEntry(model.TimelineBillet, billet.ID,
+			model.TimelineImported, "importado da API do ERP", ""))
+		return nil
+	}
+
+	changes := model.BilletChanges(current, billet)
+	switch {
+	case len(changes) == 0:
+		state.UnchangedCount++
+		return nil
+	case current.IsReconciled():
+		state.SkippedCount++
+		return nil
+	}
+
+	billet.ContractID = current.ContractID
+	billet.CustomerID = current.CustomerID
+	billet.InstallmentNumber = current.InstallmentNumber
+	updated, err := uc.billetRepository.Update(ctx, billet)
+	if err != nil {
+		return errors.NewDatabaseError("atualizar boleto do ERP", err)
+	}
+	existing[billet.ID] = updated
+	state.UpdatedCount++
+	recordTimeline(ctx, uc.timelineRepository, model.NewTimelineEntry(model.TimelineBillet, billet.ID,
+		model.TimelineUpdated, "alterado pela API do ERP: "+strings.Join(changes, "; "), ""))
+
+	return nil
+}
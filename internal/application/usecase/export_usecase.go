@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/domain/service"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// ExportUseCase implementa o envio automático do arquivo de resultado de cada execução de conciliação ao
+// ERP, com o registro de cada envio e o reenvio manual dos arquivos
+type ExportUseCase struct {
+	exportRepository  repository.ResultExportRepository
+	runRepository     repository.ReconciliationRunRepository
+	billetRepository  repository.BilletRepository
+	paymentRepository repository.PaymentRepository
+	generator         service.ResultFileGenerator
+	sender            service.FileSender
+}
+
+// NewExportUseCase cria uma nova instância do ExportUseCase. Sem sender, o envio automático fica desabilitado
+func NewExportUseCase(
+	exportRepo repository.ResultExportRepository,
+	runRepo repository.ReconciliationRunRepository,
+	billetRepo repository.BilletRepository,
+	paymentRepo repository.PaymentRepository,
+	generator service.ResultFileGenerator,
+	sender service.FileSender,
+) *ExportUseCase {
+	return &ExportUseCase{
+		exportRepository:  exportRepo,
+		runRepository:     runRepo,
+		billetRepository:  billetRepo,
+		paymentRepository: paymentRepo,
+		generator:         generator,
+		sender:            sender,
+	}
+}
+
+// Enabled indica se o envio dos arquivos de resultado está configurado
+func (uc *ExportUseCase) Enabled() bool {
+	return uc != nil && uc.sender != nil
+}
+
+// checkAvailable verifica se o envio pode ser feito: ele precisa estar configurado, e os dados do
+// ambiente sandbox nunca são enviados ao ERP
+func (uc *ExportUseCase) checkAvailable(ctx context.Context) error {
+	if !uc.Enabled() {
+		return errors.NewValidationError("export", "envio dos arquivos de resultado não configurado")
+	}
+	if model.IsSandbox(ctx) {
+		return errors.NewValidationError("export", "envio dos arquivos de resultado indisponível no ambiente sandbox")
+	}
+	return nil
+}
+
+// ExportRun gera os arquivos de resultado da execução concluída, registra e envia ao destino configurado.
+// Uma falha de envio fica registrada para o reenvio manual e não é retornada como erro
+func (uc *ExportUseCase) ExportRun(ctx context.Context, run *model.ReconciliationRun) ([]*model.ResultExport, error) {
+	if err := uc.checkAvailable(ctx); err != nil {
+		return nil, err
+	}
+
+	if !run.IsCompleted() {
+		return nil, errors.NewConflictError("execução", run.ID, "execução ainda não concluída")
+	}
+
+	records, err := uc.loadRecords(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := uc.generator.Split(records)
+	if err != nil {
+		return nil, errors.NewValidationError("export", err.Error())
+	}
+
+	keys := make([]string, 0, len(files))
+	for key := range files {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	exports := make([]*model.ResultExport, 0, len(keys))
+	for _, key := range keys {
+		export, err := uc.exportFile(ctx, run, key, files[key])
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, export)
+	}
+
+	return exports, nil
+}
+
+// exportFile gera, registra e envia um dos arquivos da execução com o próximo número sequencial da chave
+func (uc *ExportUseCase) exportFile(ctx context.Context, run *model.ReconciliationRun, key string, records []model.ResultRecord) (*model.ResultExport, error) {
+	sequence, err := uc.exportRepository.NextSequence(ctx, uc.generator.Format(), key)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar sequência do arquivo de resultado", err)
+	}
+
+	content, err := uc.generator.Generate(run, key, sequence, records)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := fmt.Sprintf("conciliacao_%s_%s", run.StartedAt.Format("20060102T150405"), run.ID)
+	if key != "" {
+		fileName += "_" + key
+	}
+	fileName += "." + uc.generator.Extension()
+
+	export := model.NewResultExport(run.ID, uc.generator.Format(), key, sequence, fileName, uc.sender.Destination(), content)
+	if err := uc.exportRepository.Create(ctx, export); err != nil {
+		return nil, errors.NewDatabaseError("registrar arquivo de resultado", err)
+	}
+
+	return uc.send(ctx, export, "")
+}
+
+// loadRecords reúne os boletos conciliados na execução com os boletos e pagamentos correspondentes
+func (uc *ExportUseCase) loadRecords(ctx context.Context, run *model.ReconciliationRun) ([]model.ResultRecord, error) {
+	if run.Result == nil || len(run.Result.ReconciledBillets) == 0 {
+		return nil, nil
+	}
+
+	billetIDs := make([]string, 0, len(run.Result.ReconciledBillets))
+	paymentIDs := make([]string, 0, len(run.Result.ReconciledBillets))
+	for _, reconciled := range run.Result.ReconciledBillets {
+		billetIDs = append(billetIDs, reconciled.BilletID)
+		paymentIDs = append(paymentIDs, reconciled.TransactionID)
+	}
+
+	billets, err := uc.billetRepository.GetByIDs(ctx, billetIDs)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar boletos da execução", err)
+	}
+	payments, err := uc.paymentRepository.GetByIDs(ctx, paymentIDs)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar pagamentos da execução", err)
+	}
+
+	billetsByID := make(map[string]*model.Billet, len(billets))
+	for _, billet := range billets {
+		billetsByID[billet.ID] = billet
+	}
+	paymentsByID := make(map[string]*model.Payment, len(payments))
+	for _, payment := range payments {
+		paymentsByID[payment.ID] = payment
+	}
+
+	records := make([]model.ResultRecord, 0, len(run.Result.ReconciledBillets))
+	for _, reconciled := range run.Result.ReconciledBillets {
+		records = append(records, model.ResultRecord{
+			Reconciled: reconciled,
+			Billet:     billetsByID[reconciled.BilletID],
+			Payment:    paymentsByID[reconciled.TransactionID],
+		})
+	}
+
+	return records, nil
+}
+
+// ExportRunByID gera e envia novamente os arquivos de resultado de uma execução concluída
+func (uc *ExportUseCase) ExportRunByID(ctx context.Context, runID string) ([]*model.ResultExport, error) {
+	if runID == "" {
+		return nil, errors.NewValidationError("run_id", "ID da execução não pode ser vazio")
+	}
+
+	run, err := uc.runRepository.GetByID(ctx, runID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar execução de conciliação", err)
+	}
+	if run == nil {
+		return nil, errors.NewNotFoundError("execução", runID)
+	}
+
+	return uc.ExportRun(ctx, run)
+}
+
+// ListExports lista os arquivos de resultado, opcionalmente apenas os da execução informada
+func (uc *ExportUseCase) ListExports(ctx context.Context, runID string) ([]*model.ResultExport, error) {
+	exports, err := uc.exportRepository.List(ctx, runID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar arquivos de resultado", err)
+	}
+	return exports, nil
+}
+
+// GetExport recupera um arquivo de resultado com o seu conteúdo
+func (uc *ExportUseCase) GetExport(ctx context.Context, id string) (*model.ResultExport, error) {
+	if id == "" {
+		return nil, errors.NewValidationError("id", "ID do arquivo não pode ser vazio")
+	}
+
+	export, err := uc.exportRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar arquivo de resultado", err)
+	}
+	if export == nil {
+		return nil, errors.NewNotFoundError("arquivo de resultado", id)
+	}
+
+	return export, nil
+}
+
+// ResendExport reenvia manualmente o mesmo conteúdo de um arquivo de resultado ao destino configurado
+func (uc *ExportUseCase) ResendExport(ctx context.Context, id, actor string) (*model.ResultExport, error) {
+	if err := uc.checkAvailable(ctx); err != nil {
+		return nil, err
+	}
+
+	export, err := uc.GetExport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.send(ctx, export, actor)
+}
+
+// send envia o arquivo e registra a tentativa
+func (uc *ExportUseCase) send(ctx context.Context, export *model.ResultExport, actor string) (*model.ResultExport, error) {
+	err := uc.sender.Send(ctx, export.FileName, export.Content)
+	if err != nil {
+		log.Printf("erro ao enviar arquivo de resultado %s: %v", export.FileName, err)
+	}
+
+	export.RecordAttempt(actor, err)
+	if err := uc.exportRepository.UpdateAttempt(ctx, export); err != nil {
+		return nil, errors.NewDatabaseError("registrar envio do arquivo de resultado", err)
+	}
+
+	return export, nil
+}
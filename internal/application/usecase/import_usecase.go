@@ -0,0 +1,325 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/domain/service"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// ImportUseCase implementa os casos de uso relacionados à importação de arquivos bancários
+type ImportUseCase struct {
+	paymentRepository    repository.PaymentRepository
+	importFileRepository repository.ImportFileRepository
+	timelineRepository   repository.TimelineRepository
+	eventPublisher       service.EventPublisher
+	totalsPolicy         model.TotalsPolicy
+}
+
+// NewImportUseCase cria uma nova instância do ImportUseCase
+func NewImportUseCase(
+	paymentRepo repository.PaymentRepository,
+	importFileRepo repository.ImportFileRepository,
+	timelineRepo repository.TimelineRepository,
+	eventPublisher service.EventPublisher,
+	totalsPolicy model.TotalsPolicy,
+) *ImportUseCase {
+	if totalsPolicy == "" {
+		totalsPolicy = model.TotalsPolicyReject
+	}
+
+	return &ImportUseCase{
+		paymentRepository:    paymentRepo,
+		importFileRepository: importFileRepo,
+		timelineRepository:   timelineRepo,
+		eventPublisher:       eventPublisher,
+		totalsPolicy:         totalsPolicy,
+	}
+}
+
+// ImportFile registra um arquivo bancário e seus pagamentos. Arquivos com número sequencial já
+// importado são recusados; quando o número pula em relação ao último arquivo do convênio, a
+// lacuna é registrada em log e publicada como evento, sem impedir a importação. Arquivos com totais
+// do trailer divergentes são recusados ou, na política de sinalização, importados com os pagamentos
+// retidos para revisão manual, fora da conciliação até serem aprovados. No modo all_or_nothing (padrão)
+// os pagamentos são gravados em uma única transação; no modo best_effort cada pagamento é gravado
+// individualmente e os que falharem são reportados no resultado
+func (uc *ImportUseCase) ImportFile(ctx context.Context, file *model.ImportFile, payments []*model.Payment, mode model.ImportMode) (*model.ImportResult, error) {
+	mode = mode.OrDefault(model.ImportModeAllOrNothing)
+	if !mode.IsValid() {
+		return nil, errors.NewValidationError("mode", "modo de importação deve ser all_or_nothing ou best_effort")
+	}
+
+	if err := validateImportFile(file); err != nil {
+		return nil, err
+	}
+
+	if !file.IsConsistent() && uc.totalsPolicy == model.TotalsPolicyReject {
+		return nil, errors.NewValidationError("trailer", "totais do arquivo não conferem: "+strings.Join(file.Inconsistencies, "; "))
+	}
+
+	existing, err := uc.importFileRepository.GetBySequence(ctx, file.BankCode, file.Agreement, file.Direction, file.Sequence)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar arquivo importado", err)
+	}
+	if existing != nil {
+		return nil, errors.NewConflictError("arquivo", fmt.Sprintf("%s/%s/%d", file.Agreement, file.Direction, file.Sequence),
+			"número sequencial já importado para o convênio")
+	}
+
+	lastSequence, err := uc.importFileRepository.GetLastSequence(ctx, file.BankCode, file.Agreement, file.Direction)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar último número sequencial", err)
+	}
+
+	result := &model.ImportResult{File: file, Mode: mode}
+
+	payments, err = uc.createPayments(ctx, payments, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if !file.IsConsistent() {
+		if err := uc.holdPayments(ctx, file, payments); err != nil {
+			return nil, err
+		}
+	}
+
+	file.Payments = len(payments)
+	if err := uc.importFileRepository.Create(ctx, file); err != nil {
+		return nil, errors.NewDatabaseError("registrar arquivo importado", err)
+	}
+
+	uc.recordImportedPayments(ctx, file, payments)
+	uc.publishImportedPayments(ctx, payments)
+
+	// O primeiro arquivo do convênio não tem referência para detectar lacunas
+	if lastSequence > 0 && file.Sequence > lastSequence+1 {
+		result.Gap = model.NewImportGap(file.BankCode, file.Agreement, file.Direction, lastSequence, file.Sequence)
+		uc.alertGap(ctx, file, result.Gap)
+	}
+
+	return result, nil
+}
+
+// ImportStatement importa os lançamentos de um extrato bancário (CSV ou OFX), nos mesmos modos e com
+// a mesma política para divergências de ImportFile. Extratos não têm número sequencial: o arquivo não é
+// registrado e a reimportação é barrada pelo ID dos lançamentos, derivado do extrato
+func (uc *ImportUseCase) ImportStatement(ctx context.Context, file *model.ImportFile, payments []*model.Payment, mode model.ImportMode) (*model.ImportResult, error) {
+	mode = mode.OrDefault(model.ImportModeAllOrNothing)
+	if !mode.IsValid() {
+		return nil, errors.NewValidationError("mode", "modo de importação deve ser all_or_nothing ou best_effort")
+	}
+
+	if file == nil || !file.IsStatement() {
+		return nil, errors.NewValidationError("file", "extrato não pode ser vazio")
+	}
+
+	if !file.IsConsistent() && uc.totalsPolicy == model.TotalsPolicyReject {
+		return nil, errors.NewValidationError("statement", "extrato inconsistente: "+strings.Join(file.Inconsistencies, "; "))
+	}
+
+	for i, payment := range payments {
+		if err := validatePayment(payment); err != nil {
+			return nil, errors.NewValidationError("payments", fmt.Sprintf("lançamento %d do extrato: %v", i+1, err))
+		}
+	}
+
+	result := &model.ImportResult{File: file, Mode: mode}
+
+	payments, err := uc.createPayments(ctx, payments, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if !file.IsConsistent() {
+		if err := uc.holdPayments(ctx, file, payments); err != nil {
+			return nil, err
+		}
+	}
+
+	file.Payments = len(payments)
+	uc.recordImportedPayments(ctx, file, payments)
+	uc.publishImportedPayments(ctx, payments)
+
+	return result, nil
+}
+
+// ListImportFiles lista os arquivos bancários importados
+func (uc *ImportUseCase) ListImportFiles(ctx context.Context) ([]*model.ImportFile, error) {
+	files, err := uc.importFileRepository.GetAll(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar arquivos importados", err)
+	}
+
+	return files, nil
+}
+
+// ListGaps lista as lacunas na numeração sequencial dos arquivos de cada convênio
+func (uc *ImportUseCase) ListGaps(ctx context.Context) ([]*model.ImportGap, error) {
+	gaps, err := uc.importFileRepository.FindGaps(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar lacunas de sequência", err)
+	}
+
+	return gaps, nil
+}
+
+// createPayments grava os pagamentos de um arquivo no modo da importação e retorna os gravados
+func (uc *ImportUseCase) createPayments(ctx context.Context, payments []*model.Payment, result *model.ImportResult) ([]*model.Payment, error) {
+	if result.Mode == model.ImportModeBestEffort {
+		return uc.createPaymentsIndividually(ctx, payments, result), nil
+	}
+
+	if len(payments) > 0 {
+		if err := uc.paymentRepository.CreateMany(ctx, payments); err != nil {
+			return nil, errors.NewDatabaseError("salvar pagamentos do arquivo", err)
+		}
+	}
+	return payments, nil
+}
+
+// createPaymentsIndividually grava cada pagamento do arquivo separadamente, registrando no resultado o
+// desfecho de cada um, e retorna os pagamentos gravados
+func (uc *ImportUseCase) createPaymentsIndividually(ctx context.Context, payments []*model.Payment, result *model.ImportResult) []*model.Payment {
+	created := make([]*model.Payment, 0, len(payments))
+	result.Items = make([]model.BatchItemResult, 0, len(payments))
+
+	for i, payment := range payments {
+		item := model.BatchItemResult{Index: i, ID: payment.ID, Success: true}
+		if _, err := uc.paymentRepository.Create(ctx, payment); err != nil {
+			item.Success = false
+			item.Error = errors.NewDatabaseError("salvar pagamento do arquivo", err).Error()
+		} else {
+			created = append(created, payment)
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	return created
+}
+
+// holdPayments marca como suspeitos os pagamentos de um arquivo com totais divergentes, retendo-os
+// na revisão manual de pagamentos
+func (uc *ImportUseCase) holdPayments(ctx context.Context, file *model.ImportFile, payments []*model.Payment) error {
+	reason := fmt.Sprintf("arquivo %d do convênio %s com totais do trailer divergentes", file.Sequence, file.Agreement)
+	if file.IsStatement() {
+		reason = importSource(file) + " inconsistente"
+	}
+	log.Printf("%s: %s", reason, strings.Join(file.Inconsistencies, "; "))
+
+	for _, payment := range payments {
+		if err := uc.paymentRepository.UpdateReviewStatus(ctx, payment.ID, model.ReviewStatusSuspicious, &reason); err != nil {
+			return errors.NewDatabaseError("reter pagamento do arquivo", err)
+		}
+
+		payment.ReviewStatus = model.ReviewStatusSuspicious
+		payment.ReviewReason = &reason
+	}
+
+	return nil
+}
+
+// recordImportedPayments registra na linha do tempo de cada pagamento o arquivo de origem e, nos
+// arquivos com totais divergentes, a retenção para revisão manual. Extratos não são registrados, então
+// as entradas não referenciam o arquivo
+func (uc *ImportUseCase) recordImportedPayments(ctx context.Context, file *model.ImportFile, payments []*model.Payment) {
+	description := "importado do " + importSource(file)
+	if file.FileName != "" {
+		description += ": " + file.FileName
+	}
+
+	reference := file.ID
+	if file.IsStatement() {
+		reference = ""
+	}
+
+	entries := make([]*model.TimelineEntry, 0, len(payments))
+	for _, payment := range payments {
+		imported := model.NewTimelineEntry(model.TimelinePayment, payment.ID, model.TimelineImported, description, "")
+		imported.Reference = reference
+		entries = append(entries, imported)
+
+		if payment.IsSuspicious() && payment.ReviewReason != nil {
+			held := model.NewTimelineEntry(model.TimelinePayment, payment.ID, model.TimelineClassified,
+				"retido para revisão manual: "+*payment.ReviewReason, "")
+			held.Reference = reference
+			entries = append(entries, held)
+		}
+	}
+
+	recordTimeline(ctx, uc.timelineRepository, entries...)
+}
+
+// importSource descreve a origem dos pagamentos importados: o arquivo do convênio ou o extrato
+func importSource(file *model.ImportFile) string {
+	if !file.IsStatement() {
+		return fmt.Sprintf("arquivo %d do convênio %s (banco %s)", file.Sequence, file.Agreement, file.BankCode)
+	}
+	if file.BankAccount != "" {
+		return "extrato da conta " + file.BankAccount
+	}
+	return "extrato"
+}
+
+// publishImportedPayments publica os eventos internos dos pagamentos importados; falhas de publicação
+// não desfazem a importação
+func (uc *ImportUseCase) publishImportedPayments(ctx context.Context, payments []*model.Payment) {
+	if uc.eventPublisher == nil || len(payments) == 0 {
+		return
+	}
+
+	events := make([]*model.Event, 0, len(payments))
+	for _, payment := range payments {
+		event := model.NewEvent(model.EventPaymentImported, payment.BankAccount, payment.Amount)
+		event.TransactionID = payment.ID
+		event.ReferenceID = payment.ReferenceID
+		events = append(events, event)
+	}
+
+	if err := uc.eventPublisher.Publish(ctx, events); err != nil {
+		log.Printf("erro ao publicar eventos de pagamentos importados: %v", err)
+	}
+}
+
+// alertGap registra a lacuna em log e a publica aos assinantes; falhas de publicação não desfazem a importação
+func (uc *ImportUseCase) alertGap(ctx context.Context, file *model.ImportFile, gap *model.ImportGap) {
+	description := fmt.Sprintf("convênio %s (%s, banco %s): arquivo %d recebido após o %d, faltando %d arquivo(s) (%d a %d)",
+		gap.Agreement, gap.Direction, gap.BankCode, gap.NextSequence, gap.PreviousSequence,
+		gap.MissingCount, gap.MissingFrom, gap.MissingTo)
+	log.Printf("lacuna de sequência detectada: %s", description)
+
+	if uc.eventPublisher == nil {
+		return
+	}
+
+	event := model.NewEvent(model.EventImportSequenceGap, file.BankAccount, 0)
+	event.Description = description
+
+	if err := uc.eventPublisher.Publish(ctx, []*model.Event{event}); err != nil {
+		log.Printf("erro ao publicar alerta de lacuna de sequência: %v", err)
+	}
+}
+
+// validateImportFile valida a identificação do arquivo bancário
+func validateImportFile(file *model.ImportFile) error {
+	if file == nil {
+		return errors.NewValidationError("file", "arquivo não pode ser vazio")
+	}
+	if file.BankCode == "" {
+		return errors.NewValidationError("bank_code", "código do banco é obrigatório")
+	}
+	if file.Agreement == "" {
+		return errors.NewValidationError("agreement", "código do convênio é obrigatório")
+	}
+	if file.Sequence <= 0 {
+		return errors.NewValidationError("sequence", "número sequencial do arquivo deve ser positivo")
+	}
+
+	return nil
+}
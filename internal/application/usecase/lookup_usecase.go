@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// MaxLookupIDs limita a quantidade de IDs consultados em uma única requisição de lookup
+const MaxLookupIDs = 1000
+
+// BilletLookupResult representa o resultado da consulta de boletos por múltiplos IDs
+type BilletLookupResult struct {
+	Found    []*model.Billet `json:"found"`
+	NotFound []string        `json:"not_found"`
+}
+
+// PaymentLookupResult representa o resultado da consulta de pagamentos por múltiplos IDs
+type PaymentLookupResult struct {
+	Found    []*model.Payment `json:"found"`
+	NotFound []string         `json:"not_found"`
+}
+
+// LookupUseCase implementa a consulta em lote de boletos e pagamentos por ID, evitando que os
+// integradores façam uma chamada individual por registro
+type LookupUseCase struct {
+	billetRepository  repository.BilletRepository
+	paymentRepository repository.PaymentRepository
+}
+
+// NewLookupUseCase cria uma nova instância do LookupUseCase
+func NewLookupUseCase(billetRepo repository.BilletRepository, paymentRepo repository.PaymentRepository) *LookupUseCase {
+	return &LookupUseCase{
+		billetRepository:  billetRepo,
+		paymentRepository: paymentRepo,
+	}
+}
+
+// LookupBillets busca os boletos dos IDs informados, retornando os encontrados e os IDs não encontrados
+// (inexistentes ou de contas fora do escopo de acesso) na ordem da requisição
+func (uc *LookupUseCase) LookupBillets(ctx context.Context, ids []string) (*BilletLookupResult, error) {
+	ids, err := normalizeLookupIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	billets, err := uc.billetRepository.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar boletos por IDs", err)
+	}
+
+	byID := make(map[string]*model.Billet, len(billets))
+	for _, billet := range billets {
+		byID[billet.ID] = billet
+	}
+
+	result := &BilletLookupResult{Found: make([]*model.Billet, 0, len(billets)), NotFound: []string{}}
+	for _, id := range ids {
+		if billet, ok := byID[id]; ok {
+			result.Found = append(result.Found, billet)
+		} else {
+			result.NotFound = append(result.NotFound, id)
+		}
+	}
+
+	return result, nil
+}
+
+// LookupPayments busca os pagamentos dos IDs informados, retornando os encontrados e os IDs não
+// encontrados (inexistentes ou de contas fora do escopo de acesso) na ordem da requisição
+func (uc *LookupUseCase) LookupPayments(ctx context.Context, ids []string) (*PaymentLookupResult, error) {
+	ids, err := normalizeLookupIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := uc.paymentRepository.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar pagamentos por IDs", err)
+	}
+
+	byID := make(map[string]*model.Payment, len(payments))
+	for _, payment := range payments {
+		byID[payment.ID] = payment
+	}
+
+	result := &PaymentLookupResult{Found: make([]*model.Payment, 0, len(payments)), NotFound: []string{}}
+	for _, id := range ids {
+		if payment, ok := byID[id]; ok {
+			result.Found = append(result.Found, payment)
+		} else {
+			result.NotFound = append(result.NotFound, id)
+		}
+	}
+
+	return result, nil
+}
+
+// normalizeLookupIDs valida a lista de IDs consultados e remove as repetições, mantendo a ordem
+func normalizeLookupIDs(ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, errors.NewValidationError("ids", "informe ao menos um ID")
+	}
+
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			return nil, errors.NewValidationError("ids", "IDs não podem ser vazios")
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	if len(unique) > MaxLookupIDs {
+		return nil, errors.NewValidationError("ids", fmt.Sprintf("no máximo %d IDs por consulta", MaxLookupIDs))
+	}
+
+	return unique, nil
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// DefaultMaintenanceReportListLimit define quantos relatórios da manutenção são listados quando nenhum
+// limite é informado
+const DefaultMaintenanceReportListLimit = 30
+
+// MaxMaintenanceReportListLimit limita a quantidade de relatórios da manutenção listados por consulta
+const MaxMaintenanceReportListLimit = 500
+
+// MaintenanceUseCase implementa a rotina de manutenção: expurga as execuções concluídas antigas, as
+// chaves de idempotência vencidas, as entregas de webhook finalizadas, os retratos das pendências além
+// da retenção, os bloqueios de boletos vencidos, os registros de mensagens de fila antigos e a trilha de
+// auditoria vencida, e anonimiza os dados do pagador após a quitação (LGPD), em cada shard
+type MaintenanceUseCase struct {
+	repositories     map[string]repository.MaintenanceRepository
+	reportRepository repository.MaintenanceReportRepository
+	policy           model.RetentionPolicy
+}
+
+// NewMaintenanceUseCase cria uma nova instância do MaintenanceUseCase com um repositório por shard. Sem
+// reportRepo, os relatórios das execuções não são gravados
+func NewMaintenanceUseCase(
+	repositories map[string]repository.MaintenanceRepository,
+	reportRepo repository.MaintenanceReportRepository,
+	policy model.RetentionPolicy,
+) *MaintenanceUseCase {
+	return &MaintenanceUseCase{
+		repositories:     repositories,
+		reportRepository: reportRepo,
+		policy:           policy,
+	}
+}
+
+// RunMaintenance expurga os registros vencidos no instante informado, grava o relatório e retorna o que
+// foi removido de cada shard. Os expurgos são idempotentes: após uma falha, a rotina pode ser reexecutada
+// por inteiro
+func (uc *MaintenanceUseCase) RunMaintenance(ctx context.Context, now time.Time) (*model.MaintenanceReport, error) {
+	report := model.NewMaintenanceReport(time.Now(), uc.policy)
+
+	// Ordem fixa dos shards, para que os logs de execuções seguidas sejam comparáveis
+	names := make([]string, 0, len(uc.repositories))
+	for name := range uc.repositories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		counts, err := uc.purgeShard(ctx, uc.repositories[name], now)
+		if err != nil {
+			return nil, errors.NewDatabaseError(fmt.Sprintf("manutenção do shard %s", name), err)
+		}
+
+		report.RecordShard(name, counts)
+		log.Printf("manutenção do shard %s: %d execuções, %d chaves de idempotência, %d entregas de webhook, %d retratos das pendências, %d bloqueios, %d mensagens processadas e %d registros de auditoria removidos; %d boletos e %d pagamentos anonimizados",
+			name, counts.Runs, counts.IdempotencyKeys, counts.EventDeliveries, counts.PendingSnapshots, counts.ExpiredClaims, counts.ProcessedMessages,
+			counts.AuditRecords, counts.AnonymizedBillets, counts.AnonymizedPayments)
+	}
+
+	report.DurationMs = time.Since(report.StartedAt).Milliseconds()
+	log.Printf("manutenção concluída em %dms: %d registros removidos ou anonimizados", report.DurationMs, report.Removed.Total())
+
+	if uc.reportRepository != nil {
+		if err := uc.reportRepository.Save(ctx, report); err != nil {
+			return nil, errors.NewDatabaseError("gravar relatório da manutenção", err)
+		}
+	}
+
+	return report, nil
+}
+
+// ListReports lista os relatórios das execuções mais recentes da manutenção, do mais novo para o mais antigo
+func (uc *MaintenanceUseCase) ListReports(ctx context.Context, limit int) ([]*model.MaintenanceReport, error) {
+	if uc.reportRepository == nil {
+		return []*model.MaintenanceReport{}, nil
+	}
+
+	if limit <= 0 {
+		limit = DefaultMaintenanceReportListLimit
+	}
+	if limit > MaxMaintenanceReportListLimit {
+		return nil, errors.NewValidationError("limit", fmt.Sprintf("no máximo %d relatórios por consulta", MaxMaintenanceReportListLimit))
+	}
+
+	reports, err := uc.reportRepository.List(ctx, limit)
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar relatórios da manutenção", err)
+	}
+
+	return reports, nil
+}
+
+// purgeShard aplica a política de retenção a um shard. As execuções são expurgadas antes das chaves de
+// idempotência, para que a chave de uma execução removida não seja contada também como expirada
+func (uc *MaintenanceUseCase) purgeShard(ctx context.Context, repo repository.MaintenanceRepository, now time.Time) (model.PurgeCounts, error) {
+	var counts model.PurgeCounts
+	var err error
+
+	if uc.policy.Runs > 0 {
+		if counts.Runs, err = repo.PurgeCompletedRuns(ctx, now.Add(-uc.policy.Runs)); err != nil {
+			return counts, err
+		}
+	}
+
+	if uc.policy.IdempotencyKeys > 0 {
+		if counts.IdempotencyKeys, err = repo.ExpireIdempotencyKeys(ctx, now.Add(-uc.policy.IdempotencyKeys)); err != nil {
+			return counts, err
+		}
+	}
+
+	if uc.policy.EventDeliveries > 0 {
+		if counts.EventDeliveries, err = repo.PurgeEventDeliveries(ctx, now.Add(-uc.policy.EventDeliveries)); err != nil {
+			return counts, err
+		}
+	}
+
+	if uc.policy.PendingSnapshots > 0 {
+		if counts.PendingSnapshots, err = repo.PurgePendingSnapshots(ctx, now.Add(-uc.policy.PendingSnapshots)); err != nil {
+			return counts, err
+		}
+	}
+
+	if uc.policy.ProcessedMessages > 0 {
+		if counts.ProcessedMessages, err = repo.PurgeProcessedMessages(ctx, now.Add(-uc.policy.ProcessedMessages)); err != nil {
+			return counts, err
+		}
+	}
+
+	if uc.policy.AuditTrail > 0 {
+		if counts.AuditRecords, err = repo.PurgeAuditTrail(ctx, now.Add(-uc.policy.AuditTrail)); err != nil {
+			return counts, err
+		}
+	}
+
+	// Os dados do pagador são apagados, e não o boleto ou o pagamento, que seguem nos relatórios e na
+	// trilha de auditoria
+	if uc.policy.PayerData > 0 {
+		before := now.Add(-uc.policy.PayerData)
+		if counts.AnonymizedBillets, err = repo.AnonymizeBilletPayers(ctx, before); err != nil {
+			return counts, err
+		}
+		if counts.AnonymizedPayments, err = repo.AnonymizePaymentPayers(ctx, before); err != nil {
+			return counts, err
+		}
+	}
+
+	// Bloqueios vencidos já não impedem o trabalho no boleto; removê-los apenas mantém a tabela enxuta
+	if counts.ExpiredClaims, err = repo.PurgeExpiredClaims(ctx, now); err != nil {
+		return counts, err
+	}
+
+	return counts, nil
+}
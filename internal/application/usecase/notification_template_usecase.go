@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// NotificationTemplateUseCase implementa os casos de uso dos templates de notificação dos tenants.
+// Cada alteração grava uma nova versão, e as versões anteriores podem ser restauradas
+type NotificationTemplateUseCase struct {
+	templateRepository repository.NotificationTemplateRepository
+}
+
+// NewNotificationTemplateUseCase cria uma nova instância do NotificationTemplateUseCase
+func NewNotificationTemplateUseCase(templateRepo repository.NotificationTemplateRepository) *NotificationTemplateUseCase {
+	return &NotificationTemplateUseCase{
+		templateRepository: templateRepo,
+	}
+}
+
+// ListTemplates lista a versão aplicada do template de cada tipo de evento do tenant
+func (uc *NotificationTemplateUseCase) ListTemplates(ctx context.Context, tenant string) ([]*model.NotificationTemplate, error) {
+	templates, err := uc.templateRepository.GetCurrent(ctx, tenant)
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar templates de notificação", err)
+	}
+	return templates, nil
+}
+
+// SaveTemplate grava uma nova versão do template do tenant para o tipo de evento. O assunto e a
+// mensagem são obrigatórios e precisam ser Go templates válidos para as variáveis do evento
+func (uc *NotificationTemplateUseCase) SaveTemplate(ctx context.Context, tenant string, eventType model.EventType, subject, body, actor string) (*model.NotificationTemplate, error) {
+	if err := validateTemplateEventType(eventType); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(subject) == "" {
+		return nil, errors.NewValidationError("subject", "assunto do template é obrigatório")
+	}
+	if strings.TrimSpace(body) == "" {
+		return nil, errors.NewValidationError("body", "mensagem do template é obrigatória")
+	}
+
+	notificationTemplate := model.NewNotificationTemplate(tenant, eventType, subject, body, actor)
+	if err := notificationTemplate.Validate(); err != nil {
+		return nil, errors.NewValidationError("template", err.Error())
+	}
+
+	if err := uc.templateRepository.Create(ctx, notificationTemplate); err != nil {
+		return nil, errors.NewDatabaseError("gravar template de notificação", err)
+	}
+
+	return notificationTemplate, nil
+}
+
+// ListVersions lista as versões do template do tenant para o tipo de evento, da mais recente à mais antiga
+func (uc *NotificationTemplateUseCase) ListVersions(ctx context.Context, tenant string, eventType model.EventType) ([]*model.NotificationTemplate, error) {
+	if err := validateTemplateEventType(eventType); err != nil {
+		return nil, err
+	}
+
+	templates, err := uc.templateRepository.GetVersions(ctx, tenant, eventType)
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar versões do template de notificação", err)
+	}
+	return templates, nil
+}
+
+// RestoreVersion grava uma nova versão do template com o assunto e a mensagem da versão informada
+func (uc *NotificationTemplateUseCase) RestoreVersion(ctx context.Context, tenant string, eventType model.EventType, version int, actor string) (*model.NotificationTemplate, error) {
+	if err := validateTemplateEventType(eventType); err != nil {
+		return nil, err
+	}
+
+	previous, err := uc.templateRepository.GetVersion(ctx, tenant, eventType, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.SaveTemplate(ctx, tenant, eventType, previous.Subject, previous.Body, actor)
+}
+
+// validateTemplateEventType verifica se o tipo de evento gera notificações
+func validateTemplateEventType(eventType model.EventType) error {
+	if !model.IsKnownEventType(eventType) {
+		return errors.NewValidationError("event_type", fmt.Sprintf("tipo de evento desconhecido: %s", eventType))
+	}
+	return nil
+}
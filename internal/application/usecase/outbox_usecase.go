@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// DefaultOutboxListLimit define quantas entregas são listadas quando nenhum limite é informado
+const DefaultOutboxListLimit = 100
+
+// MaxOutboxBatchSize limita a quantidade de entregas reenfileiradas ou listadas por requisição
+const MaxOutboxBatchSize = 1000
+
+// DefaultOutboxMetricsWindow define a janela padrão das métricas de atraso de publicação
+const DefaultOutboxMetricsWindow = 24 * time.Hour
+
+// OutboxUseCase implementa os casos de uso de operação do outbox de entregas de eventos
+type OutboxUseCase struct {
+	deliveryRepository repository.EventDeliveryRepository
+}
+
+// NewOutboxUseCase cria uma nova instância do OutboxUseCase
+func NewOutboxUseCase(deliveryRepo repository.EventDeliveryRepository) *OutboxUseCase {
+	return &OutboxUseCase{
+		deliveryRepository: deliveryRepo,
+	}
+}
+
+// ListDeliveries lista as entregas nas situações informadas; sem situação, lista as pendentes e com falha
+func (uc *OutboxUseCase) ListDeliveries(ctx context.Context, statuses []model.DeliveryStatus, limit int) ([]*model.EventDelivery, error) {
+	if len(statuses) == 0 {
+		statuses = []model.DeliveryStatus{model.DeliveryPending, model.DeliveryFailed}
+	}
+
+	for _, status := range statuses {
+		if !model.IsKnownDeliveryStatus(status) {
+			return nil, errors.NewValidationError("status", fmt.Sprintf("situação de entrega desconhecida: %s", status))
+		}
+	}
+
+	if limit <= 0 {
+		limit = DefaultOutboxListLimit
+	}
+	if limit > MaxOutboxBatchSize {
+		return nil, errors.NewValidationError("limit", fmt.Sprintf("no máximo %d entregas por consulta", MaxOutboxBatchSize))
+	}
+
+	deliveries, err := uc.deliveryRepository.GetByStatus(ctx, statuses, limit)
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar entregas do outbox", err)
+	}
+
+	return deliveries, nil
+}
+
+// GetDelivery busca uma entrega pelo ID
+func (uc *OutboxUseCase) GetDelivery(ctx context.Context, deliveryID string) (*model.EventDelivery, error) {
+	if deliveryID == "" {
+		return nil, errors.NewValidationError("id", "ID da entrega não pode ser vazio")
+	}
+
+	return uc.deliveryRepository.GetByID(ctx, deliveryID)
+}
+
+// RequeueDeliveries devolve à fila as entregas pendentes ou com falha, zerando as tentativas.
+// Entregas já concluídas ou descartadas são ignoradas
+func (uc *OutboxUseCase) RequeueDeliveries(ctx context.Context, deliveryIDs []string) (int, error) {
+	if len(deliveryIDs) == 0 {
+		return 0, errors.NewValidationError("ids", "informe ao menos uma entrega")
+	}
+	if len(deliveryIDs) > MaxOutboxBatchSize {
+		return 0, errors.NewValidationError("ids", fmt.Sprintf("no máximo %d entregas por requisição", MaxOutboxBatchSize))
+	}
+
+	requeued, err := uc.deliveryRepository.Requeue(ctx, deliveryIDs, time.Now())
+	if err != nil {
+		return 0, errors.NewDatabaseError("reenfileirar entregas", err)
+	}
+
+	return requeued, nil
+}
+
+// DiscardDelivery descarta uma entrega pendente ou com falha, registrando o motivo
+func (uc *OutboxUseCase) DiscardDelivery(ctx context.Context, deliveryID, reason string) (*model.EventDelivery, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.NewValidationError("reason", "motivo do descarte é obrigatório")
+	}
+
+	delivery, err := uc.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if delivery.Status != model.DeliveryPending && delivery.Status != model.DeliveryFailed {
+		return nil, errors.NewConflictError("entrega", deliveryID, fmt.Sprintf("entrega na situação %s não pode ser descartada", delivery.Status))
+	}
+
+	now := time.Now()
+	if err := uc.deliveryRepository.Discard(ctx, deliveryID, reason, now); err != nil {
+		return nil, err
+	}
+
+	delivery.Status = model.DeliveryDiscarded
+	delivery.DiscardReason = &reason
+	delivery.UpdatedAt = now
+
+	return delivery, nil
+}
+
+// GetMetrics calcula o volume pendente e o atraso de publicação das entregas concluídas na janela informada
+func (uc *OutboxUseCase) GetMetrics(ctx context.Context, window time.Duration) (*model.OutboxMetrics, error) {
+	if window <= 0 {
+		window = DefaultOutboxMetricsWindow
+	}
+
+	metrics, err := uc.deliveryRepository.GetMetrics(ctx, time.Now().Add(-window))
+	if err != nil {
+		return nil, errors.NewDatabaseError("calcular métricas do outbox", err)
+	}
+	metrics.WindowSeconds = window.Seconds()
+
+	return metrics, nil
+}
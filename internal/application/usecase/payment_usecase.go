@@ -0,0 +1,309 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// PaymentUseCase implementa os casos de uso relacionados a pagamentos
+type PaymentUseCase struct {
+	paymentRepository  repository.PaymentRepository
+	timelineRepository repository.TimelineRepository
+}
+
+// NewPaymentUseCase cria uma nova instância do PaymentUseCase
+func NewPaymentUseCase(paymentRepo repository.PaymentRepository, timelineRepo repository.TimelineRepository) *PaymentUseCase {
+	return &PaymentUseCase{
+		paymentRepository:  paymentRepo,
+		timelineRepository: timelineRepo,
+	}
+}
+
+// CreatePayment cria um novo pagamento
+func (uc *PaymentUseCase) CreatePayment(ctx context.Context, payment *model.Payment) (*model.Payment, error) {
+	if err := validatePayment(payment); err != nil {
+		return nil, err
+	}
+
+	// Um ID já cadastrado retorna erro de conflito
+	createdPayment, err := uc.paymentRepository.Create(ctx, payment)
+	if errors.IsConflictError(err) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.NewDatabaseError("criar", err)
+	}
+
+	return createdPayment, nil
+}
+
+// ConsumePayment grava o pagamento de uma mensagem recebida por fila pelo consumidor informado. O broker
+// entrega cada mensagem ao menos uma vez; o registro do message_id na mesma transação do pagamento garante
+// que cada mensagem produza no máximo um pagamento. Uma reentrega de mensagem já processada retorna
+// MessageDuplicate sem gravar nada, e deve ser confirmada ao broker como as demais. Em caso de erro, nada
+// é registrado e a mensagem pode ser reentregue
+func (uc *PaymentUseCase) ConsumePayment(ctx context.Context, consumer, messageID string, payment *model.Payment) (model.MessageOutcome, error) {
+	if consumer == "" {
+		return "", errors.NewValidationError("consumer", "consumidor da mensagem não pode ser vazio")
+	}
+	if messageID == "" {
+		return "", errors.NewValidationError("message_id", "ID da mensagem não pode ser vazio")
+	}
+	if err := validatePayment(payment); err != nil {
+		return "", err
+	}
+
+	created, err := uc.paymentRepository.CreateFromMessage(ctx, model.NewProcessedMessage(consumer, messageID, payment.ID), payment)
+	if errors.IsConflictError(err) || errors.IsValidationError(err) || errors.IsForbiddenError(err) {
+		return "", err
+	}
+	if err != nil {
+		return "", errors.NewDatabaseError("consumir mensagem de pagamento", err)
+	}
+
+	if !created {
+		log.Printf("mensagem %s do consumidor %s já processada, reentrega descartada", messageID, consumer)
+		return model.MessageDuplicate, nil
+	}
+
+	recordTimeline(ctx, uc.timelineRepository, model.NewTimelineEntry(model.TimelinePayment, payment.ID, model.TimelineImported,
+		fmt.Sprintf("recebido pela fila %s (mensagem %s)", consumer, messageID), ""))
+
+	return model.MessageProcessed, nil
+}
+
+// GetPaymentByID busca um pagamento pelo ID
+func (uc *PaymentUseCase) GetPaymentByID(ctx context.Context, paymentID string) (*model.Payment, error) {
+	if paymentID == "" {
+		return nil, errors.NewValidationError("transaction_id", "ID do pagamento não pode ser vazio")
+	}
+
+	payment, err := uc.paymentRepository.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// ListPayments lista pagamentos com base em parâmetros de filtro
+func (uc *PaymentUseCase) ListPayments(ctx context.Context, params map[string]string) ([]*model.Payment, error) {
+	payments, err := uc.paymentRepository.List(ctx, createPaymentFilter(params))
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar", err)
+	}
+
+	return payments, nil
+}
+
+// ImportPayments importa uma lista de pagamentos. No modo best_effort (padrão) cada item é gravado
+// individualmente e os inválidos ou que falharem são reportados pelo índice no lote; no modo
+// all_or_nothing os pagamentos são gravados em uma única transação, e qualquer erro aborta o lote inteiro
+func (uc *PaymentUseCase) ImportPayments(ctx context.Context, payments []*model.Payment, mode model.ImportMode) (*ImportResult, error) {
+	result, err := newImportResult(mode, len(payments))
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Mode == model.ImportModeAllOrNothing {
+		result = uc.importPaymentsAtomically(ctx, payments, result)
+		uc.recordImports(ctx, payments, result)
+		return result, nil
+	}
+
+	for i, payment := range payments {
+		if err := validatePayment(payment); err != nil {
+			result.addItem(i, paymentID(payment), err)
+			continue
+		}
+
+		_, err := uc.paymentRepository.Create(ctx, payment)
+		if errors.IsConflictError(err) {
+			// Pagamentos duplicados são ignorados e reportados como erro do item
+			err = errors.NewConflictError("pagamento", payment.ID, "pagamento já existe e foi ignorado")
+		} else if err != nil {
+			err = errors.NewDatabaseError("salvar pagamento", err)
+		}
+		result.addItem(i, payment.ID, err)
+	}
+
+	uc.recordImports(ctx, payments, result)
+
+	return result, nil
+}
+
+// recordImports registra na linha do tempo dos pagamentos gravados a importação em lote
+func (uc *PaymentUseCase) recordImports(ctx context.Context, payments []*model.Payment, result *ImportResult) {
+	description := fmt.Sprintf("importado em lote de %d pagamentos (modo %s)", len(payments), result.Mode)
+
+	entries := make([]*model.TimelineEntry, 0, result.Imported)
+	for _, item := range result.Items {
+		if item.Success {
+			entries = append(entries, model.NewTimelineEntry(model.TimelinePayment, item.ID, model.TimelineImported, description, ""))
+		}
+	}
+
+	recordTimeline(ctx, uc.timelineRepository, entries...)
+}
+
+// importPaymentsAtomically valida todos os pagamentos e os grava em uma única transação; qualquer item
+// inválido ou falha na gravação desfaz o lote inteiro
+func (uc *PaymentUseCase) importPaymentsAtomically(ctx context.Context, payments []*model.Payment, result *ImportResult) *ImportResult {
+	ids := make([]string, len(payments))
+	itemErrors := make(map[int]error)
+	for i, payment := range payments {
+		ids[i] = paymentID(payment)
+		if err := validatePayment(payment); err != nil {
+			itemErrors[i] = err
+		}
+	}
+
+	if len(itemErrors) > 0 {
+		result.abort(ids, itemErrors, fmt.Errorf("lote abortado: %d item(ns) inválido(s)", len(itemErrors)))
+		return result
+	}
+
+	if err := uc.paymentRepository.CreateMany(ctx, payments); err != nil {
+		result.abort(ids, itemErrors, errors.NewDatabaseError("salvar lote de pagamentos", err))
+		return result
+	}
+
+	for i, id := range ids {
+		result.addItem(i, id, nil)
+	}
+
+	return result
+}
+
+// paymentID retorna o ID de um item de lote, que pode ser nulo
+func paymentID(payment *model.Payment) string {
+	if payment == nil {
+		return ""
+	}
+	return payment.ID
+}
+
+// UpdatePayment atualiza um pagamento existente
+func (uc *PaymentUseCase) UpdatePayment(ctx context.Context, payment *model.Payment) (*model.Payment, error) {
+	if err := validatePayment(payment); err != nil {
+		return nil, err
+	}
+
+	updatedPayment, err := uc.paymentRepository.Update(ctx, payment)
+	if errors.IsNotFoundError(err) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.NewDatabaseError("atualizar", err)
+	}
+
+	return updatedPayment, nil
+}
+
+// DeletePayment remove um pagamento pelo ID
+func (uc *PaymentUseCase) DeletePayment(ctx context.Context, paymentID string) error {
+	if paymentID == "" {
+		return errors.NewValidationError("transaction_id", "ID do pagamento não pode ser vazio")
+	}
+
+	err := uc.paymentRepository.Delete(ctx, paymentID)
+	if errors.IsNotFoundError(err) {
+		return err
+	}
+	if err != nil {
+		return errors.NewDatabaseError("excluir", err)
+	}
+
+	return nil
+}
+
+// validatePayment valida os dados de um pagamento
+func validatePayment(payment *model.Payment) error {
+	if payment == nil {
+		return errors.NewValidationError("", "pagamento não pode ser nulo")
+	}
+
+	if payment.ID == "" {
+		return errors.NewValidationError("transaction_id", "ID da transação é obrigatório")
+	}
+
+	if payment.BankAccount == "" {
+		return errors.NewValidationError("bank_account", "conta bancária é obrigatória")
+	}
+
+	if payment.Amount <= 0 {
+		return errors.NewValidationError("amount", "valor deve ser maior que zero")
+	}
+
+	if payment.PaymentDate.IsZero() {
+		return errors.NewValidationError("payment_date", "data do pagamento é obrigatória")
+	}
+
+	if payment.EntryType != model.EntryTypeCredit && payment.EntryType != model.EntryTypeDebit {
+		return errors.NewValidationError("entry_type", "tipo de lançamento deve ser credito ou debito")
+	}
+
+	if payment.BarCode != "" {
+		if _, err := model.NormalizeBarcode(payment.BarCode); err != nil {
+			return errors.NewValidationError("bar_code", err.Error())
+		}
+	}
+
+	if payment.PayerDocument != "" {
+		if err := model.ValidatePayerDocument(payment.PayerDocument); err != nil {
+			return errors.NewValidationError("payer_document", err.Error())
+		}
+	}
+
+	if !payment.PaymentMethod.IsValid() {
+		return errors.NewValidationError("payment_method", "meio de pagamento deve ser boleto, pix, transferencia ou deposito")
+	}
+
+	return nil
+}
+
+// createPaymentFilter cria um filtro para busca de pagamentos com base nos parâmetros
+func createPaymentFilter(params map[string]string) model.PaymentFilter {
+	filter := model.PaymentFilter{
+		BankAccount: params["bank_account"],
+		ReferenceID: params["reference_id"],
+		EntryType:   model.EntryType(params["entry_type"]),
+	}
+
+	// Filtros de data
+	if startDate, err := time.Parse("2006-01-02", params["start_date"]); err == nil {
+		filter.StartDate = &startDate
+	}
+
+	if endDate, err := time.Parse("2006-01-02", params["end_date"]); err == nil {
+		filter.EndDate = &endDate
+	}
+
+	// Filtros de valor
+	var minAmount, maxAmount float64
+	if _, err := fmt.Sscanf(params["min_amount"], "%f", &minAmount); err == nil {
+		filter.MinAmount = &minAmount
+	}
+
+	if _, err := fmt.Sscanf(params["max_amount"], "%f", &maxAmount); err == nil {
+		filter.MaxAmount = &maxAmount
+	}
+
+	// Filtros de paginação
+	var limit, offset int
+	if _, err := fmt.Sscanf(params["limit"], "%d", &limit); err == nil && limit > 0 {
+		filter.Limit = limit
+	}
+
+	if _, err := fmt.Sscanf(params["offset"], "%d", &offset); err == nil && offset >= 0 {
+		filter.Offset = offset
+	}
+
+	return filter
+}
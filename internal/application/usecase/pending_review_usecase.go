@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/domain/service"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// PendingReviewUseCase implementa a revisão noturna das pendências: reexecuta o matching incremental,
+// compara as pendências com as do dia anterior e mantém a série diária exibida no dashboard
+type PendingReviewUseCase struct {
+	reconciliationUseCase *ReconciliationUseCase
+	billetRepository      repository.BilletRepository
+	snapshotRepository    repository.PendingSnapshotRepository
+	eventPublisher        service.EventPublisher
+}
+
+// NewPendingReviewUseCase cria uma nova instância do PendingReviewUseCase
+func NewPendingReviewUseCase(
+	reconciliationUseCase *ReconciliationUseCase,
+	billetRepo repository.BilletRepository,
+	snapshotRepo repository.PendingSnapshotRepository,
+	eventPublisher service.EventPublisher,
+) *PendingReviewUseCase {
+	return &PendingReviewUseCase{
+		reconciliationUseCase: reconciliationUseCase,
+		billetRepository:      billetRepo,
+		snapshotRepository:    snapshotRepo,
+		eventPublisher:        eventPublisher,
+	}
+}
+
+// RunNightlyReview reexecuta a conciliação das pendências, grava o retrato das pendências do dia com a
+// variação em relação ao retrato anterior e publica o relatório "X boletos saíram de pendente, Y novos entraram"
+func (uc *PendingReviewUseCase) RunNightlyReview(ctx context.Context, day time.Time) (*model.PendingSnapshot, error) {
+	reconciled := 0
+
+	// Sem janela de datas a execução não é idempotente: cada noite concilia o que estiver pendente
+	result, err := uc.reconciliationUseCase.RunReconciliation(ctx, ReconciliationParams{})
+	switch {
+	case err == nil:
+		for _, billet := range result.ReconciledBillets {
+			if billet.ConciliationStatus.IsMatched() {
+				reconciled++
+			}
+		}
+	case errors.IsConflictError(err):
+		// Dia já fechado ou execução em andamento: o retrato das pendências continua útil
+		log.Printf("revisão noturna sem nova conciliação: %v", err)
+	default:
+		return nil, err
+	}
+
+	pending, err := uc.billetRepository.FindNonReconciled(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar boletos pendentes", err)
+	}
+
+	previous, err := uc.snapshotRepository.GetLatestBefore(ctx, day)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar retrato anterior das pendências", err)
+	}
+
+	snapshot := model.NewPendingSnapshot(day, pending)
+	snapshot.ReconciledInRun = reconciled
+	snapshot.CompareWith(previous)
+
+	if err := uc.snapshotRepository.Save(ctx, snapshot); err != nil {
+		return nil, errors.NewDatabaseError("gravar retrato das pendências", err)
+	}
+
+	uc.publishReport(ctx, snapshot)
+
+	return snapshot, nil
+}
+
+// GetSeries retorna a série diária de pendências do período (start_date e end_date, AAAA-MM-DD)
+func (uc *PendingReviewUseCase) GetSeries(ctx context.Context, params map[string]string) ([]*model.PendingSnapshot, error) {
+	_, startDate, endDate, err := parsePeriodParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := uc.snapshotRepository.GetSeries(ctx, startDate, endDate)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar série de pendências", err)
+	}
+
+	return series, nil
+}
+
+// publishReport publica o relatório noturno; falhas de publicação não desfazem o retrato gravado
+func (uc *PendingReviewUseCase) publishReport(ctx context.Context, snapshot *model.PendingSnapshot) {
+	if uc.eventPublisher == nil {
+		return
+	}
+
+	event := model.NewEvent(model.EventPendingReport, "", snapshot.PendingAmount)
+	event.Description = snapshot.Summary()
+
+	if err := uc.eventPublisher.Publish(ctx, []*model.Event{event}); err != nil {
+		log.Printf("erro ao publicar relatório de pendências: %v", err)
+	}
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// accountBlock reúne os boletos e pagamentos pendentes de uma conta bancária. Boletos e pagamentos de
+// contas diferentes nunca são pareados, então cada bloco é conciliado de forma independente
+type accountBlock struct {
+	account  string
+	billets  []*model.Billet
+	payments []*model.Payment
+}
+
+// billetBlock e paymentBlock são os blocos por conta lidos de cada cursor
+type billetBlock struct {
+	account string
+	billets []*model.Billet
+}
+
+type paymentBlock struct {
+	account  string
+	payments []*model.Payment
+}
+
+// streamAccountBlocks percorre os boletos e pagamentos pendentes dos filtros, ambos ordenados por conta
+// bancária, e chama fn com os de cada conta. Apenas a conta em conciliação e a próxima de cada cursor
+// ficam em memória
+func (uc *ReconciliationUseCase) streamAccountBlocks(
+	ctx context.Context,
+	billetFilter model.PendingFilter,
+	paymentFilter model.PendingFilter,
+	fn func(block accountBlock) error,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	billetBlocks := make(chan billetBlock)
+	billetErr := make(chan error, 1)
+	go func() {
+		defer close(billetBlocks)
+
+		var current billetBlock
+		send := func() error {
+			select {
+			case billetBlocks <- current:
+				current = billetBlock{}
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		err := uc.billetRepository.StreamNonReconciled(ctx, billetFilter, func(billet *model.Billet) error {
+			if len(current.billets) > 0 && billet.BankAccount != current.account {
+				if err := send(); err != nil {
+					return err
+				}
+			}
+			current.account = billet.BankAccount
+			current.billets = append(current.billets, billet)
+			return nil
+		})
+		if err == nil && len(current.billets) > 0 {
+			err = send()
+		}
+		billetErr <- err
+	}()
+
+	paymentBlocks := make(chan paymentBlock)
+	paymentErr := make(chan error, 1)
+	go func() {
+		defer close(paymentBlocks)
+
+		var current paymentBlock
+		send := func() error {
+			select {
+			case paymentBlocks <- current:
+				current = paymentBlock{}
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		err := uc.paymentRepository.StreamNonReconciled(ctx, paymentFilter, func(payment *model.Payment) error {
+			if len(current.payments) > 0 && payment.BankAccount != current.account {
+				if err := send(); err != nil {
+					return err
+				}
+			}
+			current.account = payment.BankAccount
+			current.payments = append(current.payments, payment)
+			return nil
+		})
+		if err == nil && len(current.payments) > 0 {
+			err = send()
+		}
+		paymentErr <- err
+	}()
+
+	// O erro de leitura de cada cursor é enviado antes do fechamento do canal de blocos
+	var streamErr error
+	closed := func(errs chan error, operation string) {
+		if err := <-errs; err != nil && streamErr == nil {
+			streamErr = errors.NewDatabaseError(operation, err)
+		}
+	}
+	nextBillets := func() (billetBlock, bool) {
+		block, ok := <-billetBlocks
+		if !ok {
+			closed(billetErr, "percorrer boletos não conciliados")
+		}
+		return block, ok
+	}
+	nextPayments := func() (paymentBlock, bool) {
+		block, ok := <-paymentBlocks
+		if !ok {
+			closed(paymentErr, "percorrer pagamentos não conciliados")
+		}
+		return block, ok
+	}
+
+	// stop interrompe os cursores ainda abertos e aguarda o fim da leitura
+	stop := func(billetsOpen, paymentsOpen bool) {
+		cancel()
+		if billetsOpen {
+			for range billetBlocks {
+			}
+			<-billetErr
+		}
+		if paymentsOpen {
+			for range paymentBlocks {
+			}
+			<-paymentErr
+		}
+	}
+
+	billets, billetsOK := nextBillets()
+	payments, paymentsOK := nextPayments()
+	for (billetsOK || paymentsOK) && streamErr == nil {
+		var block accountBlock
+		switch {
+		case billetsOK && (!paymentsOK || billets.account < payments.account):
+			block = accountBlock{account: billets.account, billets: billets.billets}
+			billets, billetsOK = nextBillets()
+		case paymentsOK && (!billetsOK || payments.account < billets.account):
+			block = accountBlock{account: payments.account, payments: payments.payments}
+			payments, paymentsOK = nextPayments()
+		default:
+			block = accountBlock{account: billets.account, billets: billets.billets, payments: payments.payments}
+			billets, billetsOK = nextBillets()
+			payments, paymentsOK = nextPayments()
+		}
+
+		// Um cursor interrompido deixaria a conta sem parte dos seus boletos ou pagamentos
+		if streamErr != nil {
+			break
+		}
+
+		if err := fn(block); err != nil {
+			stop(billetsOK, paymentsOK)
+			return err
+		}
+	}
+
+	stop(billetsOK, paymentsOK)
+	return streamErr
+}
+
+// groupAccountBlocks agrupa por conta bancária os boletos e pagamentos já carregados, na ordem das contas,
+// para que cada conta seja conciliada com as suas próprias regras
+func groupAccountBlocks(billets []*model.Billet, payments []*model.Payment) []accountBlock {
+	blocks := make(map[string]*accountBlock)
+	block := func(account string) *accountBlock {
+		if _, found := blocks[account]; !found {
+			blocks[account] = &accountBlock{account: account}
+		}
+		return blocks[account]
+	}
+
+	for _, billet := range billets {
+		current := block(billet.BankAccount)
+		current.billets = append(current.billets, billet)
+	}
+	for _, payment := range payments {
+		current := block(payment.BankAccount)
+		current.payments = append(current.payments, payment)
+	}
+
+	accounts := make([]string, 0, len(blocks))
+	for account := range blocks {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+
+	grouped := make([]accountBlock, 0, len(accounts))
+	for _, account := range accounts {
+		grouped = append(grouped, *blocks[account])
+	}
+	return grouped
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/domain/service"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// RankerUseCase implementa os casos de uso de revisão manual, treino e ativação do ranker de candidatos
+type RankerUseCase struct {
+	billetRepository      repository.BilletRepository
+	paymentRepository     repository.PaymentRepository
+	matchReviewRepository repository.MatchReviewRepository
+	rankerRepository      repository.RankerRepository
+}
+
+// NewRankerUseCase cria uma nova instância do RankerUseCase
+func NewRankerUseCase(
+	billetRepo repository.BilletRepository,
+	paymentRepo repository.PaymentRepository,
+	matchReviewRepo repository.MatchReviewRepository,
+	rankerRepo repository.RankerRepository,
+) *RankerUseCase {
+	return &RankerUseCase{
+		billetRepository:      billetRepo,
+		paymentRepository:     paymentRepo,
+		matchReviewRepository: matchReviewRepo,
+		rankerRepository:      rankerRepo,
+	}
+}
+
+// RecordReview registra a aprovação ou rejeição manual de um par boleto/pagamento,
+// calculando as características do par para o dataset de treino
+func (uc *RankerUseCase) RecordReview(ctx context.Context, tenantID, billetID, transactionID string, approved bool, reviewer string) (*model.MatchReview, error) {
+	if billetID == "" {
+		return nil, errors.NewValidationError("billet_id", "ID do boleto não pode ser vazio")
+	}
+
+	if transactionID == "" {
+		return nil, errors.NewValidationError("transaction_id", "ID do pagamento não pode ser vazio")
+	}
+
+	billet, err := uc.billetRepository.GetByID(ctx, billetID)
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := uc.paymentRepository.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	review := model.NewMatchReview(tenantID, billet.ID, payment.ID, approved, reviewer, service.ExtractMatchFeatures(billet, payment))
+	if err := uc.matchReviewRepository.Create(ctx, review); err != nil {
+		return nil, errors.NewDatabaseError("registrar revisão manual", err)
+	}
+
+	return review, nil
+}
+
+// ListReviews lista o dataset de revisões manuais de um tenant
+func (uc *RankerUseCase) ListReviews(ctx context.Context, tenantID string) ([]*model.MatchReview, error) {
+	reviews, err := uc.matchReviewRepository.GetByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar revisões manuais", err)
+	}
+
+	return reviews, nil
+}
+
+// GetRanker busca o modelo treinado de um tenant
+func (uc *RankerUseCase) GetRanker(ctx context.Context, tenantID string) (*model.RankerModel, error) {
+	rankerModel, err := uc.rankerRepository.GetByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar modelo do ranker", err)
+	}
+
+	if rankerModel == nil {
+		return nil, errors.NewNotFoundError("ranker", tenantID)
+	}
+
+	return rankerModel, nil
+}
+
+// TrainRanker treina o ranker do tenant com matches aprovados e rejeitados manualmente.
+// Sem amostras informadas, usa o dataset de revisões registradas do tenant.
+// Um modelo já ativo continua ativo com os novos pesos
+func (uc *RankerUseCase) TrainRanker(ctx context.Context, tenantID string, samples []model.RankerSample) (*model.RankerModel, error) {
+	if len(samples) == 0 {
+		reviews, err := uc.ListReviews(ctx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, review := range reviews {
+			samples = append(samples, review.ToRankerSample())
+		}
+	}
+
+	if len(samples) < service.MinRankerSamples {
+		return nil, errors.NewValidationError("samples", fmt.Sprintf("são necessárias ao menos %d revisões para treinar o ranker", service.MinRankerSamples))
+	}
+
+	var approved int
+	for _, sample := range samples {
+		if sample.Features.AmountDiffPercentage < 0 || sample.Features.DaysDiff < 0 {
+			return nil, errors.NewValidationError("samples", "diferenças de valor e de dias não podem ser negativas")
+		}
+		if sample.Approved {
+			approved++
+		}
+	}
+
+	if approved == 0 || approved == len(samples) {
+		return nil, errors.NewValidationError("samples", "o treino exige matches aprovados e rejeitados")
+	}
+
+	current, err := uc.rankerRepository.GetByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar modelo do ranker", err)
+	}
+
+	rankerModel := service.TrainLogisticRanker(tenantID, samples)
+	rankerModel.Enabled = current != nil && current.Enabled
+	rankerModel.TrainedAt = time.Now()
+
+	if err := uc.rankerRepository.Save(ctx, rankerModel); err != nil {
+		return nil, errors.NewDatabaseError("salvar modelo do ranker", err)
+	}
+
+	return rankerModel, nil
+}
+
+// SetRankerEnabled ativa ou desativa o ranker treinado de um tenant
+func (uc *RankerUseCase) SetRankerEnabled(ctx context.Context, tenantID string, enabled bool) (*model.RankerModel, error) {
+	rankerModel, err := uc.GetRanker(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	rankerModel.Enabled = enabled
+	if err := uc.rankerRepository.Save(ctx, rankerModel); err != nil {
+		return nil, errors.NewDatabaseError("salvar modelo do ranker", err)
+	}
+
+	return rankerModel, nil
+}
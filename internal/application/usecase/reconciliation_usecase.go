@@ -0,0 +1,2856 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/domain/service"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// DefaultClaimTTL define a duração padrão do bloqueio de um boleto em investigação
+const DefaultClaimTTL = 30 * time.Minute
+
+// MaxClaimTTL define a duração máxima permitida para o bloqueio de um boleto
+const MaxClaimTTL = 8 * time.Hour
+
+// ReconciliationUseCase implementa os casos de uso relacionados à conciliação
+type ReconciliationUseCase struct {
+	billetRepository         repository.BilletRepository
+	paymentRepository        repository.PaymentRepository
+	reconciliationRepository repository.ReconciliationRepository
+	claimRepository          repository.BilletClaimRepository
+	closingRepository        repository.DailyClosingRepository
+	runRepository            repository.ReconciliationRunRepository
+	timelineRepository       repository.TimelineRepository
+	accountRepository        repository.BankAccountRepository
+	creditRepository         repository.UnappliedCreditRepository
+	rankerRepository         repository.RankerRepository
+	ruleSetRepository        repository.RuleSetRepository
+	reconciliationService    service.ReconciliationService
+	bankRules                model.BankRules
+	eventPublisher           service.EventPublisher
+	exportUseCase            *ExportUseCase
+	shadowUseCase            *ShadowUseCase
+}
+
+// NewReconciliationUseCase cria uma nova instância do ReconciliationUseCase
+func NewReconciliationUseCase(
+	billetRepo repository.BilletRepository,
+	paymentRepo repository.PaymentRepository,
+	reconciliationRepo repository.ReconciliationRepository,
+	claimRepo repository.BilletClaimRepository,
+	closingRepo repository.DailyClosingRepository,
+	runRepo repository.ReconciliationRunRepository,
+	timelineRepo repository.TimelineRepository,
+	accountRepo repository.BankAccountRepository,
+	creditRepo repository.UnappliedCreditRepository,
+	rankerRepo repository.RankerRepository,
+	ruleSetRepo repository.RuleSetRepository,
+	reconciliationService service.ReconciliationService,
+	bankRules model.BankRules,
+	eventPublisher service.EventPublisher,
+	exportUseCase *ExportUseCase,
+	shadowUseCase *ShadowUseCase,
+) *ReconciliationUseCase {
+	return &ReconciliationUseCase{
+		billetRepository:         billetRepo,
+		paymentRepository:        paymentRepo,
+		reconciliationRepository: reconciliationRepo,
+		claimRepository:          claimRepo,
+		closingRepository:        closingRepo,
+		runRepository:            runRepo,
+		timelineRepository:       timelineRepo,
+		accountRepository:        accountRepo,
+		creditRepository:         creditRepo,
+		rankerRepository:         rankerRepo,
+		ruleSetRepository:        ruleSetRepo,
+		reconciliationService:    reconciliationService,
+		bankRules:                bankRules,
+		eventPublisher:           eventPublisher,
+		exportUseCase:            exportUseCase,
+		shadowUseCase:            shadowUseCase,
+	}
+}
+
+// ReconciliationParams representa os parâmetros de uma execução de conciliação
+type ReconciliationParams struct {
+	StartDate      time.Time
+	EndDate        time.Time
+	FilterAccounts []string
+	Tenant         string
+
+	// UseCredits habilita a estratégia que quita boletos em aberto com os créditos não aplicados do pagador
+	UseCredits bool
+
+	// Strategies define a ordem das estratégias e os parâmetros de cada uma; vazia, usa a ordem padrão
+	Strategies []model.StrategyConfig
+
+	// Tolerance substitui a tolerância percentual das estratégias que comparam valores e não têm
+	// tolerância própria; nula, usa a do serviço
+	Tolerance *float64
+
+	// AccountTolerances substitui Tolerance nas contas informadas, indexada pela conta bancária
+	AccountTolerances map[string]float64
+
+	// MaxDaysDiff limita, em dias, a diferença entre a emissão do boleto e o pagamento nas estratégias que
+	// comparam datas e não têm janela própria; nula, essas estratégias não têm janela de datas
+	MaxDaysDiff *int
+
+	// DryRun executa as estratégias e devolve o resultado completo sem gravar nada: nem conciliações,
+	// nem a execução, nem a marcação de pagamentos suspeitos, e sem publicar eventos
+	DryRun bool
+
+	// SuggestCorrections gera, para os boletos que não conciliaram, os pagamentos candidatos e as
+	// sugestões de correção do cadastro
+	SuggestCorrections bool
+}
+
+// validate verifica a ordem das estratégias, exige tolerâncias entre 0 e 100 e uma janela de datas
+// positiva. As tolerâncias por conta precisam estar entre as contas da execução, quando ela é restrita a
+// algumas contas
+func (p ReconciliationParams) validate() error {
+	if err := validateStrategies(p.Strategies); err != nil {
+		return err
+	}
+	if p.Tolerance != nil && (*p.Tolerance < 0 || *p.Tolerance > 100) {
+		return errors.NewValidationError("tolerance", "tolerância deve estar entre 0 e 100")
+	}
+	if p.MaxDaysDiff != nil && *p.MaxDaysDiff <= 0 {
+		return errors.NewValidationError("max_days_diff", "janela de datas deve ser maior que zero")
+	}
+
+	accounts := make(map[string]bool, len(p.FilterAccounts))
+	for _, account := range p.FilterAccounts {
+		accounts[account] = true
+	}
+	for account, tolerance := range p.AccountTolerances {
+		if tolerance < 0 || tolerance > 100 {
+			return errors.NewValidationError("account_tolerances", fmt.Sprintf("tolerância da conta %s deve estar entre 0 e 100", account))
+		}
+		if len(accounts) > 0 && !accounts[account] {
+			return errors.NewValidationError("account_tolerances", fmt.Sprintf("conta %s fora das contas da execução", account))
+		}
+	}
+	return nil
+}
+
+// runParams retorna os parâmetros registrados na execução
+func (p ReconciliationParams) runParams() *model.RunParams {
+	params := &model.RunParams{
+		BankAccounts:      p.FilterAccounts,
+		UseCredits:        p.UseCredits,
+		Strategies:        p.Strategies,
+		Tolerance:         p.Tolerance,
+		AccountTolerances: p.AccountTolerances,
+		MaxDaysDiff:       p.MaxDaysDiff,
+	}
+	if !p.StartDate.IsZero() {
+		params.StartDate = &p.StartDate
+	}
+	if !p.EndDate.IsZero() {
+		params.EndDate = &p.EndDate
+	}
+	return params
+}
+
+// SpecificReconciliationParams define os boletos e pagamentos de uma conciliação específica
+type SpecificReconciliationParams struct {
+	BilletIDs      []string
+	TransactionIDs []string
+	Tenant         string
+
+	// Tolerance substitui a tolerância percentual das estratégias que comparam valores; nula, usa a padrão
+	Tolerance *float64
+}
+
+// validate exige ao menos um boleto e um pagamento e uma tolerância entre 0 e 100
+func (p SpecificReconciliationParams) validate() error {
+	if len(p.BilletIDs) == 0 {
+		return errors.NewValidationError("billet_ids", "informe ao menos um boleto")
+	}
+	if len(p.TransactionIDs) == 0 {
+		return errors.NewValidationError("transaction_ids", "informe ao menos um pagamento")
+	}
+	if p.Tolerance != nil && (*p.Tolerance < 0 || *p.Tolerance > 100) {
+		return errors.NewValidationError("tolerance", "tolerância deve estar entre 0 e 100")
+	}
+	return nil
+}
+
+// runParams retorna os parâmetros registrados na execução
+func (p SpecificReconciliationParams) runParams() *model.RunParams {
+	return &model.RunParams{
+		BilletIDs:      p.BilletIDs,
+		TransactionIDs: p.TransactionIDs,
+		Tolerance:      p.Tolerance,
+	}
+}
+
+// StaleRunTimeout define após quanto tempo uma execução ainda em andamento é considerada abandonada
+// (ex.: processo interrompido), liberando seus parâmetros para uma nova execução
+const StaleRunTimeout = time.Hour
+
+// DefaultRunListLimit limita a listagem de execuções quando o limite não é informado
+const DefaultRunListLimit = 50
+
+// ParamsHash retorna o hash que identifica execuções repetidas: mesma janela de datas, mesmas contas
+// (em qualquer ordem), mesmo tenant, mesmo uso de créditos, mesma ordem de estratégias, mesmas
+// tolerâncias, mesma janela de dias, mesma versão do motor e mesmos conjuntos de regras do tenant, de
+// forma que alterar as regras ou atualizar o motor gere uma nova execução.
+// Sem janela de datas completa, retorna vazio e a execução não é idempotente
+func (p ReconciliationParams) ParamsHash(engineVersion string, ruleSets model.RuleSets) string {
+	if p.StartDate.IsZero() || p.EndDate.IsZero() {
+		return ""
+	}
+
+	accounts := append([]string(nil), p.FilterAccounts...)
+	sort.Strings(accounts)
+
+	var tolerance string
+	if p.Tolerance != nil {
+		tolerance = strconv.FormatFloat(*p.Tolerance, 'f', -1, 64)
+	}
+
+	accountTolerances := make([]string, 0, len(p.AccountTolerances))
+	for account, accountTolerance := range p.AccountTolerances {
+		accountTolerances = append(accountTolerances, account+":"+strconv.FormatFloat(accountTolerance, 'f', -1, 64))
+	}
+	sort.Strings(accountTolerances)
+
+	var maxDaysDiff string
+	if p.MaxDaysDiff != nil {
+		maxDaysDiff = strconv.Itoa(*p.MaxDaysDiff)
+	}
+
+	// Os conjuntos de regras entram pelo conteúdo, ordenados pela conta; o geral do tenant não tem conta
+	rules := make([]string, 0, len(ruleSets))
+	for _, ruleSet := range ruleSets {
+		rules = append(rules, ruleSet.BankAccount+"="+canonicalStrategies(ruleSet.Strategies))
+	}
+	sort.Strings(rules)
+
+	canonical := strings.Join([]string{
+		p.StartDate.UTC().Format(time.RFC3339Nano),
+		p.EndDate.UTC().Format(time.RFC3339Nano),
+		strings.Join(accounts, ","),
+		p.Tenant,
+		"creditos=" + strconv.FormatBool(p.UseCredits),
+		"estrategias=" + canonicalStrategies(p.Strategies),
+		"tolerancia=" + tolerance,
+		"tolerancia_contas=" + strings.Join(accountTolerances, ","),
+		"janela_dias=" + maxDaysDiff,
+		"motor=" + engineVersion,
+		"regras=" + strings.Join(rules, ";"),
+	}, "|")
+
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalStrategies representa a ordem de estratégias e os parâmetros de cada uma no hash da execução
+func canonicalStrategies(configs []model.StrategyConfig) string {
+	strategies := make([]string, 0, len(configs))
+	for _, config := range configs {
+		strategy := string(config.Strategy)
+		if config.Params.Tolerance != nil {
+			strategy += ":" + strconv.FormatFloat(*config.Params.Tolerance, 'f', -1, 64)
+		}
+		if config.Params.MaxDaysDiff != nil {
+			strategy += ":" + strconv.Itoa(*config.Params.MaxDaysDiff) + "d"
+		}
+		if config.Params.MinSimilarity != nil {
+			strategy += ":~" + strconv.FormatFloat(*config.Params.MinSimilarity, 'f', -1, 64)
+		}
+		strategies = append(strategies, strategy)
+	}
+	return strings.Join(strategies, ",")
+}
+
+// RunReconciliation executa a conciliação entre os boletos e pagamentos ainda não conciliados.
+// A execução é idempotente por janela de datas: repetida com os mesmos parâmetros, devolve o
+// resultado da execução anterior em vez de conciliar novamente
+func (uc *ReconciliationUseCase) RunReconciliation(ctx context.Context, params ReconciliationParams) (*model.ReconciliationResult, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	// A simulação não grava nada: não depende do fechamento do dia nem registra a execução
+	if params.DryRun {
+		result, err := uc.reconcile(ctx, "", params)
+		if err != nil {
+			return nil, err
+		}
+		result.DryRun = true
+		result.Tolerance = uc.effectiveTolerance(params.Tolerance)
+		return result, nil
+	}
+
+	if err := uc.ensureDayNotClosed(ctx, time.Now()); err != nil {
+		return nil, err
+	}
+
+	run, previous, err := uc.startRun(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		return previous, nil
+	}
+
+	result, err := uc.reconcile(ctx, run.ID, params)
+	if err != nil {
+		uc.failRun(ctx, run, result, err)
+		return nil, err
+	}
+
+	uc.completeRun(ctx, run, result)
+
+	return result, nil
+}
+
+// completeRun registra o resultado e o término da execução, publica o resumo da execução e envia o
+// arquivo de resultado ao ERP
+func (uc *ReconciliationUseCase) completeRun(ctx context.Context, run *model.ReconciliationRun, result *model.ReconciliationResult) {
+	result.RunID = run.ID
+	run.Complete(result)
+
+	// As conciliações já foram gravadas: uma falha aqui só impede o reaproveitamento do resultado
+	if err := uc.runRepository.Complete(ctx, run); err != nil {
+		log.Printf("erro ao concluir execução %s: %v", run.ID, err)
+	}
+
+	uc.publishEvents(ctx, []*model.Event{newRunSummaryEvent(run)})
+
+	// Envio do arquivo de resultado ao ERP; falhas ficam registradas para o reenvio manual. As execuções
+	// do ambiente sandbox não são enviadas
+	if uc.exportUseCase.Enabled() && !model.IsSandbox(ctx) {
+		if _, err := uc.exportUseCase.ExportRun(ctx, run); err != nil {
+			log.Printf("erro ao exportar resultado da execução %s: %v", run.ID, err)
+		}
+	}
+}
+
+// failRun registra a falha da execução. As conciliações das contas gravadas antes da falha continuam
+// válidas e referenciam a execução, que é mantida com o resultado parcial e libera os parâmetros para
+// que a execução possa ser repetida com os pendentes restantes
+func (uc *ReconciliationUseCase) failRun(ctx context.Context, run *model.ReconciliationRun, partial *model.ReconciliationResult, cause error) {
+	log.Printf("execução %s falhou: %v", run.ID, cause)
+
+	if partial != nil {
+		partial.RunID = run.ID
+	}
+	run.Fail(partial)
+
+	if err := uc.runRepository.Complete(ctx, run); err != nil {
+		log.Printf("erro ao registrar falha da execução %s: %v", run.ID, err)
+	}
+}
+
+// newRunSummaryEvent cria o evento de resumo da execução concluída
+func newRunSummaryEvent(run *model.ReconciliationRun) *model.Event {
+	event := model.NewEvent(model.EventReconciliationSummary, "", 0)
+	event.Summary = run.Summary()
+	event.Description = fmt.Sprintf("execução %s concluída com %d boletos conciliados, %d aguardando aprovação e %d sem conciliação (%.2f%% conciliados)",
+		run.ID, event.Summary.Totals.Reconciled, event.Summary.Totals.PendingApproval, event.Summary.Totals.NonReconciled, event.Summary.MatchRate)
+	return event
+}
+
+// startRun registra a execução. Quando já existe uma execução concluída com os mesmos parâmetros,
+// retorna o resultado dela; com uma execução em andamento, retorna erro de conflito
+func (uc *ReconciliationUseCase) startRun(ctx context.Context, params ReconciliationParams) (*model.ReconciliationRun, *model.ReconciliationResult, error) {
+	ruleSets, err := uc.tenantRuleSets(ctx, params.Tenant)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	engineVersion := uc.reconciliationService.Version()
+	run := model.NewReconciliationRun(params.ParamsHash(engineVersion, ruleSets), params.Tenant)
+	run.Tolerance = uc.effectiveTolerance(params.Tolerance)
+	run.EngineVersion = engineVersion
+	run.Params = params.runParams()
+
+	created, err := uc.runRepository.Create(ctx, run)
+	if err != nil {
+		return nil, nil, errors.NewDatabaseError("registrar execução de conciliação", err)
+	}
+	if created {
+		return run, nil, nil
+	}
+
+	existing, err := uc.runRepository.GetByParamsHash(ctx, run.ParamsHash)
+	if err != nil {
+		return nil, nil, errors.NewDatabaseError("buscar execução de conciliação", err)
+	}
+
+	switch {
+	case existing == nil:
+		// A execução anterior falhou e liberou os parâmetros entre as duas consultas
+		return nil, nil, errors.NewConflictError("execução", run.ParamsHash, "execução com os mesmos parâmetros acabou de falhar; tente novamente")
+	case existing.IsCompleted() && existing.Result != nil:
+		result := *existing.Result
+		result.Replayed = true
+		return nil, &result, nil
+	case time.Since(existing.StartedAt) > StaleRunTimeout:
+		// Execução abandonada: é registrada como falha, mantendo as conciliações que chegou a gravar, e a
+		// nova execução é registrada com os mesmos parâmetros
+		existing.Fail(existing.Result)
+		if err := uc.runRepository.Complete(ctx, existing); err != nil {
+			return nil, nil, errors.NewDatabaseError("encerrar execução abandonada", err)
+		}
+		return uc.startRun(ctx, params)
+	default:
+		return nil, nil, errors.NewConflictError("execução", existing.ID, "execução com os mesmos parâmetros em andamento")
+	}
+}
+
+// reconcile concilia os boletos e pagamentos pendentes da janela e contas informadas, persiste as
+// conciliações e as pendências da execução runID e publica os eventos do resultado. Os pendentes são
+// percorridos por cursor e conciliados conta a conta, sem carregar todo o período em memória
+func (uc *ReconciliationUseCase) reconcile(ctx context.Context, runID string, params ReconciliationParams) (*model.ReconciliationResult, error) {
+	ctx, err := uc.withTenantRanker(ctx, params.Tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.UseCredits {
+		ctx, err = uc.withAvailableCredits(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if params.SuggestCorrections {
+		ctx = service.WithSuggestions(ctx)
+	}
+
+	result := &model.ReconciliationResult{
+		ReconciledBillets:    []model.ReconciledBillet{},
+		NonReconciledBillets: []model.Billet{},
+	}
+
+	// O evento interno da execução acompanha o lote para que os consumidores distingam os
+	// resultados de uma execução completa dos de um rematch
+	events := []*model.Event{model.NewEvent(model.EventReconciliationRun, "", 0)}
+
+	ruleSets, err := uc.tenantRuleSets(ctx, params.Tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	billetFilter, paymentFilter := uc.pendingFilters(params)
+
+	// Em caso de falha, o resultado parcial e os eventos das contas já gravadas são mantidos
+	err = uc.streamAccountBlocks(ctx, billetFilter, paymentFilter, func(block accountBlock) error {
+		blockCtx := ctx
+		if strategies := accountStrategies(params, ruleSets, block.account); len(strategies) > 0 {
+			blockCtx = service.WithStrategies(ctx, strategies)
+		}
+
+		partial, blockEvents, err := uc.reconcileBlock(blockCtx, runID, params, block.billets, block.payments)
+		if err != nil {
+			return err
+		}
+
+		result.Merge(partial)
+		events = append(events, blockEvents...)
+		return nil
+	})
+
+	if !params.DryRun {
+		uc.publishEvents(ctx, events)
+	}
+
+	return result, err
+}
+
+// reconcileBlock concilia e persiste os boletos e pagamentos pendentes de uma conta bancária,
+// retornando o resultado e os eventos a publicar ao final da execução. Na simulação, o resultado é
+// devolvido sem persistir nada e sem eventos
+func (uc *ReconciliationUseCase) reconcileBlock(ctx context.Context, runID string, params ReconciliationParams, billets []*model.Billet, payments []*model.Payment) (*model.ReconciliationResult, []*model.Event, error) {
+	billets, payments = filterReconciliationInput(billets, payments, params, uc.bankRules)
+
+	billets, payments, exclusions, err := uc.excludeInactiveAccounts(ctx, billets, payments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := uc.flagOutliers(ctx, payments, !params.DryRun); err != nil {
+		return nil, nil, err
+	}
+
+	// O motor shadow, quando configurado, concilia os mesmos dados em paralelo; a simulação não tem
+	// execução registrada para o relatório de divergência
+	var shadow *shadowExecution
+	if !params.DryRun {
+		shadow = uc.shadowUseCase.start(ctx, billets, payments)
+	}
+
+	result, err := uc.reconciliationService.ReconcileBilletsWithPayments(ctx, billets, payments)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.InactiveAccounts = exclusions
+	recordPeakMemory(result)
+
+	// Os créditos usados na simulação não são abatidos: o saldo pode ser consumido até a execução real
+	if params.DryRun {
+		return result, nil, nil
+	}
+
+	if err := uc.debitCreditApplications(ctx, result, billets); err != nil {
+		return nil, nil, err
+	}
+
+	if err := uc.persistReconciledBillets(ctx, runID, result.ReconciledBillets); err != nil {
+		return nil, nil, err
+	}
+
+	if err := uc.persistNonReconciledBillets(ctx, runID, result.NonReconciledBillets); err != nil {
+		return nil, nil, err
+	}
+
+	if err := uc.persistAmbiguousReferences(ctx, runID, result.AmbiguousReferences, billets); err != nil {
+		return nil, nil, err
+	}
+
+	uc.recordCreditApplications(ctx, result.CreditApplications)
+
+	if err := uc.markIgnoredPayments(ctx, payments, result.IgnoredPayments); err != nil {
+		return nil, nil, err
+	}
+
+	if err := uc.persistUnappliedCredits(ctx, result.UnappliedCredits); err != nil {
+		return nil, nil, err
+	}
+
+	uc.shadowUseCase.record(ctx, runID, shadow, result)
+
+	return result, buildReconciliationEvents(result, billets, payments), nil
+}
+
+// recordPeakMemory amostra o heap em uso ao fim da conciliação de uma conta, enquanto os boletos e
+// pagamentos dela ainda estão em memória
+func recordPeakMemory(result *model.ReconciliationResult) {
+	if result.Metrics == nil {
+		return
+	}
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	result.Metrics.RecordMemory(stats.HeapAlloc)
+}
+
+// pendingFilters retorna os filtros dos boletos e pagamentos pendentes da execução: o período e as contas
+// pedidos, com o fim do período dos pagamentos estendido pelo maior prazo de crédito entre os bancos
+func (uc *ReconciliationUseCase) pendingFilters(params ReconciliationParams) (model.PendingFilter, model.PendingFilter) {
+	billetFilter := model.PendingFilter{
+		StartDate:    params.StartDate,
+		EndDate:      params.EndDate,
+		BankAccounts: params.FilterAccounts,
+	}
+
+	paymentFilter := billetFilter
+	if !params.EndDate.IsZero() {
+		paymentFilter.EndDate = uc.bankRules.LatestCreditDate(params.EndDate)
+	}
+
+	return billetFilter, paymentFilter
+}
+
+// loadReconciliationInput carrega os boletos e pagamentos pendentes do período e das contas pedidos e
+// aplica os demais filtros da execução, como o prazo de crédito de cada banco
+func (uc *ReconciliationUseCase) loadReconciliationInput(ctx context.Context, params ReconciliationParams) ([]*model.Billet, []*model.Payment, error) {
+	billetFilter, paymentFilter := uc.pendingFilters(params)
+
+	billets, err := uc.billetRepository.FindNonReconciledByFilter(ctx, billetFilter)
+	if err != nil {
+		return nil, nil, errors.NewDatabaseError("buscar boletos não conciliados", err)
+	}
+
+	payments, err := uc.paymentRepository.FindNonReconciledByFilter(ctx, paymentFilter)
+	if err != nil {
+		return nil, nil, errors.NewDatabaseError("buscar pagamentos não conciliados", err)
+	}
+
+	billets, payments = filterReconciliationInput(billets, payments, params, uc.bankRules)
+	return billets, payments, nil
+}
+
+// excludeInactiveAccounts remove os boletos e pagamentos de contas inativas no cadastro, retornando
+// por conta o que foi deixado fora da execução
+func (uc *ReconciliationUseCase) excludeInactiveAccounts(ctx context.Context, billets []*model.Billet, payments []*model.Payment) ([]*model.Billet, []*model.Payment, []model.InactiveAccountExclusion, error) {
+	inactive, err := uc.accountRepository.GetInactive(ctx)
+	if err != nil {
+		return nil, nil, nil, errors.NewDatabaseError("buscar contas inativas", err)
+	}
+
+	if len(inactive) == 0 {
+		return billets, payments, nil, nil
+	}
+
+	exclusions := make(map[string]*model.InactiveAccountExclusion, len(inactive))
+	for _, account := range inactive {
+		exclusions[account] = &model.InactiveAccountExclusion{BankAccount: account, Billets: []string{}, Payments: []string{}}
+	}
+
+	activeBillets := make([]*model.Billet, 0, len(billets))
+	for _, billet := range billets {
+		if exclusion, ok := exclusions[billet.BankAccount]; ok {
+			exclusion.Billets = append(exclusion.Billets, billet.ID)
+			continue
+		}
+		activeBillets = append(activeBillets, billet)
+	}
+
+	activePayments := make([]*model.Payment, 0, len(payments))
+	for _, payment := range payments {
+		if exclusion, ok := exclusions[payment.BankAccount]; ok {
+			exclusion.Payments = append(exclusion.Payments, payment.ID)
+			continue
+		}
+		activePayments = append(activePayments, payment)
+	}
+
+	// Só entram no relatório as contas que tinham registros na execução
+	var report []model.InactiveAccountExclusion
+	for _, account := range inactive {
+		exclusion := exclusions[account]
+		if len(exclusion.Billets) > 0 || len(exclusion.Payments) > 0 {
+			report = append(report, *exclusion)
+		}
+	}
+
+	return activeBillets, activePayments, report, nil
+}
+
+// DefaultWhatIfTolerances define as tolerâncias simuladas quando nenhuma é informada
+var DefaultWhatIfTolerances = []float64{1, 3, 5}
+
+// MaxWhatIfTolerances limita a quantidade de tolerâncias simuladas em uma requisição
+const MaxWhatIfTolerances = 10
+
+// SimulateTolerances executa a conciliação em memória com cada tolerância informada e retorna a taxa
+// de conciliação resultante, sem persistir conciliações nem publicar eventos. Cada simulação usa o
+// mesmo serviço e as mesmas regras de cada conta da conciliação, trocando apenas a tolerância das
+// estratégias sem tolerância própria
+func (uc *ReconciliationUseCase) SimulateTolerances(ctx context.Context, params ReconciliationParams, tolerances []float64) ([]model.ToleranceSimulation, error) {
+	if len(tolerances) == 0 {
+		tolerances = DefaultWhatIfTolerances
+	}
+
+	if len(tolerances) > MaxWhatIfTolerances {
+		return nil, errors.NewValidationError("tolerances", fmt.Sprintf("no máximo %d tolerâncias por simulação", MaxWhatIfTolerances))
+	}
+
+	for _, tolerance := range tolerances {
+		if tolerance < 0 || tolerance > 100 {
+			return nil, errors.NewValidationError("tolerances", "tolerâncias devem estar entre 0 e 100")
+		}
+	}
+
+	billets, payments, err := uc.loadReconciliationInput(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	billets, payments, _, err = uc.excludeInactiveAccounts(ctx, billets, payments)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err = uc.withTenantRanker(ctx, params.Tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleSets, err := uc.tenantRuleSets(ctx, params.Tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := groupAccountBlocks(billets, payments)
+
+	simulations := make([]model.ToleranceSimulation, 0, len(tolerances))
+	for _, tolerance := range tolerances {
+		// A tolerância simulada substitui a da execução e as das contas
+		toleranceParams := params
+		toleranceParams.Tolerance = &tolerance
+		toleranceParams.AccountTolerances = nil
+
+		result := &model.ReconciliationResult{}
+		for _, block := range blocks {
+			blockCtx := service.WithStrategies(ctx, accountStrategies(toleranceParams, ruleSets, block.account))
+
+			partial, err := uc.reconciliationService.ReconcileBilletsWithPayments(blockCtx, block.billets, block.payments)
+			if err != nil {
+				return nil, err
+			}
+			result.Merge(partial)
+		}
+
+		simulation := model.ToleranceSimulation{
+			TolerancePercentage:  tolerance,
+			TotalBillets:         len(billets),
+			ReconciledBillets:    len(result.ReconciledBillets),
+			AmbiguousReferences:  len(result.AmbiguousReferences),
+			NonReconciledBillets: len(result.NonReconciledBillets),
+		}
+
+		for _, reconciled := range result.ReconciledBillets {
+			if reconciled.ConciliationStatus == model.StatusSuccessful || reconciled.ConciliationStatus == model.StatusPaidWithInterest {
+				simulation.SuccessfulMatches++
+			} else {
+				simulation.DifferentValueMatches++
+			}
+		}
+
+		if simulation.TotalBillets > 0 {
+			simulation.ReconciliationRate = float64(simulation.ReconciledBillets) / float64(simulation.TotalBillets) * 100
+		}
+
+		simulations = append(simulations, simulation)
+	}
+
+	return simulations, nil
+}
+
+// RematchBillet executa novamente o pipeline de estratégias apenas para um boleto,
+// contra os pagamentos ainda não utilizados da conta dele, persistindo o resultado.
+// As estratégias são as do conjunto de regras do tenant para a conta do boleto.
+// O boleto não pode estar bloqueado por outro analista
+func (uc *ReconciliationUseCase) RematchBillet(ctx context.Context, billetID, tenant, actor string) (*model.ReconciliationResult, error) {
+	if billetID == "" {
+		return nil, errors.NewValidationError("billet_id", "ID do boleto não pode ser vazio")
+	}
+
+	billet, err := uc.billetRepository.GetByID(ctx, billetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.ensureNotClaimedByOther(ctx, billetID, actor); err != nil {
+		return nil, err
+	}
+
+	if err := uc.ensureDayNotClosed(ctx, time.Now()); err != nil {
+		return nil, err
+	}
+
+	account, err := uc.accountRepository.GetByAccount(ctx, billet.BankAccount)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar conta bancária", err)
+	}
+	if !account.IsActive() {
+		return nil, errors.NewValidationError("bank_account", fmt.Sprintf("conta bancária %s está inativa", billet.BankAccount))
+	}
+
+	// Um boleto já conciliado não deve ser pareado novamente
+	reconciliations, err := uc.reconciliationRepository.GetByBilletID(ctx, billetID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar conciliações do boleto", err)
+	}
+
+	for _, reconciliation := range reconciliations {
+		if reconciliation.ConciliationStatus.IsReserved() {
+			return nil, errors.NewConflictError("boleto", billetID, "boleto já conciliado")
+		}
+	}
+
+	ctx, err = uc.withTenantRanker(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleSets, err := uc.tenantRuleSets(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	strategies := accountStrategies(ReconciliationParams{Tenant: tenant}, ruleSets, billet.BankAccount)
+	if len(strategies) > 0 {
+		ctx = service.WithStrategies(ctx, strategies)
+	}
+
+	payments, err := uc.paymentRepository.FindNonReconciledByFilter(ctx, rematchPaymentFilter(billet, maxStrategyTolerance(strategies, uc.reconciliationService.DefaultTolerance())))
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar pagamentos não conciliados", err)
+	}
+
+	result, err := uc.reconciliationService.ReconcileBilletsWithPayments(ctx, []*model.Billet{billet}, payments)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.persistReconciledBillets(ctx, "", result.ReconciledBillets); err != nil {
+		return nil, err
+	}
+
+	if err := uc.markIgnoredPayments(ctx, payments, result.IgnoredPayments); err != nil {
+		return nil, err
+	}
+
+	if err := uc.persistUnappliedCredits(ctx, result.UnappliedCredits); err != nil {
+		return nil, err
+	}
+
+	// Pagamentos órfãos só são notificados nas execuções completas
+	uc.publishEvents(ctx, buildReconciliationEvents(result, []*model.Billet{billet}, nil))
+
+	return result, nil
+}
+
+// rematchPaymentFilter restringe os pagamentos do rematch à conta do boleto e aos valores que ele ainda
+// pode absorver: o valor devido hoje, com multa e juros, mais a tolerância. Os pagamentos menores
+// continuam na faixa para as estratégias que somam vários pagamentos; um pagamento maior só quita o
+// boleto junto com outros, o que fica para as execuções completas
+func rematchPaymentFilter(billet *model.Billet, tolerance float64) model.PendingFilter {
+	return model.PendingFilter{
+		BankAccounts: []string{billet.BankAccount},
+		MaxAmount:    billet.ExpectedAmount(time.Now()) * (1 + tolerance/100),
+	}
+}
+
+// maxStrategyTolerance retorna a maior tolerância entre a padrão e as próprias das estratégias, que
+// limita a faixa de valores dos pagamentos candidatos
+func maxStrategyTolerance(strategies []model.StrategyConfig, defaultTolerance float64) float64 {
+	tolerance := defaultTolerance
+	for _, config := range strategies {
+		if config.Strategy.AcceptsTolerance() && config.Params.Tolerance != nil && *config.Params.Tolerance > tolerance {
+			tolerance = *config.Params.Tolerance
+		}
+	}
+	return tolerance
+}
+
+// ReconcileSpecific concilia apenas os boletos e pagamentos informados, com a tolerância pedida e as
+// regras do tenant para cada conta, e registra o resultado como uma execução própria. IDs inexistentes e
+// boletos ou pagamentos já conciliados são ignorados
+func (uc *ReconciliationUseCase) ReconcileSpecific(ctx context.Context, params SpecificReconciliationParams) (*model.ReconciliationResult, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := uc.ensureDayNotClosed(ctx, time.Now()); err != nil {
+		return nil, err
+	}
+
+	billets, payments, err := uc.loadSpecificInput(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err = uc.withTenantRanker(ctx, params.Tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleSets, err := uc.tenantRuleSets(ctx, params.Tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sem hash de parâmetros, a execução não é idempotente: cada chamada concilia o que ainda estiver pendente
+	run := model.NewReconciliationRun("", params.Tenant)
+	run.Tolerance = uc.effectiveTolerance(params.Tolerance)
+	run.EngineVersion = uc.reconciliationService.Version()
+	run.Params = params.runParams()
+	if _, err := uc.runRepository.Create(ctx, run); err != nil {
+		return nil, errors.NewDatabaseError("registrar execução de conciliação", err)
+	}
+
+	blockParams := ReconciliationParams{Tenant: params.Tenant, Tolerance: params.Tolerance}
+	result := &model.ReconciliationResult{
+		ReconciledBillets:    []model.ReconciledBillet{},
+		NonReconciledBillets: []model.Billet{},
+	}
+	events := []*model.Event{model.NewEvent(model.EventReconciliationRun, "", 0)}
+
+	for _, block := range groupAccountBlocks(billets, payments) {
+		blockCtx := ctx
+		if strategies := accountStrategies(blockParams, ruleSets, block.account); len(strategies) > 0 {
+			blockCtx = service.WithStrategies(ctx, strategies)
+		}
+
+		partial, blockEvents, err := uc.reconcileBlock(blockCtx, run.ID, blockParams, block.billets, block.payments)
+		if err != nil {
+			uc.publishEvents(ctx, events)
+			uc.failRun(ctx, run, result, err)
+			return nil, err
+		}
+
+		result.Merge(partial)
+		events = append(events, blockEvents...)
+	}
+
+	uc.publishEvents(ctx, events)
+	uc.completeRun(ctx, run, result)
+
+	return result, nil
+}
+
+// loadSpecificInput carrega os boletos e pagamentos informados que ainda não foram conciliados
+func (uc *ReconciliationUseCase) loadSpecificInput(ctx context.Context, params SpecificReconciliationParams) ([]*model.Billet, []*model.Payment, error) {
+	billets, err := uc.billetRepository.GetByIDs(ctx, params.BilletIDs)
+	if err != nil {
+		return nil, nil, errors.NewDatabaseError("buscar boletos", err)
+	}
+
+	payments, err := uc.paymentRepository.GetByIDs(ctx, params.TransactionIDs)
+	if err != nil {
+		return nil, nil, errors.NewDatabaseError("buscar pagamentos", err)
+	}
+
+	pendingBillets := make([]*model.Billet, 0, len(billets))
+	for _, billet := range billets {
+		reconciliations, err := uc.reconciliationRepository.GetByBilletID(ctx, billet.ID)
+		if err != nil {
+			return nil, nil, errors.NewDatabaseError("buscar conciliações do boleto", err)
+		}
+		if !hasMatch(reconciliations) {
+			pendingBillets = append(pendingBillets, billet)
+		}
+	}
+
+	pendingPayments := make([]*model.Payment, 0, len(payments))
+	for _, payment := range payments {
+		reconciliations, err := uc.reconciliationRepository.GetByTransactionID(ctx, payment.ID)
+		if err != nil {
+			return nil, nil, errors.NewDatabaseError("buscar conciliações do pagamento", err)
+		}
+		if !hasMatch(reconciliations) {
+			pendingPayments = append(pendingPayments, payment)
+		}
+	}
+
+	return pendingBillets, pendingPayments, nil
+}
+
+// hasMatch indica se alguma das conciliações pareou o boleto ou o pagamento, ainda que por sugestão
+func hasMatch(reconciliations []*model.Reconciliation) bool {
+	for _, reconciliation := range reconciliations {
+		if reconciliation.ConciliationStatus.IsReserved() {
+			return true
+		}
+	}
+	return false
+}
+
+// strategiesWithTolerance retorna uma cópia da ordem das estratégias (a padrão, quando vazia) com a tolerância
+// aplicada às que comparam valores e não têm tolerância própria
+func strategiesWithTolerance(strategies []model.StrategyConfig, tolerance float64) []model.StrategyConfig {
+	if len(strategies) == 0 {
+		strategies = model.DefaultStrategyConfigs()
+	} else {
+		strategies = append([]model.StrategyConfig(nil), strategies...)
+	}
+
+	for i := range strategies {
+		if strategies[i].Strategy.AcceptsTolerance() && strategies[i].Params.Tolerance == nil {
+			strategies[i].Params.Tolerance = &tolerance
+		}
+	}
+	return strategies
+}
+
+// strategiesWithDateWindow retorna uma cópia da ordem das estratégias (a padrão, quando vazia) com a janela
+// de datas aplicada às que comparam datas e não têm janela própria
+func strategiesWithDateWindow(strategies []model.StrategyConfig, maxDaysDiff int) []model.StrategyConfig {
+	if len(strategies) == 0 {
+		strategies = model.DefaultStrategyConfigs()
+	} else {
+		strategies = append([]model.StrategyConfig(nil), strategies...)
+	}
+
+	for i := range strategies {
+		if strategies[i].Strategy.AcceptsDateWindow() && strategies[i].Params.MaxDaysDiff == nil {
+			strategies[i].Params.MaxDaysDiff = &maxDaysDiff
+		}
+	}
+	return strategies
+}
+
+// accountStrategies retorna a ordem das estratégias de uma conta: a informada na execução ou, sem ela, a
+// do conjunto de regras do tenant que vale para a conta. As estratégias sem tolerância própria recebem a
+// tolerância da conta ou, sem ela, a da execução, e as sem janela de datas própria recebem a da execução.
+// Vazia, o serviço usa a ordem, a tolerância e a janela de datas padrão
+func accountStrategies(params ReconciliationParams, ruleSets model.RuleSets, account string) []model.StrategyConfig {
+	strategies := params.Strategies
+	if len(strategies) == 0 {
+		if ruleSet := ruleSets.For(account); ruleSet != nil {
+			strategies = ruleSet.Strategies
+		}
+	}
+
+	tolerance := params.Tolerance
+	if accountTolerance, found := params.AccountTolerances[account]; found {
+		tolerance = &accountTolerance
+	}
+
+	if tolerance != nil {
+		strategies = strategiesWithTolerance(strategies, *tolerance)
+	}
+	if params.MaxDaysDiff != nil {
+		strategies = strategiesWithDateWindow(strategies, *params.MaxDaysDiff)
+	}
+	return strategies
+}
+
+// tenantRuleSets recupera os conjuntos de regras de matching configurados para o tenant
+func (uc *ReconciliationUseCase) tenantRuleSets(ctx context.Context, tenant string) (model.RuleSets, error) {
+	if tenant == "" {
+		return nil, nil
+	}
+
+	ruleSets, err := uc.ruleSetRepository.GetByTenant(ctx, tenant)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar conjuntos de regras", err)
+	}
+	return ruleSets, nil
+}
+
+// effectiveTolerance retorna a tolerância percentual padrão de uma execução: a informada ou a do serviço
+func (uc *ReconciliationUseCase) effectiveTolerance(tolerance *float64) float64 {
+	if tolerance != nil {
+		return *tolerance
+	}
+	return uc.reconciliationService.DefaultTolerance()
+}
+
+// ReevaluateBillet reavalia as conciliações com valor diferente de um boleto após a correção do seu valor
+// (ex.: pelo ERP), comparando o valor atual do boleto com o do pagamento conciliado. Quando os valores
+// passam a coincidir, a conciliação vira conciliado_com_sucesso; caso contrário, a diferença é atualizada.
+// Cada mudança é registrada no histórico de status. O boleto não pode estar bloqueado por outro analista
+func (uc *ReconciliationUseCase) ReevaluateBillet(ctx context.Context, billetID, actor string) (*model.ReevaluationResult, error) {
+	if billetID == "" {
+		return nil, errors.NewValidationError("billet_id", "ID do boleto não pode ser vazio")
+	}
+
+	billet, err := uc.billetRepository.GetByID(ctx, billetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.ensureNotClaimedByOther(ctx, billetID, actor); err != nil {
+		return nil, err
+	}
+
+	reconciliations, err := uc.reconciliationRepository.GetByBilletID(ctx, billetID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar conciliações do boleto", err)
+	}
+
+	result := &model.ReevaluationResult{BilletID: billetID, Changes: []*model.ReconciliationStatusChange{}}
+	for _, reconciliation := range reconciliations {
+		if reconciliation.ConciliationStatus != model.StatusDifferentValue || reconciliation.TransactionID == nil {
+			continue
+		}
+
+		if err := uc.ensureDayNotClosed(ctx, reconciliation.ReconciliationDate); err != nil {
+			return nil, err
+		}
+
+		payment, err := uc.paymentRepository.GetByID(ctx, *reconciliation.TransactionID)
+		if errors.IsNotFoundError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.NewDatabaseError("buscar pagamento conciliado", err)
+		}
+
+		paidAt := uc.bankRules.For(model.PaymentBankCode(payment, billet)).PaymentDate(payment.PaymentDate)
+		amountDiff, _, lateCharges := billet.AmountDiffAt(payment.Amount, paidAt)
+		amountDiff = math.Round(amountDiff*100) / 100
+		status := model.AmountMatchStatus(amountDiff, lateCharges)
+
+		if status == reconciliation.ConciliationStatus && amountDiff == reconciliation.AmountDiff {
+			continue
+		}
+
+		reason := fmt.Sprintf("valor do boleto corrigido para %.2f; pagamento de %.2f", billet.Amount, payment.Amount)
+		change := model.NewReconciliationStatusChange(reconciliation, status, amountDiff, reason, actor)
+		if err := uc.reconciliationRepository.UpdateStatus(ctx, change); err != nil {
+			return nil, errors.NewDatabaseError("atualizar status da conciliação", err)
+		}
+
+		change.Apply(reconciliation)
+		result.Changes = append(result.Changes, change)
+	}
+
+	return result, nil
+}
+
+// GetStatusChanges recupera o histórico de mudanças de status das conciliações de um boleto
+func (uc *ReconciliationUseCase) GetStatusChanges(ctx context.Context, billetID string) ([]*model.ReconciliationStatusChange, error) {
+	if billetID == "" {
+		return nil, errors.NewValidationError("billet_id", "ID do boleto não pode ser vazio")
+	}
+
+	changes, err := uc.reconciliationRepository.GetStatusChanges(ctx, billetID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar histórico de status", err)
+	}
+
+	return changes, nil
+}
+
+// ClaimBillet bloqueia um boleto para o analista durante a investigação, impedindo que outro
+// analista o concilie manualmente em paralelo. Renovar o próprio bloqueio estende a expiração
+func (uc *ReconciliationUseCase) ClaimBillet(ctx context.Context, billetID, actor string, ttl time.Duration) (*model.BilletClaim, error) {
+	if billetID == "" {
+		return nil, errors.NewValidationError("billet_id", "ID do boleto não pode ser vazio")
+	}
+
+	if actor == "" {
+		return nil, errors.NewValidationError("claimed_by", "identificação do analista é obrigatória")
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultClaimTTL
+	}
+
+	if ttl > MaxClaimTTL {
+		return nil, errors.NewValidationError("ttl_seconds", fmt.Sprintf("duração máxima do bloqueio é %s", MaxClaimTTL))
+	}
+
+	if _, err := uc.billetRepository.GetByID(ctx, billetID); err != nil {
+		return nil, err
+	}
+
+	claim, acquired, err := uc.claimRepository.Acquire(ctx, model.NewBilletClaim(billetID, actor, ttl))
+	if err != nil {
+		return nil, errors.NewDatabaseError("bloquear boleto", err)
+	}
+
+	if !acquired {
+		return nil, claimConflict(claim)
+	}
+
+	return claim, nil
+}
+
+// ReleaseBilletClaim libera o bloqueio de um boleto mantido pelo analista
+func (uc *ReconciliationUseCase) ReleaseBilletClaim(ctx context.Context, billetID, actor string) error {
+	if billetID == "" {
+		return errors.NewValidationError("billet_id", "ID do boleto não pode ser vazio")
+	}
+
+	released, err := uc.claimRepository.Release(ctx, billetID, actor)
+	if err != nil {
+		return errors.NewDatabaseError("liberar bloqueio", err)
+	}
+
+	if released {
+		return nil
+	}
+
+	claim, err := uc.GetBilletClaim(ctx, billetID)
+	if err != nil {
+		return err
+	}
+
+	return claimConflict(claim)
+}
+
+// GetBilletClaim recupera o bloqueio vigente de um boleto
+func (uc *ReconciliationUseCase) GetBilletClaim(ctx context.Context, billetID string) (*model.BilletClaim, error) {
+	if billetID == "" {
+		return nil, errors.NewValidationError("billet_id", "ID do boleto não pode ser vazio")
+	}
+
+	if _, err := uc.billetRepository.GetByID(ctx, billetID); err != nil {
+		return nil, err
+	}
+
+	claim, err := uc.claimRepository.GetByBilletID(ctx, billetID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar bloqueio", err)
+	}
+
+	if !claim.IsActive(time.Now()) {
+		return nil, errors.NewNotFoundError("bloqueio do boleto", billetID)
+	}
+
+	return claim, nil
+}
+
+// ensureNotClaimedByOther impede a ação quando o boleto está bloqueado por outro analista
+func (uc *ReconciliationUseCase) ensureNotClaimedByOther(ctx context.Context, billetID, actor string) error {
+	claim, err := uc.claimRepository.GetByBilletID(ctx, billetID)
+	if err != nil {
+		return errors.NewDatabaseError("buscar bloqueio", err)
+	}
+
+	if claim.BlocksActor(actor, time.Now()) {
+		return claimConflict(claim)
+	}
+
+	return nil
+}
+
+// ensureDayNotClosed impede novas conciliações e alterações nas existentes em um dia cujo
+// fechamento já foi confirmado, pois os resultados desse dia estão congelados
+func (uc *ReconciliationUseCase) ensureDayNotClosed(ctx context.Context, day time.Time) error {
+	closing, err := uc.closingRepository.GetByDate(ctx, day)
+	if err != nil {
+		return errors.NewDatabaseError("buscar fechamento diário", err)
+	}
+
+	if closing.IsConfirmed() {
+		return errors.NewConflictError("fechamento", closing.Date.Format("2006-01-02"), "o dia tem fechamento confirmado e seus resultados estão congelados")
+	}
+
+	return nil
+}
+
+// claimConflict cria o erro de conflito indicando quem está com o boleto e até quando
+func claimConflict(claim *model.BilletClaim) error {
+	return errors.NewConflictError("boleto", claim.BilletID,
+		fmt.Sprintf("boleto em análise por %s até %s", claim.ClaimedBy, claim.ExpiresAt.Format(time.RFC3339)))
+}
+
+// GetReconciliationByID busca uma conciliação pelo ID
+func (uc *ReconciliationUseCase) GetReconciliationByID(ctx context.Context, reconciliationID string) (*model.Reconciliation, error) {
+	if reconciliationID == "" {
+		return nil, errors.NewValidationError("id", "ID da conciliação não pode ser vazio")
+	}
+
+	return uc.reconciliationRepository.GetByID(ctx, reconciliationID)
+}
+
+// GetRun recupera uma execução de conciliação com o resultado e as medições
+func (uc *ReconciliationUseCase) GetRun(ctx context.Context, runID string) (*model.ReconciliationRun, error) {
+	if runID == "" {
+		return nil, errors.NewValidationError("id", "ID da execução não pode ser vazio")
+	}
+
+	run, err := uc.runRepository.GetByID(ctx, runID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar execução de conciliação", err)
+	}
+	if run == nil {
+		return nil, errors.NewNotFoundError("execução", runID)
+	}
+
+	return run, nil
+}
+
+// GetShadowReport retorna o relatório de divergência entre o motor de produção e o motor shadow em uma execução
+func (uc *ReconciliationUseCase) GetShadowReport(ctx context.Context, runID string) (*model.ShadowReport, error) {
+	if _, err := uc.GetRun(ctx, runID); err != nil {
+		return nil, err
+	}
+
+	return uc.shadowUseCase.GetReport(ctx, runID)
+}
+
+// ListRuns lista as execuções de conciliação dos tenants informados por status e período de início,
+// das mais recentes para as mais antigas
+func (uc *ReconciliationUseCase) ListRuns(ctx context.Context, tenants []string, params map[string]string) ([]*model.ReconciliationRun, error) {
+	_, startDate, endDate, err := parsePeriodParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := model.ReconciliationRunFilter{
+		Tenants:       tenants,
+		Status:        model.ReconciliationRunStatus(params["status"]),
+		EngineVersion: params["engine_version"],
+		StartDate:     startDate,
+		EndDate:       endDate,
+		Limit:         DefaultRunListLimit,
+	}
+
+	if filter.Status != "" && filter.Status != model.RunInProgress && filter.Status != model.RunCompleted && filter.Status != model.RunFailed {
+		return nil, errors.NewValidationError("status", "status deve ser em_andamento, concluida ou falhou")
+	}
+
+	if limitStr, ok := params["limit"]; ok {
+		var limit int
+		if _, err := fmt.Sscanf(limitStr, "%d", &limit); err == nil && limit > 0 {
+			filter.Limit = limit
+		}
+	}
+
+	if offsetStr, ok := params["offset"]; ok {
+		var offset int
+		if _, err := fmt.Sscanf(offsetStr, "%d", &offset); err == nil && offset >= 0 {
+			filter.Offset = offset
+		}
+	}
+
+	runs, err := uc.runRepository.List(ctx, filter)
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar execuções de conciliação", err)
+	}
+
+	return runs, nil
+}
+
+// GetRunItems recupera uma execução de conciliação com as conciliações gravadas por ela. Conciliações
+// desfeitas depois da execução não são listadas
+func (uc *ReconciliationUseCase) GetRunItems(ctx context.Context, runID string) (*model.ReconciliationRun, []*model.Reconciliation, error) {
+	run, err := uc.GetRun(ctx, runID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, err := uc.reconciliationRepository.GetByFilter(ctx, model.ReconciliationFilter{RunID: runID})
+	if err != nil {
+		return nil, nil, errors.NewDatabaseError("listar conciliações da execução", err)
+	}
+
+	return run, items, nil
+}
+
+// GetReconciliationHistory recupera o histórico de conciliações de um boleto
+func (uc *ReconciliationUseCase) GetReconciliationHistory(ctx context.Context, billetID string) ([]*model.Reconciliation, error) {
+	if billetID == "" {
+		return nil, errors.NewValidationError("billet_id", "ID do boleto não pode ser vazio")
+	}
+
+	history, err := uc.reconciliationRepository.GetReconciliationHistory(ctx, billetID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar histórico", err)
+	}
+
+	return history, nil
+}
+
+// GetPaymentReconciliationHistory recupera o histórico de conciliações de um pagamento
+func (uc *ReconciliationUseCase) GetPaymentReconciliationHistory(ctx context.Context, transactionID string) ([]*model.Reconciliation, error) {
+	if transactionID == "" {
+		return nil, errors.NewValidationError("transaction_id", "ID do pagamento não pode ser vazio")
+	}
+
+	history, err := uc.reconciliationRepository.GetReconciliationHistoryByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar histórico do pagamento", err)
+	}
+
+	return history, nil
+}
+
+// ListExcludedPayments lista os lançamentos de débito, que são excluídos automaticamente da conciliação
+func (uc *ReconciliationUseCase) ListExcludedPayments(ctx context.Context) ([]*model.Payment, error) {
+	payments, err := uc.paymentRepository.GetByEntryType(ctx, model.EntryTypeDebit)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar pagamentos excluídos", err)
+	}
+
+	return payments, nil
+}
+
+// ListSuspiciousPayments lista os pagamentos marcados como suspeitos, que aguardam revisão manual
+func (uc *ReconciliationUseCase) ListSuspiciousPayments(ctx context.Context) ([]*model.Payment, error) {
+	payments, err := uc.paymentRepository.GetByReviewStatus(ctx, model.ReviewStatusSuspicious)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar pagamentos suspeitos", err)
+	}
+
+	return payments, nil
+}
+
+// ApprovePayment libera um pagamento suspeito para a conciliação automática
+func (uc *ReconciliationUseCase) ApprovePayment(ctx context.Context, paymentID, actor string) (*model.Payment, error) {
+	if paymentID == "" {
+		return nil, errors.NewValidationError("transaction_id", "ID do pagamento não pode ser vazio")
+	}
+
+	payment, err := uc.paymentRepository.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !payment.IsSuspicious() {
+		return nil, errors.NewValidationError("review_status", "pagamento não está marcado como suspeito")
+	}
+
+	if err := uc.paymentRepository.UpdateReviewStatus(ctx, paymentID, model.ReviewStatusApproved, payment.ReviewReason); err != nil {
+		return nil, errors.NewDatabaseError("aprovar pagamento", err)
+	}
+
+	payment.ReviewStatus = model.ReviewStatusApproved
+	recordTimeline(ctx, uc.timelineRepository, model.NewTimelineEntry(model.TimelinePayment, payment.ID,
+		model.TimelineClassified, "pagamento suspeito aprovado para a conciliação", actor))
+
+	return payment, nil
+}
+
+// ListIgnoredPayments lista os pagamentos ignorados por estarem abaixo do valor mínimo da conciliação automática
+func (uc *ReconciliationUseCase) ListIgnoredPayments(ctx context.Context) ([]*model.Payment, error) {
+	payments, err := uc.paymentRepository.GetByReviewStatus(ctx, model.ReviewStatusBelowMinimum)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar pagamentos ignorados", err)
+	}
+
+	return payments, nil
+}
+
+// ForcePayment força a entrada na conciliação automática de um pagamento ignorado pelo valor mínimo
+func (uc *ReconciliationUseCase) ForcePayment(ctx context.Context, paymentID, actor string) (*model.Payment, error) {
+	if paymentID == "" {
+		return nil, errors.NewValidationError("transaction_id", "ID do pagamento não pode ser vazio")
+	}
+
+	payment, err := uc.paymentRepository.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !payment.IsBelowMinimum() {
+		return nil, errors.NewValidationError("review_status", "pagamento não está ignorado por valor mínimo")
+	}
+
+	if err := uc.paymentRepository.UpdateReviewStatus(ctx, paymentID, model.ReviewStatusApproved, payment.ReviewReason); err != nil {
+		return nil, errors.NewDatabaseError("forçar pagamento", err)
+	}
+
+	payment.ReviewStatus = model.ReviewStatusApproved
+	recordTimeline(ctx, uc.timelineRepository, model.NewTimelineEntry(model.TimelinePayment, payment.ID,
+		model.TimelineClassified, "pagamento abaixo do valor mínimo liberado para a conciliação", actor))
+
+	return payment, nil
+}
+
+// ListUnappliedCredits lista os créditos não aplicados das contas do escopo, filtrados por pagador
+// (payer_id), conta (bank_account) e, com available=true, apenas os que ainda têm saldo
+func (uc *ReconciliationUseCase) ListUnappliedCredits(ctx context.Context, params map[string]string) ([]*model.UnappliedCredit, error) {
+	filter := model.UnappliedCreditFilter{
+		PayerID:       params["payer_id"],
+		BankAccount:   params["bank_account"],
+		OnlyAvailable: params["available"] == "true",
+	}
+
+	credits, err := uc.creditRepository.List(ctx, filter)
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar créditos não aplicados", err)
+	}
+
+	scope := model.AccessScopeFromContext(ctx)
+	filtered := make([]*model.UnappliedCredit, 0, len(credits))
+	for _, credit := range credits {
+		if scope.AllowsAccount(credit.BankAccount) {
+			filtered = append(filtered, credit)
+		}
+	}
+
+	return filtered, nil
+}
+
+// GetUnappliedCredit recupera um crédito não aplicado de uma conta do escopo
+func (uc *ReconciliationUseCase) GetUnappliedCredit(ctx context.Context, creditID string) (*model.UnappliedCredit, error) {
+	if creditID == "" {
+		return nil, errors.NewValidationError("id", "ID do crédito não pode ser vazio")
+	}
+
+	credit, err := uc.creditRepository.GetByID(ctx, creditID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar crédito não aplicado", err)
+	}
+	if credit == nil {
+		return nil, errors.NewNotFoundError("crédito não aplicado", creditID)
+	}
+
+	if !model.AccessScopeFromContext(ctx).AllowsAccount(credit.BankAccount) {
+		return nil, errors.NewForbiddenError("crédito não aplicado", creditID)
+	}
+
+	credit.Applications, err = uc.creditRepository.GetApplications(ctx, creditID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar aplicações do crédito", err)
+	}
+
+	return credit, nil
+}
+
+// ApplyUnappliedCredit quita um boleto em aberto do pagador com o saldo do crédito não aplicado, conciliando-o
+// com o pagamento de origem do crédito. O boleto deve ser da mesma conta, ter valor fixo coberto pelo saldo e
+// não pode estar bloqueado por outro analista
+func (uc *ReconciliationUseCase) ApplyUnappliedCredit(ctx context.Context, creditID, billetID, actor string) (*model.Reconciliation, error) {
+	if billetID == "" {
+		return nil, errors.NewValidationError("billet_id", "ID do boleto não pode ser vazio")
+	}
+
+	credit, err := uc.GetUnappliedCredit(ctx, creditID)
+	if err != nil {
+		return nil, err
+	}
+
+	billet, err := uc.billetRepository.GetByID(ctx, billetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if billet.CustomerID == nil || *billet.CustomerID != credit.PayerID || billet.BankAccount != credit.BankAccount {
+		return nil, errors.NewValidationError("billet_id", "boleto não pertence ao pagador e à conta do crédito")
+	}
+
+	if billet.OpenAmount {
+		return nil, errors.NewValidationError("billet_id", "boleto de valor aberto não pode ser quitado com crédito")
+	}
+
+	if billet.Amount > credit.Balance {
+		return nil, errors.NewValidationError("billet_id", fmt.Sprintf("saldo do crédito (%.2f) não cobre o valor do boleto (%.2f)", credit.Balance, billet.Amount))
+	}
+
+	if err := uc.ensureNotClaimedByOther(ctx, billetID, actor); err != nil {
+		return nil, err
+	}
+
+	if err := uc.ensureDayNotClosed(ctx, time.Now()); err != nil {
+		return nil, err
+	}
+
+	reconciliations, err := uc.reconciliationRepository.GetByBilletID(ctx, billetID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar conciliações do boleto", err)
+	}
+
+	for _, reconciliation := range reconciliations {
+		if reconciliation.ConciliationStatus.IsReserved() {
+			return nil, errors.NewConflictError("boleto", billetID, "boleto já conciliado")
+		}
+	}
+
+	// A conciliação registra o pagamento de origem do crédito para rastreio, mas não o vincula ao boleto
+	transactionID := credit.OriginTransactionID
+	reconciliation := model.NewReconciliation(billet.ID, &transactionID, billet.BankAccount,
+		model.StatusSuccessful, model.StrategyUnappliedCredit, 0, billet.ReferenceID)
+	reconciliation.SetTimeToReconcile(credit.CreatedAt)
+
+	// O saldo é abatido na mesma transação da conciliação e da aplicação: aplicações simultâneas não consomam
+	// o mesmo saldo, e uma falha ao gravar a conciliação não consome o saldo
+	applied, err := uc.creditRepository.Apply(ctx, reconciliation, model.NewCreditApplication(credit.ID, billet.ID, billet.Amount, actor))
+	if err != nil {
+		return nil, errors.NewDatabaseError("aplicar crédito não aplicado", err)
+	}
+	if !applied {
+		return nil, errors.NewConflictError("crédito não aplicado", credit.ID, "saldo do crédito já foi utilizado")
+	}
+
+	entry := model.NewTimelineEntry(model.TimelineBillet, billet.ID, model.TimelineClassified,
+		fmt.Sprintf("boleto quitado com %.2f do crédito não aplicado do pagador %s", billet.Amount, credit.PayerID), actor)
+	entry.Reference = credit.ID
+	recordTimeline(ctx, uc.timelineRepository, entry)
+
+	event := model.NewEvent(model.EventBilletReconciled, billet.BankAccount, billet.Amount)
+	event.BilletID = billet.ID
+	event.TransactionID = transactionID
+	event.ReferenceID = billet.ReferenceID
+	uc.publishEvents(ctx, []*model.Event{event})
+
+	return reconciliation, nil
+}
+
+// ManualMatch concilia manualmente o boleto com o pagamento informados, para os casos que as estratégias
+// automáticas não resolvem. Nenhum dos dois pode estar conciliado; a diferença de valor é registrada e
+// define o status da conciliação
+func (uc *ReconciliationUseCase) ManualMatch(ctx context.Context, billetID, transactionID, actor string) (*model.Reconciliation, error) {
+	if billetID == "" {
+		return nil, errors.NewValidationError("billet_id", "ID do boleto não pode ser vazio")
+	}
+	if transactionID == "" {
+		return nil, errors.NewValidationError("transaction_id", "ID da transação não pode ser vazio")
+	}
+
+	billet, err := uc.billetRepository.GetByID(ctx, billetID)
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := uc.paymentRepository.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.IsDebit() {
+		return nil, errors.NewValidationError("transaction_id", "lançamento de débito não pode ser conciliado com boleto")
+	}
+
+	if err := uc.ensureNotClaimedByOther(ctx, billetID, actor); err != nil {
+		return nil, err
+	}
+
+	if err := uc.ensureDayNotClosed(ctx, time.Now()); err != nil {
+		return nil, err
+	}
+
+	billetReconciliations, err := uc.reconciliationRepository.GetByBilletID(ctx, billetID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar conciliações do boleto", err)
+	}
+
+	for _, reconciliation := range billetReconciliations {
+		if reconciliation.ConciliationStatus.IsReserved() {
+			return nil, errors.NewConflictError("boleto", billetID, "boleto já conciliado")
+		}
+	}
+
+	paymentReconciliations, err := uc.reconciliationRepository.GetByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar conciliações do pagamento", err)
+	}
+
+	for _, reconciliation := range paymentReconciliations {
+		if reconciliation.ConciliationStatus.IsReserved() {
+			return nil, errors.NewConflictError("pagamento", transactionID, "pagamento já conciliado")
+		}
+	}
+
+	// O pagamento é comparado com o valor esperado na data em que foi feito, com a multa e os juros de
+	// atraso; boletos de valor aberto aceitam qualquer valor pago
+	paidAt := uc.bankRules.For(model.PaymentBankCode(payment, billet)).PaymentDate(payment.PaymentDate)
+	amountDiff, _, lateCharges := billet.AmountDiffAt(payment.Amount, paidAt)
+	amountDiff = math.Round(amountDiff*100) / 100
+	status := model.AmountMatchStatus(amountDiff, lateCharges)
+
+	reconciliation := model.NewReconciliation(billet.ID, &payment.ID, billet.BankAccount,
+		status, model.StrategyManual, amountDiff, billet.ReferenceID)
+	reconciliation.SetTimeToReconcile(payment.PaymentDate)
+
+	if err := uc.reconciliationRepository.Create(ctx, reconciliation); err != nil {
+		return nil, errors.NewDatabaseError("salvar conciliação manual", err)
+	}
+
+	entry := model.NewTimelineEntry(model.TimelineBillet, billet.ID, model.TimelineReconciled,
+		fmt.Sprintf("boleto conciliado manualmente com o pagamento %s (diferença de %.2f)", payment.ID, amountDiff), actor)
+	entry.Reference = reconciliation.ID
+	recordTimeline(ctx, uc.timelineRepository, entry)
+
+	event := model.NewEvent(model.EventBilletReconciled, billet.BankAccount, billet.Amount)
+	event.BilletID = billet.ID
+	event.TransactionID = payment.ID
+	event.ReferenceID = billet.ReferenceID
+	uc.publishEvents(ctx, []*model.Event{event})
+
+	return reconciliation, nil
+}
+
+// ApproveReconciliation aprova o pareamento que aguarda aprovação, sugerido pela estratégia conta/valor/data
+// ou com diferença de valor: a conciliação passa a conciliado_com_sucesso, conciliado_com_juros ou
+// valor_diferente, conforme a diferença de valor e os encargos de atraso na data do pagamento, o boleto e
+// o pagamento são vinculados e o evento de boleto conciliado é publicado. As conciliações de um grupo são
+// aprovadas juntas. O aprovador é obrigatório e fica registrado com a decisão e no histórico de status
+func (uc *ReconciliationUseCase) ApproveReconciliation(ctx context.Context, reconciliationID, reason, approver string) ([]*model.ReconciliationApproval, error) {
+	if reconciliationID == "" {
+		return nil, errors.NewValidationError("id", "ID da conciliação não pode ser vazio")
+	}
+	if strings.TrimSpace(approver) == "" {
+		return nil, errors.NewValidationError("approver", "o aprovador é obrigatório para decidir sobre o pareamento")
+	}
+
+	reconciliations, err := uc.pendingApprovalGroup(ctx, reconciliationID, approver)
+	if err != nil {
+		return nil, err
+	}
+
+	billetIDs := make([]string, 0, len(reconciliations))
+	var transactionIDs []string
+	for _, member := range reconciliations {
+		billetIDs = append(billetIDs, member.BilletID)
+		if member.TransactionID != nil {
+			transactionIDs = append(transactionIDs, *member.TransactionID)
+		}
+	}
+
+	billets, err := uc.billetRepository.GetByIDs(ctx, billetIDs)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar boletos do pareamento", err)
+	}
+	billetsByID := make(map[string]*model.Billet, len(billets))
+	for _, billet := range billets {
+		billetsByID[billet.ID] = billet
+	}
+
+	payments, err := uc.paymentRepository.GetByIDs(ctx, transactionIDs)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar pagamentos do pareamento", err)
+	}
+	paymentsByID := make(map[string]*model.Payment, len(payments))
+	for _, payment := range payments {
+		paymentsByID[payment.ID] = payment
+	}
+
+	approvals := make([]*model.ReconciliationApproval, 0, len(reconciliations))
+	for _, member := range reconciliations {
+		// O valor esperado na data do pagamento diz se o pareamento sem diferença foi pago com juros
+		var lateCharges bool
+		billet := billetsByID[member.BilletID]
+		if billet != nil && member.TransactionID != nil {
+			if payment := paymentsByID[*member.TransactionID]; payment != nil {
+				paidAt := uc.bankRules.For(model.PaymentBankCode(payment, billet)).PaymentDate(payment.PaymentDate)
+				_, _, lateCharges = billet.AmountDiffAt(payment.Amount, paidAt)
+			}
+		}
+		status := model.AmountMatchStatus(member.AmountDiff, lateCharges)
+
+		memberReason := reason
+		if strings.TrimSpace(memberReason) == "" {
+			memberReason = approvalReason(member)
+		}
+
+		approvals = append(approvals, model.NewReconciliationApproval(member, status, approver, memberReason))
+	}
+
+	if err := uc.reconciliationRepository.Approve(ctx, approvals); err != nil {
+		return nil, errors.NewDatabaseError("aprovar pareamento", err)
+	}
+
+	// Os eventos levam o valor dos boletos conciliados
+	billetAmounts := make(map[string]float64, len(billets))
+	for _, billet := range billets {
+		billetAmounts[billet.ID] = billet.Amount
+	}
+
+	var entries []*model.TimelineEntry
+	var events []*model.Event
+	for i, approval := range approvals {
+		approval.Apply(reconciliations[i])
+
+		var transactionID string
+		if approval.TransactionID != nil {
+			transactionID = *approval.TransactionID
+		}
+
+		entry := model.NewTimelineEntry(model.TimelineBillet, approval.BilletID, model.TimelineReconciled,
+			fmt.Sprintf("pareamento com o pagamento %s aprovado (diferença de %.2f): %s", transactionID, approval.AmountDiff, approval.Reason), approver)
+		entry.Reference = approval.ReconciliationID
+		entries = append(entries, entry)
+
+		event := model.NewEvent(model.EventBilletReconciled, approval.BankAccount, billetAmounts[approval.BilletID])
+		event.BilletID = approval.BilletID
+		event.TransactionID = transactionID
+		event.ReferenceID = reconciliations[i].ReferenceID
+		events = append(events, event)
+	}
+	recordTimeline(ctx, uc.timelineRepository, entries...)
+	uc.publishEvents(ctx, events)
+
+	return approvals, nil
+}
+
+// RejectReconciliation rejeita o pareamento que aguarda aprovação: a conciliação é removida, o boleto e o
+// pagamento voltam a ficar em aberto para as próximas execuções e a rejeição é registrada com o aprovador
+// e o motivo. As conciliações de um grupo são rejeitadas juntas. Nenhum evento é publicado, pois o
+// pareamento não chegou a ser anunciado
+func (uc *ReconciliationUseCase) RejectReconciliation(ctx context.Context, reconciliationID, reason, approver string) ([]*model.ReconciliationApproval, error) {
+	if reconciliationID == "" {
+		return nil, errors.NewValidationError("id", "ID da conciliação não pode ser vazio")
+	}
+	if strings.TrimSpace(approver) == "" {
+		return nil, errors.NewValidationError("approver", "o aprovador é obrigatório para decidir sobre o pareamento")
+	}
+	if strings.TrimSpace(reason) == "" {
+		return nil, errors.NewValidationError("reason", "o motivo é obrigatório para rejeitar o pareamento")
+	}
+
+	reconciliations, err := uc.pendingApprovalGroup(ctx, reconciliationID, approver)
+	if err != nil {
+		return nil, err
+	}
+
+	rejections := make([]*model.ReconciliationApproval, 0, len(reconciliations))
+	for _, member := range reconciliations {
+		rejections = append(rejections, model.NewReconciliationRejection(member, approver, reason))
+	}
+
+	if err := uc.reconciliationRepository.Reject(ctx, rejections); err != nil {
+		return nil, errors.NewDatabaseError("rejeitar pareamento", err)
+	}
+
+	var entries []*model.TimelineEntry
+	for _, rejection := range rejections {
+		var transactionID string
+		if rejection.TransactionID != nil {
+			transactionID = *rejection.TransactionID
+		}
+
+		entry := model.NewTimelineEntry(model.TimelineBillet, rejection.BilletID, model.TimelineUndone,
+			fmt.Sprintf("pareamento com o pagamento %s rejeitado: %s", transactionID, reason), approver)
+		entry.Reference = rejection.ReconciliationID
+		entries = append(entries, entry)
+
+		if rejection.TransactionID != nil {
+			entry := model.NewTimelineEntry(model.TimelinePayment, transactionID, model.TimelineUndone,
+				fmt.Sprintf("pareamento com o boleto %s rejeitado: %s", rejection.BilletID, reason), approver)
+			entry.Reference = rejection.ReconciliationID
+			entries = append(entries, entry)
+		}
+	}
+	recordTimeline(ctx, uc.timelineRepository, entries...)
+
+	return rejections, nil
+}
+
+// GetApprovals recupera as aprovações e rejeições dos pareamentos de um boleto
+func (uc *ReconciliationUseCase) GetApprovals(ctx context.Context, billetID string) ([]*model.ReconciliationApproval, error) {
+	if billetID == "" {
+		return nil, errors.NewValidationError("billet_id", "ID do boleto não pode ser vazio")
+	}
+
+	approvals, err := uc.reconciliationRepository.GetApprovals(ctx, billetID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar aprovações", err)
+	}
+
+	return approvals, nil
+}
+
+// pendingApprovalGroup recupera a conciliação que aguarda aprovação com as demais do seu grupo, e verifica
+// se o aprovador pode decidir sobre elas: os boletos não podem estar bloqueados por outro analista nem o
+// dia da conciliação fechado
+func (uc *ReconciliationUseCase) pendingApprovalGroup(ctx context.Context, reconciliationID, approver string) ([]*model.Reconciliation, error) {
+	reconciliation, err := uc.reconciliationRepository.GetByID(ctx, reconciliationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !reconciliation.ConciliationStatus.IsPendingApproval() {
+		return nil, errors.NewConflictError("conciliação", reconciliationID, "conciliação não aguarda aprovação")
+	}
+
+	reconciliations := []*model.Reconciliation{reconciliation}
+	if reconciliation.GroupID != nil && reconciliation.TransactionID != nil {
+		group, err := uc.groupReconciliations(ctx, reconciliation)
+		if err != nil {
+			return nil, err
+		}
+
+		reconciliations = nil
+		for _, member := range group {
+			if member.ConciliationStatus == reconciliation.ConciliationStatus {
+				reconciliations = append(reconciliations, member)
+			}
+		}
+	}
+
+	for _, member := range reconciliations {
+		if err := uc.ensureNotClaimedByOther(ctx, member.BilletID, approver); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.ensureDayNotClosed(ctx, reconciliation.ReconciliationDate); err != nil {
+		return nil, err
+	}
+
+	return reconciliations, nil
+}
+
+// approvalReason descreve a aprovação quando o aprovador não informa o motivo
+func approvalReason(reconciliation *model.Reconciliation) string {
+	if reconciliation.ConciliationStatus == model.StatusAwaitingApproval {
+		return fmt.Sprintf("diferença de valor de %.2f aprovada", reconciliation.AmountDiff)
+	}
+	if reconciliation.Confidence != nil {
+		return fmt.Sprintf("pareamento sugerido aprovado (confiança de %.2f)", *reconciliation.Confidence)
+	}
+	return "pareamento sugerido aprovado"
+}
+
+// UndoReconciliation desfaz a conciliação informada: o vínculo é removido, o boleto e os pagamentos voltam
+// a ficar em aberto e o registro de quem desfez e do motivo é gravado para auditoria. As conciliações de
+// um grupo, em que um pagamento quitou vários boletos, são desfeitas juntas. Um pareamento que aguarda
+// aprovação também pode ser desfeito, mas a rejeição fica registrada apenas por RejectReconciliation
+func (uc *ReconciliationUseCase) UndoReconciliation(ctx context.Context, reconciliationID, reason, actor string) ([]*model.ReconciliationUndo, error) {
+	if reconciliationID == "" {
+		return nil, errors.NewValidationError("id", "ID da conciliação não pode ser vazio")
+	}
+	if strings.TrimSpace(reason) == "" {
+		return nil, errors.NewValidationError("reason", "o motivo é obrigatório para desfazer a conciliação")
+	}
+
+	reconciliation, err := uc.reconciliationRepository.GetByID(ctx, reconciliationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !reconciliation.ConciliationStatus.IsReserved() {
+		return nil, errors.NewConflictError("conciliação", reconciliationID, "conciliação não vincula boleto e pagamento")
+	}
+
+	// O saldo dos créditos não aplicados já foi movimentado e não é devolvido
+	if reconciliation.ConciliationStrategy == model.StrategyUnappliedCredit || reconciliation.ConciliationStrategy == model.StrategySplit {
+		return nil, errors.NewConflictError("conciliação", reconciliationID, "conciliação que movimenta créditos não aplicados não pode ser desfeita")
+	}
+
+	reconciliations := []*model.Reconciliation{reconciliation}
+	if reconciliation.GroupID != nil && reconciliation.TransactionID != nil {
+		reconciliations, err = uc.groupReconciliations(ctx, reconciliation)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	undos := make([]*model.ReconciliationUndo, 0, len(reconciliations))
+	billetIDs := make([]string, 0, len(reconciliations))
+	for _, member := range reconciliations {
+		if err := uc.ensureNotClaimedByOther(ctx, member.BilletID, actor); err != nil {
+			return nil, err
+		}
+		undos = append(undos, model.NewReconciliationUndo(member, reason, actor))
+		billetIDs = append(billetIDs, member.BilletID)
+	}
+
+	// Os eventos levam o valor dos boletos que voltam a ficar em aberto
+	billets, err := uc.billetRepository.GetByIDs(ctx, billetIDs)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar boletos da conciliação", err)
+	}
+	billetAmounts := make(map[string]float64, len(billets))
+	for _, billet := range billets {
+		billetAmounts[billet.ID] = billet.Amount
+	}
+
+	if err := uc.ensureDayNotClosed(ctx, reconciliation.ReconciliationDate); err != nil {
+		return nil, err
+	}
+
+	if err := uc.reconciliationRepository.Undo(ctx, undos); err != nil {
+		return nil, errors.NewDatabaseError("desfazer conciliação", err)
+	}
+
+	var entries []*model.TimelineEntry
+	var events []*model.Event
+	for _, undo := range undos {
+		transactionIDs := undoTransactionIDs(undo)
+
+		entry := model.NewTimelineEntry(model.TimelineBillet, undo.BilletID, model.TimelineUndone,
+			fmt.Sprintf("conciliação com %s desfeita: %s", strings.Join(transactionIDs, ", "), reason), actor)
+		entry.Reference = undo.ReconciliationID
+		entries = append(entries, entry)
+
+		for _, transactionID := range transactionIDs {
+			entry := model.NewTimelineEntry(model.TimelinePayment, transactionID, model.TimelineUndone,
+				fmt.Sprintf("conciliação com o boleto %s desfeita: %s", undo.BilletID, reason), actor)
+			entry.Reference = undo.ReconciliationID
+			entries = append(entries, entry)
+		}
+
+		// O pareamento que aguardava aprovação não foi anunciado e não há o que desfazer
+		if undo.ConciliationStatus.IsPendingApproval() {
+			continue
+		}
+
+		event := model.NewEvent(model.EventReconciliationUndone, undo.BankAccount, billetAmounts[undo.BilletID])
+		event.BilletID = undo.BilletID
+		if undo.TransactionID != nil {
+			event.TransactionID = *undo.TransactionID
+		}
+		event.Description = reason
+		events = append(events, event)
+	}
+	recordTimeline(ctx, uc.timelineRepository, entries...)
+	uc.publishEvents(ctx, events)
+
+	return undos, nil
+}
+
+// groupReconciliations retorna as conciliações pareadas do grupo da conciliação, que compartilham o pagamento
+func (uc *ReconciliationUseCase) groupReconciliations(ctx context.Context, reconciliation *model.Reconciliation) ([]*model.Reconciliation, error) {
+	byTransaction, err := uc.reconciliationRepository.GetByTransactionID(ctx, *reconciliation.TransactionID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar conciliações do grupo", err)
+	}
+
+	var group []*model.Reconciliation
+	for _, member := range byTransaction {
+		if member.GroupID != nil && *member.GroupID == *reconciliation.GroupID && member.ConciliationStatus.IsReserved() {
+			group = append(group, member)
+		}
+	}
+	return group, nil
+}
+
+// undoTransactionIDs lista, sem repetição, os pagamentos desvinculados pela conciliação desfeita
+func undoTransactionIDs(undo *model.ReconciliationUndo) []string {
+	var transactionIDs []string
+	seen := make(map[string]bool)
+	if undo.TransactionID != nil {
+		transactionIDs = append(transactionIDs, *undo.TransactionID)
+		seen[*undo.TransactionID] = true
+	}
+	for _, transactionID := range undo.TransactionIDs {
+		if !seen[transactionID] {
+			transactionIDs = append(transactionIDs, transactionID)
+			seen[transactionID] = true
+		}
+	}
+	return transactionIDs
+}
+
+// withAvailableCredits habilita a estratégia de créditos com os créditos com saldo das contas do escopo
+func (uc *ReconciliationUseCase) withAvailableCredits(ctx context.Context) (context.Context, error) {
+	credits, err := uc.creditRepository.List(ctx, model.UnappliedCreditFilter{OnlyAvailable: true})
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar créditos não aplicados", err)
+	}
+
+	scope := model.AccessScopeFromContext(ctx)
+	available := make([]*model.UnappliedCredit, 0, len(credits))
+	for _, credit := range credits {
+		if scope.AllowsAccount(credit.BankAccount) {
+			available = append(available, credit)
+		}
+	}
+
+	return service.WithAvailableCredits(ctx, available), nil
+}
+
+// debitCreditApplications abate do saldo dos créditos os valores usados pela estratégia de créditos antes
+// de persistir as conciliações. Quando o saldo já foi consumido por outra aplicação, o boleto volta a
+// ficar em aberto no resultado
+func (uc *ReconciliationUseCase) debitCreditApplications(ctx context.Context, result *model.ReconciliationResult, billets []*model.Billet) error {
+	if len(result.CreditApplications) == 0 {
+		return nil
+	}
+
+	rejected := make(map[string]bool)
+	applications := make([]model.CreditApplication, 0, len(result.CreditApplications))
+	for _, application := range result.CreditApplications {
+		debited, err := uc.creditRepository.Debit(ctx, application.CreditID, application.Amount)
+		if err != nil {
+			return errors.NewDatabaseError("abater saldo do crédito", err)
+		}
+		if !debited {
+			log.Printf("saldo do crédito %s já utilizado; boleto %s permanece em aberto", application.CreditID, application.BilletID)
+			rejected[application.BilletID] = true
+			continue
+		}
+		applications = append(applications, application)
+	}
+	result.CreditApplications = applications
+
+	if len(rejected) == 0 {
+		return nil
+	}
+
+	reconciled := make([]model.ReconciledBillet, 0, len(result.ReconciledBillets))
+	for _, billet := range result.ReconciledBillets {
+		if !rejected[billet.BilletID] || billet.ConciliationStrategy != model.StrategyUnappliedCredit {
+			reconciled = append(reconciled, billet)
+		}
+	}
+	result.ReconciledBillets = reconciled
+
+	for _, billet := range billets {
+		if rejected[billet.ID] {
+			result.NonReconciledBillets = append(result.NonReconciledBillets, *billet)
+		}
+	}
+
+	return nil
+}
+
+// recordCreditApplications registra no histórico dos créditos e na linha do tempo dos boletos as aplicações
+// feitas pela estratégia de créditos; falhas não desfazem a conciliação já persistida
+func (uc *ReconciliationUseCase) recordCreditApplications(ctx context.Context, applications []model.CreditApplication) {
+	for i := range applications {
+		application := &applications[i]
+		if err := uc.creditRepository.CreateApplication(ctx, application); err != nil {
+			log.Printf("erro ao registrar aplicação do crédito %s: %v", application.CreditID, err)
+		}
+
+		entry := model.NewTimelineEntry(model.TimelineBillet, application.BilletID, model.TimelineClassified,
+			fmt.Sprintf("boleto quitado com %.2f do crédito não aplicado %s", application.Amount, application.CreditID), "")
+		entry.Reference = application.CreditID
+		recordTimeline(ctx, uc.timelineRepository, entry)
+	}
+}
+
+// persistUnappliedCredits grava as sobras dos pagamentos divididos e registra a origem do crédito na
+// linha do tempo do pagamento
+func (uc *ReconciliationUseCase) persistUnappliedCredits(ctx context.Context, credits []model.UnappliedCredit) error {
+	if len(credits) == 0 {
+		return nil
+	}
+
+	records := make([]*model.UnappliedCredit, 0, len(credits))
+	entries := make([]*model.TimelineEntry, 0, len(credits))
+	for i := range credits {
+		credit := &credits[i]
+		records = append(records, credit)
+		entry := model.NewTimelineEntry(model.TimelinePayment, credit.OriginTransactionID, model.TimelineClassified,
+			fmt.Sprintf("sobra de %.2f registrada como crédito não aplicado do pagador %s", credit.Amount, credit.PayerID), "")
+		entry.Reference = credit.ID
+		entries = append(entries, entry)
+	}
+
+	if err := uc.creditRepository.CreateMany(ctx, records); err != nil {
+		return errors.NewDatabaseError("salvar créditos não aplicados", err)
+	}
+
+	recordTimeline(ctx, uc.timelineRepository, entries...)
+	return nil
+}
+
+// markIgnoredPayments marca os pagamentos ignorados pelo valor mínimo para que fiquem disponíveis
+// à revisão manual. Pagamentos já marcados não são atualizados novamente
+func (uc *ReconciliationUseCase) markIgnoredPayments(ctx context.Context, payments []*model.Payment, ignored []string) error {
+	if len(ignored) == 0 {
+		return nil
+	}
+
+	ignoredIDs := make(map[string]bool, len(ignored))
+	for _, paymentID := range ignored {
+		ignoredIDs[paymentID] = true
+	}
+
+	for _, payment := range payments {
+		if !ignoredIDs[payment.ID] || payment.ReviewStatus != model.ReviewStatusNone {
+			continue
+		}
+
+		reason := fmt.Sprintf("valor %.2f abaixo do mínimo para conciliação automática", payment.Amount)
+		if err := uc.paymentRepository.UpdateReviewStatus(ctx, payment.ID, model.ReviewStatusBelowMinimum, &reason); err != nil {
+			return errors.NewDatabaseError("marcar pagamento ignorado", err)
+		}
+
+		payment.ReviewStatus = model.ReviewStatusBelowMinimum
+		payment.ReviewReason = &reason
+		recordTimeline(ctx, uc.timelineRepository, model.NewTimelineEntry(model.TimelinePayment, payment.ID,
+			model.TimelineClassified, "ignorado pela conciliação automática: "+reason, ""))
+	}
+
+	return nil
+}
+
+// withTenantRanker ativa no contexto o ranker treinado do tenant, quando habilitado
+func (uc *ReconciliationUseCase) withTenantRanker(ctx context.Context, tenantID string) (context.Context, error) {
+	if tenantID == "" {
+		return ctx, nil
+	}
+
+	rankerModel, err := uc.rankerRepository.GetByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar modelo do ranker", err)
+	}
+
+	if rankerModel == nil || !rankerModel.Enabled {
+		return ctx, nil
+	}
+
+	return service.WithCandidateRanker(ctx, service.NewLogisticRanker(rankerModel)), nil
+}
+
+// flagOutliers marca como suspeitos os pagamentos cujo valor destoa do histórico da conta.
+// Pagamentos já revisados (aprovados ou suspeitos) não são reavaliados. Sem persist, a marcação
+// vale apenas para os pagamentos em memória, como na simulação
+func (uc *ReconciliationUseCase) flagOutliers(ctx context.Context, payments []*model.Payment, persist bool) error {
+	history := make(map[string][]float64)
+
+	for _, payment := range payments {
+		if payment.ReviewStatus != model.ReviewStatusNone || payment.IsDebit() {
+			continue
+		}
+
+		amounts, ok := history[payment.BankAccount]
+		if !ok {
+			var err error
+			amounts, err = uc.paymentRepository.GetReconciledAmounts(ctx, payment.BankAccount, service.OutlierHistorySize)
+			if err != nil {
+				return errors.NewDatabaseError("buscar histórico de valores", err)
+			}
+			history[payment.BankAccount] = amounts
+		}
+
+		reason, suspicious := service.DetectAmountOutlier(amounts, payment.Amount)
+		if !suspicious {
+			continue
+		}
+
+		if !persist {
+			payment.ReviewStatus = model.ReviewStatusSuspicious
+			payment.ReviewReason = &reason
+			continue
+		}
+
+		if err := uc.paymentRepository.UpdateReviewStatus(ctx, payment.ID, model.ReviewStatusSuspicious, &reason); err != nil {
+			return errors.NewDatabaseError("marcar pagamento suspeito", err)
+		}
+
+		payment.ReviewStatus = model.ReviewStatusSuspicious
+		payment.ReviewReason = &reason
+		recordTimeline(ctx, uc.timelineRepository, model.NewTimelineEntry(model.TimelinePayment, payment.ID,
+			model.TimelineClassified, "retido como suspeito: "+reason, ""))
+	}
+
+	return nil
+}
+
+// LedgerComparisonParams representa o período, as contas e o saldo de contas a receber informado pelo razão
+type LedgerComparisonParams struct {
+	StartDate      time.Time
+	EndDate        time.Time
+	FilterAccounts []string
+	LedgerBalance  float64
+}
+
+// CompareLedger compara o saldo de contas a receber do razão contábil com o saldo em aberto, no fim do
+// período, dos boletos emitidos no período, detalhando os boletos em aberto, os resíduos das conciliações
+// com valor diferente e os créditos do período não conciliados que explicam a diferença
+func (uc *ReconciliationUseCase) CompareLedger(ctx context.Context, params LedgerComparisonParams) (*model.LedgerComparison, error) {
+	if params.StartDate.IsZero() || params.EndDate.IsZero() {
+		return nil, errors.NewValidationError("period", "data inicial e data final são obrigatórias")
+	}
+	if params.EndDate.Before(params.StartDate) {
+		return nil, errors.NewValidationError("end_date", "data final não pode ser anterior à data inicial")
+	}
+
+	// Os créditos do período chegam até a data de crédito mais tardia entre os bancos; o corte exato de
+	// cada pagamento fica com filterReconciliationInput
+	creditEndDate := uc.bankRules.LatestCreditDate(params.EndDate)
+
+	billets, err := uc.billetRepository.List(ctx, model.BilletFilter{
+		StartDate: &params.StartDate,
+		EndDate:   &params.EndDate,
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar boletos", err)
+	}
+
+	payments, err := uc.paymentRepository.List(ctx, model.PaymentFilter{
+		EntryType: model.EntryTypeCredit,
+		StartDate: &params.StartDate,
+		EndDate:   &creditEndDate,
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar pagamentos", err)
+	}
+
+	billetReconciliations, err := uc.reconciliationRepository.GetByFilter(ctx, model.ReconciliationFilter{
+		DateField: model.DateFieldIssuance,
+		StartDate: &params.StartDate,
+		EndDate:   &params.EndDate,
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar conciliações dos boletos", err)
+	}
+
+	paymentReconciliations, err := uc.reconciliationRepository.GetByFilter(ctx, model.ReconciliationFilter{
+		DateField: model.DateFieldPayment,
+		StartDate: &params.StartDate,
+		EndDate:   &creditEndDate,
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar conciliações dos pagamentos", err)
+	}
+
+	paymentsByID := make(map[string]*model.Payment, len(payments))
+	for _, payment := range payments {
+		paymentsByID[payment.ID] = payment
+	}
+
+	// Pagamentos utilizados em conciliações. Uma parte de pagamento parcial cuja conciliação começou antes
+	// do período não aparece nas conciliações buscadas, mas já está vinculada a ela no próprio pagamento
+	reconciledPayments := make(map[string]bool)
+	for _, reconciliation := range append(billetReconciliations, paymentReconciliations...) {
+		if reconciliation.TransactionID == nil {
+			continue
+		}
+		reconciledPayments[*reconciliation.TransactionID] = true
+		for _, transactionID := range reconciliation.TransactionIDs {
+			reconciledPayments[transactionID] = true
+		}
+	}
+	for _, payment := range payments {
+		if payment.ReconciliationID != "" {
+			reconciledPayments[payment.ID] = true
+		}
+	}
+
+	// Pagamentos das conciliações dos boletos do período feitos fora da janela de créditos buscada
+	var missingPayments []string
+	for _, reconciliation := range billetReconciliations {
+		if reconciliation.TransactionID == nil || !reconciliation.ConciliationStatus.IsMatched() {
+			continue
+		}
+		for _, transactionID := range append([]string{*reconciliation.TransactionID}, reconciliation.TransactionIDs...) {
+			if _, loaded := paymentsByID[transactionID]; !loaded {
+				missingPayments = append(missingPayments, transactionID)
+			}
+		}
+	}
+	if len(missingPayments) > 0 {
+		settlementPayments, err := uc.paymentRepository.GetByIDs(ctx, missingPayments)
+		if err != nil {
+			return nil, errors.NewDatabaseError("buscar pagamentos das conciliações", err)
+		}
+		for _, payment := range settlementPayments {
+			paymentsByID[payment.ID] = payment
+		}
+	}
+
+	// Liquidação de cada boleto até o fim do período
+	settledBy := make(map[string]ledgerSettlement)
+	for _, reconciliation := range billetReconciliations {
+		if reconciliation.TransactionID == nil || !reconciliation.ConciliationStatus.IsMatched() {
+			continue
+		}
+
+		if settlement, settled := settleByPeriodEnd(reconciliation, paymentsByID, params.EndDate); settled {
+			settledBy[reconciliation.BilletID] = settlement
+		}
+	}
+
+	periodBillets, periodPayments := filterReconciliationInput(billets, payments, ReconciliationParams{
+		StartDate:      params.StartDate,
+		EndDate:        params.EndDate,
+		FilterAccounts: params.FilterAccounts,
+	}, uc.bankRules)
+
+	comparison := model.NewLedgerComparison(params.StartDate, params.EndDate, params.FilterAccounts, params.LedgerBalance)
+
+	for _, billet := range periodBillets {
+		settlement, settled := settledBy[billet.ID]
+		switch {
+		case !settled:
+			comparison.AddItem(model.LedgerItemOpenBillet, billet.BankAccount, billet.ID, "", billet.IssuanceDate, billet.Amount)
+		case settlement.amount != billet.Amount:
+			comparison.AddItem(model.LedgerItemAmountDifference, billet.BankAccount, billet.ID, settlement.payment.ID,
+				settlement.payment.PaymentDate, billet.Amount-settlement.amount)
+		}
+	}
+
+	for _, payment := range periodPayments {
+		if payment.IsDebit() || reconciledPayments[payment.ID] {
+			continue
+		}
+		comparison.AddItem(model.LedgerItemUnreconciledPayment, payment.BankAccount, "", payment.ID, payment.PaymentDate, -payment.Amount)
+	}
+
+	comparison.Finish()
+
+	return comparison, nil
+}
+
+// ledgerSettlement representa a liquidação de um boleto no razão: o pagamento que a completou e o valor pago
+type ledgerSettlement struct {
+	payment *model.Payment
+	amount  float64
+}
+
+// settleByPeriodEnd retorna a liquidação do boleto pela conciliação quando todos os pagamentos dela foram
+// feitos até o fim do período. Nos pagamentos parciais, o valor pago é a soma das partes
+func settleByPeriodEnd(reconciliation *model.Reconciliation, paymentsByID map[string]*model.Payment, endDate time.Time) (ledgerSettlement, bool) {
+	transactionIDs := reconciliation.TransactionIDs
+	if len(transactionIDs) == 0 {
+		transactionIDs = []string{*reconciliation.TransactionID}
+	}
+
+	var settlement ledgerSettlement
+	for _, transactionID := range transactionIDs {
+		payment, exists := paymentsByID[transactionID]
+		if !exists || payment.PaymentDate.After(endDate) {
+			return ledgerSettlement{}, false
+		}
+
+		if settlement.payment == nil || payment.PaymentDate.After(settlement.payment.PaymentDate) {
+			settlement.payment = payment
+		}
+		settlement.amount = math.Round((settlement.amount+payment.Amount)*100) / 100
+	}
+
+	return settlement, true
+}
+
+// ListReconciliations lista as conciliações por conta, status, estratégia, execução e período. O parâmetro
+// date_field escolhe a data do filtro de período: payment (regime de caixa), reconciliation ou issuance (competência)
+func (uc *ReconciliationUseCase) ListReconciliations(ctx context.Context, params map[string]string) ([]*model.Reconciliation, error) {
+	dateField, startDate, endDate, err := parsePeriodParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := model.ReconciliationFilter{
+		BankAccount:   params["bank_account"],
+		Status:        model.ConciliationStatus(params["status"]),
+		Strategy:      model.ConciliationStrategy(params["strategy"]),
+		DateField:     dateField,
+		StartDate:     startDate,
+		EndDate:       endDate,
+		RunID:         params["run_id"],
+		EngineVersion: params["engine_version"],
+	}
+
+	if limitStr, ok := params["limit"]; ok {
+		var limit int
+		if _, err := fmt.Sscanf(limitStr, "%d", &limit); err == nil && limit > 0 {
+			filter.Limit = limit
+		}
+	}
+
+	if offsetStr, ok := params["offset"]; ok {
+		var offset int
+		if _, err := fmt.Sscanf(offsetStr, "%d", &offset); err == nil && offset >= 0 {
+			filter.Offset = offset
+		}
+	}
+
+	reconciliations, err := uc.reconciliationRepository.GetByFilter(ctx, filter)
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar conciliações", err)
+	}
+
+	return reconciliations, nil
+}
+
+// GetTimeToReconcileStatistics calcula os percentis do tempo entre pagamento e conciliação por conta e período
+func (uc *ReconciliationUseCase) GetTimeToReconcileStatistics(ctx context.Context, params map[string]string) ([]*model.TimeToReconcileStatistics, error) {
+	dateField, startDate, endDate, err := parsePeriodParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := model.TimeToReconcileFilter{
+		BankAccount:   params["bank_account"],
+		EngineVersion: params["engine_version"],
+		DateField:     dateField,
+		StartDate:     startDate,
+		EndDate:       endDate,
+	}
+
+	statistics, err := uc.reconciliationRepository.GetTimeToReconcileStatistics(ctx, filter)
+	if err != nil {
+		return nil, errors.NewDatabaseError("calcular tempo até conciliação", err)
+	}
+
+	return statistics, nil
+}
+
+// GetEngineVersionStatistics calcula os totais e a taxa de conciliação por dia e versão do motor de
+// matching, para correlacionar quedas da taxa com os deploys. Com group_by=bank_account, os totais
+// ficam separados também por conta
+func (uc *ReconciliationUseCase) GetEngineVersionStatistics(ctx context.Context, params map[string]string) ([]*model.EngineVersionStatistics, error) {
+	dateField, startDate, endDate, err := parsePeriodParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	groupBy := params["group_by"]
+	if groupBy != "" && groupBy != "bank_account" {
+		return nil, errors.NewValidationError("group_by", "group_by deve ser bank_account")
+	}
+
+	filter := model.EngineVersionStatisticsFilter{
+		BankAccount:   params["bank_account"],
+		EngineVersion: params["engine_version"],
+		DateField:     dateField,
+		StartDate:     startDate,
+		EndDate:       endDate,
+	}
+
+	statistics, err := uc.reconciliationRepository.GetEngineVersionStatistics(ctx, filter)
+	if err != nil {
+		return nil, errors.NewDatabaseError("calcular estatísticas por versão do motor", err)
+	}
+
+	if groupBy == "bank_account" {
+		return statistics, nil
+	}
+	return model.SummarizeEngineVersionStatistics(statistics), nil
+}
+
+// GetDailyStatistics calcula os totais de conciliação por dia e conta do período, usados no relatório
+// regulatório. O período pode ser informado por start_date e end_date ou pelo mês de referência (month)
+func (uc *ReconciliationUseCase) GetDailyStatistics(ctx context.Context, params map[string]string) ([]*model.DailyReconciliationStatistics, error) {
+	dateField, startDate, endDate, err := parseReportPeriodParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := model.DailyStatisticsFilter{
+		BankAccount: params["bank_account"],
+		DateField:   dateField,
+		StartDate:   startDate,
+		EndDate:     endDate,
+	}
+
+	statistics, err := uc.reconciliationRepository.GetDailyStatistics(ctx, filter)
+	if err != nil {
+		return nil, errors.NewDatabaseError("calcular estatísticas diárias de conciliação", err)
+	}
+
+	return statistics, nil
+}
+
+// GetAccountRanking ordena as contas bancárias pelo critério de sort_by (pending_amount, padrão;
+// reconciliation_rate ou pending_age) para priorizar as tratativas com cada banco. O período (start_date
+// e end_date) considera a data de emissão dos boletos, e limit restringe às primeiras contas do ranking
+func (uc *ReconciliationUseCase) GetAccountRanking(ctx context.Context, params map[string]string) ([]*model.AccountPerformance, error) {
+	sortBy := model.AccountRankingSort(params["sort_by"]).OrDefault()
+	if !sortBy.IsValid() {
+		return nil, errors.NewValidationError("sort_by", "ordenação deve ser pending_amount, reconciliation_rate ou pending_age")
+	}
+
+	_, startDate, endDate, err := parsePeriodParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := model.AccountRankingFilter{
+		StartDate: startDate,
+		EndDate:   endDate,
+		AsOf:      time.Now().UTC(),
+	}
+
+	performances, err := uc.billetRepository.GetAccountPerformances(ctx, filter)
+	if err != nil {
+		return nil, errors.NewDatabaseError("calcular ranking das contas", err)
+	}
+
+	for _, performance := range performances {
+		if performance.TotalBillets > 0 {
+			performance.ReconciliationRate = float64(performance.ReconciledBillets) / float64(performance.TotalBillets) * 100
+		}
+	}
+
+	model.SortAccountPerformances(performances, sortBy)
+
+	var limit int
+	if _, err := fmt.Sscanf(params["limit"], "%d", &limit); err == nil && limit > 0 && limit < len(performances) {
+		performances = performances[:limit]
+	}
+
+	return performances, nil
+}
+
+// parseReportPeriodParams interpreta o período dos relatórios, informado por start_date e end_date ou
+// pelo mês de referência (month), que prevalece
+func parseReportPeriodParams(params map[string]string) (model.ReconciliationDateField, *time.Time, *time.Time, error) {
+	dateField, startDate, endDate, err := parsePeriodParams(params)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	if month, ok := params["month"]; ok {
+		first, err := time.Parse("2006-01", month)
+		if err != nil {
+			return "", nil, nil, errors.NewValidationError("month", "mês de referência deve estar no formato AAAA-MM")
+		}
+		last := first.AddDate(0, 1, -1)
+		startDate, endDate = &first, &last
+	}
+
+	return dateField, startDate, endDate, nil
+}
+
+// parsePeriodParams valida o período (start_date e end_date) das consultas de conciliação e o
+// campo de data (date_field) sobre o qual ele se aplica, que por padrão é a data da conciliação
+func parsePeriodParams(params map[string]string) (model.ReconciliationDateField, *time.Time, *time.Time, error) {
+	dateField := model.ReconciliationDateField(params["date_field"]).OrDefault()
+	if !dateField.IsValid() {
+		return "", nil, nil, errors.NewValidationError("date_field", "campo de data deve ser payment, reconciliation ou issuance")
+	}
+
+	var startDate, endDate *time.Time
+
+	if startDateStr, ok := params["start_date"]; ok {
+		date, err := time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			return "", nil, nil, errors.NewValidationError("start_date", "data inicial deve estar no formato AAAA-MM-DD")
+		}
+		startDate = &date
+	}
+
+	if endDateStr, ok := params["end_date"]; ok {
+		date, err := time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			return "", nil, nil, errors.NewValidationError("end_date", "data final deve estar no formato AAAA-MM-DD")
+		}
+		endDate = &date
+	}
+
+	if startDate != nil && endDate != nil && endDate.Before(*startDate) {
+		return "", nil, nil, errors.NewValidationError("end_date", "data final não pode ser anterior à data inicial")
+	}
+
+	return dateField, startDate, endDate, nil
+}
+
+// persistReconciledBillets converte os boletos conciliados em registros de conciliação e os persiste,
+// vinculados à execução runID quando informada
+func (uc *ReconciliationUseCase) persistReconciledBillets(ctx context.Context, runID string, reconciledBillets []model.ReconciledBillet) error {
+	if len(reconciledBillets) == 0 {
+		return nil
+	}
+
+	reconciliations := make([]*model.Reconciliation, 0, len(reconciledBillets))
+	for _, reconciled := range reconciledBillets {
+		transactionID := reconciled.TransactionID
+		reconciliation := model.NewReconciliation(
+			reconciled.BilletID,
+			&transactionID,
+			reconciled.BankAccount,
+			reconciled.ConciliationStatus,
+			reconciled.ConciliationStrategy,
+			reconciled.AmountDiff,
+			reconciled.ReferenceID,
+		)
+		reconciliation.TransactionIDs = reconciled.TransactionIDs
+		if reconciled.GroupID != "" {
+			groupID := reconciled.GroupID
+			reconciliation.GroupID = &groupID
+		}
+		reconciliation.Confidence = reconciled.Confidence
+		reconciliation.EngineVersion = uc.reconciliationService.Version()
+		reconciliation.SetTimeToReconcile(reconciled.PaymentDate)
+		if runID != "" {
+			reconciliation.RunID = &runID
+		}
+
+		reconciliations = append(reconciliations, reconciliation)
+	}
+
+	if err := uc.reconciliationRepository.CreateMany(ctx, reconciliations); err != nil {
+		return errors.NewDatabaseError("salvar conciliações", err)
+	}
+
+	return uc.registerOpenAmounts(ctx, reconciledBillets)
+}
+
+// persistNonReconciledBillets registra as pendências da execução runID como conciliações nao_conciliado,
+// para que o histórico do boleto mostre as tentativas frustradas. Esses registros não tiram o boleto
+// dos pendentes das próximas execuções
+func (uc *ReconciliationUseCase) persistNonReconciledBillets(ctx context.Context, runID string, billets []model.Billet) error {
+	if len(billets) == 0 {
+		return nil
+	}
+
+	reconciliations := make([]*model.Reconciliation, 0, len(billets))
+	for _, billet := range billets {
+		reconciliation := model.NewNonReconciled(billet, runID)
+		reconciliation.EngineVersion = uc.reconciliationService.Version()
+		reconciliations = append(reconciliations, reconciliation)
+	}
+
+	if err := uc.reconciliationRepository.CreateMany(ctx, reconciliations); err != nil {
+		return errors.NewDatabaseError("salvar boletos não conciliados", err)
+	}
+
+	return nil
+}
+
+// persistAmbiguousReferences registra os boletos das referências ambíguas da execução runID como
+// conciliações referencia_ambigua, que entram nas estatísticas e no histórico do boleto. Como os registros
+// nao_conciliado, eles não tiram o boleto dos pendentes das próximas execuções
+func (uc *ReconciliationUseCase) persistAmbiguousReferences(ctx context.Context, runID string, ambiguousReferences []model.AmbiguousReference, billets []*model.Billet) error {
+	if len(ambiguousReferences) == 0 {
+		return nil
+	}
+
+	billetsByID := make(map[string]*model.Billet, len(billets))
+	for _, billet := range billets {
+		billetsByID[billet.ID] = billet
+	}
+
+	var reconciliations []*model.Reconciliation
+	for _, ambiguous := range ambiguousReferences {
+		for _, billetID := range ambiguous.BilletIDs {
+			billet, found := billetsByID[billetID]
+			if !found {
+				continue
+			}
+
+			reconciliation := model.NewAmbiguousReference(*billet, runID)
+			reconciliation.EngineVersion = uc.reconciliationService.Version()
+			reconciliations = append(reconciliations, reconciliation)
+		}
+	}
+
+	if len(reconciliations) == 0 {
+		return nil
+	}
+
+	if err := uc.reconciliationRepository.CreateMany(ctx, reconciliations); err != nil {
+		return errors.NewDatabaseError("salvar referências ambíguas", err)
+	}
+
+	return nil
+}
+
+// registerOpenAmounts registra o valor pago como valor do título nos boletos de valor aberto conciliados
+func (uc *ReconciliationUseCase) registerOpenAmounts(ctx context.Context, reconciledBillets []model.ReconciledBillet) error {
+	for _, reconciled := range reconciledBillets {
+		if reconciled.PaidAmount == nil {
+			continue
+		}
+
+		billet, err := uc.billetRepository.GetByID(ctx, reconciled.BilletID)
+		if err != nil {
+			return errors.NewDatabaseError("buscar boleto de valor aberto", err)
+		}
+		if billet == nil {
+			continue
+		}
+
+		billet.Amount = *reconciled.PaidAmount
+		billet.UpdatedAt = time.Now()
+		if _, err := uc.billetRepository.Update(ctx, billet); err != nil {
+			return errors.NewDatabaseError("registrar valor pago do boleto de valor aberto", err)
+		}
+	}
+
+	return nil
+}
+
+// publishEvents publica os eventos da conciliação; falhas de publicação não desfazem a conciliação
+func (uc *ReconciliationUseCase) publishEvents(ctx context.Context, events []*model.Event) {
+	if uc.eventPublisher == nil {
+		return
+	}
+
+	if err := uc.eventPublisher.Publish(ctx, events); err != nil {
+		log.Printf("erro ao publicar eventos de conciliação: %v", err)
+	}
+}
+
+// buildReconciliationEvents gera os eventos de boletos conciliados, boletos e pagamentos órfãos
+// e referências ambíguas a partir do resultado de uma conciliação
+func buildReconciliationEvents(result *model.ReconciliationResult, billets []*model.Billet, payments []*model.Payment) []*model.Event {
+	billetsByID := make(map[string]*model.Billet, len(billets))
+	for _, billet := range billets {
+		billetsByID[billet.ID] = billet
+	}
+
+	// Pagamentos utilizados, ambíguos ou excluídos não são órfãos
+	notOrphan := make(map[string]bool)
+	for _, transactionID := range result.ExcludedPayments {
+		notOrphan[transactionID] = true
+	}
+
+	var events []*model.Event
+
+	for _, reconciled := range result.ReconciledBillets {
+		notOrphan[reconciled.TransactionID] = true
+		for _, transactionID := range reconciled.TransactionIDs {
+			notOrphan[transactionID] = true
+		}
+
+		// O pareamento que aguarda aprovação só é anunciado quando aprovado
+		if reconciled.ConciliationStatus.IsPendingApproval() {
+			continue
+		}
+
+		var amount float64
+		if billet, ok := billetsByID[reconciled.BilletID]; ok {
+			amount = billet.Amount
+		}
+
+		event := model.NewEvent(model.EventBilletReconciled, reconciled.BankAccount, amount)
+		event.BilletID = reconciled.BilletID
+		event.TransactionID = reconciled.TransactionID
+		event.ReferenceID = reconciled.ReferenceID
+		events = append(events, event)
+	}
+
+	for _, billet := range result.NonReconciledBillets {
+		event := model.NewEvent(model.EventOrphanBillet, billet.BankAccount, billet.Amount)
+		event.BilletID = billet.ID
+		event.ReferenceID = billet.ReferenceID
+		events = append(events, event)
+	}
+
+	for _, ambiguous := range result.AmbiguousReferences {
+		var bankAccount string
+		var amount float64
+		for _, billetID := range ambiguous.BilletIDs {
+			if billet, ok := billetsByID[billetID]; ok {
+				bankAccount = billet.BankAccount
+				amount += billet.Amount
+			}
+		}
+		for _, transactionID := range ambiguous.TransactionIDs {
+			notOrphan[transactionID] = true
+		}
+
+		referenceID := ambiguous.ReferenceID
+		event := model.NewEvent(model.EventAmbiguousReference, bankAccount, amount)
+		event.ReferenceID = &referenceID
+		events = append(events, event)
+	}
+
+	for _, payment := range payments {
+		if notOrphan[payment.ID] {
+			continue
+		}
+
+		event := model.NewEvent(model.EventOrphanPayment, payment.BankAccount, payment.Amount)
+		event.TransactionID = payment.ID
+		event.ReferenceID = payment.ReferenceID
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// filterReconciliationInput aplica os filtros de período e contas aos boletos e pagamentos. Para os
+// pagamentos, o fim do período é estendido pelo prazo de crédito do banco (ex.: um dia útil em bancos D+1)
+func filterReconciliationInput(
+	billets []*model.Billet,
+	payments []*model.Payment,
+	params ReconciliationParams,
+	bankRules model.BankRules,
+) ([]*model.Billet, []*model.Payment) {
+	accounts := make(map[string]bool, len(params.FilterAccounts))
+	for _, account := range params.FilterAccounts {
+		accounts[account] = true
+	}
+
+	accept := func(bankAccount string, date, endDate time.Time) bool {
+		if len(accounts) > 0 && !accounts[bankAccount] {
+			return false
+		}
+		if !params.StartDate.IsZero() && date.Before(params.StartDate) {
+			return false
+		}
+		if !endDate.IsZero() && date.After(endDate) {
+			return false
+		}
+		return true
+	}
+
+	// Banco de cada conta, para os pagamentos recebidos sem o código do banco
+	accountBanks := make(map[string]string)
+
+	filteredBillets := make([]*model.Billet, 0, len(billets))
+	for _, billet := range billets {
+		if billet.BankCode != "" {
+			accountBanks[billet.BankAccount] = billet.BankCode
+		}
+		if accept(billet.BankAccount, billet.IssuanceDate, params.EndDate) {
+			filteredBillets = append(filteredBillets, billet)
+		}
+	}
+
+	filteredPayments := make([]*model.Payment, 0, len(payments))
+	for _, payment := range payments {
+		bankCode := payment.BankCode
+		if bankCode == "" {
+			bankCode = accountBanks[payment.BankAccount]
+		}
+
+		endDate := params.EndDate
+		if !endDate.IsZero() {
+			endDate = bankRules.For(bankCode).CreditDate(endDate)
+		}
+
+		if accept(payment.BankAccount, payment.PaymentDate, endDate) {
+			filteredPayments = append(filteredPayments, payment)
+		}
+	}
+
+	return filteredBillets, filteredPayments
+}
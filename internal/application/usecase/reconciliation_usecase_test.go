@@ -0,0 +1,444 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// Repositórios falsos dos testes do caso de uso. Cada um embute a interface do domínio e implementa
+// apenas os métodos chamados pelas operações testadas
+
+// fakeBilletRepository guarda os boletos em memória
+type fakeBilletRepository struct {
+	repository.BilletRepository
+
+	billets map[string]*model.Billet
+}
+
+func (r *fakeBilletRepository) GetByID(ctx context.Context, id string) (*model.Billet, error) {
+	billet, found := r.billets[id]
+	if !found {
+		return nil, errors.NewNotFoundError("boleto", id)
+	}
+	return billet, nil
+}
+
+func (r *fakeBilletRepository) GetByIDs(ctx context.Context, ids []string) ([]*model.Billet, error) {
+	var billets []*model.Billet
+	for _, id := range ids {
+		if billet, found := r.billets[id]; found {
+			billets = append(billets, billet)
+		}
+	}
+	return billets, nil
+}
+
+// fakeReconciliationRepository guarda as conciliações em memória e registra as desfeitas
+type fakeReconciliationRepository struct {
+	repository.ReconciliationRepository
+
+	reconciliations []*model.Reconciliation
+	undone          []*model.ReconciliationUndo
+}
+
+func (r *fakeReconciliationRepository) GetByID(ctx context.Context, id string) (*model.Reconciliation, error) {
+	for _, reconciliation := range r.reconciliations {
+		if reconciliation.ID == id {
+			return reconciliation, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("conciliação", id)
+}
+
+func (r *fakeReconciliationRepository) GetByBilletID(ctx context.Context, billetID string) ([]*model.Reconciliation, error) {
+	var reconciliations []*model.Reconciliation
+	for _, reconciliation := range r.reconciliations {
+		if reconciliation.BilletID == billetID {
+			reconciliations = append(reconciliations, reconciliation)
+		}
+	}
+	return reconciliations, nil
+}
+
+func (r *fakeReconciliationRepository) GetByTransactionID(ctx context.Context, transactionID string) ([]*model.Reconciliation, error) {
+	var reconciliations []*model.Reconciliation
+	for _, reconciliation := range r.reconciliations {
+		if reconciliation.TransactionID != nil && *reconciliation.TransactionID == transactionID {
+			reconciliations = append(reconciliations, reconciliation)
+		}
+	}
+	return reconciliations, nil
+}
+
+func (r *fakeReconciliationRepository) Undo(ctx context.Context, undos []*model.ReconciliationUndo) error {
+	r.undone = append(r.undone, undos...)
+	return nil
+}
+
+// fakeClaimRepository guarda os bloqueios de boletos em memória
+type fakeClaimRepository struct {
+	repository.BilletClaimRepository
+
+	claims map[string]*model.BilletClaim
+}
+
+func (r *fakeClaimRepository) GetByBilletID(ctx context.Context, billetID string) (*model.BilletClaim, error) {
+	return r.claims[billetID], nil
+}
+
+// fakeClosingRepository devolve o mesmo fechamento para qualquer dia; nulo, nenhum dia está fechado
+type fakeClosingRepository struct {
+	repository.DailyClosingRepository
+
+	closing *model.DailyClosing
+}
+
+func (r *fakeClosingRepository) GetByDate(ctx context.Context, date time.Time) (*model.DailyClosing, error) {
+	return r.closing, nil
+}
+
+// fakeCreditRepository guarda os créditos não aplicados em memória. consumedElsewhere simula uma
+// aplicação concorrente que consome o saldo entre a leitura do crédito e a aplicação
+type fakeCreditRepository struct {
+	repository.UnappliedCreditRepository
+
+	credits           map[string]*model.UnappliedCredit
+	applied           []*model.CreditApplication
+	consumedElsewhere float64
+}
+
+func (r *fakeCreditRepository) GetByID(ctx context.Context, id string) (*model.UnappliedCredit, error) {
+	credit, found := r.credits[id]
+	if !found {
+		return nil, nil
+	}
+	stored := *credit
+	return &stored, nil
+}
+
+func (r *fakeCreditRepository) GetApplications(ctx context.Context, creditID string) ([]model.CreditApplication, error) {
+	return nil, nil
+}
+
+func (r *fakeCreditRepository) Apply(ctx context.Context, reconciliation *model.Reconciliation, application *model.CreditApplication) (bool, error) {
+	credit := r.credits[application.CreditID]
+	if credit.Balance-r.consumedElsewhere < application.Amount {
+		return false, nil
+	}
+	credit.Balance -= application.Amount
+	r.applied = append(r.applied, application)
+	return true, nil
+}
+
+// fakeEventPublisher registra os eventos publicados
+type fakeEventPublisher struct {
+	events []*model.Event
+}
+
+func (p *fakeEventPublisher) Publish(ctx context.Context, events []*model.Event) error {
+	p.events = append(p.events, events...)
+	return nil
+}
+
+// reconciliationFixture reúne o caso de uso e os repositórios falsos de um cenário
+type reconciliationFixture struct {
+	uc              *ReconciliationUseCase
+	billets         *fakeBilletRepository
+	reconciliations *fakeReconciliationRepository
+	claims          *fakeClaimRepository
+	closings        *fakeClosingRepository
+	credits         *fakeCreditRepository
+	events          *fakeEventPublisher
+}
+
+func newReconciliationFixture() *reconciliationFixture {
+	f := &reconciliationFixture{
+		billets:         &fakeBilletRepository{billets: make(map[string]*model.Billet)},
+		reconciliations: &fakeReconciliationRepository{},
+		claims:          &fakeClaimRepository{claims: make(map[string]*model.BilletClaim)},
+		closings:        &fakeClosingRepository{},
+		credits:         &fakeCreditRepository{credits: make(map[string]*model.UnappliedCredit)},
+		events:          &fakeEventPublisher{},
+	}
+	f.uc = NewReconciliationUseCase(f.billets, nil, f.reconciliations, f.claims, f.closings, nil, nil, nil,
+		f.credits, nil, nil, nil, nil, f.events, nil, nil)
+	return f
+}
+
+// confirmedClosing cria um fechamento confirmado para o dia de hoje
+func confirmedClosing() *model.DailyClosing {
+	closing := &model.DailyClosing{Date: time.Now()}
+	closing.Confirm("supervisor")
+	return closing
+}
+
+// expectError confere a classe do erro retornado; sem classe esperada, a operação deve ter sucesso
+func expectError(t *testing.T, err error, want func(error) bool) {
+	t.Helper()
+
+	if want == nil {
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+		return
+	}
+	if err == nil || !want(err) {
+		t.Fatalf("erro %v não é da classe esperada", err)
+	}
+}
+
+// TestApplyUnappliedCredit garante que o crédito só quita boletos de valor fixo do mesmo pagador e da
+// mesma conta cobertos pelo saldo, e que o saldo é abatido apenas quando a conciliação é gravada
+func TestApplyUnappliedCredit(t *testing.T) {
+	const account = "12345-6"
+	payer := "P1"
+	otherPayer := "P2"
+
+	tests := []struct {
+		name        string
+		configure   func(f *reconciliationFixture, billet *model.Billet)
+		ctx         context.Context
+		wantErr     func(error) bool
+		wantBalance float64
+	}{
+		{
+			name:        "quita o boleto do pagador",
+			wantBalance: 50,
+		},
+		{
+			name:      "boleto de outro pagador",
+			configure: func(f *reconciliationFixture, billet *model.Billet) { billet.CustomerID = &otherPayer },
+			wantErr:   errors.IsValidationError,
+		},
+		{
+			name:      "boleto de outra conta",
+			configure: func(f *reconciliationFixture, billet *model.Billet) { billet.BankAccount = "99999-9" },
+			wantErr:   errors.IsValidationError,
+		},
+		{
+			name:      "boleto de valor aberto",
+			configure: func(f *reconciliationFixture, billet *model.Billet) { billet.OpenAmount = true },
+			wantErr:   errors.IsValidationError,
+		},
+		{
+			name:      "saldo não cobre o boleto",
+			configure: func(f *reconciliationFixture, billet *model.Billet) { billet.Amount = 150.01 },
+			wantErr:   errors.IsValidationError,
+		},
+		{
+			name: "boleto bloqueado por outro analista",
+			configure: func(f *reconciliationFixture, billet *model.Billet) {
+				f.claims.claims[billet.ID] = model.NewBilletClaim(billet.ID, "outro", time.Hour)
+			},
+			wantErr: errors.IsConflictError,
+		},
+		{
+			name: "bloqueio do próprio analista",
+			configure: func(f *reconciliationFixture, billet *model.Billet) {
+				f.claims.claims[billet.ID] = model.NewBilletClaim(billet.ID, "analista", time.Hour)
+			},
+			wantBalance: 50,
+		},
+		{
+			name: "boleto já conciliado",
+			configure: func(f *reconciliationFixture, billet *model.Billet) {
+				transactionID := "T9"
+				f.reconciliations.reconciliations = append(f.reconciliations.reconciliations, model.NewReconciliation(
+					billet.ID, &transactionID, account, model.StatusSuccessful, model.StrategyReferenceID, 0, nil))
+			},
+			wantErr: errors.IsConflictError,
+		},
+		{
+			name:      "dia com fechamento confirmado",
+			configure: func(f *reconciliationFixture, billet *model.Billet) { f.closings.closing = confirmedClosing() },
+			wantErr:   errors.IsConflictError,
+		},
+		{
+			name:      "saldo consumido por aplicação concorrente",
+			configure: func(f *reconciliationFixture, billet *model.Billet) { f.credits.consumedElsewhere = 100 },
+			wantErr:   errors.IsConflictError,
+		},
+		{
+			name:    "conta fora do escopo da API key",
+			ctx:     model.WithAccessScope(context.Background(), &model.AccessScope{BankAccounts: []string{"99999-9"}}),
+			wantErr: errors.IsForbiddenError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newReconciliationFixture()
+
+			credit := model.NewUnappliedCredit(payer, account, "T0", 150)
+			f.credits.credits[credit.ID] = credit
+
+			billet := model.NewBillet("B1", account, 100, time.Now(), nil)
+			billet.CustomerID = &payer
+			f.billets.billets[billet.ID] = billet
+
+			if tt.configure != nil {
+				tt.configure(f, billet)
+			}
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			reconciliation, err := f.uc.ApplyUnappliedCredit(ctx, credit.ID, billet.ID, "analista")
+			expectError(t, err, tt.wantErr)
+
+			if tt.wantErr != nil {
+				if len(f.credits.applied) != 0 || credit.Balance != 150 || len(f.events.events) != 0 {
+					t.Fatalf("crédito aplicado apesar do erro: saldo %.2f, %d aplicações, %d eventos",
+						credit.Balance, len(f.credits.applied), len(f.events.events))
+				}
+				return
+			}
+
+			if reconciliation.ConciliationStatus != model.StatusSuccessful || reconciliation.ConciliationStrategy != model.StrategyUnappliedCredit {
+				t.Fatalf("conciliação %s/%s, esperado %s/%s", reconciliation.ConciliationStatus, reconciliation.ConciliationStrategy,
+					model.StatusSuccessful, model.StrategyUnappliedCredit)
+			}
+			if reconciliation.TransactionID == nil || *reconciliation.TransactionID != credit.OriginTransactionID {
+				t.Fatalf("conciliação sem o pagamento de origem do crédito: %v", reconciliation.TransactionID)
+			}
+			if credit.Balance != tt.wantBalance {
+				t.Fatalf("saldo do crédito %.2f, esperado %.2f", credit.Balance, tt.wantBalance)
+			}
+			if len(f.events.events) != 1 || f.events.events[0].Type != model.EventBilletReconciled {
+				t.Fatalf("eventos publicados inesperados: %+v", f.events.events)
+			}
+		})
+	}
+}
+
+// TestUndoReconciliation garante que só pareamentos que não movimentam créditos podem ser desfeitos, que os
+// membros de um grupo são desfeitos juntos e que só os pareamentos já anunciados geram evento
+func TestUndoReconciliation(t *testing.T) {
+	const account = "12345-6"
+	transactionID := "T1"
+	groupID := "G1"
+
+	// reconciliation cria uma conciliação do boleto com o pagamento T1
+	reconciliation := func(billetID string, status model.ConciliationStatus, strategy model.ConciliationStrategy) *model.Reconciliation {
+		return model.NewReconciliation(billetID, &transactionID, account, status, strategy, 0, nil)
+	}
+
+	tests := []struct {
+		name       string
+		target     *model.Reconciliation
+		others     []*model.Reconciliation
+		reason     string
+		configure  func(f *reconciliationFixture)
+		wantErr    func(error) bool
+		wantUndos  int
+		wantEvents int
+	}{
+		{
+			name:       "desfaz o pareamento",
+			target:     reconciliation("B1", model.StatusSuccessful, model.StrategyReferenceID),
+			reason:     "pagamento de outro boleto",
+			wantUndos:  1,
+			wantEvents: 1,
+		},
+		{
+			name:    "motivo obrigatório",
+			target:  reconciliation("B1", model.StatusSuccessful, model.StrategyReferenceID),
+			reason:  "  ",
+			wantErr: errors.IsValidationError,
+		},
+		{
+			name:    "tentativa sem pareamento",
+			target:  model.NewReconciliation("B1", nil, account, model.StatusNotReconciled, "", 0, nil),
+			reason:  "revisão",
+			wantErr: errors.IsConflictError,
+		},
+		{
+			name:    "pareamento com crédito não aplicado",
+			target:  reconciliation("B1", model.StatusSuccessful, model.StrategyUnappliedCredit),
+			reason:  "revisão",
+			wantErr: errors.IsConflictError,
+		},
+		{
+			name:    "pareamento por divisão de pagamento",
+			target:  reconciliation("B1", model.StatusSuccessful, model.StrategySplit),
+			reason:  "revisão",
+			wantErr: errors.IsConflictError,
+		},
+		{
+			name:       "pareamento aguardando aprovação não gera evento",
+			target:     reconciliation("B1", model.StatusSuggested, model.StrategyAccountAmountDate),
+			reason:     "pareamento incorreto",
+			wantUndos:  1,
+			wantEvents: 0,
+		},
+		{
+			name:   "grupo é desfeito junto",
+			target: reconciliation("B1", model.StatusSuccessful, model.StrategyAggregate),
+			others: []*model.Reconciliation{
+				reconciliation("B2", model.StatusSuccessful, model.StrategyAggregate),
+				reconciliation("B3", model.StatusNotReconciled, model.StrategyAggregate),
+			},
+			reason:     "pagamento de outro contrato",
+			wantUndos:  2,
+			wantEvents: 2,
+		},
+		{
+			name:   "membro do grupo bloqueado por outro analista",
+			target: reconciliation("B1", model.StatusSuccessful, model.StrategyAggregate),
+			others: []*model.Reconciliation{reconciliation("B2", model.StatusSuccessful, model.StrategyAggregate)},
+			reason: "revisão",
+			configure: func(f *reconciliationFixture) {
+				f.claims.claims["B2"] = model.NewBilletClaim("B2", "outro", time.Hour)
+			},
+			wantErr: errors.IsConflictError,
+		},
+		{
+			name:      "dia com fechamento confirmado",
+			target:    reconciliation("B1", model.StatusSuccessful, model.StrategyReferenceID),
+			reason:    "revisão",
+			configure: func(f *reconciliationFixture) { f.closings.closing = confirmedClosing() },
+			wantErr:   errors.IsConflictError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newReconciliationFixture()
+
+			if tt.target.ConciliationStrategy == model.StrategyAggregate {
+				tt.target.GroupID = &groupID
+				for _, member := range tt.others {
+					member.GroupID = &groupID
+				}
+			}
+			f.reconciliations.reconciliations = append([]*model.Reconciliation{tt.target}, tt.others...)
+			for _, member := range f.reconciliations.reconciliations {
+				f.billets.billets[member.BilletID] = model.NewBillet(member.BilletID, account, 100, time.Now(), nil)
+			}
+			if tt.configure != nil {
+				tt.configure(f)
+			}
+
+			undos, err := f.uc.UndoReconciliation(context.Background(), tt.target.ID, tt.reason, "analista")
+			expectError(t, err, tt.wantErr)
+
+			if len(undos) != tt.wantUndos || len(f.reconciliations.undone) != tt.wantUndos {
+				t.Fatalf("%d conciliações desfeitas (%d gravadas), esperado %d", len(undos), len(f.reconciliations.undone), tt.wantUndos)
+			}
+			if len(f.events.events) != tt.wantEvents {
+				t.Fatalf("%d eventos publicados, esperado %d", len(f.events.events), tt.wantEvents)
+			}
+			for _, event := range f.events.events {
+				if event.Type != model.EventReconciliationUndone || event.Amount != 100 || event.Description != tt.reason {
+					t.Fatalf("evento inesperado: %+v", event)
+				}
+			}
+		})
+	}
+}
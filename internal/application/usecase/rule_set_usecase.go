@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// RuleSetUseCase implementa os casos de uso dos conjuntos de regras de matching. O conjunto de uma
+// conta, ou na falta dele o geral do tenant, define as estratégias das execuções que não informam
+// uma ordem própria
+type RuleSetUseCase struct {
+	ruleSetRepository repository.RuleSetRepository
+}
+
+// NewRuleSetUseCase cria uma nova instância do RuleSetUseCase
+func NewRuleSetUseCase(ruleSetRepo repository.RuleSetRepository) *RuleSetUseCase {
+	return &RuleSetUseCase{
+		ruleSetRepository: ruleSetRepo,
+	}
+}
+
+// ListRuleSets lista os conjuntos de regras do tenant
+func (uc *RuleSetUseCase) ListRuleSets(ctx context.Context, tenant string) (model.RuleSets, error) {
+	ruleSets, err := uc.ruleSetRepository.GetByTenant(ctx, tenant)
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar conjuntos de regras", err)
+	}
+	return ruleSets, nil
+}
+
+// SaveRuleSet cria ou substitui o conjunto de regras do tenant para a conta informada ou, sem conta,
+// o conjunto geral. O conjunto precisa de ao menos uma estratégia
+func (uc *RuleSetUseCase) SaveRuleSet(ctx context.Context, tenant, bankAccount string, strategies []model.StrategyConfig, actor string) (*model.RuleSet, error) {
+	if len(strategies) == 0 {
+		return nil, errors.NewValidationError("strategies", "o conjunto de regras precisa de ao menos uma estratégia")
+	}
+	if err := validateStrategies(strategies); err != nil {
+		return nil, err
+	}
+
+	ruleSet := model.NewRuleSet(tenant, bankAccount, strategies, actor)
+	if err := uc.ruleSetRepository.Save(ctx, ruleSet); err != nil {
+		return nil, errors.NewDatabaseError("gravar conjunto de regras", err)
+	}
+
+	return ruleSet, nil
+}
+
+// DeleteRuleSet remove o conjunto de regras do tenant para a conta informada ou, sem conta, o
+// conjunto geral; as execuções seguintes voltam ao conjunto geral ou à ordem padrão
+func (uc *RuleSetUseCase) DeleteRuleSet(ctx context.Context, tenant, bankAccount string) error {
+	return uc.ruleSetRepository.Delete(ctx, tenant, bankAccount)
+}
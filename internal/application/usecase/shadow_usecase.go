@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/domain/service"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// DefaultShadowEngineVersion identifica o motor shadow quando a versão não é informada
+const DefaultShadowEngineVersion = "v2"
+
+// ShadowUseCase implementa o shadow mode: uma nova versão do motor de conciliação roda em paralelo à de
+// produção sobre os mesmos dados de cada execução, e o resultado dela é gravado em tabela separada para
+// o relatório de divergência, sem conciliar nada nem publicar eventos
+type ShadowUseCase struct {
+	shadowRepository repository.ShadowReconciliationRepository
+	engine           service.ReconciliationService
+	engineVersion    string
+	strategies       []model.StrategyConfig
+}
+
+// NewShadowUseCase cria uma nova instância do ShadowUseCase. Sem motor, o shadow mode fica desabilitado
+// e apenas os relatórios já gravados podem ser consultados. Com estratégias informadas, o motor shadow
+// usa essa ordem em vez da ordem da execução
+func NewShadowUseCase(
+	shadowRepo repository.ShadowReconciliationRepository,
+	engine service.ReconciliationService,
+	engineVersion string,
+	strategies []model.StrategyConfig,
+) *ShadowUseCase {
+	if engineVersion == "" {
+		engineVersion = DefaultShadowEngineVersion
+	}
+
+	return &ShadowUseCase{
+		shadowRepository: shadowRepo,
+		engine:           engine,
+		engineVersion:    engineVersion,
+		strategies:       strategies,
+	}
+}
+
+// Enabled indica se o motor shadow está configurado
+func (uc *ShadowUseCase) Enabled() bool {
+	return uc != nil && uc.engine != nil
+}
+
+// shadowExecution acompanha a conciliação do motor shadow de um bloco, executada em paralelo à de produção
+type shadowExecution struct {
+	done   chan struct{}
+	result *model.ReconciliationResult
+	err    error
+}
+
+// start inicia o motor shadow sobre os boletos e pagamentos de um bloco da execução, retornando nil
+// quando o shadow mode está desabilitado. O motor só lê os dados, que podem ser compartilhados com o
+// motor de produção
+func (uc *ShadowUseCase) start(ctx context.Context, billets []*model.Billet, payments []*model.Payment) *shadowExecution {
+	if !uc.Enabled() {
+		return nil
+	}
+
+	if len(uc.strategies) > 0 {
+		ctx = service.WithStrategies(ctx, uc.strategies)
+	}
+
+	execution := &shadowExecution{done: make(chan struct{})}
+	go func() {
+		defer close(execution.done)
+
+		// Uma falha do motor shadow nunca pode derrubar a execução de produção
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				execution.err = fmt.Errorf("pânico no motor shadow: %v", recovered)
+			}
+		}()
+
+		execution.result, execution.err = uc.engine.ReconcileBilletsWithPayments(ctx, billets, payments)
+	}()
+
+	return execution
+}
+
+// record aguarda o motor shadow de um bloco, compara o resultado com o de produção e grava a comparação.
+// Falhas são apenas registradas em log, pois não afetam o resultado de produção
+func (uc *ShadowUseCase) record(ctx context.Context, runID string, execution *shadowExecution, primary *model.ReconciliationResult) {
+	if execution == nil {
+		return
+	}
+
+	<-execution.done
+	if execution.err != nil {
+		log.Printf("erro no motor shadow %s da execução %s: %v", uc.engineVersion, runID, execution.err)
+		return
+	}
+
+	comparisons := model.CompareShadowResults(runID, uc.engineVersion, primary, execution.result)
+	if err := uc.shadowRepository.CreateMany(ctx, comparisons); err != nil {
+		log.Printf("erro ao gravar resultado do motor shadow %s da execução %s: %v", uc.engineVersion, runID, err)
+	}
+}
+
+// GetReport monta o relatório de divergência entre o motor de produção e o motor shadow em uma execução
+func (uc *ShadowUseCase) GetReport(ctx context.Context, runID string) (*model.ShadowReport, error) {
+	if uc == nil {
+		return nil, errors.NewValidationError("shadow", "shadow mode não configurado")
+	}
+
+	comparisons, err := uc.shadowRepository.GetByRunID(ctx, runID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar resultados do motor shadow", err)
+	}
+
+	return model.NewShadowReport(runID, comparisons), nil
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/service"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// BuildStrategies monta a ordem das estratégias de uma execução a partir dos nomes e dos parâmetros
+// por estratégia pedidos. Sem nomes, os parâmetros se aplicam à ordem padrão
+func BuildStrategies(names []string, params map[string]model.StrategyParams) ([]model.StrategyConfig, error) {
+	if len(names) == 0 && len(params) == 0 {
+		return nil, nil
+	}
+
+	var strategies []model.StrategyConfig
+	if len(names) == 0 {
+		strategies = model.DefaultStrategyConfigs()
+	} else {
+		strategies = make([]model.StrategyConfig, 0, len(names))
+		for _, name := range names {
+			strategies = append(strategies, model.StrategyConfig{Strategy: model.ConciliationStrategy(name)})
+		}
+	}
+
+	for name, strategyParams := range params {
+		found := false
+		for i := range strategies {
+			if string(strategies[i].Strategy) == name {
+				strategies[i].Params = strategyParams
+				found = true
+			}
+		}
+		if !found {
+			return nil, errors.NewValidationError("strategy_params", fmt.Sprintf("parâmetros informados para a estratégia %q, que não está na ordem de estratégias", name))
+		}
+	}
+
+	if err := validateStrategies(strategies); err != nil {
+		return nil, err
+	}
+
+	return strategies, nil
+}
+
+// validateStrategies valida a ordem das estratégias: apenas estratégias registradas no serviço, sem
+// repetição, tolerâncias entre 0 e 100 nas estratégias que comparam valores, janelas de datas positivas
+// nas que comparam datas e similaridades mínimas entre 0 e 1 nas que comparam textos
+func validateStrategies(strategies []model.StrategyConfig) error {
+	seen := make(map[model.ConciliationStrategy]bool, len(strategies))
+	for _, config := range strategies {
+		if _, found := service.LookupStrategy(config.Strategy); !found {
+			return errors.NewValidationError("strategies", fmt.Sprintf("estratégia desconhecida %q; estratégias aceitas: %s", config.Strategy, acceptedStrategies()))
+		}
+		if seen[config.Strategy] {
+			return errors.NewValidationError("strategies", fmt.Sprintf("estratégia %q informada mais de uma vez", config.Strategy))
+		}
+		seen[config.Strategy] = true
+
+		if tolerance := config.Params.Tolerance; tolerance != nil {
+			if !config.Strategy.AcceptsTolerance() {
+				return errors.NewValidationError("strategy_params", fmt.Sprintf("a estratégia %q não aceita tolerância", config.Strategy))
+			}
+			if *tolerance < 0 || *tolerance > 100 {
+				return errors.NewValidationError("strategy_params", fmt.Sprintf("tolerância da estratégia %q deve estar entre 0 e 100", config.Strategy))
+			}
+		}
+		if maxDaysDiff := config.Params.MaxDaysDiff; maxDaysDiff != nil {
+			if !config.Strategy.AcceptsDateWindow() {
+				return errors.NewValidationError("strategy_params", fmt.Sprintf("a estratégia %q não aceita janela de datas", config.Strategy))
+			}
+			if *maxDaysDiff <= 0 {
+				return errors.NewValidationError("strategy_params", fmt.Sprintf("janela de datas da estratégia %q deve ser maior que zero", config.Strategy))
+			}
+		}
+		if minSimilarity := config.Params.MinSimilarity; minSimilarity != nil {
+			if !config.Strategy.AcceptsMinSimilarity() {
+				return errors.NewValidationError("strategy_params", fmt.Sprintf("a estratégia %q não aceita similaridade mínima", config.Strategy))
+			}
+			if *minSimilarity <= 0 || *minSimilarity > 1 {
+				return errors.NewValidationError("strategy_params", fmt.Sprintf("similaridade mínima da estratégia %q deve ser maior que 0 e no máximo 1", config.Strategy))
+			}
+		}
+	}
+	return nil
+}
+
+// acceptedStrategies lista os nomes das estratégias automáticas, na ordem padrão, seguidos dos das opcionais
+// e dos das demais estratégias registradas no serviço
+func acceptedStrategies() string {
+	names := make([]string, 0, len(model.DefaultStrategyOrder)+len(model.OptionalStrategies))
+	for _, strategy := range model.DefaultStrategyOrder {
+		names = append(names, string(strategy))
+	}
+	for _, strategy := range model.OptionalStrategies {
+		names = append(names, string(strategy))
+	}
+	for _, strategy := range service.RegisteredStrategies() {
+		if !model.IsAutomaticStrategy(strategy) {
+			names = append(names, string(strategy))
+		}
+	}
+	return strings.Join(names, ", ")
+}
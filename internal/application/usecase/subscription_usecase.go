@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// SubscriptionUseCase implementa os casos de uso relacionados às assinaturas de eventos
+type SubscriptionUseCase struct {
+	subscriptionRepository repository.SubscriptionRepository
+}
+
+// NewSubscriptionUseCase cria uma nova instância do SubscriptionUseCase
+func NewSubscriptionUseCase(subscriptionRepo repository.SubscriptionRepository) *SubscriptionUseCase {
+	return &SubscriptionUseCase{
+		subscriptionRepository: subscriptionRepo,
+	}
+}
+
+// CreateSubscription cadastra um novo assinante de eventos
+func (uc *SubscriptionUseCase) CreateSubscription(ctx context.Context, subscription *model.Subscription) (*model.Subscription, error) {
+	if err := validateSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	if err := uc.subscriptionRepository.Create(ctx, subscription); err != nil {
+		return nil, errors.NewDatabaseError("criar assinatura", err)
+	}
+
+	return subscription, nil
+}
+
+// GetSubscription busca uma assinatura pelo ID
+func (uc *SubscriptionUseCase) GetSubscription(ctx context.Context, subscriptionID string) (*model.Subscription, error) {
+	if subscriptionID == "" {
+		return nil, errors.NewValidationError("id", "ID da assinatura não pode ser vazio")
+	}
+
+	return uc.subscriptionRepository.GetByID(ctx, subscriptionID)
+}
+
+// ListSubscriptions lista todas as assinaturas
+func (uc *SubscriptionUseCase) ListSubscriptions(ctx context.Context) ([]*model.Subscription, error) {
+	subscriptions, err := uc.subscriptionRepository.GetAll(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar assinaturas", err)
+	}
+
+	return subscriptions, nil
+}
+
+// DeleteSubscription remove uma assinatura
+func (uc *SubscriptionUseCase) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	if subscriptionID == "" {
+		return errors.NewValidationError("id", "ID da assinatura não pode ser vazio")
+	}
+
+	return uc.subscriptionRepository.Delete(ctx, subscriptionID)
+}
+
+// validateSubscription valida a URL de entrega e os filtros de uma assinatura
+func validateSubscription(subscription *model.Subscription) error {
+	if subscription.Name == "" {
+		return errors.NewValidationError("name", "nome da assinatura é obrigatório")
+	}
+
+	callbackURL, err := url.Parse(subscription.CallbackURL)
+	if err != nil || (callbackURL.Scheme != "http" && callbackURL.Scheme != "https") || callbackURL.Host == "" {
+		return errors.NewValidationError("callback_url", "URL de entrega deve ser http(s) absoluta")
+	}
+
+	for _, eventType := range subscription.EventTypes {
+		if !model.IsKnownEventType(eventType) {
+			return errors.NewValidationError("event_types", fmt.Sprintf("tipo de evento desconhecido: %s", eventType))
+		}
+	}
+
+	if subscription.MinAmount != nil && *subscription.MinAmount < 0 {
+		return errors.NewValidationError("min_amount", "valor mínimo não pode ser negativo")
+	}
+
+	return nil
+}
@@ -0,0 +1,342 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// MaxCommentLength limita o tamanho de um comentário na linha do tempo
+const MaxCommentLength = 2000
+
+// TimelineUseCase monta a linha do tempo unificada de boletos e pagamentos, usada pelo suporte para
+// acompanhar tudo o que aconteceu com um título sem consultar cada histórico separadamente
+type TimelineUseCase struct {
+	billetRepository         repository.BilletRepository
+	paymentRepository        repository.PaymentRepository
+	reconciliationRepository repository.ReconciliationRepository
+	deliveryRepository       repository.EventDeliveryRepository
+	timelineRepository       repository.TimelineRepository
+}
+
+// NewTimelineUseCase cria uma nova instância do TimelineUseCase
+func NewTimelineUseCase(
+	billetRepo repository.BilletRepository,
+	paymentRepo repository.PaymentRepository,
+	reconciliationRepo repository.ReconciliationRepository,
+	deliveryRepo repository.EventDeliveryRepository,
+	timelineRepo repository.TimelineRepository,
+) *TimelineUseCase {
+	return &TimelineUseCase{
+		billetRepository:         billetRepo,
+		paymentRepository:        paymentRepo,
+		reconciliationRepository: reconciliationRepo,
+		deliveryRepository:       deliveryRepo,
+		timelineRepository:       timelineRepo,
+	}
+}
+
+// GetBilletTimeline agrega em ordem cronológica a criação do boleto, as entradas gravadas (alterações,
+// importações, comentários, undo), as tentativas de matching e conciliações, as mudanças de status e
+// as notificações enviadas aos assinantes
+func (uc *TimelineUseCase) GetBilletTimeline(ctx context.Context, billetID string) (*model.Timeline, error) {
+	if billetID == "" {
+		return nil, errors.NewValidationError("billet_id", "ID do boleto não pode ser vazio")
+	}
+
+	billet, err := uc.billetRepository.GetByID(ctx, billetID)
+	if err != nil {
+		return nil, err
+	}
+
+	created := &model.TimelineEntry{
+		EntityType:  model.TimelineBillet,
+		EntityID:    billet.ID,
+		Kind:        model.TimelineCreated,
+		Description: fmt.Sprintf("boleto criado na conta %s com valor %.2f", billet.BankAccount, billet.Amount),
+		OccurredAt:  billet.CreatedAt,
+	}
+	entries := []*model.TimelineEntry{created}
+
+	recorded, err := uc.timelineRepository.GetByEntity(ctx, model.TimelineBillet, billet.ID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar linha do tempo do boleto", err)
+	}
+	entries = append(entries, recorded...)
+
+	reconciliations, err := uc.reconciliationRepository.GetByBilletID(ctx, billet.ID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar conciliações do boleto", err)
+	}
+	for _, reconciliation := range reconciliations {
+		entries = append(entries, reconciliationEntry(model.TimelineBillet, billet.ID, reconciliation))
+	}
+
+	changes, err := uc.reconciliationRepository.GetStatusChanges(ctx, billet.ID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar mudanças de status do boleto", err)
+	}
+	for _, change := range changes {
+		entries = append(entries, statusChangeEntry(model.TimelineBillet, billet.ID, change))
+	}
+
+	deliveries, err := uc.deliveryRepository.GetByBilletID(ctx, billet.ID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar notificações do boleto", err)
+	}
+	for _, delivery := range deliveries {
+		for _, event := range delivery.Events {
+			if event.BilletID == billet.ID {
+				entries = append(entries, notificationEntry(model.TimelineBillet, billet.ID, delivery, event))
+			}
+		}
+	}
+
+	return model.NewTimeline(model.TimelineBillet, billet.ID, entries), nil
+}
+
+// AddBilletComment registra um comentário do suporte na linha do tempo do boleto
+func (uc *TimelineUseCase) AddBilletComment(ctx context.Context, billetID, text, actor string) (*model.TimelineEntry, error) {
+	text = strings.TrimSpace(text)
+	if err := validateComment(text); err != nil {
+		return nil, err
+	}
+
+	billet, err := uc.billetRepository.GetByID(ctx, billetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.addComment(ctx, model.TimelineBillet, billet.ID, text, actor)
+}
+
+// GetPaymentTimeline agrega em ordem cronológica o recebimento do pagamento, as entradas gravadas
+// (arquivo de origem, classificações manuais e automáticas, comentários), as tentativas de matching e
+// conciliações, as mudanças de status, os estornos (débitos com a mesma referência) e as notificações
+func (uc *TimelineUseCase) GetPaymentTimeline(ctx context.Context, transactionID string) (*model.Timeline, error) {
+	if transactionID == "" {
+		return nil, errors.NewValidationError("transaction_id", "ID do pagamento não pode ser vazio")
+	}
+
+	payment, err := uc.paymentRepository.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	description := fmt.Sprintf("crédito recebido na conta %s com valor %.2f", payment.BankAccount, payment.Amount)
+	if payment.IsDebit() {
+		description = fmt.Sprintf("débito lançado na conta %s com valor %.2f, fora da conciliação", payment.BankAccount, payment.Amount)
+	}
+	entries := []*model.TimelineEntry{{
+		EntityType:  model.TimelinePayment,
+		EntityID:    payment.ID,
+		Kind:        model.TimelineCreated,
+		Description: description,
+		OccurredAt:  payment.CreatedAt,
+	}}
+
+	recorded, err := uc.timelineRepository.GetByEntity(ctx, model.TimelinePayment, payment.ID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar linha do tempo do pagamento", err)
+	}
+	entries = append(entries, recorded...)
+
+	reconciliations, err := uc.reconciliationRepository.GetByTransactionID(ctx, payment.ID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar conciliações do pagamento", err)
+	}
+
+	// As mudanças de status são registradas por boleto: consulta cada boleto uma vez e mantém
+	// apenas as das conciliações deste pagamento
+	paymentReconciliations := make(map[string]bool, len(reconciliations))
+	billetIDs := []string{}
+	for _, reconciliation := range reconciliations {
+		entries = append(entries, reconciliationEntry(model.TimelinePayment, payment.ID, reconciliation))
+		paymentReconciliations[reconciliation.ID] = true
+		billetIDs = append(billetIDs, reconciliation.BilletID)
+	}
+
+	seenBillets := make(map[string]bool, len(billetIDs))
+	for _, billetID := range billetIDs {
+		if seenBillets[billetID] {
+			continue
+		}
+		seenBillets[billetID] = true
+
+		changes, err := uc.reconciliationRepository.GetStatusChanges(ctx, billetID)
+		if err != nil {
+			return nil, errors.NewDatabaseError("buscar mudanças de status do pagamento", err)
+		}
+		for _, change := range changes {
+			if paymentReconciliations[change.ReconciliationID] {
+				entries = append(entries, statusChangeEntry(model.TimelinePayment, payment.ID, change))
+			}
+		}
+	}
+
+	reversals, err := uc.findReversals(ctx, payment)
+	if err != nil {
+		return nil, err
+	}
+	entries = append(entries, reversals...)
+
+	deliveries, err := uc.deliveryRepository.GetByTransactionID(ctx, payment.ID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar notificações do pagamento", err)
+	}
+	for _, delivery := range deliveries {
+		for _, event := range delivery.Events {
+			if event.TransactionID == payment.ID {
+				entries = append(entries, notificationEntry(model.TimelinePayment, payment.ID, delivery, event))
+			}
+		}
+	}
+
+	return model.NewTimeline(model.TimelinePayment, payment.ID, entries), nil
+}
+
+// AddPaymentComment registra um comentário do suporte na linha do tempo do pagamento
+func (uc *TimelineUseCase) AddPaymentComment(ctx context.Context, transactionID, text, actor string) (*model.TimelineEntry, error) {
+	text = strings.TrimSpace(text)
+	if err := validateComment(text); err != nil {
+		return nil, err
+	}
+
+	payment, err := uc.paymentRepository.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.addComment(ctx, model.TimelinePayment, payment.ID, text, actor)
+}
+
+// addComment grava o comentário na linha do tempo da entidade
+func (uc *TimelineUseCase) addComment(ctx context.Context, entityType model.TimelineEntityType, entityID, text, actor string) (*model.TimelineEntry, error) {
+	entry := model.NewTimelineEntry(entityType, entityID, model.TimelineComment, text, actor)
+	if err := uc.timelineRepository.Create(ctx, entry); err != nil {
+		return nil, errors.NewDatabaseError("registrar comentário", err)
+	}
+
+	return entry, nil
+}
+
+// findReversals identifica os estornos de um crédito: débitos da mesma conta e referência lançados
+// depois do crédito
+func (uc *TimelineUseCase) findReversals(ctx context.Context, payment *model.Payment) ([]*model.TimelineEntry, error) {
+	if payment.IsDebit() || payment.ReferenceID == nil || *payment.ReferenceID == "" {
+		return nil, nil
+	}
+
+	related, err := uc.paymentRepository.GetByReferenceID(ctx, *payment.ReferenceID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar estornos do pagamento", err)
+	}
+
+	entries := []*model.TimelineEntry{}
+	for _, debit := range related {
+		if !debit.IsDebit() || debit.BankAccount != payment.BankAccount || debit.PaymentDate.Before(payment.PaymentDate) {
+			continue
+		}
+
+		entries = append(entries, &model.TimelineEntry{
+			EntityType:  model.TimelinePayment,
+			EntityID:    payment.ID,
+			Kind:        model.TimelineReversed,
+			Description: fmt.Sprintf("estornado pelo débito %s de valor %.2f", debit.ID, debit.Amount),
+			Reference:   debit.ID,
+			OccurredAt:  debit.CreatedAt,
+		})
+	}
+
+	return entries, nil
+}
+
+// validateComment valida o texto de um comentário da linha do tempo
+func validateComment(text string) error {
+	if text == "" {
+		return errors.NewValidationError("text", "comentário não pode ser vazio")
+	}
+	if len(text) > MaxCommentLength {
+		return errors.NewValidationError("text", fmt.Sprintf("comentário deve ter no máximo %d caracteres", MaxCommentLength))
+	}
+	return nil
+}
+
+// recordTimeline grava entradas na linha do tempo; falhas não desfazem a operação já concluída
+func recordTimeline(ctx context.Context, timelineRepository repository.TimelineRepository, entries ...*model.TimelineEntry) {
+	if timelineRepository == nil || len(entries) == 0 {
+		return
+	}
+
+	if err := timelineRepository.CreateMany(ctx, entries); err != nil {
+		log.Printf("erro ao registrar linha do tempo: %v", err)
+	}
+}
+
+// reconciliationEntry converte uma conciliação em entrada da linha do tempo: conciliação efetiva quando
+// houve pareamento, ou tentativa de matching sem sucesso nos demais status
+func reconciliationEntry(entityType model.TimelineEntityType, entityID string, reconciliation *model.Reconciliation) *model.TimelineEntry {
+	entry := &model.TimelineEntry{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Reference:  reconciliation.ID,
+		OccurredAt: reconciliation.ReconciliationDate,
+	}
+
+	if !reconciliation.ConciliationStatus.IsMatched() {
+		entry.Kind = model.TimelineMatchAttempt
+		entry.Description = fmt.Sprintf("tentativa de matching sem pareamento: %s", reconciliation.ConciliationStatus)
+		if reconciliation.RunID != nil {
+			entry.Description += fmt.Sprintf(" (execução %s)", *reconciliation.RunID)
+		}
+		return entry
+	}
+
+	transactionID := ""
+	if reconciliation.TransactionID != nil {
+		transactionID = *reconciliation.TransactionID
+	}
+
+	entry.Kind = model.TimelineReconciled
+	entry.Description = fmt.Sprintf("boleto %s conciliado com o pagamento %s (%s, estratégia %s, diferença %.2f)",
+		reconciliation.BilletID, transactionID, reconciliation.ConciliationStatus,
+		reconciliation.ConciliationStrategy, reconciliation.AmountDiff)
+	return entry
+}
+
+// statusChangeEntry converte uma mudança de status de conciliação em entrada da linha do tempo
+func statusChangeEntry(entityType model.TimelineEntityType, entityID string, change *model.ReconciliationStatusChange) *model.TimelineEntry {
+	return &model.TimelineEntry{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Kind:       model.TimelineStatusChanged,
+		Description: fmt.Sprintf("conciliação passou de %s para %s: %s",
+			change.PreviousStatus, change.NewStatus, change.Reason),
+		Actor:      change.ChangedBy,
+		Reference:  change.ReconciliationID,
+		OccurredAt: change.ChangedAt,
+	}
+}
+
+// notificationEntry converte o evento de uma entrega do outbox em entrada da linha do tempo
+func notificationEntry(entityType model.TimelineEntityType, entityID string, delivery *model.EventDelivery, event *model.Event) *model.TimelineEntry {
+	occurredAt := delivery.CreatedAt
+	if delivery.DeliveredAt != nil {
+		occurredAt = *delivery.DeliveredAt
+	}
+
+	return &model.TimelineEntry{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Kind:       model.TimelineNotification,
+		Description: fmt.Sprintf("evento %s notificado à assinatura %s (%s)",
+			event.Type, delivery.SubscriptionID, delivery.Status),
+		Reference:  delivery.ID,
+		OccurredAt: occurredAt,
+	}
+}
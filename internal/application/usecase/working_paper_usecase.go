@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// WorkingPaperUseCase implementa a geração do papel de trabalho das conciliações para as auditorias externas
+type WorkingPaperUseCase struct {
+	billetRepository         repository.BilletRepository
+	paymentRepository        repository.PaymentRepository
+	reconciliationRepository repository.ReconciliationRepository
+}
+
+// NewWorkingPaperUseCase cria uma nova instância do WorkingPaperUseCase
+func NewWorkingPaperUseCase(
+	billetRepo repository.BilletRepository,
+	paymentRepo repository.PaymentRepository,
+	reconciliationRepo repository.ReconciliationRepository,
+) *WorkingPaperUseCase {
+	return &WorkingPaperUseCase{
+		billetRepository:         billetRepo,
+		paymentRepository:        paymentRepo,
+		reconciliationRepository: reconciliationRepo,
+	}
+}
+
+// GetWorkingPaper monta o papel de trabalho das conciliações do mês (month) ou do período (start_date e
+// end_date) informado, opcionalmente restrito a uma conta (bank_account), com os valores do boleto e do
+// pagamento de cada conciliação. O responsável que prepara o papel de trabalho é obrigatório
+func (uc *WorkingPaperUseCase) GetWorkingPaper(ctx context.Context, params map[string]string, preparedBy string) (*model.WorkingPaper, error) {
+	if strings.TrimSpace(preparedBy) == "" {
+		return nil, errors.NewValidationError("prepared_by", "o responsável pelo papel de trabalho é obrigatório")
+	}
+
+	dateField, startDate, endDate, err := parseReportPeriodParams(params)
+	if err != nil {
+		return nil, err
+	}
+	if startDate == nil || endDate == nil {
+		return nil, errors.NewValidationError("month", "informe o mês de referência (month) ou o período (start_date e end_date)")
+	}
+
+	reconciliations, err := uc.reconciliationRepository.GetByFilter(ctx, model.ReconciliationFilter{
+		BankAccount: params["bank_account"],
+		DateField:   dateField,
+		StartDate:   startDate,
+		EndDate:     endDate,
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("listar conciliações do papel de trabalho", err)
+	}
+
+	lines, err := uc.workingPaperLines(ctx, reconciliations)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.NewWorkingPaper(*startDate, *endDate, params["bank_account"], preparedBy, lines), nil
+}
+
+// workingPaperLines monta as linhas do papel de trabalho, buscando de uma vez os boletos e os pagamentos
+// das conciliações. O valor pago das conciliações de pagamentos parciais é a soma dos pagamentos
+func (uc *WorkingPaperUseCase) workingPaperLines(ctx context.Context, reconciliations []*model.Reconciliation) ([]*model.WorkingPaperLine, error) {
+	var billetIDs, transactionIDs []string
+	for _, reconciliation := range reconciliations {
+		billetIDs = append(billetIDs, reconciliation.BilletID)
+		transactionIDs = append(transactionIDs, reconciliationTransactionIDs(reconciliation)...)
+	}
+
+	billets, err := uc.billetRepository.GetByIDs(ctx, billetIDs)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar boletos do papel de trabalho", err)
+	}
+	billetsByID := make(map[string]*model.Billet, len(billets))
+	for _, billet := range billets {
+		billetsByID[billet.ID] = billet
+	}
+
+	payments, err := uc.paymentRepository.GetByIDs(ctx, transactionIDs)
+	if err != nil {
+		return nil, errors.NewDatabaseError("buscar pagamentos do papel de trabalho", err)
+	}
+	paymentsByID := make(map[string]*model.Payment, len(payments))
+	for _, payment := range payments {
+		paymentsByID[payment.ID] = payment
+	}
+
+	lines := make([]*model.WorkingPaperLine, 0, len(reconciliations))
+	for _, reconciliation := range reconciliations {
+		line := &model.WorkingPaperLine{
+			ReconciliationID:     reconciliation.ID,
+			ReconciliationDate:   reconciliation.ReconciliationDate,
+			BankAccount:          reconciliation.BankAccount,
+			BilletID:             reconciliation.BilletID,
+			AmountDiff:           reconciliation.AmountDiff,
+			ConciliationStatus:   reconciliation.ConciliationStatus,
+			ConciliationStrategy: reconciliation.ConciliationStrategy,
+		}
+		if reconciliation.ReferenceID != nil {
+			line.ReferenceID = *reconciliation.ReferenceID
+		}
+		if reconciliation.TransactionID != nil {
+			line.TransactionID = *reconciliation.TransactionID
+		}
+
+		if billet, found := billetsByID[reconciliation.BilletID]; found {
+			line.BilletAmount = billet.Amount
+			line.IssuanceDate = &billet.IssuanceDate
+		}
+
+		for _, transactionID := range reconciliationTransactionIDs(reconciliation) {
+			payment, found := paymentsByID[transactionID]
+			if !found {
+				continue
+			}
+			line.PaidAmount += payment.Amount
+			if line.PaymentDate == nil || payment.PaymentDate.After(*line.PaymentDate) {
+				line.PaymentDate = &payment.PaymentDate
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// reconciliationTransactionIDs retorna os pagamentos da conciliação: os somados nos pagamentos parciais
+// ou o pagamento conciliado
+func reconciliationTransactionIDs(reconciliation *model.Reconciliation) []string {
+	if len(reconciliation.TransactionIDs) > 0 {
+		return reconciliation.TransactionIDs
+	}
+	if reconciliation.TransactionID != nil {
+		return []string{*reconciliation.TransactionID}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"context"
+)
+
+// AccessScope restringe um usuário/API key a um subconjunto de contas bancárias e de permissões.
+// Um escopo sem contas ou sem permissões não impõe restrição na respectiva dimensão
+type AccessScope struct {
+	Subject      string             `json:"subject"`
+	Tenant       string             `json:"tenant,omitempty"` // Tenant ao qual a credencial está vinculada
+	BankAccounts []string           `json:"bank_accounts"`
+	Permissions  []APIKeyPermission `json:"permissions,omitempty"`
+}
+
+// accessScopeKey é a chave do escopo de acesso no contexto da requisição
+type accessScopeKey struct{}
+
+// WithAccessScope associa um escopo de acesso ao contexto
+func WithAccessScope(ctx context.Context, scope *AccessScope) context.Context {
+	return context.WithValue(ctx, accessScopeKey{}, scope)
+}
+
+// AccessScopeFromContext recupera o escopo de acesso do contexto, ou nil quando não há restrição
+func AccessScopeFromContext(ctx context.Context) *AccessScope {
+	scope, _ := ctx.Value(accessScopeKey{}).(*AccessScope)
+	return scope
+}
+
+// Restricted indica se o escopo limita as contas acessíveis
+func (s *AccessScope) Restricted() bool {
+	return s != nil && len(s.BankAccounts) > 0
+}
+
+// AllowsAccount verifica se a conta bancária está dentro do escopo
+func (s *AccessScope) AllowsAccount(bankAccount string) bool {
+	if !s.Restricted() {
+		return true
+	}
+
+	for _, account := range s.BankAccounts {
+		if account == bankAccount {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsPermission verifica se o escopo concede a permissão. A permissão admin concede todas as demais
+func (s *AccessScope) AllowsPermission(permission APIKeyPermission) bool {
+	if s == nil || len(s.Permissions) == 0 {
+		return true
+	}
+
+	for _, granted := range s.Permissions {
+		if granted == permission || granted == PermissionAdmin {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// AccountRankingSort define o critério de ordenação do ranking de contas
+type AccountRankingSort string
+
+const (
+	// RankByPendingAmount ordena pelo maior valor pendente de conciliação (padrão)
+	RankByPendingAmount AccountRankingSort = "pending_amount"
+
+	// RankByReconciliationRate ordena pela menor taxa de conciliação
+	RankByReconciliationRate AccountRankingSort = "reconciliation_rate"
+
+	// RankByPendingAge ordena pela maior idade média das pendências
+	RankByPendingAge AccountRankingSort = "pending_age"
+)
+
+// OrDefault retorna o critério informado, ou o valor pendente quando vazio
+func (s AccountRankingSort) OrDefault() AccountRankingSort {
+	if s == "" {
+		return RankByPendingAmount
+	}
+	return s
+}
+
+// IsValid verifica se o critério de ordenação é válido
+func (s AccountRankingSort) IsValid() bool {
+	switch s {
+	case RankByPendingAmount, RankByReconciliationRate, RankByPendingAge:
+		return true
+	default:
+		return false
+	}
+}
+
+// AccountRankingFilter representa os filtros do ranking de contas. O período considera a data de
+// emissão dos boletos, e a idade das pendências é contada até AsOf
+type AccountRankingFilter struct {
+	StartDate *time.Time
+	EndDate   *time.Time
+	AsOf      time.Time
+}
+
+// AccountPerformance representa os indicadores de conciliação dos boletos de uma conta bancária
+type AccountPerformance struct {
+	BankAccount        string  `json:"bank_account"`
+	TotalBillets       int64   `json:"total_billets"`
+	ReconciledBillets  int64   `json:"reconciled_billets"`
+	PendingBillets     int64   `json:"pending_billets"`
+	PendingAmount      float64 `json:"pending_amount"`
+	ReconciliationRate float64 `json:"reconciliation_rate"`
+	AvgPendingAgeDays  float64 `json:"avg_pending_age_days"`
+	MaxPendingAgeDays  float64 `json:"max_pending_age_days"`
+}
+
+// SortAccountPerformances ordena as contas pelo critério informado, da que mais precisa de tratativa
+// para a que menos precisa. Empates são desfeitos pelo valor pendente e, por fim, pela conta
+func SortAccountPerformances(performances []*AccountPerformance, by AccountRankingSort) {
+	sort.SliceStable(performances, func(i, j int) bool {
+		a, b := performances[i], performances[j]
+
+		switch by {
+		case RankByReconciliationRate:
+			if a.ReconciliationRate != b.ReconciliationRate {
+				return a.ReconciliationRate < b.ReconciliationRate
+			}
+		case RankByPendingAge:
+			if a.AvgPendingAgeDays != b.AvgPendingAgeDays {
+				return a.AvgPendingAgeDays > b.AvgPendingAgeDays
+			}
+		}
+
+		if a.PendingAmount != b.PendingAmount {
+			return a.PendingAmount > b.PendingAmount
+		}
+		return a.BankAccount < b.BankAccount
+	})
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// APIKeyPermission define os escopos de permissão de uma API key
+type APIKeyPermission string
+
+const (
+	PermissionRead      APIKeyPermission = "read"
+	PermissionImport    APIKeyPermission = "import"
+	PermissionReconcile APIKeyPermission = "reconcile"
+	PermissionAdmin     APIKeyPermission = "admin"
+)
+
+// KnownAPIKeyPermissions lista os escopos aceitos na criação de API keys
+var KnownAPIKeyPermissions = []APIKeyPermission{
+	PermissionRead,
+	PermissionImport,
+	PermissionReconcile,
+	PermissionAdmin,
+}
+
+// IsKnownAPIKeyPermission verifica se o escopo é suportado
+func IsKnownAPIKeyPermission(permission APIKeyPermission) bool {
+	for _, known := range KnownAPIKeyPermissions {
+		if known == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// apiKeyPrefix identifica as chaves emitidas pelo sistema
+const apiKeyPrefix = "cb_"
+
+// APIKey representa uma API key gerenciada, armazenada apenas pelo hash do segredo
+type APIKey struct {
+	ID           string             `json:"id"`
+	Name         string             `json:"name"`
+	Tenant       string             `json:"tenant"` // Tenant ao qual a chave dá acesso
+	Prefix       string             `json:"prefix"` // Início da chave, para identificação nos logs e na listagem
+	KeyHash      string             `json:"-"`
+	Permissions  []APIKeyPermission `json:"permissions"`
+	BankAccounts []string           `json:"bank_accounts,omitempty"` // Vazio permite todas as contas
+	ExpiresAt    *time.Time         `json:"expires_at,omitempty"`
+	RevokedAt    *time.Time         `json:"revoked_at,omitempty"`
+	LastUsedAt   *time.Time         `json:"last_used_at,omitempty"`
+	CreatedAt    time.Time          `json:"created_at"`
+}
+
+// NewAPIKey cria uma nova API key do tenant e retorna também o segredo em texto claro, exibido uma única vez
+func NewAPIKey(name, tenant string, permissions []APIKeyPermission, bankAccounts []string, expiresAt *time.Time) (*APIKey, string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, "", err
+	}
+	secret := apiKeyPrefix + hex.EncodeToString(b[:])
+
+	return &APIKey{
+		ID:           generateUUID(),
+		Name:         name,
+		Tenant:       tenant,
+		Prefix:       secret[:len(apiKeyPrefix)+8],
+		KeyHash:      HashAPIKey(secret),
+		Permissions:  permissions,
+		BankAccounts: bankAccounts,
+		ExpiresAt:    expiresAt,
+		CreatedAt:    time.Now(),
+	}, secret, nil
+}
+
+// HashAPIKey calcula o hash SHA-256 (hexadecimal) de um segredo de API key
+func HashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsActive verifica se a chave não foi revogada nem expirou
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// AccessScope retorna o escopo de acesso concedido pela chave
+func (k *APIKey) AccessScope() *AccessScope {
+	return &AccessScope{
+		Subject:      k.Name,
+		Tenant:       k.Tenant,
+		BankAccounts: k.BankAccounts,
+		Permissions:  k.Permissions,
+	}
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+)
+
+// AuditCategory define as categorias de eventos de auditoria
+type AuditCategory string
+
+const (
+	AuditCategoryAuthentication AuditCategory = "autenticacao"
+	AuditCategoryOperation      AuditCategory = "operacao"
+)
+
+// AuditOutcome define o resultado de uma ação auditada
+type AuditOutcome string
+
+const (
+	AuditOutcomeSuccess AuditOutcome = "sucesso"
+	AuditOutcomeFailure AuditOutcome = "falha"
+)
+
+// AuditEvent representa uma ação auditada, encaminhada ao SIEM da área de segurança
+type AuditEvent struct {
+	ID         string        `json:"id"`
+	Category   AuditCategory `json:"category"`
+	Action     string        `json:"action"`
+	Outcome    AuditOutcome  `json:"outcome"`
+	Subject    string        `json:"subject,omitempty"`
+	Tenant     string        `json:"tenant,omitempty"`
+	SourceIP   string        `json:"source_ip,omitempty"`
+	Method     string        `json:"method,omitempty"`
+	Path       string        `json:"path,omitempty"`
+	StatusCode int           `json:"status_code,omitempty"`
+	OccurredAt time.Time     `json:"occurred_at"`
+}
+
+// NewAuditEvent cria uma nova instância de AuditEvent
+func NewAuditEvent(category AuditCategory, action string, outcome AuditOutcome) *AuditEvent {
+	return &AuditEvent{
+		ID:         generateUUID(),
+		Category:   category,
+		Action:     action,
+		Outcome:    outcome,
+		OccurredAt: time.Now(),
+	}
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"time"
+)
+
+// BankAccountStatus define a situação de uma conta bancária no cadastro
+type BankAccountStatus string
+
+const (
+	BankAccountActive   BankAccountStatus = "ativa"
+	BankAccountInactive BankAccountStatus = "inativa"
+)
+
+// BankAccount representa a situação cadastral de uma conta bancária. Contas sem cadastro são
+// consideradas ativas; boletos e pagamentos de contas inativas ficam fora da conciliação e não
+// podem mais ser registrados
+type BankAccount struct {
+	Account   string            `json:"bank_account"`
+	Status    BankAccountStatus `json:"status"`
+	Reason    string            `json:"reason,omitempty"`
+	UpdatedBy string            `json:"updated_by,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// NewBankAccount cria o cadastro de uma conta bancária ativa
+func NewBankAccount(account string) *BankAccount {
+	now := time.Now()
+
+	return &BankAccount{
+		Account:   account,
+		Status:    BankAccountActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// IsActive indica se a conta está ativa; contas sem cadastro (nil) são ativas
+func (a *BankAccount) IsActive() bool {
+	return a == nil || a.Status != BankAccountInactive
+}
+
+// Deactivate inativa a conta em nome do usuário, com o motivo informado
+func (a *BankAccount) Deactivate(actor, reason string) {
+	a.Status = BankAccountInactive
+	a.Reason = reason
+	a.UpdatedBy = actor
+	a.UpdatedAt = time.Now()
+}
+
+// Activate reativa a conta em nome do usuário
+func (a *BankAccount) Activate(actor string) {
+	a.Status = BankAccountActive
+	a.Reason = ""
+	a.UpdatedBy = actor
+	a.UpdatedAt = time.Now()
+}
+
+// InactiveAccountExclusion relata os boletos e pagamentos de uma conta inativa deixados fora de uma execução
+type InactiveAccountExclusion struct {
+	BankAccount string   `json:"bank_account"`
+	Billets     []string `json:"boletos"`
+	Payments    []string `json:"pagamentos"`
+}
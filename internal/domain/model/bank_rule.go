@@ -0,0 +1,67 @@
+package model
+
+import (
+	"time"
+)
+
+// BankRule define as regras de conciliação específicas de um banco
+type BankRule struct {
+	// CreditDelayDays é a quantidade de dias úteis entre o pagamento do boleto e o crédito na conta
+	// (ex.: 1 para bancos que creditam em D+1)
+	CreditDelayDays int `json:"credit_delay_days"`
+}
+
+// BankRules indexa as regras de conciliação pelo código do banco
+type BankRules map[string]BankRule
+
+// For retorna a regra do banco, ou a regra neutra (crédito em D+0) quando o banco não tem regra própria
+func (r BankRules) For(bankCode string) BankRule {
+	return r[bankCode]
+}
+
+// LatestCreditDate retorna a data de crédito mais tardia, entre todos os bancos, de um pagamento feito na data
+func (r BankRules) LatestCreditDate(paymentDate time.Time) time.Time {
+	latest := paymentDate
+	for _, rule := range r {
+		if creditDate := rule.CreditDate(paymentDate); creditDate.After(latest) {
+			latest = creditDate
+		}
+	}
+	return latest
+}
+
+// PaymentBankCode retorna o banco que rege o pagamento: o do próprio lançamento ou, sem ele, o da
+// conta de cobrança do boleto
+func PaymentBankCode(payment *Payment, billet *Billet) string {
+	if payment.BankCode != "" || billet == nil {
+		return payment.BankCode
+	}
+	return billet.BankCode
+}
+
+// PaymentDate estima a data em que o pagamento foi feito a partir da data do crédito na conta
+func (r BankRule) PaymentDate(creditDate time.Time) time.Time {
+	return AddBusinessDays(creditDate, -r.CreditDelayDays)
+}
+
+// CreditDate estima a data do crédito na conta de um pagamento feito na data informada
+func (r BankRule) CreditDate(paymentDate time.Time) time.Time {
+	return AddBusinessDays(paymentDate, r.CreditDelayDays)
+}
+
+// AddBusinessDays soma (ou subtrai, quando negativo) dias úteis à data, ignorando sábados e domingos
+func AddBusinessDays(date time.Time, days int) time.Time {
+	step := 1
+	if days < 0 {
+		step, days = -1, -days
+	}
+
+	for days > 0 {
+		date = date.AddDate(0, 0, step)
+		if date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+			days--
+		}
+	}
+
+	return date
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tamanhos do código de barras e da linha digitável dos boletos (padrão FEBRABAN)
+const (
+	BarcodeLength = 44
+
+	// DigitableLineLength é o tamanho da linha digitável dos boletos de cobrança
+	DigitableLineLength = 47
+
+	// CollectionDigitableLineLength é o tamanho da linha digitável dos boletos de arrecadação (convênios),
+	// cujo código de barras começa com 8
+	CollectionDigitableLineLength = 48
+)
+
+// OnlyDigits remove a formatação (pontos, espaços) do código de barras ou da linha digitável
+func OnlyDigits(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+}
+
+// ValidateBarcode verifica se o código de barras tem 44 dígitos e, nos boletos de cobrança, o dígito
+// verificador geral (posição 5, módulo 11)
+func ValidateBarcode(barcode string) error {
+	if len(barcode) != BarcodeLength || OnlyDigits(barcode) != barcode {
+		return fmt.Errorf("código de barras deve ter %d dígitos", BarcodeLength)
+	}
+
+	if isCollectionBarcode(barcode) {
+		return nil
+	}
+
+	if want := barcodeCheckDigit(barcode[:4] + barcode[5:]); barcode[4] != want {
+		return fmt.Errorf("dígito verificador do código de barras inválido: esperado %c, obtido %c", want, barcode[4])
+	}
+	return nil
+}
+
+// BarcodeFromDigitableLine converte a linha digitável no código de barras, verificando os dígitos dos
+// campos da linha de cobrança (módulo 10) e o dígito verificador geral
+func BarcodeFromDigitableLine(line string) (string, error) {
+	if OnlyDigits(line) != line {
+		return "", fmt.Errorf("linha digitável deve conter apenas dígitos")
+	}
+
+	switch len(line) {
+	case DigitableLineLength:
+		fields := []string{line[0:10], line[10:21], line[21:32]}
+		for i, field := range fields {
+			if want := mod10CheckDigit(field[:len(field)-1]); field[len(field)-1] != want {
+				return "", fmt.Errorf("dígito verificador do campo %d da linha digitável inválido", i+1)
+			}
+		}
+
+		// Banco e moeda, dígito geral, fator de vencimento e valor, e o campo livre distribuído nos três campos
+		barcode := line[0:4] + line[32:33] + line[33:47] + line[4:9] + line[10:20] + line[21:31]
+		if err := ValidateBarcode(barcode); err != nil {
+			return "", err
+		}
+		return barcode, nil
+	case CollectionDigitableLineLength:
+		// Quatro blocos de 11 dígitos, cada um seguido do seu dígito verificador
+		barcode := line[0:11] + line[12:23] + line[24:35] + line[36:47]
+		if !isCollectionBarcode(barcode) {
+			return "", fmt.Errorf("linha digitável de %d dígitos deve ser de arrecadação (iniciada por 8)", CollectionDigitableLineLength)
+		}
+		return barcode, nil
+	default:
+		return "", fmt.Errorf("linha digitável deve ter %d ou %d dígitos", DigitableLineLength, CollectionDigitableLineLength)
+	}
+}
+
+// NormalizeBarcode retorna o código de barras de um identificador do boleto, seja o próprio código de
+// barras ou a linha digitável
+func NormalizeBarcode(value string) (string, error) {
+	value = OnlyDigits(value)
+	if len(value) == BarcodeLength {
+		return value, ValidateBarcode(value)
+	}
+	return BarcodeFromDigitableLine(value)
+}
+
+// isCollectionBarcode identifica os códigos de barras de arrecadação (convênios), que iniciam com 8
+func isCollectionBarcode(barcode string) bool {
+	return strings.HasPrefix(barcode, "8")
+}
+
+// barcodeCheckDigit calcula o dígito verificador geral do código de barras de cobrança (módulo 11, pesos
+// de 2 a 9 da direita para a esquerda); os restos que resultariam em 0, 10 ou 11 usam o dígito 1
+func barcodeCheckDigit(digits string) byte {
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+
+	digit := 11 - sum%11
+	if digit == 0 || digit == 10 || digit == 11 {
+		digit = 1
+	}
+	return byte('0' + digit)
+}
+
+// mod10CheckDigit calcula o dígito verificador de um campo da linha digitável (módulo 10, pesos 2 e 1
+// alternados da direita para a esquerda, somando os algarismos dos produtos)
+func mod10CheckDigit(digits string) byte {
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		product := int(digits[i]-'0') * weight
+		sum += product/10 + product%10
+		weight = 3 - weight
+	}
+	return byte('0' + (10-sum%10)%10)
+}
@@ -1,9 +1,18 @@
 package model
 
 import (
+	"math"
 	"time"
 )
 
+// BilletStatus define a situação de conciliação de um boleto
+type BilletStatus string
+
+const (
+	BilletStatusIssued     BilletStatus = "emitido"
+	BilletStatusReconciled BilletStatus = "conciliado"
+)
+
 // Billet representa um boleto emitido no sistema
 type Billet struct {
 	ID           string    `json:"billet_id"`
@@ -12,6 +21,43 @@ type Billet struct {
 	IssuanceDate time.Time `json:"issuance_date"`
 	ReferenceID  *string   `json:"reference_id,omitempty"`
 
+	// InstallmentNumber identifica a parcela de um carnê; o reference_id carrega o sufixo da parcela
+	InstallmentNumber *int `json:"installment_number,omitempty"`
+
+	// ContractID e CustomerID agrupam os boletos por contrato e por cliente
+	ContractID *string `json:"contract_id,omitempty"`
+	CustomerID *string `json:"customer_id,omitempty"`
+
+	// OpenAmount indica um boleto de valor aberto (ex.: depósito identificado), que aceita
+	// qualquer valor pago; ao conciliar, o valor pago passa a ser o valor do título
+	OpenAmount bool `json:"open_amount,omitempty"`
+
+	// BankCode identifica o banco (código COMPE) da conta de cobrança do boleto
+	BankCode string `json:"bank_code,omitempty"`
+
+	// BarCode e DigitableLine identificam o título na compensação: o código de barras de 44 dígitos e a
+	// linha digitável correspondente, sem formatação
+	BarCode       string `json:"bar_code,omitempty"`
+	DigitableLine string `json:"digitable_line,omitempty"`
+
+	// PayerDocument é o CPF ou CNPJ do pagador, apenas dígitos
+	PayerDocument string `json:"payer_document,omitempty"`
+
+	// PayerName é o nome do pagador, comparado com a descrição dos pagamentos sem chaves exatas
+	PayerName string `json:"payer_name,omitempty"`
+
+	// DueDate é o vencimento do boleto. Pago depois dele, o boleto acumula a multa de FinePercent sobre o
+	// valor e os juros de mora de InterestRate, percentual ao mês cobrado pro rata die
+	DueDate      *time.Time `json:"due_date,omitempty"`
+	InterestRate float64    `json:"interest_rate,omitempty"`
+	FinePercent  float64    `json:"fine_percent,omitempty"`
+
+	// Vínculo com a conciliação que pareou o boleto, gravado na mesma transação da conciliação. Não é
+	// alterado pelo cadastro do boleto
+	ReconciliationID string       `json:"reconciliation_id,omitempty"`
+	TransactionID    *string      `json:"transaction_id,omitempty"`
+	Status           BilletStatus `json:"status"`
+
 	// Campos adicionais para controle interno
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -27,7 +73,89 @@ func NewBillet(id, bankAccount string, amount float64, issuanceDate time.Time, r
 		Amount:       amount,
 		IssuanceDate: issuanceDate,
 		ReferenceID:  referenceID,
+		Status:       BilletStatusIssued,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
 }
+
+// IsReconciled indica se o boleto está vinculado a uma conciliação
+func (b *Billet) IsReconciled() bool {
+	return b.ReconciliationID != ""
+}
+
+// Barcode retorna o código de barras do boleto, informado diretamente ou obtido da linha digitável.
+// Vazio quando o boleto não tem nenhum dos dois válido
+func (b *Billet) Barcode() string {
+	if b.BarCode != "" {
+		return b.BarCode
+	}
+	if b.DigitableLine == "" {
+		return ""
+	}
+
+	barcode, err := BarcodeFromDigitableLine(b.DigitableLine)
+	if err != nil {
+		return ""
+	}
+	return barcode
+}
+
+// AmountDiff calcula a diferença absoluta e percentual entre o valor pago e o valor do boleto.
+// Boletos de valor aberto aceitam qualquer valor, sem diferença
+func (b *Billet) AmountDiff(paidAmount float64) (float64, float64) {
+	if b.OpenAmount {
+		return 0, 0
+	}
+
+	amountDiff := math.Abs(paidAmount - b.Amount)
+	return amountDiff, (amountDiff / b.Amount) * 100
+}
+
+// HasLateCharges indica se o boleto cobra multa ou juros de mora depois do vencimento. Boletos de valor
+// aberto não têm encargos
+func (b *Billet) HasLateCharges() bool {
+	return b.DueDate != nil && !b.OpenAmount && (b.InterestRate > 0 || b.FinePercent > 0)
+}
+
+// DaysLate conta os dias corridos entre o vencimento e a data do pagamento, desconsiderando o horário;
+// zero quando o boleto não tem vencimento ou foi pago até ele
+func (b *Billet) DaysLate(paidAt time.Time) int {
+	if b.DueDate == nil {
+		return 0
+	}
+
+	due := time.Date(b.DueDate.Year(), b.DueDate.Month(), b.DueDate.Day(), 0, 0, 0, 0, time.UTC)
+	paid := time.Date(paidAt.Year(), paidAt.Month(), paidAt.Day(), 0, 0, 0, 0, time.UTC)
+	if !paid.After(due) {
+		return 0
+	}
+	return int(paid.Sub(due).Hours() / 24)
+}
+
+// ExpectedAmount calcula o valor devido do boleto pago em paidAt: depois do vencimento, o valor do boleto
+// acrescido da multa e dos juros de mora dos dias de atraso (mês de 30 dias), arredondado em centavos
+func (b *Billet) ExpectedAmount(paidAt time.Time) float64 {
+	daysLate := b.DaysLate(paidAt)
+	if !b.HasLateCharges() || daysLate == 0 {
+		return b.Amount
+	}
+
+	fine := b.Amount * b.FinePercent / 100
+	interest := b.Amount * b.InterestRate / 100 / 30 * float64(daysLate)
+	return math.Round((b.Amount+fine+interest)*100) / 100
+}
+
+// AmountDiffAt calcula a diferença absoluta e percentual entre o valor pago e o valor esperado do boleto
+// na data do pagamento, e se o valor esperado inclui multa e juros de atraso. Com encargos, a diferença
+// é arredondada em centavos, como o valor esperado
+func (b *Billet) AmountDiffAt(paidAmount float64, paidAt time.Time) (float64, float64, bool) {
+	if !b.HasLateCharges() || b.DaysLate(paidAt) == 0 {
+		amountDiff, amountDiffPercentage := b.AmountDiff(paidAmount)
+		return amountDiff, amountDiffPercentage, false
+	}
+
+	expected := b.ExpectedAmount(paidAt)
+	amountDiff := math.Round(math.Abs(paidAmount-expected)*100) / 100
+	return amountDiff, (amountDiff / expected) * 100, true
+}
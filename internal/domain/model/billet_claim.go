@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+)
+
+// BilletClaim representa o bloqueio ("claim") de um boleto por um analista durante a investigação
+type BilletClaim struct {
+	BilletID  string    `json:"billet_id"`
+	ClaimedBy string    `json:"claimed_by"`
+	ClaimedAt time.Time `json:"claimed_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewBilletClaim cria um novo bloqueio de boleto válido pelo tempo informado
+func NewBilletClaim(billetID, claimedBy string, ttl time.Duration) *BilletClaim {
+	now := time.Now()
+
+	return &BilletClaim{
+		BilletID:  billetID,
+		ClaimedBy: claimedBy,
+		ClaimedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+// IsActive indica se o bloqueio ainda não expirou
+func (c *BilletClaim) IsActive(now time.Time) bool {
+	return c != nil && now.Before(c.ExpiresAt)
+}
+
+// BlocksActor indica se o bloqueio impede que o analista informado atue sobre o boleto
+func (c *BilletClaim) BlocksActor(actor string, now time.Time) bool {
+	return c.IsActive(now) && c.ClaimedBy != actor
+}
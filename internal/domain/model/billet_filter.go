@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// BilletFilter representa os filtros da listagem de boletos. O período se aplica à data de emissão
+type BilletFilter struct {
+	BankAccount string
+	ReferenceID string
+	ContractID  string
+	StartDate   *time.Time
+	EndDate     *time.Time
+	MinAmount   *float64
+	MaxAmount   *float64
+	Limit       int
+	Offset      int
+}
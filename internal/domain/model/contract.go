@@ -0,0 +1,12 @@
+package model
+
+// ContractStatistics representa os indicadores de conciliação dos boletos de um contrato
+type ContractStatistics struct {
+	ContractID           string  `json:"contract_id"`
+	TotalBillets         int64   `json:"total_billets"`
+	ReconciledBillets    int64   `json:"reconciled_billets"`
+	NotReconciledBillets int64   `json:"not_reconciled_billets"`
+	OpenAmount           float64 `json:"open_amount"`
+	PaidAmount           float64 `json:"paid_amount"`
+	ReconciliationRate   float64 `json:"reconciliation_rate"`
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+)
+
+// DailyClosingStatus define os possíveis status de um fechamento diário
+type DailyClosingStatus string
+
+const (
+	ClosingOpen      DailyClosingStatus = "aberto"
+	ClosingConfirmed DailyClosingStatus = "confirmado"
+)
+
+// DailyClosing agrupa as conciliações de um dia num fechamento. Enquanto aberto, o fechamento é uma
+// prévia; após a confirmação, os resultados do dia ficam congelados e não podem mais ser alterados
+type DailyClosing struct {
+	ID                  string                           `json:"id"`
+	Date                time.Time                        `json:"date"`
+	Status              DailyClosingStatus               `json:"status"`
+	ReconciliationCount int64                            `json:"reconciliation_count"`
+	Statistics          []*DailyReconciliationStatistics `json:"statistics"`
+	CreatedBy           string                           `json:"created_by"`
+	CreatedAt           time.Time                        `json:"created_at"`
+	ConfirmedBy         string                           `json:"confirmed_by,omitempty"`
+	ConfirmedAt         *time.Time                       `json:"confirmed_at,omitempty"`
+}
+
+// NewDailyClosing cria um novo fechamento aberto para o dia informado
+func NewDailyClosing(date time.Time, createdBy string) *DailyClosing {
+	return &DailyClosing{
+		ID:         generateUUID(),
+		Date:       ClosingDay(date),
+		Status:     ClosingOpen,
+		Statistics: []*DailyReconciliationStatistics{},
+		CreatedBy:  createdBy,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// ClosingDay retorna o dia (sem horário) ao qual o instante pertence para fins de fechamento
+func ClosingDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// IsConfirmed indica se o fechamento foi confirmado e os resultados do dia estão congelados
+func (c *DailyClosing) IsConfirmed() bool {
+	return c != nil && c.Status == ClosingConfirmed
+}
+
+// SetStatistics registra os totais do dia no fechamento
+func (c *DailyClosing) SetStatistics(statistics []*DailyReconciliationStatistics) {
+	c.Statistics = statistics
+	c.ReconciliationCount = 0
+	for _, stats := range statistics {
+		c.ReconciliationCount += stats.Total
+	}
+}
+
+// Confirm confirma o fechamento em nome do usuário informado
+func (c *DailyClosing) Confirm(confirmedBy string) {
+	now := time.Now()
+	c.Status = ClosingConfirmed
+	c.ConfirmedBy = confirmedBy
+	c.ConfirmedAt = &now
+}
+
+// TotalPaidAmount retorna o valor pago somado de todas as contas do fechamento
+func (c *DailyClosing) TotalPaidAmount() float64 {
+	var total float64
+	for _, stats := range c.Statistics {
+		total += stats.PaidAmount
+	}
+	return total
+}
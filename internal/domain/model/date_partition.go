@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+)
+
+// DateWindow representa uma sub-janela [Start, End] de uma janela de datas particionada
+type DateWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// PartitionPolicy define o particionamento de execuções com janelas de datas grandes em sub-janelas
+// mensais, para que nenhuma consulta traga os registros de todo o período de uma vez. Boletos e
+// pagamentos só são pareados dentro da mesma sub-janela
+type PartitionPolicy struct {
+	// MaxDays é o tamanho máximo, em dias, de uma janela processada de uma vez; zero desabilita o particionamento
+	MaxDays int `json:"max_days"`
+
+	// Concurrency é a quantidade de sub-janelas processadas em paralelo; 1 processa sequencialmente
+	Concurrency int `json:"concurrency"`
+}
+
+// DefaultPartitionPolicy particiona janelas maiores que 31 dias, processando uma sub-janela por vez
+var DefaultPartitionPolicy = PartitionPolicy{MaxDays: 31, Concurrency: 1}
+
+// Split divide a janela em sub-janelas mensais quando ela excede MaxDays. A primeira e a última
+// sub-janelas respeitam o início e o fim informados. Janelas menores, incompletas ou com o
+// particionamento desabilitado são retornadas inteiras
+func (p PartitionPolicy) Split(start, end time.Time) []DateWindow {
+	if p.MaxDays <= 0 || start.IsZero() || end.IsZero() || !end.After(start.AddDate(0, 0, p.MaxDays)) {
+		return []DateWindow{{Start: start, End: end}}
+	}
+
+	var windows []DateWindow
+	for current := start; !current.After(end); {
+		nextMonth := time.Date(current.Year(), current.Month(), 1, 0, 0, 0, 0, current.Location()).AddDate(0, 1, 0)
+
+		// Precisão de microssegundos do Postgres: o fim não pode ser arredondado para o mês seguinte
+		windowEnd := nextMonth.Add(-time.Microsecond)
+		if windowEnd.After(end) {
+			windowEnd = end
+		}
+
+		windows = append(windows, DateWindow{Start: current, End: windowEnd})
+		current = nextMonth
+	}
+
+	return windows
+}
+
+// Parallelism retorna a quantidade de sub-janelas processadas em paralelo, no mínimo uma
+func (p PartitionPolicy) Parallelism() int {
+	if p.Concurrency < 1 {
+		return 1
+	}
+	return p.Concurrency
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"context"
+)
+
+// Environment identifica o ambiente dos dados de uma requisição
+type Environment string
+
+const (
+	// EnvironmentProduction é o ambiente dos dados reais, usado quando o cliente não informa o ambiente
+	EnvironmentProduction Environment = "production"
+
+	// EnvironmentSandbox é o ambiente de testes dos integradores: os dados ficam isolados dos de produção
+	// e nada é enviado a sistemas externos (webhooks, notificações e ERP)
+	EnvironmentSandbox Environment = "sandbox"
+)
+
+// IsValid verifica se o ambiente é conhecido
+func (e Environment) IsValid() bool {
+	return e == EnvironmentProduction || e == EnvironmentSandbox
+}
+
+// environmentKey é a chave do ambiente no contexto da requisição
+type environmentKey struct{}
+
+// WithEnvironment associa o ambiente ao contexto
+func WithEnvironment(ctx context.Context, environment Environment) context.Context {
+	return context.WithValue(ctx, environmentKey{}, environment)
+}
+
+// EnvironmentFromContext recupera o ambiente do contexto, ou produção quando não foi informado
+func EnvironmentFromContext(ctx context.Context) Environment {
+	if environment, ok := ctx.Value(environmentKey{}).(Environment); ok && environment != "" {
+		return environment
+	}
+	return EnvironmentProduction
+}
+
+// IsSandbox indica se a operação do contexto pertence ao ambiente sandbox
+func IsSandbox(ctx context.Context) bool {
+	return EnvironmentFromContext(ctx) == EnvironmentSandbox
+}
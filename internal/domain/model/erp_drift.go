@@ -0,0 +1,149 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ERPBillet representa um boleto em aberto no ERP, como informado pela API do ERP
+type ERPBillet struct {
+	ID           string    `json:"billet_id"`
+	BankAccount  string    `json:"bank_account"`
+	Amount       float64   `json:"amount"`
+	IssuanceDate time.Time `json:"issuance_date"`
+	ReferenceID  *string   `json:"reference_id,omitempty"`
+}
+
+// ERPDriftKind define o tipo de divergência entre os boletos em aberto do ERP e a base local
+type ERPDriftKind string
+
+const (
+	// DriftMissingLocally indica um boleto em aberto no ERP que não existe na base local
+	DriftMissingLocally ERPDriftKind = "ausente_localmente"
+
+	// DriftReconciledLocally indica um boleto em aberto no ERP que já está conciliado, ou aguardando
+	// aprovação, na base local
+	DriftReconciledLocally ERPDriftKind = "conciliado_localmente"
+
+	// DriftMissingInERP indica um boleto em aberto na base local que não está em aberto no ERP
+	DriftMissingInERP ERPDriftKind = "ausente_no_erp"
+
+	// DriftAmountMismatch indica um boleto em aberto nos dois lados com valores diferentes
+	DriftAmountMismatch ERPDriftKind = "valor_divergente"
+)
+
+// ERPDrift representa a divergência de um boleto entre o ERP e a base local
+type ERPDrift struct {
+	Kind        ERPDriftKind `json:"kind"`
+	BilletID    string       `json:"billet_id"`
+	BankAccount string       `json:"bank_account"`
+	ERPAmount   *float64     `json:"erp_amount,omitempty"`
+	LocalAmount *float64     `json:"local_amount,omitempty"`
+}
+
+// ERPDriftReport representa o resultado de uma verificação de divergências entre os boletos em aberto
+// do ERP e os boletos pendentes da base local
+type ERPDriftReport struct {
+	ID                     string      `json:"id"`
+	CheckedAt              time.Time   `json:"checked_at"`
+	ERPOpenCount           int         `json:"erp_open_count"`
+	LocalOpenCount         int         `json:"local_open_count"`
+	MissingLocallyCount    int         `json:"missing_locally_count"`
+	ReconciledLocallyCount int         `json:"reconciled_locally_count"`
+	MissingInERPCount      int         `json:"missing_in_erp_count"`
+	AmountMismatchCount    int         `json:"amount_mismatch_count"`
+	Drifts                 []*ERPDrift `json:"drifts"`
+}
+
+// NewERPDriftReport compara os boletos em aberto do ERP com os pendentes da base local. known traz os
+// boletos do ERP encontrados na base local fora das pendências, que já foram conciliados localmente.
+// Boletos de valor aberto não têm o valor comparado
+func NewERPDriftReport(erpOpen []*ERPBillet, localOpen []*Billet, known []*Billet) *ERPDriftReport {
+	report := &ERPDriftReport{
+		ID:             generateUUID(),
+		CheckedAt:      time.Now(),
+		ERPOpenCount:   len(erpOpen),
+		LocalOpenCount: len(localOpen),
+		Drifts:         []*ERPDrift{},
+	}
+
+	pending := make(map[string]*Billet, len(localOpen))
+	for _, billet := range localOpen {
+		pending[billet.ID] = billet
+	}
+
+	existing := make(map[string]bool, len(known))
+	for _, billet := range known {
+		existing[billet.ID] = true
+	}
+
+	inERP := make(map[string]bool, len(erpOpen))
+	for _, erpBillet := range erpOpen {
+		inERP[erpBillet.ID] = true
+		erpAmount := erpBillet.Amount
+
+		local, found := pending[erpBillet.ID]
+		switch {
+		case found:
+			if !local.OpenAmount && math.Round(local.Amount*100) != math.Round(erpAmount*100) {
+				localAmount := local.Amount
+				report.add(&ERPDrift{Kind: DriftAmountMismatch, BilletID: erpBillet.ID, BankAccount: local.BankAccount,
+					ERPAmount: &erpAmount, LocalAmount: &localAmount})
+			}
+		case existing[erpBillet.ID]:
+			report.add(&ERPDrift{Kind: DriftReconciledLocally, BilletID: erpBillet.ID, BankAccount: erpBillet.BankAccount,
+				ERPAmount: &erpAmount})
+		default:
+			report.add(&ERPDrift{Kind: DriftMissingLocally, BilletID: erpBillet.ID, BankAccount: erpBillet.BankAccount,
+				ERPAmount: &erpAmount})
+		}
+	}
+
+	for _, billet := range localOpen {
+		if !inERP[billet.ID] {
+			localAmount := billet.Amount
+			report.add(&ERPDrift{Kind: DriftMissingInERP, BilletID: billet.ID, BankAccount: billet.BankAccount,
+				LocalAmount: &localAmount})
+		}
+	}
+
+	sort.Slice(report.Drifts, func(i, j int) bool {
+		if report.Drifts[i].Kind != report.Drifts[j].Kind {
+			return report.Drifts[i].Kind < report.Drifts[j].Kind
+		}
+		return report.Drifts[i].BilletID < report.Drifts[j].BilletID
+	})
+
+	return report
+}
+
+// add inclui a divergência no relatório e atualiza a contagem do tipo
+func (r *ERPDriftReport) add(drift *ERPDrift) {
+	r.Drifts = append(r.Drifts, drift)
+
+	switch drift.Kind {
+	case DriftMissingLocally:
+		r.MissingLocallyCount++
+	case DriftReconciledLocally:
+		r.ReconciledLocallyCount++
+	case DriftMissingInERP:
+		r.MissingInERPCount++
+	case DriftAmountMismatch:
+		r.AmountMismatchCount++
+	}
+}
+
+// HasDrifts verifica se a verificação encontrou alguma divergência
+func (r *ERPDriftReport) HasDrifts() bool {
+	return len(r.Drifts) > 0
+}
+
+// Summary descreve as divergências no formato enviado na notificação da verificação
+func (r *ERPDriftReport) Summary() string {
+	return fmt.Sprintf("%d divergências entre o ERP e a base local: %d ausentes na base, %d já conciliados na base, "+
+		"%d ausentes no ERP, %d com valor divergente (%d em aberto no ERP, %d pendentes na base)",
+		len(r.Drifts), r.MissingLocallyCount, r.ReconciledLocallyCount, r.MissingInERPCount, r.AmountMismatchCount,
+		r.ERPOpenCount, r.LocalOpenCount)
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxERPSyncErrors limita quantos erros de boletos são mantidos no estado da sincronização
+const MaxERPSyncErrors = 100
+
+// ERPBilletChange representa um boleto criado ou alterado no ERP, com a data da alteração usada como
+// cursor da sincronização
+type ERPBilletChange struct {
+	Billet    *Billet   `json:"billet"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ERPSyncError representa um boleto do ERP que não pôde ser sincronizado
+type ERPSyncError struct {
+	BilletID string `json:"billet_id"`
+	Error    string `json:"error"`
+}
+
+// ERPSyncState representa o estado da sincronização dos boletos de uma origem do ERP: o cursor, que é a
+// data de alteração do último boleto sincronizado, e o resultado da sincronização mais recente
+type ERPSyncState struct {
+	Source         string          `json:"source"`
+	Cursor         *time.Time      `json:"cursor,omitempty"`
+	SyncedAt       time.Time       `json:"synced_at"`
+	CreatedCount   int             `json:"created_count"`
+	UpdatedCount   int             `json:"updated_count"`
+	UnchangedCount int             `json:"unchanged_count"`
+	SkippedCount   int             `json:"skipped_count"` // Boletos já conciliados na base local, que não podem ser alterados
+	FailedCount    int             `json:"failed_count"`
+	Errors         []*ERPSyncError `json:"errors"`
+}
+
+// NewERPSyncState inicia o estado de uma sincronização a partir do cursor da sincronização anterior
+func NewERPSyncState(source string, cursor *time.Time) *ERPSyncState {
+	return &ERPSyncState{
+		Source:   source,
+		Cursor:   cursor,
+		SyncedAt: time.Now(),
+		Errors:   []*ERPSyncError{},
+	}
+}
+
+// Advance move o cursor para a data de alteração de um boleto processado, sem retroceder
+func (s *ERPSyncState) Advance(updatedAt time.Time) {
+	if s.Cursor == nil || updatedAt.After(*s.Cursor) {
+		cursor := updatedAt
+		s.Cursor = &cursor
+	}
+}
+
+// Fail registra um boleto que não pôde ser sincronizado
+func (s *ERPSyncState) Fail(billetID string, err error) {
+	s.FailedCount++
+	if len(s.Errors) < MaxERPSyncErrors {
+		s.Errors = append(s.Errors, &ERPSyncError{BilletID: billetID, Error: err.Error()})
+	}
+}
+
+// Total conta os boletos recebidos do ERP na sincronização
+func (s *ERPSyncState) Total() int {
+	return s.CreatedCount + s.UpdatedCount + s.UnchangedCount + s.SkippedCount + s.FailedCount
+}
+
+// Summary descreve o resultado da sincronização para os logs
+func (s *ERPSyncState) Summary() string {
+	return fmt.Sprintf("%d boletos do ERP: %d criados, %d alterados, %d sem alteração, %d já conciliados, %d com erro",
+		s.Total(), s.CreatedCount, s.UpdatedCount, s.UnchangedCount, s.SkippedCount, s.FailedCount)
+}
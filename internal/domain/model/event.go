@@ -0,0 +1,87 @@
+package model
+
+import (
+	"time"
+)
+
+// EventType define os tipos de evento publicados pelo sistema
+type EventType string
+
+const (
+	EventBilletReconciled   EventType = "boleto_conciliado"
+	EventOrphanBillet       EventType = "boleto_orfao"
+	EventOrphanPayment      EventType = "pagamento_orfao"
+	EventAmbiguousReference EventType = "referencia_ambigua"
+	EventImportSequenceGap  EventType = "lacuna_sequencia_arquivo"
+	EventDailyClosing       EventType = "fechamento_diario_confirmado"
+	EventPendingReport      EventType = "relatorio_pendencias"
+
+	// EventReconciliationUndone informa que um operador desfez a conciliação de um boleto, que volta a
+	// ficar em aberto
+	EventReconciliationUndone EventType = "conciliacao_desfeita"
+
+	// EventReconciliationSummary resume uma execução de conciliação concluída, com os totais e a taxa
+	// de conciliação
+	EventReconciliationSummary EventType = "resumo_conciliacao"
+
+	// EventERPDrift informa as divergências encontradas entre os boletos em aberto do ERP e a base local
+	EventERPDrift EventType = "divergencia_erp"
+)
+
+// Eventos internos, consumidos pelo próprio sistema (ex.: estatísticas em tempo real) e não
+// entregues aos assinantes
+const (
+	EventPaymentImported   EventType = "pagamento_importado"
+	EventReconciliationRun EventType = "conciliacao_executada"
+)
+
+// KnownEventTypes lista os tipos de evento aceitos nas assinaturas
+var KnownEventTypes = []EventType{
+	EventBilletReconciled,
+	EventOrphanBillet,
+	EventOrphanPayment,
+	EventAmbiguousReference,
+	EventImportSequenceGap,
+	EventDailyClosing,
+	EventPendingReport,
+	EventReconciliationUndone,
+	EventReconciliationSummary,
+	EventERPDrift,
+}
+
+// IsKnownEventType verifica se o tipo de evento é suportado
+func IsKnownEventType(eventType EventType) bool {
+	for _, known := range KnownEventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event representa um evento de negócio entregue aos assinantes
+type Event struct {
+	ID            string    `json:"id"`
+	Type          EventType `json:"type"`
+	BankAccount   string    `json:"bank_account"`
+	Amount        float64   `json:"amount"`
+	BilletID      string    `json:"billet_id,omitempty"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	ReferenceID   *string   `json:"reference_id,omitempty"`
+	Description   string    `json:"description,omitempty"`
+	OccurredAt    time.Time `json:"occurred_at"`
+
+	// Summary traz o resumo da execução nos eventos de resumo de conciliação
+	Summary *RunSummary `json:"summary,omitempty"`
+}
+
+// NewEvent cria uma nova instância de Event
+func NewEvent(eventType EventType, bankAccount string, amount float64) *Event {
+	return &Event{
+		ID:          generateUUID(),
+		Type:        eventType,
+		BankAccount: bankAccount,
+		Amount:      amount,
+		OccurredAt:  time.Now(),
+	}
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"time"
+)
+
+// DeliveryStatus define a situação de uma entrega de eventos no outbox
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pendente"
+	DeliveryDelivered DeliveryStatus = "entregue"
+	DeliveryFailed    DeliveryStatus = "falha"
+	DeliveryDiscarded DeliveryStatus = "descartada"
+)
+
+// IsKnownDeliveryStatus verifica se a situação de entrega é suportada
+func IsKnownDeliveryStatus(status DeliveryStatus) bool {
+	switch status {
+	case DeliveryPending, DeliveryDelivered, DeliveryFailed, DeliveryDiscarded:
+		return true
+	}
+	return false
+}
+
+// EventDelivery representa a entrega de um conjunto de eventos a um assinante, persistida no outbox
+// antes do envio para que falhas possam ser reprocessadas
+type EventDelivery struct {
+	ID             string         `json:"id"`
+	SubscriptionID string         `json:"subscription_id"`
+	Events         []*Event       `json:"events"`
+	Status         DeliveryStatus `json:"status"`
+	Attempts       int            `json:"attempts"`
+	LastError      *string        `json:"last_error,omitempty"`
+	DiscardReason  *string        `json:"discard_reason,omitempty"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// NewEventDelivery cria uma nova entrega pendente, elegível para envio imediato
+func NewEventDelivery(subscriptionID string, events []*Event) *EventDelivery {
+	now := time.Now()
+
+	return &EventDelivery{
+		ID:             generateUUID(),
+		SubscriptionID: subscriptionID,
+		Events:         events,
+		Status:         DeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// OutboxMetrics representa o volume e o atraso de publicação das entregas de eventos
+type OutboxMetrics struct {
+	Pending                int     `json:"pending"`
+	Failed                 int     `json:"failed"`
+	OldestPendingSeconds   float64 `json:"oldest_pending_seconds"`
+	DeliveredInWindow      int     `json:"delivered_in_window"`
+	AvgPublishDelaySeconds float64 `json:"avg_publish_delay_seconds"`
+	P95PublishDelaySeconds float64 `json:"p95_publish_delay_seconds"`
+	MaxPublishDelaySeconds float64 `json:"max_publish_delay_seconds"`
+	WindowSeconds          float64 `json:"window_seconds"`
+}
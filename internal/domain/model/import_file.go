@@ -0,0 +1,95 @@
+package model
+
+import (
+	"time"
+)
+
+// TotalsPolicy define o tratamento de arquivos cujos totais do trailer divergem dos registros importados
+type TotalsPolicy string
+
+const (
+	// TotalsPolicyReject recusa o arquivo inteiro
+	TotalsPolicyReject TotalsPolicy = "rejeitar"
+	// TotalsPolicyFlag importa o arquivo retendo os pagamentos para revisão manual antes da conciliação
+	TotalsPolicyFlag TotalsPolicy = "sinalizar"
+)
+
+// ImportFile representa um arquivo bancário (CNAB) importado, identificado pelo número sequencial
+// do arquivo dentro do convênio
+type ImportFile struct {
+	ID          string    `json:"id"`
+	BankCode    string    `json:"bank_code"`
+	Agreement   string    `json:"agreement"` // Código do convênio no banco
+	Direction   string    `json:"direction"` // remessa ou retorno
+	Sequence    int       `json:"sequence"`
+	FileName    string    `json:"file_name,omitempty"`
+	BankAccount string    `json:"bank_account"`
+	Records     int       `json:"records"`
+	Payments    int       `json:"payments"`
+	GeneratedAt time.Time `json:"generated_at"`
+	ImportedAt  time.Time `json:"imported_at"`
+
+	// Divergências entre os totais do trailer e os registros do arquivo
+	Inconsistencies []string `json:"inconsistencies,omitempty"`
+}
+
+// NewImportFile cria uma nova instância de ImportFile
+func NewImportFile(bankCode, agreement, direction string, sequence int, generatedAt time.Time) *ImportFile {
+	return &ImportFile{
+		ID:          generateUUID(),
+		BankCode:    bankCode,
+		Agreement:   agreement,
+		Direction:   direction,
+		Sequence:    sequence,
+		GeneratedAt: generatedAt,
+		ImportedAt:  time.Now(),
+	}
+}
+
+// IsStatement indica se o arquivo é um extrato (CSV, OFX): extratos não têm convênio nem número
+// sequencial e não são registrados entre os arquivos importados
+func (f *ImportFile) IsStatement() bool {
+	return f.Sequence == 0
+}
+
+// IsConsistent indica se os totais do trailer conferem com os registros do arquivo
+func (f *ImportFile) IsConsistent() bool {
+	return len(f.Inconsistencies) == 0
+}
+
+// ImportGap representa uma lacuna na numeração sequencial dos arquivos de um convênio,
+// indicando arquivos que não foram recebidos/importados
+type ImportGap struct {
+	BankCode         string `json:"bank_code"`
+	Agreement        string `json:"agreement"`
+	Direction        string `json:"direction"`
+	PreviousSequence int    `json:"previous_sequence"`
+	NextSequence     int    `json:"next_sequence"`
+	MissingFrom      int    `json:"missing_from"`
+	MissingTo        int    `json:"missing_to"`
+	MissingCount     int    `json:"missing_count"`
+}
+
+// NewImportGap cria a lacuna entre dois números sequenciais importados consecutivamente
+func NewImportGap(bankCode, agreement, direction string, previousSequence, nextSequence int) *ImportGap {
+	return &ImportGap{
+		BankCode:         bankCode,
+		Agreement:        agreement,
+		Direction:        direction,
+		PreviousSequence: previousSequence,
+		NextSequence:     nextSequence,
+		MissingFrom:      previousSequence + 1,
+		MissingTo:        nextSequence - 1,
+		MissingCount:     nextSequence - previousSequence - 1,
+	}
+}
+
+// ImportResult representa o resultado da importação de um arquivo bancário
+type ImportResult struct {
+	File *ImportFile `json:"file"`
+	Gap  *ImportGap  `json:"gap,omitempty"` // Lacuna detectada em relação ao último arquivo do convênio
+	Mode ImportMode  `json:"mode"`
+
+	// Items traz o resultado de cada pagamento do arquivo na importação best_effort
+	Items []BatchItemResult `json:"items,omitempty"`
+}
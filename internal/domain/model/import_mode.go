@@ -0,0 +1,35 @@
+package model
+
+// ImportMode define o tratamento dos itens inválidos em uma importação em lote
+type ImportMode string
+
+const (
+	// ImportModeAllOrNothing grava todos os itens em uma única transação e aborta a importação
+	// inteira se qualquer item for inválido ou falhar
+	ImportModeAllOrNothing ImportMode = "all_or_nothing"
+
+	// ImportModeBestEffort grava os itens válidos e reporta os inválidos
+	ImportModeBestEffort ImportMode = "best_effort"
+)
+
+// IsValid verifica se o modo de importação é suportado
+func (m ImportMode) IsValid() bool {
+	return m == ImportModeAllOrNothing || m == ImportModeBestEffort
+}
+
+// OrDefault retorna o modo de importação, ou o modo padrão informado quando não definido
+func (m ImportMode) OrDefault(defaultMode ImportMode) ImportMode {
+	if m == "" {
+		return defaultMode
+	}
+	return m
+}
+
+// BatchItemResult representa o resultado de um item de uma importação em lote, identificado pela
+// posição no lote e pelo ID informado
+type BatchItemResult struct {
+	Index   int    `json:"index"`
+	ID      string `json:"id,omitempty"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// LedgerItemKind define a natureza de um item que compõe a diferença entre o razão e o sistema
+type LedgerItemKind string
+
+const (
+	// LedgerItemOpenBillet é um boleto do período sem liquidação até o fim do período
+	LedgerItemOpenBillet LedgerItemKind = "boleto_em_aberto"
+	// LedgerItemAmountDifference é o saldo residual de um boleto conciliado com valor diferente
+	LedgerItemAmountDifference LedgerItemKind = "diferenca_de_valor"
+	// LedgerItemUnreconciledPayment é um crédito do período não associado a nenhum boleto, que o
+	// razão pode já ter baixado de contas a receber
+	LedgerItemUnreconciledPayment LedgerItemKind = "pagamento_nao_conciliado"
+)
+
+// LedgerComparisonItem representa um item do detalhamento da comparação com o razão. Amount é o
+// efeito do item: positivo para saldo em aberto no sistema, negativo para baixas esperadas no razão
+type LedgerComparisonItem struct {
+	Kind          LedgerItemKind `json:"kind"`
+	BankAccount   string         `json:"bank_account"`
+	BilletID      string         `json:"billet_id,omitempty"`
+	TransactionID string         `json:"transaction_id,omitempty"`
+	Date          time.Time      `json:"date"`
+	Amount        float64        `json:"amount"`
+}
+
+// LedgerComparison representa a comparação entre o saldo de contas a receber do razão contábil e o
+// saldo em aberto dos boletos do período segundo as conciliações registradas
+type LedgerComparison struct {
+	StartDate             time.Time               `json:"start_date"`
+	EndDate               time.Time               `json:"end_date"`
+	BankAccounts          []string                `json:"bank_accounts,omitempty"`
+	LedgerBalance         float64                 `json:"ledger_balance"`
+	SystemBalance         float64                 `json:"system_balance"`
+	Difference            float64                 `json:"difference"`
+	ExplainedDifference   float64                 `json:"explained_difference"`
+	UnexplainedDifference float64                 `json:"unexplained_difference"`
+	Items                 []*LedgerComparisonItem `json:"items"`
+}
+
+// NewLedgerComparison cria uma comparação a partir do saldo informado pelo razão
+func NewLedgerComparison(startDate, endDate time.Time, bankAccounts []string, ledgerBalance float64) *LedgerComparison {
+	return &LedgerComparison{
+		StartDate:     startDate,
+		EndDate:       endDate,
+		BankAccounts:  bankAccounts,
+		LedgerBalance: ledgerBalance,
+		Items:         []*LedgerComparisonItem{},
+	}
+}
+
+// AddItem inclui um item no detalhamento
+func (c *LedgerComparison) AddItem(kind LedgerItemKind, bankAccount, billetID, transactionID string, date time.Time, amount float64) {
+	c.Items = append(c.Items, &LedgerComparisonItem{
+		Kind:          kind,
+		BankAccount:   bankAccount,
+		BilletID:      billetID,
+		TransactionID: transactionID,
+		Date:          date,
+		Amount:        roundCents(amount),
+	})
+}
+
+// Finish calcula os saldos e as diferenças a partir dos itens e os ordena por data. Os boletos em
+// aberto e os resíduos compõem o saldo do sistema; os pagamentos não conciliados explicam a diferença
+func (c *LedgerComparison) Finish() {
+	var system, explained float64
+	for _, item := range c.Items {
+		switch item.Kind {
+		case LedgerItemOpenBillet, LedgerItemAmountDifference:
+			system += item.Amount
+		case LedgerItemUnreconciledPayment:
+			explained += item.Amount
+		}
+	}
+
+	c.SystemBalance = roundCents(system)
+	c.Difference = roundCents(c.LedgerBalance - c.SystemBalance)
+	c.ExplainedDifference = roundCents(explained)
+	c.UnexplainedDifference = roundCents(c.Difference - c.ExplainedDifference)
+
+	sort.SliceStable(c.Items, func(i, j int) bool {
+		return c.Items[i].Date.Before(c.Items[j].Date)
+	})
+}
+
+// roundCents arredonda um valor monetário para centavos
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+)
+
+// LiveStats representa os contadores transmitidos em tempo real aos painéis do time financeiro
+type LiveStats struct {
+	Tenant                string                  `json:"tenant"`
+	Date                  string                  `json:"date"`
+	PaymentsImportedToday int                     `json:"payments_imported_today"`
+	AmountImportedToday   float64                 `json:"amount_imported_today"`
+	LastRun               *ReconciliationRunStats `json:"last_run"`
+	UpdatedAt             time.Time               `json:"updated_at"`
+}
+
+// ReconciliationRunStats resume o resultado de uma execução da conciliação
+type ReconciliationRunStats struct {
+	ExecutedAt          time.Time `json:"executed_at"`
+	Reconciled          int       `json:"reconciled"`
+	ReconciledAmount    float64   `json:"reconciled_amount"`
+	OrphanBillets       int       `json:"orphan_billets"`
+	OrphanPayments      int       `json:"orphan_payments"`
+	AmbiguousReferences int       `json:"ambiguous_references"`
+}
+
+// Count contabiliza um evento da execução
+func (s *ReconciliationRunStats) Count(event *Event) {
+	switch event.Type {
+	case EventBilletReconciled:
+		s.Reconciled++
+		s.ReconciledAmount += event.Amount
+	case EventOrphanBillet:
+		s.OrphanBillets++
+	case EventOrphanPayment:
+		s.OrphanPayments++
+	case EventAmbiguousReference:
+		s.AmbiguousReferences++
+	}
+}
+
+// Add soma os contadores de outra execução
+func (s *ReconciliationRunStats) Add(other *ReconciliationRunStats) {
+	s.Reconciled += other.Reconciled
+	s.ReconciledAmount += other.ReconciledAmount
+	s.OrphanBillets += other.OrphanBillets
+	s.OrphanPayments += other.OrphanPayments
+	s.AmbiguousReferences += other.AmbiguousReferences
+}
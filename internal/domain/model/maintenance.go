@@ -0,0 +1,127 @@
+package model
+
+import (
+	"time"
+)
+
+// RetentionPolicy define por quanto tempo a rotina de manutenção mantém cada tipo de registro; uma
+// retenção zero mantém os registros indefinidamente
+type RetentionPolicy struct {
+	// Runs é a retenção das execuções de conciliação concluídas, contada a partir da conclusão
+	Runs time.Duration
+
+	// IdempotencyKeys é a validade do hash de parâmetros das execuções concluídas; vencido, uma nova
+	// chamada para a mesma janela volta a conciliar em vez de repetir o resultado guardado
+	IdempotencyKeys time.Duration
+
+	// EventDeliveries é a retenção das entregas de webhook entregues ou descartadas
+	EventDeliveries time.Duration
+
+	// PendingSnapshots é a retenção dos retratos diários das pendências
+	PendingSnapshots time.Duration
+
+	// ProcessedMessages é a retenção dos registros de mensagens de fila processadas; precisa cobrir o
+	// prazo em que o broker ainda pode reentregar uma mensagem
+	ProcessedMessages time.Duration
+
+	// AuditTrail é a retenção da trilha de auditoria: mudanças de status, conciliações desfeitas,
+	// decisões de aprovação e entradas da linha do tempo
+	AuditTrail time.Duration
+
+	// PayerData é o prazo, contado a partir da quitação, após o qual os dados do pagador (documento,
+	// nome e descrição do pagamento) são anonimizados nos boletos e pagamentos conciliados (LGPD)
+	PayerData time.Duration
+}
+
+// DefaultRetentionPolicy retorna a política de retenção padrão: execuções por 90 dias, chaves de
+// idempotência por 7 dias, entregas de webhook por 30 dias, retratos das pendências por 400 dias,
+// mensagens de fila processadas por 14 dias, trilha de auditoria por 5 anos e dados do pagador por 2
+// anos após a quitação
+func DefaultRetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{
+		Runs:              90 * 24 * time.Hour,
+		IdempotencyKeys:   7 * 24 * time.Hour,
+		EventDeliveries:   30 * 24 * time.Hour,
+		PendingSnapshots:  400 * 24 * time.Hour,
+		ProcessedMessages: 14 * 24 * time.Hour,
+		AuditTrail:        5 * 365 * 24 * time.Hour,
+		PayerData:         2 * 365 * 24 * time.Hour,
+	}
+}
+
+// RetentionDays descreve a política em dias por tipo de registro, como gravada no relatório da
+// manutenção; zero indica retenção indefinida
+func (p RetentionPolicy) RetentionDays() map[string]int {
+	days := func(retention time.Duration) int {
+		return int(retention / (24 * time.Hour))
+	}
+
+	return map[string]int{
+		"runs":               days(p.Runs),
+		"idempotency_keys":   days(p.IdempotencyKeys),
+		"event_deliveries":   days(p.EventDeliveries),
+		"pending_snapshots":  days(p.PendingSnapshots),
+		"processed_messages": days(p.ProcessedMessages),
+		"audit_trail":        days(p.AuditTrail),
+		"payer_data":         days(p.PayerData),
+	}
+}
+
+// PurgeCounts contabiliza os registros removidos, ou anonimizados, pela rotina de manutenção
+type PurgeCounts struct {
+	Runs               int64 `json:"runs"`
+	IdempotencyKeys    int64 `json:"idempotency_keys"`
+	EventDeliveries    int64 `json:"event_deliveries"`
+	PendingSnapshots   int64 `json:"pending_snapshots"`
+	ExpiredClaims      int64 `json:"expired_claims"`
+	ProcessedMessages  int64 `json:"processed_messages"`
+	AuditRecords       int64 `json:"audit_records"`
+	AnonymizedBillets  int64 `json:"anonymized_billets"`
+	AnonymizedPayments int64 `json:"anonymized_payments"`
+}
+
+// Total soma os registros removidos e anonimizados
+func (c PurgeCounts) Total() int64 {
+	return c.Runs + c.IdempotencyKeys + c.EventDeliveries + c.PendingSnapshots + c.ExpiredClaims + c.ProcessedMessages +
+		c.AuditRecords + c.AnonymizedBillets + c.AnonymizedPayments
+}
+
+// add acumula a contagem de outro shard
+func (c *PurgeCounts) add(other PurgeCounts) {
+	c.Runs += other.Runs
+	c.IdempotencyKeys += other.IdempotencyKeys
+	c.EventDeliveries += other.EventDeliveries
+	c.PendingSnapshots += other.PendingSnapshots
+	c.ExpiredClaims += other.ExpiredClaims
+	c.ProcessedMessages += other.ProcessedMessages
+	c.AuditRecords += other.AuditRecords
+	c.AnonymizedBillets += other.AnonymizedBillets
+	c.AnonymizedPayments += other.AnonymizedPayments
+}
+
+// MaintenanceReport resume uma execução da rotina de manutenção, com a política aplicada, o total
+// removido e o de cada shard. É gravado como registro do que foi expurgado
+type MaintenanceReport struct {
+	ID            string                 `json:"id"`
+	RetentionDays map[string]int         `json:"retention_days"`
+	Removed       PurgeCounts            `json:"removed"`
+	Shards        map[string]PurgeCounts `json:"shards"`
+	StartedAt     time.Time              `json:"started_at"`
+	DurationMs    int64                  `json:"duration_ms"`
+}
+
+// NewMaintenanceReport cria o resumo vazio de uma execução da manutenção com a política informada
+func NewMaintenanceReport(startedAt time.Time, policy RetentionPolicy) *MaintenanceReport {
+	return &MaintenanceReport{
+		ID:            generateUUID(),
+		RetentionDays: policy.RetentionDays(),
+		Shards:        make(map[string]PurgeCounts),
+		StartedAt:     startedAt,
+	}
+}
+
+// RecordShard registra o que foi removido de um shard, acumulando no total
+func (r *MaintenanceReport) RecordShard(shard string, counts PurgeCounts) {
+	r.Shards[shard] = counts
+	r.Removed.add(counts)
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// NotificationSeverity define a gravidade de uma notificação, usada no roteamento aos canais
+type NotificationSeverity string
+
+const (
+	SeverityInfo     NotificationSeverity = "info"
+	SeverityWarning  NotificationSeverity = "aviso"
+	SeverityCritical NotificationSeverity = "critico"
+)
+
+// severityRank ordena as gravidades da menor para a maior
+var severityRank = map[NotificationSeverity]int{
+	SeverityInfo:     1,
+	SeverityWarning:  2,
+	SeverityCritical: 3,
+}
+
+// IsValid verifica se a gravidade é suportada
+func (s NotificationSeverity) IsValid() bool {
+	_, ok := severityRank[s]
+	return ok
+}
+
+// AtLeast verifica se a gravidade é igual ou maior que a mínima informada. Uma mínima vazia aceita todas
+func (s NotificationSeverity) AtLeast(minimum NotificationSeverity) bool {
+	return minimum == "" || severityRank[s] >= severityRank[minimum]
+}
+
+// EventSeverity retorna a gravidade padrão das notificações de um tipo de evento
+func EventSeverity(eventType EventType) NotificationSeverity {
+	switch eventType {
+	case EventImportSequenceGap:
+		return SeverityCritical
+	case EventOrphanBillet, EventOrphanPayment, EventAmbiguousReference, EventERPDrift:
+		return SeverityWarning
+	default:
+		return SeverityInfo
+	}
+}
+
+// Notification representa uma notificação de um evento de negócio enviada aos canais do tenant
+type Notification struct {
+	Tenant    string               `json:"tenant,omitempty"`
+	Severity  NotificationSeverity `json:"severity"`
+	Subject   string               `json:"subject"`
+	Message   string               `json:"message"`
+	Event     *Event               `json:"event"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+// NewNotification cria a notificação de um evento com a gravidade padrão do tipo de evento
+func NewNotification(tenant string, event *Event) *Notification {
+	severity := EventSeverity(event.Type)
+
+	message := fmt.Sprintf("conta %s, valor %.2f", event.BankAccount, event.Amount)
+	if event.BilletID != "" {
+		message += ", boleto " + event.BilletID
+	}
+	if event.TransactionID != "" {
+		message += ", pagamento " + event.TransactionID
+	}
+	if event.Description != "" {
+		message += ": " + event.Description
+	}
+
+	return &Notification{
+		Tenant:    tenant,
+		Severity:  severity,
+		Subject:   fmt.Sprintf("[%s] %s", severity, event.Type),
+		Message:   message,
+		Event:     event,
+		CreatedAt: time.Now(),
+	}
+}
+
+// NotificationRoute direciona as notificações de um tenant aos canais informados, filtrando por tipo
+// de evento e gravidade mínima. Sem tipos de evento, a rota aceita todos os tipos conhecidos
+type NotificationRoute struct {
+	EventTypes  []EventType          `json:"event_types,omitempty"`
+	MinSeverity NotificationSeverity `json:"min_severity,omitempty"`
+	Channels    []string             `json:"channels"`
+}
+
+// Matches verifica se a rota aceita a notificação
+func (r NotificationRoute) Matches(notification *Notification) bool {
+	if !notification.Severity.AtLeast(r.MinSeverity) {
+		return false
+	}
+
+	if len(r.EventTypes) == 0 {
+		return true
+	}
+
+	for _, eventType := range r.EventTypes {
+		if eventType == notification.Event.Type {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"text/template"
+	"time"
+)
+
+// NotificationTemplate define o assunto e a mensagem, em Go template, das notificações de um tipo de
+// evento de um tenant. Cada alteração grava uma nova versão; a de maior número é a aplicada
+type NotificationTemplate struct {
+	ID        string    `json:"id"`
+	Tenant    string    `json:"tenant"`
+	EventType EventType `json:"event_type"`
+	Version   int       `json:"version"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NotificationTemplateData reúne as variáveis disponíveis nos templates de notificação. Summary só é
+// preenchido nos eventos de resumo de conciliação
+type NotificationTemplateData struct {
+	Tenant   string
+	Severity NotificationSeverity
+	Event    *Event
+	Summary  *RunSummary
+	Links    NotificationLinks
+}
+
+// NotificationLinks reúne os links da API relacionados ao evento, vazios quando não se aplicam ou
+// quando a URL base das notificações não está configurada
+type NotificationLinks struct {
+	Run    string
+	Billet string
+}
+
+// NewNotificationTemplate cria uma versão do template do tenant para o tipo de evento; o número da
+// versão é atribuído ao gravar
+func NewNotificationTemplate(tenant string, eventType EventType, subject, body, createdBy string) *NotificationTemplate {
+	return &NotificationTemplate{
+		ID:        generateUUID(),
+		Tenant:    tenant,
+		EventType: eventType,
+		Subject:   subject,
+		Body:      body,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Validate verifica se o assunto e a mensagem são templates válidos, executando-os com variáveis de
+// exemplo do tipo de evento
+func (t *NotificationTemplate) Validate() error {
+	sample := NotificationTemplateData{
+		Tenant:   t.Tenant,
+		Severity: EventSeverity(t.EventType),
+		Event:    NewEvent(t.EventType, "", 0),
+	}
+	if t.EventType == EventReconciliationSummary {
+		sample.Summary = &RunSummary{}
+	}
+	_, err := t.render(sample, io.Discard)
+	return err
+}
+
+// Render aplica o template à notificação, substituindo o assunto e a mensagem padrão
+func (t *NotificationTemplate) Render(notification *Notification, links NotificationLinks) error {
+	data := NotificationTemplateData{
+		Tenant:   notification.Tenant,
+		Severity: notification.Severity,
+		Event:    notification.Event,
+		Links:    links,
+	}
+	if notification.Event != nil {
+		data.Summary = notification.Event.Summary
+	}
+
+	var message bytes.Buffer
+	subject, err := t.render(data, &message)
+	if err != nil {
+		return err
+	}
+
+	notification.Subject = subject
+	notification.Message = message.String()
+	return nil
+}
+
+// render executa o assunto e a mensagem com as variáveis informadas, escrevendo a mensagem em body
+func (t *NotificationTemplate) render(data NotificationTemplateData, body io.Writer) (string, error) {
+	subjectTemplate, err := template.New("subject").Option("missingkey=error").Parse(t.Subject)
+	if err != nil {
+		return "", fmt.Errorf("assunto inválido: %w", err)
+	}
+	bodyTemplate, err := template.New("body").Option("missingkey=error").Parse(t.Body)
+	if err != nil {
+		return "", fmt.Errorf("mensagem inválida: %w", err)
+	}
+
+	var subject bytes.Buffer
+	if err := subjectTemplate.Execute(&subject, data); err != nil {
+		return "", fmt.Errorf("assunto inválido: %w", err)
+	}
+	if err := bodyTemplate.Execute(body, data); err != nil {
+		return "", fmt.Errorf("mensagem inválida: %w", err)
+	}
+
+	return subject.String(), nil
+}
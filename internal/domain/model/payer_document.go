@@ -0,0 +1,63 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tamanhos do documento do pagador: CPF para pessoas físicas e CNPJ para pessoas jurídicas
+const (
+	CPFLength  = 11
+	CNPJLength = 14
+)
+
+// ValidatePayerDocument verifica se o documento do pagador é um CPF ou um CNPJ válido, apenas dígitos,
+// conferindo os dois dígitos verificadores
+func ValidatePayerDocument(document string) error {
+	if OnlyDigits(document) != document || (len(document) != CPFLength && len(document) != CNPJLength) {
+		return fmt.Errorf("documento do pagador deve ter %d dígitos (CPF) ou %d dígitos (CNPJ)", CPFLength, CNPJLength)
+	}
+
+	// Sequências de um mesmo dígito passam no cálculo, mas não são documentos emitidos
+	if strings.Count(document, document[:1]) == len(document) {
+		return fmt.Errorf("documento do pagador inválido")
+	}
+
+	// Os pesos do CPF crescem sem reinício; os do CNPJ reiniciam em 2 depois de 9
+	maxWeight := CPFLength
+	if len(document) == CNPJLength {
+		maxWeight = 9
+	}
+
+	body := document[:len(document)-2]
+	first := documentCheckDigit(body, maxWeight)
+	second := documentCheckDigit(body+string(first), maxWeight)
+	if document[len(document)-2] != first || document[len(document)-1] != second {
+		return fmt.Errorf("dígitos verificadores do documento do pagador inválidos")
+	}
+	return nil
+}
+
+// IsCNPJ indica se o documento do pagador é de pessoa jurídica
+func IsCNPJ(document string) bool {
+	return len(document) == CNPJLength
+}
+
+// documentCheckDigit calcula um dígito verificador do CPF ou do CNPJ (módulo 11, pesos a partir de 2 da
+// direita para a esquerda); restos menores que 2 resultam no dígito 0
+func documentCheckDigit(digits string, maxWeight int) byte {
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > maxWeight {
+			weight = 2
+		}
+	}
+
+	remainder := sum % 11
+	if remainder < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - remainder)
+}
@@ -4,6 +4,34 @@ import (
 	"time"
 )
 
+// EntryType define o tipo de lançamento do extrato bancário
+type EntryType string
+
+const (
+	EntryTypeCredit EntryType = "credito"
+	EntryTypeDebit  EntryType = "debito"
+)
+
+// PaymentReviewStatus define a situação de revisão manual de um pagamento
+type PaymentReviewStatus string
+
+const (
+	ReviewStatusNone       PaymentReviewStatus = ""
+	ReviewStatusSuspicious PaymentReviewStatus = "suspeito"
+	ReviewStatusApproved   PaymentReviewStatus = "aprovado"
+
+	// ReviewStatusBelowMinimum marca os pagamentos abaixo do valor mínimo da conciliação automática
+	ReviewStatusBelowMinimum PaymentReviewStatus = "ignorado_por_valor_minimo"
+)
+
+// PaymentStatus define a situação de conciliação de um pagamento
+type PaymentStatus string
+
+const (
+	PaymentStatusReceived   PaymentStatus = "recebido"
+	PaymentStatusReconciled PaymentStatus = "conciliado"
+)
+
 // Payment representa um pagamento bancário recebido no sistema
 type Payment struct {
 	ID          string    `json:"transaction_id"`
@@ -11,6 +39,33 @@ type Payment struct {
 	Amount      float64   `json:"amount"`
 	PaymentDate time.Time `json:"payment_date"`
 	ReferenceID *string   `json:"reference_id,omitempty"`
+	EntryType   EntryType `json:"entry_type"`
+
+	// BankCode identifica o banco (código COMPE) de origem do lançamento
+	BankCode string `json:"bank_code,omitempty"`
+
+	// BarCode traz o código de barras (ou a linha digitável) do título pago, devolvido pelo banco no
+	// arquivo de liquidação
+	BarCode string `json:"bar_code,omitempty"`
+
+	// PayerDocument é o CPF ou CNPJ de quem pagou, apenas dígitos, quando o extrato o informa
+	PayerDocument string `json:"payer_document,omitempty"`
+
+	// Description é o histórico do lançamento no extrato, que costuma trazer o nome de quem pagou
+	Description string `json:"description,omitempty"`
+
+	// PaymentMethod é o meio de pagamento (boleto, PIX, transferência, depósito), quando o extrato o informa
+	PaymentMethod PaymentMethod `json:"payment_method,omitempty"`
+
+	// Revisão manual de pagamentos com valor destoante do histórico da conta
+	ReviewStatus PaymentReviewStatus `json:"review_status,omitempty"`
+	ReviewReason *string             `json:"review_reason,omitempty"`
+
+	// Vínculo com a conciliação que pareou o pagamento, gravado na mesma transação da conciliação. No
+	// pagamento dividido entre boletos, aponta a última conciliação gravada
+	ReconciliationID string        `json:"reconciliation_id,omitempty"`
+	BilletID         *string       `json:"billet_id,omitempty"`
+	Status           PaymentStatus `json:"status"`
 
 	// Campos adicionais para controle interno
 	CreatedAt time.Time `json:"created_at"`
@@ -27,7 +82,43 @@ func NewPayment(id, bankAccount string, amount float64, paymentDate time.Time, r
 		Amount:      amount,
 		PaymentDate: paymentDate,
 		ReferenceID: referenceID,
+		EntryType:   EntryTypeCredit,
+		Status:      PaymentStatusReceived,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
 }
+
+// IsDebit indica se o lançamento é um débito, que nunca participa da conciliação de recebíveis
+func (p *Payment) IsDebit() bool {
+	return p.EntryType == EntryTypeDebit
+}
+
+// IsReconciled indica se o pagamento está vinculado a uma conciliação
+func (p *Payment) IsReconciled() bool {
+	return p.ReconciliationID != ""
+}
+
+// Barcode retorna o código de barras do título pago, convertendo a linha digitável quando o banco a
+// informa no lugar do código. Vazio quando o pagamento não tem código de barras válido
+func (p *Payment) Barcode() string {
+	if p.BarCode == "" {
+		return ""
+	}
+
+	barcode, err := NormalizeBarcode(p.BarCode)
+	if err != nil {
+		return ""
+	}
+	return barcode
+}
+
+// IsSuspicious indica se o pagamento está retido aguardando revisão manual
+func (p *Payment) IsSuspicious() bool {
+	return p.ReviewStatus == ReviewStatusSuspicious
+}
+
+// IsBelowMinimum indica se o pagamento foi ignorado por estar abaixo do valor mínimo da conciliação automática
+func (p *Payment) IsBelowMinimum() bool {
+	return p.ReviewStatus == ReviewStatusBelowMinimum
+}
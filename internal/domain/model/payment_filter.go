@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// PaymentFilter representa os filtros da listagem de pagamentos. O período se aplica à data do pagamento
+type PaymentFilter struct {
+	BankAccount string
+	ReferenceID string
+	EntryType   EntryType
+	StartDate   *time.Time
+	EndDate     *time.Time
+	MinAmount   *float64
+	MaxAmount   *float64
+	Limit       int
+	Offset      int
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// PaymentMethod define o meio pelo qual o pagamento foi feito
+type PaymentMethod string
+
+const (
+	PaymentMethodBillet   PaymentMethod = "boleto"
+	PaymentMethodPix      PaymentMethod = "pix"
+	PaymentMethodTransfer PaymentMethod = "transferencia" // TED ou DOC
+	PaymentMethodDeposit  PaymentMethod = "deposito"
+)
+
+// IsValid verifica se o meio de pagamento é conhecido; vazio indica que o extrato não o informa
+func (m PaymentMethod) IsValid() bool {
+	switch m {
+	case "", PaymentMethodBillet, PaymentMethodPix, PaymentMethodTransfer, PaymentMethodDeposit:
+		return true
+	}
+	return false
+}
+
+// PaymentMethodRule define a tolerância e a janela de datas das estratégias que escolhem o boleto entre
+// candidatos para os pagamentos de um meio de pagamento, no lugar dos parâmetros da execução
+type PaymentMethodRule struct {
+	// Tolerance é a tolerância percentual de diferença de valor dos pagamentos do meio
+	Tolerance *float64 `json:"tolerance,omitempty"`
+
+	// MaxDaysDiff limita, em dias corridos, a diferença entre a emissão do boleto e o pagamento; zero
+	// exige o mesmo dia
+	MaxDaysDiff *int `json:"max_days_diff,omitempty"`
+}
+
+// PaymentMethodRules indexa as regras de conciliação pelo meio de pagamento
+type PaymentMethodRules map[PaymentMethod]PaymentMethodRule
+
+// DefaultPaymentMethodRules retorna as regras padrão: o PIX liquida na hora, então concilia apenas no
+// mesmo dia e sem diferença de valor
+func DefaultPaymentMethodRules() PaymentMethodRules {
+	zeroTolerance, sameDay := 0.0, 0
+	return PaymentMethodRules{
+		PaymentMethodPix: {Tolerance: &zeroTolerance, MaxDaysDiff: &sameDay},
+	}
+}
+
+// Validate verifica os meios de pagamento e os parâmetros das regras
+func (r PaymentMethodRules) Validate() error {
+	for method, rule := range r {
+		if method == "" || !method.IsValid() {
+			return fmt.Errorf("meio de pagamento desconhecido: %q", method)
+		}
+		if rule.Tolerance != nil && (*rule.Tolerance < 0 || *rule.Tolerance > 100) {
+			return fmt.Errorf("tolerância do meio %s deve estar entre 0 e 100", method)
+		}
+		if rule.MaxDaysDiff != nil && *rule.MaxDaysDiff < 0 {
+			return fmt.Errorf("janela de datas do meio %s não pode ser negativa", method)
+		}
+	}
+	return nil
+}
+
+// For retorna a regra do meio de pagamento e se ele tem regra própria
+func (r PaymentMethodRules) For(method PaymentMethod) (PaymentMethodRule, bool) {
+	rule, found := r[method]
+	return rule, found
+}
+
+// WithinDays verifica se as datas estão a no máximo days dias corridos uma da outra, desconsiderando
+// o horário
+func WithinDays(a, b time.Time, days int) bool {
+	dayA := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	dayB := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+
+	diff := dayA.Sub(dayB)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= time.Duration(days)*24*time.Hour
+}
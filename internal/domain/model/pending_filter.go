@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// PendingFilter restringe os boletos e pagamentos pendentes carregados pela conciliação. Datas
+// zeradas não limitam o período, sem contas todas as contas são consideradas e valores zerados não
+// limitam a faixa de valores
+type PendingFilter struct {
+	StartDate    time.Time
+	EndDate      time.Time
+	BankAccounts []string
+	MinAmount    float64
+	MaxAmount    float64
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// PendingSnapshot registra as pendências (boletos não conciliados) ao final do job noturno de um dia
+// e a variação em relação ao dia anterior, formando a série exibida no dashboard
+type PendingSnapshot struct {
+	ID               string    `json:"id"`
+	Date             time.Time `json:"date"`
+	PendingCount     int       `json:"pending_count"`
+	PendingAmount    float64   `json:"pending_amount"`
+	ExitedCount      int       `json:"exited_count"`  // Boletos que saíram de pendente desde o dia anterior
+	EnteredCount     int       `json:"entered_count"` // Boletos que passaram a ficar pendentes desde o dia anterior
+	ReconciledInRun  int       `json:"reconciled_in_run"`
+	PendingBilletIDs []string  `json:"-"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// NewPendingSnapshot cria o retrato das pendências do dia a partir dos boletos não conciliados
+func NewPendingSnapshot(date time.Time, pending []*Billet) *PendingSnapshot {
+	snapshot := &PendingSnapshot{
+		ID:               generateUUID(),
+		Date:             ClosingDay(date),
+		PendingCount:     len(pending),
+		PendingBilletIDs: make([]string, 0, len(pending)),
+		CreatedAt:        time.Now(),
+	}
+
+	for _, billet := range pending {
+		snapshot.PendingAmount += billet.Amount
+		snapshot.PendingBilletIDs = append(snapshot.PendingBilletIDs, billet.ID)
+	}
+	sort.Strings(snapshot.PendingBilletIDs)
+
+	return snapshot
+}
+
+// CompareWith calcula quantos boletos saíram e quantos entraram nas pendências desde o retrato anterior.
+// Sem retrato anterior, todas as pendências contam como novas
+func (s *PendingSnapshot) CompareWith(previous *PendingSnapshot) {
+	if previous == nil {
+		s.ExitedCount, s.EnteredCount = 0, s.PendingCount
+		return
+	}
+
+	current := make(map[string]bool, len(s.PendingBilletIDs))
+	for _, id := range s.PendingBilletIDs {
+		current[id] = true
+	}
+
+	s.ExitedCount, s.EnteredCount = 0, 0
+	for _, id := range previous.PendingBilletIDs {
+		if current[id] {
+			delete(current, id)
+		} else {
+			s.ExitedCount++
+		}
+	}
+	s.EnteredCount = len(current)
+}
+
+// Summary descreve a variação das pendências no formato enviado no relatório noturno
+func (s *PendingSnapshot) Summary() string {
+	return fmt.Sprintf("%d boletos saíram de pendente, %d novos entraram (%d pendentes em %s)",
+		s.ExitedCount, s.EnteredCount, s.PendingCount, s.Date.Format("2006-01-02"))
+}
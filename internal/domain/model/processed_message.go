@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+)
+
+// ProcessedMessage registra uma mensagem de fila já processada por um consumidor. Como o broker entrega
+// cada mensagem ao menos uma vez, o registro permite descartar as reentregas da mesma mensagem
+type ProcessedMessage struct {
+	Consumer    string    `json:"consumer"`
+	MessageID   string    `json:"message_id"`
+	PaymentID   string    `json:"payment_id,omitempty"`
+	ProcessedAt time.Time `json:"processed_at"`
+}
+
+// NewProcessedMessage cria o registro do processamento da mensagem pelo consumidor
+func NewProcessedMessage(consumer, messageID, paymentID string) *ProcessedMessage {
+	return &ProcessedMessage{
+		Consumer:    consumer,
+		MessageID:   messageID,
+		PaymentID:   paymentID,
+		ProcessedAt: time.Now(),
+	}
+}
+
+// MessageOutcome representa o desfecho do consumo de uma mensagem
+type MessageOutcome string
+
+const (
+	// MessageProcessed indica que a mensagem foi processada pela primeira vez
+	MessageProcessed MessageOutcome = "processada"
+
+	// MessageDuplicate indica uma reentrega de mensagem já processada, descartada sem efeito
+	MessageDuplicate MessageOutcome = "duplicada"
+
+	// MessageRejected indica uma mensagem inválida ou em conflito, descartada sem efeito porque qualquer
+	// reentrega falharia do mesmo modo
+	MessageRejected MessageOutcome = "rejeitada"
+)
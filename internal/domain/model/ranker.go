@@ -0,0 +1,65 @@
+package model
+
+import (
+	"time"
+)
+
+// MatchFeatures representa as características de um par boleto/pagamento usadas para ranquear candidatos
+type MatchFeatures struct {
+	AmountDiffPercentage float64 `json:"amount_diff_percentage"`
+	DaysDiff             float64 `json:"days_diff"`
+	PartialReference     bool    `json:"partial_reference"` // Uma referência contém a outra
+}
+
+// RankerSample representa um match revisado manualmente, usado no treino do ranker
+type RankerSample struct {
+	Features MatchFeatures `json:"features"`
+	Approved bool          `json:"approved"`
+}
+
+// RankerModel representa os pesos da regressão logística treinada para um tenant
+type RankerModel struct {
+	TenantID               string    `json:"tenant_id"`
+	Enabled                bool      `json:"enabled"`
+	Bias                   float64   `json:"bias"`
+	AmountDiffWeight       float64   `json:"amount_diff_weight"`
+	DaysDiffWeight         float64   `json:"days_diff_weight"`
+	PartialReferenceWeight float64   `json:"partial_reference_weight"`
+	Samples                int       `json:"samples"`
+	TrainedAt              time.Time `json:"trained_at"`
+}
+
+// MatchReview representa a aprovação ou rejeição manual de um par boleto/pagamento,
+// com as características do par no momento da revisão
+type MatchReview struct {
+	ID            string        `json:"id"`
+	TenantID      string        `json:"tenant_id"`
+	BilletID      string        `json:"billet_id"`
+	TransactionID string        `json:"transaction_id"`
+	Approved      bool          `json:"approved"`
+	Reviewer      string        `json:"reviewer,omitempty"`
+	Features      MatchFeatures `json:"features"`
+	ReviewedAt    time.Time     `json:"reviewed_at"`
+}
+
+// NewMatchReview cria uma nova revisão manual de um par boleto/pagamento
+func NewMatchReview(tenantID, billetID, transactionID string, approved bool, reviewer string, features MatchFeatures) *MatchReview {
+	return &MatchReview{
+		ID:            generateUUID(),
+		TenantID:      tenantID,
+		BilletID:      billetID,
+		TransactionID: transactionID,
+		Approved:      approved,
+		Reviewer:      reviewer,
+		Features:      features,
+		ReviewedAt:    time.Now(),
+	}
+}
+
+// ToRankerSample converte a revisão em uma amostra de treino do ranker
+func (r *MatchReview) ToRankerSample() RankerSample {
+	return RankerSample{
+		Features: r.Features,
+		Approved: r.Approved,
+	}
+}
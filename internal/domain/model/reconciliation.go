@@ -1,6 +1,8 @@
 package model
 
 import (
+	"crypto/rand"
+	"fmt"
 	"time"
 )
 
@@ -11,14 +13,93 @@ const (
 	StatusSuccessful     ConciliationStatus = "conciliado_com_sucesso"
 	StatusDifferentValue ConciliationStatus = "valor_diferente"
 	StatusNotReconciled  ConciliationStatus = "nao_conciliado"
+	StatusAmbiguousRef   ConciliationStatus = "referencia_ambigua"
+
+	// StatusPartiallyReconciled indica um boleto quitado pela soma de vários pagamentos parciais
+	StatusPartiallyReconciled ConciliationStatus = "parcialmente_conciliado"
+
+	// StatusSuggested indica um pareamento de baixa confiança da estratégia conta/valor/data, que só passa
+	// a valer depois de aprovado por um analista
+	StatusSuggested ConciliationStatus = "sugerido"
+
+	// StatusAwaitingApproval indica um pareamento automático com diferença de valor, que só passa a valer
+	// como valor_diferente depois de aprovado por um analista
+	StatusAwaitingApproval ConciliationStatus = "aguardando_aprovacao"
+
+	// StatusPaidWithInterest indica um boleto pago depois do vencimento pelo valor acrescido da multa e dos
+	// juros de mora, calculados para a data do pagamento
+	StatusPaidWithInterest ConciliationStatus = "conciliado_com_juros"
 )
 
+// AmountMatchStatus define o status de um pareamento pela diferença contra o valor esperado do boleto:
+// sem diferença, conciliado com sucesso ou, quando o valor esperado inclui encargos de atraso, conciliado
+// com juros
+func AmountMatchStatus(amountDiff float64, lateCharges bool) ConciliationStatus {
+	switch {
+	case amountDiff != 0:
+		return StatusDifferentValue
+	case lateCharges:
+		return StatusPaidWithInterest
+	}
+	return StatusSuccessful
+}
+
+// IsMatched indica se o status representa um boleto efetivamente pareado com um ou mais pagamentos
+func (s ConciliationStatus) IsMatched() bool {
+	return s == StatusSuccessful || s == StatusDifferentValue || s == StatusPartiallyReconciled ||
+		s == StatusPaidWithInterest
+}
+
+// IsPendingApproval indica se o status aguarda a decisão de um analista: pareamentos sugeridos e
+// pareamentos automáticos com diferença de valor
+func (s ConciliationStatus) IsPendingApproval() bool {
+	return s == StatusSuggested || s == StatusAwaitingApproval
+}
+
+// IsReserved indica se o status ocupa o boleto e o pagamento: pareados ou com pareamento à espera de
+// aprovação. Boletos e pagamentos reservados ficam fora das próximas execuções e dos pareamentos manuais
+func (s ConciliationStatus) IsReserved() bool {
+	return s.IsMatched() || s.IsPendingApproval()
+}
+
 // ConciliationStrategy define as estratégias possíveis de conciliação
 type ConciliationStrategy string
 
 const (
 	StrategyReferenceID       ConciliationStrategy = "reference_id"
 	StrategyAccountAmountDate ConciliationStrategy = "conta_valor_data"
+	StrategyInstallment       ConciliationStrategy = "parcela"
+
+	// StrategySplit concilia um pagamento com vários boletos do mesmo pagador; a sobra vira crédito não aplicado
+	StrategySplit ConciliationStrategy = "divisao_pagamento"
+
+	// StrategyUnappliedCredit concilia um boleto com o crédito não aplicado do pagador, aplicado manualmente
+	// ou pela estratégia opcional de créditos
+	StrategyUnappliedCredit ConciliationStrategy = "credito_nao_aplicado"
+
+	// StrategyAggregate concilia um pagamento com o grupo de boletos em aberto da conta cuja soma
+	// corresponde ao valor pago
+	StrategyAggregate ConciliationStrategy = "agrupamento_boletos"
+
+	// StrategyPartialPayment concilia um boleto com vários pagamentos da mesma conta e referência, somados
+	// até cobrir o valor do boleto
+	StrategyPartialPayment ConciliationStrategy = "pagamento_parcial"
+
+	// StrategyBarcode concilia o boleto com o pagamento do mesmo código de barras (ou linha digitável),
+	// devolvido pelo banco no arquivo de liquidação
+	StrategyBarcode ConciliationStrategy = "codigo_barras"
+
+	// StrategyPayerDocument concilia o pagamento sem referência com o boleto do mesmo pagador (CPF ou
+	// CNPJ) e valor dentro da tolerância
+	StrategyPayerDocument ConciliationStrategy = "documento_pagador"
+
+	// StrategyFuzzyName concilia o pagamento com o boleto da conta cujo nome do pagador mais se aproxima
+	// da descrição do pagamento, acima da similaridade mínima e com valor dentro da tolerância
+	StrategyFuzzyName ConciliationStrategy = "nome_aproximado"
+
+	// StrategyManual registra o vínculo entre boleto e pagamento feito por um operador, nos casos que as
+	// estratégias automáticas não resolvem; nunca compõe a ordem de uma execução
+	StrategyManual ConciliationStrategy = "manual"
 )
 
 // Reconciliation representa o resultado da conciliação entre boleto e pagamento
@@ -32,10 +113,27 @@ type Reconciliation struct {
 	AmountDiff           float64              `json:"amount_diff"`
 	ReferenceID          *string              `json:"reference_id,omitempty"`
 
+	// TransactionIDs lista os pagamentos que, somados, quitaram o boleto nas conciliações de pagamentos
+	// parciais; TransactionID é o primeiro deles
+	TransactionIDs []string `json:"transaction_ids,omitempty"`
+
+	// RunID identifica a execução que gravou o registro; vazio nas conciliações manuais e rematches
+	RunID *string `json:"run_id,omitempty"`
+
+	// GroupID reúne as conciliações dos boletos quitados juntos por um único pagamento
+	GroupID *string `json:"group_id,omitempty"`
+
+	// Confidence é a confiança, de 0 a 1, do pareamento escolhido pela estratégia conta/valor/data
+	Confidence *float64 `json:"confidence,omitempty"`
+
+	// EngineVersion é a versão do motor de matching que gravou o registro; vazia nas conciliações manuais
+	EngineVersion string `json:"engine_version,omitempty"`
+
 	// Campos adicionais
-	ReconciliationDate time.Time `json:"reconciliation_date"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
+	ReconciliationDate     time.Time `json:"reconciliation_date"`
+	TimeToReconcileSeconds *int64    `json:"time_to_reconcile_seconds,omitempty"`
+	CreatedAt              time.Time `json:"created_at"`
+	UpdatedAt              time.Time `json:"updated_at"`
 }
 
 // NewReconciliation cria uma nova instância de Reconciliation
@@ -65,17 +163,112 @@ func NewReconciliation(
 	}
 }
 
-// generateUUID é uma função auxiliar para gerar um UUID
-// Em uma implementação real, você usaria uma biblioteca para gerar UUIDs
+// NewNonReconciled cria o registro de uma tentativa frustrada de conciliação do boleto na execução
+func NewNonReconciled(billet Billet, runID string) *Reconciliation {
+	reconciliation := NewReconciliation(billet.ID, nil, billet.BankAccount, StatusNotReconciled, "", 0, billet.ReferenceID)
+	reconciliation.RunID = &runID
+	return reconciliation
+}
+
+// NewAmbiguousReference cria o registro referencia_ambigua de um boleto que a execução runID deixou
+// para revisão manual por compartilhar o reference_id com outros boletos ou pagamentos
+func NewAmbiguousReference(billet Billet, runID string) *Reconciliation {
+	reconciliation := NewReconciliation(billet.ID, nil, billet.BankAccount, StatusAmbiguousRef, StrategyReferenceID, 0, billet.ReferenceID)
+	reconciliation.RunID = &runID
+	return reconciliation
+}
+
+// SetTimeToReconcile registra o tempo decorrido entre a data do pagamento e a data da conciliação
+func (r *Reconciliation) SetTimeToReconcile(paymentDate time.Time) {
+	if paymentDate.IsZero() {
+		return
+	}
+
+	seconds := int64(r.ReconciliationDate.Sub(paymentDate).Seconds())
+	if seconds < 0 {
+		seconds = 0
+	}
+	r.TimeToReconcileSeconds = &seconds
+}
+
+// NewReconciliationGroupID gera o identificador de um grupo de conciliações
+func NewReconciliationGroupID() string {
+	return generateUUID()
+}
+
+// generateUUID é uma função auxiliar para gerar um UUID (versão 4)
+// O identificador precisa ser único mesmo quando várias conciliações são criadas no mesmo segundo
 func generateUUID() string {
-	// Implementação simplificada para exemplo
-	return "rec-" + time.Now().Format("20060102150405")
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// Fallback improvável: identificador baseado no relógio
+		return "rec-" + time.Now().Format("20060102150405.000000000")
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
 }
 
 // Definindo o modelo para resposta de reconciliação
 type ReconciliationResult struct {
-	ReconciledBillets    []ReconciledBillet `json:"boletos_conciliados"`
-	NonReconciledBillets []Billet           `json:"boletos_nao_conciliados"`
+	ReconciledBillets    []ReconciledBillet   `json:"boletos_conciliados"`
+	NonReconciledBillets []Billet             `json:"boletos_nao_conciliados"`
+	AmbiguousReferences  []AmbiguousReference `json:"referencias_ambiguas,omitempty"`
+	Suggestions          []BilletSuggestions  `json:"sugestoes_correcao,omitempty"`
+	ExcludedPayments     []string             `json:"pagamentos_excluidos,omitempty"`
+	HeldPayments         []string             `json:"pagamentos_suspeitos,omitempty"`
+	IgnoredPayments      []string             `json:"pagamentos_ignorados_valor_minimo,omitempty"`
+
+	// UnappliedCredits são as sobras dos pagamentos divididos entre boletos do mesmo pagador
+	UnappliedCredits []UnappliedCredit `json:"creditos_nao_aplicados,omitempty"`
+
+	// CreditApplications são os créditos não aplicados usados pela estratégia de créditos para quitar boletos
+	CreditApplications []CreditApplication `json:"creditos_aplicados,omitempty"`
+
+	// InactiveAccounts relata os boletos e pagamentos de contas inativas deixados fora da execução
+	InactiveAccounts []InactiveAccountExclusion `json:"exclusoes_contas_inativas,omitempty"`
+
+	// RunID identifica a execução que produziu o resultado; Replayed indica que o resultado é o de
+	// uma execução anterior com os mesmos parâmetros, devolvido sem conciliar novamente
+	RunID    string `json:"run_id,omitempty"`
+	Replayed bool   `json:"reaproveitado,omitempty"`
+
+	// DryRun indica que o resultado é de uma simulação: nada foi gravado e não há execução registrada
+	DryRun bool `json:"simulacao,omitempty"`
+
+	// Tolerance é a tolerância percentual padrão da execução que produziu o resultado
+	Tolerance float64 `json:"tolerance,omitempty"`
+
+	// Metrics são as medições da conciliação, gravadas na execução e não no resultado
+	Metrics *RunMetrics `json:"-"`
+}
+
+// Merge acumula no resultado os itens do resultado de outra execução (ex.: outra sub-janela de uma
+// execução particionada). A identificação da execução não é acumulada
+func (r *ReconciliationResult) Merge(other *ReconciliationResult) {
+	if other == nil {
+		return
+	}
+
+	r.ReconciledBillets = append(r.ReconciledBillets, other.ReconciledBillets...)
+	r.NonReconciledBillets = append(r.NonReconciledBillets, other.NonReconciledBillets...)
+	r.AmbiguousReferences = append(r.AmbiguousReferences, other.AmbiguousReferences...)
+	r.Suggestions = append(r.Suggestions, other.Suggestions...)
+	r.ExcludedPayments = append(r.ExcludedPayments, other.ExcludedPayments...)
+	r.HeldPayments = append(r.HeldPayments, other.HeldPayments...)
+	r.IgnoredPayments = append(r.IgnoredPayments, other.IgnoredPayments...)
+	r.UnappliedCredits = append(r.UnappliedCredits, other.UnappliedCredits...)
+	r.CreditApplications = append(r.CreditApplications, other.CreditApplications...)
+	r.InactiveAccounts = append(r.InactiveAccounts, other.InactiveAccounts...)
+
+	if other.Metrics != nil {
+		if r.Metrics == nil {
+			r.Metrics = NewRunMetrics()
+		}
+		r.Metrics.Merge(other.Metrics)
+	}
 }
 
 // ReconciledBillet representa um boleto que foi conciliado com um pagamento
@@ -87,4 +280,28 @@ type ReconciledBillet struct {
 	ConciliationStrategy ConciliationStrategy `json:"conciliation_strategy"`
 	ReferenceID          *string              `json:"reference_id,omitempty"`
 	AmountDiff           float64              `json:"amount_diff"`
+	PaymentDate          time.Time            `json:"payment_date"`
+
+	// TransactionIDs lista os pagamentos parciais somados para quitar o boleto, quando mais de um
+	TransactionIDs []string `json:"transaction_ids,omitempty"`
+
+	// GroupID identifica o grupo de boletos quitados juntos pelo mesmo pagamento
+	GroupID string `json:"group_id,omitempty"`
+
+	// PaidAmount é o valor pago registrado como valor do título em boletos de valor aberto
+	PaidAmount *float64 `json:"paid_amount,omitempty"`
+
+	// Confidence é a confiança, de 0 a 1, do pareamento escolhido pela estratégia conta/valor/data entre
+	// os candidatos da conta, ou a similaridade entre nome e descrição na estratégia de nome aproximado.
+	// As demais estratégias pareiam por critérios exatos e não a informam
+	Confidence *float64 `json:"confidence,omitempty"`
+}
+
+// AmbiguousReference agrupa boletos e pagamentos que compartilham o mesmo reference_id
+// e que não puderam ser desempatados automaticamente, ficando pendentes de revisão
+type AmbiguousReference struct {
+	ReferenceID        string             `json:"reference_id"`
+	ConciliationStatus ConciliationStatus `json:"conciliation_status"`
+	BilletIDs          []string           `json:"billet_ids"`
+	TransactionIDs     []string           `json:"transaction_ids"`
 }
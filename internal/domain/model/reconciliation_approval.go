@@ -0,0 +1,85 @@
+package model
+
+import (
+	"time"
+)
+
+// ApprovalDecision define as decisões possíveis sobre um pareamento que aguarda aprovação
+type ApprovalDecision string
+
+const (
+	ApprovalApproved ApprovalDecision = "aprovada"
+	ApprovalRejected ApprovalDecision = "rejeitada"
+)
+
+// ReconciliationApproval registra, para auditoria, a decisão de um analista sobre um pareamento que
+// aguardava aprovação: quem decidiu, quando e com qual motivo. A aprovação leva a conciliação ao novo
+// status; a rejeição remove a conciliação e devolve o boleto e o pagamento à situação inicial
+type ReconciliationApproval struct {
+	ID               string             `json:"id"`
+	ReconciliationID string             `json:"reconciliation_id"`
+	BilletID         string             `json:"billet_id"`
+	TransactionID    *string            `json:"transaction_id,omitempty"`
+	BankAccount      string             `json:"bank_account"`
+	Decision         ApprovalDecision   `json:"decision"`
+	PreviousStatus   ConciliationStatus `json:"previous_status"`
+	NewStatus        ConciliationStatus `json:"new_status,omitempty"`
+	AmountDiff       float64            `json:"amount_diff"`
+	Confidence       *float64           `json:"confidence,omitempty"`
+	Approver         string             `json:"approver"`
+	Reason           string             `json:"reason,omitempty"`
+	DecidedAt        time.Time          `json:"decided_at"`
+}
+
+// NewReconciliationApproval cria o registro da aprovação do pareamento, que passa ao status informado
+func NewReconciliationApproval(reconciliation *Reconciliation, newStatus ConciliationStatus, approver, reason string) *ReconciliationApproval {
+	approval := newReconciliationDecision(reconciliation, ApprovalApproved, approver, reason)
+	approval.NewStatus = newStatus
+	return approval
+}
+
+// NewReconciliationRejection cria o registro da rejeição do pareamento
+func NewReconciliationRejection(reconciliation *Reconciliation, approver, reason string) *ReconciliationApproval {
+	return newReconciliationDecision(reconciliation, ApprovalRejected, approver, reason)
+}
+
+func newReconciliationDecision(reconciliation *Reconciliation, decision ApprovalDecision, approver, reason string) *ReconciliationApproval {
+	return &ReconciliationApproval{
+		ID:               generateUUID(),
+		ReconciliationID: reconciliation.ID,
+		BilletID:         reconciliation.BilletID,
+		TransactionID:    reconciliation.TransactionID,
+		BankAccount:      reconciliation.BankAccount,
+		Decision:         decision,
+		PreviousStatus:   reconciliation.ConciliationStatus,
+		AmountDiff:       reconciliation.AmountDiff,
+		Confidence:       reconciliation.Confidence,
+		Approver:         approver,
+		Reason:           reason,
+		DecidedAt:        time.Now(),
+	}
+}
+
+// StatusChange retorna a mudança de status aplicada pela aprovação, registrada também no histórico de
+// status da conciliação
+func (a *ReconciliationApproval) StatusChange() *ReconciliationStatusChange {
+	return &ReconciliationStatusChange{
+		ID:                 generateUUID(),
+		ReconciliationID:   a.ReconciliationID,
+		BilletID:           a.BilletID,
+		BankAccount:        a.BankAccount,
+		PreviousStatus:     a.PreviousStatus,
+		NewStatus:          a.NewStatus,
+		PreviousAmountDiff: a.AmountDiff,
+		NewAmountDiff:      a.AmountDiff,
+		Reason:             a.Reason,
+		ChangedBy:          a.Approver,
+		ChangedAt:          a.DecidedAt,
+	}
+}
+
+// Apply aplica a aprovação à conciliação
+func (a *ReconciliationApproval) Apply(reconciliation *Reconciliation) {
+	reconciliation.ConciliationStatus = a.NewStatus
+	reconciliation.UpdatedAt = a.DecidedAt
+}
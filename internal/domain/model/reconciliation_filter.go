@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+)
+
+// ReconciliationDateField indica qual data das conciliações o filtro de período aplica:
+// a data do pagamento (regime de caixa), a data da conciliação ou a data de emissão do boleto (competência)
+type ReconciliationDateField string
+
+const (
+	DateFieldPayment        ReconciliationDateField = "payment"
+	DateFieldReconciliation ReconciliationDateField = "reconciliation"
+	DateFieldIssuance       ReconciliationDateField = "issuance"
+)
+
+// IsValid verifica se o campo de data é suportado
+func (f ReconciliationDateField) IsValid() bool {
+	switch f {
+	case DateFieldPayment, DateFieldReconciliation, DateFieldIssuance:
+		return true
+	}
+	return false
+}
+
+// OrDefault retorna o campo de data, ou a data da conciliação quando não informado
+func (f ReconciliationDateField) OrDefault() ReconciliationDateField {
+	if f == "" {
+		return DateFieldReconciliation
+	}
+	return f
+}
+
+// ReconciliationFilter representa os filtros da listagem de conciliações
+type ReconciliationFilter struct {
+	BankAccount string
+	Status      ConciliationStatus
+	Strategy    ConciliationStrategy
+	DateField   ReconciliationDateField
+	StartDate   *time.Time
+	EndDate     *time.Time
+	Limit       int
+	Offset      int
+
+	// RunID restringe a listagem às conciliações gravadas pela execução
+	RunID string
+
+	// EngineVersion restringe a listagem às conciliações gravadas pela versão do motor de matching
+	EngineVersion string
+}
@@ -0,0 +1,182 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// ReconciliationRunStatus define os possíveis status de uma execução de conciliação
+type ReconciliationRunStatus string
+
+const (
+	RunInProgress ReconciliationRunStatus = "em_andamento"
+	RunCompleted  ReconciliationRunStatus = "concluida"
+	RunFailed     ReconciliationRunStatus = "falhou"
+)
+
+// ReconciliationRun registra uma execução de conciliação. Execuções com janela de datas guardam o
+// hash dos parâmetros, que identifica chamadas repetidas para a mesma janela, contas e tenant
+type ReconciliationRun struct {
+	ID         string                  `json:"id"`
+	ParamsHash string                  `json:"params_hash,omitempty"`
+	Tenant     string                  `json:"tenant,omitempty"`
+	Status     ReconciliationRunStatus `json:"status"`
+	Result     *ReconciliationResult   `json:"result,omitempty"`
+	StartedAt  time.Time               `json:"started_at"`
+	FinishedAt *time.Time              `json:"finished_at,omitempty"`
+
+	// Tolerance é a tolerância percentual aplicada às estratégias que comparam valores e não têm
+	// tolerância própria na execução
+	Tolerance float64 `json:"tolerance"`
+
+	// EngineVersion é a versão do motor de matching que fez a execução
+	EngineVersion string `json:"engine_version,omitempty"`
+
+	// Metrics são as medições da execução; nulas nas execuções em andamento
+	Metrics *RunMetrics `json:"metrics,omitempty"`
+
+	// Params são os parâmetros informados na execução e Totals o resumo do resultado, nulo nas
+	// execuções em andamento
+	Params *RunParams `json:"params,omitempty"`
+	Totals *RunTotals `json:"totals,omitempty"`
+}
+
+// RunParams registra os parâmetros informados em uma execução de conciliação
+type RunParams struct {
+	StartDate         *time.Time         `json:"start_date,omitempty"`
+	EndDate           *time.Time         `json:"end_date,omitempty"`
+	BankAccounts      []string           `json:"bank_accounts,omitempty"`
+	BilletIDs         []string           `json:"billet_ids,omitempty"`
+	TransactionIDs    []string           `json:"transaction_ids,omitempty"`
+	UseCredits        bool               `json:"use_credits,omitempty"`
+	Strategies        []StrategyConfig   `json:"strategies,omitempty"`
+	Tolerance         *float64           `json:"tolerance,omitempty"`
+	AccountTolerances map[string]float64 `json:"account_tolerances,omitempty"`
+	MaxDaysDiff       *int               `json:"max_days_diff,omitempty"`
+}
+
+// RunTotals resume o resultado de uma execução para a listagem das execuções, sem os itens
+type RunTotals struct {
+	Reconciled          int `json:"reconciled"`
+	DifferentValue      int `json:"different_value"`
+	NonReconciled       int `json:"non_reconciled"`
+	AmbiguousReferences int `json:"ambiguous_references"`
+	Suggestions         int `json:"suggestions"`
+	ExcludedPayments    int `json:"excluded_payments"`
+
+	// PendingApproval conta os pareamentos sugeridos e os com diferença de valor, que aguardam aprovação
+	PendingApproval int `json:"pending_approval"`
+}
+
+// NewRunTotals resume o resultado da execução. Reconciled conta os boletos pareados, inclusive os
+// com diferença de valor, que também são contados em DifferentValue
+func NewRunTotals(result *ReconciliationResult) *RunTotals {
+	totals := &RunTotals{}
+	if result == nil {
+		return totals
+	}
+
+	for _, billet := range result.ReconciledBillets {
+		if billet.ConciliationStatus.IsMatched() {
+			totals.Reconciled++
+		}
+		if billet.ConciliationStatus == StatusDifferentValue {
+			totals.DifferentValue++
+		}
+		if billet.ConciliationStatus.IsPendingApproval() {
+			totals.PendingApproval++
+		}
+	}
+
+	totals.NonReconciled = len(result.NonReconciledBillets)
+	totals.AmbiguousReferences = len(result.AmbiguousReferences)
+	totals.Suggestions = len(result.Suggestions)
+	totals.ExcludedPayments = len(result.ExcludedPayments)
+
+	return totals
+}
+
+// RunSummary resume uma execução concluída para os eventos e as notificações de resumo. MatchRate é o
+// percentual dos boletos da execução que foram conciliados, sem contar os que aguardam aprovação
+type RunSummary struct {
+	RunID      string    `json:"run_id"`
+	Totals     RunTotals `json:"totals"`
+	MatchRate  float64   `json:"match_rate"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// ReconciliationRunFilter representa os filtros da listagem de execuções. O período é aplicado sobre
+// o início da execução
+type ReconciliationRunFilter struct {
+	Tenants       []string
+	Status        ReconciliationRunStatus
+	EngineVersion string
+	StartDate     *time.Time
+	EndDate       *time.Time
+	Limit         int
+	Offset        int
+}
+
+// NewReconciliationRun cria uma nova execução em andamento
+func NewReconciliationRun(paramsHash, tenant string) *ReconciliationRun {
+	return &ReconciliationRun{
+		ID:         generateUUID(),
+		ParamsHash: paramsHash,
+		Tenant:     tenant,
+		Status:     RunInProgress,
+		StartedAt:  time.Now(),
+	}
+}
+
+// IsCompleted indica se a execução terminou e tem o resultado registrado
+func (r *ReconciliationRun) IsCompleted() bool {
+	return r.Status == RunCompleted
+}
+
+// Complete registra o resultado, as medições e o término da execução
+func (r *ReconciliationRun) Complete(result *ReconciliationResult) {
+	r.finish(RunCompleted, result)
+}
+
+// Fail registra o término da execução com falha e o resultado parcial, das contas que chegaram a ser
+// gravadas. O hash dos parâmetros é descartado para que a execução possa ser repetida
+func (r *ReconciliationRun) Fail(result *ReconciliationResult) {
+	r.finish(RunFailed, result)
+	r.ParamsHash = ""
+}
+
+// finish registra o status final, o resultado, as medições e o término da execução
+func (r *ReconciliationRun) finish(status ReconciliationRunStatus, result *ReconciliationResult) {
+	now := time.Now()
+	r.Status = status
+	r.Result = result
+
+	r.Metrics = NewRunMetrics()
+	if result != nil {
+		result.Tolerance = r.Tolerance
+		r.Metrics.Merge(result.Metrics)
+	}
+	r.Metrics.DurationMs = now.Sub(r.StartedAt).Milliseconds()
+	r.Totals = NewRunTotals(result)
+
+	r.FinishedAt = &now
+}
+
+// Summary retorna o resumo da execução concluída; nil nas execuções em andamento
+func (r *ReconciliationRun) Summary() *RunSummary {
+	if r.Totals == nil || r.FinishedAt == nil {
+		return nil
+	}
+
+	summary := &RunSummary{
+		RunID:      r.ID,
+		Totals:     *r.Totals,
+		StartedAt:  r.StartedAt,
+		FinishedAt: *r.FinishedAt,
+	}
+	if billets := r.Totals.Reconciled + r.Totals.PendingApproval + r.Totals.NonReconciled; billets > 0 {
+		summary.MatchRate = math.Round(float64(r.Totals.Reconciled)/float64(billets)*10000) / 100
+	}
+	return summary
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+)
+
+// ReconciliationStatusChange registra a mudança de status de uma conciliação já persistida,
+// preservando o histórico de reavaliações
+type ReconciliationStatusChange struct {
+	ID                 string             `json:"id"`
+	ReconciliationID   string             `json:"reconciliation_id"`
+	BilletID           string             `json:"billet_id"`
+	BankAccount        string             `json:"bank_account"`
+	PreviousStatus     ConciliationStatus `json:"previous_status"`
+	NewStatus          ConciliationStatus `json:"new_status"`
+	PreviousAmountDiff float64            `json:"previous_amount_diff"`
+	NewAmountDiff      float64            `json:"new_amount_diff"`
+	Reason             string             `json:"reason"`
+	ChangedBy          string             `json:"changed_by,omitempty"`
+	ChangedAt          time.Time          `json:"changed_at"`
+}
+
+// NewReconciliationStatusChange cria o registro da mudança de status e de diferença de valor da conciliação
+func NewReconciliationStatusChange(reconciliation *Reconciliation, newStatus ConciliationStatus, newAmountDiff float64, reason, changedBy string) *ReconciliationStatusChange {
+	return &ReconciliationStatusChange{
+		ID:                 generateUUID(),
+		ReconciliationID:   reconciliation.ID,
+		BilletID:           reconciliation.BilletID,
+		BankAccount:        reconciliation.BankAccount,
+		PreviousStatus:     reconciliation.ConciliationStatus,
+		NewStatus:          newStatus,
+		PreviousAmountDiff: reconciliation.AmountDiff,
+		NewAmountDiff:      newAmountDiff,
+		Reason:             reason,
+		ChangedBy:          changedBy,
+		ChangedAt:          time.Now(),
+	}
+}
+
+// Apply aplica a mudança à conciliação
+func (c *ReconciliationStatusChange) Apply(reconciliation *Reconciliation) {
+	reconciliation.ConciliationStatus = c.NewStatus
+	reconciliation.AmountDiff = c.NewAmountDiff
+	reconciliation.UpdatedAt = c.ChangedAt
+}
+
+// ReevaluationResult representa o resultado da reavaliação das conciliações de um boleto
+type ReevaluationResult struct {
+	BilletID string                        `json:"billet_id"`
+	Changes  []*ReconciliationStatusChange `json:"changes"`
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+)
+
+// ReconciliationUndo registra, para auditoria, a conciliação desfeita por um operador: o vínculo
+// removido entre boleto e pagamento, quem o desfez e o motivo
+type ReconciliationUndo struct {
+	ID                   string               `json:"id"`
+	ReconciliationID     string               `json:"reconciliation_id"`
+	BilletID             string               `json:"billet_id"`
+	TransactionID        *string              `json:"transaction_id,omitempty"`
+	TransactionIDs       []string             `json:"transaction_ids,omitempty"`
+	BankAccount          string               `json:"bank_account"`
+	ConciliationStatus   ConciliationStatus   `json:"conciliation_status"`
+	ConciliationStrategy ConciliationStrategy `json:"conciliation_strategy"`
+	AmountDiff           float64              `json:"amount_diff"`
+	GroupID              *string              `json:"group_id,omitempty"`
+	Reason               string               `json:"reason"`
+	UndoneBy             string               `json:"undone_by,omitempty"`
+	UndoneAt             time.Time            `json:"undone_at"`
+}
+
+// NewReconciliationUndo cria o registro da conciliação desfeita, copiando o vínculo que será removido
+func NewReconciliationUndo(reconciliation *Reconciliation, reason, undoneBy string) *ReconciliationUndo {
+	return &ReconciliationUndo{
+		ID:                   generateUUID(),
+		ReconciliationID:     reconciliation.ID,
+		BilletID:             reconciliation.BilletID,
+		TransactionID:        reconciliation.TransactionID,
+		TransactionIDs:       reconciliation.TransactionIDs,
+		BankAccount:          reconciliation.BankAccount,
+		ConciliationStatus:   reconciliation.ConciliationStatus,
+		ConciliationStrategy: reconciliation.ConciliationStrategy,
+		AmountDiff:           reconciliation.AmountDiff,
+		GroupID:              reconciliation.GroupID,
+		Reason:               reason,
+		UndoneBy:             undoneBy,
+		UndoneAt:             time.Now(),
+	}
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+// DailyStatisticsFilter representa os filtros das estatísticas diárias de conciliação por conta
+type DailyStatisticsFilter struct {
+	BankAccount string
+	DateField   ReconciliationDateField
+	StartDate   *time.Time
+	EndDate     *time.Time
+}
+
+// DailyReconciliationStatistics representa os totais de conciliação de uma conta em um dia
+type DailyReconciliationStatistics struct {
+	Date           time.Time `json:"date"`
+	BankAccount    string    `json:"bank_account"`
+	Total          int64     `json:"total"`
+	Successful     int64     `json:"successful"`
+	DifferentValue int64     `json:"different_value"`
+	NotReconciled  int64     `json:"not_reconciled"`
+	AmbiguousRef   int64     `json:"ambiguous_reference"`
+	BilletAmount   float64   `json:"billet_amount"`
+	PaidAmount     float64   `json:"paid_amount"`
+	AmountDiff     float64   `json:"amount_diff"`
+}
+
+// Campos disponíveis para as colunas do relatório regulatório
+const (
+	ReportFieldDate           = "date"
+	ReportFieldBankAccount    = "bank_account"
+	ReportFieldTotal          = "total"
+	ReportFieldSuccessful     = "successful"
+	ReportFieldDifferentValue = "different_value"
+	ReportFieldNotReconciled  = "not_reconciled"
+	ReportFieldAmbiguousRef   = "ambiguous_reference"
+	ReportFieldBilletAmount   = "billet_amount"
+	ReportFieldPaidAmount     = "paid_amount"
+	ReportFieldAmountDiff     = "amount_diff"
+	ReportFieldSuccessRate    = "success_rate"
+)
+
+// ReportColumn representa uma coluna do relatório regulatório: o cabeçalho exigido pela auditoria
+// e o campo das estatísticas diárias exibido nela
+type ReportColumn struct {
+	Header string `json:"header"`
+	Field  string `json:"field"`
+}
+
+// DefaultRegulatoryReportColumns define o layout padrão do relatório de conciliação diária por conta
+var DefaultRegulatoryReportColumns = []ReportColumn{
+	{Header: "DATA_REFERENCIA", Field: ReportFieldDate},
+	{Header: "CONTA", Field: ReportFieldBankAccount},
+	{Header: "QTD_TOTAL", Field: ReportFieldTotal},
+	{Header: "QTD_CONCILIADOS", Field: ReportFieldSuccessful},
+	{Header: "QTD_VALOR_DIVERGENTE", Field: ReportFieldDifferentValue},
+	{Header: "QTD_NAO_CONCILIADOS", Field: ReportFieldNotReconciled},
+	{Header: "QTD_REFERENCIA_AMBIGUA", Field: ReportFieldAmbiguousRef},
+	{Header: "VLR_BOLETOS", Field: ReportFieldBilletAmount},
+	{Header: "VLR_PAGO", Field: ReportFieldPaidAmount},
+	{Header: "VLR_DIFERENCA", Field: ReportFieldAmountDiff},
+	{Header: "PCT_CONCILIADO", Field: ReportFieldSuccessRate},
+}
+
+// IsValidReportField verifica se o campo pode ser usado numa coluna do relatório
+func IsValidReportField(field string) bool {
+	_, ok := (&DailyReconciliationStatistics{}).Field(field)
+	return ok
+}
+
+// SuccessRate retorna o percentual de conciliações com sucesso no dia
+func (s *DailyReconciliationStatistics) SuccessRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Successful) / float64(s.Total) * 100
+}
+
+// Field retorna o valor formatado de um campo para o relatório: datas em AAAA-MM-DD e valores
+// com duas casas decimais separadas por ponto. Retorna false quando o campo não existe
+func (s *DailyReconciliationStatistics) Field(field string) (string, bool) {
+	switch field {
+	case ReportFieldDate:
+		return s.Date.Format("2006-01-02"), true
+	case ReportFieldBankAccount:
+		return s.BankAccount, true
+	case ReportFieldTotal:
+		return strconv.FormatInt(s.Total, 10), true
+	case ReportFieldSuccessful:
+		return strconv.FormatInt(s.Successful, 10), true
+	case ReportFieldDifferentValue:
+		return strconv.FormatInt(s.DifferentValue, 10), true
+	case ReportFieldNotReconciled:
+		return strconv.FormatInt(s.NotReconciled, 10), true
+	case ReportFieldAmbiguousRef:
+		return strconv.FormatInt(s.AmbiguousRef, 10), true
+	case ReportFieldBilletAmount:
+		return strconv.FormatFloat(s.BilletAmount, 'f', 2, 64), true
+	case ReportFieldPaidAmount:
+		return strconv.FormatFloat(s.PaidAmount, 'f', 2, 64), true
+	case ReportFieldAmountDiff:
+		return strconv.FormatFloat(s.AmountDiff, 'f', 2, 64), true
+	case ReportFieldSuccessRate:
+		return strconv.FormatFloat(s.SuccessRate(), 'f', 2, 64), true
+	default:
+		return "", false
+	}
+}
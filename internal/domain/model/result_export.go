@@ -0,0 +1,96 @@
+package model
+
+import (
+	"time"
+)
+
+// ExportStatus define a situação do envio de um arquivo de resultado
+type ExportStatus string
+
+const (
+	ExportPending ExportStatus = "pendente"
+	ExportSent    ExportStatus = "enviado"
+	ExportFailed  ExportStatus = "falha"
+)
+
+// ResultExport registra o arquivo de resultado de uma execução de conciliação enviado ao ERP. O conteúdo
+// gerado é guardado para que o reenvio manual transmita exatamente o mesmo arquivo
+type ResultExport struct {
+	ID            string       `json:"id"`
+	RunID         string       `json:"run_id"`
+	Format        string       `json:"format"`
+	FileKey       string       `json:"file_key,omitempty"`
+	Sequence      int          `json:"sequence"`
+	FileName      string       `json:"file_name"`
+	Destination   string       `json:"destination"`
+	Status        ExportStatus `json:"status"`
+	Attempts      int          `json:"attempts"`
+	LastError     string       `json:"last_error,omitempty"`
+	LastAttemptBy string       `json:"last_attempt_by,omitempty"`
+	Content       []byte       `json:"-"`
+	CreatedAt     time.Time    `json:"created_at"`
+	LastAttemptAt *time.Time   `json:"last_attempt_at,omitempty"`
+	SentAt        *time.Time   `json:"sent_at,omitempty"`
+}
+
+// NewResultExport cria o registro de um arquivo de resultado ainda não enviado. A chave identifica o
+// arquivo entre os gerados pela execução (ex.: o convênio) e a sequência é a numeração do arquivo no layout
+func NewResultExport(runID, format, fileKey string, sequence int, fileName, destination string, content []byte) *ResultExport {
+	return &ResultExport{
+		ID:          generateUUID(),
+		RunID:       runID,
+		Format:      format,
+		FileKey:     fileKey,
+		Sequence:    sequence,
+		FileName:    fileName,
+		Destination: destination,
+		Status:      ExportPending,
+		Content:     content,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// RecordAttempt registra uma tentativa de envio feita pelo usuário (vazio no envio automático) e o seu resultado
+func (e *ResultExport) RecordAttempt(actor string, err error) {
+	now := time.Now()
+	e.Attempts++
+	e.LastAttemptAt = &now
+	e.LastAttemptBy = actor
+
+	if err != nil {
+		e.Status = ExportFailed
+		e.LastError = err.Error()
+		return
+	}
+
+	e.Status = ExportSent
+	e.LastError = ""
+	e.SentAt = &now
+}
+
+// ResultRecord reúne um boleto conciliado na execução com o boleto e o pagamento correspondentes, usados
+// pelos layouts que precisam dos dados do título (ex.: CNAB de baixa)
+type ResultRecord struct {
+	Reconciled ReconciledBillet
+	Billet     *Billet
+	Payment    *Payment
+}
+
+// PaidAmount retorna o valor baixado do título: o valor pago registrado em boletos de valor aberto, o
+// valor do título quando o pagamento foi dividido ou agrupado, veio de crédito ou foi pago em partes, e o
+// valor do pagamento nos demais casos
+func (r ResultRecord) PaidAmount() float64 {
+	if r.Reconciled.PaidAmount != nil {
+		return *r.Reconciled.PaidAmount
+	}
+
+	switch r.Reconciled.ConciliationStrategy {
+	case StrategySplit, StrategyAggregate, StrategyUnappliedCredit, StrategyPartialPayment:
+		return r.Billet.Amount
+	}
+
+	if r.Payment != nil {
+		return r.Payment.Amount
+	}
+	return r.Billet.Amount
+}
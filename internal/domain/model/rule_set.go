@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+)
+
+// RuleSet define o pipeline de matching configurado para um tenant: as estratégias aplicadas, a ordem
+// e os parâmetros de cada uma, como tolerâncias e janelas de datas. Com BankAccount, o conjunto vale
+// apenas para a conta; sem ele, para as contas do tenant sem conjunto próprio
+type RuleSet struct {
+	Tenant      string           `json:"tenant"`
+	BankAccount string           `json:"bank_account,omitempty"`
+	Strategies  []StrategyConfig `json:"strategies"`
+	UpdatedBy   string           `json:"updated_by,omitempty"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// NewRuleSet cria o conjunto de regras do tenant, geral ou da conta informada
+func NewRuleSet(tenant, bankAccount string, strategies []StrategyConfig, updatedBy string) *RuleSet {
+	return &RuleSet{
+		Tenant:      tenant,
+		BankAccount: bankAccount,
+		Strategies:  strategies,
+		UpdatedBy:   updatedBy,
+		UpdatedAt:   time.Now(),
+	}
+}
+
+// RuleSets reúne os conjuntos de regras de um tenant
+type RuleSets []*RuleSet
+
+// For retorna o conjunto de regras aplicado à conta: o da própria conta ou, sem ele, o geral do
+// tenant. Retorna nil quando o tenant não configurou nenhum dos dois
+func (r RuleSets) For(bankAccount string) *RuleSet {
+	var general *RuleSet
+	for _, ruleSet := range r {
+		switch ruleSet.BankAccount {
+		case bankAccount:
+			return ruleSet
+		case "":
+			general = ruleSet
+		}
+	}
+	return general
+}
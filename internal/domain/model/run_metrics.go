@@ -0,0 +1,79 @@
+package model
+
+import (
+	"time"
+)
+
+// RunMetrics registra as medições de uma execução de conciliação, usadas no planejamento de capacidade
+type RunMetrics struct {
+	// DurationMs é a duração total da execução, do registro até a conclusão
+	DurationMs int64 `json:"duration_ms"`
+
+	// CandidatesEvaluated soma os candidatos avaliados por todas as estratégias
+	CandidatesEvaluated int64 `json:"candidates_evaluated"`
+
+	// PeakMemoryBytes é o maior heap em uso amostrado ao fim da conciliação de cada conta; é uma
+	// estimativa, pois o heap inclui o que o restante do processo usa no momento
+	PeakMemoryBytes uint64 `json:"peak_memory_bytes"`
+
+	// Strategies traz as medições de cada estratégia aplicada, somadas entre as contas da execução
+	Strategies map[ConciliationStrategy]*StrategyMetrics `json:"strategies"`
+}
+
+// StrategyMetrics registra as medições de uma estratégia em uma execução. Os candidatos são os pares
+// de boleto e pagamento ainda em aberto quando a estratégia começa, na mesma conta
+type StrategyMetrics struct {
+	DurationMs float64 `json:"duration_ms"`
+	Candidates int64   `json:"candidates"`
+	Matches    int     `json:"matches"`
+}
+
+// NewRunMetrics cria as medições vazias de uma execução
+func NewRunMetrics() *RunMetrics {
+	return &RunMetrics{
+		Strategies: make(map[ConciliationStrategy]*StrategyMetrics),
+	}
+}
+
+// RecordStrategy acumula a duração, os candidatos e as conciliações de uma aplicação da estratégia
+func (m *RunMetrics) RecordStrategy(strategy ConciliationStrategy, duration time.Duration, candidates int64, matches int) {
+	metrics, found := m.Strategies[strategy]
+	if !found {
+		metrics = &StrategyMetrics{}
+		m.Strategies[strategy] = metrics
+	}
+
+	metrics.DurationMs += float64(duration.Microseconds()) / 1000
+	metrics.Candidates += candidates
+	metrics.Matches += matches
+	m.CandidatesEvaluated += candidates
+}
+
+// RecordMemory registra o heap em uso amostrado, mantendo o maior valor
+func (m *RunMetrics) RecordMemory(heapBytes uint64) {
+	if heapBytes > m.PeakMemoryBytes {
+		m.PeakMemoryBytes = heapBytes
+	}
+}
+
+// Merge acumula as medições de outra parte da execução (ex.: outra conta)
+func (m *RunMetrics) Merge(other *RunMetrics) {
+	if other == nil {
+		return
+	}
+
+	for strategy, metrics := range other.Strategies {
+		current, found := m.Strategies[strategy]
+		if !found {
+			current = &StrategyMetrics{}
+			m.Strategies[strategy] = current
+		}
+
+		current.DurationMs += metrics.DurationMs
+		current.Candidates += metrics.Candidates
+		current.Matches += metrics.Matches
+	}
+
+	m.CandidatesEvaluated += other.CandidatesEvaluated
+	m.RecordMemory(other.PeakMemoryBytes)
+}
@@ -0,0 +1,182 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// ShadowDivergence classifica a comparação entre o resultado de produção e o do motor shadow para um boleto
+type ShadowDivergence string
+
+const (
+	// ShadowAgreement indica que os dois motores conciliaram o boleto com o mesmo pagamento e a mesma estratégia
+	ShadowAgreement ShadowDivergence = "igual"
+
+	// ShadowDifferentPayment indica que os motores conciliaram o boleto com pagamentos diferentes
+	ShadowDifferentPayment ShadowDivergence = "pagamento_diferente"
+
+	// ShadowDifferentStrategy indica que os motores escolheram o mesmo pagamento por estratégias diferentes
+	ShadowDifferentStrategy ShadowDivergence = "estrategia_diferente"
+
+	// ShadowOnlyPrimary indica que apenas o motor de produção conciliou o boleto
+	ShadowOnlyPrimary ShadowDivergence = "apenas_producao"
+
+	// ShadowOnlyShadow indica que apenas o motor shadow conciliou o boleto
+	ShadowOnlyShadow ShadowDivergence = "apenas_shadow"
+)
+
+// ShadowReconciliation registra, para um boleto de uma execução, o resultado do motor shadow ao lado do
+// de produção. O resultado shadow nunca é aplicado: serve apenas para validar uma nova versão do algoritmo
+type ShadowReconciliation struct {
+	ID            string `json:"id"`
+	RunID         string `json:"run_id"`
+	BilletID      string `json:"billet_id"`
+	BankAccount   string `json:"bank_account"`
+	EngineVersion string `json:"engine_version"`
+
+	PrimaryTransactionID *string              `json:"primary_transaction_id,omitempty"`
+	PrimaryStrategy      ConciliationStrategy `json:"primary_strategy,omitempty"`
+	PrimaryStatus        ConciliationStatus   `json:"primary_status,omitempty"`
+
+	ShadowTransactionID *string              `json:"shadow_transaction_id,omitempty"`
+	ShadowStrategy      ConciliationStrategy `json:"shadow_strategy,omitempty"`
+	ShadowStatus        ConciliationStatus   `json:"shadow_status,omitempty"`
+	ShadowAmountDiff    float64              `json:"shadow_amount_diff"`
+
+	Divergence ShadowDivergence `json:"divergence"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+// CompareShadowResults compara, boleto a boleto, o resultado de produção com o do motor shadow sobre os
+// mesmos dados. Boletos que nenhum dos motores conciliou ficam fora da comparação
+func CompareShadowResults(runID, engineVersion string, primary, shadow *ReconciliationResult) []*ShadowReconciliation {
+	primaryByBillet := make(map[string]ReconciledBillet, len(primary.ReconciledBillets))
+	for _, reconciled := range primary.ReconciledBillets {
+		primaryByBillet[reconciled.BilletID] = reconciled
+	}
+
+	shadowByBillet := make(map[string]ReconciledBillet, len(shadow.ReconciledBillets))
+	for _, reconciled := range shadow.ReconciledBillets {
+		shadowByBillet[reconciled.BilletID] = reconciled
+	}
+
+	billetIDs := make([]string, 0, len(primaryByBillet)+len(shadowByBillet))
+	for billetID := range primaryByBillet {
+		billetIDs = append(billetIDs, billetID)
+	}
+	for billetID := range shadowByBillet {
+		if _, ok := primaryByBillet[billetID]; !ok {
+			billetIDs = append(billetIDs, billetID)
+		}
+	}
+	sort.Strings(billetIDs)
+
+	now := time.Now()
+	comparisons := make([]*ShadowReconciliation, 0, len(billetIDs))
+	for _, billetID := range billetIDs {
+		primaryMatch, inPrimary := primaryByBillet[billetID]
+		shadowMatch, inShadow := shadowByBillet[billetID]
+
+		comparison := &ShadowReconciliation{
+			ID:            generateUUID(),
+			RunID:         runID,
+			BilletID:      billetID,
+			EngineVersion: engineVersion,
+			CreatedAt:     now,
+		}
+
+		if inPrimary {
+			transactionID := primaryMatch.TransactionID
+			comparison.BankAccount = primaryMatch.BankAccount
+			comparison.PrimaryTransactionID = &transactionID
+			comparison.PrimaryStrategy = primaryMatch.ConciliationStrategy
+			comparison.PrimaryStatus = primaryMatch.ConciliationStatus
+		}
+		if inShadow {
+			transactionID := shadowMatch.TransactionID
+			comparison.BankAccount = shadowMatch.BankAccount
+			comparison.ShadowTransactionID = &transactionID
+			comparison.ShadowStrategy = shadowMatch.ConciliationStrategy
+			comparison.ShadowStatus = shadowMatch.ConciliationStatus
+			comparison.ShadowAmountDiff = shadowMatch.AmountDiff
+		}
+
+		switch {
+		case !inShadow:
+			comparison.Divergence = ShadowOnlyPrimary
+		case !inPrimary:
+			comparison.Divergence = ShadowOnlyShadow
+		case primaryMatch.TransactionID != shadowMatch.TransactionID:
+			comparison.Divergence = ShadowDifferentPayment
+		case primaryMatch.ConciliationStrategy != shadowMatch.ConciliationStrategy:
+			comparison.Divergence = ShadowDifferentStrategy
+		default:
+			comparison.Divergence = ShadowAgreement
+		}
+
+		comparisons = append(comparisons, comparison)
+	}
+
+	return comparisons
+}
+
+// ShadowReport resume a divergência entre o motor de produção e o motor shadow em uma execução
+type ShadowReport struct {
+	RunID          string   `json:"run_id"`
+	EngineVersions []string `json:"engine_versions"`
+
+	// PrimaryReconciled e ShadowReconciled são os boletos conciliados por cada motor
+	PrimaryReconciled int `json:"primary_reconciled"`
+	ShadowReconciled  int `json:"shadow_reconciled"`
+
+	// Agreements são os boletos conciliados igualmente pelos dois motores; DivergenceRate é a
+	// porcentagem dos boletos comparados em que os motores discordam
+	Agreements     int                      `json:"agreements"`
+	Divergent      int                      `json:"divergent"`
+	DivergenceRate float64                  `json:"divergence_rate"`
+	ByDivergence   map[ShadowDivergence]int `json:"by_divergence"`
+
+	// Divergences lista os boletos em que os motores discordam
+	Divergences []*ShadowReconciliation `json:"divergences"`
+}
+
+// NewShadowReport monta o relatório de divergência de uma execução a partir das comparações gravadas
+func NewShadowReport(runID string, comparisons []*ShadowReconciliation) *ShadowReport {
+	report := &ShadowReport{
+		RunID:          runID,
+		EngineVersions: []string{},
+		ByDivergence:   make(map[ShadowDivergence]int),
+		Divergences:    []*ShadowReconciliation{},
+	}
+
+	versions := make(map[string]bool)
+	for _, comparison := range comparisons {
+		if !versions[comparison.EngineVersion] {
+			versions[comparison.EngineVersion] = true
+			report.EngineVersions = append(report.EngineVersions, comparison.EngineVersion)
+		}
+
+		if comparison.PrimaryTransactionID != nil {
+			report.PrimaryReconciled++
+		}
+		if comparison.ShadowTransactionID != nil {
+			report.ShadowReconciled++
+		}
+
+		report.ByDivergence[comparison.Divergence]++
+		if comparison.Divergence == ShadowAgreement {
+			report.Agreements++
+			continue
+		}
+
+		report.Divergent++
+		report.Divergences = append(report.Divergences, comparison)
+	}
+	sort.Strings(report.EngineVersions)
+
+	if len(comparisons) > 0 {
+		report.DivergenceRate = float64(report.Divergent) / float64(len(comparisons)) * 100
+	}
+
+	return report
+}
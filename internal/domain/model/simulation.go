@@ -0,0 +1,13 @@
+package model
+
+// ToleranceSimulation representa o resultado de uma conciliação simulada em memória com uma tolerância
+type ToleranceSimulation struct {
+	TolerancePercentage   float64 `json:"tolerance_percentage"`
+	TotalBillets          int     `json:"total_billets"`
+	ReconciledBillets     int     `json:"reconciled_billets"`
+	SuccessfulMatches     int     `json:"successful_matches"`
+	DifferentValueMatches int     `json:"different_value_matches"`
+	AmbiguousReferences   int     `json:"ambiguous_references"`
+	NonReconciledBillets  int     `json:"non_reconciled_billets"`
+	ReconciliationRate    float64 `json:"reconciliation_rate"`
+}
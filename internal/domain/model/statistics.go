@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+)
+
+// TimeToReconcileFilter representa os filtros das estatísticas de tempo até a conciliação
+type TimeToReconcileFilter struct {
+	BankAccount   string
+	EngineVersion string
+	DateField     ReconciliationDateField
+	StartDate     *time.Time
+	EndDate       *time.Time
+}
+
+// TimeToReconcileStatistics representa os percentis do tempo entre o pagamento e a conciliação de uma conta
+type TimeToReconcileStatistics struct {
+	BankAccount string  `json:"bank_account"`
+	Count       int64   `json:"count"`
+	P50Seconds  float64 `json:"p50_seconds"`
+	P90Seconds  float64 `json:"p90_seconds"`
+	P99Seconds  float64 `json:"p99_seconds"`
+}
+
+// EngineVersionStatisticsFilter representa os filtros das estatísticas por versão do motor de matching
+type EngineVersionStatisticsFilter struct {
+	BankAccount   string
+	EngineVersion string
+	DateField     ReconciliationDateField
+	StartDate     *time.Time
+	EndDate       *time.Time
+}
+
+// EngineVersionStatistics representa os totais das conciliações gravadas por uma versão do motor de
+// matching em um dia e conta. Matched conta os boletos pareados, inclusive com valor diferente ou
+// por pagamentos parciais
+type EngineVersionStatistics struct {
+	Date          time.Time `json:"date"`
+	EngineVersion string    `json:"engine_version"`
+	BankAccount   string    `json:"bank_account,omitempty"`
+	Total         int64     `json:"total"`
+	Matched       int64     `json:"matched"`
+	Suggested     int64     `json:"suggested"`
+	NotReconciled int64     `json:"not_reconciled"`
+	AmbiguousRef  int64     `json:"ambiguous_reference"`
+
+	// MatchRate é a porcentagem dos registros gravados pela versão que parearam o boleto
+	MatchRate float64 `json:"match_rate"`
+}
+
+// SummarizeEngineVersionStatistics soma as contas de cada dia e versão, mantendo a ordem de dia e versão,
+// e calcula a taxa de conciliação de cada grupo. A comparação entre dias vizinhos mostra a queda da taxa
+// que coincide com a troca de versão
+func SummarizeEngineVersionStatistics(statistics []*EngineVersionStatistics) []*EngineVersionStatistics {
+	summary := []*EngineVersionStatistics{}
+	byKey := make(map[string]*EngineVersionStatistics)
+
+	for _, stats := range statistics {
+		key := stats.Date.Format("2006-01-02") + "|" + stats.EngineVersion
+		group, found := byKey[key]
+		if !found {
+			group = &EngineVersionStatistics{Date: stats.Date, EngineVersion: stats.EngineVersion}
+			byKey[key] = group
+			summary = append(summary, group)
+		}
+
+		group.Total += stats.Total
+		group.Matched += stats.Matched
+		group.Suggested += stats.Suggested
+		group.NotReconciled += stats.NotReconciled
+		group.AmbiguousRef += stats.AmbiguousRef
+	}
+
+	for _, group := range summary {
+		if group.Total > 0 {
+			group.MatchRate = float64(group.Matched) / float64(group.Total) * 100
+		}
+	}
+
+	return summary
+}
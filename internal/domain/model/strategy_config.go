@@ -0,0 +1,111 @@
+package model
+
+// StrategyParams define os parâmetros de uma estratégia de conciliação em uma execução
+type StrategyParams struct {
+	// Tolerance substitui, na estratégia, a tolerância percentual de diferença de valor do serviço
+	Tolerance *float64 `json:"tolerance,omitempty"`
+
+	// MaxDaysDiff limita, em dias, a diferença entre a emissão do boleto e o pagamento nas estratégias
+	// que comparam datas; nula, a estratégia não tem janela de datas
+	MaxDaysDiff *int `json:"max_days_diff,omitempty"`
+
+	// MinSimilarity substitui, nas estratégias que comparam textos, a similaridade mínima (de 0 a 1)
+	// entre o nome do pagador e a descrição do pagamento
+	MinSimilarity *float64 `json:"min_similarity,omitempty"`
+}
+
+// DefaultMinSimilarity é a similaridade mínima padrão entre o nome do pagador e a descrição do
+// pagamento na estratégia de nome aproximado
+const DefaultMinSimilarity = 0.9
+
+// StrategyConfig define uma estratégia da ordem de conciliação de uma execução e os seus parâmetros
+type StrategyConfig struct {
+	Strategy ConciliationStrategy `json:"strategy"`
+	Params   StrategyParams       `json:"params"`
+}
+
+// DefaultStrategyOrder define a ordem padrão das estratégias automáticas. O código de barras identifica o
+// título com mais segurança que o reference_id e vem antes dele; o documento e o nome do pagador, que
+// atendem os pagamentos sem referência, vêm antes da conta/valor/data. A estratégia de créditos só é aplicada
+// quando a execução habilita o uso de créditos não aplicados
+var DefaultStrategyOrder = []ConciliationStrategy{
+	StrategyBarcode,
+	StrategyReferenceID,
+	StrategyInstallment,
+	StrategyPayerDocument,
+	StrategyFuzzyName,
+	StrategyAccountAmountDate,
+	StrategySplit,
+	StrategyAggregate,
+	StrategyUnappliedCredit,
+}
+
+// OptionalStrategies lista as estratégias automáticas fora da ordem padrão, aplicadas apenas quando a
+// execução as inclui na ordem. A de pagamentos parciais deve vir antes de reference_id, que trataria
+// como ambígua a referência com vários pagamentos
+var OptionalStrategies = []ConciliationStrategy{
+	StrategyPartialPayment,
+}
+
+// toleranceStrategies lista as estratégias que comparam valores com a tolerância percentual
+var toleranceStrategies = map[ConciliationStrategy]bool{
+	StrategyBarcode:           true,
+	StrategyReferenceID:       true,
+	StrategyInstallment:       true,
+	StrategyPayerDocument:     true,
+	StrategyFuzzyName:         true,
+	StrategyAccountAmountDate: true,
+	StrategyAggregate:         true,
+	StrategyPartialPayment:    true,
+}
+
+// dateWindowStrategies lista as estratégias que escolhem o boleto pela proximidade de datas e aceitam
+// uma janela de datas
+var dateWindowStrategies = map[ConciliationStrategy]bool{
+	StrategyFuzzyName:         true,
+	StrategyAccountAmountDate: true,
+}
+
+// similarityStrategies lista as estratégias que comparam textos e aceitam uma similaridade mínima
+var similarityStrategies = map[ConciliationStrategy]bool{
+	StrategyFuzzyName: true,
+}
+
+// IsAutomaticStrategy indica se a estratégia pode compor a ordem de conciliação de uma execução
+func IsAutomaticStrategy(strategy ConciliationStrategy) bool {
+	for _, automatic := range DefaultStrategyOrder {
+		if automatic == strategy {
+			return true
+		}
+	}
+	for _, optional := range OptionalStrategies {
+		if optional == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsTolerance indica se a estratégia compara valores com a tolerância percentual
+func (s ConciliationStrategy) AcceptsTolerance() bool {
+	return toleranceStrategies[s]
+}
+
+// AcceptsDateWindow indica se a estratégia aceita uma janela de datas
+func (s ConciliationStrategy) AcceptsDateWindow() bool {
+	return dateWindowStrategies[s]
+}
+
+// AcceptsMinSimilarity indica se a estratégia aceita uma similaridade mínima
+func (s ConciliationStrategy) AcceptsMinSimilarity() bool {
+	return similarityStrategies[s]
+}
+
+// DefaultStrategyConfigs retorna a ordem padrão das estratégias, sem parâmetros próprios
+func DefaultStrategyConfigs() []StrategyConfig {
+	configs := make([]StrategyConfig, 0, len(DefaultStrategyOrder))
+	for _, strategy := range DefaultStrategyOrder {
+		configs = append(configs, StrategyConfig{Strategy: strategy})
+	}
+	return configs
+}
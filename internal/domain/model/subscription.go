@@ -0,0 +1,86 @@
+package model
+
+import (
+	"time"
+)
+
+// Subscription representa um assinante de eventos (webhook) com filtros de entrega.
+// Filtros vazios não restringem: sem tipos de evento, todos são entregues; sem contas, todas as contas
+type Subscription struct {
+	ID           string      `json:"id"`
+	Name         string      `json:"name"`
+	CallbackURL  string      `json:"callback_url"`
+	Secret       string      `json:"-"`
+	EventTypes   []EventType `json:"event_types"`
+	BankAccounts []string    `json:"bank_accounts"`
+	MinAmount    *float64    `json:"min_amount,omitempty"`
+	Active       bool        `json:"active"`
+
+	// Campos adicionais para controle interno
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewSubscription cria uma nova instância de Subscription ativa
+func NewSubscription(name, callbackURL, secret string, eventTypes []EventType, bankAccounts []string, minAmount *float64) *Subscription {
+	now := time.Now()
+
+	return &Subscription{
+		ID:           generateUUID(),
+		Name:         name,
+		CallbackURL:  callbackURL,
+		Secret:       secret,
+		EventTypes:   eventTypes,
+		BankAccounts: bankAccounts,
+		MinAmount:    minAmount,
+		Active:       true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// Matches verifica se o evento atende aos filtros de tipo, conta bancária e valor mínimo.
+// Eventos internos nunca são entregues aos assinantes
+func (s *Subscription) Matches(event *Event) bool {
+	if !s.Active {
+		return false
+	}
+
+	if !IsKnownEventType(event.Type) {
+		return false
+	}
+
+	if len(s.EventTypes) > 0 && !containsEventType(s.EventTypes, event.Type) {
+		return false
+	}
+
+	if len(s.BankAccounts) > 0 && !containsString(s.BankAccounts, event.BankAccount) {
+		return false
+	}
+
+	if s.MinAmount != nil && event.Amount < *s.MinAmount {
+		return false
+	}
+
+	return true
+}
+
+// containsEventType verifica se o tipo de evento está na lista
+func containsEventType(eventTypes []EventType, eventType EventType) bool {
+	for _, candidate := range eventTypes {
+		if candidate == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString verifica se o valor está na lista
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+)
+
+// SuggestionType define os tipos de sugestão de correção para boletos não conciliados
+type SuggestionType string
+
+const (
+	SuggestionFeeDifference      SuggestionType = "diferenca_tarifa"
+	SuggestionAccountMismatch    SuggestionType = "conta_divergente"
+	SuggestionAmountOutOfRange   SuggestionType = "valor_fora_tolerancia"
+	SuggestionReferenceMismatch  SuggestionType = "referencia_divergente"
+	SuggestionMissingReferenceID SuggestionType = "referencia_ausente"
+)
+
+// MatchCandidate representa um pagamento candidato a conciliar com um boleto não conciliado
+type MatchCandidate struct {
+	TransactionID string    `json:"transaction_id"`
+	BankAccount   string    `json:"bank_account"`
+	Amount        float64   `json:"amount"`
+	PaymentDate   time.Time `json:"payment_date"`
+	ReferenceID   *string   `json:"reference_id,omitempty"`
+	AmountDiff    float64   `json:"amount_diff"`
+	DaysDiff      int       `json:"days_diff"`
+}
+
+// CorrectionSuggestion representa uma sugestão de correção de dados gerada automaticamente
+type CorrectionSuggestion struct {
+	Type          SuggestionType `json:"type"`
+	Message       string         `json:"message"`
+	TransactionID string         `json:"transaction_id,omitempty"`
+}
+
+// BilletSuggestions agrupa os candidatos de match e as sugestões de correção de um boleto
+type BilletSuggestions struct {
+	BilletID    string                 `json:"billet_id"`
+	Candidates  []MatchCandidate       `json:"candidates"`
+	Suggestions []CorrectionSuggestion `json:"suggestions"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"context"
+)
+
+// tenantKey é a chave do tenant no contexto da requisição
+type tenantKey struct{}
+
+// WithTenant associa o tenant ao contexto
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext recupera o tenant do contexto, ou vazio quando não foi informado
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
@@ -0,0 +1,146 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TimelineEntityType define as entidades que possuem linha do tempo
+type TimelineEntityType string
+
+const (
+	TimelineBillet  TimelineEntityType = "boleto"
+	TimelinePayment TimelineEntityType = "pagamento"
+)
+
+// TimelineEntryKind define os tipos de acontecimento exibidos na linha do tempo
+type TimelineEntryKind string
+
+const (
+	TimelineCreated       TimelineEntryKind = "criacao"
+	TimelineUpdated       TimelineEntryKind = "alteracao"
+	TimelineImported      TimelineEntryKind = "importacao"
+	TimelineMatchAttempt  TimelineEntryKind = "tentativa_matching"
+	TimelineReconciled    TimelineEntryKind = "conciliacao"
+	TimelineStatusChanged TimelineEntryKind = "mudanca_status"
+	TimelineUndone        TimelineEntryKind = "desfeita"
+	TimelineComment       TimelineEntryKind = "comentario"
+	TimelineNotification  TimelineEntryKind = "notificacao"
+	TimelineReversed      TimelineEntryKind = "estorno"
+	TimelineClassified    TimelineEntryKind = "classificacao"
+)
+
+// TimelineEntry representa um acontecimento na vida de um boleto ou pagamento. Parte das entradas é
+// gravada no momento da ação (comentários, alterações, importações); as demais são derivadas das
+// conciliações, mudanças de status e entregas de eventos ao montar a linha do tempo
+type TimelineEntry struct {
+	ID          string             `json:"id,omitempty"`
+	EntityType  TimelineEntityType `json:"entity_type"`
+	EntityID    string             `json:"entity_id"`
+	Kind        TimelineEntryKind  `json:"kind"`
+	Description string             `json:"description"`
+	Actor       string             `json:"actor,omitempty"`
+	Reference   string             `json:"reference,omitempty"` // ID relacionado (conciliação, pagamento, lote, entrega)
+	OccurredAt  time.Time          `json:"occurred_at"`
+}
+
+// NewTimelineEntry cria uma nova entrada de linha do tempo ocorrida agora
+func NewTimelineEntry(entityType TimelineEntityType, entityID string, kind TimelineEntryKind, description, actor string) *TimelineEntry {
+	return &TimelineEntry{
+		ID:          generateUUID(),
+		EntityType:  entityType,
+		EntityID:    entityID,
+		Kind:        kind,
+		Description: description,
+		Actor:       actor,
+		OccurredAt:  time.Now(),
+	}
+}
+
+// Timeline agrega, em ordem cronológica, os acontecimentos de um boleto ou pagamento
+type Timeline struct {
+	EntityType TimelineEntityType `json:"entity_type"`
+	EntityID   string             `json:"entity_id"`
+	Entries    []*TimelineEntry   `json:"entries"`
+}
+
+// NewTimeline cria a linha do tempo ordenando as entradas cronologicamente; entradas simultâneas
+// mantêm a ordem em que foram informadas
+func NewTimeline(entityType TimelineEntityType, entityID string, entries []*TimelineEntry) *Timeline {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].OccurredAt.Before(entries[j].OccurredAt)
+	})
+
+	return &Timeline{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Entries:    entries,
+	}
+}
+
+// BilletChanges descreve os campos alterados entre duas versões de um boleto
+func BilletChanges(previous, current *Billet) []string {
+	changes := []string{}
+
+	if previous.BankAccount != current.BankAccount {
+		changes = append(changes, fmt.Sprintf("conta: %s → %s", previous.BankAccount, current.BankAccount))
+	}
+	if previous.Amount != current.Amount {
+		changes = append(changes, fmt.Sprintf("valor: %.2f → %.2f", previous.Amount, current.Amount))
+	}
+	if !previous.IssuanceDate.Equal(current.IssuanceDate) {
+		changes = append(changes, fmt.Sprintf("emissão: %s → %s",
+			previous.IssuanceDate.Format("2006-01-02"), current.IssuanceDate.Format("2006-01-02")))
+	}
+	if stringValue(previous.ReferenceID) != stringValue(current.ReferenceID) {
+		changes = append(changes, fmt.Sprintf("referência: %q → %q", stringValue(previous.ReferenceID), stringValue(current.ReferenceID)))
+	}
+	if previous.OpenAmount != current.OpenAmount {
+		changes = append(changes, fmt.Sprintf("valor aberto: %t → %t", previous.OpenAmount, current.OpenAmount))
+	}
+	if previous.BankCode != current.BankCode {
+		changes = append(changes, fmt.Sprintf("banco: %q → %q", previous.BankCode, current.BankCode))
+	}
+	if previous.BarCode != current.BarCode {
+		changes = append(changes, fmt.Sprintf("código de barras: %q → %q", previous.BarCode, current.BarCode))
+	}
+	if previous.DigitableLine != current.DigitableLine {
+		changes = append(changes, fmt.Sprintf("linha digitável: %q → %q", previous.DigitableLine, current.DigitableLine))
+	}
+	if dateValue(previous.DueDate) != dateValue(current.DueDate) {
+		changes = append(changes, fmt.Sprintf("vencimento: %q → %q", dateValue(previous.DueDate), dateValue(current.DueDate)))
+	}
+	if previous.InterestRate != current.InterestRate {
+		changes = append(changes, fmt.Sprintf("juros: %.2f%% → %.2f%% ao mês", previous.InterestRate, current.InterestRate))
+	}
+	if previous.FinePercent != current.FinePercent {
+		changes = append(changes, fmt.Sprintf("multa: %.2f%% → %.2f%%", previous.FinePercent, current.FinePercent))
+	}
+	// O documento e o nome do pagador são dados pessoais: a linha do tempo, retida por mais tempo que os
+	// dados do pagador, registra apenas que mudaram
+	if previous.PayerDocument != current.PayerDocument {
+		changes = append(changes, "documento do pagador alterado")
+	}
+	if previous.PayerName != current.PayerName {
+		changes = append(changes, "nome do pagador alterado")
+	}
+
+	return changes
+}
+
+// stringValue retorna o valor de um ponteiro de string, ou vazio quando nulo
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// dateValue formata a data de um ponteiro de data, ou vazio quando nulo
+func dateValue(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.Format("2006-01-02")
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+)
+
+// UnappliedCredit representa a sobra de um pagamento que quitou boletos do pagador e excedeu o valor
+// devido. O saldo fica vinculado ao pagador e pode ser aplicado manualmente a boletos futuros
+type UnappliedCredit struct {
+	ID                  string    `json:"id"`
+	PayerID             string    `json:"payer_id"`
+	BankAccount         string    `json:"bank_account"`
+	OriginTransactionID string    `json:"origin_transaction_id"`
+	Amount              float64   `json:"amount"`
+	Balance             float64   `json:"balance"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+
+	// Applications é o histórico das aplicações do saldo a boletos do pagador
+	Applications []CreditApplication `json:"applications,omitempty"`
+}
+
+// CreditApplication registra a aplicação de parte do saldo de um crédito para quitar um boleto.
+// Aplicações feitas pela estratégia de créditos durante uma execução não têm usuário
+type CreditApplication struct {
+	ID        string    `json:"id"`
+	CreditID  string    `json:"credit_id"`
+	BilletID  string    `json:"billet_id"`
+	Amount    float64   `json:"amount"`
+	AppliedBy string    `json:"applied_by,omitempty"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// NewCreditApplication cria o registro da aplicação do crédito ao boleto
+func NewCreditApplication(creditID, billetID string, amount float64, appliedBy string) *CreditApplication {
+	return &CreditApplication{
+		ID:        generateUUID(),
+		CreditID:  creditID,
+		BilletID:  billetID,
+		Amount:    roundCents(amount),
+		AppliedBy: appliedBy,
+		AppliedAt: time.Now(),
+	}
+}
+
+// NewUnappliedCredit cria o crédito não aplicado com a sobra do pagamento de origem
+func NewUnappliedCredit(payerID, bankAccount, originTransactionID string, amount float64) *UnappliedCredit {
+	now := time.Now()
+	amount = roundCents(amount)
+
+	return &UnappliedCredit{
+		ID:                  generateUUID(),
+		PayerID:             payerID,
+		BankAccount:         bankAccount,
+		OriginTransactionID: originTransactionID,
+		Amount:              amount,
+		Balance:             amount,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+}
+
+// Available indica se o crédito ainda tem saldo a aplicar
+func (c *UnappliedCredit) Available() bool {
+	return c.Balance > 0
+}
+
+// UnappliedCreditFilter define os filtros da consulta de créditos não aplicados
+type UnappliedCreditFilter struct {
+	PayerID       string
+	BankAccount   string
+	OnlyAvailable bool
+}
@@ -0,0 +1,170 @@
+package model
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// WorkingPaperLine representa uma conciliação no papel de trabalho da auditoria externa, com os valores
+// e as datas do boleto e do pagamento conciliados
+type WorkingPaperLine struct {
+	ReconciliationID     string               `json:"reconciliation_id"`
+	ReconciliationDate   time.Time            `json:"reconciliation_date"`
+	BankAccount          string               `json:"bank_account"`
+	BilletID             string               `json:"billet_id"`
+	IssuanceDate         *time.Time           `json:"issuance_date,omitempty"`
+	BilletAmount         float64              `json:"billet_amount"`
+	TransactionID        string               `json:"transaction_id,omitempty"`
+	PaymentDate          *time.Time           `json:"payment_date,omitempty"`
+	PaidAmount           float64              `json:"paid_amount"`
+	AmountDiff           float64              `json:"amount_diff"`
+	ConciliationStatus   ConciliationStatus   `json:"conciliation_status"`
+	ConciliationStrategy ConciliationStrategy `json:"conciliation_strategy"`
+	ReferenceID          string               `json:"reference_id,omitempty"`
+}
+
+// Campos disponíveis para as colunas do papel de trabalho
+const (
+	WorkingPaperFieldReconciliationID   = "reconciliation_id"
+	WorkingPaperFieldReconciliationDate = "reconciliation_date"
+	WorkingPaperFieldBankAccount        = "bank_account"
+	WorkingPaperFieldBilletID           = "billet_id"
+	WorkingPaperFieldIssuanceDate       = "issuance_date"
+	WorkingPaperFieldBilletAmount       = "billet_amount"
+	WorkingPaperFieldTransactionID      = "transaction_id"
+	WorkingPaperFieldPaymentDate        = "payment_date"
+	WorkingPaperFieldPaidAmount         = "paid_amount"
+	WorkingPaperFieldAmountDiff         = "amount_diff"
+	WorkingPaperFieldStatus             = "conciliation_status"
+	WorkingPaperFieldStrategy           = "conciliation_strategy"
+	WorkingPaperFieldReferenceID        = "reference_id"
+)
+
+// DefaultWorkingPaperColumns define o layout padrão do papel de trabalho exigido pelas auditorias externas
+var DefaultWorkingPaperColumns = []ReportColumn{
+	{Header: "ID_CONCILIACAO", Field: WorkingPaperFieldReconciliationID},
+	{Header: "DATA_CONCILIACAO", Field: WorkingPaperFieldReconciliationDate},
+	{Header: "CONTA", Field: WorkingPaperFieldBankAccount},
+	{Header: "ID_BOLETO", Field: WorkingPaperFieldBilletID},
+	{Header: "DATA_EMISSAO", Field: WorkingPaperFieldIssuanceDate},
+	{Header: "VLR_BOLETO", Field: WorkingPaperFieldBilletAmount},
+	{Header: "ID_PAGAMENTO", Field: WorkingPaperFieldTransactionID},
+	{Header: "DATA_PAGAMENTO", Field: WorkingPaperFieldPaymentDate},
+	{Header: "VLR_PAGO", Field: WorkingPaperFieldPaidAmount},
+	{Header: "VLR_DIFERENCA", Field: WorkingPaperFieldAmountDiff},
+	{Header: "STATUS", Field: WorkingPaperFieldStatus},
+	{Header: "CRITERIO", Field: WorkingPaperFieldStrategy},
+	{Header: "REFERENCIA", Field: WorkingPaperFieldReferenceID},
+}
+
+// IsValidWorkingPaperField verifica se o campo pode ser usado numa coluna do papel de trabalho
+func IsValidWorkingPaperField(field string) bool {
+	_, ok := (&WorkingPaperLine{}).Field(field)
+	return ok
+}
+
+// Field retorna o valor formatado de um campo para o papel de trabalho: datas em AAAA-MM-DD e valores
+// com duas casas decimais separadas por ponto. Retorna false quando o campo não existe
+func (l *WorkingPaperLine) Field(field string) (string, bool) {
+	switch field {
+	case WorkingPaperFieldReconciliationID:
+		return l.ReconciliationID, true
+	case WorkingPaperFieldReconciliationDate:
+		return formatWorkingPaperDate(&l.ReconciliationDate), true
+	case WorkingPaperFieldBankAccount:
+		return l.BankAccount, true
+	case WorkingPaperFieldBilletID:
+		return l.BilletID, true
+	case WorkingPaperFieldIssuanceDate:
+		return formatWorkingPaperDate(l.IssuanceDate), true
+	case WorkingPaperFieldBilletAmount:
+		return strconv.FormatFloat(l.BilletAmount, 'f', 2, 64), true
+	case WorkingPaperFieldTransactionID:
+		return l.TransactionID, true
+	case WorkingPaperFieldPaymentDate:
+		return formatWorkingPaperDate(l.PaymentDate), true
+	case WorkingPaperFieldPaidAmount:
+		return strconv.FormatFloat(l.PaidAmount, 'f', 2, 64), true
+	case WorkingPaperFieldAmountDiff:
+		return strconv.FormatFloat(l.AmountDiff, 'f', 2, 64), true
+	case WorkingPaperFieldStatus:
+		return string(l.ConciliationStatus), true
+	case WorkingPaperFieldStrategy:
+		return string(l.ConciliationStrategy), true
+	case WorkingPaperFieldReferenceID:
+		return l.ReferenceID, true
+	default:
+		return "", false
+	}
+}
+
+// formatWorkingPaperDate formata a data em AAAA-MM-DD; vazia quando não informada
+func formatWorkingPaperDate(date *time.Time) string {
+	if date == nil || date.IsZero() {
+		return ""
+	}
+	return date.Format("2006-01-02")
+}
+
+// WorkingPaperAccountTotals representa os totais de uma conta no papel de trabalho
+type WorkingPaperAccountTotals struct {
+	BankAccount  string  `json:"bank_account"`
+	Lines        int     `json:"lines"`
+	BilletAmount float64 `json:"billet_amount"`
+	PaidAmount   float64 `json:"paid_amount"`
+	AmountDiff   float64 `json:"amount_diff"`
+}
+
+// add soma a linha aos totais
+func (t *WorkingPaperAccountTotals) add(line *WorkingPaperLine) {
+	t.Lines++
+	t.BilletAmount += line.BilletAmount
+	t.PaidAmount += line.PaidAmount
+	t.AmountDiff += line.AmountDiff
+}
+
+// WorkingPaper representa o papel de trabalho das conciliações de um período para a auditoria externa:
+// as conciliações, os totais por conta e o total geral, preparado pelo responsável que o assina
+type WorkingPaper struct {
+	StartDate   time.Time                    `json:"start_date"`
+	EndDate     time.Time                    `json:"end_date"`
+	BankAccount string                       `json:"bank_account,omitempty"`
+	PreparedBy  string                       `json:"prepared_by"`
+	GeneratedAt time.Time                    `json:"generated_at"`
+	Lines       []*WorkingPaperLine          `json:"lines"`
+	Totals      []*WorkingPaperAccountTotals `json:"totals"`
+	GrandTotal  WorkingPaperAccountTotals    `json:"grand_total"`
+}
+
+// NewWorkingPaper monta o papel de trabalho das linhas informadas, com os totais de cada conta em ordem
+// alfabética e o total geral
+func NewWorkingPaper(startDate, endDate time.Time, bankAccount, preparedBy string, lines []*WorkingPaperLine) *WorkingPaper {
+	paper := &WorkingPaper{
+		StartDate:   startDate,
+		EndDate:     endDate,
+		BankAccount: bankAccount,
+		PreparedBy:  preparedBy,
+		GeneratedAt: time.Now(),
+		Lines:       lines,
+		Totals:      []*WorkingPaperAccountTotals{},
+	}
+
+	byAccount := make(map[string]*WorkingPaperAccountTotals)
+	for _, line := range lines {
+		totals, found := byAccount[line.BankAccount]
+		if !found {
+			totals = &WorkingPaperAccountTotals{BankAccount: line.BankAccount}
+			byAccount[line.BankAccount] = totals
+			paper.Totals = append(paper.Totals, totals)
+		}
+		totals.add(line)
+		paper.GrandTotal.add(line)
+	}
+
+	sort.Slice(paper.Totals, func(i, j int) bool {
+		return paper.Totals[i].BankAccount < paper.Totals[j].BankAccount
+	})
+
+	return paper
+}
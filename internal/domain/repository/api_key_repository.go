@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// APIKeyRepository define as operações de repositório para API keys gerenciadas
+type APIKeyRepository interface {
+	// Create registra uma nova API key
+	Create(ctx context.Context, apiKey *model.APIKey) error
+
+	// GetByID recupera uma API key pelo ID
+	GetByID(ctx context.Context, id string) (*model.APIKey, error)
+
+	// GetByHash recupera uma API key pelo hash do segredo, ou nil quando não existe
+	GetByHash(ctx context.Context, keyHash string) (*model.APIKey, error)
+
+	// List lista todas as API keys do tenant, inclusive revogadas e expiradas
+	List(ctx context.Context, tenant string) ([]*model.APIKey, error)
+
+	// Revoke revoga a API key imediatamente
+	Revoke(ctx context.Context, id string, revokedAt time.Time) error
+
+	// UpdateExpiration altera a data de expiração de uma API key
+	UpdateExpiration(ctx context.Context, id string, expiresAt time.Time) error
+
+	// TouchLastUsed registra o último uso de uma API key
+	TouchLastUsed(ctx context.Context, id string, usedAt time.Time) error
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// BankAccountRepository define as operações de repositório para o cadastro de contas bancárias
+type BankAccountRepository interface {
+	// Save grava a situação da conta, criando o cadastro quando ainda não existe
+	Save(ctx context.Context, account *model.BankAccount) error
+
+	// GetByAccount recupera o cadastro da conta, ou nil quando a conta não está cadastrada
+	GetByAccount(ctx context.Context, account string) (*model.BankAccount, error)
+
+	// GetAll recupera todas as contas cadastradas
+	GetAll(ctx context.Context) ([]*model.BankAccount, error)
+
+	// GetInactive recupera o número das contas inativas
+	GetInactive(ctx context.Context) ([]string, error)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// BilletClaimRepository define as operações de repositório para bloqueios de boletos
+type BilletClaimRepository interface {
+	// Acquire registra o bloqueio se o boleto estiver livre, expirado ou já bloqueado pelo mesmo analista.
+	// Retorna o bloqueio vigente e se ele pertence ao solicitante
+	Acquire(ctx context.Context, claim *model.BilletClaim) (*model.BilletClaim, bool, error)
+
+	// GetByBilletID recupera o bloqueio de um boleto, ou nil quando não existe
+	GetByBilletID(ctx context.Context, billetID string) (*model.BilletClaim, error)
+
+	// Release remove o bloqueio de um boleto mantido pelo analista informado
+	Release(ctx context.Context, billetID, claimedBy string) (bool, error)
+}
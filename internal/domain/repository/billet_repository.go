@@ -2,36 +2,64 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"conciliacao-bancaria/internal/domain/model"
 )
 
 // BilletRepository define as operações de repositório para boletos
 type BilletRepository interface {
-	// Create persiste um novo boleto no banco de dados
-	Create(ctx context.Context, billet *model.Billet) error
+	// Create persiste um novo boleto no banco de dados e retorna o registro gravado. Um ID já
+	// cadastrado retorna errors.ConflictError
+	Create(ctx context.Context, billet *model.Billet) (*model.Billet, error)
 
 	// CreateMany persiste múltiplos boletos no banco de dados
 	CreateMany(ctx context.Context, billets []*model.Billet) error
 
-	// GetByID recupera um boleto pelo seu ID
+	// GetByID recupera um boleto pelo seu ID; inexistente, retorna errors.NotFoundError
 	GetByID(ctx context.Context, id string) (*model.Billet, error)
 
 	// GetAll recupera todos os boletos
 	GetAll(ctx context.Context) ([]*model.Billet, error)
 
+	// List recupera os boletos que atendem ao filtro
+	List(ctx context.Context, filter model.BilletFilter) ([]*model.Billet, error)
+
 	// GetByBankAccount recupera boletos por conta bancária
 	GetByBankAccount(ctx context.Context, bankAccount string) ([]*model.Billet, error)
 
+	// GetByIDs recupera os boletos com os IDs informados; IDs inexistentes são ignorados
+	GetByIDs(ctx context.Context, ids []string) ([]*model.Billet, error)
+
 	// GetByReferenceID recupera boletos por ID de referência
 	GetByReferenceID(ctx context.Context, referenceID string) ([]*model.Billet, error)
 
-	// Update atualiza um boleto existente
-	Update(ctx context.Context, billet *model.Billet) error
+	// Update atualiza um boleto existente e retorna o registro gravado; inexistente, retorna errors.NotFoundError
+	Update(ctx context.Context, billet *model.Billet) (*model.Billet, error)
 
-	// Delete remove um boleto pelo ID
+	// Delete remove um boleto pelo ID; inexistente, retorna errors.NotFoundError
 	Delete(ctx context.Context, id string) error
 
 	// FindNonReconciled encontra boletos que ainda não foram conciliados
 	FindNonReconciled(ctx context.Context) ([]*model.Billet, error)
+
+	// FindNonReconciledByPeriod encontra boletos ainda não conciliados emitidos no período [start, end]
+	FindNonReconciledByPeriod(ctx context.Context, start, end time.Time) ([]*model.Billet, error)
+
+	// FindNonReconciledByFilter encontra boletos ainda não conciliados emitidos no período e das contas do filtro
+	FindNonReconciledByFilter(ctx context.Context, filter model.PendingFilter) ([]*model.Billet, error)
+
+	// StreamNonReconciled percorre os boletos ainda não conciliados do filtro, ordenados por conta bancária
+	// e data de emissão, chamando fn para cada um sem carregar todos em memória. Um erro de fn interrompe a leitura
+	StreamNonReconciled(ctx context.Context, filter model.PendingFilter, fn func(billet *model.Billet) error) error
+
+	// GetByContractID recupera os boletos de um contrato
+	GetByContractID(ctx context.Context, contractID string) ([]*model.Billet, error)
+
+	// GetContractStatistics calcula os totais de conciliação dos boletos de um contrato
+	GetContractStatistics(ctx context.Context, contractID string) (*model.ContractStatistics, error)
+
+	// GetAccountPerformances calcula os totais de conciliação e a idade das pendências dos boletos de
+	// cada conta bancária no período do filtro
+	GetAccountPerformances(ctx context.Context, filter model.AccountRankingFilter) ([]*model.AccountPerformance, error)
 }
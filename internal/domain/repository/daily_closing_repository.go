@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// DailyClosingRepository define as operações de repositório para fechamentos diários
+type DailyClosingRepository interface {
+	// Create persiste um novo fechamento. Retorna false quando o dia já tem fechamento
+	Create(ctx context.Context, closing *model.DailyClosing) (bool, error)
+
+	// GetByID recupera um fechamento pelo seu ID
+	GetByID(ctx context.Context, id string) (*model.DailyClosing, error)
+
+	// GetByDate recupera o fechamento do dia, ou nil quando não existe
+	GetByDate(ctx context.Context, date time.Time) (*model.DailyClosing, error)
+
+	// GetAll recupera todos os fechamentos, do mais recente para o mais antigo
+	GetAll(ctx context.Context) ([]*model.DailyClosing, error)
+
+	// UpdateStatistics atualiza a prévia dos totais de um fechamento ainda aberto
+	UpdateStatistics(ctx context.Context, closing *model.DailyClosing) error
+
+	// Confirm grava a confirmação do fechamento, com os totais finais do dia. Retorna false quando
+	// o fechamento já havia sido confirmado
+	Confirm(ctx context.Context, closing *model.DailyClosing) (bool, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// ERPDriftRepository define as operações de repositório para os relatórios de divergência entre o ERP e a base local
+type ERPDriftRepository interface {
+	// Save grava o relatório de uma verificação
+	Save(ctx context.Context, report *model.ERPDriftReport) error
+
+	// GetLatest recupera o relatório da verificação mais recente, ou nil quando não existe
+	GetLatest(ctx context.Context) (*model.ERPDriftReport, error)
+
+	// List recupera os relatórios mais recentes, do mais novo para o mais antigo, até o limite informado
+	List(ctx context.Context, limit int) ([]*model.ERPDriftReport, error)
+}
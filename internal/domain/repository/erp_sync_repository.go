@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// ERPSyncRepository define as operações de repositório para o estado da sincronização dos boletos do ERP
+type ERPSyncRepository interface {
+	// Get recupera o estado da sincronização da origem, ou nil quando ela nunca foi sincronizada
+	Get(ctx context.Context, source string) (*model.ERPSyncState, error)
+
+	// Save grava o estado da sincronização da origem, substituindo o anterior
+	Save(ctx context.Context, state *model.ERPSyncState) error
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// EventDeliveryRepository define as operações de repositório para o outbox de entregas de eventos
+type EventDeliveryRepository interface {
+	// CreateMany registra entregas pendentes no outbox
+	CreateMany(ctx context.Context, deliveries []*model.EventDelivery) error
+
+	// GetByID recupera uma entrega pelo ID
+	GetByID(ctx context.Context, id string) (*model.EventDelivery, error)
+
+	// GetByStatus recupera as entregas nas situações informadas, das mais antigas para as mais recentes
+	GetByStatus(ctx context.Context, statuses []model.DeliveryStatus, limit int) ([]*model.EventDelivery, error)
+
+	// ClaimDue reserva, pelo tempo de lease, as entregas pendentes cuja próxima tentativa já venceu,
+	// evitando que duas instâncias enviem a mesma entrega
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.EventDelivery, error)
+
+	// MarkDelivered registra o sucesso de uma entrega
+	MarkDelivered(ctx context.Context, id string, attempts int, deliveredAt time.Time) error
+
+	// MarkAttemptFailed registra a falha de uma tentativa, com a situação e a próxima tentativa resultantes
+	MarkAttemptFailed(ctx context.Context, id string, attempts int, lastError string, status model.DeliveryStatus, nextAttemptAt time.Time) error
+
+	// Requeue devolve à fila as entregas pendentes ou com falha, zerando as tentativas, e retorna quantas foram reenfileiradas
+	Requeue(ctx context.Context, ids []string, now time.Time) (int, error)
+
+	// Discard descarta uma entrega pendente ou com falha, registrando o motivo
+	Discard(ctx context.Context, id string, reason string, discardedAt time.Time) error
+
+	// GetByBilletID recupera as entregas com eventos do boleto informado, das mais antigas para as mais recentes
+	GetByBilletID(ctx context.Context, billetID string) ([]*model.EventDelivery, error)
+
+	// GetByTransactionID recupera as entregas com eventos do pagamento informado, das mais antigas para as mais recentes
+	GetByTransactionID(ctx context.Context, transactionID string) ([]*model.EventDelivery, error)
+
+	// GetMetrics calcula o volume pendente e o atraso de publicação das entregas concluídas desde a data informada
+	GetMetrics(ctx context.Context, since time.Time) (*model.OutboxMetrics, error)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// ImportFileRepository define as operações de repositório para os arquivos bancários importados
+type ImportFileRepository interface {
+	// Create registra um arquivo importado
+	Create(ctx context.Context, file *model.ImportFile) error
+
+	// GetBySequence recupera o arquivo importado com o número sequencial informado, ou nil quando não existe
+	GetBySequence(ctx context.Context, bankCode, agreement, direction string, sequence int) (*model.ImportFile, error)
+
+	// GetLastSequence recupera o maior número sequencial importado do convênio, ou 0 quando não há arquivos
+	GetLastSequence(ctx context.Context, bankCode, agreement, direction string) (int, error)
+
+	// GetAll recupera todos os arquivos importados
+	GetAll(ctx context.Context) ([]*model.ImportFile, error)
+
+	// FindGaps encontra as lacunas na numeração sequencial dos arquivos de cada convênio
+	FindGaps(ctx context.Context) ([]*model.ImportGap, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// MaintenanceReportRepository define as operações de repositório para os relatórios da rotina de
+// manutenção, que registram o que foi expurgado e anonimizado em cada execução
+type MaintenanceReportRepository interface {
+	// Save grava o relatório de uma execução da manutenção
+	Save(ctx context.Context, report *model.MaintenanceReport) error
+
+	// List recupera os relatórios mais recentes, do mais novo para o mais antigo, até o limite informado
+	List(ctx context.Context, limit int) ([]*model.MaintenanceReport, error)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// MaintenanceRepository define as operações de expurgo da rotina de manutenção de um banco. Cada
+// operação retorna a quantidade de registros removidos
+type MaintenanceRepository interface {
+	// PurgeCompletedRuns remove as execuções de conciliação concluídas antes do instante informado
+	PurgeCompletedRuns(ctx context.Context, before time.Time) (int64, error)
+
+	// ExpireIdempotencyKeys descarta o hash de parâmetros das execuções concluídas antes do instante
+	// informado, liberando a janela para uma nova execução
+	ExpireIdempotencyKeys(ctx context.Context, before time.Time) (int64, error)
+
+	// PurgeEventDeliveries remove as entregas de webhook entregues ou descartadas antes do instante
+	// informado; entregas pendentes ou com falha são mantidas para reenvio
+	PurgeEventDeliveries(ctx context.Context, before time.Time) (int64, error)
+
+	// PurgePendingSnapshots remove os retratos das pendências de dias anteriores ao informado
+	PurgePendingSnapshots(ctx context.Context, before time.Time) (int64, error)
+
+	// PurgeExpiredClaims remove os bloqueios de boletos vencidos no instante informado
+	PurgeExpiredClaims(ctx context.Context, now time.Time) (int64, error)
+
+	// PurgeProcessedMessages remove os registros das mensagens de fila processadas antes do instante informado
+	PurgeProcessedMessages(ctx context.Context, before time.Time) (int64, error)
+
+	// PurgeAuditTrail remove os registros da trilha de auditoria (mudanças de status, conciliações
+	// desfeitas, decisões de aprovação e entradas da linha do tempo) anteriores ao instante informado
+	PurgeAuditTrail(ctx context.Context, before time.Time) (int64, error)
+
+	// AnonymizeBilletPayers apaga o documento e o nome do pagador dos boletos quitados, isto é,
+	// conciliados, antes do instante informado
+	AnonymizeBilletPayers(ctx context.Context, before time.Time) (int64, error)
+
+	// AnonymizePaymentPayers apaga o documento do pagador e a descrição dos pagamentos conciliados antes
+	// do instante informado
+	AnonymizePaymentPayers(ctx context.Context, before time.Time) (int64, error)
+}
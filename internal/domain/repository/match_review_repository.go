@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// MatchReviewRepository define as operações de repositório para as revisões manuais de matches
+type MatchReviewRepository interface {
+	// Create registra uma revisão manual
+	Create(ctx context.Context, review *model.MatchReview) error
+
+	// GetByTenant recupera as revisões de um tenant, da mais antiga para a mais recente
+	GetByTenant(ctx context.Context, tenantID string) ([]*model.MatchReview, error)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// NotificationTemplateRepository define as operações de repositório para as versões dos templates de
+// notificação dos tenants
+type NotificationTemplateRepository interface {
+	// Create grava uma nova versão do template, atribuindo a ela o número seguinte ao da última versão
+	Create(ctx context.Context, notificationTemplate *model.NotificationTemplate) error
+
+	// GetCurrent recupera a última versão do template de cada tipo de evento do tenant
+	GetCurrent(ctx context.Context, tenant string) ([]*model.NotificationTemplate, error)
+
+	// GetVersions recupera as versões do template do tenant para o tipo de evento, da mais recente à mais antiga
+	GetVersions(ctx context.Context, tenant string, eventType model.EventType) ([]*model.NotificationTemplate, error)
+
+	// GetVersion recupera uma versão do template do tenant para o tipo de evento
+	GetVersion(ctx context.Context, tenant string, eventType model.EventType, version int) (*model.NotificationTemplate, error)
+}
@@ -2,36 +2,76 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"conciliacao-bancaria/internal/domain/model"
 )
 
 // PaymentRepository define as operações de repositório para pagamentos
 type PaymentRepository interface {
-	// Create persiste um novo pagamento no banco de dados
-	Create(ctx context.Context, payment *model.Payment) error
+	// Create persiste um novo pagamento no banco de dados e retorna o registro gravado. Um ID já
+	// cadastrado retorna errors.ConflictError
+	Create(ctx context.Context, payment *model.Payment) (*model.Payment, error)
 
 	// CreateMany persiste múltiplos pagamentos no banco de dados
 	CreateMany(ctx context.Context, payments []*model.Payment) error
 
-	// GetByID recupera um pagamento pelo seu ID
+	// CreateFromMessage registra a mensagem de fila e grava o pagamento dela na mesma transação. Quando a
+	// mensagem já foi processada pelo consumidor, nada é gravado e retorna false. Um pagamento com ID já
+	// cadastrado retorna errors.ConflictError, sem registrar a mensagem
+	CreateFromMessage(ctx context.Context, message *model.ProcessedMessage, payment *model.Payment) (bool, error)
+
+	// GetByID recupera um pagamento pelo seu ID; inexistente, retorna errors.NotFoundError
 	GetByID(ctx context.Context, id string) (*model.Payment, error)
 
 	// GetAll recupera todos os pagamentos
 	GetAll(ctx context.Context) ([]*model.Payment, error)
 
+	// List recupera os pagamentos que atendem ao filtro
+	List(ctx context.Context, filter model.PaymentFilter) ([]*model.Payment, error)
+
 	// GetByBankAccount recupera pagamentos por conta bancária
 	GetByBankAccount(ctx context.Context, bankAccount string) ([]*model.Payment, error)
 
+	// GetByIDs recupera os pagamentos com os IDs informados; IDs inexistentes são ignorados
+	GetByIDs(ctx context.Context, ids []string) ([]*model.Payment, error)
+
 	// GetByReferenceID recupera pagamentos por ID de referência
 	GetByReferenceID(ctx context.Context, referenceID string) ([]*model.Payment, error)
 
-	// Update atualiza um pagamento existente
-	Update(ctx context.Context, payment *model.Payment) error
+	// GetByEntryType recupera pagamentos por tipo de lançamento (crédito/débito)
+	GetByEntryType(ctx context.Context, entryType model.EntryType) ([]*model.Payment, error)
 
-	// Delete remove um pagamento pelo ID
+	// Update atualiza um pagamento existente e retorna o registro gravado; inexistente, retorna errors.NotFoundError
+	Update(ctx context.Context, payment *model.Payment) (*model.Payment, error)
+
+	// Delete remove um pagamento pelo ID; inexistente, retorna errors.NotFoundError
 	Delete(ctx context.Context, id string) error
 
 	// FindByBankAccountAndAmount encontra pagamentos por conta bancária e valor aproximado
 	FindByBankAccountAndAmount(ctx context.Context, bankAccount string, amount float64, tolerance float64) ([]*model.Payment, error)
+
+	// FindNonReconciled encontra pagamentos que ainda não foram utilizados em conciliações
+	FindNonReconciled(ctx context.Context) ([]*model.Payment, error)
+
+	// FindNonReconciledByPeriod encontra pagamentos ainda não utilizados em conciliações feitos no período [start, end]
+	FindNonReconciledByPeriod(ctx context.Context, start, end time.Time) ([]*model.Payment, error)
+
+	// FindNonReconciledByFilter encontra pagamentos ainda não utilizados em conciliações feitos no período e
+	// das contas do filtro
+	FindNonReconciledByFilter(ctx context.Context, filter model.PendingFilter) ([]*model.Payment, error)
+
+	// StreamNonReconciled percorre os pagamentos ainda não utilizados em conciliações do filtro, ordenados
+	// por conta bancária e data de pagamento, chamando fn para cada um sem carregar todos em memória.
+	// Um erro de fn interrompe a leitura
+	StreamNonReconciled(ctx context.Context, filter model.PendingFilter, fn func(payment *model.Payment) error) error
+
+	// GetReconciledAmounts recupera os valores dos pagamentos conciliados mais recentes de uma conta
+	GetReconciledAmounts(ctx context.Context, bankAccount string, limit int) ([]float64, error)
+
+	// GetByReviewStatus recupera pagamentos pela situação de revisão manual
+	GetByReviewStatus(ctx context.Context, status model.PaymentReviewStatus) ([]*model.Payment, error)
+
+	// UpdateReviewStatus atualiza a situação de revisão manual de um pagamento
+	UpdateReviewStatus(ctx context.Context, id string, status model.PaymentReviewStatus, reason *string) error
 }
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// PendingSnapshotRepository define as operações de repositório para a série diária de pendências
+type PendingSnapshotRepository interface {
+	// Save grava o retrato do dia, substituindo o existente quando o job é reexecutado no mesmo dia
+	Save(ctx context.Context, snapshot *model.PendingSnapshot) error
+
+	// GetLatestBefore recupera o retrato mais recente anterior ao dia informado, ou nil quando não existe
+	GetLatestBefore(ctx context.Context, date time.Time) (*model.PendingSnapshot, error)
+
+	// GetSeries recupera os retratos do período, do mais antigo para o mais recente
+	GetSeries(ctx context.Context, startDate, endDate *time.Time) ([]*model.PendingSnapshot, error)
+}
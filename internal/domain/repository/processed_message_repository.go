@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// ProcessedMessageRepository define as operações de repositório para as mensagens de fila processadas. O
+// registro da mensagem é gravado junto do pagamento, por PaymentRepository.CreateFromMessage
+type ProcessedMessageRepository interface {
+	// GetByID recupera o registro da mensagem processada pelo consumidor, ou nil quando não existe
+	GetByID(ctx context.Context, consumer, messageID string) (*model.ProcessedMessage, error)
+}
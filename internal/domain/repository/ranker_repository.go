@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// RankerRepository define as operações de repositório para os pesos do ranker de candidatos
+type RankerRepository interface {
+	// GetByTenant recupera o modelo treinado de um tenant, ou nil quando não existe
+	GetByTenant(ctx context.Context, tenantID string) (*model.RankerModel, error)
+
+	// Save cria ou substitui o modelo de um tenant
+	Save(ctx context.Context, rankerModel *model.RankerModel) error
+}
@@ -23,6 +23,10 @@ type ReconciliationRepository interface {
 	// GetByBilletID recupera conciliações por ID do boleto
 	GetByBilletID(ctx context.Context, billetID string) ([]*model.Reconciliation, error)
 
+	// GetByFilter recupera as conciliações que atendem aos filtros, aplicando o filtro de período
+	// sobre a data escolhida em filter.DateField
+	GetByFilter(ctx context.Context, filter model.ReconciliationFilter) ([]*model.Reconciliation, error)
+
 	// GetByTransactionID recupera conciliações por ID da transação
 	GetByTransactionID(ctx context.Context, transactionID string) ([]*model.Reconciliation, error)
 
@@ -32,6 +36,41 @@ type ReconciliationRepository interface {
 	// Delete remove uma conciliação pelo ID
 	Delete(ctx context.Context, id string) error
 
+	// Undo remove as conciliações desfeitas, devolve os boletos e pagamentos à situação inicial e grava
+	// os registros de auditoria, na mesma transação
+	Undo(ctx context.Context, undos []*model.ReconciliationUndo) error
+
+	// Approve leva as conciliações aprovadas ao novo status, vincula os boletos e pagamentos e grava as
+	// aprovações e as mudanças de status, na mesma transação
+	Approve(ctx context.Context, approvals []*model.ReconciliationApproval) error
+
+	// Reject remove as conciliações rejeitadas, devolve os boletos e pagamentos à situação inicial e grava
+	// as rejeições, na mesma transação
+	Reject(ctx context.Context, rejections []*model.ReconciliationApproval) error
+
+	// GetApprovals recupera as aprovações e rejeições dos pareamentos de um boleto
+	GetApprovals(ctx context.Context, billetID string) ([]*model.ReconciliationApproval, error)
+
 	// GetReconciliationHistory recupera o histórico de conciliações para auditoria
 	GetReconciliationHistory(ctx context.Context, billetID string) ([]*model.Reconciliation, error)
+
+	// GetReconciliationHistoryByTransactionID recupera o histórico de conciliações de um pagamento para auditoria
+	GetReconciliationHistoryByTransactionID(ctx context.Context, transactionID string) ([]*model.Reconciliation, error)
+
+	// UpdateStatus aplica a mudança de status e de diferença de valor à conciliação e registra a
+	// mudança no histórico, na mesma transação
+	UpdateStatus(ctx context.Context, change *model.ReconciliationStatusChange) error
+
+	// GetStatusChanges recupera o histórico de mudanças de status das conciliações de um boleto
+	GetStatusChanges(ctx context.Context, billetID string) ([]*model.ReconciliationStatusChange, error)
+
+	// GetTimeToReconcileStatistics calcula os percentis do tempo entre pagamento e conciliação por conta
+	GetTimeToReconcileStatistics(ctx context.Context, filter model.TimeToReconcileFilter) ([]*model.TimeToReconcileStatistics, error)
+
+	// GetDailyStatistics calcula os totais de conciliação por dia e conta, agrupados pela data de filter.DateField
+	GetDailyStatistics(ctx context.Context, filter model.DailyStatisticsFilter) ([]*model.DailyReconciliationStatistics, error)
+
+	// GetEngineVersionStatistics calcula os totais de conciliação por dia, versão do motor de matching e
+	// conta, agrupados pela data de filter.DateField. Registros sem versão ficam de fora
+	GetEngineVersionStatistics(ctx context.Context, filter model.EngineVersionStatisticsFilter) ([]*model.EngineVersionStatistics, error)
 }
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// ReconciliationRunRepository define as operações de repositório para execuções de conciliação
+type ReconciliationRunRepository interface {
+	// Create persiste uma nova execução. Retorna false quando já existe execução com o mesmo hash de parâmetros
+	Create(ctx context.Context, run *model.ReconciliationRun) (bool, error)
+
+	// GetByID recupera uma execução pelo seu ID, ou nil quando não existe
+	GetByID(ctx context.Context, id string) (*model.ReconciliationRun, error)
+
+	// List lista as execuções, das mais recentes para as mais antigas, sem o resultado completo
+	List(ctx context.Context, filter model.ReconciliationRunFilter) ([]*model.ReconciliationRun, error)
+
+	// GetByParamsHash recupera a execução com o hash de parâmetros informado, ou nil quando não existe
+	GetByParamsHash(ctx context.Context, paramsHash string) (*model.ReconciliationRun, error)
+
+	// Complete grava o status final, o resultado, o término e o hash de parâmetros da execução, que as
+	// execuções com falha descartam
+	Complete(ctx context.Context, run *model.ReconciliationRun) error
+
+	// Delete remove uma execução, liberando o hash de parâmetros para uma nova tentativa
+	Delete(ctx context.Context, id string) error
+}
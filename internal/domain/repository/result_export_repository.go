@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// ResultExportRepository define as operações de repositório para os arquivos de resultado enviados ao ERP
+type ResultExportRepository interface {
+	// Create persiste o registro de um arquivo de resultado
+	Create(ctx context.Context, export *model.ResultExport) error
+
+	// NextSequence retorna o próximo número sequencial dos arquivos do layout com a chave informada
+	NextSequence(ctx context.Context, format, fileKey string) (int, error)
+
+	// GetByID recupera um arquivo de resultado com o conteúdo, ou nil quando não existe
+	GetByID(ctx context.Context, id string) (*model.ResultExport, error)
+
+	// List recupera os arquivos de resultado, sem o conteúdo, dos mais recentes para os mais antigos.
+	// Com runID informado, apenas os da execução
+	List(ctx context.Context, runID string) ([]*model.ResultExport, error)
+
+	// UpdateAttempt grava a situação e a última tentativa de envio
+	UpdateAttempt(ctx context.Context, export *model.ResultExport) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// RuleSetRepository define as operações de repositório para os conjuntos de regras de matching dos tenants
+type RuleSetRepository interface {
+	// Save cria ou substitui o conjunto de regras do tenant e da conta
+	Save(ctx context.Context, ruleSet *model.RuleSet) error
+
+	// GetByTenant recupera os conjuntos de regras de um tenant, o geral antes dos das contas
+	GetByTenant(ctx context.Context, tenant string) (model.RuleSets, error)
+
+	// Delete remove o conjunto de regras do tenant e da conta; vazia, remove o conjunto geral
+	Delete(ctx context.Context, tenant, bankAccount string) error
+}
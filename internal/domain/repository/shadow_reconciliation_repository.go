@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// ShadowReconciliationRepository define as operações de repositório para os resultados do motor shadow,
+// gravados em tabela separada das conciliações de produção
+type ShadowReconciliationRepository interface {
+	// CreateMany persiste as comparações entre o resultado de produção e o do motor shadow
+	CreateMany(ctx context.Context, comparisons []*model.ShadowReconciliation) error
+
+	// GetByRunID recupera as comparações gravadas em uma execução, ordenadas pelo boleto
+	GetByRunID(ctx context.Context, runID string) ([]*model.ShadowReconciliation, error)
+}
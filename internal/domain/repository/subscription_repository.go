@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// SubscriptionRepository define as operações de repositório para assinaturas de eventos
+type SubscriptionRepository interface {
+	// Create persiste uma nova assinatura no banco de dados
+	Create(ctx context.Context, subscription *model.Subscription) error
+
+	// GetByID recupera uma assinatura pelo seu ID
+	GetByID(ctx context.Context, id string) (*model.Subscription, error)
+
+	// GetAll recupera todas as assinaturas
+	GetAll(ctx context.Context) ([]*model.Subscription, error)
+
+	// FindActive recupera as assinaturas ativas
+	FindActive(ctx context.Context) ([]*model.Subscription, error)
+
+	// Delete remove uma assinatura pelo ID
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// TimelineRepository define as operações de repositório para as entradas gravadas da linha do tempo
+type TimelineRepository interface {
+	// Create registra uma entrada na linha do tempo
+	Create(ctx context.Context, entry *model.TimelineEntry) error
+
+	// CreateMany registra várias entradas, como as de um lote importado
+	CreateMany(ctx context.Context, entries []*model.TimelineEntry) error
+
+	// GetByEntity recupera as entradas gravadas de um boleto ou pagamento, da mais antiga para a mais recente
+	GetByEntity(ctx context.Context, entityType model.TimelineEntityType, entityID string) ([]*model.TimelineEntry, error)
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// UnappliedCreditRepository define as operações de repositório para os créditos não aplicados dos pagadores
+type UnappliedCreditRepository interface {
+	// CreateMany persiste os créditos gerados por uma execução
+	CreateMany(ctx context.Context, credits []*model.UnappliedCredit) error
+
+	// GetByID recupera um crédito, ou nil quando não existe
+	GetByID(ctx context.Context, id string) (*model.UnappliedCredit, error)
+
+	// List recupera os créditos que atendem ao filtro, dos mais recentes para os mais antigos
+	List(ctx context.Context, filter model.UnappliedCreditFilter) ([]*model.UnappliedCredit, error)
+
+	// Debit abate o valor do saldo do crédito se houver saldo suficiente. Retorna se o valor foi abatido
+	Debit(ctx context.Context, id string, amount float64) (bool, error)
+
+	// CreateApplication registra uma aplicação do saldo do crédito no histórico
+	CreateApplication(ctx context.Context, application *model.CreditApplication) error
+
+	// Apply abate o valor da aplicação do saldo do crédito, persiste a conciliação do boleto quitado e
+	// registra a aplicação, na mesma transação. Retorna false, sem gravar nada, quando o saldo não cobre o valor
+	Apply(ctx context.Context, reconciliation *model.Reconciliation, application *model.CreditApplication) (bool, error)
+
+	// GetApplications recupera o histórico de aplicações de um crédito, em ordem cronológica
+	GetApplications(ctx context.Context, creditID string) ([]model.CreditApplication, error)
+}
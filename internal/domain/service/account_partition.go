@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// accountPartition reúne os boletos e pagamentos de uma conta bancária, conciliados de forma
+// independente das demais contas, com o resultado parcial da conta
+type accountPartition struct {
+	billets              []*model.Billet
+	payments             []*model.Payment
+	reconciledBilletsMap map[string]bool
+	usedPaymentsMap      map[string]bool
+	result               *model.ReconciliationResult
+}
+
+// partitionByAccount separa os boletos e pagamentos por conta bancária, na ordem em que cada conta
+// aparece primeiro nos boletos e depois nos pagamentos, preservando a ordem dos itens dentro da conta
+func partitionByAccount(billets []*model.Billet, payments []*model.Payment) []*accountPartition {
+	var partitions []*accountPartition
+	byAccount := make(map[string]*accountPartition)
+
+	partitionOf := func(bankAccount string) *accountPartition {
+		partition, found := byAccount[bankAccount]
+		if !found {
+			partition = &accountPartition{
+				reconciledBilletsMap: make(map[string]bool),
+				usedPaymentsMap:      make(map[string]bool),
+				result: &model.ReconciliationResult{
+					ReconciledBillets: []model.ReconciledBillet{},
+					Metrics:           model.NewRunMetrics(),
+				},
+			}
+			byAccount[bankAccount] = partition
+			partitions = append(partitions, partition)
+		}
+		return partition
+	}
+
+	for _, billet := range billets {
+		partition := partitionOf(billet.BankAccount)
+		partition.billets = append(partition.billets, billet)
+	}
+	for _, payment := range payments {
+		partition := partitionOf(payment.BankAccount)
+		partition.payments = append(partition.payments, payment)
+	}
+
+	return partitions
+}
+
+// applyStrategiesByAccount aplica as estratégias a cada conta bancária separadamente, com até
+// s.concurrency contas em paralelo, e junta os resultados parciais na ordem das contas, de modo que a
+// mesma entrada produz sempre o mesmo resultado. O tempo registrado por estratégia é a soma do tempo
+// gasto em cada conta
+func (s *DefaultReconciliationService) applyStrategiesByAccount(
+	ctx context.Context,
+	billets []*model.Billet,
+	payments []*model.Payment,
+	reconciledBilletsMap map[string]bool,
+	usedPaymentsMap map[string]bool,
+	result *model.ReconciliationResult,
+) {
+	partitions := partitionByAccount(billets, payments)
+
+	workers := s.concurrency
+	if workers > len(partitions) {
+		workers = len(partitions)
+	}
+
+	jobs := make(chan *accountPartition)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for partition := range jobs {
+				s.applyStrategies(ctx, partition.billets, partition.payments, partition.reconciledBilletsMap, partition.usedPaymentsMap, partition.result)
+			}
+		}()
+	}
+
+	for _, partition := range partitions {
+		jobs <- partition
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, partition := range partitions {
+		result.ReconciledBillets = append(result.ReconciledBillets, partition.result.ReconciledBillets...)
+		result.AmbiguousReferences = append(result.AmbiguousReferences, partition.result.AmbiguousReferences...)
+		result.UnappliedCredits = append(result.UnappliedCredits, partition.result.UnappliedCredits...)
+		result.CreditApplications = append(result.CreditApplications, partition.result.CreditApplications...)
+		result.Metrics.Merge(partition.result.Metrics)
+
+		for billetID := range partition.reconciledBilletsMap {
+			reconciledBilletsMap[billetID] = true
+		}
+		for paymentID := range partition.usedPaymentsMap {
+			usedPaymentsMap[paymentID] = true
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// MaxAggregateCandidates limita os boletos em aberto da conta considerados para um pagamento na busca
+// pelo grupo de boletos, mantendo a busca exaustiva viável
+const MaxAggregateCandidates = 20
+
+// MaxAggregateGroupSize limita a quantidade de boletos quitados juntos por um único pagamento
+const MaxAggregateGroupSize = 6
+
+// aggregateStrategy concilia os pagamentos que não encontraram boleto com o grupo de boletos em aberto
+// da mesma conta cuja soma dos valores esperados na data do pagamento, com multa e juros de atraso,
+// corresponde ao valor pago dentro da tolerância. Entre os grupos possíveis, é
+// escolhido o de menor diferença, depois o com menos boletos e, por fim, o com os boletos mais antigos.
+// Os boletos do grupo são registrados com o mesmo GroupID; a diferença de valor fica no primeiro deles,
+// para não ser somada mais de uma vez
+type aggregateStrategy struct{}
+
+// aggregateCandidate é um boleto em aberto candidato ao grupo, com o valor esperado na data do pagamento
+type aggregateCandidate struct {
+	billet      *model.Billet
+	expected    float64
+	lateCharges bool
+}
+
+// Name retorna o nome da estratégia
+func (aggregateStrategy) Name() model.ConciliationStrategy {
+	return model.StrategyAggregate
+}
+
+// Match quita, com cada pagamento que não encontrou boleto, o grupo de boletos em aberto da conta cuja
+// soma corresponde ao valor pago
+func (aggregateStrategy) Match(ctx context.Context, billets []*model.Billet, payments []*model.Payment, state *MatchState) []model.ReconciledBillet {
+	var matches []model.ReconciledBillet
+
+	for _, payment := range payments {
+		if state.UsedPayments[payment.ID] {
+			continue
+		}
+
+		// A regra do meio de pagamento substitui a tolerância da estratégia e restringe as datas dos boletos
+		tolerance := paymentTolerance(state.MethodRules, payment, state.Tolerance)
+		maxAmount := payment.Amount * (1 + tolerance/100)
+
+		// Boletos em aberto da conta que cabem no pagamento, dos mais antigos para os mais recentes
+		var candidates []aggregateCandidate
+		for _, billet := range billets {
+			if state.ReconciledBillets[billet.ID] || billet.OpenAmount || billet.BankAccount != payment.BankAccount {
+				continue
+			}
+			if billet.Amount <= 0 {
+				continue
+			}
+			if !withinPaymentWindow(state.MethodRules, payment, effectivePaymentDate(state.BankRules, payment, billet), billet, 0) {
+				continue
+			}
+
+			expected, lateCharges := expectedAmountAt(state.BankRules, payment, billet)
+			if expected > maxAmount {
+				continue
+			}
+			candidates = append(candidates, aggregateCandidate{billet: billet, expected: expected, lateCharges: lateCharges})
+		}
+
+		if len(candidates) < 2 {
+			continue
+		}
+
+		sort.SliceStable(candidates, func(i, j int) bool {
+			a, b := candidates[i].billet, candidates[j].billet
+			if !a.IssuanceDate.Equal(b.IssuanceDate) {
+				return a.IssuanceDate.Before(b.IssuanceDate)
+			}
+			return a.ID < b.ID
+		})
+		if len(candidates) > MaxAggregateCandidates {
+			candidates = candidates[:MaxAggregateCandidates]
+		}
+
+		group, amountDiff := findAggregateGroup(candidates, payment.Amount, tolerance)
+		if len(group) == 0 {
+			continue
+		}
+
+		groupID := model.NewReconciliationGroupID()
+		for i, candidate := range group {
+			billet := candidate.billet
+			reconciled := model.ReconciledBillet{
+				BilletID:             billet.ID,
+				BankAccount:          billet.BankAccount,
+				TransactionID:        payment.ID,
+				ConciliationStatus:   model.AmountMatchStatus(amountDiff, candidate.lateCharges),
+				ConciliationStrategy: model.StrategyAggregate,
+				ReferenceID:          billet.ReferenceID,
+				PaymentDate:          payment.PaymentDate,
+				GroupID:              groupID,
+			}
+			if i == 0 {
+				reconciled.AmountDiff = amountDiff
+			}
+
+			matches = append(matches, reconciled)
+			state.ReconciledBillets[billet.ID] = true
+		}
+		state.UsedPayments[payment.ID] = true
+	}
+
+	return matches
+}
+
+// findAggregateGroup procura, entre os candidatos ordenados do mais antigo para o mais recente, o grupo
+// de dois ou mais boletos cuja soma dos valores esperados fica dentro da tolerância do valor pago. Retorna
+// o grupo, na ordem dos candidatos, e a diferença absoluta de valor; sem grupo possível, retorna nil
+func findAggregateGroup(candidates []aggregateCandidate, paidAmount, tolerancePercentage float64) ([]aggregateCandidate, float64) {
+	maxDiff := paidAmount * tolerancePercentage / 100
+
+	// Soma dos candidatos a partir de cada posição, para descartar ramos que não alcançam o valor pago
+	suffixSums := make([]float64, len(candidates)+1)
+	for i := len(candidates) - 1; i >= 0; i-- {
+		suffixSums[i] = suffixSums[i+1] + candidates[i].expected
+	}
+
+	var best []int
+	bestDiff := math.MaxFloat64
+	current := make([]int, 0, MaxAggregateGroupSize)
+
+	var search func(start int, sum float64)
+	search = func(start int, sum float64) {
+		if len(current) >= 2 {
+			diff := roundCents(math.Abs(sum - paidAmount))
+			// Como os candidatos são percorridos dos mais antigos para os mais recentes, o primeiro grupo
+			// encontrado com a mesma diferença e quantidade de boletos é o de boletos mais antigos
+			if diff <= maxDiff && (diff < bestDiff || (diff == bestDiff && len(current) < len(best))) {
+				best = append(best[:0], current...)
+				bestDiff = diff
+			}
+		}
+
+		// Um par com o valor exato não pode ser superado
+		if len(current) == MaxAggregateGroupSize || (bestDiff == 0 && len(best) == 2) {
+			return
+		}
+
+		for i := start; i < len(candidates); i++ {
+			next := sum + candidates[i].expected
+			if roundCents(next-paidAmount) > maxDiff {
+				continue
+			}
+			if roundCents(paidAmount-sum-suffixSums[i]) > maxDiff {
+				return
+			}
+
+			current = append(current, i)
+			search(i+1, next)
+			current = current[:len(current)-1]
+		}
+	}
+	search(0, 0)
+
+	if len(best) == 0 {
+		return nil, 0
+	}
+
+	group := make([]aggregateCandidate, 0, len(best))
+	for _, i := range best {
+		group = append(group, candidates[i])
+	}
+	return group, bestDiff
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"math"
+	"sort"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// amountWindowSlack alarga a faixa de valores da busca para absorver o arredondamento do cálculo dos
+// limites; a tolerância de cada candidato é conferida depois com a mesma conta da estratégia
+const amountWindowSlack = 1e-9
+
+// indexedBillet guarda um boleto do índice com a posição dele na lista original, usada para desempatar
+// os candidatos na mesma ordem da varredura sequencial
+type indexedBillet struct {
+	billet   *model.Billet
+	position int
+}
+
+// billetAmountIndex indexa os boletos de valor fixo em aberto por conta bancária, ordenados por valor
+// e data de emissão. Os candidatos de um pagamento ficam na faixa de valores em que a diferença
+// percentual cabe na tolerância, encontrada por busca binária em vez de percorrer todos os boletos
+type billetAmountIndex struct {
+	byAccount map[string][]indexedBillet
+
+	// irregular guarda, por conta, os boletos com valor não positivo ou com multa e juros de atraso, em
+	// que a faixa de valores do boleto não se aplica; eles são sempre avaliados
+	irregular map[string][]indexedBillet
+}
+
+// newBilletAmountIndex indexa os boletos ainda não conciliados. Boletos de valor aberto ficam fora,
+// pois sem valor fixo só conciliam pela referência
+func newBilletAmountIndex(billets []*model.Billet, reconciledBilletsMap map[string]bool) *billetAmountIndex {
+	index := &billetAmountIndex{
+		byAccount: make(map[string][]indexedBillet),
+		irregular: make(map[string][]indexedBillet),
+	}
+
+	for position, billet := range billets {
+		if reconciledBilletsMap[billet.ID] || billet.OpenAmount {
+			continue
+		}
+
+		entry := indexedBillet{billet: billet, position: position}
+		if billet.Amount > 0 && !billet.HasLateCharges() {
+			index.byAccount[billet.BankAccount] = append(index.byAccount[billet.BankAccount], entry)
+		} else {
+			index.irregular[billet.BankAccount] = append(index.irregular[billet.BankAccount], entry)
+		}
+	}
+
+	for _, entries := range index.byAccount {
+		sort.Slice(entries, func(i, j int) bool {
+			a, b := entries[i].billet, entries[j].billet
+			if a.Amount != b.Amount {
+				return a.Amount < b.Amount
+			}
+			if !a.IssuanceDate.Equal(b.IssuanceDate) {
+				return a.IssuanceDate.Before(b.IssuanceDate)
+			}
+			return entries[i].position < entries[j].position
+		})
+	}
+
+	return index
+}
+
+// forEachCandidate chama fn para os boletos da conta cujo valor pode estar dentro da tolerância do
+// valor pago. Para um boleto de valor b, a diferença |p - b| cabe em t% de b somente quando
+// p/(1+t) <= b e, com t abaixo de 100%, b <= p/(1-t). A faixa é um superconjunto: quem chama ainda
+// confere a tolerância de cada candidato
+func (i *billetAmountIndex) forEachCandidate(bankAccount string, amount, tolerancePercentage float64, fn func(billet *model.Billet, position int)) {
+	for _, entry := range i.irregular[bankAccount] {
+		fn(entry.billet, entry.position)
+	}
+
+	entries := i.byAccount[bankAccount]
+	if len(entries) == 0 {
+		return
+	}
+
+	rate := tolerancePercentage / 100
+	lower := amount / (1 + rate)
+	lower -= math.Abs(lower)*amountWindowSlack + amountWindowSlack
+
+	upper := math.Inf(1)
+	if rate < 1 {
+		upper = amount / (1 - rate)
+		upper += math.Abs(upper)*amountWindowSlack + amountWindowSlack
+	}
+
+	// Tolerância inválida (ex.: NaN): sem faixa confiável, todos os boletos da conta são avaliados
+	if math.IsNaN(lower) || math.IsNaN(upper) {
+		lower, upper = math.Inf(-1), math.Inf(1)
+	}
+
+	start := sort.Search(len(entries), func(k int) bool {
+		return entries[k].billet.Amount >= lower
+	})
+
+	for _, entry := range entries[start:] {
+		if entry.billet.Amount > upper {
+			break
+		}
+		fn(entry.billet, entry.position)
+	}
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// AuditLogger registra eventos de auditoria e de autenticação
+type AuditLogger interface {
+	Record(ctx context.Context, event *model.AuditEvent)
+}
+
+// NoopAuditLogger descarta os eventos, usado quando nenhum SIEM está configurado
+type NoopAuditLogger struct{}
+
+// Record descarta o evento
+func (NoopAuditLogger) Record(ctx context.Context, event *model.AuditEvent) {}
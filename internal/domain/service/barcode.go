@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// barcodeStrategy concilia os boletos com os pagamentos do mesmo código de barras. O banco devolve o
+// código (ou a linha digitável) do título no arquivo de liquidação, o que identifica o boleto mesmo quando
+// o pagador não informa a referência. Vem antes de reference_id na ordem padrão
+type barcodeStrategy struct{}
+
+// Name retorna o nome da estratégia
+func (barcodeStrategy) Name() model.ConciliationStrategy {
+	return model.StrategyBarcode
+}
+
+// Match concilia os boletos e pagamentos com o mesmo código de barras, dentro da tolerância de valor.
+// Códigos com mais de um candidato são resolvidos pelo valor e pela data, como em reference_id; os
+// itens que sobram seguem para as próximas estratégias
+func (barcodeStrategy) Match(ctx context.Context, billets []*model.Billet, payments []*model.Payment, state *MatchState) []model.ReconciledBillet {
+	var matches []model.ReconciledBillet
+
+	paymentsByBarcode := make(map[string][]*model.Payment)
+	for _, payment := range payments {
+		if state.UsedPayments[payment.ID] {
+			continue
+		}
+		if barcode := payment.Barcode(); barcode != "" {
+			paymentsByBarcode[barcode] = append(paymentsByBarcode[barcode], payment)
+		}
+	}
+	if len(paymentsByBarcode) == 0 {
+		return nil
+	}
+
+	// Agrupar boletos por código de barras preservando a ordem de chegada dos códigos
+	billetsByBarcode := make(map[string][]*model.Billet)
+	var barcodes []string
+	for _, billet := range billets {
+		if state.ReconciledBillets[billet.ID] {
+			continue
+		}
+
+		barcode := billet.Barcode()
+		if barcode == "" {
+			continue
+		}
+		if _, exists := billetsByBarcode[barcode]; !exists {
+			barcodes = append(barcodes, barcode)
+		}
+		billetsByBarcode[barcode] = append(billetsByBarcode[barcode], billet)
+	}
+
+	for _, barcode := range barcodes {
+		candidatePayments, found := paymentsByBarcode[barcode]
+		if !found {
+			continue
+		}
+
+		pairs := matchReferencePairs(billetsByBarcode[barcode], candidatePayments, state.Tolerance, state.BankRules, state.MethodRules)
+		for _, pair := range pairs {
+			matches = append(matches, model.ReconciledBillet{
+				BilletID:             pair.billet.ID,
+				BankAccount:          pair.billet.BankAccount,
+				TransactionID:        pair.payment.ID,
+				ConciliationStatus:   pair.status,
+				ConciliationStrategy: model.StrategyBarcode,
+				ReferenceID:          pair.billet.ReferenceID,
+				AmountDiff:           pair.amountDiff,
+				PaymentDate:          pair.payment.PaymentDate,
+				PaidAmount:           openAmountPaid(pair.billet, pair.payment),
+			})
+
+			state.ReconciledBillets[pair.billet.ID] = true
+			state.UsedPayments[pair.payment.ID] = true
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"math"
+	"time"
+)
+
+// SuggestionConfidenceThreshold é a confiança mínima para as estratégias conta/valor/data e de nome
+// aproximado conciliarem um pagamento direto; abaixo dela o pareamento fica sugerido, à espera de aprovação
+const SuggestionConfidenceThreshold = 0.8
+
+// Pesos de cada critério na confiança do pareamento
+const (
+	confidenceDateWeight       = 0.4
+	confidenceAmountWeight     = 0.3
+	confidenceUniquenessWeight = 0.3
+)
+
+// confidenceDateWindow é a diferença de data a partir da qual a proximidade não contribui mais para a
+// confiança
+const confidenceDateWindow = 60 * 24 * time.Hour
+
+// matchConfidence calcula a confiança, de 0 a 1, do boleto escolhido para um pagamento, combinando a
+// proximidade de datas, a diferença de valor em relação à tolerância e a unicidade do candidato entre os
+// boletos da conta que cabiam na tolerância. O resultado é arredondado em duas casas
+func matchConfidence(dateDiff time.Duration, amountDiffPercentage, tolerancePercentage float64, candidates int) float64 {
+	dateScore := 1 - float64(dateDiff)/float64(confidenceDateWindow)
+	if dateScore < 0 {
+		dateScore = 0
+	}
+
+	// Sem tolerância, só há candidatos com o valor exato. Boletos de valor não positivo não têm diferença
+	// percentual válida e não pontuam no valor
+	amountScore := 1.0
+	if tolerancePercentage > 0 {
+		amountScore = 1 - amountDiffPercentage/tolerancePercentage
+	}
+	if math.IsNaN(amountScore) || amountScore < 0 {
+		amountScore = 0
+	} else if amountScore > 1 {
+		amountScore = 1
+	}
+
+	uniquenessScore := 1.0
+	if candidates > 1 {
+		uniquenessScore = 1 / float64(candidates)
+	}
+
+	confidence := confidenceDateWeight*dateScore + confidenceAmountWeight*amountScore + confidenceUniquenessWeight*uniquenessScore
+	return math.Round(confidence*100) / 100
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// availableCreditsKey é a chave dos créditos disponíveis no contexto da conciliação
+type availableCreditsKey struct{}
+
+// WithAvailableCredits habilita a estratégia de créditos na conciliação, com os créditos não aplicados
+// que podem quitar boletos dos respectivos pagadores
+func WithAvailableCredits(ctx context.Context, credits []*model.UnappliedCredit) context.Context {
+	return context.WithValue(ctx, availableCreditsKey{}, credits)
+}
+
+// availableCreditsFromContext recupera os créditos disponíveis do contexto, ou nil quando a estratégia não está habilitada
+func availableCreditsFromContext(ctx context.Context) []*model.UnappliedCredit {
+	credits, _ := ctx.Value(availableCreditsKey{}).([]*model.UnappliedCredit)
+	return credits
+}
+
+// unappliedCreditStrategy quita os boletos ainda em aberto com o saldo dos créditos não aplicados do
+// mesmo pagador (customer_id) e conta, usando primeiro os créditos mais antigos. O boleto só é quitado
+// quando um crédito cobre todo o seu valor; os créditos informados não são alterados
+type unappliedCreditStrategy struct{}
+
+// Name retorna o nome da estratégia
+func (unappliedCreditStrategy) Name() model.ConciliationStrategy {
+	return model.StrategyUnappliedCredit
+}
+
+// Match quita os boletos em aberto com os créditos disponíveis no contexto da execução; sem créditos, a
+// estratégia não concilia nada
+func (unappliedCreditStrategy) Match(ctx context.Context, billets []*model.Billet, payments []*model.Payment, state *MatchState) []model.ReconciledBillet {
+	var matches []model.ReconciledBillet
+
+	credits := availableCreditsFromContext(ctx)
+	if len(credits) == 0 {
+		return nil
+	}
+
+	// Saldo disponível de cada crédito nesta execução, agrupado por pagador e conta
+	balances := make(map[string]float64, len(credits))
+	creditsByPayer := make(map[string][]*model.UnappliedCredit)
+	for _, credit := range credits {
+		if !credit.Available() {
+			continue
+		}
+
+		balances[credit.ID] = credit.Balance
+		key := splitKey(credit.PayerID, credit.BankAccount)
+		creditsByPayer[key] = append(creditsByPayer[key], credit)
+	}
+
+	for _, payerCredits := range creditsByPayer {
+		sort.SliceStable(payerCredits, func(i, j int) bool {
+			return payerCredits[i].CreatedAt.Before(payerCredits[j].CreatedAt)
+		})
+	}
+
+	for _, billet := range billets {
+		if state.ReconciledBillets[billet.ID] || billet.OpenAmount || billet.CustomerID == nil {
+			continue
+		}
+
+		for _, credit := range creditsByPayer[splitKey(*billet.CustomerID, billet.BankAccount)] {
+			if roundCents(balances[credit.ID]) < billet.Amount {
+				continue
+			}
+
+			matches = append(matches, model.ReconciledBillet{
+				BilletID:             billet.ID,
+				BankAccount:          billet.BankAccount,
+				TransactionID:        credit.OriginTransactionID,
+				ConciliationStatus:   model.StatusSuccessful,
+				ConciliationStrategy: model.StrategyUnappliedCredit,
+				ReferenceID:          billet.ReferenceID,
+				PaymentDate:          credit.CreatedAt,
+			})
+			state.CreditApplications = append(state.CreditApplications, *model.NewCreditApplication(credit.ID, billet.ID, billet.Amount, ""))
+
+			balances[credit.ID] -= billet.Amount
+			state.ReconciledBillets[billet.ID] = true
+			break
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// ERPBilletSource consulta os boletos em aberto no ERP, comparados periodicamente com a base local
+type ERPBilletSource interface {
+	// Source descreve a origem dos boletos, sem credenciais
+	Source() string
+
+	// ListOpenBillets retorna todos os boletos em aberto no ERP
+	ListOpenBillets(ctx context.Context) ([]*model.ERPBillet, error)
+}
+
+// ERPBilletFeed consulta os boletos criados ou alterados no ERP, sincronizados periodicamente com a base local
+type ERPBilletFeed interface {
+	// Source descreve a origem dos boletos, sem credenciais
+	Source() string
+
+	// ListChangedBillets retorna os boletos criados ou alterados no ERP a partir de since, inclusive, ou
+	// todos quando since é nil
+	ListChangedBillets(ctx context.Context, since *time.Time) ([]*model.ERPBilletChange, error)
+}
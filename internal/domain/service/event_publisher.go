@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// EventPublisher publica eventos de negócio para os assinantes interessados
+type EventPublisher interface {
+	Publish(ctx context.Context, events []*model.Event) error
+}
+
+// NoopEventPublisher descarta os eventos, usado quando não há assinantes configurados
+type NoopEventPublisher struct{}
+
+// Publish descarta os eventos
+func (NoopEventPublisher) Publish(ctx context.Context, events []*model.Event) error {
+	return nil
+}
+
+// MultiEventPublisher repassa os eventos a vários publicadores
+type MultiEventPublisher struct {
+	publishers []EventPublisher
+}
+
+// NewMultiEventPublisher cria uma nova instância de MultiEventPublisher
+func NewMultiEventPublisher(publishers ...EventPublisher) *MultiEventPublisher {
+	return &MultiEventPublisher{publishers: publishers}
+}
+
+// Publish repassa os eventos a todos os publicadores, mesmo quando algum falha, e retorna o primeiro erro
+func (p *MultiEventPublisher) Publish(ctx context.Context, events []*model.Event) error {
+	var firstErr error
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, events); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// ProductionEventPublisher repassa apenas os eventos do ambiente de produção: as operações do
+// ambiente sandbox não notificam assinantes nem sistemas externos
+type ProductionEventPublisher struct {
+	publisher EventPublisher
+}
+
+// NewProductionEventPublisher cria uma nova instância de ProductionEventPublisher
+func NewProductionEventPublisher(publisher EventPublisher) *ProductionEventPublisher {
+	return &ProductionEventPublisher{publisher: publisher}
+}
+
+// Publish repassa os eventos ao publicador, descartando os do ambiente sandbox
+func (p *ProductionEventPublisher) Publish(ctx context.Context, events []*model.Event) error {
+	if model.IsSandbox(ctx) {
+		return nil
+	}
+	return p.publisher.Publish(ctx, events)
+}
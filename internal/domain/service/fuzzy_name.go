@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// fuzzyNameStrategy concilia os pagamentos cuja descrição traz o nome do pagador, quando faltam chaves
+// exatas (referência, código de barras, documento). Os candidatos são os boletos da conta com valor
+// dentro da tolerância, pontuados pela similaridade entre o nome do pagador e a descrição
+type fuzzyNameStrategy struct{}
+
+// Name retorna o nome da estratégia
+func (fuzzyNameStrategy) Name() model.ConciliationStrategy {
+	return model.StrategyFuzzyName
+}
+
+// Match concilia cada pagamento com descrição com o boleto da conta de maior similaridade, desde que
+// acima da similaridade mínima; empates ficam com a menor diferença de valor e depois de data. Abaixo de
+// SuggestionConfidenceThreshold, o pareamento fica sugerido, à espera de aprovação
+func (fuzzyNameStrategy) Match(ctx context.Context, billets []*model.Billet, payments []*model.Payment, state *MatchState) []model.ReconciledBillet {
+	var matches []model.ReconciledBillet
+
+	index := newBilletAmountIndex(billets, state.ReconciledBillets)
+
+	for _, payment := range payments {
+		if state.UsedPayments[payment.ID] || payment.Description == "" {
+			continue
+		}
+
+		var bestBillet *model.Billet
+		var bestPosition int
+		var bestSimilarity float64
+		var bestAmountDiff float64 = math.MaxFloat64
+		var bestLateCharges bool
+		var bestDateDiff time.Duration = time.Duration(math.MaxInt64)
+
+		// A regra do meio de pagamento substitui a tolerância e a janela de datas da estratégia
+		tolerance := paymentTolerance(state.MethodRules, payment, state.Tolerance)
+
+		index.forEachCandidate(payment.BankAccount, payment.Amount, tolerance, func(billet *model.Billet, position int) {
+			if state.ReconciledBillets[billet.ID] || billet.PayerName == "" {
+				return
+			}
+
+			paidAt := effectivePaymentDate(state.BankRules, payment, billet)
+			amountDiff, amountDiffPercentage, lateCharges := billet.AmountDiffAt(payment.Amount, paidAt)
+			if amountDiffPercentage > tolerance {
+				return
+			}
+
+			if !withinPaymentWindow(state.MethodRules, payment, paidAt, billet, state.MaxDateDiff) {
+				return
+			}
+
+			dateDiff := paidAt.Sub(billet.IssuanceDate)
+			if dateDiff < 0 {
+				dateDiff = -dateDiff
+			}
+
+			similarity := nameSimilarity(billet.PayerName, payment.Description)
+			if similarity < state.MinSimilarity {
+				return
+			}
+
+			isBetter := bestBillet == nil ||
+				similarity > bestSimilarity ||
+				similarity == bestSimilarity && amountDiff < bestAmountDiff ||
+				similarity == bestSimilarity && amountDiff == bestAmountDiff && dateDiff < bestDateDiff ||
+				similarity == bestSimilarity && amountDiff == bestAmountDiff && dateDiff == bestDateDiff && position < bestPosition
+
+			if isBetter {
+				bestBillet = billet
+				bestPosition = position
+				bestSimilarity = similarity
+				bestAmountDiff = amountDiff
+				bestLateCharges = lateCharges
+				bestDateDiff = dateDiff
+			}
+		})
+
+		if bestBillet == nil {
+			continue
+		}
+
+		// Como na estratégia conta/valor/data, a similaridade baixa deixa o pareamento apenas sugerido
+		similarity := math.Round(bestSimilarity*100) / 100
+		status := model.AmountMatchStatus(bestAmountDiff, bestLateCharges)
+		if similarity < SuggestionConfidenceThreshold {
+			status = model.StatusSuggested
+		}
+
+		matches = append(matches, model.ReconciledBillet{
+			BilletID:             bestBillet.ID,
+			BankAccount:          bestBillet.BankAccount,
+			TransactionID:        payment.ID,
+			ConciliationStatus:   status,
+			ConciliationStrategy: model.StrategyFuzzyName,
+			ReferenceID:          bestBillet.ReferenceID,
+			AmountDiff:           bestAmountDiff,
+			PaymentDate:          payment.PaymentDate,
+			PaidAmount:           openAmountPaid(bestBillet, payment),
+			Confidence:           &similarity,
+		})
+
+		state.ReconciledBillets[bestBillet.ID] = true
+		state.UsedPayments[payment.ID] = true
+	}
+
+	return matches
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// Notifier envia notificações por um canal (e-mail, Slack, webhook, SMS etc.)
+type Notifier interface {
+	// Channel retorna o nome do canal, referenciado nas rotas de notificação dos tenants
+	Channel() string
+
+	// Notify envia a notificação pelo canal
+	Notify(ctx context.Context, notification *model.Notification) error
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// MinOutlierHistory define a quantidade mínima de valores no histórico da conta para avaliar outliers
+const MinOutlierHistory = 10
+
+// OutlierHistorySize define quantos valores recentes do histórico da conta são considerados
+const OutlierHistorySize = 500
+
+// iqrFactor define a distância (em IQRs) a partir dos quartis que caracteriza um valor extremo
+const iqrFactor = 3.0
+
+// zScoreThreshold define o z-score a partir do qual um valor é considerado extremo
+const zScoreThreshold = 3.5
+
+// magnitudeFactor define a razão em relação à mediana típica de erros de digitação de centavos/milhares
+const magnitudeFactor = 10.0
+
+// DetectAmountOutlier verifica se o valor destoa do histórico da conta, retornando o motivo.
+// Usa as cercas do intervalo interquartil (IQR); quando o histórico não tem dispersão entre os
+// quartis, recorre ao z-score e, por fim, à ordem de grandeza em relação à mediana
+func DetectAmountOutlier(history []float64, amount float64) (string, bool) {
+	if len(history) < MinOutlierHistory {
+		return "", false
+	}
+
+	sorted := append([]float64(nil), history...)
+	sort.Float64s(sorted)
+
+	q1 := percentile(sorted, 0.25)
+	q3 := percentile(sorted, 0.75)
+	median := percentile(sorted, 0.5)
+
+	if iqr := q3 - q1; iqr > 0 {
+		lower, upper := q1-iqrFactor*iqr, q3+iqrFactor*iqr
+		if amount < lower || amount > upper {
+			return fmt.Sprintf("valor %s fora do intervalo esperado para a conta (%s a %s)",
+				formatBRL(amount), formatBRL(math.Max(lower, 0)), formatBRL(upper)), true
+		}
+		return "", false
+	}
+
+	mean, stdDev := meanAndStdDev(sorted)
+	if stdDev > 0 {
+		if zScore := (amount - mean) / stdDev; math.Abs(zScore) > zScoreThreshold {
+			return fmt.Sprintf("valor %s com z-score %.1f em relação ao histórico da conta", formatBRL(amount), zScore), true
+		}
+		return "", false
+	}
+
+	if median > 0 && amount > 0 && (amount/median >= magnitudeFactor || median/amount >= magnitudeFactor) {
+		return fmt.Sprintf("valor %s difere em ordem de grandeza do valor usual da conta (%s)",
+			formatBRL(amount), formatBRL(median)), true
+	}
+
+	return "", false
+}
+
+// percentile calcula o percentil por interpolação linear de uma lista ordenada
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	position := p * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	if lower == upper {
+		return sorted[lower]
+	}
+
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(position-float64(lower))
+}
+
+// meanAndStdDev calcula a média e o desvio padrão populacional
+func meanAndStdDev(values []float64) (float64, float64) {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
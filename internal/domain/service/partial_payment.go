@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// partialPaymentStrategy concilia os boletos pagos em partes: os pagamentos ainda não utilizados com
+// a mesma conta e reference_id do boleto são somados, dos mais antigos para os mais recentes, até cobrir
+// o valor do boleto. A conciliação só acontece quando mais de um pagamento é necessário e a soma fica
+// dentro da tolerância; o boleto é registrado como parcialmente_conciliado com os pagamentos somados
+type partialPaymentStrategy struct{}
+
+// Name retorna o nome da estratégia
+func (partialPaymentStrategy) Name() model.ConciliationStrategy {
+	return model.StrategyPartialPayment
+}
+
+// Match concilia os boletos cujo valor é coberto pela soma de vários pagamentos da mesma referência
+func (partialPaymentStrategy) Match(ctx context.Context, billets []*model.Billet, payments []*model.Payment, state *MatchState) []model.ReconciledBillet {
+	var matches []model.ReconciledBillet
+
+	// Agrupar os pagamentos disponíveis por conta e referência
+	paymentsByReference := make(map[string][]*model.Payment)
+	for _, payment := range payments {
+		if state.UsedPayments[payment.ID] || payment.ReferenceID == nil || *payment.ReferenceID == "" {
+			continue
+		}
+
+		key := splitKey(*payment.ReferenceID, payment.BankAccount)
+		paymentsByReference[key] = append(paymentsByReference[key], payment)
+	}
+
+	// Somar primeiro os pagamentos mais antigos
+	for _, referencePayments := range paymentsByReference {
+		sort.SliceStable(referencePayments, func(i, j int) bool {
+			if !referencePayments[i].PaymentDate.Equal(referencePayments[j].PaymentDate) {
+				return referencePayments[i].PaymentDate.Before(referencePayments[j].PaymentDate)
+			}
+			return referencePayments[i].ID < referencePayments[j].ID
+		})
+	}
+
+	for _, billet := range billets {
+		// Boletos de valor aberto não têm valor a cobrir
+		if state.ReconciledBillets[billet.ID] || billet.OpenAmount || billet.ReferenceID == nil || *billet.ReferenceID == "" {
+			continue
+		}
+
+		referencePayments, found := paymentsByReference[splitKey(*billet.ReferenceID, billet.BankAccount)]
+		if !found {
+			continue
+		}
+
+		minimum := roundCents(billet.Amount * (1 - state.Tolerance/100))
+		var parts []*model.Payment
+		var total float64
+		for _, payment := range referencePayments {
+			if state.UsedPayments[payment.ID] {
+				continue
+			}
+
+			parts = append(parts, payment)
+			total = roundCents(total + payment.Amount)
+			if total >= minimum {
+				break
+			}
+		}
+
+		// Um único pagamento é conciliado pelas demais estratégias
+		if len(parts) < 2 || total < minimum {
+			continue
+		}
+
+		amountDiff := roundCents(math.Abs(total - billet.Amount))
+		if amountDiff/billet.Amount*100 > state.Tolerance {
+			continue
+		}
+
+		transactionIDs := make([]string, 0, len(parts))
+		for _, payment := range parts {
+			transactionIDs = append(transactionIDs, payment.ID)
+			state.UsedPayments[payment.ID] = true
+		}
+
+		// A data do pagamento é a da parte que completou o valor do boleto
+		matches = append(matches, model.ReconciledBillet{
+			BilletID:             billet.ID,
+			BankAccount:          billet.BankAccount,
+			TransactionID:        transactionIDs[0],
+			TransactionIDs:       transactionIDs,
+			ConciliationStatus:   model.StatusPartiallyReconciled,
+			ConciliationStrategy: model.StrategyPartialPayment,
+			ReferenceID:          billet.ReferenceID,
+			AmountDiff:           amountDiff,
+			PaymentDate:          parts[len(parts)-1].PaymentDate,
+		})
+		state.ReconciledBillets[billet.ID] = true
+	}
+
+	return matches
+}
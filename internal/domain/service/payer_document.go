@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// payerDocumentStrategy concilia os pagamentos sem referência com os boletos do mesmo pagador. Muitos
+// extratos trazem apenas o CPF ou CNPJ de quem pagou, o que restringe os candidatos aos boletos do
+// pagador antes da comparação por conta, valor e data
+type payerDocumentStrategy struct{}
+
+// Name retorna o nome da estratégia
+func (payerDocumentStrategy) Name() model.ConciliationStrategy {
+	return model.StrategyPayerDocument
+}
+
+// Match concilia os pagamentos sem reference_id com os boletos do mesmo documento do pagador, dentro da
+// tolerância de valor. Pagadores com mais de um candidato são resolvidos pelo valor e pela data, como em
+// reference_id; os itens que sobram seguem para as próximas estratégias
+func (payerDocumentStrategy) Match(ctx context.Context, billets []*model.Billet, payments []*model.Payment, state *MatchState) []model.ReconciledBillet {
+	var matches []model.ReconciledBillet
+
+	paymentsByDocument := make(map[string][]*model.Payment)
+	for _, payment := range payments {
+		if state.UsedPayments[payment.ID] || payment.PayerDocument == "" {
+			continue
+		}
+		if payment.ReferenceID != nil && *payment.ReferenceID != "" {
+			continue
+		}
+		paymentsByDocument[payment.PayerDocument] = append(paymentsByDocument[payment.PayerDocument], payment)
+	}
+	if len(paymentsByDocument) == 0 {
+		return nil
+	}
+
+	// Agrupar boletos por documento preservando a ordem de chegada dos pagadores
+	billetsByDocument := make(map[string][]*model.Billet)
+	var documents []string
+	for _, billet := range billets {
+		if state.ReconciledBillets[billet.ID] || billet.PayerDocument == "" {
+			continue
+		}
+		if _, exists := billetsByDocument[billet.PayerDocument]; !exists {
+			documents = append(documents, billet.PayerDocument)
+		}
+		billetsByDocument[billet.PayerDocument] = append(billetsByDocument[billet.PayerDocument], billet)
+	}
+
+	for _, document := range documents {
+		candidatePayments, found := paymentsByDocument[document]
+		if !found {
+			continue
+		}
+
+		pairs := matchReferencePairs(billetsByDocument[document], candidatePayments, state.Tolerance, state.BankRules, state.MethodRules)
+		for _, pair := range pairs {
+			matches = append(matches, model.ReconciledBillet{
+				BilletID:             pair.billet.ID,
+				BankAccount:          pair.billet.BankAccount,
+				TransactionID:        pair.payment.ID,
+				ConciliationStatus:   pair.status,
+				ConciliationStrategy: model.StrategyPayerDocument,
+				ReferenceID:          pair.billet.ReferenceID,
+				AmountDiff:           pair.amountDiff,
+				PaymentDate:          pair.payment.PaymentDate,
+				PaidAmount:           openAmountPaid(pair.billet, pair.payment),
+			})
+
+			state.ReconciledBillets[pair.billet.ID] = true
+			state.UsedPayments[pair.payment.ID] = true
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// MinRankerSamples define a quantidade mínima de revisões manuais para treinar o ranker
+const MinRankerSamples = 20
+
+// rankerEpochs define a quantidade de iterações do gradiente descendente no treino
+const rankerEpochs = 500
+
+// rankerLearningRate define a taxa de aprendizado do treino
+const rankerLearningRate = 0.1
+
+// rankerRegularization define o peso da regularização L2 aplicada no treino
+const rankerRegularization = 0.01
+
+// CandidateRanker atribui uma pontuação a um par boleto/pagamento; pontuações maiores indicam o melhor candidato
+type CandidateRanker interface {
+	Score(features model.MatchFeatures) float64
+}
+
+// candidateRankerKey é a chave do ranker no contexto da conciliação
+type candidateRankerKey struct{}
+
+// WithCandidateRanker associa um ranker ao contexto, ativando o reordenamento de candidatos ambíguos
+func WithCandidateRanker(ctx context.Context, ranker CandidateRanker) context.Context {
+	return context.WithValue(ctx, candidateRankerKey{}, ranker)
+}
+
+// candidateRankerFromContext recupera o ranker do contexto, ou nil quando não está ativo
+func candidateRankerFromContext(ctx context.Context) CandidateRanker {
+	ranker, _ := ctx.Value(candidateRankerKey{}).(CandidateRanker)
+	return ranker
+}
+
+// LogisticRanker ranqueia candidatos com uma regressão logística simples
+type LogisticRanker struct {
+	model *model.RankerModel
+}
+
+// NewLogisticRanker cria uma nova instância de LogisticRanker com os pesos informados
+func NewLogisticRanker(rankerModel *model.RankerModel) *LogisticRanker {
+	return &LogisticRanker{
+		model: rankerModel,
+	}
+}
+
+// Score retorna a probabilidade estimada de o par ser aprovado por um analista
+func (r *LogisticRanker) Score(features model.MatchFeatures) float64 {
+	x := featureVector(features)
+	z := r.model.Bias +
+		r.model.AmountDiffWeight*x[0] +
+		r.model.DaysDiffWeight*x[1] +
+		r.model.PartialReferenceWeight*x[2]
+	return sigmoid(z)
+}
+
+// TrainLogisticRanker ajusta os pesos da regressão logística por gradiente descendente
+// sobre os matches aprovados e rejeitados manualmente
+func TrainLogisticRanker(tenantID string, samples []model.RankerSample) *model.RankerModel {
+	var weights [3]float64
+	var bias float64
+	n := float64(len(samples))
+
+	for epoch := 0; epoch < rankerEpochs; epoch++ {
+		var gradWeights [3]float64
+		var gradBias float64
+
+		for _, sample := range samples {
+			x := featureVector(sample.Features)
+			prediction := sigmoid(bias + weights[0]*x[0] + weights[1]*x[1] + weights[2]*x[2])
+
+			label := 0.0
+			if sample.Approved {
+				label = 1.0
+			}
+
+			diff := prediction - label
+			for i := range x {
+				gradWeights[i] += diff * x[i]
+			}
+			gradBias += diff
+		}
+
+		for i := range weights {
+			weights[i] -= rankerLearningRate * (gradWeights[i]/n + rankerRegularization*weights[i])
+		}
+		bias -= rankerLearningRate * gradBias / n
+	}
+
+	return &model.RankerModel{
+		TenantID:               tenantID,
+		Bias:                   bias,
+		AmountDiffWeight:       weights[0],
+		DaysDiffWeight:         weights[1],
+		PartialReferenceWeight: weights[2],
+		Samples:                len(samples),
+	}
+}
+
+// ExtractMatchFeatures calcula as características de um par boleto/pagamento
+func ExtractMatchFeatures(billet *model.Billet, payment *model.Payment) model.MatchFeatures {
+	amountDiffPercentage := 0.0
+	if billet.Amount != 0 {
+		amountDiffPercentage = math.Abs(payment.Amount-billet.Amount) / billet.Amount * 100
+	}
+
+	return model.MatchFeatures{
+		AmountDiffPercentage: amountDiffPercentage,
+		DaysDiff:             absDuration(payment.PaymentDate.Sub(billet.IssuanceDate)).Hours() / 24,
+		PartialReference:     partialReferenceMatch(billet.ReferenceID, payment.ReferenceID),
+	}
+}
+
+// partialReferenceMatch verifica se uma referência contém a outra, ignorando caixa e separadores
+func partialReferenceMatch(a, b *string) bool {
+	if a == nil || b == nil {
+		return false
+	}
+
+	refA, refB := normalizeAccount(*a), normalizeAccount(*b)
+	if refA == "" || refB == "" {
+		return false
+	}
+
+	return strings.Contains(refA, refB) || strings.Contains(refB, refA)
+}
+
+// featureVector normaliza as características para escalas comparáveis
+// (diferença percentual em dezenas de pontos e diferença de dias em meses)
+func featureVector(features model.MatchFeatures) [3]float64 {
+	partialReference := 0.0
+	if features.PartialReference {
+		partialReference = 1.0
+	}
+
+	return [3]float64{
+		features.AmountDiffPercentage / 10,
+		features.DaysDiff / 30,
+		partialReference,
+	}
+}
+
+// sigmoid aplica a função logística
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
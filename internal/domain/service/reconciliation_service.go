@@ -3,14 +3,26 @@ package service
 import (
 	"context"
 	"math"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"conciliacao-bancaria/internal/domain/model"
 )
 
-// TolerancePercentage define a tolerância percentual para diferença de valores (5%)
+// TolerancePercentage define a tolerância percentual padrão para diferença de valores (5%)
 const TolerancePercentage = 5.0
 
+// MinAutoReconcileAmount define o valor mínimo padrão de um pagamento para a conciliação automática (R$ 1,00).
+// Valores menores costumam ser rendimentos ou depósitos de teste
+const MinAutoReconcileAmount = 1.0
+
+// EngineVersion identifica a versão do motor de matching gravada em cada conciliação e execução. Deve
+// mudar a cada alteração das estratégias, e o build pode substituí-la para identificar o deploy
+// (-ldflags "-X conciliacao-bancaria/internal/domain/service.EngineVersion=...")
+var EngineVersion = "v1"
+
 // ReconciliationService define as operações de serviço para conciliação
 type ReconciliationService interface {
 	// ReconcileBilletsWithPayments realiza a conciliação entre boletos e pagamentos
@@ -18,16 +30,78 @@ type ReconciliationService interface {
 
 	// GetReconciliationStatus recupera o status de conciliação de um boleto
 	GetReconciliationStatus(ctx context.Context, billetID string) (*model.Reconciliation, error)
+
+	// DefaultTolerance retorna a tolerância percentual das estratégias sem tolerância própria na execução
+	DefaultTolerance() float64
+
+	// Version retorna a versão do motor de matching
+	Version() string
 }
 
 // DefaultReconciliationService implementa ReconciliationService
 type DefaultReconciliationService struct {
-	// Dependências podem ser adicionadas aqui
+	// tolerancePercentage define a diferença percentual aceita para conciliar com valor diferente
+	tolerancePercentage float64
+
+	// maxDateDiff define a maior diferença entre a emissão do boleto e o pagamento aceita pelas
+	// estratégias com janela de datas; zero, sem limite
+	maxDateDiff time.Duration
+
+	// minAmount define o valor abaixo do qual os pagamentos ficam fora da conciliação automática
+	minAmount float64
+
+	// bankRules define as regras por banco, como o prazo de crédito aplicado às comparações de data
+	bankRules model.BankRules
+
+	// methodRules define a tolerância e a janela de datas da estratégia de conta, valor e data por meio
+	// de pagamento (ex.: PIX apenas no mesmo dia e sem diferença de valor)
+	methodRules model.PaymentMethodRules
+
+	// concurrency define quantas contas bancárias são conciliadas em paralelo; até 1, a conciliação é sequencial
+	concurrency int
 }
 
-// NewReconciliationService cria uma nova instância de DefaultReconciliationService
+// NewReconciliationService cria uma nova instância de DefaultReconciliationService com a tolerância padrão
 func NewReconciliationService() ReconciliationService {
-	return &DefaultReconciliationService{}
+	return NewReconciliationServiceWithTolerance(TolerancePercentage)
+}
+
+// NewReconciliationServiceWithTolerance cria uma nova instância de DefaultReconciliationService
+// com a tolerância percentual informada
+func NewReconciliationServiceWithTolerance(tolerancePercentage float64) ReconciliationService {
+	return NewReconciliationServiceWithLimits(tolerancePercentage, MinAutoReconcileAmount)
+}
+
+// NewReconciliationServiceWithLimits cria uma nova instância de DefaultReconciliationService com a
+// tolerância percentual e o valor mínimo de pagamento para a conciliação automática informados
+func NewReconciliationServiceWithLimits(tolerancePercentage, minAmount float64) ReconciliationService {
+	return NewReconciliationServiceWithRules(tolerancePercentage, minAmount, nil)
+}
+
+// NewReconciliationServiceWithRules cria uma nova instância de DefaultReconciliationService com a
+// tolerância, o valor mínimo e as regras por banco informados
+func NewReconciliationServiceWithRules(tolerancePercentage, minAmount float64, bankRules model.BankRules) ReconciliationService {
+	return NewReconciliationServiceWithConcurrency(tolerancePercentage, minAmount, bankRules, 1)
+}
+
+// NewReconciliationServiceWithConcurrency cria uma nova instância de DefaultReconciliationService que
+// concilia até concurrency contas bancárias em paralelo. Boletos e pagamentos de contas diferentes não
+// conciliam entre si, como na execução conta a conta do caso de uso: referências repetidas em contas
+// diferentes são resolvidas dentro de cada conta
+func NewReconciliationServiceWithConcurrency(tolerancePercentage, minAmount float64, bankRules model.BankRules, concurrency int) ReconciliationService {
+	return NewReconciliationServiceWithMethodRules(tolerancePercentage, minAmount, bankRules, model.DefaultPaymentMethodRules(), concurrency)
+}
+
+// NewReconciliationServiceWithMethodRules cria uma nova instância de DefaultReconciliationService com as
+// regras por meio de pagamento informadas no lugar das padrão; sem regras, nenhum meio tem regra própria
+func NewReconciliationServiceWithMethodRules(tolerancePercentage, minAmount float64, bankRules model.BankRules, methodRules model.PaymentMethodRules, concurrency int) ReconciliationService {
+	return &DefaultReconciliationService{
+		tolerancePercentage: tolerancePercentage,
+		minAmount:           minAmount,
+		bankRules:           bankRules,
+		methodRules:         methodRules,
+		concurrency:         concurrency,
+	}
 }
 
 // ReconcileBilletsWithPayments realiza a conciliação entre boletos e pagamentos
@@ -45,176 +119,634 @@ func (s *DefaultReconciliationService) ReconcileBilletsWithPayments(
 	result := &model.ReconciliationResult{
 		ReconciledBillets:    []model.ReconciledBillet{},
 		NonReconciledBillets: []model.Billet{},
+		Metrics:              model.NewRunMetrics(),
 	}
 
-	// 1ª Estratégia: Conciliação por reference_id
-	s.reconcileByReferenceID(billets, payments, reconciledBilletsMap, usedPaymentsMap, &result.ReconciledBillets)
+	// Débitos do extrato nunca entram na conciliação de recebíveis
+	payments, result.ExcludedPayments = filterDebitPayments(payments)
+
+	// Pagamentos suspeitos ficam retidos até a revisão manual
+	payments, result.HeldPayments = filterSuspiciousPayments(payments)
 
-	// 2ª Estratégia: Conciliação por conta, valor e data
-	s.reconcileByAccountValueDate(billets, payments, reconciledBilletsMap, usedPaymentsMap, &result.ReconciledBillets)
+	// Pagamentos abaixo do valor mínimo (rendimentos, testes) só conciliam se forçados manualmente
+	payments, result.IgnoredPayments = s.filterBelowMinimumPayments(payments)
+
+	if s.concurrency > 1 {
+		s.applyStrategiesByAccount(ctx, billets, payments, reconciledBilletsMap, usedPaymentsMap, result)
+	} else {
+		s.applyStrategies(ctx, billets, payments, reconciledBilletsMap, usedPaymentsMap, result)
+	}
 
-	// Adicionar boletos não conciliados
+	// Pareamentos com diferença de valor não são definitivos: aguardam a aprovação de um analista
+	holdDifferentValues(result.ReconciledBillets)
+
+	// Adicionar boletos não conciliados (boletos com referência ambígua são reportados à parte)
 	for _, billet := range billets {
 		if !reconciledBilletsMap[billet.ID] {
 			result.NonReconciledBillets = append(result.NonReconciledBillets, *billet)
 		}
 	}
 
+	// Gerar sugestões de correção para os boletos que não conciliaram, quando pedidas
+	if suggestionsEnabled(ctx) {
+		result.Suggestions = s.suggestCorrections(result.NonReconciledBillets, payments, usedPaymentsMap)
+	}
+
 	return result, nil
 }
 
-// GetReconciliationStatus recupera o status de conciliação de um boleto
-func (s *DefaultReconciliationService) GetReconciliationStatus(ctx context.Context, billetID string) (*model.Reconciliation, error) {
-	// Implementação completa seria feita na camada de aplicação com acesso ao repositório
-	return nil, nil
+// holdDifferentValues coloca em aguardando_aprovacao os pareamentos das estratégias com diferença de
+// valor. O boleto e o pagamento ficam reservados, mas só são vinculados quando um analista aprova
+func holdDifferentValues(reconciledBillets []model.ReconciledBillet) {
+	for i := range reconciledBillets {
+		if reconciledBillets[i].ConciliationStatus == model.StatusDifferentValue {
+			reconciledBillets[i].ConciliationStatus = model.StatusAwaitingApproval
+		}
+	}
 }
 
-// reconcileByReferenceID implementa a 1ª estratégia de conciliação
-func (s *DefaultReconciliationService) reconcileByReferenceID(
+// applyStrategies aplica as estratégias na ordem da execução aos boletos e pagamentos, acumulando no
+// resultado os boletos conciliados, as referências ambíguas, os créditos e as medições de cada estratégia.
+// Estratégias da ordem que não estão registradas são ignoradas
+func (s *DefaultReconciliationService) applyStrategies(
+	ctx context.Context,
 	billets []*model.Billet,
 	payments []*model.Payment,
 	reconciledBilletsMap map[string]bool,
 	usedPaymentsMap map[string]bool,
-	reconciledBillets *[]model.ReconciledBillet,
+	result *model.ReconciliationResult,
 ) {
-	// Mapear pagamentos por referenceID para acesso rápido
-	paymentsByReferenceID := make(map[string]*model.Payment)
-	for _, payment := range payments {
-		if payment.ReferenceID != nil && *payment.ReferenceID != "" && !usedPaymentsMap[payment.ID] {
-			paymentsByReferenceID[*payment.ReferenceID] = payment
+	state := newMatchState(reconciledBilletsMap, usedPaymentsMap)
+
+	// Estratégias na ordem da execução (padrão: reference_id, carnê, conta/valor/data, divisão, agrupamento e créditos)
+	for _, config := range strategiesFromContext(ctx) {
+		strategy, found := LookupStrategy(config.Strategy)
+		if !found {
+			continue
 		}
+		s.applyParams(state, config.Params)
+
+		started := time.Now()
+		candidates := candidatePairs(billets, payments, reconciledBilletsMap, usedPaymentsMap)
+
+		matches := strategy.Match(ctx, billets, payments, state)
+		result.ReconciledBillets = append(result.ReconciledBillets, matches...)
+
+		result.Metrics.RecordStrategy(config.Strategy, time.Since(started), candidates, len(matches))
 	}
 
-	// Tentar conciliar boletos pelo referenceID
+	result.AmbiguousReferences = append(result.AmbiguousReferences, state.AmbiguousReferences...)
+	result.UnappliedCredits = append(result.UnappliedCredits, state.UnappliedCredits...)
+	result.CreditApplications = append(result.CreditApplications, state.CreditApplications...)
+}
+
+// candidatePairs conta os pares de boleto e pagamento ainda em aberto na mesma conta, que uma estratégia
+// pode avaliar
+func candidatePairs(billets []*model.Billet, payments []*model.Payment, reconciledBilletsMap, usedPaymentsMap map[string]bool) int64 {
+	openBillets := make(map[string]int64)
 	for _, billet := range billets {
-		// Pular boletos já conciliados
-		if reconciledBilletsMap[billet.ID] {
+		if !reconciledBilletsMap[billet.ID] {
+			openBillets[billet.BankAccount]++
+		}
+	}
+
+	var pairs int64
+	for _, payment := range payments {
+		if !usedPaymentsMap[payment.ID] {
+			pairs += openBillets[payment.BankAccount]
+		}
+	}
+	return pairs
+}
+
+// filterDebitPayments separa os lançamentos de débito, retornando os créditos e os IDs dos débitos excluídos
+func filterDebitPayments(payments []*model.Payment) ([]*model.Payment, []string) {
+	var excluded []string
+	credits := make([]*model.Payment, 0, len(payments))
+
+	for _, payment := range payments {
+		if payment.IsDebit() {
+			excluded = append(excluded, payment.ID)
+			continue
+		}
+		credits = append(credits, payment)
+	}
+
+	return credits, excluded
+}
+
+// filterSuspiciousPayments separa os pagamentos suspeitos, retornando os demais e os IDs retidos
+func filterSuspiciousPayments(payments []*model.Payment) ([]*model.Payment, []string) {
+	var held []string
+	eligible := make([]*model.Payment, 0, len(payments))
+
+	for _, payment := range payments {
+		if payment.IsSuspicious() {
+			held = append(held, payment.ID)
 			continue
 		}
+		eligible = append(eligible, payment)
+	}
+
+	return eligible, held
+}
+
+// filterBelowMinimumPayments separa os pagamentos abaixo do valor mínimo da conciliação automática,
+// retornando os demais e os IDs ignorados. Pagamentos aprovados manualmente são sempre considerados
+func (s *DefaultReconciliationService) filterBelowMinimumPayments(payments []*model.Payment) ([]*model.Payment, []string) {
+	var ignored []string
+	eligible := make([]*model.Payment, 0, len(payments))
 
-		// Verificar se o boleto tem referenceID válido
-		if billet.ReferenceID == nil || *billet.ReferenceID == "" {
+	for _, payment := range payments {
+		if payment.ReviewStatus != model.ReviewStatusApproved && (payment.IsBelowMinimum() || payment.Amount < s.minAmount) {
+			ignored = append(ignored, payment.ID)
 			continue
 		}
+		eligible = append(eligible, payment)
+	}
+
+	return eligible, ignored
+}
+
+// DefaultTolerance retorna a tolerância percentual das estratégias sem tolerância própria na execução
+func (s *DefaultReconciliationService) DefaultTolerance() float64 {
+	return s.tolerancePercentage
+}
+
+// Version retorna a versão do motor de matching
+func (s *DefaultReconciliationService) Version() string {
+	return EngineVersion
+}
+
+// GetReconciliationStatus recupera o status de conciliação de um boleto
+func (s *DefaultReconciliationService) GetReconciliationStatus(ctx context.Context, billetID string) (*model.Reconciliation, error) {
+	// Implementação completa seria feita na camada de aplicação com acesso ao repositório
+	return nil, nil
+}
+
+// referenceIDStrategy implementa a 1ª estratégia de conciliação, por reference_id
+type referenceIDStrategy struct{}
+
+// Name retorna o nome da estratégia
+func (referenceIDStrategy) Name() model.ConciliationStrategy {
+	return model.StrategyReferenceID
+}
+
+// Match concilia os boletos e pagamentos com o mesmo reference_id. Referências com mais de um candidato
+// são resolvidas pelo valor e pela data, e os itens que sobram ficam marcados como ambíguos
+func (referenceIDStrategy) Match(ctx context.Context, billets []*model.Billet, payments []*model.Payment, state *MatchState) []model.ReconciledBillet {
+	var matches []model.ReconciledBillet
+
+	// Agrupar pagamentos por referenceID, mantendo todos os candidatos de cada referência
+	paymentsByReferenceID := make(map[string][]*model.Payment)
+	for _, payment := range payments {
+		if payment.ReferenceID != nil && *payment.ReferenceID != "" && !state.UsedPayments[payment.ID] {
+			paymentsByReferenceID[*payment.ReferenceID] = append(paymentsByReferenceID[*payment.ReferenceID], payment)
+		}
+	}
 
-		// Verificar se existe um pagamento com o mesmo referenceID
-		payment, found := paymentsByReferenceID[*billet.ReferenceID]
+	// Agrupar boletos por referenceID preservando a ordem de chegada das referências
+	billetsByReferenceID := make(map[string][]*model.Billet)
+	var referenceIDs []string
+	for _, billet := range billets {
+		// Pular boletos já conciliados ou sem referenceID válido
+		if state.ReconciledBillets[billet.ID] || billet.ReferenceID == nil || *billet.ReferenceID == "" {
+			continue
+		}
+
+		referenceID := *billet.ReferenceID
+		if _, exists := billetsByReferenceID[referenceID]; !exists {
+			referenceIDs = append(referenceIDs, referenceID)
+		}
+		billetsByReferenceID[referenceID] = append(billetsByReferenceID[referenceID], billet)
+	}
+
+	for _, referenceID := range referenceIDs {
+		// Verificar se existem pagamentos com o mesmo referenceID
+		candidatePayments, found := paymentsByReferenceID[referenceID]
 		if !found {
 			continue
 		}
+		candidateBillets := billetsByReferenceID[referenceID]
+
+		// Resolver os pares da referência, desempatando por valor e data quando houver mais de um candidato
+		// A referência identifica o boleto: as regras por meio de pagamento não restringem o par
+		pairs := matchReferencePairs(candidateBillets, candidatePayments, state.Tolerance, state.BankRules, nil)
+		for _, pair := range pairs {
+			// Adicionar à lista de boletos conciliados
+			matches = append(matches, model.ReconciledBillet{
+				BilletID:             pair.billet.ID,
+				BankAccount:          pair.billet.BankAccount,
+				TransactionID:        pair.payment.ID,
+				ConciliationStatus:   pair.status,
+				ConciliationStrategy: model.StrategyReferenceID,
+				ReferenceID:          pair.billet.ReferenceID,
+				AmountDiff:           pair.amountDiff,
+				PaymentDate:          pair.payment.PaymentDate,
+				PaidAmount:           openAmountPaid(pair.billet, pair.payment),
+			})
 
-		// Calcular diferença de valor
-		amountDiff := math.Abs(payment.Amount - billet.Amount)
-		amountDiffPercentage := (amountDiff / billet.Amount) * 100
+			// Marcar boleto e pagamento como utilizados
+			state.ReconciledBillets[pair.billet.ID] = true
+			state.UsedPayments[pair.payment.ID] = true
+		}
+
+		// Referência sem conflito: os itens que sobraram seguem para as próximas estratégias
+		if len(candidateBillets) == 1 && len(candidatePayments) == 1 {
+			continue
+		}
+
+		// Os demais itens da referência ficam marcados como ambíguos para revisão manual
+		ambiguous := model.AmbiguousReference{
+			ReferenceID:        referenceID,
+			ConciliationStatus: model.StatusAmbiguousRef,
+		}
+		for _, billet := range candidateBillets {
+			if !state.ReconciledBillets[billet.ID] {
+				ambiguous.BilletIDs = append(ambiguous.BilletIDs, billet.ID)
+			}
+		}
+		for _, payment := range candidatePayments {
+			if !state.UsedPayments[payment.ID] {
+				ambiguous.TransactionIDs = append(ambiguous.TransactionIDs, payment.ID)
+			}
+		}
+
+		if len(ambiguous.BilletIDs) == 0 && len(ambiguous.TransactionIDs) == 0 {
+			continue
+		}
+
+		// Itens ambíguos não participam das demais estratégias até serem revisados
+		for _, billetID := range ambiguous.BilletIDs {
+			state.ReconciledBillets[billetID] = true
+		}
+		for _, transactionID := range ambiguous.TransactionIDs {
+			state.UsedPayments[transactionID] = true
+		}
+
+		state.AmbiguousReferences = append(state.AmbiguousReferences, ambiguous)
+	}
+
+	return matches
+}
+
+// referencePair representa um par boleto/pagamento candidato dentro de uma mesma referência
+type referencePair struct {
+	billet     *model.Billet
+	payment    *model.Payment
+	amountDiff float64
+	dateDiff   time.Duration
+	status     model.ConciliationStatus
+}
+
+// matchReferencePairs escolhe os melhores pares entre boletos e pagamentos de uma mesma referência.
+// Os pagamentos de meios com regra própria usam a tolerância e a janela de datas da regra.
+// Critérios de desempate:
+// 1. Menor diferença de valor
+// 2. Menor diferença entre data de emissão e data de pagamento
+// 3. Boleto mais antigo
+func matchReferencePairs(billets []*model.Billet, payments []*model.Payment, tolerancePercentage float64, bankRules model.BankRules, methodRules model.PaymentMethodRules) []referencePair {
+	var candidates []referencePair
+	for _, billet := range billets {
+		for _, payment := range payments {
+			// Calcular diferença de valor contra o valor esperado na data do pagamento, descontado o prazo
+			// de crédito do banco (boletos de valor aberto aceitam qualquer valor)
+			paidAt := effectivePaymentDate(bankRules, payment, billet)
+			amountDiff, amountDiffPercentage, lateCharges := billet.AmountDiffAt(payment.Amount, paidAt)
 
-		// Determinar status de conciliação
-		var status model.ConciliationStatus
-		if amountDiff == 0 {
-			status = model.StatusSuccessful
-		} else if amountDiffPercentage <= TolerancePercentage {
-			status = model.StatusDifferentValue
-		} else {
 			// Se a diferença de valor for muito grande, não concilia por referenceID
+			if amountDiff != 0 && amountDiffPercentage > paymentTolerance(methodRules, payment, tolerancePercentage) {
+				continue
+			}
+
+			if !withinPaymentWindow(methodRules, payment, paidAt, billet, 0) {
+				continue
+			}
+			status := model.AmountMatchStatus(amountDiff, lateCharges)
+
+			// Calcular diferença de data
+			dateDiff := paidAt.Sub(billet.IssuanceDate)
+			if dateDiff < 0 {
+				dateDiff = -dateDiff
+			}
+
+			candidates = append(candidates, referencePair{
+				billet:     billet,
+				payment:    payment,
+				amountDiff: amountDiff,
+				dateDiff:   dateDiff,
+				status:     status,
+			})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.amountDiff != b.amountDiff {
+			return a.amountDiff < b.amountDiff
+		}
+		if a.dateDiff != b.dateDiff {
+			return a.dateDiff < b.dateDiff
+		}
+		return a.billet.IssuanceDate.Before(b.billet.IssuanceDate)
+	})
+
+	// Selecionar os pares de forma gulosa, sem reutilizar boletos ou pagamentos
+	usedBillets := make(map[string]bool)
+	usedPayments := make(map[string]bool)
+	var pairs []referencePair
+	for _, candidate := range candidates {
+		if usedBillets[candidate.billet.ID] || usedPayments[candidate.payment.ID] {
 			continue
 		}
 
-		// Adicionar à lista de boletos conciliados
-		*reconciledBillets = append(*reconciledBillets, model.ReconciledBillet{
-			BilletID:             billet.ID,
-			BankAccount:          billet.BankAccount,
+		usedBillets[candidate.billet.ID] = true
+		usedPayments[candidate.payment.ID] = true
+		pairs = append(pairs, candidate)
+	}
+
+	return pairs
+}
+
+// installmentStrategy concilia pagamentos de carnê, que informam apenas a referência base,
+// com a parcela em aberto cuja data de emissão corresponde ao pagamento
+type installmentStrategy struct{}
+
+// Name retorna o nome da estratégia
+func (installmentStrategy) Name() model.ConciliationStrategy {
+	return model.StrategyInstallment
+}
+
+// Match concilia os pagamentos com a referência base do carnê com a parcela em aberto pela data
+func (installmentStrategy) Match(ctx context.Context, billets []*model.Billet, payments []*model.Payment, state *MatchState) []model.ReconciledBillet {
+	var matches []model.ReconciledBillet
+
+	// Mapear parcelas em aberto pela referência base do carnê
+	installmentsByBaseReference := make(map[string][]*model.Billet)
+	for _, billet := range billets {
+		if state.ReconciledBillets[billet.ID] || billet.InstallmentNumber == nil || billet.ReferenceID == nil {
+			continue
+		}
+
+		baseReference := installmentBaseReference(*billet.ReferenceID, *billet.InstallmentNumber)
+		if baseReference == *billet.ReferenceID {
+			continue
+		}
+		installmentsByBaseReference[baseReference] = append(installmentsByBaseReference[baseReference], billet)
+	}
+
+	if len(installmentsByBaseReference) == 0 {
+		return nil
+	}
+
+	for _, payment := range payments {
+		if state.UsedPayments[payment.ID] || payment.ReferenceID == nil || *payment.ReferenceID == "" {
+			continue
+		}
+
+		installments, found := installmentsByBaseReference[*payment.ReferenceID]
+		if !found {
+			continue
+		}
+
+		var bestBillet *model.Billet
+		var bestAmountDiff float64
+		var bestLateCharges bool
+		var bestIssued bool
+		var minDateDiff time.Duration
+
+		for _, billet := range installments {
+			// Pular parcelas já quitadas nesta execução
+			if state.ReconciledBillets[billet.ID] {
+				continue
+			}
+
+			// Verificar se o valor esperado na data do pagamento está dentro da tolerância
+			paidAt := effectivePaymentDate(state.BankRules, payment, billet)
+			amountDiff, amountDiffPercentage, lateCharges := billet.AmountDiffAt(payment.Amount, paidAt)
+			if amountDiffPercentage > state.Tolerance {
+				continue
+			}
+
+			// Critérios para escolher a parcela:
+			// 1. Priorizar parcelas já emitidas na data do pagamento
+			// 2. Priorizar a menor diferença entre emissão e pagamento
+			// 3. Em caso de empate, priorizar a parcela de menor número
+			issued := !billet.IssuanceDate.After(paidAt)
+			dateDiff := absDuration(paidAt.Sub(billet.IssuanceDate))
+
+			isBetter := false
+			if bestBillet == nil {
+				isBetter = true
+			} else if issued != bestIssued {
+				isBetter = issued
+			} else if dateDiff < minDateDiff {
+				isBetter = true
+			} else if dateDiff == minDateDiff && *billet.InstallmentNumber < *bestBillet.InstallmentNumber {
+				isBetter = true
+			}
+
+			if isBetter {
+				bestBillet = billet
+				bestAmountDiff = amountDiff
+				bestLateCharges = lateCharges
+				bestIssued = issued
+				minDateDiff = dateDiff
+			}
+		}
+
+		if bestBillet == nil {
+			continue
+		}
+
+		matches = append(matches, model.ReconciledBillet{
+			BilletID:             bestBillet.ID,
+			BankAccount:          bestBillet.BankAccount,
 			TransactionID:        payment.ID,
-			ConciliationStatus:   status,
-			ConciliationStrategy: model.StrategyReferenceID,
-			ReferenceID:          billet.ReferenceID,
-			AmountDiff:           amountDiff,
+			ConciliationStatus:   model.AmountMatchStatus(bestAmountDiff, bestLateCharges),
+			ConciliationStrategy: model.StrategyInstallment,
+			ReferenceID:          bestBillet.ReferenceID,
+			AmountDiff:           bestAmountDiff,
+			PaymentDate:          payment.PaymentDate,
+			PaidAmount:           openAmountPaid(bestBillet, payment),
 		})
 
 		// Marcar boleto e pagamento como utilizados
-		reconciledBilletsMap[billet.ID] = true
-		usedPaymentsMap[payment.ID] = true
+		state.ReconciledBillets[bestBillet.ID] = true
+		state.UsedPayments[payment.ID] = true
 	}
+
+	return matches
 }
 
-// reconcileByAccountValueDate implementa a 2ª estratégia de conciliação
-func (s *DefaultReconciliationService) reconcileByAccountValueDate(
-	billets []*model.Billet,
-	payments []*model.Payment,
-	reconciledBilletsMap map[string]bool,
-	usedPaymentsMap map[string]bool,
-	reconciledBillets *[]model.ReconciledBillet,
-) {
+// effectivePaymentDate estima a data do pagamento a partir da data do crédito, descontando os dias
+// úteis que o banco leva para creditar (ex.: um dia útil para bancos D+1)
+func effectivePaymentDate(bankRules model.BankRules, payment *model.Payment, billet *model.Billet) time.Time {
+	return bankRules.For(model.PaymentBankCode(payment, billet)).PaymentDate(payment.PaymentDate)
+}
+
+// expectedAmountAt retorna o valor esperado do boleto na data efetiva do pagamento e se ele inclui multa
+// e juros de atraso
+func expectedAmountAt(bankRules model.BankRules, payment *model.Payment, billet *model.Billet) (float64, bool) {
+	paidAt := effectivePaymentDate(bankRules, payment, billet)
+	return billet.ExpectedAmount(paidAt), billet.HasLateCharges() && billet.DaysLate(paidAt) > 0
+}
+
+// openAmountPaid retorna o valor pago a registrar como valor do título quando o boleto é de valor aberto
+func openAmountPaid(billet *model.Billet, payment *model.Payment) *float64 {
+	if !billet.OpenAmount {
+		return nil
+	}
+
+	paidAmount := payment.Amount
+	return &paidAmount
+}
+
+// installmentBaseReference remove o sufixo de parcela do reference_id de um carnê
+// (ex.: "CARNE123-02" com parcela 2 resulta em "CARNE123"). Se o sufixo não corresponder
+// ao número da parcela, a referência é retornada sem alterações.
+func installmentBaseReference(referenceID string, installmentNumber int) string {
+	end := len(referenceID)
+	start := end
+	for start > 0 && referenceID[start-1] >= '0' && referenceID[start-1] <= '9' {
+		start--
+	}
+
+	if start == end {
+		return referenceID
+	}
+
+	suffix, err := strconv.Atoi(referenceID[start:end])
+	if err != nil || suffix != installmentNumber {
+		return referenceID
+	}
+
+	base := strings.TrimRight(referenceID[:start], "-/_. ")
+	if base == "" {
+		return referenceID
+	}
+
+	return base
+}
+
+// accountValueDateStrategy implementa a 2ª estratégia de conciliação, por conta, valor e data. Os
+// boletos em aberto são indexados por conta e valor, e cada pagamento avalia apenas os boletos da sua
+// conta na faixa de valores da tolerância, o que mantém a execução próxima de O(n log n) em vez de O(n×m).
+// Os pagamentos de meios com regra própria usam a tolerância e a janela de datas da regra
+type accountValueDateStrategy struct{}
+
+// Name retorna o nome da estratégia
+func (accountValueDateStrategy) Name() model.ConciliationStrategy {
+	return model.StrategyAccountAmountDate
+}
+
+// Match concilia cada pagamento com o boleto em aberto da conta dentro da tolerância e com a data mais
+// próxima; candidatos ambíguos podem ser reordenados pelo ranker do tenant
+func (accountValueDateStrategy) Match(ctx context.Context, billets []*model.Billet, payments []*model.Payment, state *MatchState) []model.ReconciledBillet {
+	var matches []model.ReconciledBillet
+
+	ranker := candidateRankerFromContext(ctx)
+	index := newBilletAmountIndex(billets, state.ReconciledBillets)
+
 	// Para cada pagamento não utilizado
 	for _, payment := range payments {
-		if usedPaymentsMap[payment.ID] {
+		if state.UsedPayments[payment.ID] {
 			continue
 		}
 
 		var bestBillet *model.Billet
+		var bestPosition int
 		var minDateDiff time.Duration = time.Duration(math.MaxInt64)
 		var bestAmountDiff float64 = math.MaxFloat64
-
-		// Procurar o melhor boleto para este pagamento
-		for _, billet := range billets {
-			// Pular boletos já conciliados
-			if reconciledBilletsMap[billet.ID] {
-				continue
-			}
-
-			// Verificar se conta bancária corresponde
-			if billet.BankAccount != payment.BankAccount {
-				continue
+		var bestAmountDiffPercentage float64
+		var bestLateCharges bool
+		var bestScore float64
+		var candidates int
+
+		// A regra do meio de pagamento substitui a tolerância e a janela de datas da estratégia
+		tolerance := paymentTolerance(state.MethodRules, payment, state.Tolerance)
+
+		// Procurar o melhor boleto para este pagamento entre os candidatos da conta
+		index.forEachCandidate(payment.BankAccount, payment.Amount, tolerance, func(billet *model.Billet, position int) {
+			// Pular boletos conciliados por pagamentos anteriores
+			if state.ReconciledBillets[billet.ID] {
+				return
 			}
 
-			// Calcular diferença de valor
-			amountDiff := math.Abs(payment.Amount - billet.Amount)
-			amountDiffPercentage := (amountDiff / billet.Amount) * 100
+			// Calcular diferença de valor contra o valor esperado na data do pagamento, descontado o prazo
+			// de crédito do banco
+			paymentDate := effectivePaymentDate(state.BankRules, payment, billet)
+			amountDiff, amountDiffPercentage, lateCharges := billet.AmountDiffAt(payment.Amount, paymentDate)
 
 			// Verificar se está dentro da tolerância
-			if amountDiffPercentage > TolerancePercentage {
-				continue
+			if amountDiffPercentage > tolerance {
+				return
 			}
 
 			// Calcular diferença de data
-			dateDiff := payment.PaymentDate.Sub(billet.IssuanceDate)
+			dateDiff := paymentDate.Sub(billet.IssuanceDate)
 			if dateDiff < 0 {
 				dateDiff = -dateDiff
 			}
 
+			// Boletos fora da janela de datas do meio de pagamento, contada em dias corridos, ou da
+			// estratégia não são candidatos
+			if !withinPaymentWindow(state.MethodRules, payment, paymentDate, billet, state.MaxDateDiff) {
+				return
+			}
+			candidates++
+
+			// Pontuação do ranker, quando ativo para o tenant
+			var score float64
+			if ranker != nil {
+				score = ranker.Score(ExtractMatchFeatures(billet, payment))
+			}
+
 			// Critérios para escolher o melhor boleto:
+			// 0. Com o ranker ativo, priorizar a maior pontuação
 			// 1. Priorizar a menor diferença de data
 			// 2. Em caso de empate, priorizar a menor diferença de valor
 			// 3. Em caso de empate, priorizar o boleto mais antigo
+			// 4. Em caso de empate, priorizar o boleto que vem antes na lista
 			isBetter := false
 
 			if bestBillet == nil {
 				isBetter = true
+			} else if ranker != nil && score != bestScore {
+				isBetter = score > bestScore
 			} else if dateDiff < minDateDiff {
 				isBetter = true
 			} else if dateDiff == minDateDiff && amountDiff < bestAmountDiff {
 				isBetter = true
 			} else if dateDiff == minDateDiff && amountDiff == bestAmountDiff && billet.IssuanceDate.Before(bestBillet.IssuanceDate) {
 				isBetter = true
+			} else if dateDiff == minDateDiff && amountDiff == bestAmountDiff && billet.IssuanceDate.Equal(bestBillet.IssuanceDate) && position < bestPosition {
+				isBetter = true
 			}
 
 			if isBetter {
 				bestBillet = billet
+				bestPosition = position
 				minDateDiff = dateDiff
 				bestAmountDiff = amountDiff
+				bestAmountDiffPercentage = amountDiffPercentage
+				bestLateCharges = lateCharges
+				bestScore = score
 			}
-		}
+		})
 
 		// Se encontrou um boleto para conciliar
 		if bestBillet != nil {
-			// Determinar status de conciliação
-			var status model.ConciliationStatus
-			if bestAmountDiff == 0 {
-				status = model.StatusSuccessful
-			} else {
-				status = model.StatusDifferentValue
+			// Determinar status de conciliação; com baixa confiança, o pareamento fica apenas sugerido
+			confidence := matchConfidence(minDateDiff, bestAmountDiffPercentage, tolerance, candidates)
+
+			status := model.AmountMatchStatus(bestAmountDiff, bestLateCharges)
+			if confidence < SuggestionConfidenceThreshold {
+				status = model.StatusSuggested
 			}
 
 			// Adicionar à lista de boletos conciliados
-			*reconciledBillets = append(*reconciledBillets, model.ReconciledBillet{
+			matches = append(matches, model.ReconciledBillet{
 				BilletID:             bestBillet.ID,
 				BankAccount:          bestBillet.BankAccount,
 				TransactionID:        payment.ID,
@@ -222,11 +754,15 @@ func (s *DefaultReconciliationService) reconcileByAccountValueDate(
 				ConciliationStrategy: model.StrategyAccountAmountDate,
 				ReferenceID:          bestBillet.ReferenceID,
 				AmountDiff:           bestAmountDiff,
+				PaymentDate:          payment.PaymentDate,
+				Confidence:           &confidence,
 			})
 
 			// Marcar boleto e pagamento como utilizados
-			reconciledBilletsMap[bestBillet.ID] = true
-			usedPaymentsMap[payment.ID] = true
+			state.ReconciledBillets[bestBillet.ID] = true
+			state.UsedPayments[payment.ID] = true
 		}
 	}
+
+	return matches
 }
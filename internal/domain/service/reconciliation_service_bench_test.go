@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// benchmarkScenario descreve um cenário sintético de carga para o motor de conciliação
+type benchmarkScenario struct {
+	name    string
+	records int
+}
+
+// benchmarkScenarios lista os cenários de medição
+var benchmarkScenarios = []benchmarkScenario{
+	{name: "10k", records: 10_000},
+	{name: "100k", records: 100_000},
+	{name: "1M", records: 1_000_000},
+}
+
+// accountsPerScenario define quantos registros, em média, compartilham a mesma conta bancária
+const accountsPerScenario = 20
+
+// generateScenario gera boletos e pagamentos sintéticos e determinísticos para um cenário.
+// A distribuição mistura pagamentos com reference_id, pagamentos casados apenas por
+// conta/valor/data, pagamentos com diferença dentro da tolerância e boletos órfãos.
+func generateScenario(records int, seed int64) ([]*model.Billet, []*model.Payment) {
+	rng := rand.New(rand.NewSource(seed))
+	baseDate := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	accounts := records/accountsPerScenario + 1
+
+	billets := make([]*model.Billet, 0, records)
+	payments := make([]*model.Payment, 0, records)
+
+	for i := 0; i < records; i++ {
+		bankAccount := fmt.Sprintf("C%06d", rng.Intn(accounts))
+		amount := float64(rng.Intn(500000)+100) / 100
+		issuanceDate := baseDate.Add(time.Duration(rng.Intn(365*24)) * time.Hour)
+		paymentDate := issuanceDate.Add(time.Duration(rng.Intn(15*24)) * time.Hour)
+
+		var billetReference, paymentReference *string
+		paymentAmount := amount
+
+		switch kind := rng.Intn(10); {
+		case kind < 4:
+			// Conciliação direta por reference_id
+			reference := fmt.Sprintf("REF%08d", i)
+			billetReference, paymentReference = &reference, &reference
+		case kind < 7:
+			// Conciliação por conta, valor e data
+		case kind < 9:
+			// Diferença de valor dentro da tolerância
+			paymentAmount = amount * (1 - float64(rng.Intn(5))/100)
+		default:
+			// Boleto órfão, sem pagamento correspondente
+			billets = append(billets, model.NewBillet(fmt.Sprintf("B%08d", i), bankAccount, amount, issuanceDate, nil))
+			continue
+		}
+
+		billets = append(billets, model.NewBillet(fmt.Sprintf("B%08d", i), bankAccount, amount, issuanceDate, billetReference))
+		payments = append(payments, model.NewPayment(fmt.Sprintf("T%08d", i), bankAccount, paymentAmount, paymentDate, paymentReference))
+	}
+
+	// Embaralhar os pagamentos para não favorecer a ordem de emissão
+	rng.Shuffle(len(payments), func(i, j int) {
+		payments[i], payments[j] = payments[j], payments[i]
+	})
+
+	return billets, payments
+}
+
+// shortScenarioRecords limita, com -short, o tamanho dos cenários executados
+const shortScenarioRecords = 100_000
+
+// runScenarios executa bench em um sub-benchmark por cenário, com os dados gerados fora da medição. Com
+// -short, os cenários acima de shortRecords registros são ignorados
+func runScenarios(b *testing.B, shortRecords int, bench func(b *testing.B, billets []*model.Billet, payments []*model.Payment)) {
+	for _, scenario := range benchmarkScenarios {
+		b.Run(scenario.name, func(b *testing.B) {
+			if testing.Short() && scenario.records > shortRecords {
+				b.Skip("cenário grande ignorado com -short")
+			}
+
+			billets, payments := generateScenario(scenario.records, 42)
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			bench(b, billets, payments)
+		})
+	}
+}
+
+// reconcileScenario concilia o cenário b.N vezes com o serviço informado e reporta, à parte do total
+// da execução, o tempo de cada estratégia aplicada
+func reconcileScenario(ctx context.Context, b *testing.B, reconciliationService ReconciliationService, billets []*model.Billet, payments []*model.Payment) {
+	strategyDurations := make(map[model.ConciliationStrategy]float64)
+	for i := 0; i < b.N; i++ {
+		result, err := reconciliationService.ReconcileBilletsWithPayments(ctx, billets, payments)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for strategy, metrics := range result.Metrics.Strategies {
+			strategyDurations[strategy] += metrics.DurationMs
+		}
+	}
+
+	for strategy, duration := range strategyDurations {
+		b.ReportMetric(duration/float64(b.N), string(strategy)+"-ms/op")
+	}
+}
+
+// BenchmarkReconcileBilletsWithPayments mede o motor de conciliação com a ordem padrão das estratégias.
+//
+// Uso:
+//
+//	go test ./internal/domain/service -run '^$' -bench ReconcileBilletsWithPayments -short
+func BenchmarkReconcileBilletsWithPayments(b *testing.B) {
+	reconciliationService := NewReconciliationService()
+
+	runScenarios(b, shortScenarioRecords, func(b *testing.B, billets []*model.Billet, payments []*model.Payment) {
+		reconcileScenario(context.Background(), b, reconciliationService, billets, payments)
+	})
+}
+
+// BenchmarkReconcileBilletsWithPaymentsConcurrent mede o motor conciliando as contas bancárias em
+// paralelo, com 2, 4 e 8 contas simultâneas
+func BenchmarkReconcileBilletsWithPaymentsConcurrent(b *testing.B) {
+	for _, concurrency := range []int{2, 4, 8} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			reconciliationService := NewReconciliationServiceWithConcurrency(
+				TolerancePercentage, MinAutoReconcileAmount, nil, concurrency)
+
+			runScenarios(b, shortScenarioRecords, func(b *testing.B, billets []*model.Billet, payments []*model.Payment) {
+				reconcileScenario(context.Background(), b, reconciliationService, billets, payments)
+			})
+		})
+	}
+}
+
+// BenchmarkStrategy mede cada estratégia automática isolada, aplicada sozinha sobre os cenários, o que
+// separa o custo dela do das demais. Sem as estratégias anteriores, o agrupamento de boletos recebe todos
+// os boletos em aberto e fica quadrático; com -short, apenas o cenário de 10k é executado.
+//
+// Uso:
+//
+//	go test ./internal/domain/service -run '^$' -bench 'Strategy/conta_valor_data' -short
+func BenchmarkStrategy(b *testing.B) {
+	reconciliationService := NewReconciliationService()
+
+	for _, strategy := range model.DefaultStrategyOrder {
+		b.Run(string(strategy), func(b *testing.B) {
+			ctx := WithStrategies(context.Background(), []model.StrategyConfig{{Strategy: strategy}})
+
+			runScenarios(b, 10_000, func(b *testing.B, billets []*model.Billet, payments []*model.Payment) {
+				reconcileScenario(ctx, b, reconciliationService, billets, payments)
+			})
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// ResultFileGenerator gera o arquivo de resultado de uma execução de conciliação em um layout aceito pelo ERP
+type ResultFileGenerator interface {
+	// Format retorna o nome do layout gerado (ex.: csv)
+	Format() string
+
+	// Extension retorna a extensão dos arquivos gerados, sem o ponto
+	Extension() string
+
+	// Split separa os boletos conciliados na execução pelos arquivos do layout, indexados pela chave de
+	// cada arquivo (ex.: o convênio). Layouts de arquivo único usam a chave vazia
+	Split(records []model.ResultRecord) (map[string][]model.ResultRecord, error)
+
+	// Generate gera o conteúdo de um arquivo com o número sequencial informado
+	Generate(run *model.ReconciliationRun, key string, sequence int, records []model.ResultRecord) ([]byte, error)
+}
+
+// FileSender envia arquivos a um destino externo (ex.: SFTP do ERP)
+type FileSender interface {
+	// Destination descreve o destino dos arquivos, sem credenciais
+	Destination() string
+
+	// Send envia o arquivo com o nome informado
+	Send(ctx context.Context, name string, content []byte) error
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+// accentFolding mapeia as letras acentuadas do português para a letra sem acento
+var accentFolding = map[rune]rune{
+	'á': 'a', 'à': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ç': 'c', 'ñ': 'n',
+}
+
+// normalizeText prepara nomes e descrições para a comparação: minúsculas, sem acentos e apenas letras e
+// dígitos, com as palavras separadas por um espaço
+func normalizeText(value string) string {
+	folded := strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if plain, found := accentFolding[r]; found {
+			return plain
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, value)
+
+	return strings.Join(strings.Fields(folded), " ")
+}
+
+// nameSimilarity retorna a similaridade, de 0 a 1, entre o nome do pagador e a descrição do pagamento.
+// A descrição costuma trazer prefixos do extrato (ex.: "PIX RECEBIDO"), então o nome é comparado com
+// cada trecho da descrição com o mesmo número de palavras, e vale a maior similaridade
+func nameSimilarity(name, description string) float64 {
+	nameWords := strings.Fields(normalizeText(name))
+	descriptionWords := strings.Fields(normalizeText(description))
+	if len(nameWords) == 0 || len(descriptionWords) == 0 {
+		return 0
+	}
+
+	normalizedName := strings.Join(nameWords, " ")
+	if len(descriptionWords) <= len(nameWords) {
+		return jaroWinkler(normalizedName, strings.Join(descriptionWords, " "))
+	}
+
+	var best float64
+	for start := 0; start+len(nameWords) <= len(descriptionWords); start++ {
+		window := strings.Join(descriptionWords[start:start+len(nameWords)], " ")
+		if similarity := jaroWinkler(normalizedName, window); similarity > best {
+			best = similarity
+		}
+	}
+	return best
+}
+
+// jaroWinkler calcula a similaridade de Jaro-Winkler, de 0 a 1, entre dois textos. O prefixo comum de até
+// 4 caracteres aumenta a similaridade, o que favorece nomes abreviados no fim
+func jaroWinkler(a, b string) float64 {
+	first, second := []rune(a), []rune(b)
+	if len(first) == 0 || len(second) == 0 {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	// Caracteres iguais contam como correspondência quando próximos o suficiente nos dois textos
+	longest, shortest := len(first), len(second)
+	if shortest > longest {
+		longest, shortest = shortest, longest
+	}
+	window := longest/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	firstMatched := make([]bool, len(first))
+	secondMatched := make([]bool, len(second))
+	var matches int
+	for i := range first {
+		start, end := i-window, i+window+1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(second) {
+			end = len(second)
+		}
+		for j := start; j < end; j++ {
+			if !secondMatched[j] && first[i] == second[j] {
+				firstMatched[i], secondMatched[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	// Transposições: correspondências fora de ordem, contadas pela metade
+	var transpositions, j int
+	for i := range first {
+		if !firstMatched[i] {
+			continue
+		}
+		for !secondMatched[j] {
+			j++
+		}
+		if first[i] != second[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(first)) + m/float64(len(second)) + (m-float64(transpositions)/2)/m) / 3
+
+	maxPrefix := 4
+	if shortest < maxPrefix {
+		maxPrefix = shortest
+	}
+
+	var prefix int
+	for prefix < maxPrefix && first[prefix] == second[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// splitStrategy concilia os pagamentos que não encontraram boleto com os boletos em aberto do pagador
+// identificado pelo reference_id do pagamento (customer_id dos boletos). O pagamento quita os boletos do
+// pagador na mesma conta, dos mais antigos para os mais recentes, enquanto houver valor para o valor
+// esperado de cada um na data do pagamento, com multa e juros de atraso; a sobra é registrada como crédito
+// não aplicado do pagador
+type splitStrategy struct{}
+
+// Name retorna o nome da estratégia
+func (splitStrategy) Name() model.ConciliationStrategy {
+	return model.StrategySplit
+}
+
+// Match quita os boletos do pagador com os pagamentos que não encontraram boleto, registrando a sobra
+// como crédito não aplicado
+func (splitStrategy) Match(ctx context.Context, billets []*model.Billet, payments []*model.Payment, state *MatchState) []model.ReconciledBillet {
+	var matches []model.ReconciledBillet
+
+	// Mapear boletos em aberto por pagador e conta
+	billetsByPayer := make(map[string][]*model.Billet)
+	for _, billet := range billets {
+		if state.ReconciledBillets[billet.ID] || billet.OpenAmount || billet.CustomerID == nil || *billet.CustomerID == "" {
+			continue
+		}
+
+		key := splitKey(*billet.CustomerID, billet.BankAccount)
+		billetsByPayer[key] = append(billetsByPayer[key], billet)
+	}
+
+	if len(billetsByPayer) == 0 {
+		return nil
+	}
+
+	// Quitar primeiro os boletos mais antigos do pagador
+	for _, payerBillets := range billetsByPayer {
+		sort.SliceStable(payerBillets, func(i, j int) bool {
+			if !payerBillets[i].IssuanceDate.Equal(payerBillets[j].IssuanceDate) {
+				return payerBillets[i].IssuanceDate.Before(payerBillets[j].IssuanceDate)
+			}
+			return payerBillets[i].ID < payerBillets[j].ID
+		})
+	}
+
+	for _, payment := range payments {
+		if state.UsedPayments[payment.ID] || payment.ReferenceID == nil || *payment.ReferenceID == "" {
+			continue
+		}
+
+		payerBillets, found := billetsByPayer[splitKey(*payment.ReferenceID, payment.BankAccount)]
+		if !found {
+			continue
+		}
+
+		remaining := payment.Amount
+		var paid []*model.Billet
+		var lateCharges []bool
+		for _, billet := range payerBillets {
+			if state.ReconciledBillets[billet.ID] {
+				continue
+			}
+
+			// Os boletos fora da janela de datas do meio de pagamento não são quitados pelo pagamento
+			if !withinPaymentWindow(state.MethodRules, payment, effectivePaymentDate(state.BankRules, payment, billet), billet, 0) {
+				continue
+			}
+
+			expected, late := expectedAmountAt(state.BankRules, payment, billet)
+			if roundCents(remaining) < expected {
+				break
+			}
+
+			paid = append(paid, billet)
+			lateCharges = append(lateCharges, late)
+			remaining -= expected
+		}
+
+		// Sem boleto quitado, ou com um único boleto quitado sem sobra, não há divisão
+		remaining = roundCents(remaining)
+		if len(paid) == 0 || (len(paid) == 1 && remaining == 0) {
+			continue
+		}
+
+		for i, billet := range paid {
+			matches = append(matches, model.ReconciledBillet{
+				BilletID:             billet.ID,
+				BankAccount:          billet.BankAccount,
+				TransactionID:        payment.ID,
+				ConciliationStatus:   model.AmountMatchStatus(0, lateCharges[i]),
+				ConciliationStrategy: model.StrategySplit,
+				ReferenceID:          billet.ReferenceID,
+				PaymentDate:          payment.PaymentDate,
+			})
+			state.ReconciledBillets[billet.ID] = true
+		}
+		state.UsedPayments[payment.ID] = true
+
+		if remaining > 0 {
+			state.UnappliedCredits = append(state.UnappliedCredits, *model.NewUnappliedCredit(*payment.ReferenceID, payment.BankAccount, payment.ID, remaining))
+		}
+	}
+
+	return matches
+}
+
+// splitKey identifica os boletos de um pagador em uma conta
+func splitKey(payerID, bankAccount string) string {
+	return bankAccount + "|" + payerID
+}
+
+// roundCents arredonda um valor monetário para centavos
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// Strategy define uma estratégia de matching da conciliação. Match recebe todos os boletos e pagamentos
+// da execução, deve ignorar os já conciliados ou utilizados no estado e marcar nele os que conciliar,
+// retornando os boletos conciliados pela estratégia
+type Strategy interface {
+	// Name retorna o nome da estratégia, usado na ordem das estratégias e gravado nas conciliações
+	Name() model.ConciliationStrategy
+
+	// Match concilia os boletos e pagamentos ainda em aberto no estado da execução
+	Match(ctx context.Context, billets []*model.Billet, payments []*model.Payment, state *MatchState) []model.ReconciledBillet
+}
+
+// MatchState reúne o estado da execução compartilhado entre as estratégias: os boletos e pagamentos já
+// utilizados, os resultados além dos boletos conciliados e os parâmetros da estratégia em execução
+type MatchState struct {
+	// ReconciledBillets marca os boletos já conciliados ou retirados da execução
+	ReconciledBillets map[string]bool
+
+	// UsedPayments marca os pagamentos já utilizados ou retirados da execução
+	UsedPayments map[string]bool
+
+	// AmbiguousReferences, UnappliedCredits e CreditApplications acumulam as referências ambíguas, os
+	// créditos gerados e os créditos aplicados pelas estratégias
+	AmbiguousReferences []model.AmbiguousReference
+	UnappliedCredits    []model.UnappliedCredit
+	CreditApplications  []model.CreditApplication
+
+	// Tolerance é a tolerância percentual de diferença de valor da estratégia
+	Tolerance float64
+
+	// MaxDateDiff é a maior diferença entre a emissão do boleto e o pagamento aceita pela estratégia;
+	// zero, sem limite
+	MaxDateDiff time.Duration
+
+	// BankRules são as regras por banco, como o prazo de crédito aplicado às comparações de data
+	BankRules model.BankRules
+
+	// MethodRules são as regras por meio de pagamento, que substituem a tolerância e a janela de datas
+	// das estratégias que escolhem o boleto entre candidatos
+	MethodRules model.PaymentMethodRules
+
+	// MinSimilarity é a similaridade mínima entre o nome do pagador e a descrição do pagamento nas
+	// estratégias que comparam textos
+	MinSimilarity float64
+}
+
+// paymentTolerance retorna a tolerância de valor do pagamento: a da regra do meio de pagamento, quando
+// houver, no lugar da tolerância da estratégia
+func paymentTolerance(rules model.PaymentMethodRules, payment *model.Payment, tolerance float64) float64 {
+	if rule, hasRule := rules.For(payment.PaymentMethod); hasRule && rule.Tolerance != nil {
+		return *rule.Tolerance
+	}
+	return tolerance
+}
+
+// withinPaymentWindow verifica se a data efetiva do pagamento está na janela de datas do boleto: a da regra
+// do meio de pagamento, em dias corridos, quando houver, ou maxDateDiff da estratégia (zero, sem limite)
+func withinPaymentWindow(rules model.PaymentMethodRules, payment *model.Payment, paidAt time.Time, billet *model.Billet, maxDateDiff time.Duration) bool {
+	if rule, hasRule := rules.For(payment.PaymentMethod); hasRule && rule.MaxDaysDiff != nil {
+		return model.WithinDays(paidAt, billet.IssuanceDate, *rule.MaxDaysDiff)
+	}
+
+	if maxDateDiff <= 0 {
+		return true
+	}
+
+	dateDiff := paidAt.Sub(billet.IssuanceDate)
+	if dateDiff < 0 {
+		dateDiff = -dateDiff
+	}
+	return dateDiff <= maxDateDiff
+}
+
+// newMatchState cria o estado da execução sobre os mapas de boletos conciliados e pagamentos utilizados
+func newMatchState(reconciledBilletsMap, usedPaymentsMap map[string]bool) *MatchState {
+	return &MatchState{
+		ReconciledBillets: reconciledBilletsMap,
+		UsedPayments:      usedPaymentsMap,
+	}
+}
+
+var (
+	strategyRegistryMu sync.RWMutex
+
+	// strategyRegistry reúne as estratégias disponíveis para a ordem de conciliação, pelo nome
+	strategyRegistry = map[model.ConciliationStrategy]Strategy{
+		model.StrategyBarcode:           barcodeStrategy{},
+		model.StrategyReferenceID:       referenceIDStrategy{},
+		model.StrategyInstallment:       installmentStrategy{},
+		model.StrategyPayerDocument:     payerDocumentStrategy{},
+		model.StrategyFuzzyName:         fuzzyNameStrategy{},
+		model.StrategyAccountAmountDate: accountValueDateStrategy{},
+		model.StrategySplit:             splitStrategy{},
+		model.StrategyAggregate:         aggregateStrategy{},
+		model.StrategyUnappliedCredit:   unappliedCreditStrategy{},
+		model.StrategyPartialPayment:    partialPaymentStrategy{},
+	}
+)
+
+// RegisterStrategy registra a estratégia, substituindo a de mesmo nome. Estratégias fora da ordem padrão
+// só são aplicadas nas execuções que as incluem na ordem de estratégias
+func RegisterStrategy(strategy Strategy) {
+	strategyRegistryMu.Lock()
+	defer strategyRegistryMu.Unlock()
+	strategyRegistry[strategy.Name()] = strategy
+}
+
+// LookupStrategy retorna a estratégia registrada com o nome informado
+func LookupStrategy(name model.ConciliationStrategy) (Strategy, bool) {
+	strategyRegistryMu.RLock()
+	defer strategyRegistryMu.RUnlock()
+	strategy, found := strategyRegistry[name]
+	return strategy, found
+}
+
+// RegisteredStrategies lista os nomes das estratégias registradas em ordem alfabética
+func RegisteredStrategies() []model.ConciliationStrategy {
+	strategyRegistryMu.RLock()
+	defer strategyRegistryMu.RUnlock()
+
+	names := make([]model.ConciliationStrategy, 0, len(strategyRegistry))
+	for name := range strategyRegistry {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// strategiesKey é a chave da ordem das estratégias no contexto da conciliação
+type strategiesKey struct{}
+
+// WithStrategies define a ordem das estratégias aplicadas na conciliação e os parâmetros de cada uma.
+// Estratégias fora da lista não são aplicadas
+func WithStrategies(ctx context.Context, strategies []model.StrategyConfig) context.Context {
+	return context.WithValue(ctx, strategiesKey{}, strategies)
+}
+
+// strategiesFromContext recupera a ordem das estratégias do contexto, ou a ordem padrão quando não definida
+func strategiesFromContext(ctx context.Context) []model.StrategyConfig {
+	strategies, _ := ctx.Value(strategiesKey{}).([]model.StrategyConfig)
+	if len(strategies) == 0 {
+		return model.DefaultStrategyConfigs()
+	}
+	return strategies
+}
+
+// applyParams define no estado os parâmetros próprios da estratégia, usando os do serviço para os não informados
+func (s *DefaultReconciliationService) applyParams(state *MatchState, params model.StrategyParams) {
+	state.Tolerance = s.tolerancePercentage
+	if params.Tolerance != nil {
+		state.Tolerance = *params.Tolerance
+	}
+
+	state.MaxDateDiff = s.maxDateDiff
+	if params.MaxDaysDiff != nil {
+		state.MaxDateDiff = time.Duration(*params.MaxDaysDiff) * 24 * time.Hour
+	}
+
+	state.BankRules = s.bankRules
+	state.MethodRules = s.methodRules
+
+	state.MinSimilarity = model.DefaultMinSimilarity
+	if params.MinSimilarity != nil {
+		state.MinSimilarity = *params.MinSimilarity
+	}
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// Dados comuns dos cenários das estratégias
+var (
+	testAccount  = "12345-6"
+	testIssuance = time.Date(2024, time.March, 5, 10, 0, 0, 0, time.UTC)
+
+	// testBarcode é um código de arrecadação, que dispensa o dígito verificador de cobrança
+	testBarcode = "8" + strings.Repeat("1", model.BarcodeLength-1)
+)
+
+// testBillet cria um boleto em aberto da conta de teste emitido em testIssuance
+func testBillet(id string, amount float64, configure func(*model.Billet)) *model.Billet {
+	billet := model.NewBillet(id, testAccount, amount, testIssuance, nil)
+	if configure != nil {
+		configure(billet)
+	}
+	return billet
+}
+
+// testPayment cria um pagamento da conta de teste feito days dias depois da emissão dos boletos
+func testPayment(id string, amount float64, days int, method model.PaymentMethod, configure func(*model.Payment)) *model.Payment {
+	payment := model.NewPayment(id, testAccount, amount, testIssuance.AddDate(0, 0, days), nil)
+	payment.PaymentMethod = method
+	if configure != nil {
+		configure(payment)
+	}
+	return payment
+}
+
+// testMatchState cria o estado de uma execução com 5% de tolerância, sem janela de datas e com as regras
+// padrão por meio de pagamento
+func testMatchState() *MatchState {
+	state := newMatchState(make(map[string]bool), make(map[string]bool))
+	state.Tolerance = 5
+	state.MethodRules = model.DefaultPaymentMethodRules()
+	state.MinSimilarity = model.DefaultMinSimilarity
+	return state
+}
+
+// TestPaymentMethodRulesInCandidateStrategies garante que a regra padrão do PIX (mesmo dia e sem diferença
+// de valor) vale em todas as estratégias que escolhem o boleto entre candidatos, e que os demais meios
+// continuam usando a tolerância da execução
+func TestPaymentMethodRulesInCandidateStrategies(t *testing.T) {
+	withBarcode := func(b *model.Billet) { b.BarCode = testBarcode }
+	paidBarcode := func(p *model.Payment) { p.BarCode = testBarcode }
+	withDocument := func(b *model.Billet) { b.PayerDocument = "12345678901" }
+	paidDocument := func(p *model.Payment) { p.PayerDocument = "12345678901" }
+	withName := func(b *model.Billet) { b.PayerName = "MARIA DA SILVA" }
+	paidName := func(p *model.Payment) { p.Description = "PIX RECEBIDO MARIA DA SILVA" }
+	customer := "C1"
+	withCustomer := func(b *model.Billet) { b.CustomerID = &customer }
+	paidCustomer := func(p *model.Payment) { p.ReferenceID = &customer }
+
+	tests := []struct {
+		name     string
+		strategy Strategy
+		billets  []*model.Billet
+		payment  *model.Payment
+		want     int
+	}{
+		{"barcode: PIX no mesmo dia", barcodeStrategy{}, []*model.Billet{testBillet("B1", 100, withBarcode)}, testPayment("T1", 100, 0, model.PaymentMethodPix, paidBarcode), 1},
+		{"barcode: PIX no dia seguinte", barcodeStrategy{}, []*model.Billet{testBillet("B1", 100, withBarcode)}, testPayment("T1", 100, 1, model.PaymentMethodPix, paidBarcode), 0},
+		{"barcode: PIX com diferença de valor", barcodeStrategy{}, []*model.Billet{testBillet("B1", 100, withBarcode)}, testPayment("T1", 98, 0, model.PaymentMethodPix, paidBarcode), 0},
+		{"barcode: transferência com diferença no dia seguinte", barcodeStrategy{}, []*model.Billet{testBillet("B1", 100, withBarcode)}, testPayment("T1", 98, 1, model.PaymentMethodTransfer, paidBarcode), 1},
+
+		{"payer_document: PIX no mesmo dia", payerDocumentStrategy{}, []*model.Billet{testBillet("B1", 100, withDocument)}, testPayment("T1", 100, 0, model.PaymentMethodPix, paidDocument), 1},
+		{"payer_document: PIX no dia seguinte", payerDocumentStrategy{}, []*model.Billet{testBillet("B1", 100, withDocument)}, testPayment("T1", 100, 1, model.PaymentMethodPix, paidDocument), 0},
+		{"payer_document: PIX com diferença de valor", payerDocumentStrategy{}, []*model.Billet{testBillet("B1", 100, withDocument)}, testPayment("T1", 98, 0, model.PaymentMethodPix, paidDocument), 0},
+		{"payer_document: transferência com diferença no dia seguinte", payerDocumentStrategy{}, []*model.Billet{testBillet("B1", 100, withDocument)}, testPayment("T1", 98, 1, model.PaymentMethodTransfer, paidDocument), 1},
+
+		{"fuzzy_name: PIX no mesmo dia", fuzzyNameStrategy{}, []*model.Billet{testBillet("B1", 100, withName)}, testPayment("T1", 100, 0, model.PaymentMethodPix, paidName), 1},
+		{"fuzzy_name: PIX no dia seguinte", fuzzyNameStrategy{}, []*model.Billet{testBillet("B1", 100, withName)}, testPayment("T1", 100, 1, model.PaymentMethodPix, paidName), 0},
+		{"fuzzy_name: PIX com diferença de valor", fuzzyNameStrategy{}, []*model.Billet{testBillet("B1", 100, withName)}, testPayment("T1", 98, 0, model.PaymentMethodPix, paidName), 0},
+		{"fuzzy_name: transferência com diferença no dia seguinte", fuzzyNameStrategy{}, []*model.Billet{testBillet("B1", 100, withName)}, testPayment("T1", 98, 1, model.PaymentMethodTransfer, paidName), 1},
+
+		{"aggregate: PIX no mesmo dia", aggregateStrategy{}, []*model.Billet{testBillet("B1", 60, nil), testBillet("B2", 40, nil)}, testPayment("T1", 100, 0, model.PaymentMethodPix, nil), 2},
+		{"aggregate: PIX no dia seguinte", aggregateStrategy{}, []*model.Billet{testBillet("B1", 60, nil), testBillet("B2", 40, nil)}, testPayment("T1", 100, 1, model.PaymentMethodPix, nil), 0},
+		{"aggregate: PIX com diferença de valor", aggregateStrategy{}, []*model.Billet{testBillet("B1", 60, nil), testBillet("B2", 40, nil)}, testPayment("T1", 98, 0, model.PaymentMethodPix, nil), 0},
+		{"aggregate: transferência com diferença no dia seguinte", aggregateStrategy{}, []*model.Billet{testBillet("B1", 60, nil), testBillet("B2", 40, nil)}, testPayment("T1", 98, 1, model.PaymentMethodTransfer, nil), 2},
+
+		{"split: PIX no mesmo dia", splitStrategy{}, []*model.Billet{testBillet("B1", 60, withCustomer), testBillet("B2", 40, withCustomer)}, testPayment("T1", 110, 0, model.PaymentMethodPix, paidCustomer), 2},
+		{"split: PIX no dia seguinte", splitStrategy{}, []*model.Billet{testBillet("B1", 60, withCustomer), testBillet("B2", 40, withCustomer)}, testPayment("T1", 110, 1, model.PaymentMethodPix, paidCustomer), 0},
+		{"split: transferência no dia seguinte", splitStrategy{}, []*model.Billet{testBillet("B1", 60, withCustomer), testBillet("B2", 40, withCustomer)}, testPayment("T1", 110, 1, model.PaymentMethodTransfer, paidCustomer), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := tt.strategy.Match(context.Background(), tt.billets, []*model.Payment{tt.payment}, testMatchState())
+			if len(matches) != tt.want {
+				t.Fatalf("%d boletos conciliados, esperado %d: %+v", len(matches), tt.want, matches)
+			}
+		})
+	}
+}
+
+// TestFuzzyNameSuggestionThreshold garante que a estratégia de nome aproximado só concilia direto acima
+// de SuggestionConfidenceThreshold; entre a similaridade mínima e esse limite o pareamento fica sugerido
+func TestFuzzyNameSuggestionThreshold(t *testing.T) {
+	tests := []struct {
+		name          string
+		description   string
+		minSimilarity float64
+		wantStatus    model.ConciliationStatus
+	}{
+		{"nome idêntico", "PIX RECEBIDO MARIA DA SILVA", 0.6, model.StatusSuccessful},
+		{"nome parecido acima do limite", "PIX RECEBIDO MARIA DE SOUSA", 0.6, model.StatusSuccessful},
+		{"nome parecido abaixo do limite", "PIX RECEBIDO MARIA SOUZA", 0.6, model.StatusSuggested},
+		{"abaixo da similaridade mínima", "TED JOAO PEREIRA", 0.6, ""},
+		{"similaridade mínima padrão", "PIX RECEBIDO MARIA DE SOUSA", model.DefaultMinSimilarity, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			billet := testBillet("B1", 100, func(b *model.Billet) { b.PayerName = "MARIA DA SILVA" })
+			payment := testPayment("T1", 100, 1, model.PaymentMethodTransfer, func(p *model.Payment) { p.Description = tt.description })
+
+			state := testMatchState()
+			state.MinSimilarity = tt.minSimilarity
+
+			matches := fuzzyNameStrategy{}.Match(context.Background(), []*model.Billet{billet}, []*model.Payment{payment}, state)
+			if tt.wantStatus == "" {
+				if len(matches) != 0 {
+					t.Fatalf("pagamento não deveria conciliar: %+v", matches)
+				}
+				return
+			}
+
+			if len(matches) != 1 {
+				t.Fatalf("%d boletos conciliados, esperado 1", len(matches))
+			}
+			if matches[0].ConciliationStatus != tt.wantStatus || matches[0].Confidence == nil {
+				t.Fatalf("pareamento com status %s e confiança %v, esperado %s", matches[0].ConciliationStatus, matches[0].Confidence, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// strategyCase descreve um cenário de estratégia que pareia boletos e pagamentos por um identificador
+type strategyCase struct {
+	name     string
+	billets  []*model.Billet
+	payments []*model.Payment
+	prepare  func(state *MatchState)
+	want     map[string]model.ConciliationStatus
+}
+
+// runStrategyCases executa os cenários e confere, por boleto, o pagamento pareado e o status. want indexa
+// o status esperado pelo par "boleto/pagamento"
+func runStrategyCases(t *testing.T, strategy Strategy, tests []strategyCase) {
+	t.Helper()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := testMatchState()
+			if tt.prepare != nil {
+				tt.prepare(state)
+			}
+
+			matches := strategy.Match(context.Background(), tt.billets, tt.payments, state)
+			if len(matches) != len(tt.want) {
+				t.Fatalf("%d boletos conciliados, esperado %d: %+v", len(matches), len(tt.want), matches)
+			}
+			for _, match := range matches {
+				pair := match.BilletID + "/" + match.TransactionID
+				want, found := tt.want[pair]
+				if !found {
+					t.Fatalf("pareamento inesperado %s", pair)
+				}
+				if match.ConciliationStatus != want || match.ConciliationStrategy != strategy.Name() {
+					t.Fatalf("%s: %s/%s, esperado %s/%s", pair, match.ConciliationStatus, match.ConciliationStrategy, want, strategy.Name())
+				}
+				if !state.ReconciledBillets[match.BilletID] || !state.UsedPayments[match.TransactionID] {
+					t.Fatalf("%s: pareamento não marcado no estado da execução", pair)
+				}
+			}
+		})
+	}
+}
+
+// TestBarcodeStrategy garante que a estratégia de código de barras pareia pelo código normalizado, respeita
+// a tolerância de valor e desempata candidatos do mesmo código pelo valor
+func TestBarcodeStrategy(t *testing.T) {
+	withBarcode := func(barcode string) func(*model.Billet) {
+		return func(b *model.Billet) { b.BarCode = barcode }
+	}
+	paidBarcode := func(barcode string) func(*model.Payment) {
+		return func(p *model.Payment) { p.BarCode = barcode }
+	}
+	otherBarcode := "8" + strings.Repeat("2", model.BarcodeLength-1)
+	formatted := testBarcode[:11] + " " + testBarcode[11:22] + "." + testBarcode[22:]
+
+	runStrategyCases(t, barcodeStrategy{}, []strategyCase{
+		{
+			name:     "mesmo código e valor",
+			billets:  []*model.Billet{testBillet("B1", 100, withBarcode(testBarcode))},
+			payments: []*model.Payment{testPayment("T1", 100, 1, model.PaymentMethodBillet, paidBarcode(testBarcode))},
+			want:     map[string]model.ConciliationStatus{"B1/T1": model.StatusSuccessful},
+		},
+		{
+			name:     "código do pagamento com pontuação",
+			billets:  []*model.Billet{testBillet("B1", 100, withBarcode(testBarcode))},
+			payments: []*model.Payment{testPayment("T1", 100, 1, model.PaymentMethodBillet, paidBarcode(formatted))},
+			want:     map[string]model.ConciliationStatus{"B1/T1": model.StatusSuccessful},
+		},
+		{
+			name:     "código diferente",
+			billets:  []*model.Billet{testBillet("B1", 100, withBarcode(testBarcode))},
+			payments: []*model.Payment{testPayment("T1", 100, 1, model.PaymentMethodBillet, paidBarcode(otherBarcode))},
+			want:     map[string]model.ConciliationStatus{},
+		},
+		{
+			name:     "pagamento sem código",
+			billets:  []*model.Billet{testBillet("B1", 100, withBarcode(testBarcode))},
+			payments: []*model.Payment{testPayment("T1", 100, 1, model.PaymentMethodBillet, nil)},
+			want:     map[string]model.ConciliationStatus{},
+		},
+		{
+			name:     "diferença dentro da tolerância",
+			billets:  []*model.Billet{testBillet("B1", 100, withBarcode(testBarcode))},
+			payments: []*model.Payment{testPayment("T1", 97, 1, model.PaymentMethodBillet, paidBarcode(testBarcode))},
+			want:     map[string]model.ConciliationStatus{"B1/T1": model.StatusDifferentValue},
+		},
+		{
+			name:     "diferença acima da tolerância",
+			billets:  []*model.Billet{testBillet("B1", 100, withBarcode(testBarcode))},
+			payments: []*model.Payment{testPayment("T1", 90, 1, model.PaymentMethodBillet, paidBarcode(testBarcode))},
+			want:     map[string]model.ConciliationStatus{},
+		},
+		{
+			name: "candidatos do mesmo código desempatados pelo valor",
+			billets: []*model.Billet{
+				testBillet("B1", 98, withBarcode(testBarcode)),
+				testBillet("B2", 100, withBarcode(testBarcode)),
+			},
+			payments: []*model.Payment{testPayment("T1", 100, 1, model.PaymentMethodBillet, paidBarcode(testBarcode))},
+			want:     map[string]model.ConciliationStatus{"B2/T1": model.StatusSuccessful},
+		},
+		{
+			name:     "boleto já conciliado por estratégia anterior",
+			billets:  []*model.Billet{testBillet("B1", 100, withBarcode(testBarcode))},
+			payments: []*model.Payment{testPayment("T1", 100, 1, model.PaymentMethodBillet, paidBarcode(testBarcode))},
+			prepare:  func(state *MatchState) { state.ReconciledBillets["B1"] = true },
+			want:     map[string]model.ConciliationStatus{},
+		},
+	})
+}
+
+// TestPayerDocumentStrategy garante que a estratégia de documento do pagador só considera pagamentos sem
+// referência, pareia boletos do mesmo CPF/CNPJ e desempata os boletos do pagador pelo valor
+func TestPayerDocumentStrategy(t *testing.T) {
+	withDocument := func(document string) func(*model.Billet) {
+		return func(b *model.Billet) { b.PayerDocument = document }
+	}
+	paidDocument := func(document string) func(*model.Payment) {
+		return func(p *model.Payment) { p.PayerDocument = document }
+	}
+	reference := "REF-1"
+	paidWithReference := func(p *model.Payment) {
+		p.PayerDocument = "12345678901"
+		p.ReferenceID = &reference
+	}
+
+	runStrategyCases(t, payerDocumentStrategy{}, []strategyCase{
+		{
+			name:     "mesmo documento e valor",
+			billets:  []*model.Billet{testBillet("B1", 100, withDocument("12345678901"))},
+			payments: []*model.Payment{testPayment("T1", 100, 1, model.PaymentMethodTransfer, paidDocument("12345678901"))},
+			want:     map[string]model.ConciliationStatus{"B1/T1": model.StatusSuccessful},
+		},
+		{
+			name:     "documento diferente",
+			billets:  []*model.Billet{testBillet("B1", 100, withDocument("12345678901"))},
+			payments: []*model.Payment{testPayment("T1", 100, 1, model.PaymentMethodTransfer, paidDocument("98765432100"))},
+			want:     map[string]model.ConciliationStatus{},
+		},
+		{
+			name:     "pagamento com referência fica para reference_id",
+			billets:  []*model.Billet{testBillet("B1", 100, withDocument("12345678901"))},
+			payments: []*model.Payment{testPayment("T1", 100, 1, model.PaymentMethodTransfer, paidWithReference)},
+			want:     map[string]model.ConciliationStatus{},
+		},
+		{
+			name:     "diferença dentro da tolerância",
+			billets:  []*model.Billet{testBillet("B1", 100, withDocument("12345678901"))},
+			payments: []*model.Payment{testPayment("T1", 96, 1, model.PaymentMethodTransfer, paidDocument("12345678901"))},
+			want:     map[string]model.ConciliationStatus{"B1/T1": model.StatusDifferentValue},
+		},
+		{
+			name: "boletos do pagador desempatados pelo valor",
+			billets: []*model.Billet{
+				testBillet("B1", 250, withDocument("12345678901")),
+				testBillet("B2", 100, withDocument("12345678901")),
+			},
+			payments: []*model.Payment{
+				testPayment("T1", 100, 1, model.PaymentMethodTransfer, paidDocument("12345678901")),
+				testPayment("T2", 250, 2, model.PaymentMethodTransfer, paidDocument("12345678901")),
+			},
+			want: map[string]model.ConciliationStatus{"B1/T2": model.StatusSuccessful, "B2/T1": model.StatusSuccessful},
+		},
+		{
+			name:     "pagamento já usado por estratégia anterior",
+			billets:  []*model.Billet{testBillet("B1", 100, withDocument("12345678901"))},
+			payments: []*model.Payment{testPayment("T1", 100, 1, model.PaymentMethodTransfer, paidDocument("12345678901"))},
+			prepare:  func(state *MatchState) { state.UsedPayments["T1"] = true },
+			want:     map[string]model.ConciliationStatus{},
+		},
+	})
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// DefaultBilletFee define o valor da tarifa padrão de emissão de boleto (R$ 2,00)
+const DefaultBilletFee = 2.0
+
+// MaxSuggestionCandidates define a quantidade máxima de candidatos exibidos por boleto
+const MaxSuggestionCandidates = 5
+
+// amountEpsilon define a precisão usada para comparar valores monetários
+const amountEpsilon = 0.005
+
+// suggestionsKey é a chave, no contexto da conciliação, que habilita as sugestões de correção
+type suggestionsKey struct{}
+
+// WithSuggestions habilita a geração de candidatos de match e sugestões de correção para os boletos que
+// não conciliaram. Sem ela, a conciliação não percorre os pagamentos restantes em busca de candidatos
+func WithSuggestions(ctx context.Context) context.Context {
+	return context.WithValue(ctx, suggestionsKey{}, true)
+}
+
+// suggestionsEnabled verifica se a conciliação deve gerar sugestões de correção
+func suggestionsEnabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(suggestionsKey{}).(bool)
+	return enabled
+}
+
+// suggestCorrections gera candidatos de match e sugestões de correção para os boletos não conciliados
+func (s *DefaultReconciliationService) suggestCorrections(
+	billets []model.Billet,
+	payments []*model.Payment,
+	usedPaymentsMap map[string]bool,
+) []model.BilletSuggestions {
+	var suggestions []model.BilletSuggestions
+
+	for i := range billets {
+		billet := &billets[i]
+
+		candidates := findMatchCandidates(billet, payments, usedPaymentsMap)
+		if len(candidates) == 0 {
+			continue
+		}
+
+		billetSuggestions := model.BilletSuggestions{
+			BilletID:    billet.ID,
+			Candidates:  make([]model.MatchCandidate, 0, len(candidates)),
+			Suggestions: []model.CorrectionSuggestion{},
+		}
+
+		for _, payment := range candidates {
+			billetSuggestions.Candidates = append(billetSuggestions.Candidates, newMatchCandidate(billet, payment))
+			billetSuggestions.Suggestions = append(billetSuggestions.Suggestions, correctionsFor(billet, payment, s.tolerancePercentage)...)
+		}
+
+		suggestions = append(suggestions, billetSuggestions)
+	}
+
+	return suggestions
+}
+
+// findMatchCandidates seleciona os pagamentos não utilizados que têm alguma afinidade com o boleto
+// (mesma conta, mesma referência ou mesmo valor), ordenados pela proximidade de valor e data
+func findMatchCandidates(billet *model.Billet, payments []*model.Payment, usedPaymentsMap map[string]bool) []*model.Payment {
+	var candidates []*model.Payment
+	for _, payment := range payments {
+		if usedPaymentsMap[payment.ID] {
+			continue
+		}
+
+		sameAccount := payment.BankAccount == billet.BankAccount
+		sameReference := sameReferenceID(billet.ReferenceID, payment.ReferenceID)
+		sameAmount := math.Abs(payment.Amount-billet.Amount) < amountEpsilon
+
+		if sameAccount || sameReference || sameAmount {
+			candidates = append(candidates, payment)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		diffI := math.Abs(candidates[i].Amount - billet.Amount)
+		diffJ := math.Abs(candidates[j].Amount - billet.Amount)
+		if diffI != diffJ {
+			return diffI < diffJ
+		}
+		return absDuration(candidates[i].PaymentDate.Sub(billet.IssuanceDate)) <
+			absDuration(candidates[j].PaymentDate.Sub(billet.IssuanceDate))
+	})
+
+	if len(candidates) > MaxSuggestionCandidates {
+		candidates = candidates[:MaxSuggestionCandidates]
+	}
+
+	return candidates
+}
+
+// newMatchCandidate cria a representação de um pagamento candidato para um boleto
+func newMatchCandidate(billet *model.Billet, payment *model.Payment) model.MatchCandidate {
+	return model.MatchCandidate{
+		TransactionID: payment.ID,
+		BankAccount:   payment.BankAccount,
+		Amount:        payment.Amount,
+		PaymentDate:   payment.PaymentDate,
+		ReferenceID:   payment.ReferenceID,
+		AmountDiff:    math.Abs(payment.Amount - billet.Amount),
+		DaysDiff:      int(absDuration(payment.PaymentDate.Sub(billet.IssuanceDate)).Hours() / 24),
+	}
+}
+
+// correctionsFor aplica as regras de sugestão de correção a um par boleto/pagamento
+func correctionsFor(billet *model.Billet, payment *model.Payment, tolerancePercentage float64) []model.CorrectionSuggestion {
+	var corrections []model.CorrectionSuggestion
+
+	amountDiff, amountDiffPercentage := billet.AmountDiff(payment.Amount)
+	sameAccount := payment.BankAccount == billet.BankAccount
+	sameReference := sameReferenceID(billet.ReferenceID, payment.ReferenceID)
+
+	// Diferença exatamente igual à tarifa padrão de emissão
+	if math.Abs(amountDiff-DefaultBilletFee) < amountEpsilon && (sameAccount || sameReference) {
+		corrections = append(corrections, model.CorrectionSuggestion{
+			Type: model.SuggestionFeeDifference,
+			Message: fmt.Sprintf("valor do boleto difere em exatamente %s da tarifa padrão (pagamento %s)",
+				formatBRL(DefaultBilletFee), payment.ID),
+			TransactionID: payment.ID,
+		})
+	} else if amountDiffPercentage > tolerancePercentage && sameReference {
+		// Mesma referência, mas valor acima da tolerância permitida
+		corrections = append(corrections, model.CorrectionSuggestion{
+			Type: model.SuggestionAmountOutOfRange,
+			Message: fmt.Sprintf("pagamento %s tem a mesma referência mas valor %s difere %.2f%% do boleto (%s)",
+				payment.ID, formatBRL(payment.Amount), amountDiffPercentage, formatBRL(billet.Amount)),
+			TransactionID: payment.ID,
+		})
+	}
+
+	// Pagamento compatível registrado em uma conta muito parecida (ex.: dígito verificador trocado)
+	if !sameAccount && amountDiffPercentage <= tolerancePercentage && similarAccounts(billet.BankAccount, payment.BankAccount) {
+		corrections = append(corrections, model.CorrectionSuggestion{
+			Type: model.SuggestionAccountMismatch,
+			Message: fmt.Sprintf("pagamento %s existe na conta %s em vez de %s",
+				payment.ID, payment.BankAccount, billet.BankAccount),
+			TransactionID: payment.ID,
+		})
+	}
+
+	// Mesma conta e valor, mas a referência diverge ou está ausente
+	if sameAccount && amountDiffPercentage <= tolerancePercentage && !sameReference {
+		if billet.ReferenceID == nil || *billet.ReferenceID == "" {
+			if payment.ReferenceID != nil && *payment.ReferenceID != "" {
+				corrections = append(corrections, model.CorrectionSuggestion{
+					Type: model.SuggestionMissingReferenceID,
+					Message: fmt.Sprintf("boleto sem reference_id; pagamento %s informa a referência %s",
+						payment.ID, *payment.ReferenceID),
+					TransactionID: payment.ID,
+				})
+			}
+		} else if payment.ReferenceID != nil && *payment.ReferenceID != "" {
+			corrections = append(corrections, model.CorrectionSuggestion{
+				Type: model.SuggestionReferenceMismatch,
+				Message: fmt.Sprintf("pagamento %s informa a referência %s em vez de %s",
+					payment.ID, *payment.ReferenceID, *billet.ReferenceID),
+				TransactionID: payment.ID,
+			})
+		}
+	}
+
+	return corrections
+}
+
+// sameReferenceID verifica se duas referências opcionais estão preenchidas e são idênticas
+func sameReferenceID(a, b *string) bool {
+	return a != nil && b != nil && *a != "" && *a == *b
+}
+
+// similarAccounts verifica se duas contas diferem em apenas um caractere (ignorando separadores)
+func similarAccounts(a, b string) bool {
+	a, b = normalizeAccount(a), normalizeAccount(b)
+	if a == b || len(a) != len(b) || a == "" {
+		return false
+	}
+
+	differences := 0
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			differences++
+		}
+	}
+
+	return differences == 1
+}
+
+// normalizeAccount remove separadores e espaços de um número de conta
+func normalizeAccount(account string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '.' || r == '/' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(account))
+}
+
+// formatBRL formata um valor monetário no padrão brasileiro (ex.: R$ 1.234,56)
+func formatBRL(amount float64) string {
+	negative := amount < 0
+	cents := int64(math.Round(math.Abs(amount) * 100))
+	integer := fmt.Sprintf("%d", cents/100)
+
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	formatted := fmt.Sprintf("R$ %s,%02d", grouped.String(), cents%100)
+	if negative {
+		return "-" + formatted
+	}
+	return formatted
+}
+
+// absDuration retorna o valor absoluto de uma duração
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
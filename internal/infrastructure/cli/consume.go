@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+
+	"conciliacao-bancaria/internal/domain/model"
+	pkgErrors "conciliacao-bancaria/pkg/errors"
+)
+
+// DefaultConsumer identifica o consumidor das mensagens de pagamento quando --consumer não é informado
+const DefaultConsumer = "pagamentos"
+
+// PaymentConsumer grava o pagamento de uma mensagem de fila, descartando as reentregas
+type PaymentConsumer interface {
+	ConsumePayment(ctx context.Context, consumer, messageID string, payment *model.Payment) (model.MessageOutcome, error)
+}
+
+// messageLine representa o envelope de uma mensagem de pagamento em uma linha NDJSON de entrada; os
+// campos do pagamento seguem paymentLine
+type messageLine struct {
+	MessageID string `json:"message_id"`
+	Tenant    string `json:"tenant"`
+}
+
+// outcomeLine representa uma linha NDJSON de saída com o desfecho de uma mensagem
+type outcomeLine struct {
+	MessageID     string               `json:"message_id"`
+	TransactionID string               `json:"transaction_id,omitempty"`
+	Outcome       model.MessageOutcome `json:"outcome"`
+	Error         string               `json:"error,omitempty"`
+}
+
+// RunConsume consome as mensagens de pagamento da fila entregues em NDJSON, uma por linha, e grava cada
+// pagamento pelo caso de uso de pagamentos. O adaptador do broker encaminha as mensagens ao comando e
+// confirma cada uma ao ler a linha de desfecho correspondente.
+//
+// Uso:
+//
+//	conciliacao consume [--consumer pagamentos] [--input mensagens.ndjson]
+//
+// Cada linha traz o message_id, o tenant opcional e os campos do pagamento, como na entrada do reconcile.
+// Mensagens inválidas ou em conflito saem como rejeitada e não devem ser reentregues. Uma falha de banco
+// interrompe o consumo sem desfecho da mensagem, que pode ser reentregue.
+func RunConsume(ctx context.Context, args []string, consumer PaymentConsumer, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("consume", flag.ContinueOnError)
+	consumerName := flags.String("consumer", DefaultConsumer, "consumidor registrado com cada mensagem processada")
+	input := flags.String("input", stdioPath, "arquivo NDJSON de mensagens ou - para stdin")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(stdout)
+	return readLines(*input, stdin, func(line []byte) error {
+		outcome, err := consumeMessage(ctx, consumer, *consumerName, line)
+		if err != nil {
+			return err
+		}
+		return encoder.Encode(outcome)
+	})
+}
+
+// consumeMessage grava o pagamento de uma linha e retorna o desfecho. Só as falhas que permitem
+// reentrega retornam erro
+func consumeMessage(ctx context.Context, consumer PaymentConsumer, consumerName string, line []byte) (*outcomeLine, error) {
+	var envelope messageLine
+	if err := json.Unmarshal(line, &envelope); err != nil {
+		return &outcomeLine{Outcome: model.MessageRejected, Error: err.Error()}, nil
+	}
+
+	out := &outcomeLine{MessageID: envelope.MessageID}
+
+	payment, err := decodePayment(line)
+	if err != nil {
+		out.Outcome, out.Error = model.MessageRejected, err.Error()
+		return out, nil
+	}
+	out.TransactionID = payment.ID
+
+	if envelope.Tenant != "" {
+		ctx = model.WithTenant(ctx, envelope.Tenant)
+	}
+
+	outcome, err := consumer.ConsumePayment(ctx, consumerName, envelope.MessageID, payment)
+	if isRejection(err) {
+		out.Outcome, out.Error = model.MessageRejected, err.Error()
+		return out, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out.Outcome = outcome
+	return out, nil
+}
+
+// isRejection indica os erros de consumo que se repetiriam em qualquer reentrega da mensagem
+func isRejection(err error) bool {
+	return pkgErrors.IsValidationError(err) || pkgErrors.IsValidationErrors(err) ||
+		pkgErrors.IsConflictError(err) || pkgErrors.IsForbiddenError(err)
+}
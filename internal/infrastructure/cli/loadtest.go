@@ -0,0 +1,403 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"conciliacao-bancaria/internal/infrastructure/http/middleware"
+)
+
+// Operações medidas pelo teste de carga
+const (
+	loadOpBillets         = "importacao_boletos"
+	loadOpPayments        = "importacao_pagamentos"
+	loadOpReconciliations = "conciliacao"
+)
+
+// loadOperations define a ordem das operações em cada lote e no relatório
+var loadOperations = []string{loadOpBillets, loadOpPayments, loadOpReconciliations}
+
+// LoadTestReport resume a latência e a vazão de cada operação do teste de carga
+type LoadTestReport struct {
+	BaseURL     string           `json:"base_url"`
+	Batches     int              `json:"batches"`
+	BatchSize   int              `json:"batch_size"`
+	Concurrency int              `json:"concurrency"`
+	DryRun      bool             `json:"dry_run"`
+	StartedAt   time.Time        `json:"started_at"`
+	DurationMs  int64            `json:"duration_ms"`
+	Operations  []OperationStats `json:"operations"`
+}
+
+// OperationStats traz as medições de uma operação. A vazão é calculada sobre a duração total do teste,
+// com as operações dos lotes acontecendo em paralelo
+type OperationStats struct {
+	Operation         string  `json:"operation"`
+	Requests          int     `json:"requests"`
+	Errors            int     `json:"errors"`
+	Rows              int     `json:"rows"`
+	MeanMs            float64 `json:"mean_ms"`
+	P50Ms             float64 `json:"p50_ms"`
+	P90Ms             float64 `json:"p90_ms"`
+	P99Ms             float64 `json:"p99_ms"`
+	MaxMs             float64 `json:"max_ms"`
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	RowsPerSecond     float64 `json:"rows_per_second"`
+}
+
+// loadSample registra uma requisição do teste de carga
+type loadSample struct {
+	operation string
+	duration  time.Duration
+	rows      int
+	err       error
+}
+
+// loadTester envia os lotes sintéticos à API
+type loadTester struct {
+	client    *http.Client
+	baseURL   string
+	apiKey    string
+	tenant    string
+	batchSize int
+	dryRun    bool
+	tag       string
+	day       time.Time
+}
+
+// RunLoadTest gera carga controlada contra uma instância da API: cada lote importa boletos e pagamentos
+// sintéticos em uma conta própria e concilia a conta, e o relatório traz a latência e a vazão de cada
+// operação. Os dados ficam gravados nas contas loadtest-*, separadas das contas reais.
+//
+// Uso:
+//
+//	conciliacao loadtest --url http://localhost:8080 --batches 20 --batch-size 500 --concurrency 4
+//
+// A API key e o tenant são lidos de --api-key e --tenant ou de LOADTEST_API_KEY e LOADTEST_TENANT.
+func RunLoadTest(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("loadtest", flag.ContinueOnError)
+	baseURL := flags.String("url", envOrDefault("LOADTEST_URL", "http://localhost:8080"), "endereço da instância testada")
+	apiKey := flags.String("api-key", os.Getenv("LOADTEST_API_KEY"), "API key enviada em X-API-Key")
+	tenant := flags.String("tenant", os.Getenv("LOADTEST_TENANT"), "tenant enviado em X-Tenant-ID")
+	batches := flags.Int("batches", 10, "quantidade de lotes")
+	batchSize := flags.Int("batch-size", 200, "boletos e pagamentos por lote")
+	concurrency := flags.Int("concurrency", 4, "lotes processados em paralelo")
+	timeout := flags.Duration("timeout", time.Minute, "tempo máximo de cada requisição")
+	dryRun := flags.Bool("dry-run", false, "concilia em modo de simulação, sem gravar as conciliações")
+	output := flags.String("output", "", "arquivo do relatório em JSON; sem ele, o relatório é impresso em texto")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *batches <= 0 || *batchSize <= 0 || *concurrency <= 0 {
+		return errors.New("--batches, --batch-size e --concurrency devem ser positivos")
+	}
+
+	now := time.Now().UTC()
+	tester := &loadTester{
+		client:    &http.Client{Timeout: *timeout},
+		baseURL:   strings.TrimRight(*baseURL, "/"),
+		apiKey:    *apiKey,
+		tenant:    *tenant,
+		batchSize: *batchSize,
+		dryRun:    *dryRun,
+		tag:       now.Format("20060102150405"),
+		day:       time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+	}
+
+	report := &LoadTestReport{
+		BaseURL:     tester.baseURL,
+		Batches:     *batches,
+		BatchSize:   *batchSize,
+		Concurrency: *concurrency,
+		DryRun:      *dryRun,
+		StartedAt:   now,
+	}
+
+	samples := tester.run(ctx, *batches, *concurrency)
+	report.DurationMs = time.Since(now).Milliseconds()
+	report.Operations = summarizeLoad(samples, time.Since(now))
+
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("erro ao criar arquivo do relatório: %w", err)
+		}
+		defer file.Close()
+
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("erro ao gravar relatório: %w", err)
+		}
+	}
+
+	return writeLoadReport(stdout, report)
+}
+
+// run distribui os lotes entre os workers e coleta as medições de todas as requisições
+func (t *loadTester) run(ctx context.Context, batches, concurrency int) []loadSample {
+	jobs := make(chan int)
+	results := make(chan loadSample)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range jobs {
+				t.runBatch(ctx, batch, results)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for batch := 0; batch < batches; batch++ {
+			select {
+			case jobs <- batch:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var samples []loadSample
+	for sample := range results {
+		if sample.err != nil {
+			log.Printf("loadtest: %s: %v", sample.operation, sample.err)
+		}
+		samples = append(samples, sample)
+	}
+
+	return samples
+}
+
+// runBatch importa os boletos e os pagamentos do lote e concilia a conta do lote. Com falha na
+// importação, a conciliação do lote não é executada
+func (t *loadTester) runBatch(ctx context.Context, batch int, results chan<- loadSample) {
+	account := fmt.Sprintf("loadtest-%s-%04d", t.tag, batch)
+	billets, payments := t.syntheticBatch(account, batch)
+
+	sample := t.post(ctx, loadOpBillets, "/api/v1/billets/batch", map[string]interface{}{"billets": billets}, len(billets))
+	results <- sample
+	if sample.err != nil {
+		return
+	}
+
+	sample = t.post(ctx, loadOpPayments, "/api/v1/payments/batch", map[string]interface{}{"payments": payments}, len(payments))
+	results <- sample
+	if sample.err != nil {
+		return
+	}
+
+	results <- t.post(ctx, loadOpReconciliations, "/api/v1/reconciliations", map[string]interface{}{
+		"start_date":      t.day.Format("2006-01-02"),
+		"end_date":        t.day.Format("2006-01-02"),
+		"filter_accounts": []string{account},
+		"dry_run":         t.dryRun,
+	}, len(billets))
+}
+
+// syntheticBatch gera os boletos e pagamentos do lote. A maior parte dos pagamentos corresponde ao boleto
+// pela referência; um a cada dez difere no valor e um a cada vinte vem sem referência, para que as
+// estratégias por valor e por conta também sejam exercitadas
+func (t *loadTester) syntheticBatch(account string, batch int) ([]billetLine, []paymentLine) {
+	billets := make([]billetLine, 0, t.batchSize)
+	payments := make([]paymentLine, 0, t.batchSize)
+	date := t.day.Format("2006-01-02")
+
+	for i := 0; i < t.batchSize; i++ {
+		id := fmt.Sprintf("lt-%s-%04d-%05d", t.tag, batch, i)
+		reference := "REF-" + id
+		amount := 100 + float64(i%500) + float64(i%100)/100
+
+		billets = append(billets, billetLine{
+			BilletID:     id,
+			BankAccount:  account,
+			Amount:       amount,
+			IssuanceDate: date,
+			ReferenceID:  &reference,
+		})
+
+		payment := paymentLine{
+			TransactionID: "pg-" + id,
+			BankAccount:   account,
+			Amount:        amount,
+			PaymentDate:   date,
+			ReferenceID:   &reference,
+			EntryType:     "credito",
+		}
+		if i%10 == 9 {
+			payment.Amount = math.Round(amount*99.5) / 100
+		}
+		if i%20 == 19 {
+			payment.ReferenceID = nil
+		}
+		payments = append(payments, payment)
+	}
+
+	return billets, payments
+}
+
+// post envia a requisição e mede a latência até a leitura completa da resposta
+func (t *loadTester) post(ctx context.Context, operation, path string, body interface{}, rows int) loadSample {
+	sample := loadSample{operation: operation, rows: rows}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		sample.err = err
+		return sample
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		sample.err = err
+		return sample
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.apiKey != "" {
+		req.Header.Set(middleware.APIKeyHeader, t.apiKey)
+	}
+	if t.tenant != "" {
+		req.Header.Set(middleware.TenantHeader, t.tenant)
+	}
+
+	start := time.Now()
+	resp, err := t.client.Do(req)
+	if err != nil {
+		sample.duration = time.Since(start)
+		sample.err = err
+		return sample
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	sample.duration = time.Since(start)
+	if err != nil {
+		sample.err = err
+		return sample
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		sample.err = fmt.Errorf("status %d: %s", resp.StatusCode, truncate(string(respBody), 200))
+	}
+
+	return sample
+}
+
+// summarizeLoad calcula as medições de cada operação; a vazão considera apenas as requisições com sucesso
+func summarizeLoad(samples []loadSample, elapsed time.Duration) []OperationStats {
+	durations := make(map[string][]float64)
+	stats := make(map[string]*OperationStats)
+	for _, operation := range loadOperations {
+		stats[operation] = &OperationStats{Operation: operation}
+	}
+
+	for _, sample := range samples {
+		op := stats[sample.operation]
+		op.Requests++
+		if sample.err != nil {
+			op.Errors++
+			continue
+		}
+
+		op.Rows += sample.rows
+		durations[sample.operation] = append(durations[sample.operation], float64(sample.duration.Microseconds())/1000)
+	}
+
+	seconds := elapsed.Seconds()
+	summary := make([]OperationStats, 0, len(loadOperations))
+	for _, operation := range loadOperations {
+		op := stats[operation]
+		values := durations[operation]
+		sort.Float64s(values)
+
+		if len(values) > 0 {
+			var total float64
+			for _, value := range values {
+				total += value
+			}
+			op.MeanMs = roundMs(total / float64(len(values)))
+			op.P50Ms = roundMs(nearestRank(values, 50))
+			op.P90Ms = roundMs(nearestRank(values, 90))
+			op.P99Ms = roundMs(nearestRank(values, 99))
+			op.MaxMs = roundMs(values[len(values)-1])
+		}
+
+		if seconds > 0 {
+			op.RequestsPerSecond = roundMs(float64(len(values)) / seconds)
+			op.RowsPerSecond = roundMs(float64(op.Rows) / seconds)
+		}
+
+		summary = append(summary, *op)
+	}
+
+	return summary
+}
+
+// nearestRank retorna o percentil p dos valores ordenados pelo método do posto mais próximo
+func nearestRank(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// roundMs arredonda a medição para duas casas decimais
+func roundMs(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// writeLoadReport imprime o relatório em texto, uma linha por operação
+func writeLoadReport(w io.Writer, report *LoadTestReport) error {
+	fmt.Fprintf(w, "teste de carga em %s: %d lotes de %d registros, %d em paralelo, %dms\n\n",
+		report.BaseURL, report.Batches, report.BatchSize, report.Concurrency, report.DurationMs)
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "operação\trequisições\terros\tmédia ms\tp50 ms\tp90 ms\tp99 ms\tmáx ms\treq/s\tregistros/s\t")
+	for _, op := range report.Operations {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t\n",
+			op.Operation, op.Requests, op.Errors, op.MeanMs, op.P50Ms, op.P90Ms, op.P99Ms, op.MaxMs,
+			op.RequestsPerSecond, op.RowsPerSecond)
+	}
+
+	return table.Flush()
+}
+
+// truncate limita o texto ao tamanho informado, usado nas mensagens de erro das respostas
+func truncate(value string, size int) string {
+	if len(value) <= size {
+		return value
+	}
+	return value[:size] + "..."
+}
+
+// envOrDefault obtém uma variável de ambiente ou retorna o valor padrão
+func envOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
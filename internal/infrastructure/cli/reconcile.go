@@ -0,0 +1,383 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/service"
+	"conciliacao-bancaria/internal/infrastructure/importer"
+)
+
+// stdioPath representa a leitura/escrita via stdin/stdout
+const stdioPath = "-"
+
+// billetLine representa um boleto em uma linha NDJSON de entrada
+type billetLine struct {
+	BilletID          string  `json:"billet_id"`
+	BankAccount       string  `json:"bank_account"`
+	Amount            float64 `json:"amount"`
+	IssuanceDate      string  `json:"issuance_date"`
+	ReferenceID       *string `json:"reference_id"`
+	InstallmentNumber *int    `json:"installment_number"`
+	ContractID        *string `json:"contract_id"`
+	CustomerID        *string `json:"customer_id"`
+	OpenAmount        bool    `json:"open_amount"`
+	BankCode          string  `json:"bank_code"`
+	BarCode           string  `json:"bar_code"`
+	DigitableLine     string  `json:"digitable_line"`
+	PayerDocument     string  `json:"payer_document"`
+	PayerName         string  `json:"payer_name"`
+	DueDate           string  `json:"due_date"`
+	InterestRate      float64 `json:"interest_rate"`
+	FinePercent       float64 `json:"fine_percent"`
+}
+
+// paymentLine representa um pagamento em uma linha NDJSON de entrada
+type paymentLine struct {
+	TransactionID string  `json:"transaction_id"`
+	BankAccount   string  `json:"bank_account"`
+	Amount        float64 `json:"amount"`
+	PaymentDate   string  `json:"payment_date"`
+	ReferenceID   *string `json:"reference_id"`
+	EntryType     string  `json:"entry_type"`
+	BankCode      string  `json:"bank_code"`
+	BarCode       string  `json:"bar_code"`
+	PayerDocument string  `json:"payer_document"`
+	Description   string  `json:"description"`
+	PaymentMethod string  `json:"payment_method"`
+}
+
+// resultLine representa uma linha NDJSON de saída com o resultado de um boleto
+type resultLine struct {
+	BilletID             string                     `json:"billet_id"`
+	BankAccount          string                     `json:"bank_account,omitempty"`
+	TransactionID        string                     `json:"transaction_id,omitempty"`
+	ConciliationStatus   model.ConciliationStatus   `json:"conciliation_status"`
+	ConciliationStrategy model.ConciliationStrategy `json:"conciliation_strategy,omitempty"`
+	ReferenceID          *string                    `json:"reference_id,omitempty"`
+	AmountDiff           *float64                   `json:"amount_diff,omitempty"`
+	PaidAmount           *float64                   `json:"paid_amount,omitempty"`
+}
+
+// RunReconcile executa a conciliação em modo batch, sem HTTP nem banco de dados.
+//
+// Uso:
+//
+//	conciliacao reconcile --input-billets boletos.ndjson --input-payments pagamentos.ndjson --output -
+//
+// Quando as duas entradas apontam para "-", o stdin é lido uma única vez e cada linha é
+// classificada pela presença de "billet_id" (boleto) ou "transaction_id" (pagamento).
+func RunReconcile(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	inputBillets := flags.String("input-billets", "", "arquivo NDJSON de boletos ou - para stdin")
+	inputPayments := flags.String("input-payments", "", "arquivo NDJSON de pagamentos ou - para stdin")
+	output := flags.String("output", stdioPath, "arquivo NDJSON de saída ou - para stdout")
+	minAmount := flags.Float64("min-amount", service.MinAutoReconcileAmount, "valor mínimo de pagamento para a conciliação automática")
+	bankRulesJSON := flags.String("bank-rules", os.Getenv("BANK_RULES"), "regras por banco em JSON, ex.: {\"341\":{\"credit_delay_days\":1}}")
+	methodRulesJSON := flags.String("payment-method-rules", os.Getenv("PAYMENT_METHOD_RULES"), "regras por meio de pagamento em JSON, ex.: {\"pix\":{\"tolerance\":0,\"max_days_diff\":0}}")
+	concurrency := flags.Int("concurrency", 1, "contas bancárias conciliadas em paralelo; acima de 1, cada conta é conciliada separadamente")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *inputBillets == "" || *inputPayments == "" {
+		return errors.New("--input-billets e --input-payments são obrigatórios")
+	}
+
+	var bankRules model.BankRules
+	if *bankRulesJSON != "" {
+		if err := json.Unmarshal([]byte(*bankRulesJSON), &bankRules); err != nil {
+			return fmt.Errorf("--bank-rules inválido: %w", err)
+		}
+	}
+
+	methodRules := model.DefaultPaymentMethodRules()
+	if *methodRulesJSON != "" {
+		if err := json.Unmarshal([]byte(*methodRulesJSON), &methodRules); err != nil {
+			return fmt.Errorf("--payment-method-rules inválido: %w", err)
+		}
+		if err := methodRules.Validate(); err != nil {
+			return fmt.Errorf("--payment-method-rules inválido: %w", err)
+		}
+	}
+
+	var billets []*model.Billet
+	var payments []*model.Payment
+	var err error
+
+	if *inputBillets == stdioPath && *inputPayments == stdioPath {
+		billets, payments, err = readMixed(stdin)
+		if err != nil {
+			return err
+		}
+	} else {
+		billets, err = readBilletsFrom(*inputBillets, stdin)
+		if err != nil {
+			return err
+		}
+
+		payments, err = readPaymentsFrom(*inputPayments, stdin)
+		if err != nil {
+			return err
+		}
+	}
+
+	reconciliationService := service.NewReconciliationServiceWithMethodRules(service.TolerancePercentage, *minAmount, bankRules, methodRules, *concurrency)
+	result, err := reconciliationService.ReconcileBilletsWithPayments(ctx, billets, payments)
+	if err != nil {
+		return fmt.Errorf("erro ao conciliar: %w", err)
+	}
+
+	writer := stdout
+	if *output != stdioPath {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("erro ao criar arquivo de saída: %w", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	return writeResult(writer, result)
+}
+
+// readBilletsFrom lê boletos de um arquivo ou do stdin
+func readBilletsFrom(path string, stdin io.Reader) ([]*model.Billet, error) {
+	var billets []*model.Billet
+	err := readLines(path, stdin, func(line []byte) error {
+		billet, err := decodeBillet(line)
+		if err != nil {
+			return err
+		}
+		billets = append(billets, billet)
+		return nil
+	})
+
+	return billets, err
+}
+
+// readPaymentsFrom lê pagamentos de um arquivo ou do stdin
+func readPaymentsFrom(path string, stdin io.Reader) ([]*model.Payment, error) {
+	var payments []*model.Payment
+	err := readLines(path, stdin, func(line []byte) error {
+		payment, err := decodePayment(line)
+		if err != nil {
+			return err
+		}
+		payments = append(payments, payment)
+		return nil
+	})
+
+	return payments, err
+}
+
+// readMixed lê boletos e pagamentos intercalados em um único fluxo NDJSON
+func readMixed(stdin io.Reader) ([]*model.Billet, []*model.Payment, error) {
+	var billets []*model.Billet
+	var payments []*model.Payment
+
+	err := readLines(stdioPath, stdin, func(line []byte) error {
+		var probe map[string]json.RawMessage
+		if err := json.Unmarshal(line, &probe); err != nil {
+			return err
+		}
+
+		if _, isBillet := probe["billet_id"]; isBillet {
+			billet, err := decodeBillet(line)
+			if err != nil {
+				return err
+			}
+			billets = append(billets, billet)
+			return nil
+		}
+
+		if _, isPayment := probe["transaction_id"]; isPayment {
+			payment, err := decodePayment(line)
+			if err != nil {
+				return err
+			}
+			payments = append(payments, payment)
+			return nil
+		}
+
+		return errors.New("linha sem billet_id ou transaction_id")
+	})
+
+	return billets, payments, err
+}
+
+// readLines percorre as linhas não vazias de um arquivo NDJSON (ou do stdin), convertendo
+// o encoding para UTF-8 e respeitando os limites de importação
+func readLines(path string, stdin io.Reader, fn func(line []byte) error) error {
+	reader := stdin
+	if path != stdioPath {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("erro ao abrir %s: %w", path, err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	// Guardar o original antes da conversão de encoding, para auditoria
+	if archive := importer.ArchiveFromEnv(); archive != nil {
+		tee, finish, err := archive.Tee(path, reader)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if archived, err := finish(); err == nil {
+				log.Printf("original de %s arquivado em %s", path, archived)
+			}
+		}()
+		reader = tee
+	}
+
+	reader, encoding, err := importer.NewUTF8Reader(reader, importer.EncodingFromEnv())
+	if err != nil {
+		return fmt.Errorf("erro ao ler %s: %w", path, err)
+	}
+	if encoding != importer.EncodingUTF8 {
+		log.Printf("%s convertido de %s para UTF-8", path, encoding)
+	}
+
+	err = importer.ScanLines(reader, importer.LimitsFromEnv(), func(lineNumber int, line []byte) error {
+		if err := fn(line); err != nil {
+			return fmt.Errorf("linha %d: %w", lineNumber, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao ler %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// decodeBillet converte uma linha NDJSON em boleto
+func decodeBillet(line []byte) (*model.Billet, error) {
+	var in billetLine
+	if err := json.Unmarshal(line, &in); err != nil {
+		return nil, err
+	}
+
+	issuanceDate, err := parseDate(in.IssuanceDate)
+	if err != nil {
+		return nil, fmt.Errorf("issuance_date inválida: %w", err)
+	}
+
+	billet := model.NewBillet(in.BilletID, in.BankAccount, in.Amount, issuanceDate, in.ReferenceID)
+	billet.InstallmentNumber = in.InstallmentNumber
+	billet.ContractID = in.ContractID
+	billet.CustomerID = in.CustomerID
+	billet.OpenAmount = in.OpenAmount
+	billet.BankCode = in.BankCode
+	billet.BarCode = model.OnlyDigits(in.BarCode)
+	billet.DigitableLine = model.OnlyDigits(in.DigitableLine)
+	billet.PayerDocument = model.OnlyDigits(in.PayerDocument)
+	billet.PayerName = in.PayerName
+	billet.InterestRate = in.InterestRate
+	billet.FinePercent = in.FinePercent
+
+	if in.DueDate != "" {
+		dueDate, err := parseDate(in.DueDate)
+		if err != nil {
+			return nil, fmt.Errorf("due_date inválida: %w", err)
+		}
+		billet.DueDate = &dueDate
+	}
+
+	return billet, nil
+}
+
+// decodePayment converte uma linha NDJSON em pagamento
+func decodePayment(line []byte) (*model.Payment, error) {
+	var in paymentLine
+	if err := json.Unmarshal(line, &in); err != nil {
+		return nil, err
+	}
+
+	paymentDate, err := parseDate(in.PaymentDate)
+	if err != nil {
+		return nil, fmt.Errorf("payment_date inválida: %w", err)
+	}
+
+	payment := model.NewPayment(in.TransactionID, in.BankAccount, in.Amount, paymentDate, in.ReferenceID)
+	if in.EntryType != "" {
+		payment.EntryType = model.EntryType(in.EntryType)
+	}
+	payment.BankCode = in.BankCode
+	payment.BarCode = model.OnlyDigits(in.BarCode)
+	payment.PayerDocument = model.OnlyDigits(in.PayerDocument)
+	payment.Description = in.Description
+	payment.PaymentMethod = model.PaymentMethod(in.PaymentMethod)
+
+	return payment, nil
+}
+
+// parseDate aceita datas em RFC 3339 ou apenas a data (AAAA-MM-DD)
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+
+	return time.Parse("2006-01-02", value)
+}
+
+// writeResult escreve uma linha NDJSON por boleto conciliado, ambíguo ou não conciliado
+func writeResult(writer io.Writer, result *model.ReconciliationResult) error {
+	buffered := bufio.NewWriter(writer)
+	encoder := json.NewEncoder(buffered)
+
+	for _, reconciled := range result.ReconciledBillets {
+		amountDiff := reconciled.AmountDiff
+		if err := encoder.Encode(resultLine{
+			BilletID:             reconciled.BilletID,
+			BankAccount:          reconciled.BankAccount,
+			TransactionID:        reconciled.TransactionID,
+			ConciliationStatus:   reconciled.ConciliationStatus,
+			ConciliationStrategy: reconciled.ConciliationStrategy,
+			ReferenceID:          reconciled.ReferenceID,
+			AmountDiff:           &amountDiff,
+			PaidAmount:           reconciled.PaidAmount,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, ambiguous := range result.AmbiguousReferences {
+		referenceID := ambiguous.ReferenceID
+		for _, billetID := range ambiguous.BilletIDs {
+			if err := encoder.Encode(resultLine{
+				BilletID:           billetID,
+				ConciliationStatus: ambiguous.ConciliationStatus,
+				ReferenceID:        &referenceID,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, billet := range result.NonReconciledBillets {
+		if err := encoder.Encode(resultLine{
+			BilletID:           billet.ID,
+			BankAccount:        billet.BankAccount,
+			ConciliationStatus: model.StatusNotReconciled,
+			ReferenceID:        billet.ReferenceID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return buffered.Flush()
+}
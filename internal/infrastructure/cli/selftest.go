@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"conciliacao-bancaria/internal/infrastructure/selftest"
+)
+
+// RunSelfTest executa o motor de matching contra o dataset dourado e compara o resultado de cada cenário
+// com o esperado, para validar instalações e upgrades. Retorna erro quando algum cenário diverge.
+//
+// Uso:
+//
+//	conciliacao selftest [--dataset cenarios.json] [--json]
+//
+// Sem --dataset, usa o dataset dourado embutido no binário.
+func RunSelfTest(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("selftest", flag.ContinueOnError)
+	datasetPath := flags.String("dataset", "", "arquivo JSON de cenários no formato do dataset dourado")
+	asJSON := flags.Bool("json", false, "imprime o relatório em JSON")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var report *selftest.Report
+	if *datasetPath != "" {
+		data, err := os.ReadFile(*datasetPath)
+		if err != nil {
+			return fmt.Errorf("erro ao ler %s: %w", *datasetPath, err)
+		}
+		report, err = selftest.RunDataset(ctx, data)
+		if err != nil {
+			return err
+		}
+	} else {
+		var err error
+		report, err = selftest.Run(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		writeSelfTestReport(stdout, report)
+	}
+
+	if !report.Passed {
+		return fmt.Errorf("%d de %d cenários divergentes", report.Failed(), len(report.Scenarios))
+	}
+	return nil
+}
+
+// writeSelfTestReport imprime o resultado de cada cenário e as divergências encontradas
+func writeSelfTestReport(w io.Writer, report *selftest.Report) {
+	fmt.Fprintf(w, "selftest do motor %s: %d cenários, %dms\n\n", report.EngineVersion, len(report.Scenarios), report.DurationMs)
+
+	for _, scenario := range report.Scenarios {
+		status := "ok"
+		if !scenario.Passed {
+			status = "FALHOU"
+		}
+		fmt.Fprintf(w, "%-6s %s - %s\n", status, scenario.Name, scenario.Description)
+		for _, difference := range scenario.Differences {
+			fmt.Fprintf(w, "       %s\n", difference)
+		}
+	}
+
+	if report.Passed {
+		fmt.Fprintln(w, "\nselftest aprovado")
+	} else {
+		fmt.Fprintf(w, "\nselftest reprovado: %d cenários divergentes\n", report.Failed())
+	}
+}
@@ -23,7 +23,8 @@ type DBConfig struct {
 
 // Connection representa uma conexão com o banco de dados
 type Connection struct {
-	DB *sql.DB
+	DB  *sql.DB
+	dsn string
 }
 
 // NewConnection cria uma nova conexão com o banco de dados
@@ -42,6 +43,27 @@ func NewConnection() (*Connection, error) {
 		config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode,
 	)
 
+	conn, err := Open(connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Println("Conexão com o banco de dados estabelecida com sucesso")
+	return conn, nil
+}
+
+// Open cria uma conexão com o banco de dados a partir de uma string de conexão
+func Open(connectionString string) (*Connection, error) {
+	db, err := open(connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Connection{DB: db, dsn: connectionString}, nil
+}
+
+// open abre um pool de conexões com a configuração padrão e verifica se o banco responde
+func open(connectionString string) (*sql.DB, error) {
 	db, err := sql.Open("postgres", connectionString)
 	if err != nil {
 		return nil, fmt.Errorf("falha ao abrir conexão com o banco de dados: %w", err)
@@ -57,11 +79,11 @@ func NewConnection() (*Connection, error) {
 	defer cancel()
 
 	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("falha ao conectar no banco de dados: %w", err)
 	}
 
-	log.Println("Conexão com o banco de dados estabelecida com sucesso")
-	return &Connection{DB: db}, nil
+	return db, nil
 }
 
 // Close fecha a conexão com o banco de dados
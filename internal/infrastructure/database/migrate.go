@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// DefaultSchemaFile define o script de schema aplicado pelas migrações
+const DefaultSchemaFile = "internal/infrastructure/database/migrations/schema.sql"
+
+// MigrationResult representa a aplicação do script de schema em um shard
+type MigrationResult struct {
+	Shard    string
+	Schema   string
+	Checksum string
+	Applied  bool // Falso quando o shard já estava na versão do script
+}
+
+// MigrateShardsFromFile aplica o script de schema do arquivo informado em todos os shards
+func MigrateShardsFromFile(ctx context.Context, router *ShardRouter, path string) ([]MigrationResult, error) {
+	script, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler script de schema: %w", err)
+	}
+
+	return MigrateShards(ctx, router, string(script))
+}
+
+// MigrateShards aplica o script de schema em cada shard, começando pelo padrão. Nos shards por schema,
+// o schema padrão do script é substituído pelo do shard. A versão aplicada (checksum do script) é
+// registrada em schema_migrations, e shards já na versão do script não são alterados
+func MigrateShards(ctx context.Context, router *ShardRouter, script string) ([]MigrationResult, error) {
+	checksum := sha256.Sum256([]byte(script))
+	version := hex.EncodeToString(checksum[:])
+
+	results := make([]MigrationResult, 0, len(router.Shards()))
+	for _, shard := range router.Shards() {
+		applied, err := migrateShard(ctx, shard, script, version)
+		if err != nil {
+			return results, fmt.Errorf("migração do shard %s (schema %s): %w", shard.Name, shard.Schema, err)
+		}
+
+		results = append(results, MigrationResult{Shard: shard.Name, Schema: shard.Schema, Checksum: version, Applied: applied})
+		if applied {
+			log.Printf("shard %s (schema %s): schema migrado para a versão %s", shard.Name, shard.Schema, version[:12])
+		} else {
+			log.Printf("shard %s (schema %s): schema já na versão %s", shard.Name, shard.Schema, version[:12])
+		}
+	}
+
+	return results, nil
+}
+
+// migrateShard aplica o script em uma transação, quando a versão ainda não foi registrada no shard
+func migrateShard(ctx context.Context, shard *Shard, script, version string) (bool, error) {
+	var appliedAt time.Time
+	err := shard.db.QueryRowContext(ctx,
+		"SELECT applied_at FROM "+shard.Schema+".schema_migrations WHERE checksum = $1", version,
+	).Scan(&appliedAt)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) && !isUndefinedTable(err) {
+		return false, fmt.Errorf("erro ao verificar versão do schema: %w", err)
+	}
+
+	tx, err := shard.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("falha ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, strings.ReplaceAll(script, DefaultSchema, shard.Schema)); err != nil {
+		return false, fmt.Errorf("erro ao aplicar script de schema: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO "+shard.Schema+".schema_migrations (checksum, applied_at) VALUES ($1, $2)", version, time.Now(),
+	); err != nil {
+		return false, fmt.Errorf("erro ao registrar versão do schema: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("falha ao confirmar transação: %w", err)
+	}
+
+	return true, nil
+}
+
+// isUndefinedTable identifica o erro de tabela inexistente (42P01), esperado antes da primeira migração
+func isUndefinedTable(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "42P01"
+}
@@ -10,6 +10,21 @@ CREATE TABLE IF NOT EXISTS bank_reconciliation.billets (
     amount DECIMAL(15, 2) NOT NULL,
     issuance_date TIMESTAMP NOT NULL,
     reference_id VARCHAR(50),
+    installment_number INTEGER,
+    contract_id VARCHAR(50),
+    customer_id VARCHAR(50),
+    open_amount BOOLEAN NOT NULL DEFAULT FALSE,
+    bank_code VARCHAR(3) NOT NULL DEFAULT '',
+    bar_code VARCHAR(44) NOT NULL DEFAULT '',
+    digitable_line VARCHAR(48) NOT NULL DEFAULT '',
+    payer_document VARCHAR(14) NOT NULL DEFAULT '',
+    payer_name VARCHAR(150) NOT NULL DEFAULT '',
+    due_date DATE,
+    interest_rate DECIMAL(7, 4) NOT NULL DEFAULT 0,
+    fine_percent DECIMAL(7, 4) NOT NULL DEFAULT 0,
+    reconciliation_id VARCHAR(50),
+    transaction_id VARCHAR(50),
+    status VARCHAR(20) NOT NULL DEFAULT 'emitido',
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
@@ -21,6 +36,17 @@ CREATE TABLE IF NOT EXISTS bank_reconciliation.payments (
     amount DECIMAL(15, 2) NOT NULL,
     payment_date TIMESTAMP NOT NULL,
     reference_id VARCHAR(50),
+    entry_type VARCHAR(10) NOT NULL DEFAULT 'credito',
+    bank_code VARCHAR(3) NOT NULL DEFAULT '',
+    bar_code VARCHAR(48) NOT NULL DEFAULT '',
+    payer_document VARCHAR(14) NOT NULL DEFAULT '',
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    payment_method VARCHAR(20) NOT NULL DEFAULT '',
+    review_status VARCHAR(20) NOT NULL DEFAULT '',
+    review_reason VARCHAR(255),
+    reconciliation_id VARCHAR(50),
+    billet_id VARCHAR(50),
+    status VARCHAR(20) NOT NULL DEFAULT 'recebido',
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
@@ -36,12 +62,422 @@ CREATE TABLE IF NOT EXISTS bank_reconciliation.reconciliations (
     amount_diff DECIMAL(15, 2) NOT NULL,
     reference_id VARCHAR(50),
     reconciliation_date TIMESTAMP NOT NULL,
+    time_to_reconcile_seconds BIGINT,
+    run_id VARCHAR(50),
+    transaction_ids VARCHAR(50)[],
+    group_id VARCHAR(50),
+    confidence DECIMAL(3, 2),
+    engine_version VARCHAR(50),
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     CONSTRAINT fk_billet_id FOREIGN KEY (billet_id) REFERENCES bank_reconciliation.billets(id),
     CONSTRAINT fk_transaction_id FOREIGN KEY (transaction_id) REFERENCES bank_reconciliation.payments(id)
 );
 
+-- Tabela de histórico de mudanças de status das conciliações (reavaliações)
+CREATE TABLE IF NOT EXISTS bank_reconciliation.reconciliation_status_changes (
+    id VARCHAR(50) PRIMARY KEY,
+    reconciliation_id VARCHAR(50) NOT NULL,
+    billet_id VARCHAR(50) NOT NULL,
+    bank_account VARCHAR(50) NOT NULL,
+    previous_status VARCHAR(30) NOT NULL,
+    new_status VARCHAR(30) NOT NULL,
+    previous_amount_diff DECIMAL(15, 2) NOT NULL,
+    new_amount_diff DECIMAL(15, 2) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    changed_by VARCHAR(100) NOT NULL DEFAULT '',
+    changed_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_status_change_reconciliation_id FOREIGN KEY (reconciliation_id) REFERENCES bank_reconciliation.reconciliations(id) ON DELETE CASCADE
+);
+
+-- Tabela de conciliações desfeitas por operadores, mantida para auditoria após a remoção do vínculo
+CREATE TABLE IF NOT EXISTS bank_reconciliation.reconciliation_undos (
+    id VARCHAR(50) PRIMARY KEY,
+    reconciliation_id VARCHAR(50) NOT NULL,
+    billet_id VARCHAR(50) NOT NULL,
+    transaction_id VARCHAR(50),
+    transaction_ids VARCHAR(50)[],
+    bank_account VARCHAR(50) NOT NULL,
+    conciliation_status VARCHAR(30) NOT NULL,
+    conciliation_strategy VARCHAR(30) NOT NULL,
+    amount_diff DECIMAL(15, 2) NOT NULL,
+    group_id VARCHAR(50),
+    reason VARCHAR(255) NOT NULL,
+    undone_by VARCHAR(100) NOT NULL DEFAULT '',
+    undone_at TIMESTAMP NOT NULL
+);
+
+-- Tabela das decisões de analistas sobre os pareamentos que aguardavam aprovação. Sem chave estrangeira,
+-- pois a conciliação rejeitada é removida
+CREATE TABLE IF NOT EXISTS bank_reconciliation.reconciliation_approvals (
+    id VARCHAR(50) PRIMARY KEY,
+    reconciliation_id VARCHAR(50) NOT NULL,
+    billet_id VARCHAR(50) NOT NULL,
+    transaction_id VARCHAR(50),
+    bank_account VARCHAR(50) NOT NULL,
+    decision VARCHAR(20) NOT NULL,
+    previous_status VARCHAR(30) NOT NULL,
+    new_status VARCHAR(30) NOT NULL DEFAULT '',
+    amount_diff DECIMAL(15, 2) NOT NULL,
+    confidence DECIMAL(3, 2),
+    approver VARCHAR(100) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    decided_at TIMESTAMP NOT NULL
+);
+
+-- Tabela de bloqueios de boletos em investigação
+CREATE TABLE IF NOT EXISTS bank_reconciliation.billet_claims (
+    billet_id VARCHAR(50) PRIMARY KEY,
+    claimed_by VARCHAR(100) NOT NULL,
+    claimed_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_claim_billet_id FOREIGN KEY (billet_id) REFERENCES bank_reconciliation.billets(id) ON DELETE CASCADE
+);
+
+-- Tabela de assinaturas de eventos (webhooks)
+CREATE TABLE IF NOT EXISTS bank_reconciliation.event_subscriptions (
+    id VARCHAR(50) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    callback_url VARCHAR(500) NOT NULL,
+    secret VARCHAR(200) NOT NULL DEFAULT '',
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    bank_accounts TEXT[] NOT NULL DEFAULT '{}',
+    min_amount DECIMAL(15, 2),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tabela de pesos do ranker de candidatos (regressão logística por tenant)
+CREATE TABLE IF NOT EXISTS bank_reconciliation.ranker_models (
+    tenant_id VARCHAR(100) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    bias DOUBLE PRECISION NOT NULL,
+    amount_diff_weight DOUBLE PRECISION NOT NULL,
+    days_diff_weight DOUBLE PRECISION NOT NULL,
+    partial_reference_weight DOUBLE PRECISION NOT NULL,
+    samples INTEGER NOT NULL,
+    trained_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tabela de revisões manuais de matches (dataset de treino do ranker)
+CREATE TABLE IF NOT EXISTS bank_reconciliation.match_reviews (
+    id VARCHAR(50) PRIMARY KEY,
+    tenant_id VARCHAR(100) NOT NULL,
+    billet_id VARCHAR(50) NOT NULL,
+    transaction_id VARCHAR(50) NOT NULL,
+    approved BOOLEAN NOT NULL,
+    reviewer VARCHAR(100) NOT NULL DEFAULT '',
+    amount_diff_percentage DOUBLE PRECISION NOT NULL,
+    days_diff DOUBLE PRECISION NOT NULL,
+    partial_reference BOOLEAN NOT NULL,
+    reviewed_at TIMESTAMP NOT NULL
+);
+
+-- Tabela de conjuntos de regras de matching por tenant (bank_account vazia é o conjunto geral)
+CREATE TABLE IF NOT EXISTS bank_reconciliation.rule_sets (
+    tenant_id VARCHAR(100) NOT NULL,
+    bank_account VARCHAR(50) NOT NULL DEFAULT '',
+    strategies JSONB NOT NULL,
+    updated_by VARCHAR(100) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (tenant_id, bank_account)
+);
+
+-- Tabela de versões dos templates de notificação por tenant e tipo de evento (a maior versão é a aplicada)
+CREATE TABLE IF NOT EXISTS bank_reconciliation.notification_templates (
+    id VARCHAR(50) PRIMARY KEY,
+    tenant_id VARCHAR(100) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    version INTEGER NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    created_by VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE (tenant_id, event_type, version)
+);
+
+-- Tabela de API keys gerenciadas (apenas o hash do segredo é armazenado)
+CREATE TABLE IF NOT EXISTS bank_reconciliation.api_keys (
+    id VARCHAR(50) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    tenant_id VARCHAR(100) NOT NULL DEFAULT 'default',
+    prefix VARCHAR(20) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    bank_accounts TEXT[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tabela de arquivos bancários importados (numeração sequencial por convênio)
+CREATE TABLE IF NOT EXISTS bank_reconciliation.import_files (
+    id VARCHAR(50) PRIMARY KEY,
+    bank_code VARCHAR(3) NOT NULL,
+    agreement VARCHAR(20) NOT NULL,
+    direction VARCHAR(10) NOT NULL,
+    sequence INTEGER NOT NULL,
+    file_name VARCHAR(255) NOT NULL DEFAULT '',
+    bank_account VARCHAR(50) NOT NULL,
+    records INTEGER NOT NULL,
+    payments INTEGER NOT NULL,
+    generated_at TIMESTAMP NOT NULL,
+    imported_at TIMESTAMP NOT NULL,
+    inconsistencies TEXT[],
+    CONSTRAINT uq_import_files_sequence UNIQUE (bank_code, agreement, direction, sequence)
+);
+
+-- Tabela de entregas de eventos aos assinantes (outbox)
+CREATE TABLE IF NOT EXISTS bank_reconciliation.event_deliveries (
+    id VARCHAR(50) PRIMARY KEY,
+    subscription_id VARCHAR(50) NOT NULL,
+    events JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    discard_reason VARCHAR(500),
+    next_attempt_at TIMESTAMP NOT NULL,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tabela de execuções de conciliação; o hash dos parâmetros torna idempotentes as execuções por janela de datas
+CREATE TABLE IF NOT EXISTS bank_reconciliation.reconciliation_runs (
+    id VARCHAR(50) PRIMARY KEY,
+    params_hash CHAR(64) UNIQUE,
+    tenant VARCHAR(100) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    tolerance DOUBLE PRECISION NOT NULL DEFAULT 0,
+    engine_version VARCHAR(50),
+    result JSONB,
+    metrics JSONB,
+    params JSONB,
+    totals JSONB,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP
+);
+
+-- Tabela de fechamentos diários das conciliações
+CREATE TABLE IF NOT EXISTS bank_reconciliation.daily_closings (
+    id VARCHAR(50) PRIMARY KEY,
+    closing_date DATE NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL,
+    reconciliation_count BIGINT NOT NULL DEFAULT 0,
+    statistics JSONB NOT NULL,
+    created_by VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    confirmed_by VARCHAR(100),
+    confirmed_at TIMESTAMP
+);
+
+-- Tabela da série diária de pendências gravada pelo job noturno de reconciliação incremental
+CREATE TABLE IF NOT EXISTS bank_reconciliation.pending_snapshots (
+    id VARCHAR(50) PRIMARY KEY,
+    snapshot_date DATE NOT NULL UNIQUE,
+    pending_count INTEGER NOT NULL,
+    pending_amount DECIMAL(15, 2) NOT NULL,
+    exited_count INTEGER NOT NULL,
+    entered_count INTEGER NOT NULL,
+    reconciled_in_run INTEGER NOT NULL DEFAULT 0,
+    pending_billet_ids JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- Tabela dos relatórios do job que compara os boletos em aberto do ERP com a base local
+CREATE TABLE IF NOT EXISTS bank_reconciliation.erp_drift_reports (
+    id VARCHAR(50) PRIMARY KEY,
+    checked_at TIMESTAMP NOT NULL,
+    erp_open_count INTEGER NOT NULL,
+    local_open_count INTEGER NOT NULL,
+    missing_locally_count INTEGER NOT NULL,
+    reconciled_locally_count INTEGER NOT NULL,
+    missing_in_erp_count INTEGER NOT NULL,
+    amount_mismatch_count INTEGER NOT NULL,
+    drifts JSONB NOT NULL
+);
+
+-- Tabela do estado da importação delta de boletos da API do ERP: o cursor (data de alteração do último
+-- boleto sincronizado) e o resultado da sincronização mais recente de cada origem
+CREATE TABLE IF NOT EXISTS bank_reconciliation.erp_sync_states (
+    source VARCHAR(500) PRIMARY KEY,
+    sync_cursor TIMESTAMP,
+    synced_at TIMESTAMP NOT NULL,
+    created_count INTEGER NOT NULL,
+    updated_count INTEGER NOT NULL,
+    unchanged_count INTEGER NOT NULL,
+    skipped_count INTEGER NOT NULL,
+    failed_count INTEGER NOT NULL,
+    errors JSONB NOT NULL
+);
+
+-- Tabela dos relatórios da rotina de manutenção: a política de retenção aplicada e o que foi expurgado
+-- ou anonimizado em cada shard
+CREATE TABLE IF NOT EXISTS bank_reconciliation.maintenance_reports (
+    id VARCHAR(50) PRIMARY KEY,
+    started_at TIMESTAMP NOT NULL,
+    duration_ms BIGINT NOT NULL,
+    retention_days JSONB NOT NULL,
+    removed JSONB NOT NULL,
+    shards JSONB NOT NULL
+);
+
+-- Tabela das entradas gravadas da linha do tempo de boletos e pagamentos (comentários, alterações, importações)
+CREATE TABLE IF NOT EXISTS bank_reconciliation.timeline_entries (
+    id VARCHAR(50) PRIMARY KEY,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id VARCHAR(50) NOT NULL,
+    kind VARCHAR(30) NOT NULL,
+    description TEXT NOT NULL,
+    actor VARCHAR(100) NOT NULL DEFAULT '',
+    reference VARCHAR(100) NOT NULL DEFAULT '',
+    occurred_at TIMESTAMP NOT NULL
+);
+
+-- Tabela do cadastro de contas bancárias; contas sem cadastro são consideradas ativas
+CREATE TABLE IF NOT EXISTS bank_reconciliation.bank_accounts (
+    bank_account VARCHAR(50) PRIMARY KEY,
+    status VARCHAR(20) NOT NULL,
+    reason VARCHAR(500) NOT NULL DEFAULT '',
+    updated_by VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- Tabela dos créditos não aplicados: sobras de pagamentos divididos entre boletos do mesmo pagador
+CREATE TABLE IF NOT EXISTS bank_reconciliation.unapplied_credits (
+    id VARCHAR(50) PRIMARY KEY,
+    payer_id VARCHAR(50) NOT NULL,
+    bank_account VARCHAR(50) NOT NULL,
+    origin_transaction_id VARCHAR(50) NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL,
+    balance DECIMAL(15, 2) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_unapplied_credit_transaction_id FOREIGN KEY (origin_transaction_id) REFERENCES bank_reconciliation.payments(id),
+    CONSTRAINT chk_unapplied_credit_balance CHECK (balance >= 0)
+);
+
+-- Tabela do histórico de aplicações dos créditos não aplicados a boletos
+CREATE TABLE IF NOT EXISTS bank_reconciliation.unapplied_credit_applications (
+    id VARCHAR(50) PRIMARY KEY,
+    credit_id VARCHAR(50) NOT NULL,
+    billet_id VARCHAR(50) NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL,
+    applied_by VARCHAR(100) NOT NULL DEFAULT '',
+    applied_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_credit_application_credit_id FOREIGN KEY (credit_id) REFERENCES bank_reconciliation.unapplied_credits(id),
+    CONSTRAINT fk_credit_application_billet_id FOREIGN KEY (billet_id) REFERENCES bank_reconciliation.billets(id)
+);
+
+-- Tabela dos arquivos de resultado das execuções enviados ao ERP, com o conteúdo para reenvio
+CREATE TABLE IF NOT EXISTS bank_reconciliation.result_exports (
+    id VARCHAR(50) PRIMARY KEY,
+    run_id VARCHAR(50) NOT NULL,
+    format VARCHAR(20) NOT NULL,
+    file_key VARCHAR(100) NOT NULL DEFAULT '',
+    sequence INT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    destination VARCHAR(500) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    last_attempt_by VARCHAR(100) NOT NULL DEFAULT '',
+    content BYTEA NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    last_attempt_at TIMESTAMP,
+    sent_at TIMESTAMP,
+    CONSTRAINT uq_result_exports_sequence UNIQUE (format, file_key, sequence)
+);
+
+-- Tabela das mensagens de fila já processadas por cada consumidor, para o descarte das reentregas do broker
+CREATE TABLE IF NOT EXISTS bank_reconciliation.processed_messages (
+    consumer VARCHAR(100) NOT NULL,
+    message_id VARCHAR(255) NOT NULL,
+    payment_id VARCHAR(50),
+    processed_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (consumer, message_id)
+);
+
+-- Tabela dos resultados do motor shadow, comparados boleto a boleto com os de produção de cada execução.
+-- Os registros são removidos junto com a execução
+CREATE TABLE IF NOT EXISTS bank_reconciliation.shadow_reconciliations (
+    id VARCHAR(50) PRIMARY KEY,
+    run_id VARCHAR(50) NOT NULL,
+    billet_id VARCHAR(50) NOT NULL,
+    bank_account VARCHAR(50) NOT NULL,
+    engine_version VARCHAR(50) NOT NULL,
+    primary_transaction_id VARCHAR(50),
+    primary_strategy VARCHAR(50) NOT NULL DEFAULT '',
+    primary_status VARCHAR(50) NOT NULL DEFAULT '',
+    shadow_transaction_id VARCHAR(50),
+    shadow_strategy VARCHAR(50) NOT NULL DEFAULT '',
+    shadow_status VARCHAR(50) NOT NULL DEFAULT '',
+    shadow_amount_diff DECIMAL(15, 2) NOT NULL DEFAULT 0,
+    divergence VARCHAR(30) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_shadow_reconciliation_run_id FOREIGN KEY (run_id) REFERENCES bank_reconciliation.reconciliation_runs(id) ON DELETE CASCADE
+);
+
+-- Tabela de versões do schema aplicadas pelas migrações
+CREATE TABLE IF NOT EXISTS bank_reconciliation.schema_migrations (
+    checksum CHAR(64) PRIMARY KEY,
+    applied_at TIMESTAMP NOT NULL
+);
+
+-- Colunas acrescentadas depois da criação das tabelas: os bancos criados por versões
+-- anteriores do script as recebem aqui, e os novos já as têm pelo CREATE TABLE
+ALTER TABLE bank_reconciliation.billets
+    ADD COLUMN IF NOT EXISTS installment_number INTEGER,
+    ADD COLUMN IF NOT EXISTS contract_id VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS customer_id VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS open_amount BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS bank_code VARCHAR(3) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS bar_code VARCHAR(44) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS digitable_line VARCHAR(48) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS payer_document VARCHAR(14) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS payer_name VARCHAR(150) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS due_date DATE,
+    ADD COLUMN IF NOT EXISTS interest_rate DECIMAL(7, 4) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS fine_percent DECIMAL(7, 4) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS reconciliation_id VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS transaction_id VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'emitido';
+
+ALTER TABLE bank_reconciliation.payments
+    ADD COLUMN IF NOT EXISTS entry_type VARCHAR(10) NOT NULL DEFAULT 'credito',
+    ADD COLUMN IF NOT EXISTS bank_code VARCHAR(3) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS bar_code VARCHAR(48) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS payer_document VARCHAR(14) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS description VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS payment_method VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS review_status VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS review_reason VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS reconciliation_id VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS billet_id VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'recebido';
+
+ALTER TABLE bank_reconciliation.reconciliations
+    ADD COLUMN IF NOT EXISTS time_to_reconcile_seconds BIGINT,
+    ADD COLUMN IF NOT EXISTS run_id VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS transaction_ids VARCHAR(50)[],
+    ADD COLUMN IF NOT EXISTS group_id VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS confidence DECIMAL(3, 2),
+    ADD COLUMN IF NOT EXISTS engine_version VARCHAR(50);
+
+ALTER TABLE bank_reconciliation.reconciliation_runs
+    ADD COLUMN IF NOT EXISTS tolerance DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS metrics JSONB,
+    ADD COLUMN IF NOT EXISTS params JSONB,
+    ADD COLUMN IF NOT EXISTS totals JSONB,
+    ADD COLUMN IF NOT EXISTS engine_version VARCHAR(50);
+
+ALTER TABLE bank_reconciliation.api_keys
+    ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(100) NOT NULL DEFAULT 'default';
+
 -- Índices para melhorar performance de consultas
 
 -- Índices para tabela de boletos
@@ -49,18 +485,66 @@ CREATE INDEX IF NOT EXISTS idx_billets_bank_account ON bank_reconciliation.bille
 CREATE INDEX IF NOT EXISTS idx_billets_reference_id ON bank_reconciliation.billets(reference_id);
 CREATE INDEX IF NOT EXISTS idx_billets_issuance_date ON bank_reconciliation.billets(issuance_date);
 CREATE INDEX IF NOT EXISTS idx_billets_amount ON bank_reconciliation.billets(amount);
+CREATE INDEX IF NOT EXISTS idx_billets_contract_id ON bank_reconciliation.billets(contract_id);
+CREATE INDEX IF NOT EXISTS idx_billets_customer_id ON bank_reconciliation.billets(customer_id);
+CREATE INDEX IF NOT EXISTS idx_billets_reconciliation_id ON bank_reconciliation.billets(reconciliation_id);
 
 -- Índices para tabela de pagamentos
 CREATE INDEX IF NOT EXISTS idx_payments_bank_account ON bank_reconciliation.payments(bank_account);
 CREATE INDEX IF NOT EXISTS idx_payments_reference_id ON bank_reconciliation.payments(reference_id);
 CREATE INDEX IF NOT EXISTS idx_payments_payment_date ON bank_reconciliation.payments(payment_date);
 CREATE INDEX IF NOT EXISTS idx_payments_amount ON bank_reconciliation.payments(amount);
+CREATE INDEX IF NOT EXISTS idx_payments_entry_type ON bank_reconciliation.payments(entry_type);
+CREATE INDEX IF NOT EXISTS idx_payments_review_status ON bank_reconciliation.payments(review_status);
+CREATE INDEX IF NOT EXISTS idx_payments_reconciliation_id ON bank_reconciliation.payments(reconciliation_id);
+
+-- Índices para tabela de API keys
+CREATE INDEX IF NOT EXISTS idx_api_keys_tenant_id ON bank_reconciliation.api_keys(tenant_id, created_at);
+
+-- Índices para tabela de revisões manuais
+CREATE INDEX IF NOT EXISTS idx_match_reviews_tenant_id ON bank_reconciliation.match_reviews(tenant_id, reviewed_at);
+
+-- Índices para o outbox de entregas de eventos
+CREATE INDEX IF NOT EXISTS idx_event_deliveries_status ON bank_reconciliation.event_deliveries(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_event_deliveries_delivered_at ON bank_reconciliation.event_deliveries(delivered_at);
+CREATE INDEX IF NOT EXISTS idx_event_deliveries_events ON bank_reconciliation.event_deliveries USING GIN (events jsonb_path_ops);
+
+-- Índices para tabela da linha do tempo
+CREATE INDEX IF NOT EXISTS idx_timeline_entries_entity ON bank_reconciliation.timeline_entries(entity_type, entity_id, occurred_at);
+
+-- Índices para tabela de créditos não aplicados
+CREATE INDEX IF NOT EXISTS idx_unapplied_credits_payer ON bank_reconciliation.unapplied_credits(payer_id, bank_account);
+CREATE INDEX IF NOT EXISTS idx_unapplied_credit_applications_credit ON bank_reconciliation.unapplied_credit_applications(credit_id, applied_at);
+
+-- Índices para tabela de execuções de conciliação
+CREATE INDEX IF NOT EXISTS idx_reconciliation_runs_started_at ON bank_reconciliation.reconciliation_runs(tenant, started_at);
+
+-- Índices para tabela de mensagens de fila processadas
+CREATE INDEX IF NOT EXISTS idx_processed_messages_processed_at ON bank_reconciliation.processed_messages(processed_at);
+
+-- Índices para tabela de resultados do motor shadow
+CREATE INDEX IF NOT EXISTS idx_shadow_reconciliations_run_id ON bank_reconciliation.shadow_reconciliations(run_id, divergence);
+
+-- Índices para tabela de arquivos de resultado
+CREATE INDEX IF NOT EXISTS idx_result_exports_run_id ON bank_reconciliation.result_exports(run_id, created_at);
+
+-- Índices para tabela de relatórios de divergência com o ERP
+CREATE INDEX IF NOT EXISTS idx_erp_drift_reports_checked_at ON bank_reconciliation.erp_drift_reports(checked_at);
+
+-- Índices para tabela de relatórios da manutenção
+CREATE INDEX IF NOT EXISTS idx_maintenance_reports_started_at ON bank_reconciliation.maintenance_reports(started_at);
 
 -- Índices para tabela de conciliações
 CREATE INDEX IF NOT EXISTS idx_reconciliations_billet_id ON bank_reconciliation.reconciliations(billet_id);
 CREATE INDEX IF NOT EXISTS idx_reconciliations_transaction_id ON bank_reconciliation.reconciliations(transaction_id);
 CREATE INDEX IF NOT EXISTS idx_reconciliations_status ON bank_reconciliation.reconciliations(conciliation_status);
 CREATE INDEX IF NOT EXISTS idx_reconciliations_date ON bank_reconciliation.reconciliations(reconciliation_date);
+CREATE INDEX IF NOT EXISTS idx_reconciliations_run_id ON bank_reconciliation.reconciliations(run_id);
+CREATE INDEX IF NOT EXISTS idx_reconciliations_group_id ON bank_reconciliation.reconciliations(group_id);
+CREATE INDEX IF NOT EXISTS idx_reconciliations_engine_version ON bank_reconciliation.reconciliations(engine_version);
+CREATE INDEX IF NOT EXISTS idx_reconciliation_status_changes_billet_id ON bank_reconciliation.reconciliation_status_changes(billet_id, changed_at);
+CREATE INDEX IF NOT EXISTS idx_reconciliation_undos_billet_id ON bank_reconciliation.reconciliation_undos(billet_id, undone_at);
+CREATE INDEX IF NOT EXISTS idx_reconciliation_approvals_billet_id ON bank_reconciliation.reconciliation_approvals(billet_id, decided_at);
 
 -- Função para atualizar o updated_at automaticamente
 CREATE OR REPLACE FUNCTION bank_reconciliation.update_modified_column()
@@ -72,17 +556,17 @@ END;
 $$ LANGUAGE plpgsql;
 
 -- Triggers para atualizar automaticamente o updated_at
-CREATE TRIGGER update_billets_modtime
+CREATE OR REPLACE TRIGGER update_billets_modtime
 BEFORE UPDATE ON bank_reconciliation.billets
 FOR EACH ROW
 EXECUTE FUNCTION bank_reconciliation.update_modified_column();
 
-CREATE TRIGGER update_payments_modtime
+CREATE OR REPLACE TRIGGER update_payments_modtime
 BEFORE UPDATE ON bank_reconciliation.payments
 FOR EACH ROW
 EXECUTE FUNCTION bank_reconciliation.update_modified_column();
 
-CREATE TRIGGER update_reconciliations_modtime
+CREATE OR REPLACE TRIGGER update_reconciliations_modtime
 BEFORE UPDATE ON bank_reconciliation.reconciliations
 FOR EACH ROW
 EXECUTE FUNCTION bank_reconciliation.update_modified_column();
\ No newline at end of file
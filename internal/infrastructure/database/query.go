@@ -0,0 +1,115 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QualifyColumns prefixa com o alias da tabela cada coluna de uma lista separada por vírgulas, para
+// reaproveitar as listas de colunas dos repositórios em consultas com joins
+func QualifyColumns(alias string, columns string) string {
+	qualified := strings.Split(columns, ",")
+	for i, column := range qualified {
+		qualified[i] = alias + "." + strings.TrimSpace(column)
+	}
+	return strings.Join(qualified, ", ")
+}
+
+// Query monta consultas no dialeto do PostgreSQL. Os trechos são escritos com "?" no lugar dos
+// parâmetros, numerados como $1, $2, ... na ordem em que os argumentos são acrescentados. Os trechos
+// não devem usar "?" para outro fim (ex.: operadores de JSONB)
+type Query struct {
+	base       string
+	conditions []string
+	groupBy    string
+	orderBy    string
+	limit      int
+	offset     int
+	args       []interface{}
+}
+
+// NewQuery inicia a consulta a partir do SELECT ... FROM, com os joins e os argumentos do trecho
+func NewQuery(base string, args ...interface{}) *Query {
+	q := &Query{}
+	q.base = q.bind(base, args)
+	return q
+}
+
+// Where acrescenta uma condição, ligada às demais por AND
+func (q *Query) Where(condition string, args ...interface{}) *Query {
+	q.conditions = append(q.conditions, q.bind(condition, args))
+	return q
+}
+
+// GroupBy define o agrupamento da consulta
+func (q *Query) GroupBy(columns string) *Query {
+	q.groupBy = columns
+	return q
+}
+
+// OrderBy define a ordenação da consulta
+func (q *Query) OrderBy(columns string) *Query {
+	q.orderBy = columns
+	return q
+}
+
+// Page define a paginação da consulta; valores não positivos não limitam o resultado
+func (q *Query) Page(limit, offset int) *Query {
+	q.limit = limit
+	q.offset = offset
+	return q
+}
+
+// Build retorna o SQL da consulta e os argumentos na ordem dos parâmetros
+func (q *Query) Build() (string, []interface{}) {
+	var sql strings.Builder
+	sql.WriteString(q.base)
+
+	if len(q.conditions) > 0 {
+		sql.WriteString("\n\t\tWHERE ")
+		sql.WriteString(strings.Join(q.conditions, " AND "))
+	}
+
+	if q.groupBy != "" {
+		sql.WriteString("\n\t\tGROUP BY ")
+		sql.WriteString(q.groupBy)
+	}
+
+	if q.orderBy != "" {
+		sql.WriteString("\n\t\tORDER BY ")
+		sql.WriteString(q.orderBy)
+	}
+
+	args := append([]interface{}{}, q.args...)
+
+	if q.limit > 0 {
+		args = append(args, q.limit)
+		fmt.Fprintf(&sql, " LIMIT $%d", len(args))
+	}
+
+	if q.offset > 0 {
+		args = append(args, q.offset)
+		fmt.Fprintf(&sql, " OFFSET $%d", len(args))
+	}
+
+	return sql.String(), args
+}
+
+// bind numera os "?" do trecho a partir dos argumentos já acrescentados e acrescenta os do trecho
+func (q *Query) bind(fragment string, args []interface{}) string {
+	var bound strings.Builder
+	for _, part := range strings.SplitAfter(fragment, "?") {
+		// Um "?" sem argumento é mantido e falha na execução da consulta
+		if !strings.HasSuffix(part, "?") || len(args) == 0 {
+			bound.WriteString(part)
+			continue
+		}
+
+		q.args = append(q.args, args[0])
+		args = args[1:]
+		bound.WriteString(strings.TrimSuffix(part, "?"))
+		fmt.Fprintf(&bound, "$%d", len(q.args))
+	}
+
+	return bound.String()
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// Os repositórios de contas ativas decoram os repositórios de boletos e pagamentos impedindo
+// novos registros de contas inativadas no cadastro (model.BankAccount). As demais operações
+// são repassadas sem alteração
+
+// ActiveAccountBilletRepository impede novos registros de boletos de contas inativas
+type ActiveAccountBilletRepository struct {
+	domainRepo.BilletRepository
+	accounts domainRepo.BankAccountRepository
+}
+
+// NewActiveAccountBilletRepository cria uma nova instância de ActiveAccountBilletRepository
+func NewActiveAccountBilletRepository(inner domainRepo.BilletRepository, accounts domainRepo.BankAccountRepository) domainRepo.BilletRepository {
+	return &ActiveAccountBilletRepository{BilletRepository: inner, accounts: accounts}
+}
+
+// Create persiste um novo boleto se a conta estiver ativa
+func (r *ActiveAccountBilletRepository) Create(ctx context.Context, billet *model.Billet) (*model.Billet, error) {
+	if err := ensureActiveAccounts(ctx, r.accounts, billet.BankAccount); err != nil {
+		return nil, err
+	}
+	return r.BilletRepository.Create(ctx, billet)
+}
+
+// CreateMany persiste múltiplos boletos se todas as contas estiverem ativas
+func (r *ActiveAccountBilletRepository) CreateMany(ctx context.Context, billets []*model.Billet) error {
+	accounts := make([]string, 0, len(billets))
+	for _, billet := range billets {
+		accounts = append(accounts, billet.BankAccount)
+	}
+
+	if err := ensureActiveAccounts(ctx, r.accounts, accounts...); err != nil {
+		return err
+	}
+	return r.BilletRepository.CreateMany(ctx, billets)
+}
+
+// ActiveAccountPaymentRepository impede novos registros de pagamentos de contas inativas
+type ActiveAccountPaymentRepository struct {
+	domainRepo.PaymentRepository
+	accounts domainRepo.BankAccountRepository
+}
+
+// NewActiveAccountPaymentRepository cria uma nova instância de ActiveAccountPaymentRepository
+func NewActiveAccountPaymentRepository(inner domainRepo.PaymentRepository, accounts domainRepo.BankAccountRepository) domainRepo.PaymentRepository {
+	return &ActiveAccountPaymentRepository{PaymentRepository: inner, accounts: accounts}
+}
+
+// Create persiste um novo pagamento se a conta estiver ativa
+func (r *ActiveAccountPaymentRepository) Create(ctx context.Context, payment *model.Payment) (*model.Payment, error) {
+	if err := ensureActiveAccounts(ctx, r.accounts, payment.BankAccount); err != nil {
+		return nil, err
+	}
+	return r.PaymentRepository.Create(ctx, payment)
+}
+
+// CreateMany persiste múltiplos pagamentos se todas as contas estiverem ativas
+func (r *ActiveAccountPaymentRepository) CreateMany(ctx context.Context, payments []*model.Payment) error {
+	accounts := make([]string, 0, len(payments))
+	for _, payment := range payments {
+		accounts = append(accounts, payment.BankAccount)
+	}
+
+	if err := ensureActiveAccounts(ctx, r.accounts, accounts...); err != nil {
+		return err
+	}
+	return r.PaymentRepository.CreateMany(ctx, payments)
+}
+
+// CreateFromMessage registra a mensagem de fila e grava o pagamento dela se a conta estiver ativa
+func (r *ActiveAccountPaymentRepository) CreateFromMessage(ctx context.Context, message *model.ProcessedMessage, payment *model.Payment) (bool, error) {
+	if err := ensureActiveAccounts(ctx, r.accounts, payment.BankAccount); err != nil {
+		return false, err
+	}
+	return r.PaymentRepository.CreateFromMessage(ctx, message, payment)
+}
+
+// ensureActiveAccounts retorna errors.ValidationError se alguma das contas estiver inativa
+func ensureActiveAccounts(ctx context.Context, repo domainRepo.BankAccountRepository, accounts ...string) error {
+	checked := make(map[string]bool, len(accounts))
+	for _, account := range accounts {
+		if checked[account] {
+			continue
+		}
+		checked[account] = true
+
+		bankAccount, err := repo.GetByAccount(ctx, account)
+		if err != nil {
+			return err
+		}
+		if !bankAccount.IsActive() {
+			return errors.NewValidationError("bank_account", fmt.Sprintf("conta bancária %s está inativa", account))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+	pkgErrors "conciliacao-bancaria/pkg/errors"
+)
+
+// apiKeyColumns define as colunas lidas em todas as consultas de API keys
+const apiKeyColumns = "id, name, tenant_id, prefix, key_hash, permissions, bank_accounts, expires_at, revoked_at, last_used_at, created_at"
+
+// Garantir que APIKeyRepositoryImpl implementa a interface APIKeyRepository
+var _ domainRepo.APIKeyRepository = (*APIKeyRepositoryImpl)(nil)
+
+// APIKeyRepositoryImpl implementa a interface de repositório para API keys gerenciadas
+type APIKeyRepositoryImpl struct {
+	db database.DB
+}
+
+// NewAPIKeyRepository cria uma nova instância do repositório de API keys
+func NewAPIKeyRepository(db database.DB) domainRepo.APIKeyRepository {
+	return &APIKeyRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create registra uma nova API key
+func (r *APIKeyRepositoryImpl) Create(ctx context.Context, apiKey *model.APIKey) error {
+	query := `
+		INSERT INTO bank_reconciliation.api_keys (` + apiKeyColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	permissions := make([]string, 0, len(apiKey.Permissions))
+	for _, permission := range apiKey.Permissions {
+		permissions = append(permissions, string(permission))
+	}
+
+	_, err := r.db.ExecContext(ctx, query,
+		apiKey.ID,
+		apiKey.Name,
+		apiKey.Tenant,
+		apiKey.Prefix,
+		apiKey.KeyHash,
+		pq.Array(permissions),
+		pq.Array(apiKey.BankAccounts),
+		apiKey.ExpiresAt,
+		apiKey.RevokedAt,
+		apiKey.LastUsedAt,
+		apiKey.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao criar API key: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID recupera uma API key pelo ID
+func (r *APIKeyRepositoryImpl) GetByID(ctx context.Context, id string) (*model.APIKey, error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM bank_reconciliation.api_keys
+		WHERE id = $1
+	`
+
+	apiKey, err := scanAPIKey(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NewNotFoundError("API key", id)
+		}
+		return nil, fmt.Errorf("erro ao buscar API key: %w", err)
+	}
+
+	return apiKey, nil
+}
+
+// GetByHash recupera uma API key pelo hash do segredo, ou nil quando não existe
+func (r *APIKeyRepositoryImpl) GetByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM bank_reconciliation.api_keys
+		WHERE key_hash = $1
+	`
+
+	apiKey, err := scanAPIKey(r.db.QueryRowContext(ctx, query, keyHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar API key: %w", err)
+	}
+
+	return apiKey, nil
+}
+
+// List lista todas as API keys do tenant, inclusive revogadas e expiradas
+func (r *APIKeyRepositoryImpl) List(ctx context.Context, tenant string) ([]*model.APIKey, error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM bank_reconciliation.api_keys
+		WHERE tenant_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tenant)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar API keys: %w", err)
+	}
+	defer rows.Close()
+
+	apiKeys := []*model.APIKey{}
+	for rows.Next() {
+		apiKey, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler API key: %w", err)
+		}
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return apiKeys, nil
+}
+
+// Revoke revoga a API key imediatamente
+func (r *APIKeyRepositoryImpl) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	return r.exec(ctx, id, "erro ao revogar API key",
+		"UPDATE bank_reconciliation.api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL",
+		revokedAt, id)
+}
+
+// UpdateExpiration altera a data de expiração de uma API key
+func (r *APIKeyRepositoryImpl) UpdateExpiration(ctx context.Context, id string, expiresAt time.Time) error {
+	return r.exec(ctx, id, "erro ao atualizar expiração da API key",
+		"UPDATE bank_reconciliation.api_keys SET expires_at = $1 WHERE id = $2",
+		expiresAt, id)
+}
+
+// TouchLastUsed registra o último uso de uma API key
+func (r *APIKeyRepositoryImpl) TouchLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	return r.exec(ctx, id, "erro ao registrar uso da API key",
+		"UPDATE bank_reconciliation.api_keys SET last_used_at = $1 WHERE id = $2",
+		usedAt, id)
+}
+
+// exec executa uma atualização de uma única API key, retornando NotFound quando nenhuma linha é afetada
+func (r *APIKeyRepositoryImpl) exec(ctx context.Context, id, message, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", message, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return pkgErrors.NewNotFoundError("API key", id)
+	}
+
+	return nil
+}
+
+// scanAPIKey lê uma API key a partir de uma linha de resultado
+func scanAPIKey(scanner rowScanner) (*model.APIKey, error) {
+	var apiKey model.APIKey
+	var permissions, bankAccounts []string
+	var expiresAt, revokedAt, lastUsedAt sql.NullTime
+
+	if err := scanner.Scan(
+		&apiKey.ID,
+		&apiKey.Name,
+		&apiKey.Tenant,
+		&apiKey.Prefix,
+		&apiKey.KeyHash,
+		pq.Array(&permissions),
+		pq.Array(&bankAccounts),
+		&expiresAt,
+		&revokedAt,
+		&lastUsedAt,
+		&apiKey.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	for _, permission := range permissions {
+		apiKey.Permissions = append(apiKey.Permissions, model.APIKeyPermission(permission))
+	}
+	apiKey.BankAccounts = bankAccounts
+
+	if expiresAt.Valid {
+		apiKey.ExpiresAt = &expiresAt.Time
+	}
+	if revokedAt.Valid {
+		apiKey.RevokedAt = &revokedAt.Time
+	}
+	if lastUsedAt.Valid {
+		apiKey.LastUsedAt = &lastUsedAt.Time
+	}
+
+	return &apiKey, nil
+}
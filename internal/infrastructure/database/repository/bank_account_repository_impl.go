@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// Garantir que BankAccountRepositoryImpl implementa a interface BankAccountRepository
+var _ domainRepo.BankAccountRepository = (*BankAccountRepositoryImpl)(nil)
+
+// BankAccountRepositoryImpl implementa a interface de repositório para o cadastro de contas bancárias
+type BankAccountRepositoryImpl struct {
+	db database.DB
+}
+
+// NewBankAccountRepository cria uma nova instância do repositório de contas bancárias
+func NewBankAccountRepository(db database.DB) domainRepo.BankAccountRepository {
+	return &BankAccountRepositoryImpl{
+		db: db,
+	}
+}
+
+// bankAccountColumns lista as colunas lidas por scanBankAccount
+const bankAccountColumns = `bank_account, status, reason, updated_by, created_at, updated_at`
+
+// Save grava a situação da conta, criando o cadastro quando ainda não existe
+func (r *BankAccountRepositoryImpl) Save(ctx context.Context, account *model.BankAccount) error {
+	query := `
+		INSERT INTO bank_reconciliation.bank_accounts (` + bankAccountColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (bank_account) DO UPDATE SET
+			status = EXCLUDED.status,
+			reason = EXCLUDED.reason,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		account.Account,
+		string(account.Status),
+		account.Reason,
+		account.UpdatedBy,
+		account.CreatedAt,
+		account.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao gravar conta bancária: %w", err)
+	}
+
+	return nil
+}
+
+// GetByAccount recupera o cadastro da conta, ou nil quando a conta não está cadastrada
+func (r *BankAccountRepositoryImpl) GetByAccount(ctx context.Context, account string) (*model.BankAccount, error) {
+	query := `SELECT ` + bankAccountColumns + `
+		FROM bank_reconciliation.bank_accounts
+		WHERE bank_account = $1
+	`
+
+	bankAccount, err := scanBankAccount(r.db.QueryRowContext(ctx, query, account))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar conta bancária: %w", err)
+	}
+
+	return bankAccount, nil
+}
+
+// GetAll recupera todas as contas cadastradas, ordenadas pelo número da conta
+func (r *BankAccountRepositoryImpl) GetAll(ctx context.Context) ([]*model.BankAccount, error) {
+	query := `SELECT ` + bankAccountColumns + `
+		FROM bank_reconciliation.bank_accounts
+		ORDER BY bank_account
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar contas bancárias: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := []*model.BankAccount{}
+	for rows.Next() {
+		account, err := scanBankAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler conta bancária: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// GetInactive recupera o número das contas inativas
+func (r *BankAccountRepositoryImpl) GetInactive(ctx context.Context) ([]string, error) {
+	query := `
+		SELECT bank_account
+		FROM bank_reconciliation.bank_accounts
+		WHERE status = $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, string(model.BankAccountInactive))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar contas inativas: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := []string{}
+	for rows.Next() {
+		var account string
+		if err := rows.Scan(&account); err != nil {
+			return nil, fmt.Errorf("erro ao ler conta inativa: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// scanBankAccount lê uma conta de uma linha com as colunas de bankAccountColumns
+func scanBankAccount(scanner rowScanner) (*model.BankAccount, error) {
+	var account model.BankAccount
+	var status string
+
+	err := scanner.Scan(
+		&account.Account,
+		&status,
+		&account.Reason,
+		&account.UpdatedBy,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	account.Status = model.BankAccountStatus(status)
+	return &account, nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// Garantir que BilletClaimRepositoryImpl implementa a interface BilletClaimRepository
+var _ domainRepo.BilletClaimRepository = (*BilletClaimRepositoryImpl)(nil)
+
+// BilletClaimRepositoryImpl implementa a interface de repositório para bloqueios de boletos
+type BilletClaimRepositoryImpl struct {
+	db database.DB
+}
+
+// NewBilletClaimRepository cria uma nova instância do repositório de bloqueios de boletos
+func NewBilletClaimRepository(db database.DB) domainRepo.BilletClaimRepository {
+	return &BilletClaimRepositoryImpl{
+		db: db,
+	}
+}
+
+// Acquire registra o bloqueio de forma atômica: o upsert só sobrescreve bloqueios expirados
+// ou do próprio analista, evitando que dois analistas travem o mesmo boleto em paralelo
+func (r *BilletClaimRepositoryImpl) Acquire(ctx context.Context, claim *model.BilletClaim) (*model.BilletClaim, bool, error) {
+	query := `
+		INSERT INTO bank_reconciliation.billet_claims (billet_id, claimed_by, claimed_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (billet_id) DO UPDATE
+		SET claimed_by = EXCLUDED.claimed_by,
+			claimed_at = EXCLUDED.claimed_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE bank_reconciliation.billet_claims.expires_at <= EXCLUDED.claimed_at
+			OR bank_reconciliation.billet_claims.claimed_by = EXCLUDED.claimed_by
+	`
+
+	result, err := r.db.ExecContext(ctx, query, claim.BilletID, claim.ClaimedBy, claim.ClaimedAt, claim.ExpiresAt)
+	if err != nil {
+		return nil, false, fmt.Errorf("erro ao bloquear boleto: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, false, fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected > 0 {
+		return claim, true, nil
+	}
+
+	// Outro analista mantém o bloqueio vigente
+	current, err := r.GetByBilletID(ctx, claim.BilletID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return current, false, nil
+}
+
+// GetByBilletID recupera o bloqueio de um boleto, ou nil quando não existe
+func (r *BilletClaimRepositoryImpl) GetByBilletID(ctx context.Context, billetID string) (*model.BilletClaim, error) {
+	query := `
+		SELECT billet_id, claimed_by, claimed_at, expires_at
+		FROM bank_reconciliation.billet_claims
+		WHERE billet_id = $1
+	`
+
+	var claim model.BilletClaim
+	err := r.db.QueryRowContext(ctx, query, billetID).Scan(
+		&claim.BilletID,
+		&claim.ClaimedBy,
+		&claim.ClaimedAt,
+		&claim.ExpiresAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar bloqueio do boleto: %w", err)
+	}
+
+	return &claim, nil
+}
+
+// Release remove o bloqueio de um boleto mantido pelo analista informado
+func (r *BilletClaimRepositoryImpl) Release(ctx context.Context, billetID, claimedBy string) (bool, error) {
+	query := `
+		DELETE FROM bank_reconciliation.billet_claims
+		WHERE billet_id = $1 AND claimed_by = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, billetID, claimedBy)
+	if err != nil {
+		return false, fmt.Errorf("erro ao liberar bloqueio do boleto: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
@@ -3,52 +3,93 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/lib/pq"
+
 	"conciliacao-bancaria/internal/domain/model"
 	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+	pkgErrors "conciliacao-bancaria/pkg/errors"
 )
 
+// billetColumns lista as colunas lidas nas consultas de boletos, na ordem esperada por scanBillet
+const billetColumns = `id, bank_account, amount, issuance_date, reference_id, installment_number, contract_id, customer_id, open_amount, bank_code, bar_code, digitable_line, payer_document, payer_name, due_date, interest_rate, fine_percent, reconciliation_id, transaction_id, status, created_at, updated_at`
+
+// selectBillets lê os boletos, completado pelos filtros e pela ordenação de cada consulta
+const selectBillets = `
+		SELECT ` + billetColumns + `
+		FROM bank_reconciliation.billets`
+
+// pendingBilletsQuery lê os boletos sem conciliação pareada; os registros nao_conciliado e referencia_ambigua
+// das execuções anteriores são só histórico e não contam como conciliação
+var pendingBilletsQuery = `
+		SELECT ` + database.QualifyColumns("b", billetColumns) + `
+		FROM bank_reconciliation.billets b
+		LEFT JOIN bank_reconciliation.reconciliations r ON b.id = r.billet_id
+			AND r.conciliation_status NOT IN ('nao_conciliado', 'referencia_ambigua')`
+
+// rowScanner abstrai *sql.Row e *sql.Rows para leitura de registros
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// isUniqueViolation identifica a violação de chave única (23505), como um ID já cadastrado
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
 // billetRepositoryImpl implementa a interface BilletRepository
 type billetRepositoryImpl struct {
-	db *sql.DB
+	db database.DB
 }
 
 // NewBilletRepository cria uma nova instância de BilletRepository
-func NewBilletRepository(db *sql.DB) repository.BilletRepository {
+func NewBilletRepository(db database.DB) repository.BilletRepository {
 	return &billetRepositoryImpl{db: db}
 }
 
-// Create persiste um novo boleto no banco de dados
-func (r *billetRepositoryImpl) Create(ctx context.Context, billet *model.Billet) error {
+// Create persiste um novo boleto no banco de dados e retorna o registro gravado
+func (r *billetRepositoryImpl) Create(ctx context.Context, billet *model.Billet) (*model.Billet, error) {
 	query := `
-		INSERT INTO bank_reconciliation.billets 
-		(id, bank_account, amount, issuance_date, reference_id, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`
+		INSERT INTO bank_reconciliation.billets
+		(id, bank_account, amount, issuance_date, reference_id, installment_number, contract_id, customer_id, open_amount, bank_code, bar_code, digitable_line, payer_document, payer_name, due_date, interest_rate, fine_percent, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		RETURNING ` + billetColumns
 
 	now := time.Now()
-	var referenceID *string
-	if billet.ReferenceID != nil {
-		referenceID = billet.ReferenceID
-	}
-
-	_, err := r.db.ExecContext(ctx, query,
+	created, err := scanBillet(r.db.QueryRowContext(ctx, query,
 		billet.ID,
 		billet.BankAccount,
 		billet.Amount,
 		billet.IssuanceDate,
-		referenceID,
+		billet.ReferenceID,
+		billet.InstallmentNumber,
+		billet.ContractID,
+		billet.CustomerID,
+		billet.OpenAmount,
+		billet.BankCode,
+		billet.BarCode,
+		billet.DigitableLine,
+		billet.PayerDocument,
+		billet.PayerName,
+		billet.DueDate,
+		billet.InterestRate,
+		billet.FinePercent,
 		now,
 		now,
-	)
-
+	))
 	if err != nil {
-		return fmt.Errorf("erro ao criar boleto: %w", err)
+		if isUniqueViolation(err) {
+			return nil, pkgErrors.NewConflictError("boleto", billet.ID, "boleto com este ID já existe")
+		}
+		return nil, fmt.Errorf("erro ao criar boleto: %w", err)
 	}
 
-	return nil
+	return created, nil
 }
 
 // CreateMany persiste múltiplos boletos no banco de dados
@@ -59,9 +100,9 @@ func (r *billetRepositoryImpl) CreateMany(ctx context.Context, billets []*model.
 	}
 
 	query := `
-		INSERT INTO bank_reconciliation.billets 
-		(id, bank_account, amount, issuance_date, reference_id, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO bank_reconciliation.billets
+		(id, bank_account, amount, issuance_date, reference_id, installment_number, contract_id, customer_id, open_amount, bank_code, bar_code, digitable_line, payer_document, payer_name, due_date, interest_rate, fine_percent, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
 	`
 
 	stmt, err := tx.PrepareContext(ctx, query)
@@ -85,6 +126,18 @@ func (r *billetRepositoryImpl) CreateMany(ctx context.Context, billets []*model.
 			billet.Amount,
 			billet.IssuanceDate,
 			referenceID,
+			billet.InstallmentNumber,
+			billet.ContractID,
+			billet.CustomerID,
+			billet.OpenAmount,
+			billet.BankCode,
+			billet.BarCode,
+			billet.DigitableLine,
+			billet.PayerDocument,
+			billet.PayerName,
+			billet.DueDate,
+			billet.InterestRate,
+			billet.FinePercent,
 			now,
 			now,
 		)
@@ -105,43 +158,26 @@ func (r *billetRepositoryImpl) CreateMany(ctx context.Context, billets []*model.
 // GetByID recupera um boleto pelo seu ID
 func (r *billetRepositoryImpl) GetByID(ctx context.Context, id string) (*model.Billet, error) {
 	query := `
-		SELECT id, bank_account, amount, issuance_date, reference_id, created_at, updated_at
+		SELECT ` + billetColumns + `
 		FROM bank_reconciliation.billets
 		WHERE id = $1
 	`
 
-	var billet model.Billet
-	var referenceID sql.NullString
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&billet.ID,
-		&billet.BankAccount,
-		&billet.Amount,
-		&billet.IssuanceDate,
-		&referenceID,
-		&billet.CreatedAt,
-		&billet.UpdatedAt,
-	)
-
+	billet, err := scanBillet(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("boleto não encontrado: %s", id)
+			return nil, pkgErrors.NewNotFoundError("boleto", id)
 		}
 		return nil, fmt.Errorf("erro ao buscar boleto: %w", err)
 	}
 
-	if referenceID.Valid {
-		refID := referenceID.String
-		billet.ReferenceID = &refID
-	}
-
-	return &billet, nil
+	return billet, nil
 }
 
 // GetAll recupera todos os boletos
 func (r *billetRepositoryImpl) GetAll(ctx context.Context) ([]*model.Billet, error) {
 	query := `
-		SELECT id, bank_account, amount, issuance_date, reference_id, created_at, updated_at
+		SELECT ` + billetColumns + `
 		FROM bank_reconciliation.billets
 		ORDER BY issuance_date
 	`
@@ -155,29 +191,70 @@ func (r *billetRepositoryImpl) GetAll(ctx context.Context) ([]*model.Billet, err
 	var billets []*model.Billet
 
 	for rows.Next() {
-		var billet model.Billet
-		var referenceID sql.NullString
-
-		err := rows.Scan(
-			&billet.ID,
-			&billet.BankAccount,
-			&billet.Amount,
-			&billet.IssuanceDate,
-			&referenceID,
-			&billet.CreatedAt,
-			&billet.UpdatedAt,
-		)
-
+		billet, err := scanBillet(rows)
 		if err != nil {
 			return nil, fmt.Errorf("erro ao ler boleto: %w", err)
 		}
 
-		if referenceID.Valid {
-			refID := referenceID.String
-			billet.ReferenceID = &refID
+		billets = append(billets, billet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre boletos: %w", err)
+	}
+
+	return billets, nil
+}
+
+// List recupera os boletos que atendem ao filtro, ordenados pela data de emissão
+func (r *billetRepositoryImpl) List(ctx context.Context, filter model.BilletFilter) ([]*model.Billet, error) {
+	q := database.NewQuery(selectBillets)
+
+	if filter.BankAccount != "" {
+		q.Where("bank_account = ?", filter.BankAccount)
+	}
+
+	if filter.ReferenceID != "" {
+		q.Where("reference_id = ?", filter.ReferenceID)
+	}
+
+	if filter.ContractID != "" {
+		q.Where("contract_id = ?", filter.ContractID)
+	}
+
+	if filter.StartDate != nil {
+		q.Where("issuance_date >= ?", *filter.StartDate)
+	}
+
+	if filter.EndDate != nil {
+		q.Where("issuance_date < ?", filter.EndDate.AddDate(0, 0, 1))
+	}
+
+	if filter.MinAmount != nil {
+		q.Where("amount >= ?", *filter.MinAmount)
+	}
+
+	if filter.MaxAmount != nil {
+		q.Where("amount <= ?", *filter.MaxAmount)
+	}
+
+	query, args := q.OrderBy("issuance_date, id").Page(filter.Limit, filter.Offset).Build()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar boletos: %w", err)
+	}
+	defer rows.Close()
+
+	billets := []*model.Billet{}
+
+	for rows.Next() {
+		billet, err := scanBillet(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler boleto: %w", err)
 		}
 
-		billets = append(billets, &billet)
+		billets = append(billets, billet)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -190,7 +267,7 @@ func (r *billetRepositoryImpl) GetAll(ctx context.Context) ([]*model.Billet, err
 // GetByBankAccount recupera boletos por conta bancária
 func (r *billetRepositoryImpl) GetByBankAccount(ctx context.Context, bankAccount string) ([]*model.Billet, error) {
 	query := `
-		SELECT id, bank_account, amount, issuance_date, reference_id, created_at, updated_at
+		SELECT ` + billetColumns + `
 		FROM bank_reconciliation.billets
 		WHERE bank_account = $1
 		ORDER BY issuance_date
@@ -205,29 +282,45 @@ func (r *billetRepositoryImpl) GetByBankAccount(ctx context.Context, bankAccount
 	var billets []*model.Billet
 
 	for rows.Next() {
-		var billet model.Billet
-		var referenceID sql.NullString
-
-		err := rows.Scan(
-			&billet.ID,
-			&billet.BankAccount,
-			&billet.Amount,
-			&billet.IssuanceDate,
-			&referenceID,
-			&billet.CreatedAt,
-			&billet.UpdatedAt,
-		)
-
+		billet, err := scanBillet(rows)
 		if err != nil {
 			return nil, fmt.Errorf("erro ao ler boleto: %w", err)
 		}
 
-		if referenceID.Valid {
-			refID := referenceID.String
-			billet.ReferenceID = &refID
+		billets = append(billets, billet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre boletos: %w", err)
+	}
+
+	return billets, nil
+}
+
+// GetByIDs recupera os boletos com os IDs informados em uma única consulta
+func (r *billetRepositoryImpl) GetByIDs(ctx context.Context, ids []string) ([]*model.Billet, error) {
+	query := `
+		SELECT ` + billetColumns + `
+		FROM bank_reconciliation.billets
+		WHERE id = ANY($1)
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar boletos por IDs: %w", err)
+	}
+	defer rows.Close()
+
+	billets := []*model.Billet{}
+
+	for rows.Next() {
+		billet, err := scanBillet(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler boleto: %w", err)
 		}
 
-		billets = append(billets, &billet)
+		billets = append(billets, billet)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -240,7 +333,7 @@ func (r *billetRepositoryImpl) GetByBankAccount(ctx context.Context, bankAccount
 // GetByReferenceID recupera boletos por ID de referência
 func (r *billetRepositoryImpl) GetByReferenceID(ctx context.Context, referenceID string) ([]*model.Billet, error) {
 	query := `
-		SELECT id, bank_account, amount, issuance_date, reference_id, created_at, updated_at
+		SELECT ` + billetColumns + `
 		FROM bank_reconciliation.billets
 		WHERE reference_id = $1
 		ORDER BY issuance_date
@@ -255,29 +348,12 @@ func (r *billetRepositoryImpl) GetByReferenceID(ctx context.Context, referenceID
 	var billets []*model.Billet
 
 	for rows.Next() {
-		var billet model.Billet
-		var refID sql.NullString
-
-		err := rows.Scan(
-			&billet.ID,
-			&billet.BankAccount,
-			&billet.Amount,
-			&billet.IssuanceDate,
-			&refID,
-			&billet.CreatedAt,
-			&billet.UpdatedAt,
-		)
-
+		billet, err := scanBillet(rows)
 		if err != nil {
 			return nil, fmt.Errorf("erro ao ler boleto: %w", err)
 		}
 
-		if refID.Valid {
-			id := refID.String
-			billet.ReferenceID = &id
-		}
-
-		billets = append(billets, &billet)
+		billets = append(billets, billet)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -287,41 +363,45 @@ func (r *billetRepositoryImpl) GetByReferenceID(ctx context.Context, referenceID
 	return billets, nil
 }
 
-// Update atualiza um boleto existente
-func (r *billetRepositoryImpl) Update(ctx context.Context, billet *model.Billet) error {
+// Update atualiza um boleto existente e retorna o registro gravado
+func (r *billetRepositoryImpl) Update(ctx context.Context, billet *model.Billet) (*model.Billet, error) {
 	query := `
 		UPDATE bank_reconciliation.billets
-		SET bank_account = $1, amount = $2, issuance_date = $3, reference_id = $4
-		WHERE id = $5
-	`
-
-	var referenceID *string
-	if billet.ReferenceID != nil {
-		referenceID = billet.ReferenceID
-	}
-
-	result, err := r.db.ExecContext(ctx, query,
+		SET bank_account = $1, amount = $2, issuance_date = $3, reference_id = $4, installment_number = $5,
+			contract_id = $6, customer_id = $7, open_amount = $8, bank_code = $9, bar_code = $10,
+			digitable_line = $11, payer_document = $12, payer_name = $13, due_date = $14, interest_rate = $15,
+			fine_percent = $16, updated_at = $17
+		WHERE id = $18
+		RETURNING ` + billetColumns
+
+	updated, err := scanBillet(r.db.QueryRowContext(ctx, query,
 		billet.BankAccount,
 		billet.Amount,
 		billet.IssuanceDate,
-		referenceID,
+		billet.ReferenceID,
+		billet.InstallmentNumber,
+		billet.ContractID,
+		billet.CustomerID,
+		billet.OpenAmount,
+		billet.BankCode,
+		billet.BarCode,
+		billet.DigitableLine,
+		billet.PayerDocument,
+		billet.PayerName,
+		billet.DueDate,
+		billet.InterestRate,
+		billet.FinePercent,
+		time.Now(),
 		billet.ID,
-	)
-
+	))
 	if err != nil {
-		return fmt.Errorf("erro ao atualizar boleto: %w", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("boleto não encontrado: %s", billet.ID)
+		if err == sql.ErrNoRows {
+			return nil, pkgErrors.NewNotFoundError("boleto", billet.ID)
+		}
+		return nil, fmt.Errorf("erro ao atualizar boleto: %w", err)
 	}
 
-	return nil
+	return updated, nil
 }
 
 // Delete remove um boleto pelo ID
@@ -342,7 +422,7 @@ func (r *billetRepositoryImpl) Delete(ctx context.Context, id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("boleto não encontrado: %s", id)
+		return pkgErrors.NewNotFoundError("boleto", id)
 	}
 
 	return nil
@@ -350,15 +430,12 @@ func (r *billetRepositoryImpl) Delete(ctx context.Context, id string) error {
 
 // FindNonReconciled encontra boletos que ainda não foram conciliados
 func (r *billetRepositoryImpl) FindNonReconciled(ctx context.Context) ([]*model.Billet, error) {
-	query := `
-		SELECT b.id, b.bank_account, b.amount, b.issuance_date, b.reference_id, b.created_at, b.updated_at
-		FROM bank_reconciliation.billets b
-		LEFT JOIN bank_reconciliation.reconciliations r ON b.id = r.billet_id
-		WHERE r.id IS NULL
-		ORDER BY b.issuance_date
-	`
+	query, args := database.NewQuery(pendingBilletsQuery).
+		Where("r.id IS NULL").
+		OrderBy("b.issuance_date").
+		Build()
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar boletos não conciliados: %w", err)
 	}
@@ -367,29 +444,72 @@ func (r *billetRepositoryImpl) FindNonReconciled(ctx context.Context) ([]*model.
 	var billets []*model.Billet
 
 	for rows.Next() {
-		var billet model.Billet
-		var referenceID sql.NullString
+		billet, err := scanBillet(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler boleto não conciliado: %w", err)
+		}
 
-		err := rows.Scan(
-			&billet.ID,
-			&billet.BankAccount,
-			&billet.Amount,
-			&billet.IssuanceDate,
-			&referenceID,
-			&billet.CreatedAt,
-			&billet.UpdatedAt,
-		)
+		billets = append(billets, billet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre boletos não conciliados: %w", err)
+	}
+
+	return billets, nil
+}
+
+// FindNonReconciledByPeriod encontra boletos ainda não conciliados emitidos no período [start, end]
+func (r *billetRepositoryImpl) FindNonReconciledByPeriod(ctx context.Context, start, end time.Time) ([]*model.Billet, error) {
+	query, args := database.NewQuery(pendingBilletsQuery).
+		Where("r.id IS NULL").
+		Where("b.issuance_date BETWEEN ? AND ?", start, end).
+		OrderBy("b.issuance_date").
+		Build()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar boletos não conciliados do período: %w", err)
+	}
+	defer rows.Close()
+
+	var billets []*model.Billet
 
+	for rows.Next() {
+		billet, err := scanBillet(rows)
 		if err != nil {
 			return nil, fmt.Errorf("erro ao ler boleto não conciliado: %w", err)
 		}
 
-		if referenceID.Valid {
-			refID := referenceID.String
-			billet.ReferenceID = &refID
+		billets = append(billets, billet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre boletos não conciliados: %w", err)
+	}
+
+	return billets, nil
+}
+
+// FindNonReconciledByFilter encontra boletos ainda não conciliados emitidos no período e das contas do filtro
+func (r *billetRepositoryImpl) FindNonReconciledByFilter(ctx context.Context, filter model.PendingFilter) ([]*model.Billet, error) {
+	query, args := pendingBilletsByFilter(filter).OrderBy("b.issuance_date").Build()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar boletos não conciliados do filtro: %w", err)
+	}
+	defer rows.Close()
+
+	var billets []*model.Billet
+
+	for rows.Next() {
+		billet, err := scanBillet(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler boleto não conciliado: %w", err)
 		}
 
-		billets = append(billets, &billet)
+		billets = append(billets, billet)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -398,3 +518,263 @@ func (r *billetRepositoryImpl) FindNonReconciled(ctx context.Context) ([]*model.
 
 	return billets, nil
 }
+
+// StreamNonReconciled percorre os boletos ainda não conciliados do filtro com um cursor, ordenados por
+// conta bancária (em ordem binária, a mesma das strings em Go) e data de emissão
+func (r *billetRepositoryImpl) StreamNonReconciled(ctx context.Context, filter model.PendingFilter, fn func(billet *model.Billet) error) error {
+	query, args := pendingBilletsByFilter(filter).OrderBy(`b.bank_account COLLATE "C", b.issuance_date, b.id`).Build()
+
+	err := streamCursor(ctx, r.db, "pending_billets", query, args, func(rows *sql.Rows) error {
+		billet, err := scanBillet(rows)
+		if err != nil {
+			return fmt.Errorf("erro ao ler boleto não conciliado: %w", err)
+		}
+		return fn(billet)
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao percorrer boletos não conciliados: %w", err)
+	}
+
+	return nil
+}
+
+// pendingBilletsByFilter monta a consulta dos boletos não conciliados do período e das contas do filtro
+func pendingBilletsByFilter(filter model.PendingFilter) *database.Query {
+	q := database.NewQuery(pendingBilletsQuery).Where("r.id IS NULL")
+
+	if !filter.StartDate.IsZero() {
+		q.Where("b.issuance_date >= ?", filter.StartDate)
+	}
+	if !filter.EndDate.IsZero() {
+		q.Where("b.issuance_date <= ?", filter.EndDate)
+	}
+	if len(filter.BankAccounts) > 0 {
+		q.Where("b.bank_account = ANY(?)", pq.Array(filter.BankAccounts))
+	}
+	if filter.MinAmount > 0 {
+		q.Where("b.amount >= ?", filter.MinAmount)
+	}
+	if filter.MaxAmount > 0 {
+		q.Where("b.amount <= ?", filter.MaxAmount)
+	}
+
+	return q
+}
+
+// GetByContractID recupera os boletos de um contrato
+func (r *billetRepositoryImpl) GetByContractID(ctx context.Context, contractID string) ([]*model.Billet, error) {
+	query := `
+		SELECT ` + billetColumns + `
+		FROM bank_reconciliation.billets
+		WHERE contract_id = $1
+		ORDER BY issuance_date
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar boletos por contrato: %w", err)
+	}
+	defer rows.Close()
+
+	var billets []*model.Billet
+
+	for rows.Next() {
+		billet, err := scanBillet(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler boleto: %w", err)
+		}
+
+		billets = append(billets, billet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre boletos: %w", err)
+	}
+
+	return billets, nil
+}
+
+// GetContractStatistics calcula os totais de conciliação dos boletos de um contrato
+func (r *billetRepositoryImpl) GetContractStatistics(ctx context.Context, contractID string) (*model.ContractStatistics, error) {
+	query := `
+		SELECT
+			COUNT(b.id),
+			COUNT(rc.billet_id),
+			COALESCE(SUM(b.amount) FILTER (WHERE rc.billet_id IS NULL), 0),
+			COALESCE(SUM(rc.paid_amount), 0)
+		FROM bank_reconciliation.billets b
+		LEFT JOIN (
+			SELECT r.billet_id, SUM(p.amount) AS paid_amount
+			FROM bank_reconciliation.reconciliations r
+			JOIN bank_reconciliation.payments p ON p.id = r.transaction_id OR p.id = ANY(r.transaction_ids)
+			WHERE r.conciliation_status IN ($2, $3, $4, $5)
+			GROUP BY r.billet_id
+		) rc ON rc.billet_id = b.id
+		WHERE b.contract_id = $1
+	`
+
+	stats := model.ContractStatistics{ContractID: contractID}
+
+	err := r.db.QueryRowContext(ctx, query,
+		contractID,
+		string(model.StatusSuccessful),
+		string(model.StatusDifferentValue),
+		string(model.StatusPartiallyReconciled),
+		string(model.StatusPaidWithInterest),
+	).Scan(
+		&stats.TotalBillets,
+		&stats.ReconciledBillets,
+		&stats.OpenAmount,
+		&stats.PaidAmount,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("erro ao calcular estatísticas do contrato: %w", err)
+	}
+
+	stats.NotReconciledBillets = stats.TotalBillets - stats.ReconciledBillets
+
+	return &stats, nil
+}
+
+// GetAccountPerformances calcula, por conta, os boletos conciliados, o valor pendente e a idade em dias
+// das pendências na data do filtro. Contam como conciliados os mesmos status de GetContractStatistics
+func (r *billetRepositoryImpl) GetAccountPerformances(ctx context.Context, filter model.AccountRankingFilter) ([]*model.AccountPerformance, error) {
+	q := database.NewQuery(`
+		SELECT
+			b.bank_account,
+			COUNT(b.id),
+			COUNT(rc.billet_id),
+			COALESCE(SUM(b.amount) FILTER (WHERE rc.billet_id IS NULL), 0),
+			COALESCE(AVG(EXTRACT(EPOCH FROM (?::timestamp - b.issuance_date)) / 86400) FILTER (WHERE rc.billet_id IS NULL), 0),
+			COALESCE(MAX(EXTRACT(EPOCH FROM (?::timestamp - b.issuance_date)) / 86400) FILTER (WHERE rc.billet_id IS NULL), 0)
+		FROM bank_reconciliation.billets b
+		LEFT JOIN (
+			SELECT DISTINCT r.billet_id
+			FROM bank_reconciliation.reconciliations r
+			WHERE r.conciliation_status IN (?, ?, ?, ?)
+		) rc ON rc.billet_id = b.id`,
+		filter.AsOf,
+		filter.AsOf,
+		string(model.StatusSuccessful),
+		string(model.StatusDifferentValue),
+		string(model.StatusPartiallyReconciled),
+		string(model.StatusPaidWithInterest),
+	)
+
+	if filter.StartDate != nil {
+		q.Where("b.issuance_date >= ?", *filter.StartDate)
+	}
+
+	if filter.EndDate != nil {
+		q.Where("b.issuance_date < ?", filter.EndDate.AddDate(0, 0, 1))
+	}
+
+	query, args := q.GroupBy("b.bank_account").OrderBy("b.bank_account").Build()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao calcular indicadores das contas: %w", err)
+	}
+	defer rows.Close()
+
+	performances := []*model.AccountPerformance{}
+
+	for rows.Next() {
+		performance := &model.AccountPerformance{}
+
+		err := rows.Scan(
+			&performance.BankAccount,
+			&performance.TotalBillets,
+			&performance.ReconciledBillets,
+			&performance.PendingAmount,
+			&performance.AvgPendingAgeDays,
+			&performance.MaxPendingAgeDays,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler indicadores da conta: %w", err)
+		}
+
+		performance.PendingBillets = performance.TotalBillets - performance.ReconciledBillets
+		performances = append(performances, performance)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar indicadores das contas: %w", err)
+	}
+
+	return performances, nil
+}
+
+// scanBillet lê um boleto a partir de uma linha retornada com as colunas de billetColumns
+func scanBillet(scanner rowScanner) (*model.Billet, error) {
+	var billet model.Billet
+	var referenceID sql.NullString
+	var installmentNumber sql.NullInt64
+	var contractID, customerID sql.NullString
+	var dueDate sql.NullTime
+	var reconciliationID, transactionID sql.NullString
+	var status string
+
+	err := scanner.Scan(
+		&billet.ID,
+		&billet.BankAccount,
+		&billet.Amount,
+		&billet.IssuanceDate,
+		&referenceID,
+		&installmentNumber,
+		&contractID,
+		&customerID,
+		&billet.OpenAmount,
+		&billet.BankCode,
+		&billet.BarCode,
+		&billet.DigitableLine,
+		&billet.PayerDocument,
+		&billet.PayerName,
+		&dueDate,
+		&billet.InterestRate,
+		&billet.FinePercent,
+		&reconciliationID,
+		&transactionID,
+		&status,
+		&billet.CreatedAt,
+		&billet.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if referenceID.Valid {
+		refID := referenceID.String
+		billet.ReferenceID = &refID
+	}
+
+	if installmentNumber.Valid {
+		number := int(installmentNumber.Int64)
+		billet.InstallmentNumber = &number
+	}
+
+	if contractID.Valid {
+		id := contractID.String
+		billet.ContractID = &id
+	}
+
+	if dueDate.Valid {
+		billet.DueDate = &dueDate.Time
+	}
+
+	if customerID.Valid {
+		id := customerID.String
+		billet.CustomerID = &id
+	}
+
+	billet.ReconciliationID = reconciliationID.String
+	billet.Status = model.BilletStatus(status)
+
+	if transactionID.Valid {
+		id := transactionID.String
+		billet.TransactionID = &id
+	}
+
+	return &billet, nil
+}
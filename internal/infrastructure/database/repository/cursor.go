@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// streamBatchSize define quantas linhas cada FETCH traz do cursor
+const streamBatchSize = 1000
+
+// streamCursor percorre o resultado da consulta com um cursor do Postgres, trazendo streamBatchSize
+// linhas por vez, e chama scan para cada linha. O cursor vive em uma transação somente leitura
+// desfeita ao final, de forma que só um bloco de linhas fica em memória
+func streamCursor(ctx context.Context, db database.DB, cursor, query string, args []interface{}, scan func(rows *sql.Rows) error) error {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação do cursor: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DECLARE "+cursor+" NO SCROLL CURSOR FOR "+query, args...); err != nil {
+		return fmt.Errorf("erro ao abrir cursor: %w", err)
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM %s", streamBatchSize, cursor)
+	for {
+		rows, err := tx.QueryContext(ctx, fetch)
+		if err != nil {
+			return fmt.Errorf("erro ao ler cursor: %w", err)
+		}
+
+		fetched, err := scanBatch(rows, scan)
+		if err != nil {
+			return err
+		}
+
+		if fetched < streamBatchSize {
+			return nil
+		}
+	}
+}
+
+// scanBatch chama scan para cada linha de um FETCH e retorna a quantidade de linhas lidas
+func scanBatch(rows *sql.Rows, scan func(rows *sql.Rows) error) (int, error) {
+	defer rows.Close()
+
+	fetched := 0
+	for rows.Next() {
+		fetched++
+		if err := scan(rows); err != nil {
+			return fetched, err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fetched, fmt.Errorf("erro ao iterar sobre o cursor: %w", err)
+	}
+
+	return fetched, nil
+}
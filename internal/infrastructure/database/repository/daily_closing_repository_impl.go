@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// Garantir que DailyClosingRepositoryImpl implementa a interface DailyClosingRepository
+var _ domainRepo.DailyClosingRepository = (*DailyClosingRepositoryImpl)(nil)
+
+// DailyClosingRepositoryImpl implementa a interface de repositório para fechamentos diários
+type DailyClosingRepositoryImpl struct {
+	db database.DB
+}
+
+// NewDailyClosingRepository cria uma nova instância do repositório de fechamentos diários
+func NewDailyClosingRepository(db database.DB) domainRepo.DailyClosingRepository {
+	return &DailyClosingRepositoryImpl{
+		db: db,
+	}
+}
+
+// dailyClosingColumns lista as colunas lidas por scanDailyClosing
+const dailyClosingColumns = `
+	id, closing_date, status, reconciliation_count, statistics,
+	created_by, created_at, confirmed_by, confirmed_at`
+
+// Create persiste um novo fechamento; a restrição única do dia impede dois fechamentos para a mesma data
+func (r *DailyClosingRepositoryImpl) Create(ctx context.Context, closing *model.DailyClosing) (bool, error) {
+	statistics, err := json.Marshal(closing.Statistics)
+	if err != nil {
+		return false, fmt.Errorf("erro ao serializar totais do fechamento: %w", err)
+	}
+
+	query := `
+		INSERT INTO bank_reconciliation.daily_closings (
+			id, closing_date, status, reconciliation_count, statistics, created_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (closing_date) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		closing.ID,
+		closing.Date,
+		string(closing.Status),
+		closing.ReconciliationCount,
+		statistics,
+		closing.CreatedBy,
+		closing.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("erro ao criar fechamento diário: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetByID recupera um fechamento pelo seu ID
+func (r *DailyClosingRepositoryImpl) GetByID(ctx context.Context, id string) (*model.DailyClosing, error) {
+	query := `SELECT ` + dailyClosingColumns + `
+		FROM bank_reconciliation.daily_closings
+		WHERE id = $1
+	`
+
+	closing, err := scanDailyClosing(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar fechamento diário: %w", err)
+	}
+
+	return closing, nil
+}
+
+// GetByDate recupera o fechamento do dia, ou nil quando não existe
+func (r *DailyClosingRepositoryImpl) GetByDate(ctx context.Context, date time.Time) (*model.DailyClosing, error) {
+	query := `SELECT ` + dailyClosingColumns + `
+		FROM bank_reconciliation.daily_closings
+		WHERE closing_date = $1
+	`
+
+	closing, err := scanDailyClosing(r.db.QueryRowContext(ctx, query, model.ClosingDay(date)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar fechamento diário: %w", err)
+	}
+
+	return closing, nil
+}
+
+// GetAll recupera todos os fechamentos, do mais recente para o mais antigo
+func (r *DailyClosingRepositoryImpl) GetAll(ctx context.Context) ([]*model.DailyClosing, error) {
+	query := `SELECT ` + dailyClosingColumns + `
+		FROM bank_reconciliation.daily_closings
+		ORDER BY closing_date DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar fechamentos diários: %w", err)
+	}
+	defer rows.Close()
+
+	closings := []*model.DailyClosing{}
+	for rows.Next() {
+		closing, err := scanDailyClosing(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler fechamento diário: %w", err)
+		}
+		closings = append(closings, closing)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return closings, nil
+}
+
+// UpdateStatistics atualiza a prévia dos totais; fechamentos confirmados não são alterados
+func (r *DailyClosingRepositoryImpl) UpdateStatistics(ctx context.Context, closing *model.DailyClosing) error {
+	statistics, err := json.Marshal(closing.Statistics)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar totais do fechamento: %w", err)
+	}
+
+	query := `
+		UPDATE bank_reconciliation.daily_closings
+		SET reconciliation_count = $1, statistics = $2
+		WHERE id = $3 AND status = $4
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, closing.ReconciliationCount, statistics, closing.ID, string(model.ClosingOpen)); err != nil {
+		return fmt.Errorf("erro ao atualizar totais do fechamento: %w", err)
+	}
+
+	return nil
+}
+
+// Confirm grava a confirmação apenas se o fechamento ainda estiver aberto, evitando que duas
+// confirmações concorrentes sobrescrevam os totais congelados
+func (r *DailyClosingRepositoryImpl) Confirm(ctx context.Context, closing *model.DailyClosing) (bool, error) {
+	statistics, err := json.Marshal(closing.Statistics)
+	if err != nil {
+		return false, fmt.Errorf("erro ao serializar totais do fechamento: %w", err)
+	}
+
+	query := `
+		UPDATE bank_reconciliation.daily_closings
+		SET status = $1, reconciliation_count = $2, statistics = $3, confirmed_by = $4, confirmed_at = $5
+		WHERE id = $6 AND status = $7
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		string(model.ClosingConfirmed),
+		closing.ReconciliationCount,
+		statistics,
+		closing.ConfirmedBy,
+		closing.ConfirmedAt,
+		closing.ID,
+		string(model.ClosingOpen),
+	)
+	if err != nil {
+		return false, fmt.Errorf("erro ao confirmar fechamento diário: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// scanDailyClosing lê um fechamento de uma linha com as colunas de dailyClosingColumns
+func scanDailyClosing(scanner rowScanner) (*model.DailyClosing, error) {
+	var closing model.DailyClosing
+	var status string
+	var statistics []byte
+	var confirmedBy sql.NullString
+	var confirmedAt sql.NullTime
+
+	err := scanner.Scan(
+		&closing.ID,
+		&closing.Date,
+		&status,
+		&closing.ReconciliationCount,
+		&statistics,
+		&closing.CreatedBy,
+		&closing.CreatedAt,
+		&confirmedBy,
+		&confirmedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	closing.Status = model.DailyClosingStatus(status)
+	closing.ConfirmedBy = confirmedBy.String
+	if confirmedAt.Valid {
+		closing.ConfirmedAt = &confirmedAt.Time
+	}
+
+	if err := json.Unmarshal(statistics, &closing.Statistics); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar totais do fechamento %s: %w", closing.ID, err)
+	}
+
+	return &closing, nil
+}
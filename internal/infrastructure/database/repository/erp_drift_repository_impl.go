@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// Garantir que ERPDriftRepositoryImpl implementa a interface ERPDriftRepository
+var _ domainRepo.ERPDriftRepository = (*ERPDriftRepositoryImpl)(nil)
+
+// ERPDriftRepositoryImpl implementa a interface de repositório para os relatórios de divergência com o ERP
+type ERPDriftRepositoryImpl struct {
+	db database.DB
+}
+
+// NewERPDriftRepository cria uma nova instância do repositório dos relatórios de divergência com o ERP
+func NewERPDriftRepository(db database.DB) domainRepo.ERPDriftRepository {
+	return &ERPDriftRepositoryImpl{
+		db: db,
+	}
+}
+
+// erpDriftColumns lista as colunas lidas por scanERPDriftReport
+const erpDriftColumns = `
+	id, checked_at, erp_open_count, local_open_count, missing_locally_count,
+	reconciled_locally_count, missing_in_erp_count, amount_mismatch_count, drifts`
+
+// Save grava o relatório de uma verificação com as divergências encontradas
+func (r *ERPDriftRepositoryImpl) Save(ctx context.Context, report *model.ERPDriftReport) error {
+	drifts, err := json.Marshal(report.Drifts)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar divergências com o ERP: %w", err)
+	}
+
+	query := `
+		INSERT INTO bank_reconciliation.erp_drift_reports (
+			id, checked_at, erp_open_count, local_open_count, missing_locally_count,
+			reconciled_locally_count, missing_in_erp_count, amount_mismatch_count, drifts
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		report.ID,
+		report.CheckedAt,
+		report.ERPOpenCount,
+		report.LocalOpenCount,
+		report.MissingLocallyCount,
+		report.ReconciledLocallyCount,
+		report.MissingInERPCount,
+		report.AmountMismatchCount,
+		drifts,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao gravar relatório de divergências com o ERP: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatest recupera o relatório da verificação mais recente
+func (r *ERPDriftRepositoryImpl) GetLatest(ctx context.Context) (*model.ERPDriftReport, error) {
+	query := `SELECT ` + erpDriftColumns + `
+		FROM bank_reconciliation.erp_drift_reports
+		ORDER BY checked_at DESC
+		LIMIT 1
+	`
+
+	report, err := scanERPDriftReport(r.db.QueryRowContext(ctx, query))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar relatório de divergências com o ERP: %w", err)
+	}
+
+	return report, nil
+}
+
+// List recupera os relatórios mais recentes, do mais novo para o mais antigo
+func (r *ERPDriftRepositoryImpl) List(ctx context.Context, limit int) ([]*model.ERPDriftReport, error) {
+	query := `SELECT ` + erpDriftColumns + `
+		FROM bank_reconciliation.erp_drift_reports
+		ORDER BY checked_at DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar relatórios de divergências com o ERP: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []*model.ERPDriftReport{}
+	for rows.Next() {
+		report, err := scanERPDriftReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler relatório de divergências com o ERP: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return reports, nil
+}
+
+// scanERPDriftReport lê um relatório de uma linha com as colunas de erpDriftColumns
+func scanERPDriftReport(scanner rowScanner) (*model.ERPDriftReport, error) {
+	var report model.ERPDriftReport
+	var drifts []byte
+
+	err := scanner.Scan(
+		&report.ID,
+		&report.CheckedAt,
+		&report.ERPOpenCount,
+		&report.LocalOpenCount,
+		&report.MissingLocallyCount,
+		&report.ReconciledLocallyCount,
+		&report.MissingInERPCount,
+		&report.AmountMismatchCount,
+		&drifts,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(drifts, &report.Drifts); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar divergências do relatório %s: %w", report.ID, err)
+	}
+
+	return &report, nil
+}
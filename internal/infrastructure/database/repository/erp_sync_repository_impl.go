@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// Garantir que ERPSyncRepositoryImpl implementa a interface ERPSyncRepository
+var _ domainRepo.ERPSyncRepository = (*ERPSyncRepositoryImpl)(nil)
+
+// ERPSyncRepositoryImpl implementa a interface de repositório para o estado da sincronização dos boletos do ERP
+type ERPSyncRepositoryImpl struct {
+	db database.DB
+}
+
+// NewERPSyncRepository cria uma nova instância do repositório do estado da sincronização dos boletos do ERP
+func NewERPSyncRepository(db database.DB) domainRepo.ERPSyncRepository {
+	return &ERPSyncRepositoryImpl{
+		db: db,
+	}
+}
+
+// Get recupera o estado da sincronização da origem
+func (r *ERPSyncRepositoryImpl) Get(ctx context.Context, source string) (*model.ERPSyncState, error) {
+	query := `
+		SELECT source, sync_cursor, synced_at, created_count, updated_count, unchanged_count,
+			skipped_count, failed_count, errors
+		FROM bank_reconciliation.erp_sync_states
+		WHERE source = $1
+	`
+
+	var state model.ERPSyncState
+	var cursor sql.NullTime
+	var syncErrors []byte
+
+	err := r.db.QueryRowContext(ctx, query, source).Scan(
+		&state.Source,
+		&cursor,
+		&state.SyncedAt,
+		&state.CreatedCount,
+		&state.UpdatedCount,
+		&state.UnchangedCount,
+		&state.SkippedCount,
+		&state.FailedCount,
+		&syncErrors,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar estado da sincronização com o ERP: %w", err)
+	}
+
+	if cursor.Valid {
+		state.Cursor = &cursor.Time
+	}
+	if err := json.Unmarshal(syncErrors, &state.Errors); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar erros da sincronização com o ERP: %w", err)
+	}
+
+	return &state, nil
+}
+
+// Save grava o estado da sincronização da origem, substituindo o anterior
+func (r *ERPSyncRepositoryImpl) Save(ctx context.Context, state *model.ERPSyncState) error {
+	syncErrors, err := json.Marshal(state.Errors)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar erros da sincronização com o ERP: %w", err)
+	}
+
+	query := `
+		INSERT INTO bank_reconciliation.erp_sync_states (
+			source, sync_cursor, synced_at, created_count, updated_count, unchanged_count,
+			skipped_count, failed_count, errors
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (source) DO UPDATE SET
+			sync_cursor = EXCLUDED.sync_cursor,
+			synced_at = EXCLUDED.synced_at,
+			created_count = EXCLUDED.created_count,
+			updated_count = EXCLUDED.updated_count,
+			unchanged_count = EXCLUDED.unchanged_count,
+			skipped_count = EXCLUDED.skipped_count,
+			failed_count = EXCLUDED.failed_count,
+			errors = EXCLUDED.errors
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		state.Source,
+		state.Cursor,
+		state.SyncedAt,
+		state.CreatedCount,
+		state.UpdatedCount,
+		state.UnchangedCount,
+		state.SkippedCount,
+		state.FailedCount,
+		syncErrors,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao gravar estado da sincronização com o ERP: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+	pkgErrors "conciliacao-bancaria/pkg/errors"
+)
+
+// eventDeliveryColumns define as colunas lidas em todas as consultas do outbox
+const eventDeliveryColumns = "id, subscription_id, events, status, attempts, last_error, discard_reason, next_attempt_at, delivered_at, created_at, updated_at"
+
+// Garantir que EventDeliveryRepositoryImpl implementa a interface EventDeliveryRepository
+var _ domainRepo.EventDeliveryRepository = (*EventDeliveryRepositoryImpl)(nil)
+
+// EventDeliveryRepositoryImpl implementa a interface de repositório para o outbox de entregas de eventos
+type EventDeliveryRepositoryImpl struct {
+	db database.DB
+}
+
+// NewEventDeliveryRepository cria uma nova instância do repositório do outbox de eventos
+func NewEventDeliveryRepository(db database.DB) domainRepo.EventDeliveryRepository {
+	return &EventDeliveryRepositoryImpl{
+		db: db,
+	}
+}
+
+// CreateMany registra entregas pendentes no outbox
+func (r *EventDeliveryRepositoryImpl) CreateMany(ctx context.Context, deliveries []*model.EventDelivery) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("falha ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO bank_reconciliation.event_deliveries (`+eventDeliveryColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`)
+	if err != nil {
+		return fmt.Errorf("falha ao preparar declaração: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, delivery := range deliveries {
+		events, err := json.Marshal(delivery.Events)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar eventos da entrega %s: %w", delivery.ID, err)
+		}
+
+		if _, err := stmt.ExecContext(ctx,
+			delivery.ID,
+			delivery.SubscriptionID,
+			events,
+			string(delivery.Status),
+			delivery.Attempts,
+			delivery.LastError,
+			delivery.DiscardReason,
+			delivery.NextAttemptAt,
+			delivery.DeliveredAt,
+			delivery.CreatedAt,
+			delivery.UpdatedAt,
+		); err != nil {
+			return fmt.Errorf("erro ao registrar entrega %s: %w", delivery.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("falha ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID recupera uma entrega pelo ID
+func (r *EventDeliveryRepositoryImpl) GetByID(ctx context.Context, id string) (*model.EventDelivery, error) {
+	query := `
+		SELECT ` + eventDeliveryColumns + `
+		FROM bank_reconciliation.event_deliveries
+		WHERE id = $1
+	`
+
+	delivery, err := scanEventDelivery(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NewNotFoundError("entrega", id)
+		}
+		return nil, fmt.Errorf("erro ao buscar entrega: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// GetByStatus recupera as entregas nas situações informadas, das mais antigas para as mais recentes
+func (r *EventDeliveryRepositoryImpl) GetByStatus(ctx context.Context, statuses []model.DeliveryStatus, limit int) ([]*model.EventDelivery, error) {
+	values := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		values = append(values, string(status))
+	}
+
+	query := `
+		SELECT ` + eventDeliveryColumns + `
+		FROM bank_reconciliation.event_deliveries
+		WHERE status = ANY($1)
+		ORDER BY created_at
+		LIMIT $2
+	`
+
+	return r.query(ctx, query, pq.Array(values), limit)
+}
+
+// GetByBilletID recupera as entregas com eventos do boleto
+func (r *EventDeliveryRepositoryImpl) GetByBilletID(ctx context.Context, billetID string) ([]*model.EventDelivery, error) {
+	return r.getByEventField(ctx, "billet_id", billetID)
+}
+
+// GetByTransactionID recupera as entregas com eventos do pagamento
+func (r *EventDeliveryRepositoryImpl) GetByTransactionID(ctx context.Context, transactionID string) ([]*model.EventDelivery, error) {
+	return r.getByEventField(ctx, "transaction_id", transactionID)
+}
+
+// getByEventField recupera as entregas com algum evento cujo campo tem o valor informado; a contenção
+// JSONB usa o índice GIN de events
+func (r *EventDeliveryRepositoryImpl) getByEventField(ctx context.Context, field, value string) ([]*model.EventDelivery, error) {
+	filter, err := json.Marshal([]map[string]string{{field: value}})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao montar filtro de eventos: %w", err)
+	}
+
+	query := `
+		SELECT ` + eventDeliveryColumns + `
+		FROM bank_reconciliation.event_deliveries
+		WHERE events @> $1::jsonb
+		ORDER BY created_at
+	`
+
+	return r.query(ctx, query, string(filter))
+}
+
+// ClaimDue reserva, pelo tempo de lease, as entregas pendentes cuja próxima tentativa já venceu.
+// O SKIP LOCKED impede que duas instâncias reservem a mesma entrega
+func (r *EventDeliveryRepositoryImpl) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.EventDelivery, error) {
+	query := `
+		UPDATE bank_reconciliation.event_deliveries
+		SET next_attempt_at = $1, updated_at = $2
+		WHERE id IN (
+			SELECT id
+			FROM bank_reconciliation.event_deliveries
+			WHERE status = $3 AND next_attempt_at <= $2
+			ORDER BY next_attempt_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + eventDeliveryColumns
+
+	return r.query(ctx, query, now.Add(lease), now, string(model.DeliveryPending), limit)
+}
+
+// MarkDelivered registra o sucesso de uma entrega
+func (r *EventDeliveryRepositoryImpl) MarkDelivered(ctx context.Context, id string, attempts int, deliveredAt time.Time) error {
+	return r.exec(ctx, id, "erro ao registrar entrega concluída", `
+		UPDATE bank_reconciliation.event_deliveries
+		SET status = $1, attempts = $2, delivered_at = $3, last_error = NULL, updated_at = $3
+		WHERE id = $4
+	`, string(model.DeliveryDelivered), attempts, deliveredAt, id)
+}
+
+// MarkAttemptFailed registra a falha de uma tentativa, com a situação e a próxima tentativa resultantes
+func (r *EventDeliveryRepositoryImpl) MarkAttemptFailed(ctx context.Context, id string, attempts int, lastError string, status model.DeliveryStatus, nextAttemptAt time.Time) error {
+	return r.exec(ctx, id, "erro ao registrar falha da entrega", `
+		UPDATE bank_reconciliation.event_deliveries
+		SET status = $1, attempts = $2, last_error = $3, next_attempt_at = $4, updated_at = $5
+		WHERE id = $6
+	`, string(status), attempts, lastError, nextAttemptAt, time.Now(), id)
+}
+
+// Requeue devolve à fila as entregas pendentes ou com falha, zerando as tentativas
+func (r *EventDeliveryRepositoryImpl) Requeue(ctx context.Context, ids []string, now time.Time) (int, error) {
+	query := `
+		UPDATE bank_reconciliation.event_deliveries
+		SET status = $1, attempts = 0, next_attempt_at = $2, updated_at = $2
+		WHERE id = ANY($3) AND status IN ($1, $4)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, string(model.DeliveryPending), now, pq.Array(ids), string(model.DeliveryFailed))
+	if err != nil {
+		return 0, fmt.Errorf("erro ao reenfileirar entregas: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// Discard descarta uma entrega pendente ou com falha, registrando o motivo
+func (r *EventDeliveryRepositoryImpl) Discard(ctx context.Context, id string, reason string, discardedAt time.Time) error {
+	return r.exec(ctx, id, "erro ao descartar entrega", `
+		UPDATE bank_reconciliation.event_deliveries
+		SET status = $1, discard_reason = $2, updated_at = $3
+		WHERE id = $4 AND status IN ($5, $6)
+	`, string(model.DeliveryDiscarded), reason, discardedAt, id, string(model.DeliveryPending), string(model.DeliveryFailed))
+}
+
+// GetMetrics calcula o volume pendente e o atraso de publicação (criação até entrega) das entregas
+// concluídas desde a data informada
+func (r *EventDeliveryRepositoryImpl) GetMetrics(ctx context.Context, since time.Time) (*model.OutboxMetrics, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE status = $1),
+			COUNT(*) FILTER (WHERE status = $2),
+			COALESCE(EXTRACT(EPOCH FROM ($5 - MIN(created_at) FILTER (WHERE status = $1))), 0),
+			COUNT(*) FILTER (WHERE status = $3 AND delivered_at >= $4),
+			COALESCE(AVG(EXTRACT(EPOCH FROM (delivered_at - created_at))) FILTER (WHERE status = $3 AND delivered_at >= $4), 0),
+			COALESCE(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (delivered_at - created_at)))
+				FILTER (WHERE status = $3 AND delivered_at >= $4), 0),
+			COALESCE(MAX(EXTRACT(EPOCH FROM (delivered_at - created_at))) FILTER (WHERE status = $3 AND delivered_at >= $4), 0)
+		FROM bank_reconciliation.event_deliveries
+	`
+
+	var metrics model.OutboxMetrics
+	err := r.db.QueryRowContext(ctx, query,
+		string(model.DeliveryPending),
+		string(model.DeliveryFailed),
+		string(model.DeliveryDelivered),
+		since,
+		time.Now(),
+	).Scan(
+		&metrics.Pending,
+		&metrics.Failed,
+		&metrics.OldestPendingSeconds,
+		&metrics.DeliveredInWindow,
+		&metrics.AvgPublishDelaySeconds,
+		&metrics.P95PublishDelaySeconds,
+		&metrics.MaxPublishDelaySeconds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao calcular métricas do outbox: %w", err)
+	}
+
+	return &metrics, nil
+}
+
+// query executa uma consulta que retorna entregas
+func (r *EventDeliveryRepositoryImpl) query(ctx context.Context, query string, args ...interface{}) ([]*model.EventDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar entregas: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*model.EventDelivery{}
+	for rows.Next() {
+		delivery, err := scanEventDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler entrega: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// exec executa uma atualização de uma única entrega, retornando NotFound quando nenhuma linha é afetada
+func (r *EventDeliveryRepositoryImpl) exec(ctx context.Context, id, message, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", message, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return pkgErrors.NewNotFoundError("entrega", id)
+	}
+
+	return nil
+}
+
+// scanEventDelivery lê uma entrega a partir de uma linha de resultado
+func scanEventDelivery(scanner rowScanner) (*model.EventDelivery, error) {
+	var delivery model.EventDelivery
+	var events []byte
+	var status string
+	var lastError, discardReason sql.NullString
+	var deliveredAt sql.NullTime
+
+	if err := scanner.Scan(
+		&delivery.ID,
+		&delivery.SubscriptionID,
+		&events,
+		&status,
+		&delivery.Attempts,
+		&lastError,
+		&discardReason,
+		&delivery.NextAttemptAt,
+		&deliveredAt,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(events, &delivery.Events); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar eventos da entrega %s: %w", delivery.ID, err)
+	}
+
+	delivery.Status = model.DeliveryStatus(status)
+	if lastError.Valid {
+		delivery.LastError = &lastError.String
+	}
+	if discardReason.Valid {
+		delivery.DiscardReason = &discardReason.String
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+
+	return &delivery, nil
+}
@@ -0,0 +1,645 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+)
+
+// Os repositórios com falhas decoram os repositórios SQL injetando latência e erros
+// configuráveis por operação, para validar o comportamento dos casos de uso sob falhas
+// parciais do banco. Destinam-se apenas a ambientes de teste
+
+// ErrInjectedFault identifica os erros produzidos pela injeção de falhas
+var ErrInjectedFault = errors.New("falha injetada")
+
+// FaultRule descreve a falha injetada em uma operação de repositório
+type FaultRule struct {
+	// LatencyMS atrasa a operação pelo tempo informado, respeitando o cancelamento do contexto
+	LatencyMS int `json:"latency_ms"`
+
+	// ErrorRate é a probabilidade (entre 0 e 1) de a operação falhar sem chegar ao banco
+	ErrorRate float64 `json:"error_rate"`
+
+	// AfterCalls define quantas chamadas da operação são atendidas antes de a falha começar
+	AfterCalls int `json:"after_calls"`
+
+	// Rollback, em CreateMany, repete o último registro do lote para que a inserção falhe
+	// dentro da transação e o banco desfaça o lote inteiro
+	Rollback bool `json:"rollback"`
+}
+
+// FaultInjector decide, por operação, quando injetar latência e erros
+type FaultInjector struct {
+	mu     sync.Mutex
+	rules  map[string]FaultRule
+	calls  map[string]int
+	random *rand.Rand
+}
+
+// NewFaultInjector cria um injetor com as regras indexadas por operação ("reconciliations.CreateMany").
+// A operação "<repositório>.*" aplica a regra a todos os métodos do repositório
+func NewFaultInjector(rules map[string]FaultRule) *FaultInjector {
+	return &FaultInjector{
+		rules:  rules,
+		calls:  make(map[string]int),
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// NewFaultInjectorFromEnv cria o injetor a partir da variável FAULT_INJECTION, um objeto JSON com as
+// regras por operação, ex.: {"reconciliations.CreateMany": {"rollback": true}, "billets.*": {"latency_ms": 200}}.
+// Retorna nil quando a variável não está definida
+func NewFaultInjectorFromEnv() (*FaultInjector, error) {
+	value := os.Getenv("FAULT_INJECTION")
+	if value == "" {
+		return nil, nil
+	}
+
+	var rules map[string]FaultRule
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		return nil, fmt.Errorf("FAULT_INJECTION inválido: %w", err)
+	}
+
+	for operation, rule := range rules {
+		if rule.ErrorRate < 0 || rule.ErrorRate > 1 {
+			return nil, fmt.Errorf("FAULT_INJECTION inválido: error_rate de %s deve estar entre 0 e 1", operation)
+		}
+		log.Printf("injeção de falhas ativa em %s: %+v", operation, rule)
+	}
+
+	return NewFaultInjector(rules), nil
+}
+
+// rule recupera a regra da operação ou, na falta dela, a regra de todo o repositório
+func (f *FaultInjector) rule(operation string) (FaultRule, bool) {
+	if rule, exists := f.rules[operation]; exists {
+		return rule, true
+	}
+
+	name, _, _ := strings.Cut(operation, ".")
+	rule, exists := f.rules[name+".*"]
+	return rule, exists
+}
+
+// before aplica a regra da operação antes de chamar o repositório decorado. Retorna o erro
+// injetado, se houver, e se o lote do CreateMany deve ser adulterado para forçar o rollback
+func (f *FaultInjector) before(ctx context.Context, operation string) (rollback bool, err error) {
+	if f == nil {
+		return false, nil
+	}
+
+	rule, exists := f.rule(operation)
+	if !exists {
+		return false, nil
+	}
+
+	f.mu.Lock()
+	f.calls[operation]++
+	armed := f.calls[operation] > rule.AfterCalls
+	fail := armed && rule.ErrorRate > 0 && f.random.Float64() < rule.ErrorRate
+	f.mu.Unlock()
+
+	if rule.LatencyMS > 0 {
+		timer := time.NewTimer(time.Duration(rule.LatencyMS) * time.Millisecond)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if fail {
+		return false, fmt.Errorf("%w em %s", ErrInjectedFault, operation)
+	}
+
+	return armed && rule.Rollback, nil
+}
+
+// FaultyBilletRepository injeta falhas nas operações de boletos
+type FaultyBilletRepository struct {
+	inner    domainRepo.BilletRepository
+	injector *FaultInjector
+}
+
+// NewFaultyBilletRepository cria uma nova instância de FaultyBilletRepository.
+// Sem injetor, retorna o próprio repositório decorado
+func NewFaultyBilletRepository(inner domainRepo.BilletRepository, injector *FaultInjector) domainRepo.BilletRepository {
+	if injector == nil {
+		return inner
+	}
+	return &FaultyBilletRepository{inner: inner, injector: injector}
+}
+
+// Create persiste um novo boleto
+func (r *FaultyBilletRepository) Create(ctx context.Context, billet *model.Billet) (*model.Billet, error) {
+	if _, err := r.injector.before(ctx, "billets.Create"); err != nil {
+		return nil, err
+	}
+	return r.inner.Create(ctx, billet)
+}
+
+// CreateMany persiste múltiplos boletos
+func (r *FaultyBilletRepository) CreateMany(ctx context.Context, billets []*model.Billet) error {
+	rollback, err := r.injector.before(ctx, "billets.CreateMany")
+	if err != nil {
+		return err
+	}
+	if rollback && len(billets) > 0 {
+		// Cópia com o último registro repetido, sem alterar o lote do chamador
+		billets = append(billets[:len(billets):len(billets)], billets[len(billets)-1])
+	}
+	return r.inner.CreateMany(ctx, billets)
+}
+
+// GetByID recupera um boleto pelo seu ID
+func (r *FaultyBilletRepository) GetByID(ctx context.Context, id string) (*model.Billet, error) {
+	if _, err := r.injector.before(ctx, "billets.GetByID"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetByID(ctx, id)
+}
+
+// GetAll recupera todos os boletos
+func (r *FaultyBilletRepository) GetAll(ctx context.Context) ([]*model.Billet, error) {
+	if _, err := r.injector.before(ctx, "billets.GetAll"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetAll(ctx)
+}
+
+// List recupera os boletos que atendem ao filtro
+func (r *FaultyBilletRepository) List(ctx context.Context, filter model.BilletFilter) ([]*model.Billet, error) {
+	if _, err := r.injector.before(ctx, "billets.List"); err != nil {
+		return nil, err
+	}
+	return r.inner.List(ctx, filter)
+}
+
+// GetByBankAccount recupera boletos por conta bancária
+func (r *FaultyBilletRepository) GetByBankAccount(ctx context.Context, bankAccount string) ([]*model.Billet, error) {
+	if _, err := r.injector.before(ctx, "billets.GetByBankAccount"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetByBankAccount(ctx, bankAccount)
+}
+
+// GetByIDs recupera boletos pelos IDs
+func (r *FaultyBilletRepository) GetByIDs(ctx context.Context, ids []string) ([]*model.Billet, error) {
+	if _, err := r.injector.before(ctx, "billets.GetByIDs"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetByIDs(ctx, ids)
+}
+
+// GetByReferenceID recupera boletos por ID de referência
+func (r *FaultyBilletRepository) GetByReferenceID(ctx context.Context, referenceID string) ([]*model.Billet, error) {
+	if _, err := r.injector.before(ctx, "billets.GetByReferenceID"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetByReferenceID(ctx, referenceID)
+}
+
+// Update atualiza um boleto existente
+func (r *FaultyBilletRepository) Update(ctx context.Context, billet *model.Billet) (*model.Billet, error) {
+	if _, err := r.injector.before(ctx, "billets.Update"); err != nil {
+		return nil, err
+	}
+	return r.inner.Update(ctx, billet)
+}
+
+// Delete remove um boleto pelo ID
+func (r *FaultyBilletRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.injector.before(ctx, "billets.Delete"); err != nil {
+		return err
+	}
+	return r.inner.Delete(ctx, id)
+}
+
+// FindNonReconciled encontra boletos que ainda não foram conciliados
+func (r *FaultyBilletRepository) FindNonReconciled(ctx context.Context) ([]*model.Billet, error) {
+	if _, err := r.injector.before(ctx, "billets.FindNonReconciled"); err != nil {
+		return nil, err
+	}
+	return r.inner.FindNonReconciled(ctx)
+}
+
+// FindNonReconciledByPeriod encontra boletos ainda não conciliados emitidos no período
+func (r *FaultyBilletRepository) FindNonReconciledByPeriod(ctx context.Context, start, end time.Time) ([]*model.Billet, error) {
+	if _, err := r.injector.before(ctx, "billets.FindNonReconciledByPeriod"); err != nil {
+		return nil, err
+	}
+	return r.inner.FindNonReconciledByPeriod(ctx, start, end)
+}
+
+// FindNonReconciledByFilter encontra boletos ainda não conciliados do período e das contas do filtro
+func (r *FaultyBilletRepository) FindNonReconciledByFilter(ctx context.Context, filter model.PendingFilter) ([]*model.Billet, error) {
+	if _, err := r.injector.before(ctx, "billets.FindNonReconciledByFilter"); err != nil {
+		return nil, err
+	}
+	return r.inner.FindNonReconciledByFilter(ctx, filter)
+}
+
+// StreamNonReconciled percorre os boletos ainda não conciliados do filtro
+func (r *FaultyBilletRepository) StreamNonReconciled(ctx context.Context, filter model.PendingFilter, fn func(billet *model.Billet) error) error {
+	if _, err := r.injector.before(ctx, "billets.StreamNonReconciled"); err != nil {
+		return err
+	}
+	return r.inner.StreamNonReconciled(ctx, filter, fn)
+}
+
+// GetByContractID recupera os boletos de um contrato
+func (r *FaultyBilletRepository) GetByContractID(ctx context.Context, contractID string) ([]*model.Billet, error) {
+	if _, err := r.injector.before(ctx, "billets.GetByContractID"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetByContractID(ctx, contractID)
+}
+
+// GetContractStatistics calcula as estatísticas de um contrato
+func (r *FaultyBilletRepository) GetContractStatistics(ctx context.Context, contractID string) (*model.ContractStatistics, error) {
+	if _, err := r.injector.before(ctx, "billets.GetContractStatistics"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetContractStatistics(ctx, contractID)
+}
+
+// GetAccountPerformances calcula os indicadores de conciliação por conta
+func (r *FaultyBilletRepository) GetAccountPerformances(ctx context.Context, filter model.AccountRankingFilter) ([]*model.AccountPerformance, error) {
+	if _, err := r.injector.before(ctx, "billets.GetAccountPerformances"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetAccountPerformances(ctx, filter)
+}
+
+// FaultyPaymentRepository injeta falhas nas operações de pagamentos
+type FaultyPaymentRepository struct {
+	inner    domainRepo.PaymentRepository
+	injector *FaultInjector
+}
+
+// NewFaultyPaymentRepository cria uma nova instância de FaultyPaymentRepository.
+// Sem injetor, retorna o próprio repositório decorado
+func NewFaultyPaymentRepository(inner domainRepo.PaymentRepository, injector *FaultInjector) domainRepo.PaymentRepository {
+	if injector == nil {
+		return inner
+	}
+	return &FaultyPaymentRepository{inner: inner, injector: injector}
+}
+
+// Create persiste um novo pagamento
+func (r *FaultyPaymentRepository) Create(ctx context.Context, payment *model.Payment) (*model.Payment, error) {
+	if _, err := r.injector.before(ctx, "payments.Create"); err != nil {
+		return nil, err
+	}
+	return r.inner.Create(ctx, payment)
+}
+
+// CreateMany persiste múltiplos pagamentos
+func (r *FaultyPaymentRepository) CreateMany(ctx context.Context, payments []*model.Payment) error {
+	rollback, err := r.injector.before(ctx, "payments.CreateMany")
+	if err != nil {
+		return err
+	}
+	if rollback && len(payments) > 0 {
+		// Cópia com o último registro repetido, sem alterar o lote do chamador
+		payments = append(payments[:len(payments):len(payments)], payments[len(payments)-1])
+	}
+	return r.inner.CreateMany(ctx, payments)
+}
+
+// CreateFromMessage registra a mensagem de fila e grava o pagamento dela
+func (r *FaultyPaymentRepository) CreateFromMessage(ctx context.Context, message *model.ProcessedMessage, payment *model.Payment) (bool, error) {
+	if _, err := r.injector.before(ctx, "payments.CreateFromMessage"); err != nil {
+		return false, err
+	}
+	return r.inner.CreateFromMessage(ctx, message, payment)
+}
+
+// GetByID recupera um pagamento pelo seu ID
+func (r *FaultyPaymentRepository) GetByID(ctx context.Context, id string) (*model.Payment, error) {
+	if _, err := r.injector.before(ctx, "payments.GetByID"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetByID(ctx, id)
+}
+
+// GetAll recupera todos os pagamentos
+func (r *FaultyPaymentRepository) GetAll(ctx context.Context) ([]*model.Payment, error) {
+	if _, err := r.injector.before(ctx, "payments.GetAll"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetAll(ctx)
+}
+
+// List recupera os pagamentos que atendem ao filtro
+func (r *FaultyPaymentRepository) List(ctx context.Context, filter model.PaymentFilter) ([]*model.Payment, error) {
+	if _, err := r.injector.before(ctx, "payments.List"); err != nil {
+		return nil, err
+	}
+	return r.inner.List(ctx, filter)
+}
+
+// GetByBankAccount recupera pagamentos por conta bancária
+func (r *FaultyPaymentRepository) GetByBankAccount(ctx context.Context, bankAccount string) ([]*model.Payment, error) {
+	if _, err := r.injector.before(ctx, "payments.GetByBankAccount"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetByBankAccount(ctx, bankAccount)
+}
+
+// GetByIDs recupera pagamentos pelos IDs
+func (r *FaultyPaymentRepository) GetByIDs(ctx context.Context, ids []string) ([]*model.Payment, error) {
+	if _, err := r.injector.before(ctx, "payments.GetByIDs"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetByIDs(ctx, ids)
+}
+
+// GetByReferenceID recupera pagamentos por ID de referência
+func (r *FaultyPaymentRepository) GetByReferenceID(ctx context.Context, referenceID string) ([]*model.Payment, error) {
+	if _, err := r.injector.before(ctx, "payments.GetByReferenceID"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetByReferenceID(ctx, referenceID)
+}
+
+// GetByEntryType recupera pagamentos pelo tipo de lançamento
+func (r *FaultyPaymentRepository) GetByEntryType(ctx context.Context, entryType model.EntryType) ([]*model.Payment, error) {
+	if _, err := r.injector.before(ctx, "payments.GetByEntryType"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetByEntryType(ctx, entryType)
+}
+
+// Update atualiza um pagamento existente
+func (r *FaultyPaymentRepository) Update(ctx context.Context, payment *model.Payment) (*model.Payment, error) {
+	if _, err := r.injector.before(ctx, "payments.Update"); err != nil {
+		return nil, err
+	}
+	return r.inner.Update(ctx, payment)
+}
+
+// Delete remove um pagamento pelo ID
+func (r *FaultyPaymentRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.injector.before(ctx, "payments.Delete"); err != nil {
+		return err
+	}
+	return r.inner.Delete(ctx, id)
+}
+
+// FindByBankAccountAndAmount encontra pagamentos por conta bancária e valor aproximado
+func (r *FaultyPaymentRepository) FindByBankAccountAndAmount(ctx context.Context, bankAccount string, amount float64, tolerance float64) ([]*model.Payment, error) {
+	if _, err := r.injector.before(ctx, "payments.FindByBankAccountAndAmount"); err != nil {
+		return nil, err
+	}
+	return r.inner.FindByBankAccountAndAmount(ctx, bankAccount, amount, tolerance)
+}
+
+// FindNonReconciled encontra pagamentos que ainda não foram utilizados em conciliações
+func (r *FaultyPaymentRepository) FindNonReconciled(ctx context.Context) ([]*model.Payment, error) {
+	if _, err := r.injector.before(ctx, "payments.FindNonReconciled"); err != nil {
+		return nil, err
+	}
+	return r.inner.FindNonReconciled(ctx)
+}
+
+// FindNonReconciledByPeriod encontra pagamentos ainda não utilizados em conciliações feitos no período
+func (r *FaultyPaymentRepository) FindNonReconciledByPeriod(ctx context.Context, start, end time.Time) ([]*model.Payment, error) {
+	if _, err := r.injector.before(ctx, "payments.FindNonReconciledByPeriod"); err != nil {
+		return nil, err
+	}
+	return r.inner.FindNonReconciledByPeriod(ctx, start, end)
+}
+
+// FindNonReconciledByFilter encontra pagamentos ainda não utilizados em conciliações do período e das contas do filtro
+func (r *FaultyPaymentRepository) FindNonReconciledByFilter(ctx context.Context, filter model.PendingFilter) ([]*model.Payment, error) {
+	if _, err := r.injector.before(ctx, "payments.FindNonReconciledByFilter"); err != nil {
+		return nil, err
+	}
+	return r.inner.FindNonReconciledByFilter(ctx, filter)
+}
+
+// StreamNonReconciled percorre os pagamentos ainda não utilizados em conciliações do filtro
+func (r *FaultyPaymentRepository) StreamNonReconciled(ctx context.Context, filter model.PendingFilter, fn func(payment *model.Payment) error) error {
+	if _, err := r.injector.before(ctx, "payments.StreamNonReconciled"); err != nil {
+		return err
+	}
+	return r.inner.StreamNonReconciled(ctx, filter, fn)
+}
+
+// GetReconciledAmounts recupera os valores dos pagamentos conciliados mais recentes de uma conta
+func (r *FaultyPaymentRepository) GetReconciledAmounts(ctx context.Context, bankAccount string, limit int) ([]float64, error) {
+	if _, err := r.injector.before(ctx, "payments.GetReconciledAmounts"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetReconciledAmounts(ctx, bankAccount, limit)
+}
+
+// GetByReviewStatus recupera pagamentos pela situação de revisão manual
+func (r *FaultyPaymentRepository) GetByReviewStatus(ctx context.Context, status model.PaymentReviewStatus) ([]*model.Payment, error) {
+	if _, err := r.injector.before(ctx, "payments.GetByReviewStatus"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetByReviewStatus(ctx, status)
+}
+
+// UpdateReviewStatus atualiza a situação de revisão manual de um pagamento
+func (r *FaultyPaymentRepository) UpdateReviewStatus(ctx context.Context, id string, status model.PaymentReviewStatus, reason *string) error {
+	if _, err := r.injector.before(ctx, "payments.UpdateReviewStatus"); err != nil {
+		return err
+	}
+	return r.inner.UpdateReviewStatus(ctx, id, status, reason)
+}
+
+// FaultyReconciliationRepository injeta falhas nas operações de conciliações
+type FaultyReconciliationRepository struct {
+	inner    domainRepo.ReconciliationRepository
+	injector *FaultInjector
+}
+
+// NewFaultyReconciliationRepository cria uma nova instância de FaultyReconciliationRepository.
+// Sem injetor, retorna o próprio repositório decorado
+func NewFaultyReconciliationRepository(inner domainRepo.ReconciliationRepository, injector *FaultInjector) domainRepo.ReconciliationRepository {
+	if injector == nil {
+		return inner
+	}
+	return &FaultyReconciliationRepository{inner: inner, injector: injector}
+}
+
+// Create persiste uma nova conciliação
+func (r *FaultyReconciliationRepository) Create(ctx context.Context, reconciliation *model.Reconciliation) error {
+	if _, err := r.injector.before(ctx, "reconciliations.Create"); err != nil {
+		return err
+	}
+	return r.inner.Create(ctx, reconciliation)
+}
+
+// CreateMany persiste múltiplas conciliações
+func (r *FaultyReconciliationRepository) CreateMany(ctx context.Context, reconciliations []*model.Reconciliation) error {
+	rollback, err := r.injector.before(ctx, "reconciliations.CreateMany")
+	if err != nil {
+		return err
+	}
+	if rollback && len(reconciliations) > 0 {
+		// Cópia com o último registro repetido, sem alterar o lote do chamador
+		reconciliations = append(reconciliations[:len(reconciliations):len(reconciliations)], reconciliations[len(reconciliations)-1])
+	}
+	return r.inner.CreateMany(ctx, reconciliations)
+}
+
+// GetByID recupera uma conciliação pelo seu ID
+func (r *FaultyReconciliationRepository) GetByID(ctx context.Context, id string) (*model.Reconciliation, error) {
+	if _, err := r.injector.before(ctx, "reconciliations.GetByID"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetByID(ctx, id)
+}
+
+// GetAll recupera todas as conciliações
+func (r *FaultyReconciliationRepository) GetAll(ctx context.Context) ([]*model.Reconciliation, error) {
+	if _, err := r.injector.before(ctx, "reconciliations.GetAll"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetAll(ctx)
+}
+
+// GetByBilletID recupera conciliações por ID do boleto
+func (r *FaultyReconciliationRepository) GetByBilletID(ctx context.Context, billetID string) ([]*model.Reconciliation, error) {
+	if _, err := r.injector.before(ctx, "reconciliations.GetByBilletID"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetByBilletID(ctx, billetID)
+}
+
+// GetByFilter recupera as conciliações que atendem aos filtros
+func (r *FaultyReconciliationRepository) GetByFilter(ctx context.Context, filter model.ReconciliationFilter) ([]*model.Reconciliation, error) {
+	if _, err := r.injector.before(ctx, "reconciliations.GetByFilter"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetByFilter(ctx, filter)
+}
+
+// GetByTransactionID recupera conciliações por ID da transação
+func (r *FaultyReconciliationRepository) GetByTransactionID(ctx context.Context, transactionID string) ([]*model.Reconciliation, error) {
+	if _, err := r.injector.before(ctx, "reconciliations.GetByTransactionID"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetByTransactionID(ctx, transactionID)
+}
+
+// Update atualiza uma conciliação existente
+func (r *FaultyReconciliationRepository) Update(ctx context.Context, reconciliation *model.Reconciliation) error {
+	if _, err := r.injector.before(ctx, "reconciliations.Update"); err != nil {
+		return err
+	}
+	return r.inner.Update(ctx, reconciliation)
+}
+
+// Delete remove uma conciliação pelo ID
+func (r *FaultyReconciliationRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.injector.before(ctx, "reconciliations.Delete"); err != nil {
+		return err
+	}
+	return r.inner.Delete(ctx, id)
+}
+
+// Undo desfaz as conciliações e grava os registros de auditoria
+func (r *FaultyReconciliationRepository) Undo(ctx context.Context, undos []*model.ReconciliationUndo) error {
+	if _, err := r.injector.before(ctx, "reconciliations.Undo"); err != nil {
+		return err
+	}
+	return r.inner.Undo(ctx, undos)
+}
+
+// Approve aplica as aprovações às conciliações
+func (r *FaultyReconciliationRepository) Approve(ctx context.Context, approvals []*model.ReconciliationApproval) error {
+	if _, err := r.injector.before(ctx, "reconciliations.Approve"); err != nil {
+		return err
+	}
+	return r.inner.Approve(ctx, approvals)
+}
+
+// Reject remove as conciliações rejeitadas
+func (r *FaultyReconciliationRepository) Reject(ctx context.Context, rejections []*model.ReconciliationApproval) error {
+	if _, err := r.injector.before(ctx, "reconciliations.Reject"); err != nil {
+		return err
+	}
+	return r.inner.Reject(ctx, rejections)
+}
+
+// GetApprovals recupera as aprovações e rejeições dos pareamentos de um boleto
+func (r *FaultyReconciliationRepository) GetApprovals(ctx context.Context, billetID string) ([]*model.ReconciliationApproval, error) {
+	if _, err := r.injector.before(ctx, "reconciliations.GetApprovals"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetApprovals(ctx, billetID)
+}
+
+// GetReconciliationHistory recupera o histórico de conciliações para auditoria
+func (r *FaultyReconciliationRepository) GetReconciliationHistory(ctx context.Context, billetID string) ([]*model.Reconciliation, error) {
+	if _, err := r.injector.before(ctx, "reconciliations.GetReconciliationHistory"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetReconciliationHistory(ctx, billetID)
+}
+
+// GetReconciliationHistoryByTransactionID recupera o histórico de conciliações de um pagamento
+func (r *FaultyReconciliationRepository) GetReconciliationHistoryByTransactionID(ctx context.Context, transactionID string) ([]*model.Reconciliation, error) {
+	if _, err := r.injector.before(ctx, "reconciliations.GetReconciliationHistoryByTransactionID"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetReconciliationHistoryByTransactionID(ctx, transactionID)
+}
+
+// UpdateStatus aplica a mudança de status à conciliação
+func (r *FaultyReconciliationRepository) UpdateStatus(ctx context.Context, change *model.ReconciliationStatusChange) error {
+	if _, err := r.injector.before(ctx, "reconciliations.UpdateStatus"); err != nil {
+		return err
+	}
+	return r.inner.UpdateStatus(ctx, change)
+}
+
+// GetStatusChanges recupera o histórico de mudanças de status das conciliações de um boleto
+func (r *FaultyReconciliationRepository) GetStatusChanges(ctx context.Context, billetID string) ([]*model.ReconciliationStatusChange, error) {
+	if _, err := r.injector.before(ctx, "reconciliations.GetStatusChanges"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetStatusChanges(ctx, billetID)
+}
+
+// GetTimeToReconcileStatistics calcula os percentis do tempo entre pagamento e conciliação por conta
+func (r *FaultyReconciliationRepository) GetTimeToReconcileStatistics(ctx context.Context, filter model.TimeToReconcileFilter) ([]*model.TimeToReconcileStatistics, error) {
+	if _, err := r.injector.before(ctx, "reconciliations.GetTimeToReconcileStatistics"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetTimeToReconcileStatistics(ctx, filter)
+}
+
+// GetDailyStatistics calcula os totais de conciliação por dia e conta
+func (r *FaultyReconciliationRepository) GetDailyStatistics(ctx context.Context, filter model.DailyStatisticsFilter) ([]*model.DailyReconciliationStatistics, error) {
+	if _, err := r.injector.before(ctx, "reconciliations.GetDailyStatistics"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetDailyStatistics(ctx, filter)
+}
+
+// GetEngineVersionStatistics calcula os totais de conciliação por dia, versão do motor e conta
+func (r *FaultyReconciliationRepository) GetEngineVersionStatistics(ctx context.Context, filter model.EngineVersionStatisticsFilter) ([]*model.EngineVersionStatistics, error) {
+	if _, err := r.injector.before(ctx, "reconciliations.GetEngineVersionStatistics"); err != nil {
+		return nil, err
+	}
+	return r.inner.GetEngineVersionStatistics(ctx, filter)
+}
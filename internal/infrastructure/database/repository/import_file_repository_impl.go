@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// importFileColumns define as colunas lidas em todas as consultas de arquivos importados
+const importFileColumns = "id, bank_code, agreement, direction, sequence, file_name, bank_account, records, payments, generated_at, imported_at, inconsistencies"
+
+// Garantir que ImportFileRepositoryImpl implementa a interface ImportFileRepository
+var _ domainRepo.ImportFileRepository = (*ImportFileRepositoryImpl)(nil)
+
+// ImportFileRepositoryImpl implementa a interface de repositório para arquivos bancários importados
+type ImportFileRepositoryImpl struct {
+	db database.DB
+}
+
+// NewImportFileRepository cria uma nova instância do repositório de arquivos importados
+func NewImportFileRepository(db database.DB) domainRepo.ImportFileRepository {
+	return &ImportFileRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create registra um arquivo importado
+func (r *ImportFileRepositoryImpl) Create(ctx context.Context, file *model.ImportFile) error {
+	query := `
+		INSERT INTO bank_reconciliation.import_files (` + importFileColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		file.ID,
+		file.BankCode,
+		file.Agreement,
+		file.Direction,
+		file.Sequence,
+		file.FileName,
+		file.BankAccount,
+		file.Records,
+		file.Payments,
+		file.GeneratedAt,
+		file.ImportedAt,
+		pq.Array(file.Inconsistencies),
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar arquivo importado: %w", err)
+	}
+
+	return nil
+}
+
+// GetBySequence recupera o arquivo importado com o número sequencial informado, ou nil quando não existe
+func (r *ImportFileRepositoryImpl) GetBySequence(ctx context.Context, bankCode, agreement, direction string, sequence int) (*model.ImportFile, error) {
+	query := `
+		SELECT ` + importFileColumns + `
+		FROM bank_reconciliation.import_files
+		WHERE bank_code = $1 AND agreement = $2 AND direction = $3 AND sequence = $4
+	`
+
+	file, err := scanImportFile(r.db.QueryRowContext(ctx, query, bankCode, agreement, direction, sequence))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar arquivo importado: %w", err)
+	}
+
+	return file, nil
+}
+
+// GetLastSequence recupera o maior número sequencial importado do convênio, ou 0 quando não há arquivos
+func (r *ImportFileRepositoryImpl) GetLastSequence(ctx context.Context, bankCode, agreement, direction string) (int, error) {
+	query := `
+		SELECT COALESCE(MAX(sequence), 0)
+		FROM bank_reconciliation.import_files
+		WHERE bank_code = $1 AND agreement = $2 AND direction = $3
+	`
+
+	var sequence int
+	if err := r.db.QueryRowContext(ctx, query, bankCode, agreement, direction).Scan(&sequence); err != nil {
+		return 0, fmt.Errorf("erro ao buscar último número sequencial: %w", err)
+	}
+
+	return sequence, nil
+}
+
+// GetAll recupera todos os arquivos importados
+func (r *ImportFileRepositoryImpl) GetAll(ctx context.Context) ([]*model.ImportFile, error) {
+	query := `
+		SELECT ` + importFileColumns + `
+		FROM bank_reconciliation.import_files
+		ORDER BY bank_code, agreement, direction, sequence
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar arquivos importados: %w", err)
+	}
+	defer rows.Close()
+
+	files := []*model.ImportFile{}
+	for rows.Next() {
+		file, err := scanImportFile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler arquivo importado: %w", err)
+		}
+		files = append(files, file)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return files, nil
+}
+
+// FindGaps encontra as lacunas na numeração sequencial dos arquivos de cada convênio, comparando
+// cada arquivo com o anterior na mesma sequência
+func (r *ImportFileRepositoryImpl) FindGaps(ctx context.Context) ([]*model.ImportGap, error) {
+	query := `
+		SELECT bank_code, agreement, direction, previous_sequence, sequence
+		FROM (
+			SELECT bank_code, agreement, direction, sequence,
+				LAG(sequence) OVER (PARTITION BY bank_code, agreement, direction ORDER BY sequence) AS previous_sequence
+			FROM bank_reconciliation.import_files
+		) sequences
+		WHERE previous_sequence IS NOT NULL AND sequence - previous_sequence > 1
+		ORDER BY bank_code, agreement, direction, sequence
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar lacunas de sequência: %w", err)
+	}
+	defer rows.Close()
+
+	gaps := []*model.ImportGap{}
+	for rows.Next() {
+		var bankCode, agreement, direction string
+		var previousSequence, nextSequence int
+
+		if err := rows.Scan(&bankCode, &agreement, &direction, &previousSequence, &nextSequence); err != nil {
+			return nil, fmt.Errorf("erro ao ler lacuna de sequência: %w", err)
+		}
+		gaps = append(gaps, model.NewImportGap(bankCode, agreement, direction, previousSequence, nextSequence))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return gaps, nil
+}
+
+// scanImportFile lê um arquivo importado a partir de uma linha de resultado
+func scanImportFile(scanner rowScanner) (*model.ImportFile, error) {
+	var file model.ImportFile
+
+	if err := scanner.Scan(
+		&file.ID,
+		&file.BankCode,
+		&file.Agreement,
+		&file.Direction,
+		&file.Sequence,
+		&file.FileName,
+		&file.BankAccount,
+		&file.Records,
+		&file.Payments,
+		&file.GeneratedAt,
+		&file.ImportedAt,
+		pq.Array(&file.Inconsistencies),
+	); err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
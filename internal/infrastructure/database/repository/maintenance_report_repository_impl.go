@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// Garantir que MaintenanceReportRepositoryImpl implementa a interface MaintenanceReportRepository
+var _ domainRepo.MaintenanceReportRepository = (*MaintenanceReportRepositoryImpl)(nil)
+
+// MaintenanceReportRepositoryImpl implementa a interface de repositório para os relatórios da manutenção
+type MaintenanceReportRepositoryImpl struct {
+	db database.DB
+}
+
+// NewMaintenanceReportRepository cria uma nova instância do repositório dos relatórios da manutenção
+func NewMaintenanceReportRepository(db database.DB) domainRepo.MaintenanceReportRepository {
+	return &MaintenanceReportRepositoryImpl{
+		db: db,
+	}
+}
+
+// Save grava o relatório de uma execução da manutenção
+func (r *MaintenanceReportRepositoryImpl) Save(ctx context.Context, report *model.MaintenanceReport) error {
+	retentionDays, err := json.Marshal(report.RetentionDays)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar política de retenção: %w", err)
+	}
+	removed, err := json.Marshal(report.Removed)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar registros expurgados: %w", err)
+	}
+	shards, err := json.Marshal(report.Shards)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar registros expurgados por shard: %w", err)
+	}
+
+	query := `
+		INSERT INTO bank_reconciliation.maintenance_reports (id, started_at, duration_ms, retention_days, removed, shards)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		report.ID,
+		report.StartedAt,
+		report.DurationMs,
+		retentionDays,
+		removed,
+		shards,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao gravar relatório da manutenção: %w", err)
+	}
+
+	return nil
+}
+
+// List recupera os relatórios mais recentes, do mais novo para o mais antigo
+func (r *MaintenanceReportRepositoryImpl) List(ctx context.Context, limit int) ([]*model.MaintenanceReport, error) {
+	query := `
+		SELECT id, started_at, duration_ms, retention_days, removed, shards
+		FROM bank_reconciliation.maintenance_reports
+		ORDER BY started_at DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar relatórios da manutenção: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []*model.MaintenanceReport{}
+	for rows.Next() {
+		report, err := scanMaintenanceReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler relatório da manutenção: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return reports, nil
+}
+
+// scanMaintenanceReport lê um relatório da manutenção de uma linha
+func scanMaintenanceReport(scanner rowScanner) (*model.MaintenanceReport, error) {
+	var report model.MaintenanceReport
+	var retentionDays, removed, shards []byte
+
+	if err := scanner.Scan(&report.ID, &report.StartedAt, &report.DurationMs, &retentionDays, &removed, &shards); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(retentionDays, &report.RetentionDays); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar política de retenção do relatório %s: %w", report.ID, err)
+	}
+	if err := json.Unmarshal(removed, &report.Removed); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar registros expurgados do relatório %s: %w", report.ID, err)
+	}
+	if err := json.Unmarshal(shards, &report.Shards); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar registros expurgados por shard do relatório %s: %w", report.ID, err)
+	}
+
+	return &report, nil
+}
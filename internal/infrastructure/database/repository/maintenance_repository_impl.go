@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// Garantir que MaintenanceRepositoryImpl implementa a interface MaintenanceRepository
+var _ domainRepo.MaintenanceRepository = (*MaintenanceRepositoryImpl)(nil)
+
+// MaintenanceRepositoryImpl implementa os expurgos da rotina de manutenção sobre um banco. Com shards,
+// é criada uma instância por shard, pois a manutenção não é feita em nome de um tenant
+type MaintenanceRepositoryImpl struct {
+	db database.DB
+}
+
+// NewMaintenanceRepository cria uma nova instância do repositório de manutenção
+func NewMaintenanceRepository(db database.DB) domainRepo.MaintenanceRepository {
+	return &MaintenanceRepositoryImpl{
+		db: db,
+	}
+}
+
+// PurgeCompletedRuns remove as execuções concluídas antes do instante informado
+func (r *MaintenanceRepositoryImpl) PurgeCompletedRuns(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM bank_reconciliation.reconciliation_runs
+		WHERE status = $1 AND finished_at < $2
+	`
+
+	return r.exec(ctx, "expurgar execuções de conciliação", query, string(model.RunCompleted), before)
+}
+
+// ExpireIdempotencyKeys descarta o hash de parâmetros das execuções concluídas antes do instante informado
+func (r *MaintenanceRepositoryImpl) ExpireIdempotencyKeys(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		UPDATE bank_reconciliation.reconciliation_runs
+		SET params_hash = NULL
+		WHERE params_hash IS NOT NULL AND status = $1 AND finished_at < $2
+	`
+
+	return r.exec(ctx, "expirar chaves de idempotência", query, string(model.RunCompleted), before)
+}
+
+// PurgeEventDeliveries remove as entregas entregues ou descartadas antes do instante informado
+func (r *MaintenanceRepositoryImpl) PurgeEventDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM bank_reconciliation.event_deliveries
+		WHERE status IN ($1, $2) AND COALESCE(delivered_at, updated_at) < $3
+	`
+
+	return r.exec(ctx, "expurgar entregas de webhook", query,
+		string(model.DeliveryDelivered), string(model.DeliveryDiscarded), before)
+}
+
+// PurgePendingSnapshots remove os retratos das pendências de dias anteriores ao informado
+func (r *MaintenanceRepositoryImpl) PurgePendingSnapshots(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM bank_reconciliation.pending_snapshots
+		WHERE snapshot_date < $1
+	`
+
+	return r.exec(ctx, "expurgar retratos das pendências", query, model.ClosingDay(before))
+}
+
+// PurgeExpiredClaims remove os bloqueios de boletos vencidos no instante informado
+func (r *MaintenanceRepositoryImpl) PurgeExpiredClaims(ctx context.Context, now time.Time) (int64, error) {
+	query := `
+		DELETE FROM bank_reconciliation.billet_claims
+		WHERE expires_at < $1
+	`
+
+	return r.exec(ctx, "expurgar bloqueios vencidos", query, now)
+}
+
+// PurgeProcessedMessages remove os registros das mensagens de fila processadas antes do instante informado
+func (r *MaintenanceRepositoryImpl) PurgeProcessedMessages(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM bank_reconciliation.processed_messages
+		WHERE processed_at < $1
+	`
+
+	return r.exec(ctx, "expurgar mensagens processadas", query, before)
+}
+
+// PurgeAuditTrail remove os registros da trilha de auditoria anteriores ao instante informado, somando
+// os removidos de cada tabela
+func (r *MaintenanceRepositoryImpl) PurgeAuditTrail(ctx context.Context, before time.Time) (int64, error) {
+	tables := []struct {
+		table  string
+		column string
+	}{
+		{"reconciliation_status_changes", "changed_at"},
+		{"reconciliation_undos", "undone_at"},
+		{"reconciliation_approvals", "decided_at"},
+		{"timeline_entries", "occurred_at"},
+	}
+
+	var total int64
+	for _, t := range tables {
+		query := `DELETE FROM bank_reconciliation.` + t.table + ` WHERE ` + t.column + ` < $1`
+
+		removed, err := r.exec(ctx, "expurgar "+t.table, query, before)
+		if err != nil {
+			return total, err
+		}
+		total += removed
+	}
+
+	return total, nil
+}
+
+// AnonymizeBilletPayers apaga o documento e o nome do pagador dos boletos conciliados antes do instante informado
+func (r *MaintenanceRepositoryImpl) AnonymizeBilletPayers(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		UPDATE bank_reconciliation.billets b
+		SET payer_document = '', payer_name = '', updated_at = CURRENT_TIMESTAMP
+		FROM bank_reconciliation.reconciliations r
+		WHERE r.id = b.reconciliation_id AND (b.payer_document <> '' OR b.payer_name <> '')
+			AND r.reconciliation_date < $1
+	`
+
+	return r.exec(ctx, "anonimizar pagadores dos boletos", query, before)
+}
+
+// AnonymizePaymentPayers apaga o documento do pagador e a descrição, que costuma trazer o nome de quem
+// pagou, dos pagamentos conciliados antes do instante informado
+func (r *MaintenanceRepositoryImpl) AnonymizePaymentPayers(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		UPDATE bank_reconciliation.payments p
+		SET payer_document = '', description = '', updated_at = CURRENT_TIMESTAMP
+		FROM bank_reconciliation.reconciliations r
+		WHERE r.id = p.reconciliation_id AND (p.payer_document <> '' OR p.description <> '')
+			AND r.reconciliation_date < $1
+	`
+
+	return r.exec(ctx, "anonimizar pagadores dos pagamentos", query, before)
+}
+
+// exec executa o expurgo e retorna a quantidade de registros afetados
+func (r *MaintenanceRepositoryImpl) exec(ctx context.Context, operation, query string, args ...interface{}) (int64, error) {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao %s: %w", operation, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// Garantir que MatchReviewRepositoryImpl implementa a interface MatchReviewRepository
+var _ domainRepo.MatchReviewRepository = (*MatchReviewRepositoryImpl)(nil)
+
+// MatchReviewRepositoryImpl implementa a interface de repositório para revisões manuais de matches
+type MatchReviewRepositoryImpl struct {
+	db database.DB
+}
+
+// NewMatchReviewRepository cria uma nova instância do repositório de revisões manuais
+func NewMatchReviewRepository(db database.DB) domainRepo.MatchReviewRepository {
+	return &MatchReviewRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create registra uma revisão manual
+func (r *MatchReviewRepositoryImpl) Create(ctx context.Context, review *model.MatchReview) error {
+	query := `
+		INSERT INTO bank_reconciliation.match_reviews (
+			id, tenant_id, billet_id, transaction_id, approved, reviewer,
+			amount_diff_percentage, days_diff, partial_reference, reviewed_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		review.ID,
+		review.TenantID,
+		review.BilletID,
+		review.TransactionID,
+		review.Approved,
+		review.Reviewer,
+		review.Features.AmountDiffPercentage,
+		review.Features.DaysDiff,
+		review.Features.PartialReference,
+		review.ReviewedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar revisão manual: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTenant recupera as revisões de um tenant, da mais antiga para a mais recente
+func (r *MatchReviewRepositoryImpl) GetByTenant(ctx context.Context, tenantID string) ([]*model.MatchReview, error) {
+	query := `
+		SELECT id, tenant_id, billet_id, transaction_id, approved, reviewer,
+			amount_diff_percentage, days_diff, partial_reference, reviewed_at
+		FROM bank_reconciliation.match_reviews
+		WHERE tenant_id = $1
+		ORDER BY reviewed_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar revisões manuais: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []*model.MatchReview
+	for rows.Next() {
+		var review model.MatchReview
+		if err := rows.Scan(
+			&review.ID,
+			&review.TenantID,
+			&review.BilletID,
+			&review.TransactionID,
+			&review.Approved,
+			&review.Reviewer,
+			&review.Features.AmountDiffPercentage,
+			&review.Features.DaysDiff,
+			&review.Features.PartialReference,
+			&review.ReviewedAt,
+		); err != nil {
+			return nil, fmt.Errorf("erro ao ler revisão manual: %w", err)
+		}
+		reviews = append(reviews, &review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre as revisões: %w", err)
+	}
+
+	return reviews, nil
+}
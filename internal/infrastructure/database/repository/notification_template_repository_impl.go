@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+	pkgErrors "conciliacao-bancaria/pkg/errors"
+)
+
+// notificationTemplateColumns define as colunas lidas em todas as consultas de templates de notificação
+const notificationTemplateColumns = "id, tenant_id, event_type, version, subject, body, created_by, created_at"
+
+// Garantir que NotificationTemplateRepositoryImpl implementa a interface NotificationTemplateRepository
+var _ domainRepo.NotificationTemplateRepository = (*NotificationTemplateRepositoryImpl)(nil)
+
+// NotificationTemplateRepositoryImpl implementa a interface de repositório para os templates de notificação
+type NotificationTemplateRepositoryImpl struct {
+	db database.DB
+}
+
+// NewNotificationTemplateRepository cria uma nova instância do repositório de templates de notificação
+func NewNotificationTemplateRepository(db database.DB) domainRepo.NotificationTemplateRepository {
+	return &NotificationTemplateRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create grava uma nova versão do template, atribuindo a ela o número seguinte ao da última versão.
+// Duas gravações simultâneas do mesmo template esbarram na unicidade da versão e uma delas falha
+func (r *NotificationTemplateRepositoryImpl) Create(ctx context.Context, notificationTemplate *model.NotificationTemplate) error {
+	query := `
+		INSERT INTO bank_reconciliation.notification_templates (` + notificationTemplateColumns + `)
+		SELECT $1::VARCHAR, $2::VARCHAR, $3::VARCHAR, COALESCE(MAX(version), 0) + 1, $4::TEXT, $5::TEXT, $6::VARCHAR, $7::TIMESTAMP
+		FROM bank_reconciliation.notification_templates
+		WHERE tenant_id = $2 AND event_type = $3
+		RETURNING version
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		notificationTemplate.ID,
+		notificationTemplate.Tenant,
+		notificationTemplate.EventType,
+		notificationTemplate.Subject,
+		notificationTemplate.Body,
+		notificationTemplate.CreatedBy,
+		notificationTemplate.CreatedAt,
+	).Scan(&notificationTemplate.Version)
+	if err != nil {
+		return fmt.Errorf("erro ao gravar template de notificação: %w", err)
+	}
+
+	return nil
+}
+
+// GetCurrent recupera a última versão do template de cada tipo de evento do tenant
+func (r *NotificationTemplateRepositoryImpl) GetCurrent(ctx context.Context, tenant string) ([]*model.NotificationTemplate, error) {
+	query := `
+		SELECT DISTINCT ON (event_type) ` + notificationTemplateColumns + `
+		FROM bank_reconciliation.notification_templates
+		WHERE tenant_id = $1
+		ORDER BY event_type, version DESC
+	`
+
+	return r.query(ctx, query, tenant)
+}
+
+// GetVersions recupera as versões do template do tenant para o tipo de evento, da mais recente à mais antiga
+func (r *NotificationTemplateRepositoryImpl) GetVersions(ctx context.Context, tenant string, eventType model.EventType) ([]*model.NotificationTemplate, error) {
+	query := `
+		SELECT ` + notificationTemplateColumns + `
+		FROM bank_reconciliation.notification_templates
+		WHERE tenant_id = $1 AND event_type = $2
+		ORDER BY version DESC
+	`
+
+	return r.query(ctx, query, tenant, eventType)
+}
+
+// GetVersion recupera uma versão do template do tenant para o tipo de evento
+func (r *NotificationTemplateRepositoryImpl) GetVersion(ctx context.Context, tenant string, eventType model.EventType, version int) (*model.NotificationTemplate, error) {
+	query := `
+		SELECT ` + notificationTemplateColumns + `
+		FROM bank_reconciliation.notification_templates
+		WHERE tenant_id = $1 AND event_type = $2 AND version = $3
+	`
+
+	notificationTemplate, err := scanNotificationTemplate(r.db.QueryRowContext(ctx, query, tenant, eventType, version))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NewNotFoundError("template de notificação", fmt.Sprintf("%s/%d", eventType, version))
+		}
+		return nil, fmt.Errorf("erro ao buscar template de notificação: %w", err)
+	}
+
+	return notificationTemplate, nil
+}
+
+// query executa a consulta e lê os templates retornados
+func (r *NotificationTemplateRepositoryImpl) query(ctx context.Context, query string, args ...interface{}) ([]*model.NotificationTemplate, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar templates de notificação: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []*model.NotificationTemplate{}
+	for rows.Next() {
+		notificationTemplate, err := scanNotificationTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler template de notificação: %w", err)
+		}
+		templates = append(templates, notificationTemplate)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return templates, nil
+}
+
+// scanNotificationTemplate lê um template de uma linha com as colunas de notificationTemplateColumns
+func scanNotificationTemplate(scanner rowScanner) (*model.NotificationTemplate, error) {
+	var notificationTemplate model.NotificationTemplate
+
+	err := scanner.Scan(
+		&notificationTemplate.ID,
+		&notificationTemplate.Tenant,
+		&notificationTemplate.EventType,
+		&notificationTemplate.Version,
+		&notificationTemplate.Subject,
+		&notificationTemplate.Body,
+		&notificationTemplate.CreatedBy,
+		&notificationTemplate.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &notificationTemplate, nil
+}
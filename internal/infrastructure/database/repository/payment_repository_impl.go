@@ -7,48 +7,80 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/lib/pq"
+
 	"conciliacao-bancaria/internal/domain/model"
 	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+	pkgErrors "conciliacao-bancaria/pkg/errors"
 )
 
-// SQLPaymentRepository implementa a interface PaymentRepository usando SQL
-type SQLPaymentRepository struct {
-	db *sql.DB
-}
+// paymentColumns define as colunas lidas em todas as consultas de pagamentos
+const paymentColumns = "id, bank_account, amount, payment_date, reference_id, entry_type, bank_code, bar_code, payer_document, description, payment_method, review_status, review_reason, reconciliation_id, billet_id, status, created_at, updated_at"
 
-// NewPaymentRepository cria uma nova instância de SQLPaymentRepository
-func NewPaymentRepository(db *sql.DB) repository.PaymentRepository {
-	return &SQLPaymentRepository{db: db}
-}
+// selectPayments lê os pagamentos, completado pelos filtros e pela ordenação de cada consulta
+const selectPayments = `
+		SELECT ` + paymentColumns + `
+		FROM bank_reconciliation.payments`
 
-// Create persiste um novo pagamento no banco de dados
-func (r *SQLPaymentRepository) Create(ctx context.Context, payment *model.Payment) error {
-	query := `
-		INSERT INTO payments (
-			id, bank_account, amount, payment_date, reference_id, created_at, updated_at
+// insertPaymentQuery grava um pagamento; os argumentos vêm de paymentArgs
+const insertPaymentQuery = `
+		INSERT INTO bank_reconciliation.payments (
+			id, bank_account, amount, payment_date, reference_id, entry_type, bank_code, bar_code, payer_document, description, payment_method, created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7
-		)
-	`
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+		)`
 
-	now := time.Now()
-	_, err := r.db.ExecContext(
-		ctx,
-		query,
+// paymentArgs retorna os argumentos de insertPaymentQuery, com now como data de criação e de atualização
+func paymentArgs(payment *model.Payment, now time.Time) []interface{} {
+	return []interface{}{
 		payment.ID,
 		payment.BankAccount,
 		payment.Amount,
 		payment.PaymentDate,
 		payment.ReferenceID,
+		string(entryTypeOrDefault(payment.EntryType)),
+		payment.BankCode,
+		payment.BarCode,
+		payment.PayerDocument,
+		payment.Description,
+		string(payment.PaymentMethod),
 		now,
 		now,
-	)
+	}
+}
+
+// pendingPaymentsQuery lê os pagamentos junto das conciliações que os utilizaram
+var pendingPaymentsQuery = `
+		SELECT ` + database.QualifyColumns("p", paymentColumns) + `
+		FROM bank_reconciliation.payments p
+		LEFT JOIN bank_reconciliation.reconciliations r
+			ON p.id = r.transaction_id OR p.id = ANY(r.transaction_ids)`
 
+// SQLPaymentRepository implementa a interface PaymentRepository usando SQL
+type SQLPaymentRepository struct {
+	db database.DB
+}
+
+// NewPaymentRepository cria uma nova instância de SQLPaymentRepository
+func NewPaymentRepository(db database.DB) repository.PaymentRepository {
+	return &SQLPaymentRepository{db: db}
+}
+
+// Create persiste um novo pagamento no banco de dados e retorna o registro gravado
+func (r *SQLPaymentRepository) Create(ctx context.Context, payment *model.Payment) (*model.Payment, error) {
+	query := insertPaymentQuery + `
+		RETURNING ` + paymentColumns
+
+	created, err := scanPayment(r.db.QueryRowContext(ctx, query, paymentArgs(payment, time.Now())...))
 	if err != nil {
-		return fmt.Errorf("falha ao criar pagamento: %w", err)
+		if isUniqueViolation(err) {
+			return nil, pkgErrors.NewConflictError("pagamento", payment.ID, "pagamento com este ID já existe")
+		}
+		return nil, fmt.Errorf("falha ao criar pagamento: %w", err)
 	}
 
-	return nil
+	return created, nil
 }
 
 // CreateMany persiste múltiplos pagamentos no banco de dados
@@ -64,15 +96,7 @@ func (r *SQLPaymentRepository) CreateMany(ctx context.Context, payments []*model
 		}
 	}()
 
-	query := `
-		INSERT INTO payments (
-			id, bank_account, amount, payment_date, reference_id, created_at, updated_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7
-		)
-	`
-
-	stmt, err := tx.PrepareContext(ctx, query)
+	stmt, err := tx.PrepareContext(ctx, insertPaymentQuery)
 	if err != nil {
 		return fmt.Errorf("falha ao preparar declaração: %w", err)
 	}
@@ -80,17 +104,7 @@ func (r *SQLPaymentRepository) CreateMany(ctx context.Context, payments []*model
 
 	now := time.Now()
 	for _, payment := range payments {
-		_, err = stmt.ExecContext(
-			ctx,
-			payment.ID,
-			payment.BankAccount,
-			payment.Amount,
-			payment.PaymentDate,
-			payment.ReferenceID,
-			now,
-			now,
-		)
-
+		_, err = stmt.ExecContext(ctx, paymentArgs(payment, now)...)
 		if err != nil {
 			return fmt.Errorf("falha ao inserir pagamento %s: %w", payment.ID, err)
 		}
@@ -103,53 +117,76 @@ func (r *SQLPaymentRepository) CreateMany(ctx context.Context, payments []*model
 	return nil
 }
 
+// CreateFromMessage registra a mensagem de fila e grava o pagamento dela na mesma transação. O registro
+// vem primeiro: em reentregas simultâneas, a segunda transação aguarda a primeira na chave da mensagem e
+// não grava nada
+func (r *SQLPaymentRepository) CreateFromMessage(ctx context.Context, message *model.ProcessedMessage, payment *model.Payment) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("falha ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO bank_reconciliation.processed_messages (consumer, message_id, payment_id, processed_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (consumer, message_id) DO NOTHING
+	`, message.Consumer, message.MessageID, message.PaymentID, message.ProcessedAt)
+	if err != nil {
+		return false, fmt.Errorf("erro ao registrar mensagem processada: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, insertPaymentQuery, paymentArgs(payment, time.Now())...); err != nil {
+		if isUniqueViolation(err) {
+			return false, pkgErrors.NewConflictError("pagamento", payment.ID, "pagamento com este ID já existe")
+		}
+		return false, fmt.Errorf("falha ao criar pagamento: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("falha ao confirmar transação: %w", err)
+	}
+
+	return true, nil
+}
+
 // GetByID recupera um pagamento pelo seu ID
 func (r *SQLPaymentRepository) GetByID(ctx context.Context, id string) (*model.Payment, error) {
 	query := `
 		SELECT 
-			id, bank_account, amount, payment_date, reference_id, created_at, updated_at
+			` + paymentColumns + `
 		FROM 
-			payments 
+			bank_reconciliation.payments
 		WHERE 
 			id = $1
 	`
 
-	var payment model.Payment
-	var referenceID sql.NullString
-	var createdAt, updatedAt time.Time
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&payment.ID,
-		&payment.BankAccount,
-		&payment.Amount,
-		&payment.PaymentDate,
-		&referenceID,
-		&createdAt,
-		&updatedAt,
-	)
-
+	payment, err := scanPayment(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil // Não encontrado
+			return nil, pkgErrors.NewNotFoundError("pagamento", id)
 		}
 		return nil, fmt.Errorf("falha ao recuperar pagamento: %w", err)
 	}
 
-	if referenceID.Valid {
-		refID := referenceID.String
-		payment.ReferenceID = &refID
-	}
-
-	return &payment, nil
+	return payment, nil
 }
 
 // GetAll recupera todos os pagamentos
 func (r *SQLPaymentRepository) GetAll(ctx context.Context) ([]*model.Payment, error) {
 	query := `
 		SELECT 
-			id, bank_account, amount, payment_date, reference_id, created_at, updated_at
+			` + paymentColumns + `
 		FROM 
-			payments
+			bank_reconciliation.payments
 		ORDER BY
 			payment_date
 	`
@@ -158,48 +195,59 @@ func (r *SQLPaymentRepository) GetAll(ctx context.Context) ([]*model.Payment, er
 	if err != nil {
 		return nil, fmt.Errorf("falha ao consultar pagamentos: %w", err)
 	}
-	defer rows.Close()
 
-	var payments []*model.Payment
-	for rows.Next() {
-		var payment model.Payment
-		var referenceID sql.NullString
-		var createdAt, updatedAt time.Time
-
-		if err := rows.Scan(
-			&payment.ID,
-			&payment.BankAccount,
-			&payment.Amount,
-			&payment.PaymentDate,
-			&referenceID,
-			&createdAt,
-			&updatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("falha ao ler pagamento: %w", err)
-		}
+	return scanPayments(rows, "falha ao ler pagamento")
+}
 
-		if referenceID.Valid {
-			refID := referenceID.String
-			payment.ReferenceID = &refID
-		}
+// List recupera os pagamentos que atendem ao filtro, ordenados pela data do pagamento
+func (r *SQLPaymentRepository) List(ctx context.Context, filter model.PaymentFilter) ([]*model.Payment, error) {
+	q := database.NewQuery(selectPayments)
 
-		payments = append(payments, &payment)
+	if filter.BankAccount != "" {
+		q.Where("bank_account = ?", filter.BankAccount)
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("erro ao iterar sobre os resultados: %w", err)
+	if filter.ReferenceID != "" {
+		q.Where("reference_id = ?", filter.ReferenceID)
 	}
 
-	return payments, nil
+	if filter.EntryType != "" {
+		q.Where("entry_type = ?", string(filter.EntryType))
+	}
+
+	if filter.StartDate != nil {
+		q.Where("payment_date >= ?", *filter.StartDate)
+	}
+
+	if filter.EndDate != nil {
+		q.Where("payment_date < ?", filter.EndDate.AddDate(0, 0, 1))
+	}
+
+	if filter.MinAmount != nil {
+		q.Where("amount >= ?", *filter.MinAmount)
+	}
+
+	if filter.MaxAmount != nil {
+		q.Where("amount <= ?", *filter.MaxAmount)
+	}
+
+	query, args := q.OrderBy("payment_date, id").Page(filter.Limit, filter.Offset).Build()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar pagamentos: %w", err)
+	}
+
+	return scanPayments(rows, "falha ao ler pagamento")
 }
 
 // GetByBankAccount recupera pagamentos por conta bancária
 func (r *SQLPaymentRepository) GetByBankAccount(ctx context.Context, bankAccount string) ([]*model.Payment, error) {
 	query := `
 		SELECT 
-			id, bank_account, amount, payment_date, reference_id, created_at, updated_at
+			` + paymentColumns + `
 		FROM 
-			payments
+			bank_reconciliation.payments
 		WHERE
 			bank_account = $1
 		ORDER BY
@@ -210,48 +258,17 @@ func (r *SQLPaymentRepository) GetByBankAccount(ctx context.Context, bankAccount
 	if err != nil {
 		return nil, fmt.Errorf("falha ao consultar pagamentos por conta bancária: %w", err)
 	}
-	defer rows.Close()
-
-	var payments []*model.Payment
-	for rows.Next() {
-		var payment model.Payment
-		var referenceID sql.NullString
-		var createdAt, updatedAt time.Time
-
-		if err := rows.Scan(
-			&payment.ID,
-			&payment.BankAccount,
-			&payment.Amount,
-			&payment.PaymentDate,
-			&referenceID,
-			&createdAt,
-			&updatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("falha ao ler pagamento: %w", err)
-		}
-
-		if referenceID.Valid {
-			refID := referenceID.String
-			payment.ReferenceID = &refID
-		}
-
-		payments = append(payments, &payment)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("erro ao iterar sobre os resultados: %w", err)
-	}
 
-	return payments, nil
+	return scanPayments(rows, "falha ao ler pagamento")
 }
 
 // GetByReferenceID recupera pagamentos por ID de referência
 func (r *SQLPaymentRepository) GetByReferenceID(ctx context.Context, referenceID string) ([]*model.Payment, error) {
 	query := `
 		SELECT 
-			id, bank_account, amount, payment_date, reference_id, created_at, updated_at
+			` + paymentColumns + `
 		FROM 
-			payments
+			bank_reconciliation.payments
 		WHERE
 			reference_id = $1
 		ORDER BY
@@ -262,86 +279,102 @@ func (r *SQLPaymentRepository) GetByReferenceID(ctx context.Context, referenceID
 	if err != nil {
 		return nil, fmt.Errorf("falha ao consultar pagamentos por ID de referência: %w", err)
 	}
-	defer rows.Close()
 
-	var payments []*model.Payment
-	for rows.Next() {
-		var payment model.Payment
-		var refID sql.NullString
-		var createdAt, updatedAt time.Time
-
-		if err := rows.Scan(
-			&payment.ID,
-			&payment.BankAccount,
-			&payment.Amount,
-			&payment.PaymentDate,
-			&refID,
-			&createdAt,
-			&updatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("falha ao ler pagamento: %w", err)
-		}
+	return scanPayments(rows, "falha ao ler pagamento")
+}
 
-		if refID.Valid {
-			refIDStr := refID.String
-			payment.ReferenceID = &refIDStr
-		}
+// GetByIDs recupera os pagamentos com os IDs informados em uma única consulta
+func (r *SQLPaymentRepository) GetByIDs(ctx context.Context, ids []string) ([]*model.Payment, error) {
+	query := `
+		SELECT 
+			` + paymentColumns + `
+		FROM 
+			bank_reconciliation.payments
+		WHERE
+			id = ANY($1)
+		ORDER BY
+			id
+	`
 
-		payments = append(payments, &payment)
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("falha ao consultar pagamentos por IDs: %w", err)
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("erro ao iterar sobre os resultados: %w", err)
+	return scanPayments(rows, "falha ao ler pagamento")
+}
+
+// GetByEntryType recupera pagamentos por tipo de lançamento (crédito/débito)
+func (r *SQLPaymentRepository) GetByEntryType(ctx context.Context, entryType model.EntryType) ([]*model.Payment, error) {
+	query := `
+		SELECT 
+			` + paymentColumns + `
+		FROM 
+			bank_reconciliation.payments
+		WHERE
+			entry_type = $1
+		ORDER BY
+			payment_date
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, string(entryType))
+	if err != nil {
+		return nil, fmt.Errorf("falha ao consultar pagamentos por tipo de lançamento: %w", err)
 	}
 
-	return payments, nil
+	return scanPayments(rows, "falha ao ler pagamento")
 }
 
-// Update atualiza um pagamento existente
-func (r *SQLPaymentRepository) Update(ctx context.Context, payment *model.Payment) error {
+// Update atualiza um pagamento existente e retorna o registro gravado
+func (r *SQLPaymentRepository) Update(ctx context.Context, payment *model.Payment) (*model.Payment, error) {
 	query := `
-		UPDATE payments
+		UPDATE bank_reconciliation.payments
 		SET
 			bank_account = $1,
 			amount = $2,
 			payment_date = $3,
 			reference_id = $4,
-			updated_at = $5
+			entry_type = $5,
+			bank_code = $6,
+			bar_code = $7,
+			payer_document = $8,
+			description = $9,
+			payment_method = $10,
+			updated_at = $11
 		WHERE
-			id = $6
-	`
+			id = $12
+		RETURNING ` + paymentColumns
 
-	now := time.Now()
-	result, err := r.db.ExecContext(
+	updated, err := scanPayment(r.db.QueryRowContext(
 		ctx,
 		query,
 		payment.BankAccount,
 		payment.Amount,
 		payment.PaymentDate,
 		payment.ReferenceID,
-		now,
+		string(entryTypeOrDefault(payment.EntryType)),
+		payment.BankCode,
+		payment.BarCode,
+		payment.PayerDocument,
+		payment.Description,
+		string(payment.PaymentMethod),
+		time.Now(),
 		payment.ID,
-	)
+	))
 
 	if err != nil {
-		return fmt.Errorf("falha ao atualizar pagamento: %w", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("falha ao verificar linhas afetadas: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("nenhum pagamento atualizado com o ID: %s", payment.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NewNotFoundError("pagamento", payment.ID)
+		}
+		return nil, fmt.Errorf("falha ao atualizar pagamento: %w", err)
 	}
 
-	return nil
+	return updated, nil
 }
 
 // Delete remove um pagamento pelo ID
 func (r *SQLPaymentRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM payments WHERE id = $1`
+	query := `DELETE FROM bank_reconciliation.payments WHERE id = $1`
 
 	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
@@ -354,7 +387,7 @@ func (r *SQLPaymentRepository) Delete(ctx context.Context, id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("nenhum pagamento excluído com o ID: %s", id)
+		return pkgErrors.NewNotFoundError("pagamento", id)
 	}
 
 	return nil
@@ -368,9 +401,9 @@ func (r *SQLPaymentRepository) FindByBankAccountAndAmount(ctx context.Context, b
 
 	query := `
 		SELECT 
-			id, bank_account, amount, payment_date, reference_id, created_at, updated_at
+			` + paymentColumns + `
 		FROM 
-			payments
+			bank_reconciliation.payments
 		WHERE
 			bank_account = $1
 			AND amount BETWEEN $2 AND $3
@@ -382,37 +415,266 @@ func (r *SQLPaymentRepository) FindByBankAccountAndAmount(ctx context.Context, b
 	if err != nil {
 		return nil, fmt.Errorf("falha ao consultar pagamentos por conta e valor: %w", err)
 	}
+
+	return scanPayments(rows, "falha ao ler pagamento")
+}
+
+// FindNonReconciled encontra pagamentos que ainda não foram utilizados em conciliações
+func (r *SQLPaymentRepository) FindNonReconciled(ctx context.Context) ([]*model.Payment, error) {
+	query, args := database.NewQuery(pendingPaymentsQuery).
+		Where("r.id IS NULL").
+		OrderBy("p.payment_date").
+		Build()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao consultar pagamentos não conciliados: %w", err)
+	}
+
+	return scanPayments(rows, "falha ao ler pagamento não conciliado")
+}
+
+// FindNonReconciledByPeriod encontra pagamentos ainda não utilizados em conciliações feitos no período [start, end]
+func (r *SQLPaymentRepository) FindNonReconciledByPeriod(ctx context.Context, start, end time.Time) ([]*model.Payment, error) {
+	query, args := database.NewQuery(pendingPaymentsQuery).
+		Where("r.id IS NULL").
+		Where("p.payment_date BETWEEN ? AND ?", start, end).
+		OrderBy("p.payment_date").
+		Build()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao consultar pagamentos não conciliados do período: %w", err)
+	}
+
+	return scanPayments(rows, "falha ao ler pagamento não conciliado")
+}
+
+// FindNonReconciledByFilter encontra pagamentos ainda não utilizados em conciliações feitos no período e
+// das contas do filtro
+func (r *SQLPaymentRepository) FindNonReconciledByFilter(ctx context.Context, filter model.PendingFilter) ([]*model.Payment, error) {
+	query, args := pendingPaymentsByFilter(filter).OrderBy("p.payment_date").Build()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao consultar pagamentos não conciliados do filtro: %w", err)
+	}
+
+	return scanPayments(rows, "falha ao ler pagamento não conciliado")
+}
+
+// StreamNonReconciled percorre os pagamentos ainda não utilizados em conciliações do filtro com um
+// cursor, ordenados por conta bancária (em ordem binária, a mesma das strings em Go) e data de pagamento
+func (r *SQLPaymentRepository) StreamNonReconciled(ctx context.Context, filter model.PendingFilter, fn func(payment *model.Payment) error) error {
+	query, args := pendingPaymentsByFilter(filter).OrderBy(`p.bank_account COLLATE "C", p.payment_date, p.id`).Build()
+
+	err := streamCursor(ctx, r.db, "pending_payments", query, args, func(rows *sql.Rows) error {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return fmt.Errorf("falha ao ler pagamento não conciliado: %w", err)
+		}
+		return fn(payment)
+	})
+	if err != nil {
+		return fmt.Errorf("falha ao percorrer pagamentos não conciliados: %w", err)
+	}
+
+	return nil
+}
+
+// pendingPaymentsByFilter monta a consulta dos pagamentos não conciliados do período e das contas do filtro
+func pendingPaymentsByFilter(filter model.PendingFilter) *database.Query {
+	q := database.NewQuery(pendingPaymentsQuery).Where("r.id IS NULL")
+
+	if !filter.StartDate.IsZero() {
+		q.Where("p.payment_date >= ?", filter.StartDate)
+	}
+	if !filter.EndDate.IsZero() {
+		q.Where("p.payment_date <= ?", filter.EndDate)
+	}
+	if len(filter.BankAccounts) > 0 {
+		q.Where("p.bank_account = ANY(?)", pq.Array(filter.BankAccounts))
+	}
+	if filter.MinAmount > 0 {
+		q.Where("p.amount >= ?", filter.MinAmount)
+	}
+	if filter.MaxAmount > 0 {
+		q.Where("p.amount <= ?", filter.MaxAmount)
+	}
+
+	return q
+}
+
+// GetReconciledAmounts recupera os valores dos pagamentos conciliados mais recentes de uma conta
+func (r *SQLPaymentRepository) GetReconciledAmounts(ctx context.Context, bankAccount string, limit int) ([]float64, error) {
+	query := `
+		SELECT 
+			p.amount
+		FROM 
+			bank_reconciliation.payments p
+		JOIN
+			bank_reconciliation.reconciliations r ON p.id = r.transaction_id
+		WHERE
+			p.bank_account = $1
+			AND r.conciliation_status IN ($2, $3, $4)
+		ORDER BY
+			p.payment_date DESC
+		LIMIT $5
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, bankAccount,
+		string(model.StatusSuccessful), string(model.StatusDifferentValue), string(model.StatusPaidWithInterest), limit)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao consultar histórico de valores da conta: %w", err)
+	}
 	defer rows.Close()
 
-	var payments []*model.Payment
+	var amounts []float64
 	for rows.Next() {
-		var payment model.Payment
-		var referenceID sql.NullString
-		var createdAt, updatedAt time.Time
-
-		if err := rows.Scan(
-			&payment.ID,
-			&payment.BankAccount,
-			&payment.Amount,
-			&payment.PaymentDate,
-			&referenceID,
-			&createdAt,
-			&updatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("falha ao ler pagamento: %w", err)
+		var amount float64
+		if err := rows.Scan(&amount); err != nil {
+			return nil, fmt.Errorf("falha ao ler valor do histórico: %w", err)
 		}
+		amounts = append(amounts, amount)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar sobre os resultados: %w", err)
+	}
 
-		if referenceID.Valid {
-			refID := referenceID.String
-			payment.ReferenceID = &refID
+	return amounts, nil
+}
+
+// GetByReviewStatus recupera pagamentos pela situação de revisão manual
+func (r *SQLPaymentRepository) GetByReviewStatus(ctx context.Context, status model.PaymentReviewStatus) ([]*model.Payment, error) {
+	query := `
+		SELECT 
+			` + paymentColumns + `
+		FROM 
+			bank_reconciliation.payments
+		WHERE
+			review_status = $1
+		ORDER BY
+			payment_date
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, string(status))
+	if err != nil {
+		return nil, fmt.Errorf("falha ao consultar pagamentos por situação de revisão: %w", err)
+	}
+
+	return scanPayments(rows, "falha ao ler pagamento")
+}
+
+// UpdateReviewStatus atualiza a situação de revisão manual de um pagamento
+func (r *SQLPaymentRepository) UpdateReviewStatus(ctx context.Context, id string, status model.PaymentReviewStatus, reason *string) error {
+	query := `
+		UPDATE bank_reconciliation.payments
+		SET
+			review_status = $1,
+			review_reason = $2,
+			updated_at = $3
+		WHERE
+			id = $4
+	`
+
+	result, err := r.db.ExecContext(ctx, query, string(status), reason, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar revisão do pagamento: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("falha ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("nenhum pagamento atualizado com o ID: %s", id)
+	}
+
+	return nil
+}
+
+// scanPayments lê todas as linhas de um resultado de consulta de pagamentos e fecha o cursor
+func scanPayments(rows *sql.Rows, readErrorMessage string) ([]*model.Payment, error) {
+	defer rows.Close()
+
+	var payments []*model.Payment
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", readErrorMessage, err)
 		}
 
-		payments = append(payments, &payment)
+		payments = append(payments, payment)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("erro ao iterar sobre os resultados: %w", err)
 	}
 
 	return payments, nil
 }
+
+// scanPayment lê um pagamento a partir de uma linha de resultado
+func scanPayment(scanner rowScanner) (*model.Payment, error) {
+	var payment model.Payment
+	var referenceID, reviewReason sql.NullString
+	var reconciliationID, billetID sql.NullString
+	var entryType, paymentMethod, reviewStatus, status string
+
+	if err := scanner.Scan(
+		&payment.ID,
+		&payment.BankAccount,
+		&payment.Amount,
+		&payment.PaymentDate,
+		&referenceID,
+		&entryType,
+		&payment.BankCode,
+		&payment.BarCode,
+		&payment.PayerDocument,
+		&payment.Description,
+		&paymentMethod,
+		&reviewStatus,
+		&reviewReason,
+		&reconciliationID,
+		&billetID,
+		&status,
+		&payment.CreatedAt,
+		&payment.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if referenceID.Valid {
+		refID := referenceID.String
+		payment.ReferenceID = &refID
+	}
+
+	payment.EntryType = model.EntryType(entryType)
+	payment.PaymentMethod = model.PaymentMethod(paymentMethod)
+	payment.ReviewStatus = model.PaymentReviewStatus(reviewStatus)
+
+	if reviewReason.Valid {
+		reason := reviewReason.String
+		payment.ReviewReason = &reason
+	}
+
+	payment.ReconciliationID = reconciliationID.String
+	payment.Status = model.PaymentStatus(status)
+
+	if billetID.Valid {
+		id := billetID.String
+		payment.BilletID = &id
+	}
+
+	return &payment, nil
+}
+
+// entryTypeOrDefault considera como crédito os pagamentos sem tipo de lançamento informado
+func entryTypeOrDefault(entryType model.EntryType) model.EntryType {
+	if entryType == "" {
+		return model.EntryTypeCredit
+	}
+	return entryType
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// Garantir que PendingSnapshotRepositoryImpl implementa a interface PendingSnapshotRepository
+var _ domainRepo.PendingSnapshotRepository = (*PendingSnapshotRepositoryImpl)(nil)
+
+// PendingSnapshotRepositoryImpl implementa a interface de repositório para a série diária de pendências
+type PendingSnapshotRepositoryImpl struct {
+	db database.DB
+}
+
+// NewPendingSnapshotRepository cria uma nova instância do repositório da série de pendências
+func NewPendingSnapshotRepository(db database.DB) domainRepo.PendingSnapshotRepository {
+	return &PendingSnapshotRepositoryImpl{
+		db: db,
+	}
+}
+
+// pendingSnapshotColumns lista as colunas lidas por scanPendingSnapshot
+const pendingSnapshotColumns = `
+	id, snapshot_date, pending_count, pending_amount, exited_count, entered_count,
+	reconciled_in_run, pending_billet_ids, created_at`
+
+// Save grava o retrato do dia; a restrição única do dia faz a reexecução substituir o retrato anterior
+func (r *PendingSnapshotRepositoryImpl) Save(ctx context.Context, snapshot *model.PendingSnapshot) error {
+	billetIDs, err := json.Marshal(snapshot.PendingBilletIDs)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar boletos pendentes: %w", err)
+	}
+
+	query := `
+		INSERT INTO bank_reconciliation.pending_snapshots (
+			id, snapshot_date, pending_count, pending_amount, exited_count, entered_count,
+			reconciled_in_run, pending_billet_ids, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (snapshot_date) DO UPDATE SET
+			pending_count = EXCLUDED.pending_count,
+			pending_amount = EXCLUDED.pending_amount,
+			exited_count = EXCLUDED.exited_count,
+			entered_count = EXCLUDED.entered_count,
+			reconciled_in_run = EXCLUDED.reconciled_in_run,
+			pending_billet_ids = EXCLUDED.pending_billet_ids,
+			created_at = EXCLUDED.created_at
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		snapshot.ID,
+		snapshot.Date,
+		snapshot.PendingCount,
+		snapshot.PendingAmount,
+		snapshot.ExitedCount,
+		snapshot.EnteredCount,
+		snapshot.ReconciledInRun,
+		billetIDs,
+		snapshot.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao gravar retrato das pendências: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestBefore recupera o retrato mais recente anterior ao dia informado
+func (r *PendingSnapshotRepositoryImpl) GetLatestBefore(ctx context.Context, date time.Time) (*model.PendingSnapshot, error) {
+	query := `SELECT ` + pendingSnapshotColumns + `
+		FROM bank_reconciliation.pending_snapshots
+		WHERE snapshot_date < $1
+		ORDER BY snapshot_date DESC
+		LIMIT 1
+	`
+
+	snapshot, err := scanPendingSnapshot(r.db.QueryRowContext(ctx, query, model.ClosingDay(date)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar retrato das pendências: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// GetSeries recupera os retratos do período, do mais antigo para o mais recente
+func (r *PendingSnapshotRepositoryImpl) GetSeries(ctx context.Context, startDate, endDate *time.Time) ([]*model.PendingSnapshot, error) {
+	q := database.NewQuery(`SELECT ` + pendingSnapshotColumns + `
+		FROM bank_reconciliation.pending_snapshots`)
+
+	if startDate != nil {
+		q.Where("snapshot_date >= ?", model.ClosingDay(*startDate))
+	}
+	if endDate != nil {
+		q.Where("snapshot_date <= ?", model.ClosingDay(*endDate))
+	}
+
+	query, args := q.OrderBy("snapshot_date").Build()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar série de pendências: %w", err)
+	}
+	defer rows.Close()
+
+	series := []*model.PendingSnapshot{}
+	for rows.Next() {
+		snapshot, err := scanPendingSnapshot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler retrato das pendências: %w", err)
+		}
+		series = append(series, snapshot)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return series, nil
+}
+
+// scanPendingSnapshot lê um retrato de uma linha com as colunas de pendingSnapshotColumns
+func scanPendingSnapshot(scanner rowScanner) (*model.PendingSnapshot, error) {
+	var snapshot model.PendingSnapshot
+	var billetIDs []byte
+
+	err := scanner.Scan(
+		&snapshot.ID,
+		&snapshot.Date,
+		&snapshot.PendingCount,
+		&snapshot.PendingAmount,
+		&snapshot.ExitedCount,
+		&snapshot.EnteredCount,
+		&snapshot.ReconciledInRun,
+		&billetIDs,
+		&snapshot.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(billetIDs, &snapshot.PendingBilletIDs); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar boletos pendentes do retrato %s: %w", snapshot.ID, err)
+	}
+
+	return &snapshot, nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// Garantir que ProcessedMessageRepositoryImpl implementa a interface ProcessedMessageRepository
+var _ domainRepo.ProcessedMessageRepository = (*ProcessedMessageRepositoryImpl)(nil)
+
+// ProcessedMessageRepositoryImpl implementa a interface de repositório para as mensagens de fila processadas
+type ProcessedMessageRepositoryImpl struct {
+	db database.DB
+}
+
+// NewProcessedMessageRepository cria uma nova instância do repositório de mensagens processadas
+func NewProcessedMessageRepository(db database.DB) domainRepo.ProcessedMessageRepository {
+	return &ProcessedMessageRepositoryImpl{
+		db: db,
+	}
+}
+
+// GetByID recupera o registro da mensagem processada pelo consumidor, ou nil quando não existe
+func (r *ProcessedMessageRepositoryImpl) GetByID(ctx context.Context, consumer, messageID string) (*model.ProcessedMessage, error) {
+	query := `
+		SELECT consumer, message_id, payment_id, processed_at
+		FROM bank_reconciliation.processed_messages
+		WHERE consumer = $1 AND message_id = $2
+	`
+
+	var message model.ProcessedMessage
+	var paymentID sql.NullString
+	err := r.db.QueryRowContext(ctx, query, consumer, messageID).Scan(
+		&message.Consumer,
+		&message.MessageID,
+		&paymentID,
+		&message.ProcessedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar mensagem processada: %w", err)
+	}
+
+	message.PaymentID = paymentID.String
+	return &message, nil
+}
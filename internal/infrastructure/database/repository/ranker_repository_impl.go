@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// Garantir que RankerRepositoryImpl implementa a interface RankerRepository
+var _ domainRepo.RankerRepository = (*RankerRepositoryImpl)(nil)
+
+// RankerRepositoryImpl implementa a interface de repositório para os pesos do ranker
+type RankerRepositoryImpl struct {
+	db database.DB
+}
+
+// NewRankerRepository cria uma nova instância do repositório de pesos do ranker
+func NewRankerRepository(db database.DB) domainRepo.RankerRepository {
+	return &RankerRepositoryImpl{
+		db: db,
+	}
+}
+
+// GetByTenant recupera o modelo treinado de um tenant, ou nil quando não existe
+func (r *RankerRepositoryImpl) GetByTenant(ctx context.Context, tenantID string) (*model.RankerModel, error) {
+	query := `
+		SELECT tenant_id, enabled, bias, amount_diff_weight, days_diff_weight,
+			partial_reference_weight, samples, trained_at
+		FROM bank_reconciliation.ranker_models
+		WHERE tenant_id = $1
+	`
+
+	var rankerModel model.RankerModel
+	err := r.db.QueryRowContext(ctx, query, tenantID).Scan(
+		&rankerModel.TenantID,
+		&rankerModel.Enabled,
+		&rankerModel.Bias,
+		&rankerModel.AmountDiffWeight,
+		&rankerModel.DaysDiffWeight,
+		&rankerModel.PartialReferenceWeight,
+		&rankerModel.Samples,
+		&rankerModel.TrainedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar modelo do ranker: %w", err)
+	}
+
+	return &rankerModel, nil
+}
+
+// Save cria ou substitui o modelo de um tenant
+func (r *RankerRepositoryImpl) Save(ctx context.Context, rankerModel *model.RankerModel) error {
+	query := `
+		INSERT INTO bank_reconciliation.ranker_models (
+			tenant_id, enabled, bias, amount_diff_weight, days_diff_weight,
+			partial_reference_weight, samples, trained_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP)
+		ON CONFLICT (tenant_id) DO UPDATE
+		SET enabled = EXCLUDED.enabled,
+			bias = EXCLUDED.bias,
+			amount_diff_weight = EXCLUDED.amount_diff_weight,
+			days_diff_weight = EXCLUDED.days_diff_weight,
+			partial_reference_weight = EXCLUDED.partial_reference_weight,
+			samples = EXCLUDED.samples,
+			trained_at = EXCLUDED.trained_at,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		rankerModel.TenantID,
+		rankerModel.Enabled,
+		rankerModel.Bias,
+		rankerModel.AmountDiffWeight,
+		rankerModel.DaysDiffWeight,
+		rankerModel.PartialReferenceWeight,
+		rankerModel.Samples,
+		rankerModel.TrainedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar modelo do ranker: %w", err)
+	}
+
+	return nil
+}
@@ -7,8 +7,12 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/lib/pq"
+
 	"conciliacao-bancaria/internal/domain/model"
 	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+	pkgErrors "conciliacao-bancaria/pkg/errors"
 )
 
 // Garantir que ReconciliationRepositoryImpl implementa a interface ReconciliationRepository
@@ -16,46 +20,78 @@ var _ domainRepo.ReconciliationRepository = (*ReconciliationRepositoryImpl)(nil)
 
 // ReconciliationRepositoryImpl implementa a interface de repositório para conciliações
 type ReconciliationRepositoryImpl struct {
-	db *sql.DB
+	db database.DB
 }
 
 // NewReconciliationRepository cria uma nova instância do repositório de conciliação
-func NewReconciliationRepository(db *sql.DB) domainRepo.ReconciliationRepository {
+func NewReconciliationRepository(db database.DB) domainRepo.ReconciliationRepository {
 	return &ReconciliationRepositoryImpl{
 		db: db,
 	}
 }
 
-// Create persiste uma nova conciliação no banco de dados
-func (r *ReconciliationRepositoryImpl) Create(ctx context.Context, reconciliation *model.Reconciliation) error {
-	query := `
-		INSERT INTO reconciliation (
-			id, billet_id, transaction_id, reconciliation_date, 
-			conciliation_status, conciliation_strategy, amount_diff, reference_id
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+// reconciliationColumns lista as colunas lidas nas consultas de conciliações, na ordem esperada por
+// scanReconciliation
+const reconciliationColumns = `id, billet_id, transaction_id, bank_account, reconciliation_date,
+			conciliation_status, conciliation_strategy, amount_diff, reference_id,
+			time_to_reconcile_seconds, run_id, transaction_ids, group_id, confidence, engine_version`
+
+// selectReconciliations lê as conciliações, completado pelos filtros e pela ordenação de cada consulta
+const selectReconciliations = `
+		SELECT ` + reconciliationColumns + `
+		FROM bank_reconciliation.reconciliations`
+
+// insertReconciliationQuery grava uma conciliação com as colunas de reconciliationColumns
+var insertReconciliationQuery = `
+		INSERT INTO bank_reconciliation.reconciliations (` + reconciliationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`
 
-	// Usar context com timeout para evitar operações longas em caso de problemas com o banco
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	_, err := r.db.ExecContext(
-		ctxWithTimeout,
-		query,
+// reconciliationArgs retorna os argumentos de insertReconciliationQuery
+func reconciliationArgs(reconciliation *model.Reconciliation) []interface{} {
+	return []interface{}{
 		reconciliation.ID,
 		reconciliation.BilletID,
 		reconciliation.TransactionID,
+		reconciliation.BankAccount,
 		reconciliation.ReconciliationDate,
 		string(reconciliation.ConciliationStatus),
 		string(reconciliation.ConciliationStrategy),
 		reconciliation.AmountDiff,
 		reconciliation.ReferenceID,
-	)
+		reconciliation.TimeToReconcileSeconds,
+		reconciliation.RunID,
+		pq.Array(reconciliation.TransactionIDs),
+		reconciliation.GroupID,
+		reconciliation.Confidence,
+		sql.NullString{String: reconciliation.EngineVersion, Valid: reconciliation.EngineVersion != ""},
+	}
+}
+
+// Create persiste uma nova conciliação no banco de dados
+func (r *ReconciliationRepositoryImpl) Create(ctx context.Context, reconciliation *model.Reconciliation) error {
+	// Usar context com timeout para evitar operações longas em caso de problemas com o banco
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
 
+	tx, err := r.db.BeginTx(ctxWithTimeout, nil)
 	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctxWithTimeout, insertReconciliationQuery, reconciliationArgs(reconciliation)...); err != nil {
 		return fmt.Errorf("erro ao criar conciliação: %w", err)
 	}
 
+	if err := linkReconciliation(ctxWithTimeout, tx, reconciliation); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
 	return nil
 }
 
@@ -70,47 +106,26 @@ func (r *ReconciliationRepositoryImpl) CreateMany(ctx context.Context, reconcili
 	if err != nil {
 		return fmt.Errorf("erro ao iniciar transação: %w", err)
 	}
+	defer tx.Rollback()
 
-	// Defer para garantir que a transação será revertida em caso de erro
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-		}
-	}()
-
-	query := `
-		INSERT INTO reconciliation (
-			id, billet_id, transaction_id, reconciliation_date, 
-			conciliation_status, conciliation_strategy, amount_diff, reference_id
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`
-
-	stmt, err := tx.PrepareContext(ctx, query)
+	stmt, err := tx.PrepareContext(ctx, insertReconciliationQuery)
 	if err != nil {
 		return fmt.Errorf("erro ao preparar statement: %w", err)
 	}
 	defer stmt.Close()
 
 	for _, reconciliation := range reconciliations {
-		_, err = stmt.ExecContext(
-			ctx,
-			reconciliation.ID,
-			reconciliation.BilletID,
-			reconciliation.TransactionID,
-			reconciliation.ReconciliationDate,
-			string(reconciliation.ConciliationStatus),
-			string(reconciliation.ConciliationStrategy),
-			reconciliation.AmountDiff,
-			reconciliation.ReferenceID,
-		)
-
-		if err != nil {
+		if _, err := stmt.ExecContext(ctx, reconciliationArgs(reconciliation)...); err != nil {
 			return fmt.Errorf("erro ao inserir conciliação %s: %w", reconciliation.ID, err)
 		}
+
+		if err := linkReconciliation(ctx, tx, reconciliation); err != nil {
+			return err
+		}
 	}
 
 	// Commit da transação
-	if err = tx.Commit(); err != nil {
+	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("erro ao confirmar transação: %w", err)
 	}
 
@@ -119,223 +134,800 @@ func (r *ReconciliationRepositoryImpl) CreateMany(ctx context.Context, reconcili
 
 // GetByID recupera uma conciliação pelo seu ID
 func (r *ReconciliationRepositoryImpl) GetByID(ctx context.Context, id string) (*model.Reconciliation, error) {
-	query := `
-		SELECT 
-			id, billet_id, transaction_id, reconciliation_date, 
-			conciliation_status, conciliation_strategy, amount_diff, reference_id
-		FROM reconciliation
-		WHERE id = ?
+	query := selectReconciliations + `
+		WHERE id = $1
 	`
 
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	row := r.db.QueryRowContext(ctxWithTimeout, query, id)
-
-	reconciliation := &model.Reconciliation{}
-	var conciliationStatus, conciliationStrategy string
-	var referenceID sql.NullString
-
-	err := row.Scan(
-		&reconciliation.ID,
-		&reconciliation.BilletID,
-		&reconciliation.TransactionID,
-		&reconciliation.ReconciliationDate,
-		&conciliationStatus,
-		&conciliationStrategy,
-		&reconciliation.AmountDiff,
-		&referenceID,
-	)
-
+	reconciliation, err := scanReconciliation(r.db.QueryRowContext(ctxWithTimeout, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("conciliação não encontrada: %w", err)
+			return nil, pkgErrors.NewNotFoundError("conciliação", id)
 		}
 		return nil, fmt.Errorf("erro ao buscar conciliação: %w", err)
 	}
 
-	// Converter os valores de string para os tipos de enum
-	reconciliation.ConciliationStatus = model.ConciliationStatus(conciliationStatus)
-	reconciliation.ConciliationStrategy = model.ConciliationStrategy(conciliationStrategy)
-
-	// Tratar campo opcional
-	if referenceID.Valid {
-		reconciliation.ReferenceID = &referenceID.String
-	}
-
 	return reconciliation, nil
 }
 
 // GetAll recupera todas as conciliações
 func (r *ReconciliationRepositoryImpl) GetAll(ctx context.Context) ([]*model.Reconciliation, error) {
+	query, args := database.NewQuery(selectReconciliations).
+		OrderBy("reconciliation_date DESC").
+		Build()
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return r.queryReconciliations(ctxWithTimeout, "erro ao buscar conciliações", query, args...)
+}
+
+// GetByBilletID recupera conciliações por ID do boleto
+func (r *ReconciliationRepositoryImpl) GetByBilletID(ctx context.Context, billetID string) ([]*model.Reconciliation, error) {
+	query, args := database.NewQuery(selectReconciliations).
+		Where("billet_id = ?", billetID).
+		OrderBy("reconciliation_date DESC").
+		Build()
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return r.queryReconciliations(ctxWithTimeout, "erro ao buscar conciliações por boleto", query, args...)
+}
+
+// GetByTransactionID recupera conciliações por ID da transação
+func (r *ReconciliationRepositoryImpl) GetByTransactionID(ctx context.Context, transactionID string) ([]*model.Reconciliation, error) {
+	query, args := database.NewQuery(selectReconciliations).
+		Where("(transaction_id = ? OR ? = ANY(transaction_ids))", transactionID, transactionID).
+		OrderBy("reconciliation_date DESC").
+		Build()
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return r.queryReconciliations(ctxWithTimeout, "erro ao buscar conciliações por transação", query, args...)
+}
+
+// Update atualiza uma conciliação existente
+func (r *ReconciliationRepositoryImpl) Update(ctx context.Context, reconciliation *model.Reconciliation) error {
 	query := `
-		SELECT 
-			id, billet_id, transaction_id, reconciliation_date, 
-			conciliation_status, conciliation_strategy, amount_diff, reference_id
-		FROM reconciliation
-		ORDER BY reconciliation_date DESC
+		UPDATE bank_reconciliation.reconciliations
+		SET
+			billet_id = $1,
+			transaction_id = $2,
+			reconciliation_date = $3,
+			conciliation_status = $4,
+			conciliation_strategy = $5,
+			amount_diff = $6,
+			reference_id = $7,
+			transaction_ids = $8,
+			group_id = $9
+		WHERE id = $10
 	`
 
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, 10*time.Second)
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	rows, err := r.db.QueryContext(ctxWithTimeout, query)
+	tx, err := r.db.BeginTx(ctxWithTimeout, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(
+		ctxWithTimeout,
+		query,
+		reconciliation.BilletID,
+		reconciliation.TransactionID,
+		reconciliation.ReconciliationDate,
+		string(reconciliation.ConciliationStatus),
+		string(reconciliation.ConciliationStrategy),
+		reconciliation.AmountDiff,
+		reconciliation.ReferenceID,
+		pq.Array(reconciliation.TransactionIDs),
+		reconciliation.GroupID,
+		reconciliation.ID,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar conciliações: %w", err)
+		return fmt.Errorf("erro ao atualizar conciliação: %w", err)
 	}
-	defer rows.Close()
 
-	reconciliations := []*model.Reconciliation{}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
 
-	for rows.Next() {
-		reconciliation := &model.Reconciliation{}
-		var conciliationStatus, conciliationStrategy string
-		var referenceID sql.NullString
+	if rowsAffected == 0 {
+		return fmt.Errorf("nenhuma conciliação encontrada com o ID: %s", reconciliation.ID)
+	}
 
-		err := rows.Scan(
-			&reconciliation.ID,
-			&reconciliation.BilletID,
-			&reconciliation.TransactionID,
-			&reconciliation.ReconciliationDate,
-			&conciliationStatus,
-			&conciliationStrategy,
-			&reconciliation.AmountDiff,
-			&referenceID,
-		)
+	// O boleto ou o pagamento da conciliação podem ter mudado: o vínculo é refeito
+	if err := unlinkReconciliation(ctxWithTimeout, tx, reconciliation.ID); err != nil {
+		return err
+	}
+
+	if err := linkReconciliation(ctxWithTimeout, tx, reconciliation); err != nil {
+		return err
+	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
+
+// Delete remove uma conciliação pelo ID
+func (r *ReconciliationRepositoryImpl) Delete(ctx context.Context, id string) error {
+	query := "DELETE FROM bank_reconciliation.reconciliations WHERE id = $1"
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctxWithTimeout, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctxWithTimeout, query, id)
+	if err != nil {
+		return fmt.Errorf("erro ao excluir conciliação: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("nenhuma conciliação encontrada com o ID: %s", id)
+	}
+
+	if err := unlinkReconciliation(ctxWithTimeout, tx, id); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
+
+// Undo remove as conciliações desfeitas, devolve os boletos e pagamentos à situação inicial e grava os
+// registros de auditoria, na mesma transação
+func (r *ReconciliationRepositoryImpl) Undo(ctx context.Context, undos []*model.ReconciliationUndo) error {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctxWithTimeout, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, undo := range undos {
+		if err := removeReconciliation(ctxWithTimeout, tx, undo.ReconciliationID, undo.ConciliationStatus); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctxWithTimeout, `
+			INSERT INTO bank_reconciliation.reconciliation_undos (
+				id, reconciliation_id, billet_id, transaction_id, transaction_ids, bank_account,
+				conciliation_status, conciliation_strategy, amount_diff, group_id, reason, undone_by, undone_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		`,
+			undo.ID,
+			undo.ReconciliationID,
+			undo.BilletID,
+			undo.TransactionID,
+			pq.Array(undo.TransactionIDs),
+			undo.BankAccount,
+			string(undo.ConciliationStatus),
+			string(undo.ConciliationStrategy),
+			undo.AmountDiff,
+			undo.GroupID,
+			undo.Reason,
+			undo.UndoneBy,
+			undo.UndoneAt,
+		)
 		if err != nil {
-			return nil, fmt.Errorf("erro ao ler conciliação: %w", err)
+			return fmt.Errorf("erro ao registrar conciliação desfeita: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
+
+// Approve leva as conciliações aprovadas ao novo status, vincula os boletos e pagamentos e grava as
+// aprovações e as mudanças de status, na mesma transação
+func (r *ReconciliationRepositoryImpl) Approve(ctx context.Context, approvals []*model.ReconciliationApproval) error {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctxWithTimeout, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, approval := range approvals {
+		if err := applyStatusChange(ctxWithTimeout, tx, approval.StatusChange()); err != nil {
+			return err
 		}
+		if err := insertApproval(ctxWithTimeout, tx, approval); err != nil {
+			return err
+		}
+	}
 
-		// Converter os valores de string para os tipos de enum
-		reconciliation.ConciliationStatus = model.ConciliationStatus(conciliationStatus)
-		reconciliation.ConciliationStrategy = model.ConciliationStrategy(conciliationStrategy)
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
 
-		// Tratar campo opcional
-		if referenceID.Valid {
-			reconciliation.ReferenceID = &referenceID.String
+	return nil
+}
+
+// Reject remove as conciliações rejeitadas, devolve os boletos e pagamentos à situação inicial e grava
+// as rejeições, na mesma transação
+func (r *ReconciliationRepositoryImpl) Reject(ctx context.Context, rejections []*model.ReconciliationApproval) error {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctxWithTimeout, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, rejection := range rejections {
+		if err := removeReconciliation(ctxWithTimeout, tx, rejection.ReconciliationID, rejection.PreviousStatus); err != nil {
+			return err
+		}
+		if err := insertApproval(ctxWithTimeout, tx, rejection); err != nil {
+			return err
 		}
+	}
 
-		reconciliations = append(reconciliations, reconciliation)
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
+
+// removeReconciliation exclui a conciliação com o status informado e desfaz o vínculo dos boletos e
+// pagamentos com ela. O status faz parte do filtro para não remover uma conciliação reavaliada em paralelo
+func removeReconciliation(ctx context.Context, tx database.Tx, reconciliationID string, status model.ConciliationStatus) error {
+	result, err := tx.ExecContext(ctx, `
+		DELETE FROM bank_reconciliation.reconciliations
+		WHERE id = $1 AND conciliation_status = $2
+	`, reconciliationID, string(status))
+	if err != nil {
+		return fmt.Errorf("erro ao excluir conciliação: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("conciliação %s não encontrada com o status %s", reconciliationID, status)
+	}
+
+	return unlinkReconciliation(ctx, tx, reconciliationID)
+}
+
+// insertApproval grava a decisão sobre o pareamento na transação que a aplica
+func insertApproval(ctx context.Context, tx database.Tx, approval *model.ReconciliationApproval) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO bank_reconciliation.reconciliation_approvals (`+reconciliationApprovalColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`,
+		approval.ID,
+		approval.ReconciliationID,
+		approval.BilletID,
+		approval.TransactionID,
+		approval.BankAccount,
+		string(approval.Decision),
+		string(approval.PreviousStatus),
+		string(approval.NewStatus),
+		approval.AmountDiff,
+		approval.Confidence,
+		approval.Approver,
+		approval.Reason,
+		approval.DecidedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar decisão sobre a conciliação %s: %w", approval.ReconciliationID, err)
+	}
+
+	return nil
+}
+
+// reconciliationApprovalColumns lista as colunas gravadas por insertApproval e lidas por GetApprovals
+const reconciliationApprovalColumns = `id, reconciliation_id, billet_id, transaction_id, bank_account, decision,
+	previous_status, new_status, amount_diff, confidence, approver, reason, decided_at`
+
+// GetApprovals recupera as aprovações e rejeições dos pareamentos de um boleto
+func (r *ReconciliationRepositoryImpl) GetApprovals(ctx context.Context, billetID string) ([]*model.ReconciliationApproval, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+reconciliationApprovalColumns+`
+		FROM bank_reconciliation.reconciliation_approvals
+		WHERE billet_id = $1
+		ORDER BY decided_at
+	`, billetID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar aprovações: %w", err)
+	}
+	defer rows.Close()
+
+	approvals := []*model.ReconciliationApproval{}
+	for rows.Next() {
+		approval := &model.ReconciliationApproval{}
+		var transactionID sql.NullString
+		var confidence sql.NullFloat64
+		var decision, previousStatus, newStatus string
+
+		err := rows.Scan(
+			&approval.ID,
+			&approval.ReconciliationID,
+			&approval.BilletID,
+			&transactionID,
+			&approval.BankAccount,
+			&decision,
+			&previousStatus,
+			&newStatus,
+			&approval.AmountDiff,
+			&confidence,
+			&approval.Approver,
+			&approval.Reason,
+			&approval.DecidedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler aprovação: %w", err)
+		}
+
+		if transactionID.Valid {
+			approval.TransactionID = &transactionID.String
+		}
+		if confidence.Valid {
+			approval.Confidence = &confidence.Float64
+		}
+		approval.Decision = model.ApprovalDecision(decision)
+		approval.PreviousStatus = model.ConciliationStatus(previousStatus)
+		approval.NewStatus = model.ConciliationStatus(newStatus)
+		approvals = append(approvals, approval)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+		return nil, fmt.Errorf("erro ao processar resultados das aprovações: %w", err)
 	}
 
-	return reconciliations, nil
+	return approvals, nil
 }
 
-// GetByBilletID recupera conciliações por ID do boleto
-func (r *ReconciliationRepositoryImpl) GetByBilletID(ctx context.Context, billetID string) ([]*model.Reconciliation, error) {
-	query := `
-		SELECT 
-			id, billet_id, transaction_id, reconciliation_date, 
-			conciliation_status, conciliation_strategy, amount_diff, reference_id
-		FROM reconciliation
-		WHERE billet_id = ?
-		ORDER BY reconciliation_date DESC
-	`
+// linkReconciliation grava no boleto e no pagamento pareados o vínculo com a conciliação, na transação
+// que a persiste. Os registros nao_conciliado são só histórico e não alteram o vínculo
+func linkReconciliation(ctx context.Context, tx database.Tx, reconciliation *model.Reconciliation) error {
+	if !reconciliation.ConciliationStatus.IsMatched() {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		UPDATE bank_reconciliation.billets
+		SET reconciliation_id = $1, transaction_id = $2, status = $3
+		WHERE id = $4
+	`, reconciliation.ID, reconciliation.TransactionID, string(model.BilletStatusReconciled), reconciliation.BilletID)
+	if err != nil {
+		return fmt.Errorf("erro ao vincular boleto %s à conciliação: %w", reconciliation.BilletID, err)
+	}
+
+	// Nas conciliações com crédito não aplicado, o pagamento informado é a origem do crédito, que continua
+	// vinculado à conciliação que gerou a sobra
+	if reconciliation.TransactionID == nil || reconciliation.ConciliationStrategy == model.StrategyUnappliedCredit {
+		return nil
+	}
+
+	// Nos pagamentos parciais, todas as partes são vinculadas
+	_, err = tx.ExecContext(ctx, `
+		UPDATE bank_reconciliation.payments
+		SET reconciliation_id = $1, billet_id = $2, status = $3
+		WHERE id = $4 OR id = ANY($5)
+	`, reconciliation.ID, reconciliation.BilletID, string(model.PaymentStatusReconciled), *reconciliation.TransactionID,
+		pq.Array(reconciliation.TransactionIDs))
+	if err != nil {
+		return fmt.Errorf("erro ao vincular pagamento %s à conciliação: %w", *reconciliation.TransactionID, err)
+	}
+
+	return nil
+}
+
+// linkApprovedReconciliation vincula o boleto e os pagamentos à conciliação que acabou de ser aprovada,
+// lida na própria transação da mudança de status
+func linkApprovedReconciliation(ctx context.Context, tx database.Tx, reconciliationID string) error {
+	rows, err := tx.QueryContext(ctx, selectReconciliations+` WHERE id = $1`, reconciliationID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar conciliação %s: %w", reconciliationID, err)
+	}
+
+	var reconciliation *model.Reconciliation
+	if rows.Next() {
+		reconciliation, err = scanReconciliation(rows)
+	}
+	if err == nil {
+		err = rows.Err()
+	}
+	rows.Close()
+	if err != nil {
+		return fmt.Errorf("erro ao ler conciliação %s: %w", reconciliationID, err)
+	}
+	if reconciliation == nil {
+		return fmt.Errorf("conciliação %s não encontrada", reconciliationID)
+	}
+
+	return linkReconciliation(ctx, tx, reconciliation)
+}
+
+// unlinkReconciliation desfaz o vínculo dos boletos e pagamentos com a conciliação, que voltam à
+// situação inicial
+func unlinkReconciliation(ctx context.Context, tx database.Tx, reconciliationID string) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE bank_reconciliation.billets
+		SET reconciliation_id = NULL, transaction_id = NULL, status = $1
+		WHERE reconciliation_id = $2
+	`, string(model.BilletStatusIssued), reconciliationID)
+	if err != nil {
+		return fmt.Errorf("erro ao desvincular boletos da conciliação: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE bank_reconciliation.payments
+		SET reconciliation_id = NULL, billet_id = NULL, status = $1
+		WHERE reconciliation_id = $2
+	`, string(model.PaymentStatusReceived), reconciliationID)
+	if err != nil {
+		return fmt.Errorf("erro ao desvincular pagamentos da conciliação: %w", err)
+	}
+
+	return nil
+}
+
+// GetReconciliationHistory recupera o histórico de conciliações para auditoria
+func (r *ReconciliationRepositoryImpl) GetReconciliationHistory(ctx context.Context, billetID string) ([]*model.Reconciliation, error) {
+	query, args := database.NewQuery(selectReconciliations).
+		Where("billet_id = ?", billetID).
+		OrderBy("reconciliation_date ASC").
+		Build()
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return r.queryReconciliations(ctxWithTimeout, "erro ao buscar histórico de conciliações", query, args...)
+}
+
+// GetReconciliationHistoryByTransactionID recupera o histórico de conciliações de um pagamento para auditoria
+func (r *ReconciliationRepositoryImpl) GetReconciliationHistoryByTransactionID(ctx context.Context, transactionID string) ([]*model.Reconciliation, error) {
+	query, args := database.NewQuery(selectReconciliations).
+		Where("(transaction_id = ? OR ? = ANY(transaction_ids))", transactionID, transactionID).
+		OrderBy("reconciliation_date ASC").
+		Build()
 
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	rows, err := r.db.QueryContext(ctxWithTimeout, query, billetID)
+	return r.queryReconciliations(ctxWithTimeout, "erro ao buscar histórico de conciliações do pagamento", query, args...)
+}
+
+// GetTimeToReconcileStatistics calcula os percentis (p50/p90/p99) do tempo entre pagamento e conciliação por conta
+func (r *ReconciliationRepositoryImpl) GetTimeToReconcileStatistics(ctx context.Context, filter model.TimeToReconcileFilter) ([]*model.TimeToReconcileStatistics, error) {
+	dateColumn, join := reconciliationDateColumn(filter.DateField)
+
+	q := database.NewQuery(`
+		SELECT
+			r.bank_account,
+			COUNT(*),
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY r.time_to_reconcile_seconds),
+			percentile_cont(0.9) WITHIN GROUP (ORDER BY r.time_to_reconcile_seconds),
+			percentile_cont(0.99) WITHIN GROUP (ORDER BY r.time_to_reconcile_seconds)
+		FROM bank_reconciliation.reconciliations r` + join).
+		Where("r.time_to_reconcile_seconds IS NOT NULL")
+
+	if filter.BankAccount != "" {
+		q.Where("r.bank_account = ?", filter.BankAccount)
+	}
+
+	if filter.EngineVersion != "" {
+		q.Where("r.engine_version = ?", filter.EngineVersion)
+	}
+
+	if filter.StartDate != nil {
+		q.Where(dateColumn+" >= ?", *filter.StartDate)
+	}
+
+	if filter.EndDate != nil {
+		q.Where(dateColumn+" < ?", filter.EndDate.AddDate(0, 0, 1))
+	}
+
+	query, args := q.GroupBy("r.bank_account").OrderBy("r.bank_account").Build()
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctxWithTimeout, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar conciliações por boleto: %w", err)
+		return nil, fmt.Errorf("erro ao calcular tempo até conciliação: %w", err)
 	}
 	defer rows.Close()
 
-	reconciliations := []*model.Reconciliation{}
+	statistics := []*model.TimeToReconcileStatistics{}
 
 	for rows.Next() {
-		reconciliation := &model.Reconciliation{}
-		var conciliationStatus, conciliationStrategy string
-		var referenceID sql.NullString
+		stats := &model.TimeToReconcileStatistics{}
 
 		err := rows.Scan(
-			&reconciliation.ID,
-			&reconciliation.BilletID,
-			&reconciliation.TransactionID,
-			&reconciliation.ReconciliationDate,
-			&conciliationStatus,
-			&conciliationStrategy,
-			&reconciliation.AmountDiff,
-			&referenceID,
+			&stats.BankAccount,
+			&stats.Count,
+			&stats.P50Seconds,
+			&stats.P90Seconds,
+			&stats.P99Seconds,
 		)
 
 		if err != nil {
-			return nil, fmt.Errorf("erro ao ler conciliação: %w", err)
+			return nil, fmt.Errorf("erro ao ler estatística de tempo até conciliação: %w", err)
 		}
 
-		// Converter os valores de string para os tipos de enum
-		reconciliation.ConciliationStatus = model.ConciliationStatus(conciliationStatus)
-		reconciliation.ConciliationStrategy = model.ConciliationStrategy(conciliationStrategy)
+		statistics = append(statistics, stats)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return statistics, nil
+}
+
+// GetDailyStatistics calcula, por dia e conta, a quantidade de conciliações de cada status e os
+// valores de boletos, pagamentos e diferenças, agrupando pela data escolhida em filter.DateField
+func (r *ReconciliationRepositoryImpl) GetDailyStatistics(ctx context.Context, filter model.DailyStatisticsFilter) ([]*model.DailyReconciliationStatistics, error) {
+	dateColumn := "r.reconciliation_date"
+	switch filter.DateField {
+	case model.DateFieldPayment:
+		dateColumn = "p.payment_date"
+	case model.DateFieldIssuance:
+		dateColumn = "b.issuance_date"
+	}
+
+	// Os status contados ocupam os primeiros parâmetros da consulta; os boletos pagos com juros contam
+	// como conciliados com sucesso
+	q := database.NewQuery(`
+		SELECT
+			DATE(`+dateColumn+`) AS day,
+			r.bank_account,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE r.conciliation_status IN (?, ?)),
+			COUNT(*) FILTER (WHERE r.conciliation_status = ?),
+			COUNT(*) FILTER (WHERE r.conciliation_status = ?),
+			COUNT(*) FILTER (WHERE r.conciliation_status = ?),
+			COALESCE(SUM(b.amount), 0),
+			COALESCE(SUM(p.amount), 0),
+			COALESCE(SUM(r.amount_diff), 0)
+		FROM bank_reconciliation.reconciliations r
+		JOIN bank_reconciliation.billets b ON b.id = r.billet_id
+		LEFT JOIN bank_reconciliation.payments p ON p.id = r.transaction_id`,
+		string(model.StatusSuccessful),
+		string(model.StatusPaidWithInterest),
+		string(model.StatusDifferentValue),
+		string(model.StatusNotReconciled),
+		string(model.StatusAmbiguousRef),
+	).Where(dateColumn + " IS NOT NULL")
+
+	if filter.BankAccount != "" {
+		q.Where("r.bank_account = ?", filter.BankAccount)
+	}
+	if filter.StartDate != nil {
+		q.Where(dateColumn+" >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		q.Where(dateColumn+" < ?", filter.EndDate.AddDate(0, 0, 1))
+	}
+
+	query, args := q.GroupBy("day, r.bank_account").OrderBy("day, r.bank_account").Build()
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctxWithTimeout, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao calcular estatísticas diárias de conciliação: %w", err)
+	}
+	defer rows.Close()
+
+	statistics := []*model.DailyReconciliationStatistics{}
+
+	for rows.Next() {
+		stats := &model.DailyReconciliationStatistics{}
+
+		err := rows.Scan(
+			&stats.Date,
+			&stats.BankAccount,
+			&stats.Total,
+			&stats.Successful,
+			&stats.DifferentValue,
+			&stats.NotReconciled,
+			&stats.AmbiguousRef,
+			&stats.BilletAmount,
+			&stats.PaidAmount,
+			&stats.AmountDiff,
+		)
 
-		// Tratar campo opcional
-		if referenceID.Valid {
-			reconciliation.ReferenceID = &referenceID.String
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler estatística diária de conciliação: %w", err)
 		}
 
-		reconciliations = append(reconciliations, reconciliation)
+		statistics = append(statistics, stats)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
 	}
 
-	return reconciliations, nil
+	return statistics, nil
 }
 
-// GetByTransactionID recupera conciliações por ID da transação
-func (r *ReconciliationRepositoryImpl) GetByTransactionID(ctx context.Context, transactionID string) ([]*model.Reconciliation, error) {
-	query := `
-		SELECT 
-			id, billet_id, transaction_id, reconciliation_date, 
-			conciliation_status, conciliation_strategy, amount_diff, reference_id
-		FROM reconciliation
-		WHERE transaction_id = ?
-		ORDER BY reconciliation_date DESC
-	`
+// GetEngineVersionStatistics calcula, por dia, versão do motor de matching e conta, a quantidade de
+// conciliações de cada situação, agrupando pela data escolhida em filter.DateField. Registros sem versão,
+// como as conciliações manuais e os anteriores ao controle de versão, ficam de fora
+func (r *ReconciliationRepositoryImpl) GetEngineVersionStatistics(ctx context.Context, filter model.EngineVersionStatisticsFilter) ([]*model.EngineVersionStatistics, error) {
+	dateColumn, join := reconciliationDateColumn(filter.DateField)
+
+	// Os status contados ocupam os primeiros parâmetros da consulta
+	q := database.NewQuery(`
+		SELECT
+			DATE(`+dateColumn+`) AS day,
+			r.engine_version,
+			r.bank_account,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE r.conciliation_status IN (?, ?, ?, ?)),
+			COUNT(*) FILTER (WHERE r.conciliation_status = ?),
+			COUNT(*) FILTER (WHERE r.conciliation_status = ?),
+			COUNT(*) FILTER (WHERE r.conciliation_status = ?)
+		FROM bank_reconciliation.reconciliations r`+join,
+		string(model.StatusSuccessful),
+		string(model.StatusDifferentValue),
+		string(model.StatusPartiallyReconciled),
+		string(model.StatusPaidWithInterest),
+		string(model.StatusSuggested),
+		string(model.StatusNotReconciled),
+		string(model.StatusAmbiguousRef),
+	).Where("r.engine_version IS NOT NULL")
+
+	if filter.BankAccount != "" {
+		q.Where("r.bank_account = ?", filter.BankAccount)
+	}
+	if filter.EngineVersion != "" {
+		q.Where("r.engine_version = ?", filter.EngineVersion)
+	}
+	if filter.StartDate != nil {
+		q.Where(dateColumn+" >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		q.Where(dateColumn+" < ?", filter.EndDate.AddDate(0, 0, 1))
+	}
 
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+	query, args := q.GroupBy("day, r.engine_version, r.bank_account").
+		OrderBy("day, r.engine_version, r.bank_account").
+		Build()
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	rows, err := r.db.QueryContext(ctxWithTimeout, query, transactionID)
+	rows, err := r.db.QueryContext(ctxWithTimeout, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar conciliações por transação: %w", err)
+		return nil, fmt.Errorf("erro ao calcular estatísticas por versão do motor: %w", err)
 	}
 	defer rows.Close()
 
-	reconciliations := []*model.Reconciliation{}
+	statistics := []*model.EngineVersionStatistics{}
 
 	for rows.Next() {
-		reconciliation := &model.Reconciliation{}
-		var conciliationStatus, conciliationStrategy string
-		var referenceID sql.NullString
+		stats := &model.EngineVersionStatistics{}
 
 		err := rows.Scan(
-			&reconciliation.ID,
-			&reconciliation.BilletID,
-			&reconciliation.TransactionID,
-			&reconciliation.ReconciliationDate,
-			&conciliationStatus,
-			&conciliationStrategy,
-			&reconciliation.AmountDiff,
-			&referenceID,
+			&stats.Date,
+			&stats.EngineVersion,
+			&stats.BankAccount,
+			&stats.Total,
+			&stats.Matched,
+			&stats.Suggested,
+			&stats.NotReconciled,
+			&stats.AmbiguousRef,
 		)
 
 		if err != nil {
-			return nil, fmt.Errorf("erro ao ler conciliação: %w", err)
+			return nil, fmt.Errorf("erro ao ler estatística por versão do motor: %w", err)
+		}
+
+		if stats.Total > 0 {
+			stats.MatchRate = float64(stats.Matched) / float64(stats.Total) * 100
 		}
 
-		// Converter os valores de string para os tipos de enum
-		reconciliation.ConciliationStatus = model.ConciliationStatus(conciliationStatus)
-		reconciliation.ConciliationStrategy = model.ConciliationStrategy(conciliationStrategy)
+		statistics = append(statistics, stats)
+	}
 
-		// Tratar campo opcional
-		if referenceID.Valid {
-			reconciliation.ReferenceID = &referenceID.String
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return statistics, nil
+}
+
+// GetByFilter recupera as conciliações por conta, status, estratégia, execução e período, aplicando o
+// filtro de período sobre a data escolhida (pagamento, conciliação ou emissão do boleto)
+func (r *ReconciliationRepositoryImpl) GetByFilter(ctx context.Context, filter model.ReconciliationFilter) ([]*model.Reconciliation, error) {
+	dateColumn, join := reconciliationDateColumn(filter.DateField)
+
+	q := database.NewQuery(`
+		SELECT ` + database.QualifyColumns("r", reconciliationColumns) + `
+		FROM bank_reconciliation.reconciliations r` + join)
+
+	if filter.BankAccount != "" {
+		q.Where("r.bank_account = ?", filter.BankAccount)
+	}
+
+	if filter.Status != "" {
+		q.Where("r.conciliation_status = ?", string(filter.Status))
+	}
+
+	if filter.Strategy != "" {
+		q.Where("r.conciliation_strategy = ?", string(filter.Strategy))
+	}
+
+	if filter.RunID != "" {
+		q.Where("r.run_id = ?", filter.RunID)
+	}
+
+	if filter.EngineVersion != "" {
+		q.Where("r.engine_version = ?", filter.EngineVersion)
+	}
+
+	if filter.StartDate != nil {
+		q.Where(dateColumn+" >= ?", *filter.StartDate)
+	}
+
+	if filter.EndDate != nil {
+		q.Where(dateColumn+" < ?", filter.EndDate.AddDate(0, 0, 1))
+	}
+
+	query, args := q.OrderBy(dateColumn+" DESC, r.id").Page(filter.Limit, filter.Offset).Build()
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return r.queryReconciliations(ctxWithTimeout, "erro ao buscar conciliações", query, args...)
+}
+
+// queryReconciliations executa a consulta e lê as conciliações retornadas
+func (r *ReconciliationRepositoryImpl) queryReconciliations(ctx context.Context, errorMessage, query string, args ...interface{}) ([]*model.Reconciliation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errorMessage, err)
+	}
+	defer rows.Close()
+
+	reconciliations := []*model.Reconciliation{}
+
+	for rows.Next() {
+		reconciliation, err := scanReconciliation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler conciliação: %w", err)
 		}
 
 		reconciliations = append(reconciliations, reconciliation)
@@ -348,54 +940,95 @@ func (r *ReconciliationRepositoryImpl) GetByTransactionID(ctx context.Context, t
 	return reconciliations, nil
 }
 
-// Update atualiza uma conciliação existente
-func (r *ReconciliationRepositoryImpl) Update(ctx context.Context, reconciliation *model.Reconciliation) error {
-	query := `
-		UPDATE reconciliation 
-		SET 
-			billet_id = ?, 
-			transaction_id = ?, 
-			reconciliation_date = ?, 
-			conciliation_status = ?, 
-			conciliation_strategy = ?, 
-			amount_diff = ?, 
-			reference_id = ?
-		WHERE id = ?
-	`
-
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
+// scanReconciliation lê uma conciliação a partir de uma linha de resultado
+func scanReconciliation(scanner rowScanner) (*model.Reconciliation, error) {
+	reconciliation := &model.Reconciliation{}
+	var conciliationStatus, conciliationStrategy string
+	var referenceID, engineVersion sql.NullString
 
-	_, err := r.db.ExecContext(
-		ctxWithTimeout,
-		query,
-		reconciliation.BilletID,
-		reconciliation.TransactionID,
-		reconciliation.ReconciliationDate,
-		string(reconciliation.ConciliationStatus),
-		string(reconciliation.ConciliationStrategy),
-		reconciliation.AmountDiff,
-		reconciliation.ReferenceID,
-		reconciliation.ID,
+	err := scanner.Scan(
+		&reconciliation.ID,
+		&reconciliation.BilletID,
+		&reconciliation.TransactionID,
+		&reconciliation.BankAccount,
+		&reconciliation.ReconciliationDate,
+		&conciliationStatus,
+		&conciliationStrategy,
+		&reconciliation.AmountDiff,
+		&referenceID,
+		&reconciliation.TimeToReconcileSeconds,
+		&reconciliation.RunID,
+		pq.Array(&reconciliation.TransactionIDs),
+		&reconciliation.GroupID,
+		&reconciliation.Confidence,
+		&engineVersion,
 	)
-
 	if err != nil {
-		return fmt.Errorf("erro ao atualizar conciliação: %w", err)
+		return nil, err
 	}
 
-	return nil
+	// Converter os valores de string para os tipos de enum
+	reconciliation.ConciliationStatus = model.ConciliationStatus(conciliationStatus)
+	reconciliation.ConciliationStrategy = model.ConciliationStrategy(conciliationStrategy)
+
+	// Tratar campos opcionais
+	if referenceID.Valid {
+		reconciliation.ReferenceID = &referenceID.String
+	}
+	reconciliation.EngineVersion = engineVersion.String
+
+	return reconciliation, nil
 }
 
-// Delete remove uma conciliação pelo ID
-func (r *ReconciliationRepositoryImpl) Delete(ctx context.Context, id string) error {
-	query := "DELETE FROM reconciliation WHERE id = ?"
+// reconciliationDateColumn retorna a coluna de data do filtro de período e o join necessário
+// para alcançá-la a partir da conciliação (alias r)
+func reconciliationDateColumn(field model.ReconciliationDateField) (string, string) {
+	switch field {
+	case model.DateFieldPayment:
+		return "p.payment_date", `
+		JOIN bank_reconciliation.payments p ON p.id = r.transaction_id`
+	case model.DateFieldIssuance:
+		return "b.issuance_date", `
+		JOIN bank_reconciliation.billets b ON b.id = r.billet_id`
+	default:
+		return "r.reconciliation_date", ""
+	}
+}
 
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
+// UpdateStatus aplica a mudança de status e de diferença de valor à conciliação e registra a
+// mudança no histórico, na mesma transação
+func (r *ReconciliationRepositoryImpl) UpdateStatus(ctx context.Context, change *model.ReconciliationStatusChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := applyStatusChange(ctx, tx, change); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
 
-	result, err := r.db.ExecContext(ctxWithTimeout, query, id)
+// applyStatusChange aplica a mudança de status à conciliação na transação informada, vinculando o boleto
+// e os pagamentos quando ela passa a pareada, e registra a mudança no histórico
+func applyStatusChange(ctx context.Context, tx database.Tx, change *model.ReconciliationStatusChange) error {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE bank_reconciliation.reconciliations
+		SET
+			conciliation_status = $1,
+			amount_diff = $2
+		WHERE
+			id = $3
+			AND conciliation_status = $4
+	`, string(change.NewStatus), change.NewAmountDiff, change.ReconciliationID, string(change.PreviousStatus))
 	if err != nil {
-		return fmt.Errorf("erro ao excluir conciliação: %w", err)
+		return fmt.Errorf("erro ao atualizar status da conciliação: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -403,70 +1036,91 @@ func (r *ReconciliationRepositoryImpl) Delete(ctx context.Context, id string) er
 		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
 	}
 
+	// O status anterior faz parte do filtro para não sobrescrever uma reavaliação concorrente
 	if rowsAffected == 0 {
-		return fmt.Errorf("nenhuma conciliação encontrada com o ID: %s", id)
+		return fmt.Errorf("conciliação %s não encontrada com o status %s", change.ReconciliationID, change.PreviousStatus)
 	}
 
-	return nil
-}
+	// Um pareamento que aguardava aprovação só vincula o boleto e os pagamentos quando aprovado
+	if change.NewStatus.IsMatched() && !change.PreviousStatus.IsMatched() {
+		if err := linkApprovedReconciliation(ctx, tx, change.ReconciliationID); err != nil {
+			return err
+		}
+	}
 
-// GetReconciliationHistory recupera o histórico de conciliações para auditoria
-func (r *ReconciliationRepositoryImpl) GetReconciliationHistory(ctx context.Context, billetID string) ([]*model.Reconciliation, error) {
-	query := `
-		SELECT 
-			id, billet_id, transaction_id, reconciliation_date, 
-			conciliation_status, conciliation_strategy, amount_diff, reference_id
-		FROM reconciliation
-		WHERE billet_id = ?
-		ORDER BY reconciliation_date ASC
-	`
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO bank_reconciliation.reconciliation_status_changes (
+			id, reconciliation_id, billet_id, bank_account, previous_status, new_status,
+			previous_amount_diff, new_amount_diff, reason, changed_by, changed_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`,
+		change.ID,
+		change.ReconciliationID,
+		change.BilletID,
+		change.BankAccount,
+		string(change.PreviousStatus),
+		string(change.NewStatus),
+		change.PreviousAmountDiff,
+		change.NewAmountDiff,
+		change.Reason,
+		change.ChangedBy,
+		change.ChangedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar mudança de status: %w", err)
+	}
 
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
+	return nil
+}
 
-	rows, err := r.db.QueryContext(ctxWithTimeout, query, billetID)
+// GetStatusChanges recupera o histórico de mudanças de status das conciliações de um boleto
+func (r *ReconciliationRepositoryImpl) GetStatusChanges(ctx context.Context, billetID string) ([]*model.ReconciliationStatusChange, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			id, reconciliation_id, billet_id, bank_account, previous_status, new_status,
+			previous_amount_diff, new_amount_diff, reason, changed_by, changed_at
+		FROM
+			bank_reconciliation.reconciliation_status_changes
+		WHERE
+			billet_id = $1
+		ORDER BY
+			changed_at
+	`, billetID)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar histórico de conciliações: %w", err)
+		return nil, fmt.Errorf("erro ao buscar histórico de status: %w", err)
 	}
 	defer rows.Close()
 
-	reconciliations := []*model.Reconciliation{}
-
+	changes := []*model.ReconciliationStatusChange{}
 	for rows.Next() {
-		reconciliation := &model.Reconciliation{}
-		var conciliationStatus, conciliationStrategy string
-		var referenceID sql.NullString
+		change := &model.ReconciliationStatusChange{}
+		var previousStatus, newStatus string
 
 		err := rows.Scan(
-			&reconciliation.ID,
-			&reconciliation.BilletID,
-			&reconciliation.TransactionID,
-			&reconciliation.ReconciliationDate,
-			&conciliationStatus,
-			&conciliationStrategy,
-			&reconciliation.AmountDiff,
-			&referenceID,
+			&change.ID,
+			&change.ReconciliationID,
+			&change.BilletID,
+			&change.BankAccount,
+			&previousStatus,
+			&newStatus,
+			&change.PreviousAmountDiff,
+			&change.NewAmountDiff,
+			&change.Reason,
+			&change.ChangedBy,
+			&change.ChangedAt,
 		)
-
 		if err != nil {
-			return nil, fmt.Errorf("erro ao ler histórico de conciliação: %w", err)
+			return nil, fmt.Errorf("erro ao ler mudança de status: %w", err)
 		}
 
-		// Converter os valores de string para os tipos de enum
-		reconciliation.ConciliationStatus = model.ConciliationStatus(conciliationStatus)
-		reconciliation.ConciliationStrategy = model.ConciliationStrategy(conciliationStrategy)
-
-		// Tratar campo opcional
-		if referenceID.Valid {
-			reconciliation.ReferenceID = &referenceID.String
-		}
-
-		reconciliations = append(reconciliations, reconciliation)
+		change.PreviousStatus = model.ConciliationStatus(previousStatus)
+		change.NewStatus = model.ConciliationStatus(newStatus)
+		changes = append(changes, change)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("erro ao processar resultados do histórico: %w", err)
+		return nil, fmt.Errorf("erro ao processar resultados do histórico de status: %w", err)
 	}
 
-	return reconciliations, nil
+	return changes, nil
 }
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// Garantir que ReconciliationRunRepositoryImpl implementa a interface ReconciliationRunRepository
+var _ domainRepo.ReconciliationRunRepository = (*ReconciliationRunRepositoryImpl)(nil)
+
+// ReconciliationRunRepositoryImpl implementa a interface de repositório para execuções de conciliação
+type ReconciliationRunRepositoryImpl struct {
+	db database.DB
+}
+
+// NewReconciliationRunRepository cria uma nova instância do repositório de execuções de conciliação
+func NewReconciliationRunRepository(db database.DB) domainRepo.ReconciliationRunRepository {
+	return &ReconciliationRunRepositoryImpl{
+		db: db,
+	}
+}
+
+// reconciliationRunColumns lista as colunas lidas por scanReconciliationRun
+const reconciliationRunColumns = `id, params_hash, tenant, status, tolerance, engine_version, result, metrics, params, totals, started_at, finished_at`
+
+// reconciliationRunListColumns lista as mesmas colunas sem o resultado, que a listagem não exibe
+const reconciliationRunListColumns = `id, params_hash, tenant, status, tolerance, engine_version, NULL::jsonb, metrics, params, totals, started_at, finished_at`
+
+// Create persiste uma nova execução; a restrição única do hash impede duas execuções com os mesmos
+// parâmetros. Execuções sem hash (sem janela de datas) nunca conflitam
+func (r *ReconciliationRunRepositoryImpl) Create(ctx context.Context, run *model.ReconciliationRun) (bool, error) {
+	params, err := json.Marshal(run.Params)
+	if err != nil {
+		return false, fmt.Errorf("erro ao serializar parâmetros da execução: %w", err)
+	}
+
+	query := `
+		INSERT INTO bank_reconciliation.reconciliation_runs (id, params_hash, tenant, status, tolerance, engine_version, params, started_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (params_hash) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		run.ID,
+		sql.NullString{String: run.ParamsHash, Valid: run.ParamsHash != ""},
+		run.Tenant,
+		string(run.Status),
+		run.Tolerance,
+		sql.NullString{String: run.EngineVersion, Valid: run.EngineVersion != ""},
+		params,
+		run.StartedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("erro ao criar execução de conciliação: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetByID recupera uma execução pelo seu ID
+func (r *ReconciliationRunRepositoryImpl) GetByID(ctx context.Context, id string) (*model.ReconciliationRun, error) {
+	query := `SELECT ` + reconciliationRunColumns + `
+		FROM bank_reconciliation.reconciliation_runs
+		WHERE id = $1
+	`
+
+	return r.queryRun(ctx, query, id)
+}
+
+// List lista as execuções dos tenants por status e período de início, das mais recentes para as mais antigas
+func (r *ReconciliationRunRepositoryImpl) List(ctx context.Context, filter model.ReconciliationRunFilter) ([]*model.ReconciliationRun, error) {
+	q := database.NewQuery(`
+		SELECT ` + reconciliationRunListColumns + `
+		FROM bank_reconciliation.reconciliation_runs`)
+
+	q.Where("tenant = ANY(?)", pq.Array(filter.Tenants))
+
+	if filter.Status != "" {
+		q.Where("status = ?", string(filter.Status))
+	}
+
+	if filter.EngineVersion != "" {
+		q.Where("engine_version = ?", filter.EngineVersion)
+	}
+
+	if filter.StartDate != nil {
+		q.Where("started_at >= ?", *filter.StartDate)
+	}
+
+	if filter.EndDate != nil {
+		q.Where("started_at < ?", filter.EndDate.AddDate(0, 0, 1))
+	}
+
+	query, args := q.OrderBy("started_at DESC, id").Page(filter.Limit, filter.Offset).Build()
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctxWithTimeout, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar execuções de conciliação: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []*model.ReconciliationRun{}
+	for rows.Next() {
+		run, err := scanReconciliationRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler execução de conciliação: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar execuções de conciliação: %w", err)
+	}
+
+	return runs, nil
+}
+
+// GetByParamsHash recupera a execução com o hash de parâmetros informado
+func (r *ReconciliationRunRepositoryImpl) GetByParamsHash(ctx context.Context, paramsHash string) (*model.ReconciliationRun, error) {
+	query := `SELECT ` + reconciliationRunColumns + `
+		FROM bank_reconciliation.reconciliation_runs
+		WHERE params_hash = $1
+	`
+
+	return r.queryRun(ctx, query, paramsHash)
+}
+
+// Complete grava o status final, o resultado, as medições, o término e o hash de parâmetros da execução
+func (r *ReconciliationRunRepositoryImpl) Complete(ctx context.Context, run *model.ReconciliationRun) error {
+	result, err := json.Marshal(run.Result)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar resultado da execução: %w", err)
+	}
+
+	metrics, err := json.Marshal(run.Metrics)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar medições da execução: %w", err)
+	}
+
+	totals, err := json.Marshal(run.Totals)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar totais da execução: %w", err)
+	}
+
+	query := `
+		UPDATE bank_reconciliation.reconciliation_runs
+		SET status = $1, result = $2, metrics = $3, totals = $4, finished_at = $5, params_hash = $6
+		WHERE id = $7
+	`
+
+	paramsHash := sql.NullString{String: run.ParamsHash, Valid: run.ParamsHash != ""}
+	if _, err := r.db.ExecContext(ctx, query, string(run.Status), result, metrics, totals, run.FinishedAt, paramsHash, run.ID); err != nil {
+		return fmt.Errorf("erro ao concluir execução de conciliação: %w", err)
+	}
+
+	return nil
+}
+
+// Delete remove uma execução pelo ID
+func (r *ReconciliationRunRepositoryImpl) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM bank_reconciliation.reconciliation_runs WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("erro ao remover execução de conciliação: %w", err)
+	}
+
+	return nil
+}
+
+// queryRun executa a consulta de uma única execução, retornando nil quando não existe
+func (r *ReconciliationRunRepositoryImpl) queryRun(ctx context.Context, query string, args ...interface{}) (*model.ReconciliationRun, error) {
+	run, err := scanReconciliationRun(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar execução de conciliação: %w", err)
+	}
+
+	return run, nil
+}
+
+// scanReconciliationRun lê uma execução de uma linha com as colunas de reconciliationRunColumns
+func scanReconciliationRun(scanner rowScanner) (*model.ReconciliationRun, error) {
+	var run model.ReconciliationRun
+	var paramsHash, engineVersion sql.NullString
+	var status string
+	var result, metrics, params, totals []byte
+	var finishedAt sql.NullTime
+
+	err := scanner.Scan(
+		&run.ID,
+		&paramsHash,
+		&run.Tenant,
+		&status,
+		&run.Tolerance,
+		&engineVersion,
+		&result,
+		&metrics,
+		&params,
+		&totals,
+		&run.StartedAt,
+		&finishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	run.ParamsHash = paramsHash.String
+	run.EngineVersion = engineVersion.String
+	run.Status = model.ReconciliationRunStatus(status)
+	if finishedAt.Valid {
+		run.FinishedAt = &finishedAt.Time
+	}
+
+	if len(result) > 0 {
+		if err := json.Unmarshal(result, &run.Result); err != nil {
+			return nil, fmt.Errorf("erro ao decodificar resultado da execução %s: %w", run.ID, err)
+		}
+	}
+
+	if len(metrics) > 0 {
+		if err := json.Unmarshal(metrics, &run.Metrics); err != nil {
+			return nil, fmt.Errorf("erro ao decodificar medições da execução %s: %w", run.ID, err)
+		}
+	}
+
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &run.Params); err != nil {
+			return nil, fmt.Errorf("erro ao decodificar parâmetros da execução %s: %w", run.ID, err)
+		}
+	}
+
+	if len(totals) > 0 {
+		if err := json.Unmarshal(totals, &run.Totals); err != nil {
+			return nil, fmt.Errorf("erro ao decodificar totais da execução %s: %w", run.ID, err)
+		}
+	}
+
+	return &run, nil
+}
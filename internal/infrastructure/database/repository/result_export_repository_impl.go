@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// Garantir que ResultExportRepositoryImpl implementa a interface ResultExportRepository
+var _ domainRepo.ResultExportRepository = (*ResultExportRepositoryImpl)(nil)
+
+// ResultExportRepositoryImpl implementa a interface de repositório para os arquivos de resultado
+type ResultExportRepositoryImpl struct {
+	db database.DB
+}
+
+// NewResultExportRepository cria uma nova instância do repositório de arquivos de resultado
+func NewResultExportRepository(db database.DB) domainRepo.ResultExportRepository {
+	return &ResultExportRepositoryImpl{
+		db: db,
+	}
+}
+
+// resultExportColumns lista as colunas lidas por scanResultExport, sem o conteúdo
+const resultExportColumns = `id, run_id, format, file_key, sequence, file_name, destination, status, attempts,
+	last_error, last_attempt_by, created_at, last_attempt_at, sent_at`
+
+// Create persiste o registro de um arquivo de resultado
+func (r *ResultExportRepositoryImpl) Create(ctx context.Context, export *model.ResultExport) error {
+	query := `
+		INSERT INTO bank_reconciliation.result_exports (` + resultExportColumns + `, content)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		export.ID,
+		export.RunID,
+		export.Format,
+		export.FileKey,
+		export.Sequence,
+		export.FileName,
+		export.Destination,
+		string(export.Status),
+		export.Attempts,
+		export.LastError,
+		export.LastAttemptBy,
+		export.CreatedAt,
+		export.LastAttemptAt,
+		export.SentAt,
+		export.Content,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar arquivo de resultado: %w", err)
+	}
+
+	return nil
+}
+
+// NextSequence retorna o próximo número sequencial dos arquivos do layout com a chave informada
+func (r *ResultExportRepositoryImpl) NextSequence(ctx context.Context, format, fileKey string) (int, error) {
+	query := `
+		SELECT COALESCE(MAX(sequence), 0) + 1
+		FROM bank_reconciliation.result_exports
+		WHERE format = $1 AND file_key = $2
+	`
+
+	var sequence int
+	if err := r.db.QueryRowContext(ctx, query, format, fileKey).Scan(&sequence); err != nil {
+		return 0, fmt.Errorf("erro ao buscar sequência do arquivo de resultado: %w", err)
+	}
+
+	return sequence, nil
+}
+
+// GetByID recupera um arquivo de resultado com o conteúdo, ou nil quando não existe
+func (r *ResultExportRepositoryImpl) GetByID(ctx context.Context, id string) (*model.ResultExport, error) {
+	query := `SELECT ` + resultExportColumns + `, content
+		FROM bank_reconciliation.result_exports
+		WHERE id = $1
+	`
+
+	var content []byte
+	export, err := scanResultExport(r.db.QueryRowContext(ctx, query, id), &content)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar arquivo de resultado: %w", err)
+	}
+
+	export.Content = content
+	return export, nil
+}
+
+// List recupera os arquivos de resultado, sem o conteúdo, dos mais recentes para os mais antigos
+func (r *ResultExportRepositoryImpl) List(ctx context.Context, runID string) ([]*model.ResultExport, error) {
+	query := `SELECT ` + resultExportColumns + `
+		FROM bank_reconciliation.result_exports`
+
+	var args []interface{}
+	if runID != "" {
+		query += ` WHERE run_id = $1`
+		args = append(args, runID)
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar arquivos de resultado: %w", err)
+	}
+	defer rows.Close()
+
+	exports := []*model.ResultExport{}
+	for rows.Next() {
+		export, err := scanResultExport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler arquivo de resultado: %w", err)
+		}
+		exports = append(exports, export)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return exports, nil
+}
+
+// UpdateAttempt grava a situação e a última tentativa de envio
+func (r *ResultExportRepositoryImpl) UpdateAttempt(ctx context.Context, export *model.ResultExport) error {
+	query := `
+		UPDATE bank_reconciliation.result_exports
+		SET status = $2, attempts = $3, last_error = $4, last_attempt_by = $5, last_attempt_at = $6, sent_at = $7
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		export.ID,
+		string(export.Status),
+		export.Attempts,
+		export.LastError,
+		export.LastAttemptBy,
+		export.LastAttemptAt,
+		export.SentAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar envio do arquivo de resultado: %w", err)
+	}
+
+	return nil
+}
+
+// scanResultExport lê um arquivo de resultado de uma linha com as colunas de resultExportColumns,
+// seguidas das colunas extras informadas
+func scanResultExport(scanner rowScanner, extra ...interface{}) (*model.ResultExport, error) {
+	var export model.ResultExport
+	var status string
+	var lastAttemptAt, sentAt sql.NullTime
+
+	dest := []interface{}{
+		&export.ID,
+		&export.RunID,
+		&export.Format,
+		&export.FileKey,
+		&export.Sequence,
+		&export.FileName,
+		&export.Destination,
+		&status,
+		&export.Attempts,
+		&export.LastError,
+		&export.LastAttemptBy,
+		&export.CreatedAt,
+		&lastAttemptAt,
+		&sentAt,
+	}
+
+	if err := scanner.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	export.Status = model.ExportStatus(status)
+	if lastAttemptAt.Valid {
+		export.LastAttemptAt = &lastAttemptAt.Time
+	}
+	if sentAt.Valid {
+		export.SentAt = &sentAt.Time
+	}
+
+	return &export, nil
+}
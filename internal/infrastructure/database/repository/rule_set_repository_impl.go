@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// Garantir que RuleSetRepositoryImpl implementa a interface RuleSetRepository
+var _ domainRepo.RuleSetRepository = (*RuleSetRepositoryImpl)(nil)
+
+// RuleSetRepositoryImpl implementa a interface de repositório para os conjuntos de regras de matching
+type RuleSetRepositoryImpl struct {
+	db database.DB
+}
+
+// NewRuleSetRepository cria uma nova instância do repositório de conjuntos de regras
+func NewRuleSetRepository(db database.DB) domainRepo.RuleSetRepository {
+	return &RuleSetRepositoryImpl{
+		db: db,
+	}
+}
+
+// ruleSetColumns lista as colunas lidas por scanRuleSet
+const ruleSetColumns = `tenant_id, bank_account, strategies, updated_by, updated_at`
+
+// Save cria ou substitui o conjunto de regras do tenant e da conta
+func (r *RuleSetRepositoryImpl) Save(ctx context.Context, ruleSet *model.RuleSet) error {
+	strategies, err := json.Marshal(ruleSet.Strategies)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar estratégias: %w", err)
+	}
+
+	query := `
+		INSERT INTO bank_reconciliation.rule_sets (` + ruleSetColumns + `)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id, bank_account) DO UPDATE SET
+			strategies = EXCLUDED.strategies,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		ruleSet.Tenant,
+		ruleSet.BankAccount,
+		strategies,
+		ruleSet.UpdatedBy,
+		ruleSet.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao gravar conjunto de regras: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTenant recupera os conjuntos de regras de um tenant, o geral antes dos das contas
+func (r *RuleSetRepositoryImpl) GetByTenant(ctx context.Context, tenant string) (model.RuleSets, error) {
+	query := `SELECT ` + ruleSetColumns + `
+		FROM bank_reconciliation.rule_sets
+		WHERE tenant_id = $1
+		ORDER BY bank_account
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tenant)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar conjuntos de regras: %w", err)
+	}
+	defer rows.Close()
+
+	ruleSets := model.RuleSets{}
+	for rows.Next() {
+		ruleSet, err := scanRuleSet(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler conjunto de regras: %w", err)
+		}
+		ruleSets = append(ruleSets, ruleSet)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return ruleSets, nil
+}
+
+// Delete remove o conjunto de regras do tenant e da conta; vazia, remove o conjunto geral
+func (r *RuleSetRepositoryImpl) Delete(ctx context.Context, tenant, bankAccount string) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM bank_reconciliation.rule_sets
+		WHERE tenant_id = $1 AND bank_account = $2
+	`, tenant, bankAccount)
+	if err != nil {
+		return fmt.Errorf("erro ao remover conjunto de regras: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("conjunto de regras", tenant+"/"+bankAccount)
+	}
+
+	return nil
+}
+
+// scanRuleSet lê um conjunto de regras de uma linha com as colunas de ruleSetColumns
+func scanRuleSet(scanner rowScanner) (*model.RuleSet, error) {
+	var ruleSet model.RuleSet
+	var strategies []byte
+
+	err := scanner.Scan(
+		&ruleSet.Tenant,
+		&ruleSet.BankAccount,
+		&strategies,
+		&ruleSet.UpdatedBy,
+		&ruleSet.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(strategies, &ruleSet.Strategies); err != nil {
+		return nil, fmt.Errorf("estratégias inválidas no conjunto de regras: %w", err)
+	}
+
+	return &ruleSet, nil
+}
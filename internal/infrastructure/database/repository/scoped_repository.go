@@ -0,0 +1,644 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// Os repositórios com escopo decoram os repositórios SQL aplicando o escopo de contas
+// bancárias do contexto (model.AccessScope): listas são filtradas e acessos diretos a
+// registros de contas fora do escopo retornam errors.ForbiddenError
+
+// ScopedBilletRepository aplica o escopo de contas às operações de boletos
+type ScopedBilletRepository struct {
+	inner domainRepo.BilletRepository
+}
+
+// NewScopedBilletRepository cria uma nova instância de ScopedBilletRepository
+func NewScopedBilletRepository(inner domainRepo.BilletRepository) domainRepo.BilletRepository {
+	return &ScopedBilletRepository{inner: inner}
+}
+
+// Create persiste um novo boleto se a conta estiver no escopo
+func (r *ScopedBilletRepository) Create(ctx context.Context, billet *model.Billet) (*model.Billet, error) {
+	if !model.AccessScopeFromContext(ctx).AllowsAccount(billet.BankAccount) {
+		return nil, errors.NewForbiddenError("boleto", billet.ID)
+	}
+	return r.inner.Create(ctx, billet)
+}
+
+// CreateMany persiste múltiplos boletos se todas as contas estiverem no escopo
+func (r *ScopedBilletRepository) CreateMany(ctx context.Context, billets []*model.Billet) error {
+	scope := model.AccessScopeFromContext(ctx)
+	for _, billet := range billets {
+		if !scope.AllowsAccount(billet.BankAccount) {
+			return errors.NewForbiddenError("boleto", billet.ID)
+		}
+	}
+	return r.inner.CreateMany(ctx, billets)
+}
+
+// GetByID recupera um boleto se a conta estiver no escopo
+func (r *ScopedBilletRepository) GetByID(ctx context.Context, id string) (*model.Billet, error) {
+	billet, err := r.inner.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !model.AccessScopeFromContext(ctx).AllowsAccount(billet.BankAccount) {
+		return nil, errors.NewForbiddenError("boleto", id)
+	}
+	return billet, nil
+}
+
+// GetAll recupera os boletos das contas do escopo
+func (r *ScopedBilletRepository) GetAll(ctx context.Context) ([]*model.Billet, error) {
+	billets, err := r.inner.GetAll(ctx)
+	return filterBillets(ctx, billets), err
+}
+
+// List recupera os boletos do filtro das contas do escopo
+func (r *ScopedBilletRepository) List(ctx context.Context, filter model.BilletFilter) ([]*model.Billet, error) {
+	if filter.BankAccount != "" && !model.AccessScopeFromContext(ctx).AllowsAccount(filter.BankAccount) {
+		return []*model.Billet{}, nil
+	}
+
+	billets, err := r.inner.List(ctx, filter)
+	return filterBillets(ctx, billets), err
+}
+
+// GetByBankAccount recupera boletos por conta bancária, se a conta estiver no escopo
+func (r *ScopedBilletRepository) GetByBankAccount(ctx context.Context, bankAccount string) ([]*model.Billet, error) {
+	if !model.AccessScopeFromContext(ctx).AllowsAccount(bankAccount) {
+		return []*model.Billet{}, nil
+	}
+	return r.inner.GetByBankAccount(ctx, bankAccount)
+}
+
+// GetByIDs recupera os boletos com os IDs informados das contas do escopo; os demais ficam como não encontrados
+func (r *ScopedBilletRepository) GetByIDs(ctx context.Context, ids []string) ([]*model.Billet, error) {
+	billets, err := r.inner.GetByIDs(ctx, ids)
+	return filterBillets(ctx, billets), err
+}
+
+// GetByReferenceID recupera boletos por ID de referência das contas do escopo
+func (r *ScopedBilletRepository) GetByReferenceID(ctx context.Context, referenceID string) ([]*model.Billet, error) {
+	billets, err := r.inner.GetByReferenceID(ctx, referenceID)
+	return filterBillets(ctx, billets), err
+}
+
+// Update atualiza um boleto se a conta atual e a nova estiverem no escopo
+func (r *ScopedBilletRepository) Update(ctx context.Context, billet *model.Billet) (*model.Billet, error) {
+	if _, err := r.GetByID(ctx, billet.ID); err != nil {
+		return nil, err
+	}
+
+	if !model.AccessScopeFromContext(ctx).AllowsAccount(billet.BankAccount) {
+		return nil, errors.NewForbiddenError("boleto", billet.ID)
+	}
+	return r.inner.Update(ctx, billet)
+}
+
+// Delete remove um boleto se a conta estiver no escopo
+func (r *ScopedBilletRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return r.inner.Delete(ctx, id)
+}
+
+// FindNonReconciled encontra boletos não conciliados das contas do escopo
+func (r *ScopedBilletRepository) FindNonReconciled(ctx context.Context) ([]*model.Billet, error) {
+	billets, err := r.inner.FindNonReconciled(ctx)
+	return filterBillets(ctx, billets), err
+}
+
+// FindNonReconciledByPeriod encontra boletos não conciliados do período das contas do escopo
+func (r *ScopedBilletRepository) FindNonReconciledByPeriod(ctx context.Context, start, end time.Time) ([]*model.Billet, error) {
+	billets, err := r.inner.FindNonReconciledByPeriod(ctx, start, end)
+	return filterBillets(ctx, billets), err
+}
+
+// FindNonReconciledByFilter encontra boletos não conciliados do filtro das contas do escopo
+func (r *ScopedBilletRepository) FindNonReconciledByFilter(ctx context.Context, filter model.PendingFilter) ([]*model.Billet, error) {
+	billets, err := r.inner.FindNonReconciledByFilter(ctx, filter)
+	return filterBillets(ctx, billets), err
+}
+
+// StreamNonReconciled percorre os boletos não conciliados do filtro das contas do escopo
+func (r *ScopedBilletRepository) StreamNonReconciled(ctx context.Context, filter model.PendingFilter, fn func(billet *model.Billet) error) error {
+	scope := model.AccessScopeFromContext(ctx)
+	return r.inner.StreamNonReconciled(ctx, filter, func(billet *model.Billet) error {
+		if !scope.AllowsAccount(billet.BankAccount) {
+			return nil
+		}
+		return fn(billet)
+	})
+}
+
+// GetByContractID recupera os boletos de um contrato das contas do escopo
+func (r *ScopedBilletRepository) GetByContractID(ctx context.Context, contractID string) ([]*model.Billet, error) {
+	billets, err := r.inner.GetByContractID(ctx, contractID)
+	return filterBillets(ctx, billets), err
+}
+
+// GetContractStatistics calcula as estatísticas de um contrato cujos boletos estejam todos no escopo
+func (r *ScopedBilletRepository) GetContractStatistics(ctx context.Context, contractID string) (*model.ContractStatistics, error) {
+	scope := model.AccessScopeFromContext(ctx)
+	if scope.Restricted() {
+		billets, err := r.inner.GetByContractID(ctx, contractID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, billet := range billets {
+			if !scope.AllowsAccount(billet.BankAccount) {
+				return nil, errors.NewForbiddenError("contrato", contractID)
+			}
+		}
+	}
+
+	return r.inner.GetContractStatistics(ctx, contractID)
+}
+
+// GetAccountPerformances calcula os indicadores de conciliação das contas do escopo
+func (r *ScopedBilletRepository) GetAccountPerformances(ctx context.Context, filter model.AccountRankingFilter) ([]*model.AccountPerformance, error) {
+	performances, err := r.inner.GetAccountPerformances(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := model.AccessScopeFromContext(ctx)
+	filtered := performances[:0]
+	for _, performance := range performances {
+		if scope.AllowsAccount(performance.BankAccount) {
+			filtered = append(filtered, performance)
+		}
+	}
+	return filtered, nil
+}
+
+// ScopedPaymentRepository aplica o escopo de contas às operações de pagamentos
+type ScopedPaymentRepository struct {
+	inner domainRepo.PaymentRepository
+}
+
+// NewScopedPaymentRepository cria uma nova instância de ScopedPaymentRepository
+func NewScopedPaymentRepository(inner domainRepo.PaymentRepository) domainRepo.PaymentRepository {
+	return &ScopedPaymentRepository{inner: inner}
+}
+
+// Create persiste um novo pagamento se a conta estiver no escopo
+func (r *ScopedPaymentRepository) Create(ctx context.Context, payment *model.Payment) (*model.Payment, error) {
+	if !model.AccessScopeFromContext(ctx).AllowsAccount(payment.BankAccount) {
+		return nil, errors.NewForbiddenError("pagamento", payment.ID)
+	}
+	return r.inner.Create(ctx, payment)
+}
+
+// CreateMany persiste múltiplos pagamentos se todas as contas estiverem no escopo
+func (r *ScopedPaymentRepository) CreateMany(ctx context.Context, payments []*model.Payment) error {
+	scope := model.AccessScopeFromContext(ctx)
+	for _, payment := range payments {
+		if !scope.AllowsAccount(payment.BankAccount) {
+			return errors.NewForbiddenError("pagamento", payment.ID)
+		}
+	}
+	return r.inner.CreateMany(ctx, payments)
+}
+
+// CreateFromMessage registra a mensagem de fila e grava o pagamento dela se a conta estiver no escopo
+func (r *ScopedPaymentRepository) CreateFromMessage(ctx context.Context, message *model.ProcessedMessage, payment *model.Payment) (bool, error) {
+	if !model.AccessScopeFromContext(ctx).AllowsAccount(payment.BankAccount) {
+		return false, errors.NewForbiddenError("pagamento", payment.ID)
+	}
+	return r.inner.CreateFromMessage(ctx, message, payment)
+}
+
+// GetByID recupera um pagamento se a conta estiver no escopo
+func (r *ScopedPaymentRepository) GetByID(ctx context.Context, id string) (*model.Payment, error) {
+	payment, err := r.inner.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !model.AccessScopeFromContext(ctx).AllowsAccount(payment.BankAccount) {
+		return nil, errors.NewForbiddenError("pagamento", id)
+	}
+	return payment, nil
+}
+
+// GetAll recupera os pagamentos das contas do escopo
+func (r *ScopedPaymentRepository) GetAll(ctx context.Context) ([]*model.Payment, error) {
+	payments, err := r.inner.GetAll(ctx)
+	return filterPayments(ctx, payments), err
+}
+
+// List recupera os pagamentos do filtro das contas do escopo
+func (r *ScopedPaymentRepository) List(ctx context.Context, filter model.PaymentFilter) ([]*model.Payment, error) {
+	if filter.BankAccount != "" && !model.AccessScopeFromContext(ctx).AllowsAccount(filter.BankAccount) {
+		return []*model.Payment{}, nil
+	}
+
+	payments, err := r.inner.List(ctx, filter)
+	return filterPayments(ctx, payments), err
+}
+
+// GetByBankAccount recupera pagamentos por conta bancária, se a conta estiver no escopo
+func (r *ScopedPaymentRepository) GetByBankAccount(ctx context.Context, bankAccount string) ([]*model.Payment, error) {
+	if !model.AccessScopeFromContext(ctx).AllowsAccount(bankAccount) {
+		return []*model.Payment{}, nil
+	}
+	return r.inner.GetByBankAccount(ctx, bankAccount)
+}
+
+// GetByIDs recupera os pagamentos com os IDs informados das contas do escopo; os demais ficam como não encontrados
+func (r *ScopedPaymentRepository) GetByIDs(ctx context.Context, ids []string) ([]*model.Payment, error) {
+	payments, err := r.inner.GetByIDs(ctx, ids)
+	return filterPayments(ctx, payments), err
+}
+
+// GetByReferenceID recupera pagamentos por ID de referência das contas do escopo
+func (r *ScopedPaymentRepository) GetByReferenceID(ctx context.Context, referenceID string) ([]*model.Payment, error) {
+	payments, err := r.inner.GetByReferenceID(ctx, referenceID)
+	return filterPayments(ctx, payments), err
+}
+
+// GetByEntryType recupera pagamentos por tipo de lançamento das contas do escopo
+func (r *ScopedPaymentRepository) GetByEntryType(ctx context.Context, entryType model.EntryType) ([]*model.Payment, error) {
+	payments, err := r.inner.GetByEntryType(ctx, entryType)
+	return filterPayments(ctx, payments), err
+}
+
+// Update atualiza um pagamento se a conta atual e a nova estiverem no escopo
+func (r *ScopedPaymentRepository) Update(ctx context.Context, payment *model.Payment) (*model.Payment, error) {
+	if _, err := r.GetByID(ctx, payment.ID); err != nil {
+		return nil, err
+	}
+
+	if !model.AccessScopeFromContext(ctx).AllowsAccount(payment.BankAccount) {
+		return nil, errors.NewForbiddenError("pagamento", payment.ID)
+	}
+	return r.inner.Update(ctx, payment)
+}
+
+// Delete remove um pagamento se a conta estiver no escopo
+func (r *ScopedPaymentRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return r.inner.Delete(ctx, id)
+}
+
+// FindByBankAccountAndAmount encontra pagamentos por conta e valor, se a conta estiver no escopo
+func (r *ScopedPaymentRepository) FindByBankAccountAndAmount(ctx context.Context, bankAccount string, amount float64, tolerance float64) ([]*model.Payment, error) {
+	if !model.AccessScopeFromContext(ctx).AllowsAccount(bankAccount) {
+		return []*model.Payment{}, nil
+	}
+	return r.inner.FindByBankAccountAndAmount(ctx, bankAccount, amount, tolerance)
+}
+
+// FindNonReconciled encontra pagamentos não conciliados das contas do escopo
+func (r *ScopedPaymentRepository) FindNonReconciled(ctx context.Context) ([]*model.Payment, error) {
+	payments, err := r.inner.FindNonReconciled(ctx)
+	return filterPayments(ctx, payments), err
+}
+
+// FindNonReconciledByPeriod encontra pagamentos não conciliados do período das contas do escopo
+func (r *ScopedPaymentRepository) FindNonReconciledByPeriod(ctx context.Context, start, end time.Time) ([]*model.Payment, error) {
+	payments, err := r.inner.FindNonReconciledByPeriod(ctx, start, end)
+	return filterPayments(ctx, payments), err
+}
+
+// FindNonReconciledByFilter encontra pagamentos não conciliados do filtro das contas do escopo
+func (r *ScopedPaymentRepository) FindNonReconciledByFilter(ctx context.Context, filter model.PendingFilter) ([]*model.Payment, error) {
+	payments, err := r.inner.FindNonReconciledByFilter(ctx, filter)
+	return filterPayments(ctx, payments), err
+}
+
+// StreamNonReconciled percorre os pagamentos não conciliados do filtro das contas do escopo
+func (r *ScopedPaymentRepository) StreamNonReconciled(ctx context.Context, filter model.PendingFilter, fn func(payment *model.Payment) error) error {
+	scope := model.AccessScopeFromContext(ctx)
+	return r.inner.StreamNonReconciled(ctx, filter, func(payment *model.Payment) error {
+		if !scope.AllowsAccount(payment.BankAccount) {
+			return nil
+		}
+		return fn(payment)
+	})
+}
+
+// GetReconciledAmounts recupera o histórico de valores de uma conta, se a conta estiver no escopo
+func (r *ScopedPaymentRepository) GetReconciledAmounts(ctx context.Context, bankAccount string, limit int) ([]float64, error) {
+	if !model.AccessScopeFromContext(ctx).AllowsAccount(bankAccount) {
+		return []float64{}, nil
+	}
+	return r.inner.GetReconciledAmounts(ctx, bankAccount, limit)
+}
+
+// GetByReviewStatus recupera pagamentos pela situação de revisão das contas do escopo
+func (r *ScopedPaymentRepository) GetByReviewStatus(ctx context.Context, status model.PaymentReviewStatus) ([]*model.Payment, error) {
+	payments, err := r.inner.GetByReviewStatus(ctx, status)
+	return filterPayments(ctx, payments), err
+}
+
+// UpdateReviewStatus atualiza a revisão de um pagamento se a conta estiver no escopo
+func (r *ScopedPaymentRepository) UpdateReviewStatus(ctx context.Context, id string, status model.PaymentReviewStatus, reason *string) error {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return r.inner.UpdateReviewStatus(ctx, id, status, reason)
+}
+
+// ScopedReconciliationRepository aplica o escopo de contas às operações de conciliações
+type ScopedReconciliationRepository struct {
+	inner domainRepo.ReconciliationRepository
+}
+
+// NewScopedReconciliationRepository cria uma nova instância de ScopedReconciliationRepository
+func NewScopedReconciliationRepository(inner domainRepo.ReconciliationRepository) domainRepo.ReconciliationRepository {
+	return &ScopedReconciliationRepository{inner: inner}
+}
+
+// Create persiste uma nova conciliação se a conta estiver no escopo
+func (r *ScopedReconciliationRepository) Create(ctx context.Context, reconciliation *model.Reconciliation) error {
+	if !model.AccessScopeFromContext(ctx).AllowsAccount(reconciliation.BankAccount) {
+		return errors.NewForbiddenError("conciliação", reconciliation.ID)
+	}
+	return r.inner.Create(ctx, reconciliation)
+}
+
+// CreateMany persiste múltiplas conciliações se todas as contas estiverem no escopo
+func (r *ScopedReconciliationRepository) CreateMany(ctx context.Context, reconciliations []*model.Reconciliation) error {
+	scope := model.AccessScopeFromContext(ctx)
+	for _, reconciliation := range reconciliations {
+		if !scope.AllowsAccount(reconciliation.BankAccount) {
+			return errors.NewForbiddenError("conciliação", reconciliation.ID)
+		}
+	}
+	return r.inner.CreateMany(ctx, reconciliations)
+}
+
+// GetByID recupera uma conciliação se a conta estiver no escopo
+func (r *ScopedReconciliationRepository) GetByID(ctx context.Context, id string) (*model.Reconciliation, error) {
+	reconciliation, err := r.inner.GetByID(ctx, id)
+	if err != nil || reconciliation == nil {
+		return reconciliation, err
+	}
+
+	if !model.AccessScopeFromContext(ctx).AllowsAccount(reconciliation.BankAccount) {
+		return nil, errors.NewForbiddenError("conciliação", id)
+	}
+	return reconciliation, nil
+}
+
+// GetAll recupera as conciliações das contas do escopo
+func (r *ScopedReconciliationRepository) GetAll(ctx context.Context) ([]*model.Reconciliation, error) {
+	reconciliations, err := r.inner.GetAll(ctx)
+	return filterReconciliations(ctx, reconciliations), err
+}
+
+// GetByBilletID recupera conciliações por ID do boleto das contas do escopo
+func (r *ScopedReconciliationRepository) GetByBilletID(ctx context.Context, billetID string) ([]*model.Reconciliation, error) {
+	reconciliations, err := r.inner.GetByBilletID(ctx, billetID)
+	return filterReconciliations(ctx, reconciliations), err
+}
+
+// GetByFilter recupera as conciliações filtradas das contas do escopo
+func (r *ScopedReconciliationRepository) GetByFilter(ctx context.Context, filter model.ReconciliationFilter) ([]*model.Reconciliation, error) {
+	if filter.BankAccount != "" && !model.AccessScopeFromContext(ctx).AllowsAccount(filter.BankAccount) {
+		return []*model.Reconciliation{}, nil
+	}
+
+	reconciliations, err := r.inner.GetByFilter(ctx, filter)
+	return filterReconciliations(ctx, reconciliations), err
+}
+
+// GetByTransactionID recupera conciliações por ID da transação das contas do escopo
+func (r *ScopedReconciliationRepository) GetByTransactionID(ctx context.Context, transactionID string) ([]*model.Reconciliation, error) {
+	reconciliations, err := r.inner.GetByTransactionID(ctx, transactionID)
+	return filterReconciliations(ctx, reconciliations), err
+}
+
+// Update atualiza uma conciliação se a conta atual e a nova estiverem no escopo
+func (r *ScopedReconciliationRepository) Update(ctx context.Context, reconciliation *model.Reconciliation) error {
+	if _, err := r.GetByID(ctx, reconciliation.ID); err != nil {
+		return err
+	}
+
+	if !model.AccessScopeFromContext(ctx).AllowsAccount(reconciliation.BankAccount) {
+		return errors.NewForbiddenError("conciliação", reconciliation.ID)
+	}
+	return r.inner.Update(ctx, reconciliation)
+}
+
+// Delete remove uma conciliação se a conta estiver no escopo
+func (r *ScopedReconciliationRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return r.inner.Delete(ctx, id)
+}
+
+// Undo desfaz as conciliações, desde que todas pertençam às contas do escopo
+func (r *ScopedReconciliationRepository) Undo(ctx context.Context, undos []*model.ReconciliationUndo) error {
+	scope := model.AccessScopeFromContext(ctx)
+	for _, undo := range undos {
+		if !scope.AllowsAccount(undo.BankAccount) {
+			return errors.NewForbiddenError("conciliação", undo.ReconciliationID)
+		}
+	}
+	return r.inner.Undo(ctx, undos)
+}
+
+// Approve aplica as aprovações se todas as conciliações estiverem no escopo
+func (r *ScopedReconciliationRepository) Approve(ctx context.Context, approvals []*model.ReconciliationApproval) error {
+	scope := model.AccessScopeFromContext(ctx)
+	for _, approval := range approvals {
+		if !scope.AllowsAccount(approval.BankAccount) {
+			return errors.NewForbiddenError("conciliação", approval.ReconciliationID)
+		}
+	}
+	return r.inner.Approve(ctx, approvals)
+}
+
+// Reject remove as conciliações rejeitadas se todas estiverem no escopo
+func (r *ScopedReconciliationRepository) Reject(ctx context.Context, rejections []*model.ReconciliationApproval) error {
+	scope := model.AccessScopeFromContext(ctx)
+	for _, rejection := range rejections {
+		if !scope.AllowsAccount(rejection.BankAccount) {
+			return errors.NewForbiddenError("conciliação", rejection.ReconciliationID)
+		}
+	}
+	return r.inner.Reject(ctx, rejections)
+}
+
+// GetApprovals recupera as aprovações e rejeições das contas do escopo
+func (r *ScopedReconciliationRepository) GetApprovals(ctx context.Context, billetID string) ([]*model.ReconciliationApproval, error) {
+	approvals, err := r.inner.GetApprovals(ctx, billetID)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := model.AccessScopeFromContext(ctx)
+	filtered := make([]*model.ReconciliationApproval, 0, len(approvals))
+	for _, approval := range approvals {
+		if scope.AllowsAccount(approval.BankAccount) {
+			filtered = append(filtered, approval)
+		}
+	}
+	return filtered, nil
+}
+
+// GetReconciliationHistory recupera o histórico de conciliações das contas do escopo
+func (r *ScopedReconciliationRepository) GetReconciliationHistory(ctx context.Context, billetID string) ([]*model.Reconciliation, error) {
+	reconciliations, err := r.inner.GetReconciliationHistory(ctx, billetID)
+	return filterReconciliations(ctx, reconciliations), err
+}
+
+// GetReconciliationHistoryByTransactionID recupera o histórico de conciliações de um pagamento das contas do escopo
+func (r *ScopedReconciliationRepository) GetReconciliationHistoryByTransactionID(ctx context.Context, transactionID string) ([]*model.Reconciliation, error) {
+	reconciliations, err := r.inner.GetReconciliationHistoryByTransactionID(ctx, transactionID)
+	return filterReconciliations(ctx, reconciliations), err
+}
+
+// UpdateStatus aplica a mudança de status se a conciliação estiver no escopo
+func (r *ScopedReconciliationRepository) UpdateStatus(ctx context.Context, change *model.ReconciliationStatusChange) error {
+	if _, err := r.GetByID(ctx, change.ReconciliationID); err != nil {
+		return err
+	}
+	return r.inner.UpdateStatus(ctx, change)
+}
+
+// GetStatusChanges recupera o histórico de mudanças de status das contas do escopo
+func (r *ScopedReconciliationRepository) GetStatusChanges(ctx context.Context, billetID string) ([]*model.ReconciliationStatusChange, error) {
+	changes, err := r.inner.GetStatusChanges(ctx, billetID)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := model.AccessScopeFromContext(ctx)
+	filtered := make([]*model.ReconciliationStatusChange, 0, len(changes))
+	for _, change := range changes {
+		if scope.AllowsAccount(change.BankAccount) {
+			filtered = append(filtered, change)
+		}
+	}
+	return filtered, nil
+}
+
+// GetTimeToReconcileStatistics calcula os percentis de tempo até conciliação das contas do escopo
+func (r *ScopedReconciliationRepository) GetTimeToReconcileStatistics(ctx context.Context, filter model.TimeToReconcileFilter) ([]*model.TimeToReconcileStatistics, error) {
+	scope := model.AccessScopeFromContext(ctx)
+	if filter.BankAccount != "" && !scope.AllowsAccount(filter.BankAccount) {
+		return []*model.TimeToReconcileStatistics{}, nil
+	}
+
+	statistics, err := r.inner.GetTimeToReconcileStatistics(ctx, filter)
+	if err != nil || !scope.Restricted() {
+		return statistics, err
+	}
+
+	filtered := make([]*model.TimeToReconcileStatistics, 0, len(statistics))
+	for _, stats := range statistics {
+		if scope.AllowsAccount(stats.BankAccount) {
+			filtered = append(filtered, stats)
+		}
+	}
+	return filtered, nil
+}
+
+// GetDailyStatistics calcula os totais diários de conciliação das contas do escopo
+func (r *ScopedReconciliationRepository) GetDailyStatistics(ctx context.Context, filter model.DailyStatisticsFilter) ([]*model.DailyReconciliationStatistics, error) {
+	scope := model.AccessScopeFromContext(ctx)
+	if filter.BankAccount != "" && !scope.AllowsAccount(filter.BankAccount) {
+		return []*model.DailyReconciliationStatistics{}, nil
+	}
+
+	statistics, err := r.inner.GetDailyStatistics(ctx, filter)
+	if err != nil || !scope.Restricted() {
+		return statistics, err
+	}
+
+	filtered := make([]*model.DailyReconciliationStatistics, 0, len(statistics))
+	for _, stats := range statistics {
+		if scope.AllowsAccount(stats.BankAccount) {
+			filtered = append(filtered, stats)
+		}
+	}
+
+	return filtered, nil
+}
+
+// GetEngineVersionStatistics calcula os totais por dia e versão do motor das contas do escopo
+func (r *ScopedReconciliationRepository) GetEngineVersionStatistics(ctx context.Context, filter model.EngineVersionStatisticsFilter) ([]*model.EngineVersionStatistics, error) {
+	scope := model.AccessScopeFromContext(ctx)
+	if filter.BankAccount != "" && !scope.AllowsAccount(filter.BankAccount) {
+		return []*model.EngineVersionStatistics{}, nil
+	}
+
+	statistics, err := r.inner.GetEngineVersionStatistics(ctx, filter)
+	if err != nil || !scope.Restricted() {
+		return statistics, err
+	}
+
+	filtered := make([]*model.EngineVersionStatistics, 0, len(statistics))
+	for _, stats := range statistics {
+		if scope.AllowsAccount(stats.BankAccount) {
+			filtered = append(filtered, stats)
+		}
+	}
+
+	return filtered, nil
+}
+
+// filterBillets mantém apenas os boletos das contas do escopo do contexto
+func filterBillets(ctx context.Context, billets []*model.Billet) []*model.Billet {
+	scope := model.AccessScopeFromContext(ctx)
+	if !scope.Restricted() {
+		return billets
+	}
+
+	filtered := make([]*model.Billet, 0, len(billets))
+	for _, billet := range billets {
+		if scope.AllowsAccount(billet.BankAccount) {
+			filtered = append(filtered, billet)
+		}
+	}
+	return filtered
+}
+
+// filterPayments mantém apenas os pagamentos das contas do escopo do contexto
+func filterPayments(ctx context.Context, payments []*model.Payment) []*model.Payment {
+	scope := model.AccessScopeFromContext(ctx)
+	if !scope.Restricted() {
+		return payments
+	}
+
+	filtered := make([]*model.Payment, 0, len(payments))
+	for _, payment := range payments {
+		if scope.AllowsAccount(payment.BankAccount) {
+			filtered = append(filtered, payment)
+		}
+	}
+	return filtered
+}
+
+// filterReconciliations mantém apenas as conciliações das contas do escopo do contexto
+func filterReconciliations(ctx context.Context, reconciliations []*model.Reconciliation) []*model.Reconciliation {
+	scope := model.AccessScopeFromContext(ctx)
+	if !scope.Restricted() {
+		return reconciliations
+	}
+
+	filtered := make([]*model.Reconciliation, 0, len(reconciliations))
+	for _, reconciliation := range reconciliations {
+		if scope.AllowsAccount(reconciliation.BankAccount) {
+			filtered = append(filtered, reconciliation)
+		}
+	}
+	return filtered
+}
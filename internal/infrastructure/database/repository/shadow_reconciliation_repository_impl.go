@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// Garantir que ShadowReconciliationRepositoryImpl implementa a interface ShadowReconciliationRepository
+var _ domainRepo.ShadowReconciliationRepository = (*ShadowReconciliationRepositoryImpl)(nil)
+
+// ShadowReconciliationRepositoryImpl implementa a interface de repositório para os resultados do motor shadow
+type ShadowReconciliationRepositoryImpl struct {
+	db database.DB
+}
+
+// NewShadowReconciliationRepository cria uma nova instância do repositório de resultados do motor shadow
+func NewShadowReconciliationRepository(db database.DB) domainRepo.ShadowReconciliationRepository {
+	return &ShadowReconciliationRepositoryImpl{
+		db: db,
+	}
+}
+
+// shadowReconciliationColumns lista as colunas lidas por scanShadowReconciliation
+const shadowReconciliationColumns = `id, run_id, billet_id, bank_account, engine_version,
+	primary_transaction_id, primary_strategy, primary_status,
+	shadow_transaction_id, shadow_strategy, shadow_status, shadow_amount_diff,
+	divergence, created_at`
+
+// CreateMany persiste as comparações entre o resultado de produção e o do motor shadow
+func (r *ShadowReconciliationRepositoryImpl) CreateMany(ctx context.Context, comparisons []*model.ShadowReconciliation) error {
+	if len(comparisons) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO bank_reconciliation.shadow_reconciliations (`+shadowReconciliationColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`)
+	if err != nil {
+		return fmt.Errorf("erro ao preparar statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, comparison := range comparisons {
+		_, err := stmt.ExecContext(ctx,
+			comparison.ID,
+			comparison.RunID,
+			comparison.BilletID,
+			comparison.BankAccount,
+			comparison.EngineVersion,
+			comparison.PrimaryTransactionID,
+			string(comparison.PrimaryStrategy),
+			string(comparison.PrimaryStatus),
+			comparison.ShadowTransactionID,
+			string(comparison.ShadowStrategy),
+			string(comparison.ShadowStatus),
+			comparison.ShadowAmountDiff,
+			string(comparison.Divergence),
+			comparison.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao inserir resultado shadow do boleto %s: %w", comparison.BilletID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
+
+// GetByRunID recupera as comparações gravadas em uma execução, ordenadas pelo boleto
+func (r *ShadowReconciliationRepositoryImpl) GetByRunID(ctx context.Context, runID string) ([]*model.ShadowReconciliation, error) {
+	query := `SELECT ` + shadowReconciliationColumns + `
+		FROM bank_reconciliation.shadow_reconciliations
+		WHERE run_id = $1
+		ORDER BY billet_id, engine_version
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, runID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar resultados shadow: %w", err)
+	}
+	defer rows.Close()
+
+	comparisons := []*model.ShadowReconciliation{}
+	for rows.Next() {
+		comparison, err := scanShadowReconciliation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler resultado shadow: %w", err)
+		}
+		comparisons = append(comparisons, comparison)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return comparisons, nil
+}
+
+// scanShadowReconciliation lê uma comparação com as colunas de shadowReconciliationColumns
+func scanShadowReconciliation(scanner rowScanner) (*model.ShadowReconciliation, error) {
+	var comparison model.ShadowReconciliation
+	var primaryTransactionID, shadowTransactionID sql.NullString
+	var primaryStrategy, primaryStatus, shadowStrategy, shadowStatus, divergence string
+
+	err := scanner.Scan(
+		&comparison.ID,
+		&comparison.RunID,
+		&comparison.BilletID,
+		&comparison.BankAccount,
+		&comparison.EngineVersion,
+		&primaryTransactionID,
+		&primaryStrategy,
+		&primaryStatus,
+		&shadowTransactionID,
+		&shadowStrategy,
+		&shadowStatus,
+		&comparison.ShadowAmountDiff,
+		&divergence,
+		&comparison.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if primaryTransactionID.Valid {
+		comparison.PrimaryTransactionID = &primaryTransactionID.String
+	}
+	if shadowTransactionID.Valid {
+		comparison.ShadowTransactionID = &shadowTransactionID.String
+	}
+	comparison.PrimaryStrategy = model.ConciliationStrategy(primaryStrategy)
+	comparison.PrimaryStatus = model.ConciliationStatus(primaryStatus)
+	comparison.ShadowStrategy = model.ConciliationStrategy(shadowStrategy)
+	comparison.ShadowStatus = model.ConciliationStatus(shadowStatus)
+	comparison.Divergence = model.ShadowDivergence(divergence)
+
+	return &comparison, nil
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+	pkgErrors "conciliacao-bancaria/pkg/errors"
+)
+
+// subscriptionColumns define as colunas lidas em todas as consultas de assinaturas
+const subscriptionColumns = "id, name, callback_url, secret, event_types, bank_accounts, min_amount, active, created_at, updated_at"
+
+// Garantir que SubscriptionRepositoryImpl implementa a interface SubscriptionRepository
+var _ domainRepo.SubscriptionRepository = (*SubscriptionRepositoryImpl)(nil)
+
+// SubscriptionRepositoryImpl implementa a interface de repositório para assinaturas de eventos
+type SubscriptionRepositoryImpl struct {
+	db database.DB
+}
+
+// NewSubscriptionRepository cria uma nova instância do repositório de assinaturas
+func NewSubscriptionRepository(db database.DB) domainRepo.SubscriptionRepository {
+	return &SubscriptionRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create persiste uma nova assinatura no banco de dados
+func (r *SubscriptionRepositoryImpl) Create(ctx context.Context, subscription *model.Subscription) error {
+	query := `
+		INSERT INTO bank_reconciliation.event_subscriptions (
+			` + subscriptionColumns + `
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	eventTypes := make([]string, 0, len(subscription.EventTypes))
+	for _, eventType := range subscription.EventTypes {
+		eventTypes = append(eventTypes, string(eventType))
+	}
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		subscription.ID,
+		subscription.Name,
+		subscription.CallbackURL,
+		subscription.Secret,
+		pq.Array(eventTypes),
+		pq.Array(subscription.BankAccounts),
+		subscription.MinAmount,
+		subscription.Active,
+		subscription.CreatedAt,
+		subscription.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("erro ao criar assinatura: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID recupera uma assinatura pelo seu ID
+func (r *SubscriptionRepositoryImpl) GetByID(ctx context.Context, id string) (*model.Subscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM bank_reconciliation.event_subscriptions
+		WHERE id = $1
+	`
+
+	subscription, err := scanSubscription(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgErrors.NewNotFoundError("assinatura", id)
+		}
+		return nil, fmt.Errorf("erro ao buscar assinatura: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// GetAll recupera todas as assinaturas
+func (r *SubscriptionRepositoryImpl) GetAll(ctx context.Context) ([]*model.Subscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM bank_reconciliation.event_subscriptions
+		ORDER BY created_at
+	`
+
+	return r.query(ctx, query)
+}
+
+// FindActive recupera as assinaturas ativas
+func (r *SubscriptionRepositoryImpl) FindActive(ctx context.Context) ([]*model.Subscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM bank_reconciliation.event_subscriptions
+		WHERE active = TRUE
+		ORDER BY created_at
+	`
+
+	return r.query(ctx, query)
+}
+
+// Delete remove uma assinatura pelo ID
+func (r *SubscriptionRepositoryImpl) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM bank_reconciliation.event_subscriptions WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("erro ao excluir assinatura: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return pkgErrors.NewNotFoundError("assinatura", id)
+	}
+
+	return nil
+}
+
+// query executa uma consulta de assinaturas e lê todas as linhas
+func (r *SubscriptionRepositoryImpl) query(ctx context.Context, query string, args ...interface{}) ([]*model.Subscription, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar assinaturas: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []*model.Subscription{}
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler assinatura: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// scanSubscription lê uma assinatura a partir de uma linha de resultado
+func scanSubscription(scanner rowScanner) (*model.Subscription, error) {
+	var subscription model.Subscription
+	var eventTypes, bankAccounts []string
+	var minAmount sql.NullFloat64
+
+	if err := scanner.Scan(
+		&subscription.ID,
+		&subscription.Name,
+		&subscription.CallbackURL,
+		&subscription.Secret,
+		pq.Array(&eventTypes),
+		pq.Array(&bankAccounts),
+		&minAmount,
+		&subscription.Active,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	for _, eventType := range eventTypes {
+		subscription.EventTypes = append(subscription.EventTypes, model.EventType(eventType))
+	}
+	subscription.BankAccounts = bankAccounts
+
+	if minAmount.Valid {
+		subscription.MinAmount = &minAmount.Float64
+	}
+
+	return &subscription, nil
+}
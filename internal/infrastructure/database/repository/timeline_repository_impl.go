@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// Garantir que TimelineRepositoryImpl implementa a interface TimelineRepository
+var _ domainRepo.TimelineRepository = (*TimelineRepositoryImpl)(nil)
+
+// TimelineRepositoryImpl implementa a interface de repositório para as entradas da linha do tempo
+type TimelineRepositoryImpl struct {
+	db database.DB
+}
+
+// NewTimelineRepository cria uma nova instância do repositório da linha do tempo
+func NewTimelineRepository(db database.DB) domainRepo.TimelineRepository {
+	return &TimelineRepositoryImpl{
+		db: db,
+	}
+}
+
+// insertTimelineEntry grava uma entrada da linha do tempo
+const insertTimelineEntry = `
+	INSERT INTO bank_reconciliation.timeline_entries (
+		id, entity_type, entity_id, kind, description, actor, reference, occurred_at
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+// Create registra uma entrada na linha do tempo
+func (r *TimelineRepositoryImpl) Create(ctx context.Context, entry *model.TimelineEntry) error {
+	if _, err := r.db.ExecContext(ctx, insertTimelineEntry, timelineEntryArgs(entry)...); err != nil {
+		return fmt.Errorf("erro ao registrar entrada da linha do tempo: %w", err)
+	}
+
+	return nil
+}
+
+// CreateMany registra várias entradas em uma única transação
+func (r *TimelineRepositoryImpl) CreateMany(ctx context.Context, entries []*model.TimelineEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, insertTimelineEntry)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("erro ao preparar statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		if _, err := stmt.ExecContext(ctx, timelineEntryArgs(entry)...); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("erro ao registrar entrada da linha do tempo: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao finalizar transação: %w", err)
+	}
+
+	return nil
+}
+
+// GetByEntity recupera as entradas gravadas de um boleto ou pagamento, da mais antiga para a mais recente
+func (r *TimelineRepositoryImpl) GetByEntity(ctx context.Context, entityType model.TimelineEntityType, entityID string) ([]*model.TimelineEntry, error) {
+	query := `
+		SELECT id, entity_type, entity_id, kind, description, actor, reference, occurred_at
+		FROM bank_reconciliation.timeline_entries
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY occurred_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, string(entityType), entityID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar linha do tempo: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*model.TimelineEntry{}
+	for rows.Next() {
+		var entry model.TimelineEntry
+		var entryType, kind string
+
+		err := rows.Scan(
+			&entry.ID,
+			&entryType,
+			&entry.EntityID,
+			&kind,
+			&entry.Description,
+			&entry.Actor,
+			&entry.Reference,
+			&entry.OccurredAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler entrada da linha do tempo: %w", err)
+		}
+
+		entry.EntityType = model.TimelineEntityType(entryType)
+		entry.Kind = model.TimelineEntryKind(kind)
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return entries, nil
+}
+
+// timelineEntryArgs retorna os argumentos de insertTimelineEntry
+func timelineEntryArgs(entry *model.TimelineEntry) []interface{} {
+	return []interface{}{
+		entry.ID,
+		string(entry.EntityType),
+		entry.EntityID,
+		string(entry.Kind),
+		entry.Description,
+		entry.Actor,
+		entry.Reference,
+		entry.OccurredAt,
+	}
+}
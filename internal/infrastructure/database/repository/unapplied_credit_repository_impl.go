@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// Garantir que UnappliedCreditRepositoryImpl implementa a interface UnappliedCreditRepository
+var _ domainRepo.UnappliedCreditRepository = (*UnappliedCreditRepositoryImpl)(nil)
+
+// UnappliedCreditRepositoryImpl implementa a interface de repositório para os créditos não aplicados
+type UnappliedCreditRepositoryImpl struct {
+	db database.DB
+}
+
+// NewUnappliedCreditRepository cria uma nova instância do repositório de créditos não aplicados
+func NewUnappliedCreditRepository(db database.DB) domainRepo.UnappliedCreditRepository {
+	return &UnappliedCreditRepositoryImpl{
+		db: db,
+	}
+}
+
+// unappliedCreditColumns lista as colunas lidas por scanUnappliedCredit
+const unappliedCreditColumns = `id, payer_id, bank_account, origin_transaction_id, amount, balance, created_at, updated_at`
+
+// debitCreditQuery abate o saldo do crédito apenas quando ele cobre o valor
+const debitCreditQuery = `
+	UPDATE bank_reconciliation.unapplied_credits
+	SET balance = balance - $2, updated_at = $3
+	WHERE id = $1 AND balance >= $2
+`
+
+// insertCreditApplicationQuery registra uma aplicação do crédito
+const insertCreditApplicationQuery = `
+	INSERT INTO bank_reconciliation.unapplied_credit_applications (id, credit_id, billet_id, amount, applied_by, applied_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+// CreateMany persiste os créditos gerados por uma execução
+func (r *UnappliedCreditRepositoryImpl) CreateMany(ctx context.Context, credits []*model.UnappliedCredit) error {
+	if len(credits) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO bank_reconciliation.unapplied_credits (` + unappliedCreditColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	for _, credit := range credits {
+		_, err := r.db.ExecContext(ctx, query,
+			credit.ID,
+			credit.PayerID,
+			credit.BankAccount,
+			credit.OriginTransactionID,
+			credit.Amount,
+			credit.Balance,
+			credit.CreatedAt,
+			credit.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao inserir crédito não aplicado: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetByID recupera um crédito, ou nil quando não existe
+func (r *UnappliedCreditRepositoryImpl) GetByID(ctx context.Context, id string) (*model.UnappliedCredit, error) {
+	query := `SELECT ` + unappliedCreditColumns + `
+		FROM bank_reconciliation.unapplied_credits
+		WHERE id = $1
+	`
+
+	credit, err := scanUnappliedCredit(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar crédito não aplicado: %w", err)
+	}
+
+	return credit, nil
+}
+
+// List recupera os créditos que atendem ao filtro, dos mais recentes para os mais antigos
+func (r *UnappliedCreditRepositoryImpl) List(ctx context.Context, filter model.UnappliedCreditFilter) ([]*model.UnappliedCredit, error) {
+	q := database.NewQuery(`SELECT ` + unappliedCreditColumns + `
+		FROM bank_reconciliation.unapplied_credits`)
+
+	if filter.PayerID != "" {
+		q.Where("payer_id = ?", filter.PayerID)
+	}
+	if filter.BankAccount != "" {
+		q.Where("bank_account = ?", filter.BankAccount)
+	}
+	if filter.OnlyAvailable {
+		q.Where("balance > 0")
+	}
+
+	query, args := q.OrderBy("created_at DESC").Build()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar créditos não aplicados: %w", err)
+	}
+	defer rows.Close()
+
+	credits := []*model.UnappliedCredit{}
+	for rows.Next() {
+		credit, err := scanUnappliedCredit(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler crédito não aplicado: %w", err)
+		}
+		credits = append(credits, credit)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return credits, nil
+}
+
+// Debit abate o valor do saldo de forma atômica: a atualização só ocorre se houver saldo suficiente,
+// evitando que duas aplicações simultâneas consumam o mesmo saldo
+func (r *UnappliedCreditRepositoryImpl) Debit(ctx context.Context, id string, amount float64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, debitCreditQuery, id, amount, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("erro ao abater saldo do crédito: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// CreateApplication registra uma aplicação do saldo do crédito no histórico
+func (r *UnappliedCreditRepositoryImpl) CreateApplication(ctx context.Context, application *model.CreditApplication) error {
+	_, err := r.db.ExecContext(ctx, insertCreditApplicationQuery, creditApplicationArgs(application)...)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar aplicação do crédito: %w", err)
+	}
+
+	return nil
+}
+
+// Apply abate o valor da aplicação do saldo do crédito, persiste a conciliação do boleto quitado e
+// registra a aplicação no histórico, na mesma transação. Retorna false, sem gravar nada, quando o
+// saldo não cobre o valor
+func (r *UnappliedCreditRepositoryImpl) Apply(ctx context.Context, reconciliation *model.Reconciliation, application *model.CreditApplication) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, debitCreditQuery, application.CreditID, application.Amount, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("erro ao abater saldo do crédito: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, insertReconciliationQuery, reconciliationArgs(reconciliation)...); err != nil {
+		return false, fmt.Errorf("erro ao criar conciliação: %w", err)
+	}
+
+	if err := linkReconciliation(ctx, tx, reconciliation); err != nil {
+		return false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, insertCreditApplicationQuery, creditApplicationArgs(application)...); err != nil {
+		return false, fmt.Errorf("erro ao registrar aplicação do crédito: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return true, nil
+}
+
+// creditApplicationArgs monta os argumentos de insertCreditApplicationQuery
+func creditApplicationArgs(application *model.CreditApplication) []interface{} {
+	return []interface{}{
+		application.ID,
+		application.CreditID,
+		application.BilletID,
+		application.Amount,
+		application.AppliedBy,
+		application.AppliedAt,
+	}
+}
+
+// GetApplications recupera o histórico de aplicações de um crédito, em ordem cronológica
+func (r *UnappliedCreditRepositoryImpl) GetApplications(ctx context.Context, creditID string) ([]model.CreditApplication, error) {
+	query := `
+		SELECT id, credit_id, billet_id, amount, applied_by, applied_at
+		FROM bank_reconciliation.unapplied_credit_applications
+		WHERE credit_id = $1
+		ORDER BY applied_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, creditID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar aplicações do crédito: %w", err)
+	}
+	defer rows.Close()
+
+	applications := []model.CreditApplication{}
+	for rows.Next() {
+		var application model.CreditApplication
+		err := rows.Scan(
+			&application.ID,
+			&application.CreditID,
+			&application.BilletID,
+			&application.Amount,
+			&application.AppliedBy,
+			&application.AppliedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler aplicação do crédito: %w", err)
+		}
+		applications = append(applications, application)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao processar resultados: %w", err)
+	}
+
+	return applications, nil
+}
+
+// scanUnappliedCredit lê um crédito de uma linha com as colunas de unappliedCreditColumns
+func scanUnappliedCredit(scanner rowScanner) (*model.UnappliedCredit, error) {
+	var credit model.UnappliedCredit
+
+	err := scanner.Scan(
+		&credit.ID,
+		&credit.PayerID,
+		&credit.BankAccount,
+		&credit.OriginTransactionID,
+		&credit.Amount,
+		&credit.Balance,
+		&credit.CreatedAt,
+		&credit.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &credit, nil
+}
@@ -0,0 +1,338 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// DefaultSchema define o schema usado pelas consultas dos repositórios
+const DefaultSchema = "bank_reconciliation"
+
+// DefaultShardName identifica o shard padrão, usado pelos tenants sem shard próprio
+const DefaultShardName = "default"
+
+// SandboxSchemaSuffix compõe o schema sandbox de cada shard, onde ficam os dados das requisições do
+// ambiente sandbox (ex.: bank_reconciliation_sandbox)
+const SandboxSchemaSuffix = "_sandbox"
+
+// schemaNamePattern restringe os nomes de schema aceitos, que são interpolados nas consultas
+var schemaNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// DB define as operações de banco de dados usadas pelos repositórios
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error)
+}
+
+// Tx define as operações de transação usadas pelos repositórios
+type Tx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	Commit() error
+	Rollback() error
+}
+
+// ShardConfig representa o destino dos dados de um tenant: um database próprio (DSN), um schema
+// próprio no banco padrão, ou ambos
+type ShardConfig struct {
+	DSN    string `json:"dsn,omitempty"`
+	Schema string `json:"schema,omitempty"`
+}
+
+// Shard representa o banco de dados lógico de um ou mais tenants
+type Shard struct {
+	Name   string
+	Schema string
+	db     *sql.DB
+	dsn    string
+
+	// sandbox é o shard dos dados do ambiente sandbox dos tenants deste shard, no mesmo database
+	sandbox *Shard
+}
+
+// ExecContext executa um comando no shard
+func (s *Shard) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return s.db.ExecContext(ctx, s.rewrite(query), args...)
+}
+
+// QueryContext executa uma consulta no shard
+func (s *Shard) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return s.db.QueryContext(ctx, s.rewrite(query), args...)
+}
+
+// QueryRowContext executa uma consulta de uma única linha no shard
+func (s *Shard) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return s.db.QueryRowContext(ctx, s.rewrite(query), args...)
+}
+
+// BeginTx inicia uma transação no shard
+func (s *Shard) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	tx, err := s.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &shardTx{Tx: tx, shard: s}, nil
+}
+
+// rewrite substitui o schema padrão das consultas pelo schema do shard. As tabelas não qualificadas
+// são resolvidas pelo search_path definido na conexão do shard
+func (s *Shard) rewrite(query string) string {
+	if s.Schema == DefaultSchema {
+		return query
+	}
+	return strings.ReplaceAll(query, DefaultSchema+".", s.Schema+".")
+}
+
+// shardTx aplica a substituição de schema às consultas de uma transação
+type shardTx struct {
+	*sql.Tx
+	shard *Shard
+}
+
+// ExecContext executa um comando na transação
+func (t *shardTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return t.Tx.ExecContext(ctx, t.shard.rewrite(query), args...)
+}
+
+// QueryContext executa uma consulta na transação
+func (t *shardTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return t.Tx.QueryContext(ctx, t.shard.rewrite(query), args...)
+}
+
+// PrepareContext prepara um comando na transação
+func (t *shardTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return t.Tx.PrepareContext(ctx, t.shard.rewrite(query))
+}
+
+// ShardRouter roteia as operações dos repositórios ao shard do tenant do contexto. Tenants sem
+// shard configurado, e operações sem tenant, usam o shard padrão. No ambiente sandbox, as operações
+// usam o schema sandbox do shard do tenant
+type ShardRouter struct {
+	defaultShard *Shard
+	tenants      map[string]*Shard
+	shards       []*Shard
+}
+
+// Garantir que ShardRouter e Shard implementam a interface DB
+var (
+	_ DB = (*ShardRouter)(nil)
+	_ DB = (*Shard)(nil)
+)
+
+// NewShardRouter cria um roteador com apenas o shard padrão. Sem EnableSandbox, as operações do
+// ambiente sandbox usam os shards de produção
+func NewShardRouter(conn *Connection) *ShardRouter {
+	defaultShard := &Shard{Name: DefaultShardName, Schema: DefaultSchema, db: conn.DB, dsn: conn.dsn}
+
+	return &ShardRouter{
+		defaultShard: defaultShard,
+		tenants:      make(map[string]*Shard),
+		shards:       []*Shard{defaultShard},
+	}
+}
+
+// NewShardRouterFromEnv cria o roteador com os shards da variável DB_SHARDS, um objeto JSON que
+// associa cada tenant ao seu destino, ex.: {"acme": {"schema": "tenant_acme"}, "globex": {"dsn": "host=..."}}.
+// Tenants com o mesmo destino compartilham o pool de conexões. Cada shard ganha o seu schema sandbox
+func NewShardRouterFromEnv(conn *Connection) (*ShardRouter, error) {
+	router := NewShardRouter(conn)
+
+	if value := os.Getenv("DB_SHARDS"); value != "" {
+		var configs map[string]ShardConfig
+		if err := json.Unmarshal([]byte(value), &configs); err != nil {
+			return nil, fmt.Errorf("DB_SHARDS inválido: %w", err)
+		}
+
+		if err := router.routeTenants(conn, configs); err != nil {
+			router.Close()
+			return nil, err
+		}
+	}
+
+	if err := router.EnableSandbox(); err != nil {
+		router.Close()
+		return nil, err
+	}
+
+	return router, nil
+}
+
+// routeTenants associa cada tenant ao shard do destino configurado
+func (r *ShardRouter) routeTenants(conn *Connection, configs map[string]ShardConfig) error {
+	tenants := make([]string, 0, len(configs))
+	for tenant := range configs {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+
+	shardsByTarget := make(map[string]*Shard)
+	for _, tenant := range tenants {
+		shard, err := r.openShard(conn, configs[tenant], shardsByTarget)
+		if err != nil {
+			return fmt.Errorf("shard do tenant %s: %w", tenant, err)
+		}
+		r.tenants[tenant] = shard
+		log.Printf("tenant %s roteado ao shard %s", tenant, shard.Name)
+	}
+
+	return nil
+}
+
+// EnableSandbox abre o shard sandbox de cada shard, no mesmo database e com o schema acrescido de
+// SandboxSchemaSuffix. Os shards sandbox entram em Shards(), e assim são migrados e mantidos como os demais
+func (r *ShardRouter) EnableSandbox() error {
+	production := make([]*Shard, len(r.shards))
+	copy(production, r.shards)
+
+	for _, shard := range production {
+		if shard.sandbox != nil {
+			continue
+		}
+
+		schema := shard.Schema + SandboxSchemaSuffix
+		if !schemaNamePattern.MatchString(schema) {
+			return fmt.Errorf("shard %s: nome de schema sandbox inválido: %q", shard.Name, schema)
+		}
+
+		dsn, err := withSearchPath(shard.dsn, schema)
+		if err != nil {
+			return fmt.Errorf("shard %s: %w", shard.Name, err)
+		}
+
+		db, err := open(dsn)
+		if err != nil {
+			return fmt.Errorf("sandbox do shard %s: %w", shard.Name, err)
+		}
+
+		shard.sandbox = &Shard{Name: shard.Name + SandboxSchemaSuffix, Schema: schema, db: db, dsn: shard.dsn}
+		r.shards = append(r.shards, shard.sandbox)
+	}
+
+	return nil
+}
+
+// openShard abre (ou reaproveita) o pool de conexões do destino configurado
+func (r *ShardRouter) openShard(conn *Connection, config ShardConfig, shardsByTarget map[string]*Shard) (*Shard, error) {
+	schema := config.Schema
+	if schema == "" {
+		schema = DefaultSchema
+	}
+	if !schemaNamePattern.MatchString(schema) {
+		return nil, fmt.Errorf("nome de schema inválido: %q", schema)
+	}
+
+	dsn := config.DSN
+	if dsn == "" {
+		if schema == DefaultSchema {
+			return r.defaultShard, nil
+		}
+		dsn = conn.dsn
+	}
+
+	target := dsn + "|" + schema
+	if shard, ok := shardsByTarget[target]; ok {
+		return shard, nil
+	}
+
+	// O search_path resolve as tabelas referenciadas sem schema no schema do shard
+	connectionString, err := withSearchPath(dsn, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := open(connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	shard := &Shard{Name: fmt.Sprintf("shard-%d", len(r.shards)), Schema: schema, db: db, dsn: dsn}
+	shardsByTarget[target] = shard
+	r.shards = append(r.shards, shard)
+
+	return shard, nil
+}
+
+// withSearchPath acrescenta o search_path à string de conexão, convertendo antes as strings no formato
+// de URL (postgres://...) para o formato chave=valor
+func withSearchPath(dsn, schema string) (string, error) {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		converted, err := pq.ParseURL(dsn)
+		if err != nil {
+			return "", fmt.Errorf("string de conexão inválida: %w", err)
+		}
+		dsn = converted
+	}
+	return dsn + " search_path=" + schema, nil
+}
+
+// Default retorna o shard padrão, usado pelos dados compartilhados entre tenants
+func (r *ShardRouter) Default() DB {
+	return r.defaultShard
+}
+
+// Shards lista todos os shards, começando pelo padrão e incluindo os shards sandbox
+func (r *ShardRouter) Shards() []*Shard {
+	return r.shards
+}
+
+// Close fecha os pools de conexões dos shards; o pool padrão pertence à Connection
+func (r *ShardRouter) Close() error {
+	var firstErr error
+	for _, shard := range r.shards {
+		if shard == r.defaultShard {
+			continue
+		}
+		if err := shard.db.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// resolve retorna o shard do tenant do contexto, ou o shard sandbox dele quando a operação pertence
+// ao ambiente sandbox
+func (r *ShardRouter) resolve(ctx context.Context) *Shard {
+	shard, ok := r.tenants[model.TenantFromContext(ctx)]
+	if !ok {
+		shard = r.defaultShard
+	}
+
+	if model.IsSandbox(ctx) && shard.sandbox != nil {
+		return shard.sandbox
+	}
+	return shard
+}
+
+// ExecContext executa um comando no shard do tenant do contexto
+func (r *ShardRouter) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.resolve(ctx).ExecContext(ctx, query, args...)
+}
+
+// QueryContext executa uma consulta no shard do tenant do contexto
+func (r *ShardRouter) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.resolve(ctx).QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext executa uma consulta de uma única linha no shard do tenant do contexto
+func (r *ShardRouter) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return r.resolve(ctx).QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx inicia uma transação no shard do tenant do contexto
+func (r *ShardRouter) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	return r.resolve(ctx).BeginTx(ctx, opts)
+}
@@ -0,0 +1,158 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// TestShardRouterResolve garante que cada operação vai ao shard do tenant do contexto, que tenants sem
+// shard próprio usam o padrão e que o ambiente sandbox usa o schema sandbox do shard do tenant
+func TestShardRouterResolve(t *testing.T) {
+	defaultShard := &Shard{Name: DefaultShardName, Schema: DefaultSchema}
+	defaultShard.sandbox = &Shard{Name: DefaultShardName + SandboxSchemaSuffix, Schema: DefaultSchema + SandboxSchemaSuffix}
+
+	tenantShard := &Shard{Name: "shard-1", Schema: "tenant_acme"}
+	tenantShard.sandbox = &Shard{Name: "shard-1" + SandboxSchemaSuffix, Schema: "tenant_acme" + SandboxSchemaSuffix}
+
+	// Shard sem sandbox habilitado, como no roteador criado sem EnableSandbox
+	productionOnly := &Shard{Name: "shard-2", Schema: "tenant_initech"}
+
+	router := &ShardRouter{
+		defaultShard: defaultShard,
+		tenants: map[string]*Shard{
+			"acme":    tenantShard,
+			"globex":  tenantShard,
+			"initech": productionOnly,
+		},
+	}
+
+	tests := []struct {
+		name        string
+		tenant      string
+		environment model.Environment
+		want        *Shard
+	}{
+		{"sem tenant", "", "", defaultShard},
+		{"tenant com shard próprio", "acme", "", tenantShard},
+		{"tenants com o mesmo destino compartilham o shard", "globex", "", tenantShard},
+		{"tenant sem shard próprio", "umbrella", "", defaultShard},
+		{"produção explícita", "acme", model.EnvironmentProduction, tenantShard},
+		{"sandbox do tenant com shard próprio", "acme", model.EnvironmentSandbox, tenantShard.sandbox},
+		{"sandbox do tenant sem shard próprio", "umbrella", model.EnvironmentSandbox, defaultShard.sandbox},
+		{"sandbox sem schema sandbox habilitado", "initech", model.EnvironmentSandbox, productionOnly},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.tenant != "" {
+				ctx = model.WithTenant(ctx, tt.tenant)
+			}
+			if tt.environment != "" {
+				ctx = model.WithEnvironment(ctx, tt.environment)
+			}
+
+			if got := router.resolve(ctx); got != tt.want {
+				t.Fatalf("operação roteada ao shard %s, esperado %s", got.Name, tt.want.Name)
+			}
+		})
+	}
+}
+
+// TestShardRewrite garante que as consultas usam o schema do shard no lugar do schema padrão
+func TestShardRewrite(t *testing.T) {
+	query := "SELECT b.id FROM bank_reconciliation.billets b JOIN bank_reconciliation.reconciliations r ON r.billet_id = b.id"
+
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{"schema padrão", DefaultSchema, query},
+		{"schema do tenant", "tenant_acme", "SELECT b.id FROM tenant_acme.billets b JOIN tenant_acme.reconciliations r ON r.billet_id = b.id"},
+		{"schema sandbox", DefaultSchema + SandboxSchemaSuffix,
+			"SELECT b.id FROM bank_reconciliation_sandbox.billets b JOIN bank_reconciliation_sandbox.reconciliations r ON r.billet_id = b.id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shard := &Shard{Name: tt.name, Schema: tt.schema}
+			if got := shard.rewrite(query); got != tt.want {
+				t.Fatalf("consulta reescrita %q, esperado %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRouteTenantsToDefaultShard garante que os tenants configurados sem DSN e sem schema próprios ficam
+// no shard padrão, sem abrir outro pool de conexões
+func TestRouteTenantsToDefaultShard(t *testing.T) {
+	router := NewShardRouter(&Connection{dsn: "host=localhost dbname=conciliacao"})
+
+	err := router.routeTenants(&Connection{}, map[string]ShardConfig{
+		"acme":   {},
+		"globex": {Schema: DefaultSchema},
+	})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	for _, tenant := range []string{"acme", "globex"} {
+		if shard := router.resolve(model.WithTenant(context.Background(), tenant)); shard != router.defaultShard {
+			t.Fatalf("tenant %s roteado ao shard %s, esperado o padrão", tenant, shard.Name)
+		}
+	}
+	if len(router.Shards()) != 1 {
+		t.Fatalf("%d shards abertos, esperado apenas o padrão", len(router.Shards()))
+	}
+}
+
+// TestRouteTenantsRejectsInvalidSchema garante que nomes de schema que não podem ser interpolados nas
+// consultas são recusados antes de qualquer conexão
+func TestRouteTenantsRejectsInvalidSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{"com maiúsculas", "Tenant_Acme"},
+		{"com ponto e vírgula", "acme; DROP SCHEMA bank_reconciliation"},
+		{"começando com dígito", "1acme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewShardRouter(&Connection{})
+			if err := router.routeTenants(&Connection{}, map[string]ShardConfig{"acme": {Schema: tt.schema}}); err == nil {
+				t.Fatalf("schema %q aceito", tt.schema)
+			}
+		})
+	}
+}
+
+// TestWithSearchPath garante que o search_path é acrescentado às strings de conexão nos dois formatos aceitos
+func TestWithSearchPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		dsn     string
+		want    string
+		wantErr bool
+	}{
+		{"chave=valor", "host=db dbname=conciliacao", "host=db dbname=conciliacao search_path=tenant_acme", false},
+		{"URL", "postgres://user@db:5432/conciliacao", "dbname='conciliacao' host='db' port='5432' user='user' search_path=tenant_acme", false},
+		{"URL inválida", "postgres://user@db:porta/conciliacao", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := withSearchPath(tt.dsn, "tenant_acme")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("erro %v, esperado erro: %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("string de conexão %q, esperado %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,355 @@
+package erp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/service"
+)
+
+// Garantir que Client implementa as interfaces ERPBilletSource e ERPBilletFeed
+var (
+	_ service.ERPBilletSource = (*Client)(nil)
+	_ service.ERPBilletFeed   = (*Client)(nil)
+)
+
+// defaultTimeout define o tempo máximo de cada requisição à API do ERP
+const defaultTimeout = 30 * time.Second
+
+// maxPages limita a paginação, para que uma API que nunca devolve uma página incompleta não prenda o job
+const maxPages = 10000
+
+// Config define o acesso a um endpoint de boletos da API do ERP
+type Config struct {
+	// URL do endpoint que lista os boletos: os em aberto, na verificação de divergências, ou os criados e
+	// alterados, na importação delta
+	URL string
+
+	// Token enviado no cabeçalho de autenticação; AuthHeader padrão Authorization, com o prefixo Bearer
+	Token      string
+	AuthHeader string
+
+	// PageSize habilita a paginação com os parâmetros page e page_size; zero busca tudo numa requisição
+	PageSize int
+
+	Timeout time.Duration
+}
+
+// Client consulta os boletos na API do ERP. A API responde uma lista JSON de boletos com billet_id,
+// bank_account, amount, issuance_date (AAAA-MM-DD ou RFC 3339) e reference_id; na importação delta, cada
+// boleto traz também a data de alteração (updated_at) e os dados de cadastro (bank_code, open_amount,
+// bar_code, digitable_line, payer_document, payer_name, due_date, interest_rate e fine_percent)
+type Client struct {
+	config Config
+	client *http.Client
+}
+
+// NewClient cria o cliente da API do ERP
+func NewClient(config Config) (*Client, error) {
+	parsed, err := url.Parse(config.URL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("URL da API do ERP inválida: %q", config.URL)
+	}
+	if config.PageSize < 0 {
+		return nil, fmt.Errorf("tamanho de página da API do ERP inválido: %d", config.PageSize)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	return &Client{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}, nil
+}
+
+// ClientFromEnv cria o cliente dos boletos em aberto a partir das variáveis ERP_OPEN_BILLETS_URL,
+// ERP_API_TOKEN, ERP_AUTH_HEADER, ERP_PAGE_SIZE e ERP_TIMEOUT (ex.: 30s). Retorna nil quando a API do ERP
+// não está configurada ou a configuração é inválida
+func ClientFromEnv() *Client {
+	return clientFromEnv("ERP_OPEN_BILLETS_URL", "verificação de divergências com o ERP")
+}
+
+// ChangesClientFromEnv cria o cliente da importação delta a partir de ERP_CHANGED_BILLETS_URL, com as
+// mesmas credenciais, paginação e timeout do cliente dos boletos em aberto. Retorna nil quando o endpoint
+// não está configurado ou a configuração é inválida
+func ChangesClientFromEnv() *Client {
+	return clientFromEnv("ERP_CHANGED_BILLETS_URL", "importação delta de boletos do ERP")
+}
+
+// clientFromEnv cria o cliente do endpoint da variável urlEnv; feature descreve nos logs o que fica
+// desabilitado quando a configuração é inválida
+func clientFromEnv(urlEnv, feature string) *Client {
+	endpoint := os.Getenv(urlEnv)
+	if endpoint == "" {
+		return nil
+	}
+
+	config := Config{
+		URL:        endpoint,
+		Token:      os.Getenv("ERP_API_TOKEN"),
+		AuthHeader: os.Getenv("ERP_AUTH_HEADER"),
+	}
+
+	if raw := os.Getenv("ERP_PAGE_SIZE"); raw != "" {
+		pageSize, err := strconv.Atoi(raw)
+		if err != nil {
+			log.Printf("ERP_PAGE_SIZE inválido, %s desabilitada: %v", feature, err)
+			return nil
+		}
+		config.PageSize = pageSize
+	}
+
+	if raw := os.Getenv("ERP_TIMEOUT"); raw != "" {
+		timeout, err := time.ParseDuration(raw)
+		if err != nil {
+			log.Printf("ERP_TIMEOUT inválido, %s desabilitada: %v", feature, err)
+			return nil
+		}
+		config.Timeout = timeout
+	}
+
+	client, err := NewClient(config)
+	if err != nil {
+		log.Printf("configuração da API do ERP inválida, %s desabilitada: %v", feature, err)
+		return nil
+	}
+	return client
+}
+
+// Source descreve o endpoint consultado, sem credenciais nem parâmetros
+func (c *Client) Source() string {
+	parsed, err := url.Parse(c.config.URL)
+	if err != nil {
+		return ""
+	}
+	parsed.User = nil
+	parsed.RawQuery = ""
+	return parsed.String()
+}
+
+// ListOpenBillets busca os boletos em aberto no ERP, percorrendo as páginas quando a paginação está
+// habilitada até receber uma página incompleta
+func (c *Client) ListOpenBillets(ctx context.Context) ([]*model.ERPBillet, error) {
+	items, err := c.list(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	billets := make([]*model.ERPBillet, 0, len(items))
+	for _, item := range items {
+		billet := &model.ERPBillet{
+			ID:          item.BilletID,
+			BankAccount: item.BankAccount,
+			Amount:      item.Amount,
+			ReferenceID: item.ReferenceID,
+		}
+		if item.IssuanceDate != "" {
+			billet.IssuanceDate, err = parseDate(item.IssuanceDate)
+			if err != nil {
+				return nil, fmt.Errorf("issuance_date inválida do boleto %s: %w", item.BilletID, err)
+			}
+		}
+		billets = append(billets, billet)
+	}
+
+	return billets, nil
+}
+
+// ListChangedBillets busca os boletos criados ou alterados no ERP a partir de since, enviado no parâmetro
+// updated_since (RFC 3339, em UTC); sem since, busca todos. Os boletos são retornados em ordem de alteração
+func (c *Client) ListChangedBillets(ctx context.Context, since *time.Time) ([]*model.ERPBilletChange, error) {
+	var params url.Values
+	if since != nil {
+		params = url.Values{"updated_since": {since.UTC().Format(time.RFC3339Nano)}}
+	}
+
+	items, err := c.list(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*model.ERPBilletChange, 0, len(items))
+	for _, item := range items {
+		change, err := item.toChange()
+		if err != nil {
+			return nil, fmt.Errorf("boleto %s: %w", item.BilletID, err)
+		}
+		changes = append(changes, change)
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].UpdatedAt.Before(changes[j].UpdatedAt)
+	})
+
+	return changes, nil
+}
+
+// list consulta o endpoint com os parâmetros informados, percorrendo as páginas quando a paginação está
+// habilitada até receber uma página incompleta
+func (c *Client) list(ctx context.Context, params url.Values) ([]erpBillet, error) {
+	if c.config.PageSize == 0 {
+		endpoint, err := c.endpointURL(params, 0)
+		if err != nil {
+			return nil, err
+		}
+		return c.fetch(ctx, endpoint)
+	}
+
+	var items []erpBillet
+	for page := 1; page <= maxPages; page++ {
+		endpoint, err := c.endpointURL(params, page)
+		if err != nil {
+			return nil, err
+		}
+
+		pageItems, err := c.fetch(ctx, endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("página %d: %w", page, err)
+		}
+
+		items = append(items, pageItems...)
+		if len(pageItems) < c.config.PageSize {
+			return items, nil
+		}
+	}
+
+	return nil, fmt.Errorf("API do ERP excedeu o limite de %d páginas", maxPages)
+}
+
+// endpointURL monta a URL do endpoint com os parâmetros informados e, a partir da primeira página, com os
+// parâmetros page e page_size
+func (c *Client) endpointURL(params url.Values, page int) (string, error) {
+	parsed, err := url.Parse(c.config.URL)
+	if err != nil {
+		return "", err
+	}
+
+	query := parsed.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	if page > 0 {
+		query.Set("page", strconv.Itoa(page))
+		query.Set("page_size", strconv.Itoa(c.config.PageSize))
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
+
+// erpBillet representa um boleto na resposta da API do ERP
+type erpBillet struct {
+	BilletID      string  `json:"billet_id"`
+	BankAccount   string  `json:"bank_account"`
+	Amount        float64 `json:"amount"`
+	IssuanceDate  string  `json:"issuance_date"`
+	ReferenceID   *string `json:"reference_id"`
+	BankCode      string  `json:"bank_code"`
+	OpenAmount    bool    `json:"open_amount"`
+	BarCode       string  `json:"bar_code"`
+	DigitableLine string  `json:"digitable_line"`
+	PayerDocument string  `json:"payer_document"`
+	PayerName     string  `json:"payer_name"`
+	DueDate       string  `json:"due_date"`
+	InterestRate  float64 `json:"interest_rate"`
+	FinePercent   float64 `json:"fine_percent"`
+	UpdatedAt     string  `json:"updated_at"`
+}
+
+// toChange converte o boleto da importação delta para o modelo de domínio, com a data de alteração em UTC
+func (item erpBillet) toChange() (*model.ERPBilletChange, error) {
+	if item.UpdatedAt == "" {
+		return nil, fmt.Errorf("boleto sem updated_at na resposta da API do ERP")
+	}
+	updatedAt, err := time.Parse(time.RFC3339Nano, item.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("updated_at inválida: %w", err)
+	}
+
+	var issuanceDate time.Time
+	if item.IssuanceDate != "" {
+		issuanceDate, err = parseDate(item.IssuanceDate)
+		if err != nil {
+			return nil, fmt.Errorf("issuance_date inválida: %w", err)
+		}
+	}
+
+	billet := model.NewBillet(item.BilletID, item.BankAccount, item.Amount, issuanceDate, item.ReferenceID)
+	billet.BankCode = item.BankCode
+	billet.OpenAmount = item.OpenAmount
+	billet.BarCode = model.OnlyDigits(item.BarCode)
+	billet.DigitableLine = model.OnlyDigits(item.DigitableLine)
+	billet.PayerDocument = model.OnlyDigits(item.PayerDocument)
+	billet.PayerName = strings.TrimSpace(item.PayerName)
+	billet.InterestRate = item.InterestRate
+	billet.FinePercent = item.FinePercent
+
+	if item.DueDate != "" {
+		dueDate, err := parseDate(item.DueDate)
+		if err != nil {
+			return nil, fmt.Errorf("due_date inválida: %w", err)
+		}
+		billet.DueDate = &dueDate
+	}
+
+	return &model.ERPBilletChange{Billet: billet, UpdatedAt: updatedAt.UTC()}, nil
+}
+
+// fetch consulta uma página da API
+func (c *Client) fetch(ctx context.Context, endpoint string) ([]erpBillet, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.config.Token != "" {
+		if c.config.AuthHeader != "" {
+			req.Header.Set(c.config.AuthHeader, c.config.Token)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+c.config.Token)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar a API do ERP: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("API do ERP respondeu com status %d", resp.StatusCode)
+	}
+
+	var response []erpBillet
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("resposta inválida da API do ERP: %w", err)
+	}
+
+	for _, item := range response {
+		if item.BilletID == "" {
+			return nil, fmt.Errorf("boleto sem billet_id na resposta da API do ERP")
+		}
+	}
+
+	return response, nil
+}
+
+// parseDate interpreta datas em AAAA-MM-DD ou RFC 3339
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
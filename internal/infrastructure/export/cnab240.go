@@ -0,0 +1,315 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/service"
+)
+
+// Garantir que CNAB240Generator implementa a interface ResultFileGenerator
+var _ service.ResultFileGenerator = (*CNAB240Generator)(nil)
+
+// FormatCNAB240 identifica o layout CNAB 240 de baixa dos títulos
+const FormatCNAB240 = "cnab240"
+
+// cnab240LineLength define o tamanho fixo dos registros CNAB 240
+const cnab240LineLength = 240
+
+// cnabSettlementMovement é o código de movimento de retorno de liquidação do título
+const cnabSettlementMovement = "06"
+
+// CNABAgreement representa o convênio de cobrança de uma conta bancária usado no arquivo de baixa
+type CNABAgreement struct {
+	BankCode        string `json:"bank_code"`
+	BankName        string `json:"bank_name,omitempty"`
+	Agreement       string `json:"agreement"`
+	Agency          string `json:"agency"`
+	CompanyDocument string `json:"company_document"` // CNPJ da empresa, apenas dígitos
+	CompanyName     string `json:"company_name"`
+}
+
+// CNAB240Generator gera arquivos CNAB 240 de retorno com a baixa (movimento 06) dos boletos conciliados
+// na execução, um arquivo por convênio, no layout lido pelo importador de retornos
+type CNAB240Generator struct {
+	agreements map[string]CNABAgreement // Convênio indexado pela conta bancária
+	accounts   map[string]string        // Conta bancária indexada pelo código do convênio
+}
+
+// NewCNAB240Generator cria o gerador com os convênios indexados pela conta bancária. Cada convênio deve
+// pertencer a uma única conta, pois a numeração sequencial dos arquivos é controlada por convênio
+func NewCNAB240Generator(agreements map[string]CNABAgreement) (*CNAB240Generator, error) {
+	if len(agreements) == 0 {
+		return nil, fmt.Errorf("nenhum convênio configurado para o arquivo de baixa CNAB 240")
+	}
+
+	accounts := make(map[string]string, len(agreements))
+	for account, agreement := range agreements {
+		if agreement.BankCode == "" || agreement.Agreement == "" || agreement.Agency == "" || agreement.CompanyDocument == "" {
+			return nil, fmt.Errorf("convênio da conta %s: bank_code, agreement, agency e company_document são obrigatórios", account)
+		}
+		if other, ok := accounts[agreement.Agreement]; ok {
+			return nil, fmt.Errorf("convênio %s configurado nas contas %s e %s", agreement.Agreement, other, account)
+		}
+		accounts[agreement.Agreement] = account
+	}
+
+	return &CNAB240Generator{
+		agreements: agreements,
+		accounts:   accounts,
+	}, nil
+}
+
+// NewCNAB240GeneratorFromEnv cria o gerador com os convênios em JSON de RESULT_EXPORT_CNAB_AGREEMENTS,
+// indexados pela conta bancária, ex.: {"12345-6":{"bank_code":"341","agreement":"123456","agency":"0001",...}}
+func NewCNAB240GeneratorFromEnv() (*CNAB240Generator, error) {
+	var agreements map[string]CNABAgreement
+	if raw := os.Getenv("RESULT_EXPORT_CNAB_AGREEMENTS"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &agreements); err != nil {
+			return nil, fmt.Errorf("convênios do arquivo de baixa CNAB 240 inválidos: %w", err)
+		}
+	}
+
+	return NewCNAB240Generator(agreements)
+}
+
+// Format retorna o nome do layout gerado
+func (g *CNAB240Generator) Format() string {
+	return FormatCNAB240
+}
+
+// Extension retorna a extensão dos arquivos gerados
+func (g *CNAB240Generator) Extension() string {
+	return "ret"
+}
+
+// Split separa os boletos conciliados pelo convênio da conta bancária. Contas sem convênio configurado
+// impedem a geração, para que nenhuma baixa deixe de ser enviada ao ERP
+func (g *CNAB240Generator) Split(records []model.ResultRecord) (map[string][]model.ResultRecord, error) {
+	files := make(map[string][]model.ResultRecord)
+	for _, record := range records {
+		agreement, ok := g.agreements[record.Reconciled.BankAccount]
+		if !ok {
+			return nil, fmt.Errorf("conta bancária %s sem convênio configurado para o arquivo de baixa", record.Reconciled.BankAccount)
+		}
+		files[agreement.Agreement] = append(files[agreement.Agreement], record)
+	}
+	return files, nil
+}
+
+// Generate gera o arquivo de baixa do convênio com um único lote de cobrança, com os segmentos T e U
+// de cada boleto conciliado. O ID do boleto vai no nosso número e no campo de uso da empresa
+func (g *CNAB240Generator) Generate(run *model.ReconciliationRun, key string, sequence int, records []model.ResultRecord) ([]byte, error) {
+	account, ok := g.accounts[key]
+	if !ok {
+		return nil, fmt.Errorf("convênio %s não configurado para o arquivo de baixa", key)
+	}
+	agreement := g.agreements[account]
+	accountNumber, accountDigit := splitAccount(account)
+
+	records = append([]model.ResultRecord(nil), records...)
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Reconciled.BilletID < records[j].Reconciled.BilletID
+	})
+
+	now := time.Now()
+	var lines []string
+
+	header := newCNABLine(agreement.BankCode, "0000", '0')
+	header.alpha(18, 18, "2")
+	header.numeric(19, 32, agreement.CompanyDocument)
+	header.alpha(33, 52, agreement.Agreement)
+	header.numeric(53, 57, agreement.Agency)
+	header.numeric(59, 70, accountNumber)
+	header.alpha(71, 71, accountDigit)
+	header.alpha(73, 102, agreement.CompanyName)
+	header.alpha(103, 132, agreement.BankName)
+	header.alpha(143, 143, "2")
+	header.alpha(144, 151, now.Format("02012006"))
+	header.alpha(152, 157, now.Format("150405"))
+	header.number(158, 163, int64(sequence))
+	header.alpha(164, 166, "089")
+	header.numeric(167, 171, "0")
+	lines = append(lines, header.String())
+
+	batchHeader := newCNABLine(agreement.BankCode, "0001", '1')
+	batchHeader.alpha(9, 9, "T")
+	batchHeader.alpha(10, 11, "01")
+	batchHeader.alpha(14, 16, "045")
+	batchHeader.alpha(18, 18, "2")
+	batchHeader.numeric(19, 33, agreement.CompanyDocument)
+	batchHeader.alpha(34, 53, agreement.Agreement)
+	batchHeader.numeric(54, 58, agreement.Agency)
+	batchHeader.numeric(60, 71, accountNumber)
+	batchHeader.alpha(72, 72, accountDigit)
+	batchHeader.alpha(74, 103, agreement.CompanyName)
+	batchHeader.number(184, 191, int64(sequence))
+	batchHeader.alpha(192, 199, now.Format("02012006"))
+	batchHeader.numeric(200, 207, "0")
+	lines = append(lines, batchHeader.String())
+
+	var nominalTotal int64
+	for i, record := range records {
+		if record.Billet == nil {
+			return nil, fmt.Errorf("boleto %s não encontrado para o arquivo de baixa", record.Reconciled.BilletID)
+		}
+		if len(record.Billet.ID) > 20 {
+			return nil, fmt.Errorf("boleto %s com ID maior que as 20 posições do nosso número", record.Billet.ID)
+		}
+
+		nominal := cents(record.Billet.Amount)
+		if record.Billet.OpenAmount {
+			nominal = cents(record.PaidAmount())
+		}
+		paid := cents(record.PaidAmount())
+		nominalTotal += nominal
+
+		documentNumber := ""
+		if record.Billet.ReferenceID != nil {
+			documentNumber = *record.Billet.ReferenceID
+		}
+
+		segmentT := newCNABLine(agreement.BankCode, "0001", '3')
+		segmentT.number(9, 13, int64(2*i+1))
+		segmentT.alpha(14, 14, "T")
+		segmentT.alpha(16, 17, cnabSettlementMovement)
+		segmentT.numeric(18, 22, agreement.Agency)
+		segmentT.numeric(24, 35, accountNumber)
+		segmentT.alpha(36, 36, accountDigit)
+		segmentT.alpha(38, 57, record.Billet.ID)
+		segmentT.alpha(58, 58, "1")
+		segmentT.alpha(59, 73, documentNumber)
+		segmentT.numeric(74, 81, "0")
+		segmentT.number(82, 96, nominal)
+		segmentT.alpha(97, 99, agreement.BankCode)
+		segmentT.numeric(100, 104, "0")
+		segmentT.alpha(106, 130, record.Billet.ID)
+		segmentT.alpha(131, 132, "09")
+		segmentT.numeric(133, 148, "0")
+		if document := record.Billet.PayerDocument; document != "" {
+			// Tipo de inscrição do pagador: 1 para CPF, 2 para CNPJ
+			kind := "1"
+			if model.IsCNPJ(document) {
+				kind = "2"
+			}
+			segmentT.alpha(133, 133, kind)
+			segmentT.numeric(134, 148, document)
+		}
+		segmentT.alpha(149, 188, record.Billet.PayerName)
+		segmentT.numeric(189, 213, "0")
+		lines = append(lines, segmentT.String())
+
+		var interest, discount int64
+		if paid > nominal {
+			interest = paid - nominal
+		} else {
+			discount = nominal - paid
+		}
+
+		segmentU := newCNABLine(agreement.BankCode, "0001", '3')
+		segmentU.number(9, 13, int64(2*i+2))
+		segmentU.alpha(14, 14, "U")
+		segmentU.alpha(16, 17, cnabSettlementMovement)
+		segmentU.number(18, 32, interest)
+		segmentU.number(33, 47, discount)
+		segmentU.numeric(48, 77, "0")
+		segmentU.number(78, 92, paid)
+		segmentU.number(93, 107, paid)
+		segmentU.numeric(108, 137, "0")
+		segmentU.alpha(138, 145, record.Reconciled.PaymentDate.Format("02012006"))
+		segmentU.alpha(146, 153, record.Reconciled.PaymentDate.Format("02012006"))
+		segmentU.numeric(158, 180, "0")
+		segmentU.numeric(211, 213, "0")
+		lines = append(lines, segmentU.String())
+	}
+
+	batchTrailer := newCNABLine(agreement.BankCode, "0001", '5')
+	batchTrailer.number(18, 23, int64(2*len(records)+2))
+	batchTrailer.number(24, 29, int64(len(records)))
+	batchTrailer.number(30, 46, nominalTotal)
+	batchTrailer.numeric(47, 115, "0")
+	lines = append(lines, batchTrailer.String())
+
+	fileTrailer := newCNABLine(agreement.BankCode, "9999", '9')
+	fileTrailer.number(18, 23, 1)
+	fileTrailer.number(24, 29, int64(len(lines)+1))
+	fileTrailer.numeric(30, 35, "0")
+	lines = append(lines, fileTrailer.String())
+
+	return []byte(strings.Join(lines, "\r\n") + "\r\n"), nil
+}
+
+// cnabLine monta um registro CNAB 240 posição a posição
+type cnabLine []rune
+
+// newCNABLine cria um registro em branco com o banco, o lote e o tipo de registro preenchidos
+func newCNABLine(bankCode, batch string, recordType rune) cnabLine {
+	line := cnabLine([]rune(strings.Repeat(" ", cnab240LineLength)))
+	line.numeric(1, 3, bankCode)
+	line.alpha(4, 7, batch)
+	line[7] = recordType
+	return line
+}
+
+// alpha preenche as posições [start, end] (base 1, inclusivas) com o texto alinhado à esquerda, em
+// maiúsculas e completado com brancos
+func (l cnabLine) alpha(start, end int, value string) {
+	width := end - start + 1
+	runes := []rune(strings.ToUpper(value))
+	if len(runes) > width {
+		runes = runes[:width]
+	}
+	for i := 0; i < width; i++ {
+		if i < len(runes) {
+			l[start-1+i] = runes[i]
+		} else {
+			l[start-1+i] = ' '
+		}
+	}
+}
+
+// numeric preenche as posições [start, end] com os dígitos do valor alinhados à direita e completados
+// com zeros
+func (l cnabLine) numeric(start, end int, value string) {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+
+	width := end - start + 1
+	if len(digits) > width {
+		digits = digits[len(digits)-width:]
+	}
+	l.alpha(start, end, strings.Repeat("0", width-len(digits))+digits)
+}
+
+// number preenche as posições [start, end] com o número completado com zeros
+func (l cnabLine) number(start, end int, value int64) {
+	l.numeric(start, end, fmt.Sprintf("%d", value))
+}
+
+// String retorna o registro completo
+func (l cnabLine) String() string {
+	return string(l)
+}
+
+// splitAccount separa o número e o dígito de uma conta no formato conta-dígito
+func splitAccount(account string) (string, string) {
+	if i := strings.LastIndex(account, "-"); i >= 0 {
+		return account[:i], account[i+1:]
+	}
+	return account, ""
+}
+
+// cents converte um valor em reais para centavos
+func cents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
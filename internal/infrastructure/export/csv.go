@@ -0,0 +1,95 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/service"
+)
+
+// Garantir que CSVGenerator implementa a interface ResultFileGenerator
+var _ service.ResultFileGenerator = CSVGenerator{}
+
+// FormatCSV identifica o layout CSV do arquivo de resultado
+const FormatCSV = "csv"
+
+// csvHeader define as colunas do arquivo de resultado em CSV
+var csvHeader = []string{
+	"billet_id", "transaction_id", "bank_account", "conciliation_status",
+	"conciliation_strategy", "amount_diff", "reference_id", "payment_date",
+}
+
+// CSVGenerator gera o arquivo de resultado com uma linha por boleto conciliado na execução
+type CSVGenerator struct{}
+
+// Format retorna o nome do layout gerado
+func (CSVGenerator) Format() string {
+	return FormatCSV
+}
+
+// Extension retorna a extensão dos arquivos gerados
+func (CSVGenerator) Extension() string {
+	return "csv"
+}
+
+// Split mantém todos os boletos conciliados em um único arquivo
+func (CSVGenerator) Split(records []model.ResultRecord) (map[string][]model.ResultRecord, error) {
+	return map[string][]model.ResultRecord{"": records}, nil
+}
+
+// Generate gera o CSV dos boletos conciliados na execução
+func (CSVGenerator) Generate(run *model.ReconciliationRun, key string, sequence int, records []model.ResultRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return nil, fmt.Errorf("erro ao escrever cabeçalho do arquivo de resultado: %w", err)
+	}
+
+	for _, record := range records {
+		reconciled := record.Reconciled
+
+		referenceID := ""
+		if reconciled.ReferenceID != nil {
+			referenceID = *reconciled.ReferenceID
+		}
+
+		line := []string{
+			reconciled.BilletID,
+			reconciled.TransactionID,
+			reconciled.BankAccount,
+			string(reconciled.ConciliationStatus),
+			string(reconciled.ConciliationStrategy),
+			strconv.FormatFloat(reconciled.AmountDiff, 'f', 2, 64),
+			referenceID,
+			reconciled.PaymentDate.Format(time.DateOnly),
+		}
+		if err := writer.Write(line); err != nil {
+			return nil, fmt.Errorf("erro ao escrever linha do arquivo de resultado: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("erro ao gerar arquivo de resultado: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GeneratorFromEnv cria o gerador do layout definido em RESULT_EXPORT_FORMAT (csv ou cnab240, padrão csv)
+func GeneratorFromEnv() (service.ResultFileGenerator, error) {
+	switch format := os.Getenv("RESULT_EXPORT_FORMAT"); format {
+	case "", FormatCSV:
+		return CSVGenerator{}, nil
+	case FormatCNAB240:
+		return NewCNAB240GeneratorFromEnv()
+	default:
+		return nil, fmt.Errorf("layout do arquivo de resultado inválido: %s", format)
+	}
+}
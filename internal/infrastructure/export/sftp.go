@@ -0,0 +1,164 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	"conciliacao-bancaria/internal/domain/service"
+)
+
+// sendTimeout limita a duração de um envio ao SFTP
+const sendTimeout = 30 * time.Second
+
+// Garantir que SFTPSender implementa a interface FileSender
+var _ service.FileSender = (*SFTPSender)(nil)
+
+// SFTPConfig representa a configuração do SFTP do ERP que recebe os arquivos de resultado
+type SFTPConfig struct {
+	Address        string // host:porta do servidor SFTP
+	User           string
+	Password       string
+	PrivateKeyFile string // chave privada usada no lugar (ou além) da senha
+	HostKey        string // chave pública do servidor no formato authorized_keys
+	RemoteDir      string
+}
+
+// SFTPSender envia os arquivos de resultado ao SFTP do ERP
+type SFTPSender struct {
+	config    SFTPConfig
+	sshConfig *ssh.ClientConfig
+}
+
+// NewSFTPSender cria uma nova instância de SFTPSender. A chave do servidor é obrigatória
+func NewSFTPSender(config SFTPConfig) (*SFTPSender, error) {
+	if config.Address == "" || config.User == "" {
+		return nil, fmt.Errorf("endereço e usuário do SFTP são obrigatórios")
+	}
+
+	if config.HostKey == "" {
+		return nil, fmt.Errorf("chave do servidor SFTP não informada")
+	}
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(config.HostKey))
+	if err != nil {
+		return nil, fmt.Errorf("chave do servidor SFTP inválida: %w", err)
+	}
+
+	var auth []ssh.AuthMethod
+	if config.PrivateKeyFile != "" {
+		key, err := os.ReadFile(config.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler chave privada do SFTP: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("chave privada do SFTP inválida: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if config.Password != "" {
+		auth = append(auth, ssh.Password(config.Password))
+	}
+	if len(auth) == 0 {
+		return nil, fmt.Errorf("senha ou chave privada do SFTP não informada")
+	}
+
+	return &SFTPSender{
+		config: config,
+		sshConfig: &ssh.ClientConfig{
+			User:            config.User,
+			Auth:            auth,
+			HostKeyCallback: ssh.FixedHostKey(hostKey),
+			Timeout:         sendTimeout,
+		},
+	}, nil
+}
+
+// NewSFTPSenderFromEnv cria o envio a partir das variáveis RESULT_EXPORT_SFTP_ADDRESS, RESULT_EXPORT_SFTP_USER,
+// RESULT_EXPORT_SFTP_PASSWORD, RESULT_EXPORT_SFTP_KEY_FILE, RESULT_EXPORT_SFTP_HOST_KEY e RESULT_EXPORT_SFTP_DIR.
+// Retorna nil quando o SFTP não está configurado
+func NewSFTPSenderFromEnv() *SFTPSender {
+	address := os.Getenv("RESULT_EXPORT_SFTP_ADDRESS")
+	if address == "" {
+		return nil
+	}
+
+	sender, err := NewSFTPSender(SFTPConfig{
+		Address:        address,
+		User:           os.Getenv("RESULT_EXPORT_SFTP_USER"),
+		Password:       os.Getenv("RESULT_EXPORT_SFTP_PASSWORD"),
+		PrivateKeyFile: os.Getenv("RESULT_EXPORT_SFTP_KEY_FILE"),
+		HostKey:        os.Getenv("RESULT_EXPORT_SFTP_HOST_KEY"),
+		RemoteDir:      os.Getenv("RESULT_EXPORT_SFTP_DIR"),
+	})
+	if err != nil {
+		log.Printf("configuração do SFTP inválida, exportação dos resultados desabilitada: %v", err)
+		return nil
+	}
+
+	return sender
+}
+
+// Destination descreve o destino dos arquivos, sem credenciais
+func (s *SFTPSender) Destination() string {
+	return fmt.Sprintf("sftp://%s@%s/%s", s.config.User, s.config.Address, s.config.RemoteDir)
+}
+
+// Send grava o arquivo com um nome temporário e o renomeia ao final, para que o ERP nunca leia
+// um arquivo incompleto
+func (s *SFTPSender) Send(ctx context.Context, name string, content []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", s.config.Address)
+	if err != nil {
+		return fmt.Errorf("erro ao conectar ao SFTP: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	sshConn, channels, requests, err := ssh.NewClientConn(conn, s.config.Address, s.sshConfig)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("erro ao autenticar no SFTP: %w", err)
+	}
+	sshClient := ssh.NewClient(sshConn, channels, requests)
+	defer sshClient.Close()
+
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar sessão SFTP: %w", err)
+	}
+	defer client.Close()
+
+	target := path.Join(s.config.RemoteDir, name)
+	temporary := target + ".part"
+
+	file, err := client.Create(temporary)
+	if err != nil {
+		return fmt.Errorf("erro ao criar arquivo no SFTP: %w", err)
+	}
+
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao gravar arquivo no SFTP: %w", err)
+	}
+
+	if err := client.PosixRename(temporary, target); err != nil {
+		return fmt.Errorf("erro ao renomear arquivo no SFTP: %w", err)
+	}
+
+	return nil
+}
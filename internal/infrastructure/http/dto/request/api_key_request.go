@@ -0,0 +1,37 @@
+package request
+
+import (
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// APIKeyRequest representa a estrutura de dados para a criação de uma API key
+type APIKeyRequest struct {
+	Name         string    `json:"name" validate:"required"`
+	Permissions  []string  `json:"permissions" validate:"min=1,dive,oneof=read import reconcile admin"` // read, import, reconcile e/ou admin
+	BankAccounts []string  `json:"bank_accounts,omitempty"`                                             // Vazio permite todas as contas
+	ExpiresAt    *DateTime `json:"expires_at,omitempty"`                                                // Vazio não expira
+}
+
+// RotateAPIKeyRequest representa a solicitação de rotação de uma API key
+type RotateAPIKeyRequest struct {
+	GracePeriodSeconds int `json:"grace_period_seconds,omitempty" validate:"gte=0"` // Tempo em que a chave anterior continua válida
+}
+
+// Validate verifica o nome e os escopos da API key
+func (r APIKeyRequest) Validate() error {
+	return validateStruct(r)
+}
+
+// Validate verifica o período de carência da rotação
+func (r RotateAPIKeyRequest) Validate() error {
+	return validateStruct(r)
+}
+
+// ToPermissionsDomain converte os escopos da requisição para o modelo de domínio
+func (r APIKeyRequest) ToPermissionsDomain() []model.APIKeyPermission {
+	permissions := make([]model.APIKeyPermission, 0, len(r.Permissions))
+	for _, permission := range r.Permissions {
+		permissions = append(permissions, model.APIKeyPermission(permission))
+	}
+	return permissions
+}
@@ -0,0 +1,11 @@
+package request
+
+// BankAccountStatusRequest representa o motivo informado ao inativar uma conta bancária
+type BankAccountStatusRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+// Validate verifica se o motivo da inativação foi informado
+func (r BankAccountStatusRequest) Validate() error {
+	return validateStruct(r)
+}
@@ -1,17 +1,58 @@
 package request
 
-import "time"
+import (
+	"strings"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
 
 // BilletRequest representa a estrutura de dados para a requisição de criação ou atualização de um boleto
 type BilletRequest struct {
-	BilletID     string    `json:"billet_id"`
-	BankAccount  string    `json:"bank_account"`
-	Amount       float64   `json:"amount"`
-	IssuanceDate time.Time `json:"issuance_date"`
-	ReferenceID  *string   `json:"reference_id,omitempty"`
+	BilletID          string   `json:"billet_id" validate:"required"`
+	BankAccount       string   `json:"bank_account" validate:"required"`
+	Amount            float64  `json:"amount"`
+	IssuanceDate      DateTime `json:"issuance_date" validate:"required"`
+	ReferenceID       *string  `json:"reference_id,omitempty"`
+	InstallmentNumber *int     `json:"installment_number,omitempty"` // Número da parcela em boletos de carnê
+	ContractID        *string  `json:"contract_id,omitempty"`
+	CustomerID        *string  `json:"customer_id,omitempty"`
+	OpenAmount        bool     `json:"open_amount,omitempty"`    // Boleto de valor aberto (depósito identificado)
+	BankCode          string   `json:"bank_code,omitempty"`      // Código do banco de cobrança (ex.: 341)
+	BarCode           string   `json:"bar_code,omitempty"`       // Código de barras do título (44 dígitos)
+	DigitableLine     string   `json:"digitable_line,omitempty"` // Linha digitável, com ou sem a formatação
+	PayerDocument     string   `json:"payer_document,omitempty"` // CPF ou CNPJ do pagador, com ou sem a formatação
+	PayerName         string   `json:"payer_name,omitempty" validate:"max=150"`
+
+	// Vencimento e encargos de atraso: multa sobre o valor e juros de mora, em percentual ao mês
+	DueDate      *DateTime `json:"due_date,omitempty"`
+	InterestRate float64   `json:"interest_rate,omitempty"`
+	FinePercent  float64   `json:"fine_percent,omitempty"`
 }
 
 // BilletBatchRequest representa uma lista de boletos para processamento em lote
 type BilletBatchRequest struct {
 	Billets []BilletRequest `json:"billets"`
 }
+
+// Validate verifica os campos obrigatórios da requisição; as regras de negócio ficam no caso de uso
+func (r BilletRequest) Validate() error {
+	return validateStruct(r)
+}
+
+// ToBilletDomain converte a requisição para o modelo de domínio
+func (r BilletRequest) ToBilletDomain() *model.Billet {
+	billet := model.NewBillet(r.BilletID, r.BankAccount, r.Amount, r.IssuanceDate.Time, r.ReferenceID)
+	billet.InstallmentNumber = r.InstallmentNumber
+	billet.ContractID = r.ContractID
+	billet.CustomerID = r.CustomerID
+	billet.OpenAmount = r.OpenAmount
+	billet.BankCode = r.BankCode
+	billet.BarCode = model.OnlyDigits(r.BarCode)
+	billet.DigitableLine = model.OnlyDigits(r.DigitableLine)
+	billet.PayerDocument = model.OnlyDigits(r.PayerDocument)
+	billet.PayerName = strings.TrimSpace(r.PayerName)
+	billet.DueDate = r.DueDate.Ptr()
+	billet.InterestRate = r.InterestRate
+	billet.FinePercent = r.FinePercent
+	return billet
+}
@@ -0,0 +1,11 @@
+package request
+
+// DailyClosingRequest representa a solicitação de abertura do fechamento de um dia
+type DailyClosingRequest struct {
+	Date string `json:"date" validate:"required,datetime=2006-01-02"` // Dia do fechamento no formato AAAA-MM-DD
+}
+
+// Validate verifica o dia do fechamento
+func (r DailyClosingRequest) Validate() error {
+	return validateStruct(r)
+}
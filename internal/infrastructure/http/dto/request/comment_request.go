@@ -0,0 +1,11 @@
+package request
+
+// CommentRequest representa um comentário adicionado à linha do tempo de um boleto ou pagamento
+type CommentRequest struct {
+	Text string `json:"text" validate:"required"`
+}
+
+// Validate verifica se o texto do comentário foi informado
+func (r CommentRequest) Validate() error {
+	return validateStruct(r)
+}
@@ -0,0 +1,97 @@
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateTime representa uma data informada na requisição. Além de RFC3339, aceita a data simples
+// (AAAA-MM-DD), a data e hora sem fuso e o epoch em segundos ou milissegundos, em número ou texto.
+// Datas sem fuso são interpretadas em UTC, e o valor é sempre normalizado para UTC
+type DateTime struct {
+	time.Time
+}
+
+// dateTimeLayouts lista os formatos textuais aceitos, na ordem em que são tentados
+var dateTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// epochMillisThreshold separa o epoch em segundos do em milissegundos: em segundos, o valor já
+// corresponderia ao ano 33658
+const epochMillisThreshold = 1_000_000_000_000
+
+// slashedDatePattern identifica datas como 05/03/2024, em que dia e mês não podem ser distinguidos
+var slashedDatePattern = regexp.MustCompile(`^\d{1,2}[/.-]\d{1,2}[/.-]\d{2,4}$`)
+
+// dateTimeFormats descreve os formatos aceitos nas mensagens de erro
+const dateTimeFormats = "use AAAA-MM-DD, RFC3339 (ex.: 2024-03-05T10:00:00Z) ou epoch em segundos"
+
+// ParseDateTime interpreta a data em qualquer um dos formatos aceitos, retornando-a em UTC
+func ParseDateTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("data vazia: %s", dateTimeFormats)
+	}
+
+	if slashedDatePattern.MatchString(value) {
+		return time.Time{}, fmt.Errorf("data %q ambígua: não é possível distinguir dia e mês; %s", value, dateTimeFormats)
+	}
+
+	if epoch, err := strconv.ParseInt(value, 10, 64); err == nil {
+		// Oito dígitos tanto podem ser AAAAMMDD quanto um epoch de 1970
+		if len(strings.TrimPrefix(value, "-")) == 8 {
+			return time.Time{}, fmt.Errorf("data %q ambígua: pode ser AAAAMMDD ou epoch; %s", value, dateTimeFormats)
+		}
+		if epoch >= epochMillisThreshold || epoch <= -epochMillisThreshold {
+			return time.UnixMilli(epoch).UTC(), nil
+		}
+		return time.Unix(epoch, 0).UTC(), nil
+	}
+
+	for _, layout := range dateTimeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("data %q em formato não reconhecido: %s", value, dateTimeFormats)
+}
+
+// UnmarshalJSON lê a data em texto ou o epoch em número; null mantém a data vazia
+func (d *DateTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	value := string(data)
+	if strings.HasPrefix(value, `"`) {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := ParseDateTime(value)
+	if err != nil {
+		return err
+	}
+
+	d.Time = parsed
+	return nil
+}
+
+// Ptr retorna o time.Time da data, ou nil quando a data não foi informada
+func (d *DateTime) Ptr() *time.Time {
+	if d == nil {
+		return nil
+	}
+	t := d.Time
+	return &t
+}
@@ -0,0 +1,11 @@
+package request
+
+// ExportRequest representa a solicitação de envio do arquivo de resultado de uma execução ao ERP
+type ExportRequest struct {
+	RunID string `json:"run_id" validate:"required"`
+}
+
+// Validate verifica se a execução foi informada
+func (r ExportRequest) Validate() error {
+	return validateStruct(r)
+}
@@ -0,0 +1,11 @@
+package request
+
+// LookupRequest representa a consulta de boletos ou pagamentos por múltiplos IDs
+type LookupRequest struct {
+	IDs []string `json:"ids" validate:"min=1,dive,required"`
+}
+
+// Validate verifica se a consulta informa ao menos um ID, sem IDs vazios
+func (r LookupRequest) Validate() error {
+	return validateStruct(r)
+}
@@ -0,0 +1,13 @@
+package request
+
+// NotificationTemplateRequest representa o assunto e a mensagem, em Go template, de uma nova versão do
+// template de notificação de um tipo de evento
+type NotificationTemplateRequest struct {
+	Subject string `json:"subject" validate:"required"`
+	Body    string `json:"body" validate:"required"`
+}
+
+// Validate verifica se o assunto e a mensagem foram informados
+func (r NotificationTemplateRequest) Validate() error {
+	return validateStruct(r)
+}
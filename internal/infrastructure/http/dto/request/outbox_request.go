@@ -0,0 +1,21 @@
+package request
+
+// RequeueDeliveriesRequest representa a solicitação de reenfileiramento de entregas do outbox
+type RequeueDeliveriesRequest struct {
+	IDs []string `json:"ids" validate:"min=1,dive,required"`
+}
+
+// Validate verifica se a requisição informa ao menos uma entrega
+func (r RequeueDeliveriesRequest) Validate() error {
+	return validateStruct(r)
+}
+
+// DiscardDeliveryRequest representa a solicitação de descarte de uma entrega do outbox
+type DiscardDeliveryRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+// Validate verifica se o motivo do descarte foi informado
+func (r DiscardDeliveryRequest) Validate() error {
+	return validateStruct(r)
+}
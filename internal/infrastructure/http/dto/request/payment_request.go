@@ -1,17 +1,46 @@
 package request
 
-import "time"
+import (
+	"strings"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
 
 // PaymentRequest representa a estrutura de dados para a requisição de criação ou atualização de um pagamento
 type PaymentRequest struct {
-	TransactionID string    `json:"transaction_id"`
-	BankAccount   string    `json:"bank_account"`
-	Amount        float64   `json:"amount"`
-	PaymentDate   time.Time `json:"payment_date"`
-	ReferenceID   *string   `json:"reference_id,omitempty"`
+	TransactionID string   `json:"transaction_id" validate:"required"`
+	BankAccount   string   `json:"bank_account" validate:"required"`
+	Amount        float64  `json:"amount"`
+	PaymentDate   DateTime `json:"payment_date" validate:"required"`
+	ReferenceID   *string  `json:"reference_id,omitempty"`
+	EntryType     string   `json:"entry_type,omitempty" validate:"omitempty,oneof=credito debito"` // credito (padrão) ou debito
+	BankCode      string   `json:"bank_code,omitempty"`                                            // Código do banco de origem do crédito (ex.: 341)
+	BarCode       string   `json:"bar_code,omitempty"`                                             // Código de barras ou linha digitável do título pago
+	PayerDocument string   `json:"payer_document,omitempty"`                                       // CPF ou CNPJ de quem pagou, com ou sem a formatação
+	Description   string   `json:"description,omitempty" validate:"max=255"`                       // Histórico do lançamento no extrato
+	PaymentMethod string   `json:"payment_method,omitempty"`                                       // boleto, pix, transferencia ou deposito
 }
 
 // PaymentBatchRequest representa uma lista de pagamentos para processamento em lote
 type PaymentBatchRequest struct {
 	Payments []PaymentRequest `json:"payments"`
 }
+
+// Validate verifica os campos obrigatórios da requisição; as regras de negócio ficam no caso de uso
+func (r PaymentRequest) Validate() error {
+	return validateStruct(r)
+}
+
+// ToPaymentDomain converte a requisição para o modelo de domínio
+func (r PaymentRequest) ToPaymentDomain() *model.Payment {
+	payment := model.NewPayment(r.TransactionID, r.BankAccount, r.Amount, r.PaymentDate.Time, r.ReferenceID)
+	if r.EntryType != "" {
+		payment.EntryType = model.EntryType(r.EntryType)
+	}
+	payment.BankCode = r.BankCode
+	payment.BarCode = model.OnlyDigits(r.BarCode)
+	payment.PayerDocument = model.OnlyDigits(r.PayerDocument)
+	payment.Description = strings.TrimSpace(r.Description)
+	payment.PaymentMethod = model.PaymentMethod(strings.ToLower(r.PaymentMethod))
+	return payment
+}
@@ -0,0 +1,54 @@
+package request
+
+import "conciliacao-bancaria/internal/domain/model"
+
+// RankerSampleRequest representa um match revisado manualmente enviado para o treino do ranker
+type RankerSampleRequest struct {
+	AmountDiffPercentage float64 `json:"amount_diff_percentage" validate:"gte=0"`
+	DaysDiff             float64 `json:"days_diff" validate:"gte=0"`
+	PartialReference     bool    `json:"partial_reference"`
+	Approved             bool    `json:"approved"`
+}
+
+// MatchReviewRequest representa a aprovação ou rejeição manual de um par boleto/pagamento
+type MatchReviewRequest struct {
+	BilletID      string `json:"billet_id" validate:"required"`
+	TransactionID string `json:"transaction_id" validate:"required"`
+	Approved      bool   `json:"approved"`
+}
+
+// TrainRankerRequest representa a solicitação de treino do ranker de candidatos
+type TrainRankerRequest struct {
+	Samples []RankerSampleRequest `json:"samples,omitempty" validate:"omitempty,dive"` // Vazio treina com as revisões registradas do tenant
+}
+
+// Validate verifica as amostras de treino informadas
+func (r TrainRankerRequest) Validate() error {
+	return validateStruct(r)
+}
+
+// Validate verifica se o par revisado foi informado
+func (r MatchReviewRequest) Validate() error {
+	return validateStruct(r)
+}
+
+// RankerStatusRequest representa a solicitação de ativação ou desativação do ranker do tenant
+type RankerStatusRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// ToRankerSamplesDomain converte as amostras da requisição para o modelo de domínio
+func (r TrainRankerRequest) ToRankerSamplesDomain() []model.RankerSample {
+	samples := make([]model.RankerSample, 0, len(r.Samples))
+	for _, sample := range r.Samples {
+		samples = append(samples, model.RankerSample{
+			Features: model.MatchFeatures{
+				AmountDiffPercentage: sample.AmountDiffPercentage,
+				DaysDiff:             sample.DaysDiff,
+				PartialReference:     sample.PartialReference,
+			},
+			Approved: sample.Approved,
+		})
+	}
+	return samples
+}
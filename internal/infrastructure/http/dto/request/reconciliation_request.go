@@ -1,18 +1,175 @@
 package request
 
-import "time"
+import (
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
+)
 
 // ReconciliationRequest representa a estrutura de dados para solicitar uma conciliação
 type ReconciliationRequest struct {
-	StartDate      time.Time `json:"start_date"`
-	EndDate        time.Time `json:"end_date"`
-	FilterAccounts []string  `json:"filter_accounts,omitempty"`
-	Tolerance      *float64  `json:"tolerance,omitempty"` // Tolerância para conciliação com valor diferente (padrão 5%)
+	StartDate      DateTime `json:"start_date"`
+	EndDate        DateTime `json:"end_date" validate:"omitempty,gtefield=StartDate"`
+	FilterAccounts []string `json:"filter_accounts,omitempty"`
+	Tolerance      *float64 `json:"tolerance,omitempty" validate:"omitempty,gte=0,lte=100"` // Tolerância para conciliação com valor diferente (padrão 5%)
+	UseCredits     bool     `json:"use_credits,omitempty"`                                  // Quita boletos em aberto com créditos não aplicados do pagador
+
+	// AccountTolerances substitui a tolerância nas contas informadas (ex.: {"conta-1": 2.5})
+	AccountTolerances map[string]float64 `json:"account_tolerances,omitempty" validate:"omitempty,dive,gte=0,lte=100"`
+
+	// MaxDaysDiff rejeita, nas estratégias que comparam datas (conta/valor/data), os boletos emitidos mais
+	// de max_days_diff dias antes ou depois do pagamento; a janela própria da estratégia prevalece
+	MaxDaysDiff *int `json:"max_days_diff,omitempty" validate:"omitempty,gt=0"`
+
+	// Strategies define a ordem das estratégias (ex.: ["reference_id", "conta_valor_data"]); estratégias
+	// fora da lista não são aplicadas. StrategyParams traz os parâmetros por estratégia, indexados pelo nome
+	Strategies     []string                         `json:"strategies,omitempty"`
+	StrategyParams map[string]StrategyParamsRequest `json:"strategy_params,omitempty" validate:"omitempty,dive"`
+
+	// DryRun executa a conciliação sem gravar nada, para prévia do efeito dos parâmetros
+	DryRun bool `json:"dry_run,omitempty"`
+
+	// SuggestCorrections devolve, para os boletos que não conciliaram, os pagamentos candidatos e as
+	// sugestões de correção do cadastro
+	SuggestCorrections bool `json:"suggest_corrections,omitempty"`
+}
+
+// Validate verifica a janela de datas, as tolerâncias e a diferença máxima de dias da requisição
+func (r ReconciliationRequest) Validate() error {
+	return validateStruct(r)
+}
+
+// ToReconciliationParams converte a janela de datas, as contas, as tolerâncias e a diferença máxima de
+// dias da requisição para os parâmetros da execução
+func (r ReconciliationRequest) ToReconciliationParams() usecase.ReconciliationParams {
+	return usecase.ReconciliationParams{
+		StartDate:      r.StartDate.Time,
+		EndDate:        r.EndDate.Time,
+		FilterAccounts: r.FilterAccounts,
+		UseCredits:     r.UseCredits,
+		Tolerance:      r.Tolerance,
+
+		AccountTolerances: r.AccountTolerances,
+		MaxDaysDiff:       r.MaxDaysDiff,
+		DryRun:            r.DryRun,
+
+		SuggestCorrections: r.SuggestCorrections,
+	}
+}
+
+// StrategyParamsRequest representa os parâmetros de uma estratégia de conciliação
+type StrategyParamsRequest struct {
+	Tolerance   *float64 `json:"tolerance,omitempty" validate:"omitempty,gte=0,lte=100"` // Tolerância percentual própria da estratégia
+	MaxDaysDiff *int     `json:"max_days_diff,omitempty" validate:"omitempty,gt=0"`      // Janela de datas, em dias, das estratégias que comparam datas
+}
+
+// ToStrategyParams converte os parâmetros por estratégia para o modelo de domínio
+func (r ReconciliationRequest) ToStrategyParams() map[string]model.StrategyParams {
+	return toStrategyParams(r.StrategyParams)
+}
+
+// toStrategyParams converte os parâmetros indexados pelo nome da estratégia para o modelo de domínio
+func toStrategyParams(requested map[string]StrategyParamsRequest) map[string]model.StrategyParams {
+	if len(requested) == 0 {
+		return nil
+	}
+
+	params := make(map[string]model.StrategyParams, len(requested))
+	for name, strategyParams := range requested {
+		params[name] = model.StrategyParams{Tolerance: strategyParams.Tolerance, MaxDaysDiff: strategyParams.MaxDaysDiff}
+	}
+	return params
 }
 
 // ReconciliationByIDsRequest representa a solicitação de conciliação para conjuntos específicos de boletos e pagamentos
 type ReconciliationByIDsRequest struct {
-	BilletIDs      []string `json:"billet_ids"`
-	TransactionIDs []string `json:"transaction_ids"`
-	Tolerance      *float64 `json:"tolerance,omitempty"` // Tolerância para conciliação com valor diferente (padrão 5%)
+	BilletIDs      []string `json:"billet_ids" validate:"min=1"`
+	TransactionIDs []string `json:"transaction_ids" validate:"min=1"`
+	Tolerance      *float64 `json:"tolerance,omitempty" validate:"omitempty,gte=0,lte=100"` // Tolerância para conciliação com valor diferente (padrão 5%)
+}
+
+// Validate verifica se a requisição informa ao menos um boleto e um pagamento
+func (r ReconciliationByIDsRequest) Validate() error {
+	return validateStruct(r)
+}
+
+// ToSpecificReconciliationParams converte a requisição para os parâmetros da conciliação específica do tenant
+func (r ReconciliationByIDsRequest) ToSpecificReconciliationParams(tenant string) usecase.SpecificReconciliationParams {
+	return usecase.SpecificReconciliationParams{
+		BilletIDs:      r.BilletIDs,
+		TransactionIDs: r.TransactionIDs,
+		Tenant:         tenant,
+		Tolerance:      r.Tolerance,
+	}
+}
+
+// ManualMatchRequest representa a conciliação manual de um boleto com um pagamento
+type ManualMatchRequest struct {
+	BilletID      string `json:"billet_id" validate:"required"`
+	TransactionID string `json:"transaction_id" validate:"required"`
+}
+
+// Validate verifica se o boleto e o pagamento foram informados
+func (r ManualMatchRequest) Validate() error {
+	return validateStruct(r)
+}
+
+// UndoMatchRequest representa o motivo informado ao desfazer uma conciliação
+type UndoMatchRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+// Validate verifica se o motivo foi informado
+func (r UndoMatchRequest) Validate() error {
+	return validateStruct(r)
+}
+
+// ApproveReconciliationRequest representa o motivo, opcional, informado ao aprovar um pareamento
+type ApproveReconciliationRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// RejectReconciliationRequest representa o motivo informado ao rejeitar um pareamento
+type RejectReconciliationRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+// Validate verifica se o motivo foi informado
+func (r RejectReconciliationRequest) Validate() error {
+	return validateStruct(r)
+}
+
+// WhatIfRequest representa a solicitação de simulação da conciliação com várias tolerâncias
+type WhatIfRequest struct {
+	StartDate      DateTime  `json:"start_date"`
+	EndDate        DateTime  `json:"end_date" validate:"omitempty,gtefield=StartDate"`
+	FilterAccounts []string  `json:"filter_accounts,omitempty"`
+	Tolerances     []float64 `json:"tolerances,omitempty" validate:"omitempty,dive,gte=0,lte=100"` // Tolerâncias simuladas (padrão 1%, 3% e 5%)
+}
+
+// Validate verifica a janela de datas e as tolerâncias simuladas
+func (r WhatIfRequest) Validate() error {
+	return validateStruct(r)
+}
+
+// LedgerComparisonRequest representa o saldo de contas a receber do razão contábil a ser comparado no período
+type LedgerComparisonRequest struct {
+	StartDate      DateTime `json:"start_date"`
+	EndDate        DateTime `json:"end_date" validate:"omitempty,gtefield=StartDate"`
+	FilterAccounts []string `json:"filter_accounts,omitempty"`
+	LedgerBalance  *float64 `json:"ledger_balance" validate:"required"`
+}
+
+// Validate verifica a janela de datas e exige o saldo do razão
+func (r LedgerComparisonRequest) Validate() error {
+	return validateStruct(r)
+}
+
+// BilletClaimRequest representa a solicitação de bloqueio de um boleto em investigação
+type BilletClaimRequest struct {
+	TTLSeconds int `json:"ttl_seconds,omitempty" validate:"gte=0"` // Duração do bloqueio (padrão 30 minutos)
+}
+
+// Validate verifica a duração do bloqueio
+func (r BilletClaimRequest) Validate() error {
+	return validateStruct(r)
 }
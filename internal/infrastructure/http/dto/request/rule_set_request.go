@@ -0,0 +1,26 @@
+package request
+
+import (
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// RuleSetRequest representa o conjunto de regras de matching de um tenant, geral ou de uma conta
+type RuleSetRequest struct {
+	// BankAccount limita o conjunto à conta; vazia, o conjunto vale para as contas sem conjunto próprio
+	BankAccount string `json:"bank_account,omitempty"`
+
+	// Strategies define a ordem das estratégias; vazia, o conjunto usa a ordem padrão com os parâmetros
+	// de StrategyParams, indexados pelo nome da estratégia
+	Strategies     []string                         `json:"strategies,omitempty"`
+	StrategyParams map[string]StrategyParamsRequest `json:"strategy_params,omitempty" validate:"omitempty,dive"`
+}
+
+// Validate verifica os parâmetros das estratégias do conjunto de regras
+func (r RuleSetRequest) Validate() error {
+	return validateStruct(r)
+}
+
+// ToStrategyParams converte os parâmetros por estratégia para o modelo de domínio
+func (r RuleSetRequest) ToStrategyParams() map[string]model.StrategyParams {
+	return toStrategyParams(r.StrategyParams)
+}
@@ -0,0 +1,28 @@
+package request
+
+import "conciliacao-bancaria/internal/domain/model"
+
+// SubscriptionRequest representa a estrutura de dados para a criação de uma assinatura de eventos
+type SubscriptionRequest struct {
+	Name         string   `json:"name" validate:"required"`
+	CallbackURL  string   `json:"callback_url" validate:"required,url"`
+	Secret       string   `json:"secret,omitempty"`        // Segredo usado na assinatura HMAC das entregas
+	EventTypes   []string `json:"event_types,omitempty"`   // Vazio recebe todos os tipos de evento
+	BankAccounts []string `json:"bank_accounts,omitempty"` // Vazio recebe eventos de todas as contas
+	MinAmount    *float64 `json:"min_amount,omitempty" validate:"omitempty,gte=0"`
+}
+
+// Validate verifica o nome, a URL de entrega e o valor mínimo da assinatura
+func (r SubscriptionRequest) Validate() error {
+	return validateStruct(r)
+}
+
+// ToSubscriptionDomain converte a requisição para o modelo de domínio
+func (r SubscriptionRequest) ToSubscriptionDomain() *model.Subscription {
+	eventTypes := make([]model.EventType, 0, len(r.EventTypes))
+	for _, eventType := range r.EventTypes {
+		eventTypes = append(eventTypes, model.EventType(eventType))
+	}
+
+	return model.NewSubscription(r.Name, r.CallbackURL, r.Secret, eventTypes, r.BankAccounts, r.MinAmount)
+}
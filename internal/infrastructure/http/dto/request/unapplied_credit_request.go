@@ -0,0 +1,11 @@
+package request
+
+// ApplyCreditRequest representa a aplicação de um crédito não aplicado a um boleto do pagador
+type ApplyCreditRequest struct {
+	BilletID string `json:"billet_id" validate:"required"`
+}
+
+// Validate verifica se o boleto foi informado
+func (r ApplyCreditRequest) Validate() error {
+	return validateStruct(r)
+}
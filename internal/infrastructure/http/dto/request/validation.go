@@ -0,0 +1,127 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+
+	pkgErrors "conciliacao-bancaria/pkg/errors"
+)
+
+// validate aplica as regras declaradas nas tags `validate` das requisições. Os campos são
+// identificados pelo nome no JSON, o mesmo informado pelo cliente
+var validate = newValidator()
+
+// newValidator cria o validador das requisições, nomeando os campos pela tag json
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	// As datas são validadas pelo time.Time que carregam, como nas regras required e gtefield
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.Interface().(DateTime).Time
+	}, DateTime{})
+	return v
+}
+
+// validateStruct valida a requisição pelas tags e agrega os erros de todos os campos em um único
+// ValidationErrors
+func validateStruct(req interface{}) error {
+	err := validate.Struct(req)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return err
+	}
+
+	errs := make([]*pkgErrors.ValidationError, 0, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		errs = append(errs, pkgErrors.NewValidationError(fieldPath(fieldError), fieldMessage(fieldError)))
+	}
+	return pkgErrors.NewValidationErrors(errs)
+}
+
+// fieldPath retorna o caminho do campo a partir da raiz da requisição (ex.: samples[0].days_diff)
+func fieldPath(fieldError validator.FieldError) string {
+	namespace := fieldError.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+// fieldMessage descreve a regra violada pelo campo
+func fieldMessage(fieldError validator.FieldError) string {
+	param := fieldError.Param()
+
+	switch fieldError.Tag() {
+	case "required":
+		return "campo obrigatório"
+	case "min":
+		if isCollection(fieldError.Kind()) {
+			return fmt.Sprintf("informe ao menos %s item(ns)", param)
+		}
+		return fmt.Sprintf("deve ter ao menos %s caractere(s)", param)
+	case "max":
+		if isCollection(fieldError.Kind()) {
+			return fmt.Sprintf("informe no máximo %s item(ns)", param)
+		}
+		return fmt.Sprintf("deve ter no máximo %s caractere(s)", param)
+	case "gt":
+		return fmt.Sprintf("deve ser maior que %s", param)
+	case "gte":
+		return fmt.Sprintf("deve ser maior ou igual a %s", param)
+	case "lte":
+		return fmt.Sprintf("deve ser menor ou igual a %s", param)
+	case "oneof":
+		return fmt.Sprintf("deve ser um de: %s", strings.Join(strings.Fields(param), ", "))
+	case "gtefield":
+		return fmt.Sprintf("não pode ser anterior a %s", snakeCase(param))
+	case "url":
+		return "deve ser uma URL absoluta"
+	case "datetime":
+		// O formato é o layout de data do Go; o único usado nas requisições é o de dia
+		if param == "2006-01-02" {
+			return "deve estar no formato AAAA-MM-DD"
+		}
+		return fmt.Sprintf("deve estar no formato %s", param)
+	default:
+		return fmt.Sprintf("valor inválido (%s)", fieldError.Tag())
+	}
+}
+
+// isCollection indica se as regras de tamanho do campo contam itens em vez de caracteres
+func isCollection(kind reflect.Kind) bool {
+	return kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map
+}
+
+// snakeCase converte o nome Go de um campo referenciado por outra regra (ex.: StartDate) para o
+// nome no JSON (start_date)
+func snakeCase(name string) string {
+	var snake strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				snake.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		snake.WriteRune(r)
+	}
+	return snake.String()
+}
@@ -0,0 +1,10 @@
+package response
+
+import "conciliacao-bancaria/internal/domain/model"
+
+// APIKeyCreatedResponse representa a resposta da criação ou rotação de uma API key.
+// O segredo só é exibido nesta resposta
+type APIKeyCreatedResponse struct {
+	*model.APIKey
+	Key string `json:"key"`
+}
@@ -1,18 +1,34 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
 
 // BilletResponse representa a estrutura de dados para a resposta de um boleto
 type BilletResponse struct {
-	BilletID      string    `json:"billet_id"`
-	BankAccount   string    `json:"bank_account"`
-	Amount        float64   `json:"amount"`
-	IssuanceDate  time.Time `json:"issuance_date"`
-	ReferenceID   *string   `json:"reference_id,omitempty"`
-	Status        string    `json:"status"`                   // Status atual do boleto (emitido, conciliado, cancelado, etc.)
-	TransactionID *string   `json:"transaction_id,omitempty"` // ID da transação relacionada, se conciliado
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	BilletID          string     `json:"billet_id"`
+	BankAccount       string     `json:"bank_account"`
+	Amount            float64    `json:"amount"`
+	IssuanceDate      time.Time  `json:"issuance_date"`
+	ReferenceID       *string    `json:"reference_id,omitempty"`
+	InstallmentNumber *int       `json:"installment_number,omitempty"` // Número da parcela em boletos de carnê
+	ContractID        *string    `json:"contract_id,omitempty"`
+	CustomerID        *string    `json:"customer_id,omitempty"`
+	OpenAmount        bool       `json:"open_amount,omitempty"`    // Boleto de valor aberto (depósito identificado)
+	BankCode          string     `json:"bank_code,omitempty"`      // Código do banco de cobrança
+	BarCode           string     `json:"bar_code,omitempty"`       // Código de barras do título
+	DigitableLine     string     `json:"digitable_line,omitempty"` // Linha digitável do título
+	PayerDocument     string     `json:"payer_document,omitempty"` // CPF ou CNPJ do pagador
+	PayerName         string     `json:"payer_name,omitempty"`     // Nome do pagador
+	DueDate           *time.Time `json:"due_date,omitempty"`       // Vencimento do boleto
+	InterestRate      float64    `json:"interest_rate,omitempty"`  // Juros de mora, em percentual ao mês
+	FinePercent       float64    `json:"fine_percent,omitempty"`   // Multa por atraso, em percentual do valor
+	Status            string     `json:"status"`                   // Status atual do boleto (emitido, conciliado, cancelado, etc.)
+	TransactionID     *string    `json:"transaction_id,omitempty"` // ID da transação relacionada, se conciliado
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
 }
 
 // BilletListResponse representa uma lista paginada de boletos para resposta
@@ -23,3 +39,30 @@ type BilletListResponse struct {
 	CurrentPage int              `json:"current_page"`
 	TotalPages  int              `json:"total_pages"`
 }
+
+// FromBilletDomain converte um boleto do domínio para a resposta da API
+func FromBilletDomain(billet *model.Billet) BilletResponse {
+	return BilletResponse{
+		BilletID:          billet.ID,
+		BankAccount:       billet.BankAccount,
+		Amount:            billet.Amount,
+		IssuanceDate:      billet.IssuanceDate,
+		ReferenceID:       billet.ReferenceID,
+		InstallmentNumber: billet.InstallmentNumber,
+		ContractID:        billet.ContractID,
+		CustomerID:        billet.CustomerID,
+		OpenAmount:        billet.OpenAmount,
+		BankCode:          billet.BankCode,
+		BarCode:           billet.BarCode,
+		DigitableLine:     billet.DigitableLine,
+		PayerDocument:     billet.PayerDocument,
+		PayerName:         billet.PayerName,
+		DueDate:           billet.DueDate,
+		InterestRate:      billet.InterestRate,
+		FinePercent:       billet.FinePercent,
+		Status:            string(billet.Status),
+		TransactionID:     billet.TransactionID,
+		CreatedAt:         billet.CreatedAt,
+		UpdatedAt:         billet.UpdatedAt,
+	}
+}
@@ -0,0 +1,29 @@
+package response
+
+import "conciliacao-bancaria/pkg/errors"
+
+// ValidationErrorResponse representa a resposta 422 de uma requisição com campos inválidos, com um
+// erro por regra violada
+type ValidationErrorResponse struct {
+	Message string               `json:"message"`
+	Errors  []FieldErrorResponse `json:"errors"`
+}
+
+// FieldErrorResponse representa o erro de validação de um campo da requisição
+type FieldErrorResponse struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FromValidationErrors converte os erros de validação dos campos para a resposta
+func FromValidationErrors(validationErrors *errors.ValidationErrors) ValidationErrorResponse {
+	fields := make([]FieldErrorResponse, 0, len(validationErrors.Errors))
+	for _, err := range validationErrors.Errors {
+		fields = append(fields, FieldErrorResponse{Field: err.Field, Message: err.Message})
+	}
+
+	return ValidationErrorResponse{
+		Message: "dados inválidos",
+		Errors:  fields,
+	}
+}
@@ -1,6 +1,10 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
 
 // PaymentResponse representa a estrutura de dados para a resposta de um pagamento
 type PaymentResponse struct {
@@ -9,8 +13,14 @@ type PaymentResponse struct {
 	Amount        float64   `json:"amount"`
 	PaymentDate   time.Time `json:"payment_date"`
 	ReferenceID   *string   `json:"reference_id,omitempty"`
-	Status        string    `json:"status"`              // Status atual do pagamento (recebido, conciliado, estornado, etc.)
-	BilletID      *string   `json:"billet_id,omitempty"` // ID do boleto relacionado, se conciliado
+	EntryType     string    `json:"entry_type"`
+	BankCode      string    `json:"bank_code,omitempty"`
+	BarCode       string    `json:"bar_code,omitempty"`       // Código de barras do título pago, devolvido pelo banco
+	PayerDocument string    `json:"payer_document,omitempty"` // CPF ou CNPJ de quem pagou
+	Description   string    `json:"description,omitempty"`    // Histórico do lançamento no extrato
+	PaymentMethod string    `json:"payment_method,omitempty"` // Meio de pagamento
+	Status        string    `json:"status"`                   // Status atual do pagamento (recebido, conciliado, estornado, etc.)
+	BilletID      *string   `json:"billet_id,omitempty"`      // ID do boleto relacionado, se conciliado
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 }
@@ -23,3 +33,24 @@ type PaymentListResponse struct {
 	CurrentPage int               `json:"current_page"`
 	TotalPages  int               `json:"total_pages"`
 }
+
+// FromPaymentDomain converte um pagamento do domínio para a resposta da API
+func FromPaymentDomain(payment *model.Payment) PaymentResponse {
+	return PaymentResponse{
+		TransactionID: payment.ID,
+		BankAccount:   payment.BankAccount,
+		Amount:        payment.Amount,
+		PaymentDate:   payment.PaymentDate,
+		ReferenceID:   payment.ReferenceID,
+		EntryType:     string(payment.EntryType),
+		BankCode:      payment.BankCode,
+		BarCode:       payment.BarCode,
+		PayerDocument: payment.PayerDocument,
+		Description:   payment.Description,
+		PaymentMethod: string(payment.PaymentMethod),
+		Status:        string(payment.Status),
+		BilletID:      payment.BilletID,
+		CreatedAt:     payment.CreatedAt,
+		UpdatedAt:     payment.UpdatedAt,
+	}
+}
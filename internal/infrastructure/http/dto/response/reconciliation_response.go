@@ -1,19 +1,66 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
 
 // ReconciliationItemResponse representa um item conciliado na resposta da API
 type ReconciliationItemResponse struct {
 	BilletID             string    `json:"billet_id"`
 	TransactionID        string    `json:"transaction_id"`
 	BankAccount          string    `json:"bank_account"`
-	ConciliationStatus   string    `json:"conciliation_status"`    // conciliado_com_sucesso, valor_diferente
+	ConciliationStatus   string    `json:"conciliation_status"`    // conciliado_com_sucesso, conciliado_com_juros, valor_diferente, sugerido
 	ConciliationStrategy string    `json:"conciliation_strategy"`  // reference_id, conta_valor_data
 	AmountDiff           float64   `json:"amount_diff"`            // Diferença de valor (se houver)
 	ReferenceID          *string   `json:"reference_id,omitempty"` // Quando utilizado na conciliação
+	Confidence           *float64  `json:"confidence,omitempty"`   // Confiança do pareamento por conta/valor/data
 	ReconciliationDate   time.Time `json:"reconciliation_date"`    // Data da conciliação
 }
 
+// FromReconciliationItemDomain converte uma conciliação do domínio para a resposta da API
+func FromReconciliationItemDomain(reconciliation *model.Reconciliation) ReconciliationItemResponse {
+	item := ReconciliationItemResponse{
+		BilletID:             reconciliation.BilletID,
+		BankAccount:          reconciliation.BankAccount,
+		ConciliationStatus:   string(reconciliation.ConciliationStatus),
+		ConciliationStrategy: string(reconciliation.ConciliationStrategy),
+		AmountDiff:           reconciliation.AmountDiff,
+		ReferenceID:          reconciliation.ReferenceID,
+		Confidence:           reconciliation.Confidence,
+		ReconciliationDate:   reconciliation.ReconciliationDate,
+	}
+
+	if reconciliation.TransactionID != nil {
+		item.TransactionID = *reconciliation.TransactionID
+	}
+
+	return item
+}
+
+// ReconciliationRunItemsResponse representa uma execução de conciliação com as conciliações gravadas por ela
+type ReconciliationRunItemsResponse struct {
+	Run   model.ReconciliationRun      `json:"run"`
+	Items []ReconciliationItemResponse `json:"items"`
+}
+
+// FromReconciliationRunItems converte a execução e suas conciliações para a resposta da API. O resultado
+// completo da execução não é repetido: os itens são as conciliações ainda gravadas
+func FromReconciliationRunItems(run *model.ReconciliationRun, items []*model.Reconciliation) ReconciliationRunItemsResponse {
+	resp := ReconciliationRunItemsResponse{
+		Run:   *run,
+		Items: make([]ReconciliationItemResponse, 0, len(items)),
+	}
+	resp.Run.Result = nil
+
+	for _, item := range items {
+		resp.Items = append(resp.Items, FromReconciliationItemDomain(item))
+	}
+
+	return resp
+}
+
 // NonReconciledBilletResponse representa um boleto não conciliado na resposta da API
 type NonReconciledBilletResponse struct {
 	BilletID     string    `json:"billet_id"`
@@ -50,6 +97,69 @@ type ReconciliationHistoryItem struct {
 	PairedWith           string    `json:"paired_with,omitempty"` // ID do boleto ou transação com o qual foi pareado
 	ConciliationStrategy string    `json:"conciliation_strategy,omitempty"`
 	AmountDiff           float64   `json:"amount_diff,omitempty"`
+	Confidence           *float64  `json:"confidence,omitempty"`
+	RunID                string    `json:"run_id,omitempty"` // Execução que gravou o registro
+}
+
+// FromBilletReconciliationHistory converte o histórico de conciliações de um boleto para a resposta da API
+func FromBilletReconciliationHistory(billetID string, history []*model.Reconciliation) ReconciliationHistoryResponse {
+	return fromReconciliationHistory(billetID, "boleto", history, func(reconciliation *model.Reconciliation) string {
+		if reconciliation.TransactionID != nil {
+			return *reconciliation.TransactionID
+		}
+		return ""
+	})
+}
+
+// FromPaymentReconciliationHistory converte o histórico de conciliações de um pagamento para a resposta da API
+func FromPaymentReconciliationHistory(transactionID string, history []*model.Reconciliation) ReconciliationHistoryResponse {
+	return fromReconciliationHistory(transactionID, "pagamento", history, func(reconciliation *model.Reconciliation) string {
+		return reconciliation.BilletID
+	})
+}
+
+// fromReconciliationHistory monta a resposta do histórico em ordem cronológica. O status atual é o da
+// conciliação mais recente com pareamento, ainda que sugerido, ou, sem nenhuma, o do registro mais recente
+func fromReconciliationHistory(
+	entityID string,
+	entityType string,
+	history []*model.Reconciliation,
+	pairedWith func(reconciliation *model.Reconciliation) string,
+) ReconciliationHistoryResponse {
+	resp := ReconciliationHistoryResponse{
+		EntityID:              entityID,
+		EntityType:            entityType,
+		CurrentStatus:         string(model.StatusNotReconciled),
+		ReconciliationHistory: make([]ReconciliationHistoryItem, 0, len(history)),
+	}
+
+	matched := false
+	for _, reconciliation := range history {
+		item := ReconciliationHistoryItem{
+			ReconciliationID:     reconciliation.ID,
+			ReconciliationDate:   reconciliation.ReconciliationDate,
+			Status:               string(reconciliation.ConciliationStatus),
+			PairedWith:           pairedWith(reconciliation),
+			ConciliationStrategy: string(reconciliation.ConciliationStrategy),
+			AmountDiff:           reconciliation.AmountDiff,
+			Confidence:           reconciliation.Confidence,
+		}
+		if reconciliation.RunID != nil {
+			item.RunID = *reconciliation.RunID
+		}
+		resp.ReconciliationHistory = append(resp.ReconciliationHistory, item)
+
+		isMatched := reconciliation.ConciliationStatus == model.StatusSuccessful ||
+			reconciliation.ConciliationStatus == model.StatusDifferentValue ||
+			reconciliation.ConciliationStatus == model.StatusPaidWithInterest ||
+			reconciliation.ConciliationStatus.IsPendingApproval()
+		if isMatched || !matched {
+			resp.CurrentStatus = item.Status
+			matched = matched || isMatched
+		}
+	}
+
+	return resp
 }
 
 // ReconciliationListResponse representa uma lista paginada de conciliações para resposta
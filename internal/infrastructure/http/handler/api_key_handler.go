@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/infrastructure/http/dto/request"
+	"conciliacao-bancaria/internal/infrastructure/http/dto/response"
+)
+
+// APIKeyHandler gerencia as requisições HTTP de administração das API keys
+type APIKeyHandler struct {
+	apiKeyUseCase *usecase.APIKeyUseCase
+}
+
+// NewAPIKeyHandler cria uma nova instância do APIKeyHandler
+func NewAPIKeyHandler(apiKeyUseCase *usecase.APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+// CreateAPIKey processa a requisição para criar uma API key, vinculada ao tenant da requisição
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req request.APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	apiKey, secret, err := h.apiKeyUseCase.CreateAPIKey(c.Request.Context(), requestTenant(c), req.Name, req.ToPermissionsDomain(), req.BankAccounts, req.ExpiresAt.Ptr())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.APIKeyCreatedResponse{APIKey: apiKey, Key: secret})
+}
+
+// ListAPIKeys processa a requisição para listar as API keys e seu último uso
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	apiKeys, err := h.apiKeyUseCase.ListAPIKeys(c.Request.Context(), requestTenant(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiKeys)
+}
+
+// GetAPIKey processa a requisição para obter uma API key
+func (h *APIKeyHandler) GetAPIKey(c *gin.Context) {
+	apiKeyID := c.Param("id")
+	if apiKeyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID da API key é obrigatório"})
+		return
+	}
+
+	apiKey, err := h.apiKeyUseCase.GetAPIKey(c.Request.Context(), requestTenant(c), apiKeyID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiKey)
+}
+
+// RotateAPIKey processa a requisição para emitir uma nova chave no lugar de uma existente
+func (h *APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	apiKeyID := c.Param("id")
+	if apiKeyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID da API key é obrigatório"})
+		return
+	}
+
+	var req request.RotateAPIKeyRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+			return
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	gracePeriod := time.Duration(req.GracePeriodSeconds) * time.Second
+	apiKey, secret, err := h.apiKeyUseCase.RotateAPIKey(c.Request.Context(), requestTenant(c), apiKeyID, gracePeriod)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.APIKeyCreatedResponse{APIKey: apiKey, Key: secret})
+}
+
+// RevokeAPIKey processa a requisição para revogar uma API key imediatamente
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	apiKeyID := c.Param("id")
+	if apiKeyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID da API key é obrigatório"})
+		return
+	}
+
+	if err := h.apiKeyUseCase.RevokeAPIKey(c.Request.Context(), requestTenant(c), apiKeyID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
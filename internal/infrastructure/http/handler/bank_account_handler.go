@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/infrastructure/http/dto/request"
+)
+
+// BankAccountHandler gerencia as requisições HTTP relacionadas ao cadastro de contas bancárias
+type BankAccountHandler struct {
+	bankAccountUseCase *usecase.BankAccountUseCase
+}
+
+// NewBankAccountHandler cria uma nova instância de BankAccountHandler
+func NewBankAccountHandler(bankAccountUseCase *usecase.BankAccountUseCase) *BankAccountHandler {
+	return &BankAccountHandler{
+		bankAccountUseCase: bankAccountUseCase,
+	}
+}
+
+// ListAccounts processa a requisição para listar as contas cadastradas
+func (h *BankAccountHandler) ListAccounts(c *gin.Context) {
+	accounts, err := h.bankAccountUseCase.ListAccounts(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, accounts)
+}
+
+// DeactivateAccount processa a requisição para inativar uma conta bancária
+func (h *BankAccountHandler) DeactivateAccount(c *gin.Context) {
+	account := c.Param("account")
+	if account == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Conta bancária é obrigatória"})
+		return
+	}
+
+	var req request.BankAccountStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	bankAccount, err := h.bankAccountUseCase.DeactivateAccount(c.Request.Context(), account, req.Reason, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bankAccount)
+}
+
+// ActivateAccount processa a requisição para reativar uma conta bancária
+func (h *BankAccountHandler) ActivateAccount(c *gin.Context) {
+	account := c.Param("account")
+	if account == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Conta bancária é obrigatória"})
+		return
+	}
+
+	bankAccount, err := h.bankAccountUseCase.ActivateAccount(c.Request.Context(), account, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bankAccount)
+}
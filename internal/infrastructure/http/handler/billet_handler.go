@@ -1,10 +1,12 @@
 package handler
 
 import (
-	"encoding/json"
 	"net/http"
 
+	"github.com/gin-gonic/gin"
+
 	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
 	"conciliacao-bancaria/internal/infrastructure/http/dto/request"
 	"conciliacao-bancaria/internal/infrastructure/http/dto/response"
 	"conciliacao-bancaria/pkg/errors"
@@ -23,63 +25,62 @@ func NewBilletHandler(billetUseCase *usecase.BilletUseCase) *BilletHandler {
 }
 
 // CreateBillet processa a requisição para criar um novo boleto
-func (h *BilletHandler) CreateBillet(w http.ResponseWriter, r *http.Request) {
+func (h *BilletHandler) CreateBillet(c *gin.Context) {
 	var req request.BilletRequest
-	err := json.NewDecoder(r.Body).Decode(&req)
+	err := c.ShouldBindJSON(&req)
 	if err != nil {
-		http.Error(w, "Erro ao decodificar requisição: "+err.Error(), http.StatusBadRequest)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
 		return
 	}
-	defer r.Body.Close()
 
 	// Validar requisição
 	if err := req.Validate(); err != nil {
-		http.Error(w, "Dados inválidos: "+err.Error(), http.StatusBadRequest)
+		handleError(c, err)
 		return
 	}
 
 	// Criar boleto através do caso de uso
-	billet, err := h.billetUseCase.CreateBillet(r.Context(), req.ToBilletDomain())
+	billet, err := h.billetUseCase.CreateBillet(c.Request.Context(), req.ToBilletDomain())
 	if err != nil {
-		handleError(w, err)
+		handleError(c, err)
 		return
 	}
 
 	// Converter para resposta e retornar
 	resp := response.FromBilletDomain(billet)
-	renderJSON(w, resp, http.StatusCreated)
+	c.JSON(http.StatusCreated, resp)
 }
 
-// GetBilletByID processa a requisição para buscar um boleto por ID
-func (h *BilletHandler) GetBilletByID(w http.ResponseWriter, r *http.Request) {
+// GetBillet processa a requisição para buscar um boleto por ID
+func (h *BilletHandler) GetBillet(c *gin.Context) {
 	// Extrair ID do boleto da URL
-	billetID := extractPathParam(r, "id")
+	billetID := c.Param("id")
 	if billetID == "" {
-		http.Error(w, "ID do boleto é obrigatório", http.StatusBadRequest)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do boleto é obrigatório"})
 		return
 	}
 
 	// Buscar boleto através do caso de uso
-	billet, err := h.billetUseCase.GetBilletByID(r.Context(), billetID)
+	billet, err := h.billetUseCase.GetBilletByID(c.Request.Context(), billetID)
 	if err != nil {
-		handleError(w, err)
+		handleError(c, err)
 		return
 	}
 
 	// Converter para resposta e retornar
 	resp := response.FromBilletDomain(billet)
-	renderJSON(w, resp, http.StatusOK)
+	c.JSON(http.StatusOK, resp)
 }
 
 // ListBillets processa a requisição para listar todos os boletos
-func (h *BilletHandler) ListBillets(w http.ResponseWriter, r *http.Request) {
+func (h *BilletHandler) ListBillets(c *gin.Context) {
 	// Extrair parâmetros de paginação e filtros (se necessário)
-	params := extractQueryParams(r)
+	params := extractQueryParams(c)
 
 	// Buscar boletos através do caso de uso
-	billets, err := h.billetUseCase.ListBillets(r.Context(), params)
+	billets, err := h.billetUseCase.ListBillets(c.Request.Context(), params)
 	if err != nil {
-		handleError(w, err)
+		handleError(c, err)
 		return
 	}
 
@@ -89,110 +90,174 @@ func (h *BilletHandler) ListBillets(w http.ResponseWriter, r *http.Request) {
 		resp = append(resp, response.FromBilletDomain(billet))
 	}
 
-	renderJSON(w, resp, http.StatusOK)
+	c.JSON(http.StatusOK, resp)
 }
 
-// ImportBillets processa a requisição para importar uma lista de boletos
-func (h *BilletHandler) ImportBillets(w http.ResponseWriter, r *http.Request) {
+// CreateBilletBatch processa a requisição para importar uma lista de boletos. Cada item é processado
+// individualmente (best_effort, padrão) ou em uma única transação (all_or_nothing), e a resposta
+// 207 Multi-Status traz o resultado de cada um pelo índice no lote
+func (h *BilletHandler) CreateBilletBatch(c *gin.Context) {
 	var req []request.BilletRequest
-	err := json.NewDecoder(r.Body).Decode(&req)
+	err := c.ShouldBindJSON(&req)
 	if err != nil {
-		http.Error(w, "Erro ao decodificar requisição: "+err.Error(), http.StatusBadRequest)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
 		return
 	}
-	defer r.Body.Close()
-
-	// Validar cada boleto na requisição
-	for i, billetReq := range req {
-		if err := billetReq.Validate(); err != nil {
-			http.Error(w, "Dados inválidos no boleto "+string(i)+": "+err.Error(), http.StatusBadRequest)
-			return
-		}
-	}
 
-	// Converter requisições para domínio
-	domainBillets := make([]interface{}, len(req))
+	// Converter requisições para domínio; a validação de cada item é feita pelo caso de uso
+	domainBillets := make([]*model.Billet, len(req))
 	for i, billetReq := range req {
 		domainBillets[i] = billetReq.ToBilletDomain()
 	}
 
 	// Importar boletos através do caso de uso
-	results, err := h.billetUseCase.ImportBillets(r.Context(), domainBillets)
+	// O modo de importação (all_or_nothing ou best_effort) é escolhido pelo parâmetro mode
+	results, err := h.billetUseCase.ImportBillets(c.Request.Context(), domainBillets, model.ImportMode(c.Query("mode")))
 	if err != nil {
-		handleError(w, err)
+		handleError(c, err)
 		return
 	}
 
-	// Converter para resposta e retornar
-	var resp struct {
-		Imported int      `json:"imported"`
-		Errors   []string `json:"errors,omitempty"`
+	c.JSON(batchStatus(results), results)
+}
+
+// UpdateBillet processa a requisição para atualizar um boleto. O ID vem da URL; quando informado
+// também no corpo, precisa ser o mesmo
+func (h *BilletHandler) UpdateBillet(c *gin.Context) {
+	billetID := c.Param("id")
+	if billetID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do boleto é obrigatório"})
+		return
 	}
-	resp.Imported = results.Imported
-	resp.Errors = results.Errors
 
-	renderJSON(w, resp, http.StatusOK)
+	var req request.BilletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if req.BilletID != "" && req.BilletID != billetID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "billet_id do corpo difere do ID da URL"})
+		return
+	}
+	req.BilletID = billetID
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	// Atualizar boleto através do caso de uso
+	billet, err := h.billetUseCase.UpdateBillet(c.Request.Context(), req.ToBilletDomain())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromBilletDomain(billet))
 }
 
 // DeleteBillet processa a requisição para excluir um boleto
-func (h *BilletHandler) DeleteBillet(w http.ResponseWriter, r *http.Request) {
+func (h *BilletHandler) DeleteBillet(c *gin.Context) {
 	// Extrair ID do boleto da URL
-	billetID := extractPathParam(r, "id")
+	billetID := c.Param("id")
 	if billetID == "" {
-		http.Error(w, "ID do boleto é obrigatório", http.StatusBadRequest)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do boleto é obrigatório"})
 		return
 	}
 
 	// Excluir boleto através do caso de uso
-	err := h.billetUseCase.DeleteBillet(r.Context(), billetID)
+	err := h.billetUseCase.DeleteBillet(c.Request.Context(), billetID)
 	if err != nil {
-		handleError(w, err)
+		handleError(c, err)
 		return
 	}
 
 	// Retornar sucesso sem conteúdo
-	w.WriteHeader(http.StatusNoContent)
+	c.Status(http.StatusNoContent)
+}
+
+// ListContractBillets processa a requisição para listar os boletos de um contrato
+func (h *BilletHandler) ListContractBillets(c *gin.Context) {
+	// Extrair ID do contrato da URL
+	contractID := c.Param("id")
+	if contractID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do contrato é obrigatório"})
+		return
+	}
+
+	// Buscar boletos através do caso de uso
+	billets, err := h.billetUseCase.GetBilletsByContract(c.Request.Context(), contractID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	// Converter para resposta e retornar
+	resp := make([]response.BilletResponse, 0, len(billets))
+	for _, billet := range billets {
+		resp = append(resp, response.FromBilletDomain(billet))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetContractStatistics processa a requisição para obter as estatísticas de conciliação de um contrato
+func (h *BilletHandler) GetContractStatistics(c *gin.Context) {
+	// Extrair ID do contrato da URL
+	contractID := c.Param("id")
+	if contractID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do contrato é obrigatório"})
+		return
+	}
+
+	// Calcular estatísticas através do caso de uso
+	stats, err := h.billetUseCase.GetContractStatistics(c.Request.Context(), contractID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
 }
 
 // handleError trata os diversos tipos de erro e define o status HTTP adequado
-func handleError(w http.ResponseWriter, err error) {
+func handleError(c *gin.Context, err error) {
+	// Acesso fora do escopo de contas pode chegar encapsulado em erros de banco de dados
+	if errors.IsForbiddenError(err) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
 	switch e := err.(type) {
 	case *errors.NotFoundError:
-		http.Error(w, e.Error(), http.StatusNotFound)
+		c.JSON(http.StatusNotFound, gin.H{"error": e.Error()})
 	case *errors.ValidationError:
-		http.Error(w, e.Error(), http.StatusBadRequest)
+		c.JSON(http.StatusBadRequest, gin.H{"error": e.Error()})
+	case *errors.ValidationErrors:
+		c.JSON(http.StatusUnprocessableEntity, response.FromValidationErrors(e))
 	case *errors.ConflictError:
-		http.Error(w, e.Error(), http.StatusConflict)
+		c.JSON(http.StatusConflict, gin.H{"error": e.Error()})
 	default:
-		http.Error(w, "Erro interno do servidor: "+err.Error(), http.StatusInternalServerError)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor: " + err.Error()})
 	}
 }
 
-// renderJSON serializa uma resposta para JSON e escreve no ResponseWriter
-func renderJSON(w http.ResponseWriter, data interface{}, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	if data != nil {
-		if err := json.NewEncoder(w).Encode(data); err != nil {
-			http.Error(w, "Erro ao codificar resposta: "+err.Error(), http.StatusInternalServerError)
-			return
-		}
+// batchStatus retorna 207 Multi-Status com o resultado por item, ou 422 quando uma importação
+// all_or_nothing foi abortada
+func batchStatus(result *usecase.ImportResult) int {
+	if result.Aborted {
+		return http.StatusUnprocessableEntity
 	}
-}
-
-// extractPathParam extrai um parâmetro da URL
-func extractPathParam(r *http.Request, param string) string {
-	// Esta função depende da implementação do router usado (como gorilla/mux, chi, etc.)
-	// Portanto, é uma função básica que deve ser adaptada conforme o router escolhido
-	return r.PathValue(param) // Usando PathValue do net/http a partir do Go 1.22
+	return http.StatusMultiStatus
 }
 
 // extractQueryParams extrai parâmetros de consulta da URL
-func extractQueryParams(r *http.Request) map[string]string {
+func extractQueryParams(c *gin.Context) map[string]string {
 	params := make(map[string]string)
 
 	// Extrair parâmetros comuns como paginação, ordenação, etc.
-	query := r.URL.Query()
+	query := c.Request.URL.Query()
 
 	// Exemplo de parâmetros que podem ser úteis para listagem
 	if limit := query.Get("limit"); limit != "" {
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/infrastructure/http/dto/request"
+	"conciliacao-bancaria/internal/infrastructure/report"
+)
+
+// ClosingHandler gerencia as requisições HTTP relacionadas aos fechamentos diários
+type ClosingHandler struct {
+	closingUseCase *usecase.ClosingUseCase
+	template       report.Template
+}
+
+// NewClosingHandler cria uma nova instância de ClosingHandler com o layout do relatório do fechamento
+func NewClosingHandler(closingUseCase *usecase.ClosingUseCase, template report.Template) *ClosingHandler {
+	return &ClosingHandler{
+		closingUseCase: closingUseCase,
+		template:       template,
+	}
+}
+
+// OpenClosing processa a requisição para abrir o fechamento de um dia
+func (h *ClosingHandler) OpenClosing(c *gin.Context) {
+	var req request.DailyClosingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	closing, err := h.closingUseCase.OpenClosing(c.Request.Context(), req.Date, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, closing)
+}
+
+// ListClosings processa a requisição para listar os fechamentos diários
+func (h *ClosingHandler) ListClosings(c *gin.Context) {
+	closings, err := h.closingUseCase.ListClosings(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, closings)
+}
+
+// GetClosing processa a requisição para obter um fechamento diário
+func (h *ClosingHandler) GetClosing(c *gin.Context) {
+	closingID := c.Param("id")
+	if closingID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do fechamento é obrigatório"})
+		return
+	}
+
+	closing, err := h.closingUseCase.GetClosing(c.Request.Context(), closingID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, closing)
+}
+
+// ConfirmClosing processa a requisição para confirmar um fechamento, congelando os resultados do dia
+func (h *ClosingHandler) ConfirmClosing(c *gin.Context) {
+	closingID := c.Param("id")
+	if closingID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do fechamento é obrigatório"})
+		return
+	}
+
+	closing, err := h.closingUseCase.ConfirmClosing(c.Request.Context(), closingID, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, closing)
+}
+
+// ExportClosingReport processa a requisição para exportar os totais do fechamento no layout do relatório regulatório
+func (h *ClosingHandler) ExportClosingReport(c *gin.Context) {
+	closingID := c.Param("id")
+	if closingID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do fechamento é obrigatório"})
+		return
+	}
+
+	closing, err := h.closingUseCase.GetClosing(c.Request.Context(), closingID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="fechamento_%s.csv"`, closing.Date.Format("2006-01-02")))
+	c.Status(http.StatusOK)
+
+	if err := report.WriteCSV(c.Writer, h.template, closing.Statistics); err != nil {
+		log.Printf("erro ao escrever relatório do fechamento: %v", err)
+	}
+}
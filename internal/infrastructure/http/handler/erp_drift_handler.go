@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+)
+
+// ERPDriftHandler gerencia as requisições HTTP da verificação de divergências entre o ERP e a base local
+type ERPDriftHandler struct {
+	erpDriftUseCase *usecase.ERPDriftUseCase
+}
+
+// NewERPDriftHandler cria uma nova instância de ERPDriftHandler
+func NewERPDriftHandler(erpDriftUseCase *usecase.ERPDriftUseCase) *ERPDriftHandler {
+	return &ERPDriftHandler{
+		erpDriftUseCase: erpDriftUseCase,
+	}
+}
+
+// CheckDrift processa a requisição para comparar imediatamente os boletos em aberto no ERP com os
+// pendentes da base local, fora do agendamento do job
+func (h *ERPDriftHandler) CheckDrift(c *gin.Context) {
+	if !h.erpDriftUseCase.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "API do ERP não configurada (ERP_OPEN_BILLETS_URL)"})
+		return
+	}
+
+	report, err := h.erpDriftUseCase.CheckDrift(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ListReports processa a requisição para listar os relatórios das verificações mais recentes (limit)
+func (h *ERPDriftHandler) ListReports(c *gin.Context) {
+	var limit int
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit deve ser um número inteiro"})
+			return
+		}
+		limit = parsed
+	}
+
+	reports, err := h.erpDriftUseCase.ListReports(c.Request.Context(), limit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+// GetLatestReport processa a requisição para obter o relatório da verificação mais recente
+func (h *ERPDriftHandler) GetLatestReport(c *gin.Context) {
+	report, err := h.erpDriftUseCase.GetLatest(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if report == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Nenhuma verificação de divergências com o ERP realizada"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+)
+
+// ERPSyncHandler gerencia as requisições HTTP da importação delta de boletos do ERP
+type ERPSyncHandler struct {
+	erpSyncUseCase *usecase.ERPSyncUseCase
+}
+
+// NewERPSyncHandler cria uma nova instância de ERPSyncHandler
+func NewERPSyncHandler(erpSyncUseCase *usecase.ERPSyncUseCase) *ERPSyncHandler {
+	return &ERPSyncHandler{
+		erpSyncUseCase: erpSyncUseCase,
+	}
+}
+
+// Sync processa a requisição para importar imediatamente os boletos criados ou alterados no ERP desde o
+// último cursor, fora do intervalo do job
+func (h *ERPSyncHandler) Sync(c *gin.Context) {
+	if !h.erpSyncUseCase.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "API do ERP não configurada (ERP_CHANGED_BILLETS_URL)"})
+		return
+	}
+
+	state, err := h.erpSyncUseCase.Sync(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, state)
+}
+
+// GetState processa a requisição para obter o cursor e o resultado da última sincronização
+func (h *ERPSyncHandler) GetState(c *gin.Context) {
+	state, err := h.erpSyncUseCase.GetState(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if state == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Nenhuma sincronização de boletos com o ERP realizada"})
+		return
+	}
+
+	c.JSON(http.StatusOK, state)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/infrastructure/http/dto/request"
+)
+
+// ExportHandler gerencia as requisições HTTP relacionadas aos arquivos de resultado enviados ao ERP
+type ExportHandler struct {
+	exportUseCase *usecase.ExportUseCase
+}
+
+// NewExportHandler cria uma nova instância de ExportHandler
+func NewExportHandler(exportUseCase *usecase.ExportUseCase) *ExportHandler {
+	return &ExportHandler{
+		exportUseCase: exportUseCase,
+	}
+}
+
+// ExportRun processa a requisição para gerar e enviar os arquivos de resultado de uma execução
+func (h *ExportHandler) ExportRun(c *gin.Context) {
+	var req request.ExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	exports, err := h.exportUseCase.ExportRunByID(c.Request.Context(), req.RunID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, exports)
+}
+
+// ListExports processa a requisição para listar os arquivos de resultado, filtrados por run_id
+func (h *ExportHandler) ListExports(c *gin.Context) {
+	exports, err := h.exportUseCase.ListExports(c.Request.Context(), c.Query("run_id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, exports)
+}
+
+// GetExport processa a requisição para obter o registro de envio de um arquivo de resultado
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	exportID := c.Param("id")
+	if exportID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do arquivo é obrigatório"})
+		return
+	}
+
+	export, err := h.exportUseCase.GetExport(c.Request.Context(), exportID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// DownloadExport processa a requisição para baixar o conteúdo enviado de um arquivo de resultado
+func (h *ExportHandler) DownloadExport(c *gin.Context) {
+	exportID := c.Param("id")
+	if exportID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do arquivo é obrigatório"})
+		return
+	}
+
+	export, err := h.exportUseCase.GetExport(c.Request.Context(), exportID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.FileName))
+	c.Data(http.StatusOK, "application/octet-stream", export.Content)
+}
+
+// ResendExport processa a requisição para reenviar manualmente um arquivo de resultado
+func (h *ExportHandler) ResendExport(c *gin.Context) {
+	exportID := c.Param("id")
+	if exportID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do arquivo é obrigatório"})
+		return
+	}
+
+	export, err := h.exportUseCase.ResendExport(c.Request.Context(), exportID, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
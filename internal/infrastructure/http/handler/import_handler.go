@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/infrastructure/http/middleware"
+	"conciliacao-bancaria/internal/infrastructure/importer"
+)
+
+// FileNameHeader identifica o nome original do arquivo bancário enviado no corpo da requisição
+const FileNameHeader = "X-File-Name"
+
+// ImportHandler gerencia as requisições HTTP relacionadas à importação de arquivos bancários
+type ImportHandler struct {
+	importUseCase *usecase.ImportUseCase
+	limits        importer.Limits
+}
+
+// NewImportHandler cria uma nova instância do ImportHandler
+func NewImportHandler(importUseCase *usecase.ImportUseCase, limits importer.Limits) *ImportHandler {
+	return &ImportHandler{
+		importUseCase: importUseCase,
+		limits:        limits,
+	}
+}
+
+// ImportCNAB processa a requisição para importar um arquivo CNAB 240 de retorno enviado no corpo
+func (h *ImportHandler) ImportCNAB(c *gin.Context) {
+	file, err := importer.ParseCNAB240(c.Request.Body, h.limits)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao processar arquivo CNAB: " + err.Error()})
+		return
+	}
+
+	// As linhas do arquivo contam na quota de importação do tenant
+	payments := file.Payments()
+	if !middleware.ConsumeImportRows(c, len(payments)) {
+		return
+	}
+
+	// O modo de importação (all_or_nothing ou best_effort) é escolhido pelo parâmetro mode
+	mode := model.ImportMode(c.Query("mode"))
+	result, err := h.importUseCase.ImportFile(c.Request.Context(), file.ImportFile(c.GetHeader(FileNameHeader)), payments, mode)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// ImportStatement processa a requisição para importar um extrato bancário enviado no corpo, em CSV
+// (Content-Type text/csv) ou OFX (application/x-ofx, application/ofx ou XML)
+func (h *ImportHandler) ImportStatement(c *gin.Context) {
+	var file *model.ImportFile
+	var payments []*model.Payment
+
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		statement, err := importer.ParseCSV(c.Request.Body, h.limits)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao processar extrato CSV: " + err.Error()})
+			return
+		}
+		file, payments = statement.ImportFile(c.GetHeader(FileNameHeader)), statement.Payments()
+	case "application/x-ofx", "application/ofx", "application/xml", "text/xml":
+		statement, err := importer.ParseOFX(c.Request.Body, h.limits)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao processar extrato OFX: " + err.Error()})
+			return
+		}
+		file, payments = statement.ImportFile(c.GetHeader(FileNameHeader)), statement.Payments()
+	default:
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Formato de extrato não suportado: use text/csv ou application/x-ofx"})
+		return
+	}
+
+	// Os lançamentos do extrato contam na quota de importação do tenant
+	if !middleware.ConsumeImportRows(c, len(payments)) {
+		return
+	}
+
+	// O modo de importação (all_or_nothing ou best_effort) é escolhido pelo parâmetro mode
+	mode := model.ImportMode(c.Query("mode"))
+	result, err := h.importUseCase.ImportStatement(c.Request.Context(), file, payments, mode)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// ListImportFiles processa a requisição para listar os arquivos importados
+func (h *ImportHandler) ListImportFiles(c *gin.Context) {
+	files, err := h.importUseCase.ListImportFiles(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, files)
+}
+
+// ListGaps processa a requisição para listar as lacunas de numeração sequencial dos arquivos
+func (h *ImportHandler) ListGaps(c *gin.Context) {
+	gaps, err := h.importUseCase.ListGaps(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gaps)
+}
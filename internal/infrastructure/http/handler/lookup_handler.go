@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/infrastructure/http/dto/request"
+)
+
+// LookupHandler gerencia as requisições HTTP de consulta em lote de boletos e pagamentos
+type LookupHandler struct {
+	lookupUseCase *usecase.LookupUseCase
+}
+
+// NewLookupHandler cria uma nova instância de LookupHandler
+func NewLookupHandler(lookupUseCase *usecase.LookupUseCase) *LookupHandler {
+	return &LookupHandler{
+		lookupUseCase: lookupUseCase,
+	}
+}
+
+// LookupBillets processa a requisição para consultar boletos por uma lista de IDs
+func (h *LookupHandler) LookupBillets(c *gin.Context) {
+	var req request.LookupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	result, err := h.lookupUseCase.LookupBillets(c.Request.Context(), req.IDs)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// LookupPayments processa a requisição para consultar pagamentos por uma lista de IDs
+func (h *LookupHandler) LookupPayments(c *gin.Context) {
+	var req request.LookupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	result, err := h.lookupUseCase.LookupPayments(c.Request.Context(), req.IDs)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+)
+
+// MaintenanceHandler gerencia as requisições HTTP dos relatórios da rotina de manutenção
+type MaintenanceHandler struct {
+	maintenanceUseCase *usecase.MaintenanceUseCase
+}
+
+// NewMaintenanceHandler cria uma nova instância de MaintenanceHandler
+func NewMaintenanceHandler(maintenanceUseCase *usecase.MaintenanceUseCase) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenanceUseCase: maintenanceUseCase,
+	}
+}
+
+// ListReports processa a requisição para listar os relatórios das execuções mais recentes da
+// manutenção (limit), com a política de retenção aplicada e o que foi expurgado ou anonimizado
+func (h *MaintenanceHandler) ListReports(c *gin.Context) {
+	var limit int
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit deve ser um número inteiro"})
+			return
+		}
+		limit = parsed
+	}
+
+	reports, err := h.maintenanceUseCase.ListReports(c.Request.Context(), limit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/infrastructure/http/dto/request"
+)
+
+// NotificationTemplateHandler gerencia as requisições HTTP relacionadas aos templates de notificação
+type NotificationTemplateHandler struct {
+	templateUseCase *usecase.NotificationTemplateUseCase
+}
+
+// NewNotificationTemplateHandler cria uma nova instância de NotificationTemplateHandler
+func NewNotificationTemplateHandler(templateUseCase *usecase.NotificationTemplateUseCase) *NotificationTemplateHandler {
+	return &NotificationTemplateHandler{
+		templateUseCase: templateUseCase,
+	}
+}
+
+// ListTemplates processa a requisição para listar a versão aplicada dos templates do tenant
+func (h *NotificationTemplateHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.templateUseCase.ListTemplates(c.Request.Context(), requestTenant(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// SaveTemplate processa a requisição para gravar uma nova versão do template de um tipo de evento
+func (h *NotificationTemplateHandler) SaveTemplate(c *gin.Context) {
+	var req request.NotificationTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	eventType := model.EventType(c.Param("event_type"))
+	notificationTemplate, err := h.templateUseCase.SaveTemplate(c.Request.Context(), requestTenant(c), eventType, req.Subject, req.Body, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, notificationTemplate)
+}
+
+// ListVersions processa a requisição para listar as versões do template de um tipo de evento
+func (h *NotificationTemplateHandler) ListVersions(c *gin.Context) {
+	eventType := model.EventType(c.Param("event_type"))
+	templates, err := h.templateUseCase.ListVersions(c.Request.Context(), requestTenant(c), eventType)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// RestoreVersion processa a requisição para restaurar uma versão anterior do template de um tipo de
+// evento, gravada como uma nova versão
+func (h *NotificationTemplateHandler) RestoreVersion(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version deve ser um número inteiro"})
+		return
+	}
+
+	eventType := model.EventType(c.Param("event_type"))
+	notificationTemplate, err := h.templateUseCase.RestoreVersion(c.Request.Context(), requestTenant(c), eventType, version, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, notificationTemplate)
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/infrastructure/http/dto/request"
+)
+
+// OutboxHandler gerencia as requisições HTTP de operação do outbox de entregas de eventos
+type OutboxHandler struct {
+	outboxUseCase *usecase.OutboxUseCase
+}
+
+// NewOutboxHandler cria uma nova instância do OutboxHandler
+func NewOutboxHandler(outboxUseCase *usecase.OutboxUseCase) *OutboxHandler {
+	return &OutboxHandler{
+		outboxUseCase: outboxUseCase,
+	}
+}
+
+// ListDeliveries processa a requisição para listar as entregas do outbox, filtradas por
+// situação (status=pendente,falha) e limitadas por limit
+func (h *OutboxHandler) ListDeliveries(c *gin.Context) {
+	var statuses []model.DeliveryStatus
+	if value := c.Query("status"); value != "" {
+		for _, status := range strings.Split(value, ",") {
+			statuses = append(statuses, model.DeliveryStatus(strings.TrimSpace(status)))
+		}
+	}
+
+	var limit int
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit deve ser um número inteiro"})
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries, err := h.outboxUseCase.ListDeliveries(c.Request.Context(), statuses, limit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// GetDelivery processa a requisição para obter uma entrega do outbox
+func (h *OutboxHandler) GetDelivery(c *gin.Context) {
+	deliveryID := c.Param("id")
+	if deliveryID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID da entrega é obrigatório"})
+		return
+	}
+
+	delivery, err := h.outboxUseCase.GetDelivery(c.Request.Context(), deliveryID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// RequeueDeliveries processa a requisição para reenfileirar entregas pendentes ou com falha em lote
+func (h *OutboxHandler) RequeueDeliveries(c *gin.Context) {
+	var req request.RequeueDeliveriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	requeued, err := h.outboxUseCase.RequeueDeliveries(c.Request.Context(), req.IDs)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]int{"requested": len(req.IDs), "requeued": requeued})
+}
+
+// DiscardDelivery processa a requisição para descartar uma entrega com o motivo informado
+func (h *OutboxHandler) DiscardDelivery(c *gin.Context) {
+	deliveryID := c.Param("id")
+	if deliveryID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID da entrega é obrigatório"})
+		return
+	}
+
+	var req request.DiscardDeliveryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	delivery, err := h.outboxUseCase.DiscardDelivery(c.Request.Context(), deliveryID, req.Reason)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// GetMetrics processa a requisição para obter as métricas de atraso de publicação, na janela
+// informada em window (duração Go, ex.: 1h); o padrão são as últimas 24 horas
+func (h *OutboxHandler) GetMetrics(c *gin.Context) {
+	var window time.Duration
+	if value := c.Query("window"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "window deve ser uma duração válida (ex.: 1h, 30m)"})
+			return
+		}
+		window = parsed
+	}
+
+	metrics, err := h.outboxUseCase.GetMetrics(c.Request.Context(), window)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
-	"encoding/json"
 	"net/http"
 
+	"github.com/gin-gonic/gin"
+
 	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
 	"conciliacao-bancaria/internal/infrastructure/http/dto/request"
 	"conciliacao-bancaria/internal/infrastructure/http/dto/response"
 )
@@ -22,63 +24,62 @@ func NewPaymentHandler(paymentUseCase *usecase.PaymentUseCase) *PaymentHandler {
 }
 
 // CreatePayment processa a requisição para criar um novo pagamento
-func (h *PaymentHandler) CreatePayment(w http.ResponseWriter, r *http.Request) {
+func (h *PaymentHandler) CreatePayment(c *gin.Context) {
 	var req request.PaymentRequest
-	err := json.NewDecoder(r.Body).Decode(&req)
+	err := c.ShouldBindJSON(&req)
 	if err != nil {
-		http.Error(w, "Erro ao decodificar requisição: "+err.Error(), http.StatusBadRequest)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
 		return
 	}
-	defer r.Body.Close()
 
 	// Validar requisição
 	if err := req.Validate(); err != nil {
-		http.Error(w, "Dados inválidos: "+err.Error(), http.StatusBadRequest)
+		handleError(c, err)
 		return
 	}
 
 	// Criar pagamento através do caso de uso
-	payment, err := h.paymentUseCase.CreatePayment(r.Context(), req.ToPaymentDomain())
+	payment, err := h.paymentUseCase.CreatePayment(c.Request.Context(), req.ToPaymentDomain())
 	if err != nil {
-		handleError(w, err)
+		handleError(c, err)
 		return
 	}
 
 	// Converter para resposta e retornar
 	resp := response.FromPaymentDomain(payment)
-	renderJSON(w, resp, http.StatusCreated)
+	c.JSON(http.StatusCreated, resp)
 }
 
-// GetPaymentByID processa a requisição para buscar um pagamento por ID
-func (h *PaymentHandler) GetPaymentByID(w http.ResponseWriter, r *http.Request) {
+// GetPayment processa a requisição para buscar um pagamento por ID
+func (h *PaymentHandler) GetPayment(c *gin.Context) {
 	// Extrair ID do pagamento da URL
-	paymentID := extractPathParam(r, "id")
+	paymentID := c.Param("id")
 	if paymentID == "" {
-		http.Error(w, "ID do pagamento é obrigatório", http.StatusBadRequest)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do pagamento é obrigatório"})
 		return
 	}
 
 	// Buscar pagamento através do caso de uso
-	payment, err := h.paymentUseCase.GetPaymentByID(r.Context(), paymentID)
+	payment, err := h.paymentUseCase.GetPaymentByID(c.Request.Context(), paymentID)
 	if err != nil {
-		handleError(w, err)
+		handleError(c, err)
 		return
 	}
 
 	// Converter para resposta e retornar
 	resp := response.FromPaymentDomain(payment)
-	renderJSON(w, resp, http.StatusOK)
+	c.JSON(http.StatusOK, resp)
 }
 
 // ListPayments processa a requisição para listar todos os pagamentos
-func (h *PaymentHandler) ListPayments(w http.ResponseWriter, r *http.Request) {
+func (h *PaymentHandler) ListPayments(c *gin.Context) {
 	// Extrair parâmetros de paginação e filtros
-	params := extractPaymentQueryParams(r)
+	params := extractPaymentQueryParams(c)
 
 	// Buscar pagamentos através do caso de uso
-	payments, err := h.paymentUseCase.ListPayments(r.Context(), params)
+	payments, err := h.paymentUseCase.ListPayments(c.Request.Context(), params)
 	if err != nil {
-		handleError(w, err)
+		handleError(c, err)
 		return
 	}
 
@@ -88,127 +89,99 @@ func (h *PaymentHandler) ListPayments(w http.ResponseWriter, r *http.Request) {
 		resp = append(resp, response.FromPaymentDomain(payment))
 	}
 
-	renderJSON(w, resp, http.StatusOK)
+	c.JSON(http.StatusOK, resp)
 }
 
-// ImportPayments processa a requisição para importar uma lista de pagamentos
-func (h *PaymentHandler) ImportPayments(w http.ResponseWriter, r *http.Request) {
+// CreatePaymentBatch processa a requisição para importar uma lista de pagamentos. Cada item é processado
+// individualmente (best_effort, padrão) ou em uma única transação (all_or_nothing), e a resposta
+// 207 Multi-Status traz o resultado de cada um pelo índice no lote
+func (h *PaymentHandler) CreatePaymentBatch(c *gin.Context) {
 	var req []request.PaymentRequest
-	err := json.NewDecoder(r.Body).Decode(&req)
+	err := c.ShouldBindJSON(&req)
 	if err != nil {
-		http.Error(w, "Erro ao decodificar requisição: "+err.Error(), http.StatusBadRequest)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
 		return
 	}
-	defer r.Body.Close()
-
-	// Validar cada pagamento na requisição
-	for i, paymentReq := range req {
-		if err := paymentReq.Validate(); err != nil {
-			http.Error(w, "Dados inválidos no pagamento "+string(i)+": "+err.Error(), http.StatusBadRequest)
-			return
-		}
-	}
 
-	// Converter requisições para domínio
-	domainPayments := make([]interface{}, len(req))
+	// Converter requisições para domínio; a validação de cada item é feita pelo caso de uso
+	domainPayments := make([]*model.Payment, len(req))
 	for i, paymentReq := range req {
 		domainPayments[i] = paymentReq.ToPaymentDomain()
 	}
 
 	// Importar pagamentos através do caso de uso
-	results, err := h.paymentUseCase.ImportPayments(r.Context(), domainPayments)
+	// O modo de importação (all_or_nothing ou best_effort) é escolhido pelo parâmetro mode
+	results, err := h.paymentUseCase.ImportPayments(c.Request.Context(), domainPayments, model.ImportMode(c.Query("mode")))
 	if err != nil {
-		handleError(w, err)
+		handleError(c, err)
 		return
 	}
 
-	// Converter para resposta e retornar
-	var resp struct {
-		Imported int      `json:"imported"`
-		Errors   []string `json:"errors,omitempty"`
-	}
-	resp.Imported = results.Imported
-	resp.Errors = results.Errors
-
-	renderJSON(w, resp, http.StatusOK)
+	c.JSON(batchStatus(results), results)
 }
 
-// GetPaymentsByBankAccount processa a requisição para buscar pagamentos por conta bancária
-func (h *PaymentHandler) GetPaymentsByBankAccount(w http.ResponseWriter, r *http.Request) {
-	// Extrair conta bancária da URL
-	bankAccount := extractPathParam(r, "bank_account")
-	if bankAccount == "" {
-		http.Error(w, "Conta bancária é obrigatória", http.StatusBadRequest)
+// UpdatePayment processa a requisição para atualizar um pagamento. O ID vem da URL; quando informado
+// também no corpo, precisa ser o mesmo
+func (h *PaymentHandler) UpdatePayment(c *gin.Context) {
+	paymentID := c.Param("id")
+	if paymentID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do pagamento é obrigatório"})
 		return
 	}
 
-	// Buscar pagamentos através do caso de uso
-	payments, err := h.paymentUseCase.GetPaymentsByBankAccount(r.Context(), bankAccount)
-	if err != nil {
-		handleError(w, err)
+	var req request.PaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
 		return
 	}
 
-	// Converter para resposta e retornar
-	var resp []response.PaymentResponse
-	for _, payment := range payments {
-		resp = append(resp, response.FromPaymentDomain(payment))
+	if req.TransactionID != "" && req.TransactionID != paymentID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "transaction_id do corpo difere do ID da URL"})
+		return
 	}
+	req.TransactionID = paymentID
 
-	renderJSON(w, resp, http.StatusOK)
-}
-
-// GetPaymentsByReferenceID processa a requisição para buscar pagamentos por referenceID
-func (h *PaymentHandler) GetPaymentsByReferenceID(w http.ResponseWriter, r *http.Request) {
-	// Extrair referenceID da URL
-	referenceID := extractPathParam(r, "reference_id")
-	if referenceID == "" {
-		http.Error(w, "ID de referência é obrigatório", http.StatusBadRequest)
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
 		return
 	}
 
-	// Buscar pagamentos através do caso de uso
-	payments, err := h.paymentUseCase.GetPaymentsByReferenceID(r.Context(), referenceID)
+	// Atualizar pagamento através do caso de uso
+	payment, err := h.paymentUseCase.UpdatePayment(c.Request.Context(), req.ToPaymentDomain())
 	if err != nil {
-		handleError(w, err)
+		handleError(c, err)
 		return
 	}
 
-	// Converter para resposta e retornar
-	var resp []response.PaymentResponse
-	for _, payment := range payments {
-		resp = append(resp, response.FromPaymentDomain(payment))
-	}
-
-	renderJSON(w, resp, http.StatusOK)
+	c.JSON(http.StatusOK, response.FromPaymentDomain(payment))
 }
 
 // DeletePayment processa a requisição para excluir um pagamento
-func (h *PaymentHandler) DeletePayment(w http.ResponseWriter, r *http.Request) {
+func (h *PaymentHandler) DeletePayment(c *gin.Context) {
 	// Extrair ID do pagamento da URL
-	paymentID := extractPathParam(r, "id")
+	paymentID := c.Param("id")
 	if paymentID == "" {
-		http.Error(w, "ID do pagamento é obrigatório", http.StatusBadRequest)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do pagamento é obrigatório"})
 		return
 	}
 
 	// Excluir pagamento através do caso de uso
-	err := h.paymentUseCase.DeletePayment(r.Context(), paymentID)
+	err := h.paymentUseCase.DeletePayment(c.Request.Context(), paymentID)
 	if err != nil {
-		handleError(w, err)
+		handleError(c, err)
 		return
 	}
 
 	// Retornar sucesso sem conteúdo
-	w.WriteHeader(http.StatusNoContent)
+	c.Status(http.StatusNoContent)
 }
 
 // extractPaymentQueryParams extrai parâmetros de consulta específicos para pagamentos
-func extractPaymentQueryParams(r *http.Request) map[string]string {
+func extractPaymentQueryParams(c *gin.Context) map[string]string {
 	params := make(map[string]string)
 
 	// Extrair parâmetros comuns como paginação, ordenação, etc.
-	query := r.URL.Query()
+	query := c.Request.URL.Query()
 
 	// Exemplo de parâmetros que podem ser úteis para listagem de pagamentos
 	if limit := query.Get("limit"); limit != "" {
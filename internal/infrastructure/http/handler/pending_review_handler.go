@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+)
+
+// PendingReviewHandler gerencia as requisições HTTP da série diária de pendências
+type PendingReviewHandler struct {
+	pendingReviewUseCase *usecase.PendingReviewUseCase
+}
+
+// NewPendingReviewHandler cria uma nova instância de PendingReviewHandler
+func NewPendingReviewHandler(pendingReviewUseCase *usecase.PendingReviewUseCase) *PendingReviewHandler {
+	return &PendingReviewHandler{
+		pendingReviewUseCase: pendingReviewUseCase,
+	}
+}
+
+// GetPendingSeries processa a requisição para obter a série diária de pendências gravada pelo job
+// noturno, com os boletos que saíram e entraram nas pendências a cada dia
+func (h *PendingReviewHandler) GetPendingSeries(c *gin.Context) {
+	params := extractReconciliationQueryParams(c)
+
+	series, err := h.pendingReviewUseCase.GetSeries(c.Request.Context(), params)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
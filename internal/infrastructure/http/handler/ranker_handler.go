@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/infrastructure/http/dto/request"
+)
+
+// RankerHandler gerencia as requisições HTTP relacionadas ao ranker de candidatos
+type RankerHandler struct {
+	rankerUseCase *usecase.RankerUseCase
+}
+
+// NewRankerHandler cria uma nova instância do RankerHandler
+func NewRankerHandler(rankerUseCase *usecase.RankerUseCase) *RankerHandler {
+	return &RankerHandler{
+		rankerUseCase: rankerUseCase,
+	}
+}
+
+// GetRanker processa a requisição para consultar o modelo treinado do tenant
+func (h *RankerHandler) GetRanker(c *gin.Context) {
+	rankerModel, err := h.rankerUseCase.GetRanker(c.Request.Context(), requestTenant(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rankerModel)
+}
+
+// TrainRanker processa a requisição para treinar o ranker do tenant com revisões manuais
+func (h *RankerHandler) TrainRanker(c *gin.Context) {
+	var req request.TrainRankerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	rankerModel, err := h.rankerUseCase.TrainRanker(c.Request.Context(), requestTenant(c), req.ToRankerSamplesDomain())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rankerModel)
+}
+
+// SetRankerStatus processa a requisição para ativar ou desativar o ranker do tenant
+func (h *RankerHandler) SetRankerStatus(c *gin.Context) {
+	var req request.RankerStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	rankerModel, err := h.rankerUseCase.SetRankerEnabled(c.Request.Context(), requestTenant(c), req.Enabled)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rankerModel)
+}
+
+// RecordReview processa a requisição para registrar a aprovação ou rejeição manual de um par
+func (h *RankerHandler) RecordReview(c *gin.Context) {
+	var req request.MatchReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	review, err := h.rankerUseCase.RecordReview(c.Request.Context(), requestTenant(c), req.BilletID, req.TransactionID, req.Approved, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// ListReviews processa a requisição para listar as revisões manuais do tenant
+func (h *RankerHandler) ListReviews(c *gin.Context) {
+	reviews, err := h.rankerUseCase.ListReviews(c.Request.Context(), requestTenant(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// ExportReviews processa a requisição para exportar o dataset de revisões manuais do tenant em CSV
+func (h *RankerHandler) ExportReviews(c *gin.Context) {
+	reviews, err := h.rankerUseCase.ListReviews(c.Request.Context(), requestTenant(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="match_reviews.csv"`)
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{
+		"billet_id", "transaction_id", "amount_diff_percentage", "days_diff",
+		"partial_reference", "approved", "reviewer", "reviewed_at",
+	})
+
+	for _, review := range reviews {
+		writer.Write([]string{
+			review.BilletID,
+			review.TransactionID,
+			strconv.FormatFloat(review.Features.AmountDiffPercentage, 'f', 4, 64),
+			strconv.FormatFloat(review.Features.DaysDiff, 'f', 2, 64),
+			strconv.FormatBool(review.Features.PartialReference),
+			strconv.FormatBool(review.Approved),
+			review.Reviewer,
+			review.ReviewedAt.Format(time.RFC3339),
+		})
+	}
+
+	writer.Flush()
+}
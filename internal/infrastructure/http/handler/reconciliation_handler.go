@@ -1,12 +1,16 @@
 package handler
 
 import (
-	"encoding/json"
 	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
 
 	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
 	"conciliacao-bancaria/internal/infrastructure/http/dto/request"
 	"conciliacao-bancaria/internal/infrastructure/http/dto/response"
+	"conciliacao-bancaria/internal/infrastructure/http/middleware"
 )
 
 // ReconciliationHandler gerencia as requisições HTTP relacionadas à conciliação
@@ -22,183 +26,631 @@ func NewReconciliationHandler(reconciliationUseCase *usecase.ReconciliationUseCa
 }
 
 // RunReconciliation processa a requisição para executar o processo de conciliação
-func (h *ReconciliationHandler) RunReconciliation(w http.ResponseWriter, r *http.Request) {
+func (h *ReconciliationHandler) RunReconciliation(c *gin.Context) {
 	var req request.ReconciliationRequest
-	err := json.NewDecoder(r.Body).Decode(&req)
+	err := c.ShouldBindJSON(&req)
 	if err != nil {
-		http.Error(w, "Erro ao decodificar requisição: "+err.Error(), http.StatusBadRequest)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
 		return
 	}
-	defer r.Body.Close()
 
 	// Validar requisição
 	if err := req.Validate(); err != nil {
-		http.Error(w, "Dados inválidos: "+err.Error(), http.StatusBadRequest)
+		handleError(c, err)
+		return
+	}
+
+	// Executar conciliação através do caso de uso, com o ranker do tenant quando ativo
+	params := req.ToReconciliationParams()
+	params.Tenant = requestTenant(c)
+
+	params.Strategies, err = usecase.BuildStrategies(req.Strategies, req.ToStrategyParams())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	result, err := h.reconciliationUseCase.RunReconciliation(c.Request.Context(), params)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	// Simulação: nenhuma execução foi registrada
+	if result.DryRun {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
+	// Repetição de uma execução já concluída para a mesma janela: o resultado anterior é devolvido
+	c.Header("X-Reconciliation-Run-ID", result.RunID)
+	if result.Replayed {
+		c.Header("Idempotent-Replayed", "true")
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ReconcileSpecific processa a requisição para conciliar apenas os boletos e pagamentos informados,
+// registrada como uma execução própria
+func (h *ReconciliationHandler) ReconcileSpecific(c *gin.Context) {
+	var req request.ReconciliationByIDsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
 		return
 	}
 
-	// Executar conciliação através do caso de uso
-	result, err := h.reconciliationUseCase.RunReconciliation(r.Context(), req.ToReconciliationParams())
+	result, err := h.reconciliationUseCase.ReconcileSpecific(c.Request.Context(), req.ToSpecificReconciliationParams(requestTenant(c)))
 	if err != nil {
-		handleError(w, err)
+		handleError(c, err)
 		return
 	}
 
-	// Converter resultado para a estrutura de resposta conforme requisito 3.a
-	resp := response.ReconciliationResultResponse{
-		BoletosConciliados:    make([]response.BilletReconciliationResponse, 0),
-		BoletosNaoConciliados: make([]response.BilletResponse, 0),
+	c.Header("X-Reconciliation-Run-ID", result.RunID)
+	c.JSON(http.StatusOK, result)
+}
+
+// ManualMatch processa a requisição para conciliar manualmente um boleto com um pagamento
+func (h *ReconciliationHandler) ManualMatch(c *gin.Context) {
+	var req request.ManualMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
 	}
 
-	// Preencher boletos conciliados
-	for _, reconciled := range result.ReconciledBillets {
-		resp.BoletosConciliados = append(resp.BoletosConciliados, response.FromBilletReconciliationDomain(reconciled))
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
 	}
 
-	// Preencher boletos não conciliados
-	for _, notReconciled := range result.NotReconciledBillets {
-		resp.BoletosNaoConciliados = append(resp.BoletosNaoConciliados, response.FromBilletDomain(notReconciled))
+	reconciliation, err := h.reconciliationUseCase.ManualMatch(c.Request.Context(), req.BilletID, req.TransactionID, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
 	}
 
-	renderJSON(w, resp, http.StatusOK)
+	c.JSON(http.StatusCreated, reconciliation)
 }
 
-// GetReconciliationByID processa a requisição para obter detalhes de uma conciliação específica
-func (h *ReconciliationHandler) GetReconciliationByID(w http.ResponseWriter, r *http.Request) {
+// SimulateTolerances processa a requisição de simulação (what-if) da conciliação com várias tolerâncias
+func (h *ReconciliationHandler) SimulateTolerances(c *gin.Context) {
+	var req request.WhatIfRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	params := usecase.ReconciliationParams{
+		StartDate:      req.StartDate.Time,
+		EndDate:        req.EndDate.Time,
+		FilterAccounts: req.FilterAccounts,
+		Tenant:         requestTenant(c),
+	}
+
+	// Executar a simulação em memória através do caso de uso
+	simulations, err := h.reconciliationUseCase.SimulateTolerances(c.Request.Context(), params, req.Tolerances)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, simulations)
+}
+
+// CompareLedger processa a requisição de comparação entre o saldo do razão contábil e as conciliações
+func (h *ReconciliationHandler) CompareLedger(c *gin.Context) {
+	var req request.LedgerComparisonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	params := usecase.LedgerComparisonParams{
+		StartDate:      req.StartDate.Time,
+		EndDate:        req.EndDate.Time,
+		FilterAccounts: req.FilterAccounts,
+		LedgerBalance:  *req.LedgerBalance,
+	}
+
+	comparison, err := h.reconciliationUseCase.CompareLedger(c.Request.Context(), params)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+// RematchBillet processa a requisição para refazer o matching de um único boleto
+// contra os pagamentos ainda não utilizados, após a correção dos dados do boleto
+func (h *ReconciliationHandler) RematchBillet(c *gin.Context) {
+	// Extrair ID do boleto da URL
+	billetID := c.Param("id")
+	if billetID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do boleto é obrigatório"})
+		return
+	}
+
+	// Executar o matching através do caso de uso
+	result, err := h.reconciliationUseCase.RematchBillet(c.Request.Context(), billetID, requestTenant(c), requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ReevaluateBillet processa a requisição para reavaliar as conciliações com valor diferente de um
+// boleto após a correção do seu valor
+func (h *ReconciliationHandler) ReevaluateBillet(c *gin.Context) {
+	billetID := c.Param("id")
+	if billetID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do boleto é obrigatório"})
+		return
+	}
+
+	result, err := h.reconciliationUseCase.ReevaluateBillet(c.Request.Context(), billetID, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetBilletStatusChanges processa a requisição para obter o histórico de mudanças de status das
+// conciliações de um boleto
+func (h *ReconciliationHandler) GetBilletStatusChanges(c *gin.Context) {
+	billetID := c.Param("id")
+	if billetID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do boleto é obrigatório"})
+		return
+	}
+
+	changes, err := h.reconciliationUseCase.GetStatusChanges(c.Request.Context(), billetID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+// GetBilletApprovals processa a requisição para obter as aprovações e rejeições dos pareamentos de um boleto
+func (h *ReconciliationHandler) GetBilletApprovals(c *gin.Context) {
+	billetID := c.Param("id")
+	if billetID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do boleto é obrigatório"})
+		return
+	}
+
+	approvals, err := h.reconciliationUseCase.GetApprovals(c.Request.Context(), billetID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, approvals)
+}
+
+// ClaimBillet processa a requisição para bloquear um boleto durante a investigação
+func (h *ReconciliationHandler) ClaimBillet(c *gin.Context) {
+	billetID := c.Param("id")
+	if billetID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do boleto é obrigatório"})
+		return
+	}
+
+	// O corpo é opcional: sem ele, a duração padrão é aplicada
+	var req request.BilletClaimRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+			return
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	claim, err := h.reconciliationUseCase.ClaimBillet(c.Request.Context(), billetID, requestActor(c), time.Duration(req.TTLSeconds)*time.Second)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, claim)
+}
+
+// ReleaseBilletClaim processa a requisição para liberar o bloqueio de um boleto
+func (h *ReconciliationHandler) ReleaseBilletClaim(c *gin.Context) {
+	billetID := c.Param("id")
+	if billetID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do boleto é obrigatório"})
+		return
+	}
+
+	if err := h.reconciliationUseCase.ReleaseBilletClaim(c.Request.Context(), billetID, requestActor(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetBilletClaim processa a requisição para consultar quem está com o boleto e até quando
+func (h *ReconciliationHandler) GetBilletClaim(c *gin.Context) {
+	billetID := c.Param("id")
+	if billetID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do boleto é obrigatório"})
+		return
+	}
+
+	claim, err := h.reconciliationUseCase.GetBilletClaim(c.Request.Context(), billetID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, claim)
+}
+
+// GetReconciliation processa a requisição para obter detalhes de uma conciliação específica
+func (h *ReconciliationHandler) GetReconciliation(c *gin.Context) {
 	// Extrair ID da conciliação da URL
-	reconciliationID := extractPathParam(r, "id")
+	reconciliationID := c.Param("id")
 	if reconciliationID == "" {
-		http.Error(w, "ID da conciliação é obrigatório", http.StatusBadRequest)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID da conciliação é obrigatório"})
 		return
 	}
 
 	// Buscar conciliação através do caso de uso
-	reconciliation, err := h.reconciliationUseCase.GetReconciliationByID(r.Context(), reconciliationID)
+	reconciliation, err := h.reconciliationUseCase.GetReconciliationByID(c.Request.Context(), reconciliationID)
 	if err != nil {
-		handleError(w, err)
+		handleError(c, err)
 		return
 	}
 
 	// Converter para resposta e retornar
-	resp := response.FromReconciliationDomain(reconciliation)
-	renderJSON(w, resp, http.StatusOK)
+	resp := response.FromReconciliationItemDomain(reconciliation)
+	c.JSON(http.StatusOK, resp)
+}
+
+// UndoMatch processa a requisição para desfazer uma conciliação, devolvendo o boleto e o pagamento à
+// situação de não conciliados
+func (h *ReconciliationHandler) UndoMatch(c *gin.Context) {
+	reconciliationID := c.Param("id")
+	if reconciliationID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID da conciliação é obrigatório"})
+		return
+	}
+
+	var req request.UndoMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	undos, err := h.reconciliationUseCase.UndoReconciliation(c.Request.Context(), reconciliationID, req.Reason, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, undos)
+}
+
+// ApproveReconciliation processa a requisição para aprovar um pareamento que aguarda aprovação, que passa
+// a valer como conciliação. O aprovador é identificado pela requisição
+func (h *ReconciliationHandler) ApproveReconciliation(c *gin.Context) {
+	reconciliationID := c.Param("id")
+	if reconciliationID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID da conciliação é obrigatório"})
+		return
+	}
+
+	// O corpo é opcional: sem motivo, a aprovação é descrita pelo pareamento
+	var req request.ApproveReconciliationRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+			return
+		}
+	}
+
+	approvals, err := h.reconciliationUseCase.ApproveReconciliation(c.Request.Context(), reconciliationID, req.Reason, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, approvals)
+}
+
+// RejectReconciliation processa a requisição para rejeitar um pareamento que aguarda aprovação, devolvendo
+// o boleto e o pagamento à situação de não conciliados. O aprovador é identificado pela requisição
+func (h *ReconciliationHandler) RejectReconciliation(c *gin.Context) {
+	reconciliationID := c.Param("id")
+	if reconciliationID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID da conciliação é obrigatório"})
+		return
+	}
+
+	var req request.RejectReconciliationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	rejections, err := h.reconciliationUseCase.RejectReconciliation(c.Request.Context(), reconciliationID, req.Reason, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rejections)
+}
+
+// GetRun processa a requisição para obter uma execução de conciliação com o resultado e as medições
+func (h *ReconciliationHandler) GetRun(c *gin.Context) {
+	runID := c.Param("id")
+	if runID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID da execução é obrigatório"})
+		return
+	}
+
+	run, err := h.reconciliationUseCase.GetRun(c.Request.Context(), runID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// GetShadowReport processa a requisição para obter o relatório de divergência do motor shadow em uma execução
+func (h *ReconciliationHandler) GetShadowReport(c *gin.Context) {
+	runID := c.Param("id")
+	if runID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID da execução é obrigatório"})
+		return
+	}
+
+	report, err := h.reconciliationUseCase.GetShadowReport(c.Request.Context(), runID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ListRuns processa a requisição para listar as execuções de conciliação do tenant. O tenant padrão
+// também vê as execuções do worker e da linha de comando, registradas sem tenant
+func (h *ReconciliationHandler) ListRuns(c *gin.Context) {
+	tenants := []string{requestTenant(c)}
+	if tenants[0] == middleware.DefaultTenant {
+		tenants = append(tenants, "")
+	}
+
+	runs, err := h.reconciliationUseCase.ListRuns(c.Request.Context(), tenants, extractReconciliationQueryParams(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+// GetRunItems processa a requisição para obter uma execução de conciliação com as conciliações gravadas por ela
+func (h *ReconciliationHandler) GetRunItems(c *gin.Context) {
+	runID := c.Param("id")
+	if runID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID da execução é obrigatório"})
+		return
+	}
+
+	run, items, err := h.reconciliationUseCase.GetRunItems(c.Request.Context(), runID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromReconciliationRunItems(run, items))
 }
 
 // ListReconciliations processa a requisição para listar todas as conciliações
-func (h *ReconciliationHandler) ListReconciliations(w http.ResponseWriter, r *http.Request) {
+func (h *ReconciliationHandler) ListReconciliations(c *gin.Context) {
 	// Extrair parâmetros de paginação e filtros
-	params := extractReconciliationQueryParams(r)
+	params := extractReconciliationQueryParams(c)
 
 	// Buscar conciliações através do caso de uso
-	reconciliations, err := h.reconciliationUseCase.ListReconciliations(r.Context(), params)
+	reconciliations, err := h.reconciliationUseCase.ListReconciliations(c.Request.Context(), params)
 	if err != nil {
-		handleError(w, err)
+		handleError(c, err)
 		return
 	}
 
 	// Converter para resposta e retornar
-	var resp []response.ReconciliationSummaryResponse
+	resp := make([]response.ReconciliationItemResponse, 0, len(reconciliations))
 	for _, reconciliation := range reconciliations {
-		resp = append(resp, response.FromReconciliationSummaryDomain(reconciliation))
+		resp = append(resp, response.FromReconciliationItemDomain(reconciliation))
 	}
 
-	renderJSON(w, resp, http.StatusOK)
+	c.JSON(http.StatusOK, resp)
 }
 
-// GetBilletReconciliationStatus processa a requisição para obter o status de conciliação de um boleto específico
-func (h *ReconciliationHandler) GetBilletReconciliationStatus(w http.ResponseWriter, r *http.Request) {
-	// Extrair ID do boleto da URL
-	billetID := extractPathParam(r, "billet_id")
+// GetBilletReconciliationHistory processa a requisição para obter o histórico de conciliações de um boleto,
+// incluindo as tentativas frustradas das execuções
+func (h *ReconciliationHandler) GetBilletReconciliationHistory(c *gin.Context) {
+	billetID := c.Param("id")
 	if billetID == "" {
-		http.Error(w, "ID do boleto é obrigatório", http.StatusBadRequest)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do boleto é obrigatório"})
 		return
 	}
 
-	// Buscar status de conciliação através do caso de uso
-	status, err := h.reconciliationUseCase.GetBilletReconciliationStatus(r.Context(), billetID)
+	history, err := h.reconciliationUseCase.GetReconciliationHistory(c.Request.Context(), billetID)
 	if err != nil {
-		handleError(w, err)
+		handleError(c, err)
 		return
 	}
 
-	// Converter para resposta e retornar
-	resp := response.BilletReconciliationStatusResponse{
-		BilletID:           status.BilletID,
-		ReconciliationID:   status.ReconciliationID,
-		TransactionID:      status.TransactionID,
-		Status:             status.Status,
-		Strategy:           status.Strategy,
-		AmountDiff:         status.AmountDiff,
-		ReconciliationDate: status.ReconciliationDate,
+	c.JSON(http.StatusOK, response.FromBilletReconciliationHistory(billetID, history))
+}
+
+// GetPaymentReconciliationHistory processa a requisição para obter o histórico de conciliações de um pagamento
+func (h *ReconciliationHandler) GetPaymentReconciliationHistory(c *gin.Context) {
+	transactionID := c.Param("id")
+	if transactionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do pagamento é obrigatório"})
+		return
 	}
 
-	renderJSON(w, resp, http.StatusOK)
+	history, err := h.reconciliationUseCase.GetPaymentReconciliationHistory(c.Request.Context(), transactionID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FromPaymentReconciliationHistory(transactionID, history))
 }
 
-// GetPaymentReconciliationStatus processa a requisição para obter o status de conciliação de um pagamento específico
-func (h *ReconciliationHandler) GetPaymentReconciliationStatus(w http.ResponseWriter, r *http.Request) {
-	// Extrair ID do pagamento da URL
-	paymentID := extractPathParam(r, "transaction_id")
+// ListExcludedPayments processa a requisição para listar os lançamentos de débito excluídos da conciliação
+func (h *ReconciliationHandler) ListExcludedPayments(c *gin.Context) {
+	// Buscar débitos através do caso de uso
+	payments, err := h.reconciliationUseCase.ListExcludedPayments(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payments)
+}
+
+// ListSuspiciousPayments processa a requisição para listar os pagamentos suspeitos aguardando revisão
+func (h *ReconciliationHandler) ListSuspiciousPayments(c *gin.Context) {
+	// Buscar pagamentos suspeitos através do caso de uso
+	payments, err := h.reconciliationUseCase.ListSuspiciousPayments(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payments)
+}
+
+// ApproveSuspiciousPayment processa a requisição para liberar um pagamento suspeito para a conciliação
+func (h *ReconciliationHandler) ApproveSuspiciousPayment(c *gin.Context) {
+	paymentID := c.Param("id")
 	if paymentID == "" {
-		http.Error(w, "ID do pagamento é obrigatório", http.StatusBadRequest)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do pagamento é obrigatório"})
 		return
 	}
 
-	// Buscar status de conciliação através do caso de uso
-	status, err := h.reconciliationUseCase.GetPaymentReconciliationStatus(r.Context(), paymentID)
+	// Aprovar pagamento através do caso de uso
+	payment, err := h.reconciliationUseCase.ApprovePayment(c.Request.Context(), paymentID, requestActor(c))
 	if err != nil {
-		handleError(w, err)
+		handleError(c, err)
 		return
 	}
 
-	// Converter para resposta e retornar
-	resp := response.PaymentReconciliationStatusResponse{
-		TransactionID:      status.TransactionID,
-		ReconciliationID:   status.ReconciliationID,
-		BilletID:           status.BilletID,
-		Status:             status.Status,
-		Strategy:           status.Strategy,
-		AmountDiff:         status.AmountDiff,
-		ReconciliationDate: status.ReconciliationDate,
+	c.JSON(http.StatusOK, payment)
+}
+
+// ListIgnoredPayments processa a requisição para listar os pagamentos ignorados pelo valor mínimo
+func (h *ReconciliationHandler) ListIgnoredPayments(c *gin.Context) {
+	// Buscar pagamentos ignorados através do caso de uso
+	payments, err := h.reconciliationUseCase.ListIgnoredPayments(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payments)
+}
+
+// ForceIgnoredPayment processa a requisição para forçar a conciliação de um pagamento abaixo do valor mínimo
+func (h *ReconciliationHandler) ForceIgnoredPayment(c *gin.Context) {
+	paymentID := c.Param("id")
+	if paymentID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do pagamento é obrigatório"})
+		return
 	}
 
-	renderJSON(w, resp, http.StatusOK)
+	// Forçar pagamento através do caso de uso
+	payment, err := h.reconciliationUseCase.ForcePayment(c.Request.Context(), paymentID, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
 }
 
-// GetReconciliationStatistics processa a requisição para obter estatísticas de conciliação
-func (h *ReconciliationHandler) GetReconciliationStatistics(w http.ResponseWriter, r *http.Request) {
-	// Extrair parâmetros de filtro para as estatísticas
-	params := extractReconciliationQueryParams(r)
+// GetTimeToReconcileStatistics processa a requisição para obter os percentis do tempo até a conciliação
+func (h *ReconciliationHandler) GetTimeToReconcileStatistics(c *gin.Context) {
+	// Extrair filtros de conta e período
+	params := extractReconciliationQueryParams(c)
 
-	// Buscar estatísticas através do caso de uso
-	stats, err := h.reconciliationUseCase.GetReconciliationStatistics(r.Context(), params)
+	// Calcular percentis através do caso de uso
+	stats, err := h.reconciliationUseCase.GetTimeToReconcileStatistics(c.Request.Context(), params)
 	if err != nil {
-		handleError(w, err)
+		handleError(c, err)
 		return
 	}
 
-	// Converter para resposta e retornar
-	resp := response.ReconciliationStatisticsResponse{
-		TotalBillets:                stats.TotalBillets,
-		TotalPayments:               stats.TotalPayments,
-		TotalReconciledBillets:      stats.TotalReconciledBillets,
-		TotalNotReconciledBillets:   stats.TotalNotReconciledBillets,
-		TotalMatchedByReferenceID:   stats.TotalMatchedByReferenceID,
-		TotalMatchedByAccountAmount: stats.TotalMatchedByAccountAmount,
-		TotalWithAmountDifference:   stats.TotalWithAmountDifference,
-		AverageAmountDifference:     stats.AverageAmountDifference,
-		ReconciliationRate:          stats.ReconciliationRate,
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetEngineVersionStatistics processa a requisição para obter os totais e a taxa de conciliação por dia e
+// versão do motor de matching
+func (h *ReconciliationHandler) GetEngineVersionStatistics(c *gin.Context) {
+	// Extrair filtros de conta, versão, período e agrupamento
+	params := extractReconciliationQueryParams(c)
+
+	stats, err := h.reconciliationUseCase.GetEngineVersionStatistics(c.Request.Context(), params)
+	if err != nil {
+		handleError(c, err)
+		return
 	}
 
-	renderJSON(w, resp, http.StatusOK)
+	c.JSON(http.StatusOK, stats)
 }
 
 // extractReconciliationQueryParams extrai parâmetros de consulta específicos para conciliação
-func extractReconciliationQueryParams(r *http.Request) map[string]string {
+func extractReconciliationQueryParams(c *gin.Context) map[string]string {
 	params := make(map[string]string)
 
 	// Extrair parâmetros comuns como paginação, ordenação, etc.
-	query := r.URL.Query()
+	query := c.Request.URL.Query()
 
 	// Parâmetros específicos para conciliação
 	if limit := query.Get("limit"); limit != "" {
@@ -217,6 +669,14 @@ func extractReconciliationQueryParams(r *http.Request) map[string]string {
 		params["end_date"] = endDate
 	}
 
+	if month := query.Get("month"); month != "" {
+		params["month"] = month
+	}
+
+	if dateField := query.Get("date_field"); dateField != "" {
+		params["date_field"] = dateField
+	}
+
 	if bankAccount := query.Get("bank_account"); bankAccount != "" {
 		params["bank_account"] = bankAccount
 	}
@@ -229,9 +689,47 @@ func extractReconciliationQueryParams(r *http.Request) map[string]string {
 		params["strategy"] = strategy
 	}
 
+	if runID := query.Get("run_id"); runID != "" {
+		params["run_id"] = runID
+	}
+
+	if engineVersion := query.Get("engine_version"); engineVersion != "" {
+		params["engine_version"] = engineVersion
+	}
+
+	if groupBy := query.Get("group_by"); groupBy != "" {
+		params["group_by"] = groupBy
+	}
+
 	if tolerancePercentage := query.Get("tolerance_percentage"); tolerancePercentage != "" {
 		params["tolerance_percentage"] = tolerancePercentage
 	}
 
+	if sortBy := query.Get("sort_by"); sortBy != "" {
+		params["sort_by"] = sortBy
+	}
+
 	return params
 }
+
+// requestActor identifica o analista da requisição pelo header X-User-ID ou,
+// na ausência dele, pelo titular da API key
+func requestActor(c *gin.Context) string {
+	if actor := c.GetHeader("X-User-ID"); actor != "" {
+		return actor
+	}
+
+	if scope := model.AccessScopeFromContext(c.Request.Context()); scope != nil {
+		return scope.Subject
+	}
+
+	return ""
+}
+
+// requestTenant identifica o tenant da requisição pelo header X-Tenant-ID
+func requestTenant(c *gin.Context) string {
+	if tenant := c.GetHeader(middleware.TenantHeader); tenant != "" {
+		return tenant
+	}
+	return middleware.DefaultTenant
+}
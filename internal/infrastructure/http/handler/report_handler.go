@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/infrastructure/report"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// ReportHandler gerencia as requisições HTTP dos relatórios de conciliação
+type ReportHandler struct {
+	reconciliationUseCase *usecase.ReconciliationUseCase
+	template              report.Template
+}
+
+// NewReportHandler cria uma nova instância de ReportHandler com o layout do relatório regulatório
+func NewReportHandler(reconciliationUseCase *usecase.ReconciliationUseCase, template report.Template) *ReportHandler {
+	return &ReportHandler{
+		reconciliationUseCase: reconciliationUseCase,
+		template:              template,
+	}
+}
+
+// ExportRegulatoryReport processa a requisição para exportar, em CSV no layout da auditoria regulatória,
+// a conciliação diária por conta do mês (month) ou do período (start_date e end_date) informado
+func (h *ReportHandler) ExportRegulatoryReport(c *gin.Context) {
+	params := extractReconciliationQueryParams(c)
+
+	statistics, err := h.reconciliationUseCase.GetDailyStatistics(c.Request.Context(), params)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	filename := "relatorio_regulatorio.csv"
+	if month := params["month"]; month != "" {
+		filename = fmt.Sprintf("relatorio_regulatorio_%s.csv", month)
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	if err := report.WriteCSV(c.Writer, h.template, statistics); err != nil {
+		log.Printf("erro ao escrever relatório regulatório: %v", err)
+	}
+}
+
+// GetAccountsRanking processa a requisição para obter o ranking das contas por valor pendente, taxa de
+// conciliação ou idade média das pendências (sort_by). Com format=csv, o ranking é exportado em CSV
+func (h *ReportHandler) GetAccountsRanking(c *gin.Context) {
+	params := extractReconciliationQueryParams(c)
+
+	format := c.Query("format")
+	if format != "" && format != "json" && format != "csv" {
+		handleError(c, errors.NewValidationError("format", "formato deve ser json ou csv"))
+		return
+	}
+
+	ranking, err := h.reconciliationUseCase.GetAccountRanking(c.Request.Context(), params)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if format != "csv" {
+		c.JSON(http.StatusOK, ranking)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="ranking_contas.csv"`)
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{
+		"posicao", "bank_account", "total_billets", "reconciled_billets", "pending_billets",
+		"pending_amount", "reconciliation_rate", "avg_pending_age_days", "max_pending_age_days",
+	})
+
+	for i, performance := range ranking {
+		writer.Write([]string{
+			strconv.Itoa(i + 1),
+			performance.BankAccount,
+			strconv.FormatInt(performance.TotalBillets, 10),
+			strconv.FormatInt(performance.ReconciledBillets, 10),
+			strconv.FormatInt(performance.PendingBillets, 10),
+			strconv.FormatFloat(performance.PendingAmount, 'f', 2, 64),
+			strconv.FormatFloat(performance.ReconciliationRate, 'f', 2, 64),
+			strconv.FormatFloat(performance.AvgPendingAgeDays, 'f', 1, 64),
+			strconv.FormatFloat(performance.MaxPendingAgeDays, 'f', 1, 64),
+		})
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("erro ao escrever ranking das contas: %v", err)
+	}
+}
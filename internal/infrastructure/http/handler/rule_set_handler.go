@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/infrastructure/http/dto/request"
+)
+
+// RuleSetHandler gerencia as requisições HTTP relacionadas aos conjuntos de regras de matching
+type RuleSetHandler struct {
+	ruleSetUseCase *usecase.RuleSetUseCase
+}
+
+// NewRuleSetHandler cria uma nova instância de RuleSetHandler
+func NewRuleSetHandler(ruleSetUseCase *usecase.RuleSetUseCase) *RuleSetHandler {
+	return &RuleSetHandler{
+		ruleSetUseCase: ruleSetUseCase,
+	}
+}
+
+// ListRuleSets processa a requisição para listar os conjuntos de regras do tenant
+func (h *RuleSetHandler) ListRuleSets(c *gin.Context) {
+	ruleSets, err := h.ruleSetUseCase.ListRuleSets(c.Request.Context(), requestTenant(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ruleSets)
+}
+
+// SaveRuleSet processa a requisição para criar ou substituir um conjunto de regras do tenant
+func (h *RuleSetHandler) SaveRuleSet(c *gin.Context) {
+	var req request.RuleSetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	strategies, err := usecase.BuildStrategies(req.Strategies, req.ToStrategyParams())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	ruleSet, err := h.ruleSetUseCase.SaveRuleSet(c.Request.Context(), requestTenant(c), req.BankAccount, strategies, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ruleSet)
+}
+
+// DeleteRuleSet processa a requisição para remover o conjunto de regras da conta informada em
+// bank_account ou, sem ela, o conjunto geral do tenant
+func (h *RuleSetHandler) DeleteRuleSet(c *gin.Context) {
+	if err := h.ruleSetUseCase.DeleteRuleSet(c.Request.Context(), requestTenant(c), c.Query("bank_account")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
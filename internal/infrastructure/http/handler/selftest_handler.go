@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/infrastructure/selftest"
+)
+
+// SelfTestHandler gerencia as requisições HTTP do selftest do motor de matching
+type SelfTestHandler struct{}
+
+// NewSelfTestHandler cria uma nova instância de SelfTestHandler
+func NewSelfTestHandler() *SelfTestHandler {
+	return &SelfTestHandler{}
+}
+
+// RunSelfTest processa a requisição para executar o motor de matching contra o dataset dourado, em
+// memória e sem gravar nada. Responde 200 quando todos os cenários produzem o resultado esperado e 500,
+// com as divergências no relatório, quando algum diverge
+func (h *SelfTestHandler) RunSelfTest(c *gin.Context) {
+	report, err := selftest.Run(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if !report.Passed {
+		status = http.StatusInternalServerError
+	}
+	c.JSON(status, report)
+}
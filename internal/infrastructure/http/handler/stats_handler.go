@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/infrastructure/http/middleware"
+	"conciliacao-bancaria/internal/infrastructure/realtime"
+)
+
+const (
+	// statsRefreshInterval define o intervalo de reenvio dos contadores, que também mantém a conexão
+	// ativa e reflete a virada do dia mesmo sem novos eventos
+	statsRefreshInterval = 30 * time.Second
+
+	// statsWriteTimeout limita o tempo de escrita de uma mensagem ao cliente
+	statsWriteTimeout = 10 * time.Second
+)
+
+// StatsHandler transmite as estatísticas em tempo real via WebSocket
+type StatsHandler struct {
+	hub      *realtime.StatsHub
+	upgrader websocket.Upgrader
+}
+
+// NewStatsHandler cria uma nova instância do StatsHandler. O upgrade do WebSocket aceita apenas as
+// origens da política de CORS, além da própria origem da API
+func NewStatsHandler(hub *realtime.StatsHub, cors middleware.CORSConfig) *StatsHandler {
+	return &StatsHandler{
+		hub: hub,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     cors.CheckWebSocketOrigin,
+		},
+	}
+}
+
+// StreamStats abre o WebSocket e envia os contadores do tenant da requisição a cada atualização,
+// com os valores monetários no formato escolhido pela query money_format
+func (h *StatsHandler) StreamStats(c *gin.Context) {
+	format, err := middleware.ParseMoneyFormat(c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// O upgrader já respondeu ao cliente com o erro
+		log.Printf("erro ao abrir WebSocket de estatísticas: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	subscription, current := h.hub.Subscribe(c.Request.Context())
+	defer h.hub.Unsubscribe(subscription)
+
+	// As mensagens do cliente são descartadas; a leitura apenas detecta o fechamento da conexão
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(statsRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := writeStats(conn, current, format); err != nil {
+			return
+		}
+
+		select {
+		case <-closed:
+			return
+		case current = <-subscription.Updates():
+		case <-ticker.C:
+			current = h.hub.Snapshot(c.Request.Context())
+		}
+	}
+}
+
+// writeStats envia os contadores ao cliente
+func writeStats(conn *websocket.Conn, stats model.LiveStats, format middleware.MoneyFormat) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+
+	if data, err = middleware.FormatMoney(data, format); err != nil {
+		return err
+	}
+
+	if err := conn.SetWriteDeadline(time.Now().Add(statsWriteTimeout)); err != nil {
+		return err
+	}
+	return conn.WriteMessage(websocket.TextMessage, data)
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/infrastructure/http/dto/request"
+)
+
+// SubscriptionHandler gerencia as requisições HTTP relacionadas às assinaturas de eventos
+type SubscriptionHandler struct {
+	subscriptionUseCase *usecase.SubscriptionUseCase
+}
+
+// NewSubscriptionHandler cria uma nova instância do SubscriptionHandler
+func NewSubscriptionHandler(subscriptionUseCase *usecase.SubscriptionUseCase) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		subscriptionUseCase: subscriptionUseCase,
+	}
+}
+
+// CreateSubscription processa a requisição para cadastrar um assinante de eventos
+func (h *SubscriptionHandler) CreateSubscription(c *gin.Context) {
+	var req request.SubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	subscription, err := h.subscriptionUseCase.CreateSubscription(c.Request.Context(), req.ToSubscriptionDomain())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, subscription)
+}
+
+// ListSubscriptions processa a requisição para listar as assinaturas
+func (h *SubscriptionHandler) ListSubscriptions(c *gin.Context) {
+	subscriptions, err := h.subscriptionUseCase.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+// GetSubscription processa a requisição para obter uma assinatura
+func (h *SubscriptionHandler) GetSubscription(c *gin.Context) {
+	subscriptionID := c.Param("id")
+	if subscriptionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID da assinatura é obrigatório"})
+		return
+	}
+
+	subscription, err := h.subscriptionUseCase.GetSubscription(c.Request.Context(), subscriptionID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subscription)
+}
+
+// DeleteSubscription processa a requisição para remover uma assinatura
+func (h *SubscriptionHandler) DeleteSubscription(c *gin.Context) {
+	subscriptionID := c.Param("id")
+	if subscriptionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID da assinatura é obrigatório"})
+		return
+	}
+
+	if err := h.subscriptionUseCase.DeleteSubscription(c.Request.Context(), subscriptionID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/infrastructure/http/dto/request"
+)
+
+// TimelineHandler gerencia as requisições HTTP das linhas do tempo de boletos e pagamentos
+type TimelineHandler struct {
+	timelineUseCase *usecase.TimelineUseCase
+}
+
+// NewTimelineHandler cria uma nova instância de TimelineHandler
+func NewTimelineHandler(timelineUseCase *usecase.TimelineUseCase) *TimelineHandler {
+	return &TimelineHandler{
+		timelineUseCase: timelineUseCase,
+	}
+}
+
+// GetBilletTimeline processa a requisição para obter a linha do tempo unificada de um boleto
+func (h *TimelineHandler) GetBilletTimeline(c *gin.Context) {
+	billetID := c.Param("id")
+	if billetID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do boleto é obrigatório"})
+		return
+	}
+
+	timeline, err := h.timelineUseCase.GetBilletTimeline(c.Request.Context(), billetID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
+
+// GetPaymentTimeline processa a requisição para obter a linha do tempo unificada de um pagamento
+func (h *TimelineHandler) GetPaymentTimeline(c *gin.Context) {
+	transactionID := c.Param("id")
+	if transactionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do pagamento é obrigatório"})
+		return
+	}
+
+	timeline, err := h.timelineUseCase.GetPaymentTimeline(c.Request.Context(), transactionID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
+
+// AddBilletComment processa a requisição para comentar na linha do tempo de um boleto
+func (h *TimelineHandler) AddBilletComment(c *gin.Context) {
+	billetID := c.Param("id")
+	if billetID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do boleto é obrigatório"})
+		return
+	}
+
+	var req request.CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	entry, err := h.timelineUseCase.AddBilletComment(c.Request.Context(), billetID, req.Text, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// AddPaymentComment processa a requisição para comentar na linha do tempo de um pagamento
+func (h *TimelineHandler) AddPaymentComment(c *gin.Context) {
+	transactionID := c.Param("id")
+	if transactionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do pagamento é obrigatório"})
+		return
+	}
+
+	var req request.CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	entry, err := h.timelineUseCase.AddPaymentComment(c.Request.Context(), transactionID, req.Text, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
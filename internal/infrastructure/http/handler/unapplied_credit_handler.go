@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/infrastructure/http/dto/request"
+)
+
+// UnappliedCreditHandler gerencia as requisições HTTP relacionadas aos créditos não aplicados dos pagadores
+type UnappliedCreditHandler struct {
+	reconciliationUseCase *usecase.ReconciliationUseCase
+}
+
+// NewUnappliedCreditHandler cria uma nova instância de UnappliedCreditHandler
+func NewUnappliedCreditHandler(reconciliationUseCase *usecase.ReconciliationUseCase) *UnappliedCreditHandler {
+	return &UnappliedCreditHandler{
+		reconciliationUseCase: reconciliationUseCase,
+	}
+}
+
+// ListCredits processa a requisição para listar os créditos não aplicados, filtrados por payer_id,
+// bank_account e available
+func (h *UnappliedCreditHandler) ListCredits(c *gin.Context) {
+	query := c.Request.URL.Query()
+	params := map[string]string{
+		"payer_id":     query.Get("payer_id"),
+		"bank_account": query.Get("bank_account"),
+		"available":    query.Get("available"),
+	}
+
+	credits, err := h.reconciliationUseCase.ListUnappliedCredits(c.Request.Context(), params)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, credits)
+}
+
+// GetCredit processa a requisição para obter um crédito não aplicado
+func (h *UnappliedCreditHandler) GetCredit(c *gin.Context) {
+	creditID := c.Param("id")
+	if creditID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do crédito é obrigatório"})
+		return
+	}
+
+	credit, err := h.reconciliationUseCase.GetUnappliedCredit(c.Request.Context(), creditID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, credit)
+}
+
+// ApplyCredit processa a requisição para quitar um boleto do pagador com o saldo do crédito
+func (h *UnappliedCreditHandler) ApplyCredit(c *gin.Context) {
+	creditID := c.Param("id")
+	if creditID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do crédito é obrigatório"})
+		return
+	}
+
+	var req request.ApplyCreditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao decodificar requisição: " + err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	reconciliation, err := h.reconciliationUseCase.ApplyUnappliedCredit(c.Request.Context(), creditID, req.BilletID, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, reconciliation)
+}
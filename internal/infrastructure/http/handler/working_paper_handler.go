@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/infrastructure/report"
+)
+
+// WorkingPaperHandler gerencia as requisições HTTP do papel de trabalho das auditorias externas
+type WorkingPaperHandler struct {
+	workingPaperUseCase *usecase.WorkingPaperUseCase
+	template            report.WorkingPaperTemplate
+	signer              *report.Signer
+}
+
+// NewWorkingPaperHandler cria uma nova instância de WorkingPaperHandler com o layout do papel de trabalho
+// e o assinante; sem assinante, o papel de trabalho não pode ser gerado
+func NewWorkingPaperHandler(workingPaperUseCase *usecase.WorkingPaperUseCase, template report.WorkingPaperTemplate, signer *report.Signer) *WorkingPaperHandler {
+	return &WorkingPaperHandler{
+		workingPaperUseCase: workingPaperUseCase,
+		template:            template,
+		signer:              signer,
+	}
+}
+
+// ExportWorkingPaper processa a requisição para exportar, em CSV no layout da auditoria externa, o papel
+// de trabalho das conciliações do mês (month) ou do período (start_date e end_date) informado, assinado
+// eletronicamente pelo usuário da requisição
+func (h *WorkingPaperHandler) ExportWorkingPaper(c *gin.Context) {
+	if h.signer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Assinatura eletrônica não configurada (AUDIT_SIGNING_KEY)"})
+		return
+	}
+
+	params := extractReconciliationQueryParams(c)
+
+	paper, err := h.workingPaperUseCase.GetWorkingPaper(c.Request.Context(), params, requestActor(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	// O arquivo é montado antes da resposta para que falhas não cheguem ao cliente como um CSV truncado
+	var content bytes.Buffer
+	if err := report.WriteWorkingPaper(&content, h.template, paper, h.signer); err != nil {
+		log.Printf("erro ao gerar papel de trabalho: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor: " + err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("papel_de_trabalho_%s_%s.csv", paper.StartDate.Format("20060102"), paper.EndDate.Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", content.Bytes())
+}
@@ -0,0 +1,326 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// Repositórios em memória usados pelos testes do router. Cada um embute a interface do domínio e
+// implementa apenas os métodos chamados pelas rotas testadas; os demais entram em pânico, que o
+// gin.Recovery devolve como 500
+
+// memoryBilletRepository guarda os boletos em memória
+type memoryBilletRepository struct {
+	domainRepo.BilletRepository
+
+	mu      sync.Mutex
+	billets map[string]model.Billet
+}
+
+func newMemoryBilletRepository() *memoryBilletRepository {
+	return &memoryBilletRepository{billets: make(map[string]model.Billet)}
+}
+
+func (r *memoryBilletRepository) Create(ctx context.Context, billet *model.Billet) (*model.Billet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.billets[billet.ID]; found {
+		return nil, errors.NewConflictError("boleto", billet.ID, "já cadastrado")
+	}
+	r.billets[billet.ID] = *billet
+	stored := *billet
+	return &stored, nil
+}
+
+func (r *memoryBilletRepository) CreateMany(ctx context.Context, billets []*model.Billet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, billet := range billets {
+		if _, found := r.billets[billet.ID]; found {
+			return errors.NewConflictError("boleto", billet.ID, "já cadastrado")
+		}
+	}
+	for _, billet := range billets {
+		r.billets[billet.ID] = *billet
+	}
+	return nil
+}
+
+func (r *memoryBilletRepository) GetByID(ctx context.Context, id string) (*model.Billet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	billet, found := r.billets[id]
+	if !found {
+		return nil, errors.NewNotFoundError("boleto", id)
+	}
+	return &billet, nil
+}
+
+func (r *memoryBilletRepository) Update(ctx context.Context, billet *model.Billet) (*model.Billet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.billets[billet.ID]; !found {
+		return nil, errors.NewNotFoundError("boleto", billet.ID)
+	}
+	r.billets[billet.ID] = *billet
+	stored := *billet
+	return &stored, nil
+}
+
+func (r *memoryBilletRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.billets[id]; !found {
+		return errors.NewNotFoundError("boleto", id)
+	}
+	delete(r.billets, id)
+	return nil
+}
+
+// memoryPaymentRepository guarda os pagamentos em memória
+type memoryPaymentRepository struct {
+	domainRepo.PaymentRepository
+
+	mu       sync.Mutex
+	payments map[string]model.Payment
+}
+
+func newMemoryPaymentRepository() *memoryPaymentRepository {
+	return &memoryPaymentRepository{payments: make(map[string]model.Payment)}
+}
+
+func (r *memoryPaymentRepository) Create(ctx context.Context, payment *model.Payment) (*model.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.payments[payment.ID]; found {
+		return nil, errors.NewConflictError("pagamento", payment.ID, "já cadastrado")
+	}
+	r.payments[payment.ID] = *payment
+	stored := *payment
+	return &stored, nil
+}
+
+func (r *memoryPaymentRepository) CreateMany(ctx context.Context, payments []*model.Payment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, payment := range payments {
+		if _, found := r.payments[payment.ID]; found {
+			return errors.NewConflictError("pagamento", payment.ID, "já cadastrado")
+		}
+	}
+	for _, payment := range payments {
+		r.payments[payment.ID] = *payment
+	}
+	return nil
+}
+
+func (r *memoryPaymentRepository) GetByID(ctx context.Context, id string) (*model.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payment, found := r.payments[id]
+	if !found {
+		return nil, errors.NewNotFoundError("pagamento", id)
+	}
+	return &payment, nil
+}
+
+// List ignora o filtro e retorna todos os pagamentos ordenados pelo ID
+func (r *memoryPaymentRepository) List(ctx context.Context, filter model.PaymentFilter) ([]*model.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payments := make([]*model.Payment, 0, len(r.payments))
+	for _, payment := range r.payments {
+		payment := payment
+		payments = append(payments, &payment)
+	}
+	sort.Slice(payments, func(i, j int) bool { return payments[i].ID < payments[j].ID })
+	return payments, nil
+}
+
+func (r *memoryPaymentRepository) Update(ctx context.Context, payment *model.Payment) (*model.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.payments[payment.ID]; !found {
+		return nil, errors.NewNotFoundError("pagamento", payment.ID)
+	}
+	r.payments[payment.ID] = *payment
+	stored := *payment
+	return &stored, nil
+}
+
+func (r *memoryPaymentRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.payments[id]; !found {
+		return errors.NewNotFoundError("pagamento", id)
+	}
+	delete(r.payments, id)
+	return nil
+}
+
+// memoryTimelineRepository guarda as entradas da linha do tempo em memória
+type memoryTimelineRepository struct {
+	mu      sync.Mutex
+	entries []*model.TimelineEntry
+}
+
+func (r *memoryTimelineRepository) Create(ctx context.Context, entry *model.TimelineEntry) error {
+	return r.CreateMany(ctx, []*model.TimelineEntry{entry})
+}
+
+func (r *memoryTimelineRepository) CreateMany(ctx context.Context, entries []*model.TimelineEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
+func (r *memoryTimelineRepository) GetByEntity(ctx context.Context, entityType model.TimelineEntityType, entityID string) ([]*model.TimelineEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var entries []*model.TimelineEntry
+	for _, entry := range r.entries {
+		if entry.EntityType == entityType && entry.EntityID == entityID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// memoryRuleSetRepository guarda os conjuntos de regras em memória, por tenant e conta
+type memoryRuleSetRepository struct {
+	mu       sync.Mutex
+	ruleSets map[string]model.RuleSet
+}
+
+func newMemoryRuleSetRepository() *memoryRuleSetRepository {
+	return &memoryRuleSetRepository{ruleSets: make(map[string]model.RuleSet)}
+}
+
+func (r *memoryRuleSetRepository) Save(ctx context.Context, ruleSet *model.RuleSet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.ruleSets[ruleSet.Tenant+"/"+ruleSet.BankAccount] = *ruleSet
+	return nil
+}
+
+// GetByTenant retorna os conjuntos ordenados pela conta, o que põe o geral, sem conta, primeiro
+func (r *memoryRuleSetRepository) GetByTenant(ctx context.Context, tenant string) (model.RuleSets, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ruleSets := model.RuleSets{}
+	for _, ruleSet := range r.ruleSets {
+		if ruleSet.Tenant == tenant {
+			ruleSet := ruleSet
+			ruleSets = append(ruleSets, &ruleSet)
+		}
+	}
+	sort.Slice(ruleSets, func(i, j int) bool { return ruleSets[i].BankAccount < ruleSets[j].BankAccount })
+	return ruleSets, nil
+}
+
+func (r *memoryRuleSetRepository) Delete(ctx context.Context, tenant, bankAccount string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := tenant + "/" + bankAccount
+	if _, found := r.ruleSets[key]; !found {
+		return errors.NewNotFoundError("conjunto de regras", key)
+	}
+	delete(r.ruleSets, key)
+	return nil
+}
+
+// memoryNotificationTemplateRepository guarda as versões dos templates de notificação em memória
+type memoryNotificationTemplateRepository struct {
+	mu        sync.Mutex
+	templates []model.NotificationTemplate
+}
+
+// Create atribui ao template o número seguinte ao da última versão do tenant e do tipo de evento
+func (r *memoryNotificationTemplateRepository) Create(ctx context.Context, notificationTemplate *model.NotificationTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	version := 0
+	for _, stored := range r.templates {
+		if stored.Tenant == notificationTemplate.Tenant && stored.EventType == notificationTemplate.EventType && stored.Version > version {
+			version = stored.Version
+		}
+	}
+	notificationTemplate.Version = version + 1
+	if notificationTemplate.CreatedAt.IsZero() {
+		notificationTemplate.CreatedAt = time.Now()
+	}
+	r.templates = append(r.templates, *notificationTemplate)
+	return nil
+}
+
+func (r *memoryNotificationTemplateRepository) GetCurrent(ctx context.Context, tenant string) ([]*model.NotificationTemplate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := make(map[model.EventType]model.NotificationTemplate)
+	for _, stored := range r.templates {
+		if stored.Tenant == tenant && stored.Version > current[stored.EventType].Version {
+			current[stored.EventType] = stored
+		}
+	}
+
+	templates := make([]*model.NotificationTemplate, 0, len(current))
+	for _, stored := range current {
+		stored := stored
+		templates = append(templates, &stored)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].EventType < templates[j].EventType })
+	return templates, nil
+}
+
+func (r *memoryNotificationTemplateRepository) GetVersions(ctx context.Context, tenant string, eventType model.EventType) ([]*model.NotificationTemplate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var templates []*model.NotificationTemplate
+	for _, stored := range r.templates {
+		if stored.Tenant == tenant && stored.EventType == eventType {
+			stored := stored
+			templates = append(templates, &stored)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Version > templates[j].Version })
+	return templates, nil
+}
+
+func (r *memoryNotificationTemplateRepository) GetVersion(ctx context.Context, tenant string, eventType model.EventType, version int) (*model.NotificationTemplate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stored := range r.templates {
+		if stored.Tenant == tenant && stored.EventType == eventType && stored.Version == version {
+			return &stored, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("template de notificação", fmt.Sprintf("%s/%d", eventType, version))
+}
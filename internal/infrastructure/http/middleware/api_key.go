@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// APIKeyHeader define o header HTTP que transporta a API key
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator valida uma API key e retorna o escopo de acesso concedido
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*model.AccessScope, bool, error)
+}
+
+// LoadAPIKeysFromEnv carrega as API keys e seus escopos da variável API_KEYS, no formato
+// {"<api key>": {"subject": "erp", "tenant": "acme", "bank_accounts": ["12345-6"], "permissions": ["read"]}}.
+// As chaves sem tenant ficam vinculadas ao DefaultTenant
+func LoadAPIKeysFromEnv() map[string]model.AccessScope {
+	keys := make(map[string]model.AccessScope)
+
+	raw := os.Getenv("API_KEYS")
+	if raw == "" {
+		return keys
+	}
+
+	if err := json.Unmarshal([]byte(raw), &keys); err != nil {
+		log.Printf("API_KEYS inválido, nenhuma chave estática será aceita: %v", err)
+		return make(map[string]model.AccessScope)
+	}
+
+	return keys
+}
+
+// RequireAPIKey autentica a requisição pela API key, verifica se o escopo da chave permite a rota
+// e associa ao contexto o escopo de contas, aplicado pelos repositórios em todas as consultas.
+// O tenant da requisição passa a ser o da chave; um X-Tenant-ID diferente dele é recusado
+func RequireAPIKey(authenticator APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Requisições já autenticadas por certificado de cliente (mTLS) só passam pela verificação de escopo
+		if scope := model.AccessScopeFromContext(c.Request.Context()); scope != nil {
+			requirePermission(c, scope)
+			return
+		}
+
+		scope, ok, err := authenticator.Authenticate(c.Request.Context(), c.GetHeader(APIKeyHeader))
+		if err != nil {
+			log.Printf("erro ao validar API key: %v", err)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "não foi possível validar a API key"})
+			return
+		}
+
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "API key inválida, expirada ou revogada"})
+			return
+		}
+
+		tenant := scope.Tenant
+		if tenant == "" {
+			tenant = DefaultTenant
+		}
+
+		if requested := c.GetHeader(TenantHeader); requested != "" && requested != tenant {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API key não pertence ao tenant " + requested})
+			return
+		}
+
+		c.Request.Header.Set(TenantHeader, tenant)
+		ctx := model.WithTenant(model.WithAccessScope(c.Request.Context(), scope), tenant)
+		c.Request = c.Request.WithContext(ctx)
+		requirePermission(c, scope)
+	}
+}
+
+// requirePermission interrompe a requisição quando o escopo não concede a permissão exigida pela rota
+func requirePermission(c *gin.Context, scope *model.AccessScope) {
+	permission := RequiredPermission(c.Request.Method, c.FullPath())
+	if !scope.AllowsPermission(permission) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "credencial sem o escopo " + string(permission)})
+		return
+	}
+
+	c.Next()
+}
+
+// RequiredPermission define o escopo exigido por uma rota:
+//   - administração (API keys, contas bancárias, assinaturas, configuração do ranker e confirmação de fechamentos): admin
+//   - consultas (GET/HEAD e consultas em lote por IDs): read
+//   - cadastro e importação de boletos, pagamentos e arquivos bancários: import
+//   - demais operações (conciliação, rematch, bloqueios e revisões): reconcile
+func RequiredPermission(method, path string) model.APIKeyPermission {
+	path = strings.TrimPrefix(path, "/api/v1")
+
+	switch {
+	case strings.HasPrefix(path, "/admin"),
+		strings.HasPrefix(path, "/subscriptions"),
+		path == "/ranker/train",
+		path == "/ranker/status",
+		path == "/closings/:id/confirm":
+		return model.PermissionAdmin
+	case method == http.MethodGet || method == http.MethodHead,
+		path == "/billets/lookup",
+		path == "/payments/lookup":
+		return model.PermissionRead
+	case isImportPath(path):
+		return model.PermissionImport
+	default:
+		return model.PermissionReconcile
+	}
+}
+
+// isImportPath identifica as rotas de cadastro, alteração e importação de boletos, pagamentos e arquivos bancários
+func isImportPath(path string) bool {
+	if path == "/imports" || path == "/imports/statements" {
+		return true
+	}
+	for _, resource := range []string{"/billets", "/payments"} {
+		if path == resource || path == resource+"/batch" || path == resource+"/:id" {
+			return true
+		}
+	}
+	return false
+}
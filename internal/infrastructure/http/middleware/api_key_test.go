@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// staticAuthenticator autentica as chaves de um mapa fixo
+type staticAuthenticator map[string]model.AccessScope
+
+func (a staticAuthenticator) Authenticate(ctx context.Context, key string) (*model.AccessScope, bool, error) {
+	scope, ok := a[key]
+	if !ok {
+		return nil, false, nil
+	}
+	return &scope, true, nil
+}
+
+// TestRequireAPIKeyBindsTenant garante que o tenant da requisição é sempre o da API key, tanto no
+// header lido pelos handlers quanto no contexto usado para rotear os repositórios ao shard
+func TestRequireAPIKeyBindsTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authenticator := staticAuthenticator{
+		"acme-key":   {Subject: "erp", Tenant: "acme", Permissions: []model.APIKeyPermission{model.PermissionRead}},
+		"legacy-key": {Subject: "legado", Permissions: []model.APIKeyPermission{model.PermissionRead}},
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		header     string
+		wantStatus int
+		wantTenant string
+	}{
+		{name: "sem header assume o tenant da chave", key: "acme-key", wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "header igual ao da chave", key: "acme-key", header: "acme", wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "header de outro tenant", key: "acme-key", header: "globex", wantStatus: http.StatusForbidden},
+		{name: "chave sem tenant fica no tenant padrão", key: "legacy-key", wantStatus: http.StatusOK, wantTenant: DefaultTenant},
+		{name: "chave sem tenant não acessa outro tenant", key: "legacy-key", header: "acme", wantStatus: http.StatusForbidden},
+		{name: "chave desconhecida", key: "outra", header: "acme", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(TenantContext(), RequireAPIKey(authenticator))
+			router.GET("/api/v1/billets", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{
+					"header":  TenantID(c),
+					"context": model.TenantFromContext(c.Request.Context()),
+				})
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/billets", nil)
+			req.Header.Set(APIKeyHeader, tt.key)
+			if tt.header != "" {
+				req.Header.Set(TenantHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, esperado %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			want := `{"context":"` + tt.wantTenant + `","header":"` + tt.wantTenant + `"}`
+			if rec.Body.String() != want {
+				t.Fatalf("tenant da requisição %s, esperado %s", rec.Body.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/service"
+)
+
+// Audit registra no SIEM as falhas de autenticação/autorização e todas as operações
+// que alteram dados (POST, PUT, PATCH e DELETE), com o sujeito da API key quando houver
+func Audit(logger service.AuditLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+
+		status := c.Writer.Status()
+		event := auditEventFor(c.Request.Method, path, status)
+		if event == nil {
+			return
+		}
+
+		if scope := model.AccessScopeFromContext(c.Request.Context()); scope != nil {
+			event.Subject = scope.Subject
+		}
+		event.Tenant = TenantID(c)
+		event.SourceIP = c.ClientIP()
+		event.Method = c.Request.Method
+		event.Path = path
+		event.StatusCode = status
+
+		logger.Record(c.Request.Context(), event)
+	}
+}
+
+// auditEventFor classifica a requisição, retornando nil quando ela não precisa ser auditada
+func auditEventFor(method, path string, status int) *model.AuditEvent {
+	switch {
+	case status == http.StatusUnauthorized:
+		return model.NewAuditEvent(model.AuditCategoryAuthentication, "autenticacao_negada", model.AuditOutcomeFailure)
+	case status == http.StatusForbidden:
+		return model.NewAuditEvent(model.AuditCategoryAuthentication, "acesso_negado", model.AuditOutcomeFailure)
+	case method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions:
+		return nil
+	}
+
+	outcome := model.AuditOutcomeSuccess
+	if status >= http.StatusBadRequest {
+		outcome = model.AuditOutcomeFailure
+	}
+
+	return model.NewAuditEvent(model.AuditCategoryOperation, method+" "+path, outcome)
+}
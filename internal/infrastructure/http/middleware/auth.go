@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireToken protege rotas sensíveis (ex.: profiling) exigindo o header
+// "Authorization: Bearer <token>" com o token configurado
+func RequireToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "não autorizado"})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// ClientIdentity representa o tenant e o escopo concedidos ao certificado de um parceiro
+type ClientIdentity struct {
+	Tenant string `json:"tenant"`
+	model.AccessScope
+}
+
+// LoadClientIdentitiesFromEnv carrega o mapeamento do CN dos certificados de cliente da variável
+// MTLS_CLIENTS, no formato {"<CN>": {"tenant": "banco-x", "subject": "banco-x", "permissions": ["import"]}}
+func LoadClientIdentitiesFromEnv() map[string]ClientIdentity {
+	identities := make(map[string]ClientIdentity)
+
+	raw := os.Getenv("MTLS_CLIENTS")
+	if raw == "" {
+		return identities
+	}
+
+	if err := json.Unmarshal([]byte(raw), &identities); err != nil {
+		log.Printf("MTLS_CLIENTS inválido, nenhum certificado de cliente será aceito: %v", err)
+		return make(map[string]ClientIdentity)
+	}
+
+	return identities
+}
+
+// RequireClientCertificate identifica as requisições recebidas com certificado de cliente verificado
+// (listener mTLS) pelo CN do certificado, associando ao contexto o escopo e ao header o tenant mapeados.
+// Requisições sem certificado seguem para a autenticação por API key
+func RequireClientCertificate(identities map[string]ClientIdentity) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.TLS == nil || len(c.Request.TLS.VerifiedChains) == 0 {
+			c.Next()
+			return
+		}
+
+		commonName := c.Request.TLS.VerifiedChains[0][0].Subject.CommonName
+		identity, ok := identities[commonName]
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "certificado de cliente não autorizado"})
+			return
+		}
+
+		scope := identity.AccessScope
+		if scope.Subject == "" {
+			scope.Subject = commonName
+		}
+
+		// O tenant do certificado prevalece sobre o informado pelo cliente
+		if identity.Tenant != "" {
+			c.Request.Header.Set(TenantHeader, identity.Tenant)
+		}
+
+		c.Request = c.Request.WithContext(model.WithAccessScope(c.Request.Context(), &scope))
+		c.Next()
+	}
+}
@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig define a política de CORS da API para os clientes web (frontend e dashboard)
+type CORSConfig struct {
+	// AllowedOrigins lista as origens aceitas. "*" aceita qualquer origem e "https://*.empresa.com.br"
+	// aceita os subdomínios da origem
+	AllowedOrigins []string
+
+	AllowedMethods []string
+	AllowedHeaders []string
+
+	// ExposedHeaders lista os headers da resposta que o navegador entrega ao cliente
+	ExposedHeaders []string
+
+	// AllowCredentials permite o envio de cookies e do header Authorization pelo navegador
+	AllowCredentials bool
+
+	// MaxAgeSeconds é o tempo em que o navegador reaproveita a resposta do preflight; zero não informa
+	MaxAgeSeconds int
+}
+
+// Valores padrão da política, usados quando a variável correspondente não é informada
+var (
+	defaultCORSMethods = []string{
+		http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions,
+	}
+	defaultCORSHeaders = []string{
+		"Content-Type", "Authorization", APIKeyHeader, TenantHeader, EnvironmentHeader, MoneyFormatHeader, "X-User-ID", "X-File-Name",
+	}
+	defaultCORSExposedHeaders = []string{
+		"X-Reconciliation-Run-ID", "Idempotent-Replayed", "Retry-After", "Content-Disposition",
+	}
+)
+
+// LoadCORSConfigFromEnv carrega a política de CORS do ambiente: CORS_ALLOWED_ORIGINS, CORS_ALLOWED_METHODS,
+// CORS_ALLOWED_HEADERS e CORS_EXPOSED_HEADERS (listas separadas por vírgula), CORS_ALLOW_CREDENTIALS
+// (true/false) e CORS_MAX_AGE (segundos). Sem origens, o CORS fica desabilitado
+func LoadCORSConfigFromEnv() CORSConfig {
+	config := CORSConfig{
+		AllowedOrigins: envList("CORS_ALLOWED_ORIGINS", nil),
+		AllowedMethods: envList("CORS_ALLOWED_METHODS", defaultCORSMethods),
+		AllowedHeaders: envList("CORS_ALLOWED_HEADERS", defaultCORSHeaders),
+		ExposedHeaders: envList("CORS_EXPOSED_HEADERS", defaultCORSExposedHeaders),
+		MaxAgeSeconds:  envInt("CORS_MAX_AGE"),
+	}
+
+	if raw := os.Getenv("CORS_ALLOW_CREDENTIALS"); raw != "" {
+		allow, err := strconv.ParseBool(raw)
+		if err != nil {
+			log.Printf("CORS_ALLOW_CREDENTIALS inválido, credenciais não serão permitidas: %v", err)
+		}
+		config.AllowCredentials = allow
+	}
+
+	// Com credenciais, o navegador exige a origem explícita: aceitar qualquer origem exporia a sessão
+	// do dashboard a qualquer site
+	if config.AllowCredentials && config.allowsAnyOrigin() {
+		log.Printf("CORS_ALLOW_CREDENTIALS ignorado: não pode ser combinado com a origem \"*\"")
+		config.AllowCredentials = false
+	}
+
+	return config
+}
+
+// Enabled indica se a política aceita alguma origem
+func (c CORSConfig) Enabled() bool {
+	return len(c.AllowedOrigins) > 0
+}
+
+// allowsAnyOrigin indica se a política aceita qualquer origem
+func (c CORSConfig) allowsAnyOrigin() bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsOrigin indica se a origem da requisição é aceita pela política
+func (c CORSConfig) allowsOrigin(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+
+		// Curinga de subdomínio: https://*.empresa.com.br
+		if prefix, suffix, found := strings.Cut(allowed, "*"); found &&
+			len(origin) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(strings.ToLower(origin), strings.ToLower(prefix)) &&
+			strings.HasSuffix(strings.ToLower(origin), strings.ToLower(suffix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckWebSocketOrigin verifica a origem do upgrade de WebSocket pela mesma lista de origens do CORS.
+// O navegador não aplica o CORS ao WebSocket: sem a verificação, qualquer site poderia abrir a conexão
+// com a sessão do usuário. Clientes sem o header Origin, que não são navegadores, e a própria origem da
+// API são sempre aceitos
+func (c CORSConfig) CheckWebSocketOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || c.allowsOrigin(origin) {
+		return true
+	}
+
+	parsed, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(parsed.Host, r.Host)
+}
+
+// allowsMethod indica se o método solicitado no preflight é aceito pela política
+func (c CORSConfig) allowsMethod(method string) bool {
+	for _, allowed := range c.AllowedMethods {
+		if strings.EqualFold(allowed, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// CORS aplica a política de CORS. Requisições sem o header Origin seguem sem alteração; preflights de
+// origens ou métodos não aceitos são recusados com 403 e os demais são respondidos sem chegar às rotas
+func CORS(config CORSConfig) gin.HandlerFunc {
+	allowedMethods := strings.Join(config.AllowedMethods, ", ")
+	allowedHeaders := strings.Join(config.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join(config.ExposedHeaders, ", ")
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		// A resposta depende da origem e não pode ser reaproveitada por caches para outra
+		c.Writer.Header().Add("Vary", "Origin")
+
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		if !config.allowsOrigin(origin) {
+			if preflight {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "origem não permitida"})
+				return
+			}
+			// Sem os headers de CORS, o navegador não entrega a resposta ao cliente
+			c.Next()
+			return
+		}
+
+		if config.allowsAnyOrigin() && !config.AllowCredentials {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if config.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if exposedHeaders != "" {
+				c.Header("Access-Control-Expose-Headers", exposedHeaders)
+			}
+			c.Next()
+			return
+		}
+
+		if !config.allowsMethod(c.GetHeader("Access-Control-Request-Method")) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "método não permitido"})
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+		c.Header("Access-Control-Allow-Methods", allowedMethods)
+		if allowedHeaders != "" {
+			c.Header("Access-Control-Allow-Headers", allowedHeaders)
+		}
+		if config.MaxAgeSeconds > 0 {
+			c.Header("Access-Control-Max-Age", strconv.Itoa(config.MaxAgeSeconds))
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// envList lê uma lista separada por vírgula de uma variável de ambiente, retornando o padrão quando ausente
+func envList(key string, defaultValue []string) []string {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return defaultValue
+	}
+
+	var values []string
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// EnvironmentHeader define o header HTTP que escolhe o ambiente da requisição (production ou sandbox)
+const EnvironmentHeader = "X-Environment"
+
+// EnvironmentContext propaga o ambiente da requisição no contexto. No sandbox, os repositórios usam o
+// schema sandbox do shard do tenant e os eventos não são enviados a sistemas externos. Ambientes
+// desconhecidos são recusados para que um erro de digitação não grave dados de teste em produção
+func EnvironmentContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		environment := model.EnvironmentProduction
+		if value := strings.TrimSpace(c.GetHeader(EnvironmentHeader)); value != "" {
+			environment = model.Environment(strings.ToLower(value))
+		}
+
+		if !environment.IsValid() {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": "ambiente inválido no header " + EnvironmentHeader + ": use production ou sandbox",
+			})
+			return
+		}
+
+		c.Request = c.Request.WithContext(model.WithEnvironment(c.Request.Context(), environment))
+		c.Next()
+	}
+}
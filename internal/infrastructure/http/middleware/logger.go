@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Logger registra cada requisição com o método, a rota, o status, a duração e o tenant. A rota é a
+// declarada no router (ex.: /api/v1/billets/:id), para não gravar IDs de boletos e pagamentos no log
+func Logger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+
+		log.Printf("%s %s %d %s tenant=%s", c.Request.Method, path, c.Writer.Status(), time.Since(start), TenantID(c))
+	}
+}
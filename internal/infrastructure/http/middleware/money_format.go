@@ -0,0 +1,255 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MoneyFormatHeader e MoneyFormatQuery permitem ao cliente escolher a representação dos valores
+// monetários nas respostas; a query tem precedência sobre o header
+const (
+	MoneyFormatHeader = "X-Money-Format"
+	MoneyFormatQuery  = "money_format"
+)
+
+// MoneyFormat define a representação dos valores monetários nas respostas JSON
+type MoneyFormat string
+
+const (
+	// MoneyFormatDecimal mantém o número decimal (ex.: 150.75), o formato padrão
+	MoneyFormatDecimal MoneyFormat = "decimal"
+	// MoneyFormatString envia uma string com 2 casas decimais (ex.: "150.75")
+	MoneyFormatString MoneyFormat = "string"
+	// MoneyFormatCents envia os centavos como número inteiro (ex.: 15075)
+	MoneyFormatCents MoneyFormat = "centavos"
+)
+
+// moneyFields lista os campos JSON que representam valores monetários
+var moneyFields = map[string]bool{
+	"amount":                 true,
+	"amount_diff":            true,
+	"shadow_amount_diff":     true,
+	"min_amount":             true,
+	"open_amount":            true,
+	"pending_amount":         true,
+	"paid_amount":            true,
+	"reconciled_amount":      true,
+	"amount_imported_today":  true,
+	"ledger_balance":         true,
+	"system_balance":         true,
+	"difference":             true,
+	"explained_difference":   true,
+	"unexplained_difference": true,
+}
+
+// ParseMoneyFormat lê o formato monetário escolhido pelo cliente, usando decimal quando não informado
+func ParseMoneyFormat(r *http.Request) (MoneyFormat, error) {
+	value := r.URL.Query().Get(MoneyFormatQuery)
+	if value == "" {
+		value = r.Header.Get(MoneyFormatHeader)
+	}
+
+	switch format := MoneyFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case "", MoneyFormatDecimal:
+		return MoneyFormatDecimal, nil
+	case MoneyFormatString, MoneyFormatCents:
+		return format, nil
+	default:
+		return "", fmt.Errorf("formato monetário inválido: %q (use decimal, string ou centavos)", value)
+	}
+}
+
+// FormatMoneyResponses converte os valores monetários das respostas JSON para o formato escolhido
+// pelo cliente. Respostas em outros formatos e conexões WebSocket não são alteradas
+func FormatMoneyResponses() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format, err := ParseMoneyFormat(c.Request)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if format == MoneyFormatDecimal || c.IsWebsocket() {
+			c.Next()
+			return
+		}
+
+		writer := &bufferedResponseWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		body := writer.body.Bytes()
+		if strings.HasPrefix(writer.Header().Get("Content-Type"), "application/json") {
+			if formatted, err := FormatMoney(body, format); err == nil {
+				body = formatted
+			}
+		}
+
+		c.Writer.Header().Del("Content-Length")
+		c.Writer.WriteHeader(writer.status)
+		c.Writer.Write(body)
+	}
+}
+
+// bufferedResponseWriter retém a resposta para que ela possa ser reescrita antes do envio
+type bufferedResponseWriter struct {
+	gin.ResponseWriter
+	body   bytes.Buffer
+	status int
+}
+
+// WriteHeader guarda o status da resposta
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+// WriteHeaderNow é adiado até a reescrita do corpo
+func (w *bufferedResponseWriter) WriteHeaderNow() {}
+
+// Write guarda o corpo da resposta
+func (w *bufferedResponseWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+// WriteString guarda o corpo da resposta
+func (w *bufferedResponseWriter) WriteString(data string) (int, error) {
+	return w.body.WriteString(data)
+}
+
+// Status retorna o status guardado
+func (w *bufferedResponseWriter) Status() int {
+	return w.status
+}
+
+// Size retorna o tamanho do corpo guardado
+func (w *bufferedResponseWriter) Size() int {
+	return w.body.Len()
+}
+
+// Written indica se algo já foi escrito na resposta
+func (w *bufferedResponseWriter) Written() bool {
+	return w.body.Len() > 0
+}
+
+// jsonFrame acompanha um objeto ou lista em reescrita
+type jsonFrame struct {
+	object    bool
+	expectKey bool
+	count     int
+	key       string
+}
+
+// FormatMoney reescreve os campos monetários de um documento JSON no formato informado,
+// preservando a ordem dos campos
+func FormatMoney(body []byte, format MoneyFormat) ([]byte, error) {
+	if format == MoneyFormatDecimal {
+		return body, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var out bytes.Buffer
+	var stack []*jsonFrame
+
+	// separator escreve a vírgula ou os dois-pontos que antecedem o próximo token
+	separator := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := stack[len(stack)-1]
+		switch {
+		case top.object && !top.expectKey:
+			out.WriteByte(':')
+		case top.count > 0:
+			out.WriteByte(',')
+		}
+	}
+
+	// valueWritten registra um valor completo no objeto ou lista atual
+	valueWritten := func() {
+		if len(stack) == 0 {
+			out.WriteByte('\n')
+			return
+		}
+		top := stack[len(stack)-1]
+		top.count++
+		if top.object {
+			top.expectKey = true
+		}
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch value := token.(type) {
+		case json.Delim:
+			switch value {
+			case '{', '[':
+				separator()
+				out.WriteByte(byte(value))
+				stack = append(stack, &jsonFrame{object: value == '{', expectKey: value == '{'})
+			default:
+				out.WriteByte(byte(value))
+				stack = stack[:len(stack)-1]
+				valueWritten()
+			}
+			continue
+		case string:
+			if top := len(stack); top > 0 && stack[top-1].object && stack[top-1].expectKey {
+				separator()
+				encoded, _ := json.Marshal(value)
+				out.Write(encoded)
+				stack[top-1].expectKey = false
+				stack[top-1].key = value
+				continue
+			}
+		}
+
+		separator()
+		if number, ok := token.(json.Number); ok && len(stack) > 0 && stack[len(stack)-1].object && moneyFields[stack[len(stack)-1].key] {
+			formatted, err := formatAmount(number, format)
+			if err != nil {
+				return nil, err
+			}
+			out.WriteString(formatted)
+		} else {
+			encoded, err := json.Marshal(token)
+			if err != nil {
+				return nil, err
+			}
+			out.Write(encoded)
+		}
+		valueWritten()
+	}
+
+	return out.Bytes(), nil
+}
+
+// formatAmount converte um valor monetário para o formato informado
+func formatAmount(number json.Number, format MoneyFormat) (string, error) {
+	amount, err := number.Float64()
+	if err != nil {
+		return "", err
+	}
+
+	if format == MoneyFormatCents {
+		return strconv.FormatInt(int64(math.Round(amount*100)), 10), nil
+	}
+	return strconv.Quote(strconv.FormatFloat(amount, 'f', 2, 64)), nil
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/infrastructure/importer"
+)
+
+// LimitImportPayload rejeita corpos de importação acima do tamanho máximo (413) e converte o corpo
+// para UTF-8 a partir do charset do Content-Type (ou do encoding padrão configurado), rejeitando
+// conteúdo inválido (400) antes que chegue aos parsers e às quotas. Com o arquivamento configurado,
+// o corpo original é preservado para auditoria
+func LimitImportPayload(limits importer.Limits, defaultEncoding string, archive *importer.Archive) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > limits.MaxBytes {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": importer.ErrInputTooLarge.Error()})
+			return
+		}
+
+		raw, err := importer.ReadAll(c.Request.Body, limits)
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, importer.ErrInputTooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		if archive != nil {
+			if _, err := archive.Save(TenantID(c)+".json", raw); err != nil {
+				log.Printf("erro ao arquivar payload de importação: %v", err)
+			}
+		}
+
+		encoding := defaultEncoding
+		if _, params, err := mime.ParseMediaType(c.GetHeader("Content-Type")); err == nil && params["charset"] != "" {
+			encoding = params["charset"]
+		}
+
+		body, _, err := importer.ToUTF8(raw, encoding)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Request.ContentLength = int64(len(body))
+
+		c.Next()
+	}
+}
@@ -0,0 +1,295 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// quotaWindow define a janela de contagem de linhas importadas
+const quotaWindow = time.Minute
+
+// quotaLimiterKey guarda no contexto da requisição o QuotaLimiter das rotas que só conhecem as linhas
+// importadas depois de interpretar o arquivo
+const quotaLimiterKey = "quota_limiter"
+
+// TenantQuota define os limites de consumo de um tenant. Zero significa sem limite
+type TenantQuota struct {
+	ImportRowsPerMinute          int `json:"import_rows_per_minute"`
+	MaxConcurrentReconciliations int `json:"max_concurrent_reconciliations"`
+}
+
+// QuotaUsage representa o consumo de quota de um tenant, exposto como métrica
+type QuotaUsage struct {
+	Tenant                  string      `json:"tenant"`
+	Quota                   TenantQuota `json:"quota"`
+	ImportedRowsInWindow    int         `json:"imported_rows_in_window"`
+	WindowResetAt           time.Time   `json:"window_reset_at"`
+	RunningReconciliations  int         `json:"running_reconciliations"`
+	TotalImportedRows       int64       `json:"total_imported_rows"`
+	RejectedImports         int64       `json:"rejected_imports"`
+	RejectedReconciliations int64       `json:"rejected_reconciliations"`
+}
+
+// tenantState guarda o consumo corrente de um tenant
+type tenantState struct {
+	windowStart             time.Time
+	rowsInWindow            int
+	running                 int
+	totalImportedRows       int64
+	rejectedImports         int64
+	rejectedReconciliations int64
+}
+
+// QuotaLimiter aplica quotas de importação e de execuções concorrentes de conciliação por tenant
+type QuotaLimiter struct {
+	mu        sync.Mutex
+	defaults  TenantQuota
+	overrides map[string]TenantQuota
+	tenants   map[string]*tenantState
+}
+
+// NewQuotaLimiter cria uma nova instância de QuotaLimiter
+func NewQuotaLimiter(defaults TenantQuota, overrides map[string]TenantQuota) *QuotaLimiter {
+	if overrides == nil {
+		overrides = make(map[string]TenantQuota)
+	}
+
+	return &QuotaLimiter{
+		defaults:  defaults,
+		overrides: overrides,
+		tenants:   make(map[string]*tenantState),
+	}
+}
+
+// NewQuotaLimiterFromEnv cria um QuotaLimiter a partir das variáveis de ambiente:
+// QUOTA_IMPORT_ROWS_PER_MINUTE e QUOTA_MAX_CONCURRENT_RECONCILIATIONS definem o padrão e
+// TENANT_QUOTAS (JSON no formato {"tenant": {"import_rows_per_minute": 1000, ...}}) define exceções por tenant
+func NewQuotaLimiterFromEnv() *QuotaLimiter {
+	defaults := TenantQuota{
+		ImportRowsPerMinute:          envInt("QUOTA_IMPORT_ROWS_PER_MINUTE"),
+		MaxConcurrentReconciliations: envInt("QUOTA_MAX_CONCURRENT_RECONCILIATIONS"),
+	}
+
+	overrides := make(map[string]TenantQuota)
+	if raw := os.Getenv("TENANT_QUOTAS"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &overrides); err != nil {
+			log.Printf("TENANT_QUOTAS inválido, usando apenas as quotas padrão: %v", err)
+			overrides = make(map[string]TenantQuota)
+		}
+	}
+
+	return NewQuotaLimiter(defaults, overrides)
+}
+
+// LimitImportRows limita a quantidade de linhas importadas por minuto por tenant.
+// As linhas são contadas a partir do corpo da requisição (objeto único ou lista)
+func (l *QuotaLimiter) LimitImportRows() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "erro ao ler corpo da requisição"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if !l.chargeRows(c, countRows(body)) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// MeterImportRows disponibiliza o limitador aos handlers de importação de arquivos bancários (CNAB, CSV,
+// OFX), cujas linhas só são conhecidas depois de interpretado o arquivo. O handler as cobra com
+// ConsumeImportRows
+func (l *QuotaLimiter) MeterImportRows() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(quotaLimiterKey, l)
+		c.Next()
+	}
+}
+
+// ConsumeImportRows cobra as linhas interpretadas de um arquivo na quota de importação do tenant. Com a
+// quota excedida, a requisição é abortada com 429 e o retorno é false. Rotas sem MeterImportRows não
+// têm limite
+func ConsumeImportRows(c *gin.Context, rows int) bool {
+	value, ok := c.Get(quotaLimiterKey)
+	if !ok {
+		return true
+	}
+
+	limiter, ok := value.(*QuotaLimiter)
+	if !ok {
+		return true
+	}
+	return limiter.chargeRows(c, rows)
+}
+
+// chargeRows cobra as linhas na quota do tenant da requisição, abortando-a com 429 quando excedida
+func (l *QuotaLimiter) chargeRows(c *gin.Context, rows int) bool {
+	tenant := TenantID(c)
+	allowed, retryAfter := l.consumeRows(tenant, rows)
+	if !allowed {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"error":  "quota de importação por minuto excedida",
+			"tenant": tenant,
+		})
+		return false
+	}
+	return true
+}
+
+// LimitConcurrentReconciliations limita a quantidade de conciliações executando ao mesmo tempo por tenant
+func (l *QuotaLimiter) LimitConcurrentReconciliations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenant := TenantID(c)
+		if !l.acquireRun(tenant) {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":  "limite de conciliações simultâneas excedido",
+				"tenant": tenant,
+			})
+			return
+		}
+		defer l.releaseRun(tenant)
+
+		c.Next()
+	}
+}
+
+// Usage retorna o consumo de quota corrente de um tenant
+func (l *QuotaLimiter) Usage(tenant string) QuotaUsage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state := l.stateFor(tenant)
+	return QuotaUsage{
+		Tenant:                  tenant,
+		Quota:                   l.quotaFor(tenant),
+		ImportedRowsInWindow:    state.rowsInWindow,
+		WindowResetAt:           state.windowStart.Add(quotaWindow),
+		RunningReconciliations:  state.running,
+		TotalImportedRows:       state.totalImportedRows,
+		RejectedImports:         state.rejectedImports,
+		RejectedReconciliations: state.rejectedReconciliations,
+	}
+}
+
+// consumeRows registra linhas importadas na janela corrente, retornando se a importação é permitida
+// e, em caso negativo, quanto tempo falta para a janela reiniciar
+func (l *QuotaLimiter) consumeRows(tenant string, rows int) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state := l.stateFor(tenant)
+	limit := l.quotaFor(tenant).ImportRowsPerMinute
+
+	if limit > 0 && state.rowsInWindow+rows > limit {
+		state.rejectedImports++
+		return false, state.windowStart.Add(quotaWindow).Sub(time.Now())
+	}
+
+	state.rowsInWindow += rows
+	state.totalImportedRows += int64(rows)
+	return true, 0
+}
+
+// acquireRun reserva uma execução de conciliação para o tenant, se houver quota disponível
+func (l *QuotaLimiter) acquireRun(tenant string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state := l.stateFor(tenant)
+	limit := l.quotaFor(tenant).MaxConcurrentReconciliations
+
+	if limit > 0 && state.running >= limit {
+		state.rejectedReconciliations++
+		return false
+	}
+
+	state.running++
+	return true
+}
+
+// releaseRun libera uma execução de conciliação do tenant
+func (l *QuotaLimiter) releaseRun(tenant string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if state := l.stateFor(tenant); state.running > 0 {
+		state.running--
+	}
+}
+
+// stateFor retorna o estado do tenant, reiniciando a janela de importação quando expirada.
+// Deve ser chamado com o mutex adquirido
+func (l *QuotaLimiter) stateFor(tenant string) *tenantState {
+	now := time.Now()
+
+	state, ok := l.tenants[tenant]
+	if !ok {
+		state = &tenantState{windowStart: now}
+		l.tenants[tenant] = state
+	}
+
+	if now.Sub(state.windowStart) >= quotaWindow {
+		state.windowStart = now
+		state.rowsInWindow = 0
+	}
+
+	return state
+}
+
+// quotaFor retorna a quota configurada para o tenant
+func (l *QuotaLimiter) quotaFor(tenant string) TenantQuota {
+	if quota, ok := l.overrides[tenant]; ok {
+		return quota
+	}
+	return l.defaults
+}
+
+// countRows conta as linhas de uma importação: uma lista conta seus elementos, um objeto com
+// listas (ex.: {"billets": [...]}) conta os elementos das listas e qualquer outro objeto conta uma linha
+func countRows(body []byte) int {
+	var list []json.RawMessage
+	if err := json.Unmarshal(body, &list); err == nil {
+		return len(list)
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(body, &object); err != nil {
+		return 1
+	}
+
+	rows := 0
+	for _, value := range object {
+		if err := json.Unmarshal(value, &list); err == nil {
+			rows += len(list)
+		}
+	}
+
+	if rows == 0 {
+		return 1
+	}
+	return rows
+}
+
+// envInt lê uma variável de ambiente inteira, retornando zero quando ausente ou inválida
+func envInt(key string) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 0 {
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestConsumeImportRows garante que as linhas interpretadas pelos handlers de arquivo contam na quota de
+// importação do tenant, como as linhas dos payloads JSON
+func TestConsumeImportRows(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := NewQuotaLimiter(TenantQuota{ImportRowsPerMinute: 10}, nil)
+
+	router := gin.New()
+	parsed := func(c *gin.Context) {
+		rows, _ := strconv.Atoi(c.Query("rows"))
+		if !ConsumeImportRows(c, rows) {
+			return
+		}
+		c.Status(http.StatusCreated)
+	}
+	router.POST("/imports", limiter.MeterImportRows(), parsed)
+	router.POST("/unmetered", parsed)
+
+	steps := []struct {
+		name       string
+		path       string
+		tenant     string
+		rows       int
+		wantStatus int
+	}{
+		{name: "dentro da quota", path: "/imports", tenant: "acme", rows: 6, wantStatus: http.StatusCreated},
+		{name: "excede a quota", path: "/imports", tenant: "acme", rows: 6, wantStatus: http.StatusTooManyRequests},
+		{name: "completa a quota", path: "/imports", tenant: "acme", rows: 4, wantStatus: http.StatusCreated},
+		{name: "outro tenant tem a própria quota", path: "/imports", tenant: "globex", rows: 10, wantStatus: http.StatusCreated},
+		{name: "rota sem medição não tem limite", path: "/unmetered", tenant: "acme", rows: 100, wantStatus: http.StatusCreated},
+	}
+
+	for _, step := range steps {
+		req := httptest.NewRequest(http.MethodPost, step.path+"?rows="+strconv.Itoa(step.rows), nil)
+		req.Header.Set(TenantHeader, step.tenant)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != step.wantStatus {
+			t.Fatalf("%s: status %d, esperado %d", step.name, rec.Code, step.wantStatus)
+		}
+		if step.wantStatus == http.StatusTooManyRequests && rec.Header().Get("Retry-After") == "" {
+			t.Fatalf("%s: resposta 429 sem Retry-After", step.name)
+		}
+	}
+
+	if usage := limiter.Usage("acme"); usage.ImportedRowsInWindow != 10 || usage.RejectedImports != 1 {
+		t.Fatalf("consumo de acme inesperado: %+v", usage)
+	}
+}
+
+// TestLimitImportRows garante que as linhas dos payloads JSON contam na quota do tenant, que as exceções
+// por tenant substituem a quota padrão e que quota zero não limita
+func TestLimitImportRows(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := NewQuotaLimiter(TenantQuota{ImportRowsPerMinute: 3}, map[string]TenantQuota{
+		"globex":  {ImportRowsPerMinute: 5},
+		"initech": {},
+	})
+
+	router := gin.New()
+	router.POST("/payments", limiter.LimitImportRows(), func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	steps := []struct {
+		name       string
+		tenant     string
+		body       string
+		wantStatus int
+	}{
+		{name: "objeto único conta uma linha", tenant: "acme", body: `{"transaction_id": "T1"}`, wantStatus: http.StatusCreated},
+		{name: "lista conta os elementos", tenant: "acme", body: `[{}, {}]`, wantStatus: http.StatusCreated},
+		{name: "quota padrão esgotada", tenant: "acme", body: `{"transaction_id": "T4"}`, wantStatus: http.StatusTooManyRequests},
+		{name: "exceção do tenant amplia a quota", tenant: "globex", body: `{"payments": [{}, {}, {}, {}, {}]}`, wantStatus: http.StatusCreated},
+		{name: "exceção do tenant esgotada", tenant: "globex", body: `[{}]`, wantStatus: http.StatusTooManyRequests},
+		{name: "exceção sem limite", tenant: "initech", body: `[{}, {}, {}, {}, {}, {}, {}, {}]`, wantStatus: http.StatusCreated},
+	}
+
+	for _, step := range steps {
+		req := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(step.body))
+		req.Header.Set(TenantHeader, step.tenant)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != step.wantStatus {
+			t.Fatalf("%s: status %d, esperado %d", step.name, rec.Code, step.wantStatus)
+		}
+	}
+
+	if usage := limiter.Usage("acme"); usage.ImportedRowsInWindow != 3 || usage.TotalImportedRows != 3 || usage.RejectedImports != 1 {
+		t.Fatalf("consumo de acme inesperado: %+v", usage)
+	}
+	if usage := limiter.Usage("globex"); usage.Quota.ImportRowsPerMinute != 5 || usage.ImportedRowsInWindow != 5 {
+		t.Fatalf("consumo de globex inesperado: %+v", usage)
+	}
+}
+
+// TestLimitConcurrentReconciliations garante que cada tenant tem o próprio limite de execuções simultâneas
+// e que a execução concluída libera a vaga
+func TestLimitConcurrentReconciliations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		tenant     string
+		running    int
+		wantStatus int
+	}{
+		{name: "sem execuções em andamento", tenant: "acme", running: 0, wantStatus: http.StatusAccepted},
+		{name: "abaixo do limite", tenant: "acme", running: 1, wantStatus: http.StatusAccepted},
+		{name: "no limite", tenant: "acme", running: 2, wantStatus: http.StatusTooManyRequests},
+		{name: "exceção do tenant", tenant: "globex", running: 2, wantStatus: http.StatusAccepted},
+		{name: "exceção sem limite", tenant: "initech", running: 10, wantStatus: http.StatusAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewQuotaLimiter(TenantQuota{MaxConcurrentReconciliations: 2}, map[string]TenantQuota{
+				"globex":  {MaxConcurrentReconciliations: 3},
+				"initech": {},
+			})
+			for i := 0; i < tt.running; i++ {
+				limiter.acquireRun(tt.tenant)
+			}
+
+			router := gin.New()
+			router.POST("/reconciliations", limiter.LimitConcurrentReconciliations(), func(c *gin.Context) {
+				c.Status(http.StatusAccepted)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/reconciliations", nil)
+			req.Header.Set(TenantHeader, tt.tenant)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, esperado %d", rec.Code, tt.wantStatus)
+			}
+			if usage := limiter.Usage(tt.tenant); usage.RunningReconciliations != tt.running {
+				t.Fatalf("%d execuções em andamento após a requisição, esperado %d", usage.RunningReconciliations, tt.running)
+			}
+		})
+	}
+}
+
+// TestCountRows garante a contagem de linhas dos formatos de payload aceitos pelas rotas de importação
+func TestCountRows(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"objeto único", `{"billet_id": "B1"}`, 1},
+		{"lista", `[{}, {}, {}]`, 3},
+		{"lista vazia", `[]`, 0},
+		{"objeto com listas", `{"billets": [{}, {}], "payments": [{}]}`, 3},
+		{"objeto com listas vazias", `{"billets": []}`, 1},
+		{"corpo inválido", `não é json`, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countRows([]byte(tt.body)); got != tt.want {
+				t.Fatalf("%d linhas, esperado %d", got, tt.want)
+			}
+		})
+	}
+}
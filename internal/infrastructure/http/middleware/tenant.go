@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// TenantHeader define o header HTTP que identifica o tenant da requisição
+const TenantHeader = "X-Tenant-ID"
+
+// DefaultTenant identifica as requisições que não informam o tenant
+const DefaultTenant = "default"
+
+// TenantID retorna o tenant da requisição, ou DefaultTenant quando o header não foi informado
+func TenantID(c *gin.Context) string {
+	if tenant := c.GetHeader(TenantHeader); tenant != "" {
+		return tenant
+	}
+	return DefaultTenant
+}
+
+// TenantContext propaga o tenant da requisição no contexto, usado pelos repositórios para
+// rotear as consultas ao shard do tenant
+func TenantContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(model.WithTenant(c.Request.Context(), TenantID(c)))
+		c.Next()
+	}
+}
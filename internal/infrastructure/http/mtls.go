@@ -0,0 +1,60 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// MTLSConfig representa a configuração do listener com certificado de cliente obrigatório
+type MTLSConfig struct {
+	Port         string
+	CertFile     string // Certificado do servidor
+	KeyFile      string // Chave privada do servidor
+	ClientCAFile string // CA que emite os certificados dos parceiros
+}
+
+// MTLSConfigFromEnv lê a configuração das variáveis MTLS_PORT, MTLS_CERT_FILE, MTLS_KEY_FILE e
+// MTLS_CLIENT_CA_FILE. Retorna false quando o listener mTLS não está habilitado
+func MTLSConfigFromEnv() (MTLSConfig, bool) {
+	config := MTLSConfig{
+		Port:         os.Getenv("MTLS_PORT"),
+		CertFile:     os.Getenv("MTLS_CERT_FILE"),
+		KeyFile:      os.Getenv("MTLS_KEY_FILE"),
+		ClientCAFile: os.Getenv("MTLS_CLIENT_CA_FILE"),
+	}
+
+	return config, config.Port != ""
+}
+
+// NewMTLSServer cria o servidor HTTP do listener separado que exige e valida o certificado
+// de cliente contra a CA configurada. Inicie com ListenAndServeTLS(config.CertFile, config.KeyFile)
+func NewMTLSServer(handler http.Handler, config MTLSConfig) (*http.Server, error) {
+	if config.CertFile == "" || config.KeyFile == "" || config.ClientCAFile == "" {
+		return nil, fmt.Errorf("MTLS_CERT_FILE, MTLS_KEY_FILE e MTLS_CLIENT_CA_FILE são obrigatórios com MTLS_PORT")
+	}
+
+	caPEM, err := os.ReadFile(config.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler CA dos clientes: %w", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("CA dos clientes inválida: %s", config.ClientCAFile)
+	}
+
+	return &http.Server{
+		Addr:    ":" + config.Port,
+		Handler: handler,
+		TLSConfig: &tls.Config{
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			ClientCAs:  clientCAs,
+			MinVersion: tls.VersionTLS12,
+		},
+		ReadHeaderTimeout: 10 * time.Second,
+	}, nil
+}
@@ -3,21 +3,47 @@ package http
 import (
 	"log"
 	"net/http"
+	"net/http/pprof"
+	"os"
 
 	"github.com/gin-gonic/gin"
 
 	"conciliacao-bancaria/internal/infrastructure/http/handler"
 	"conciliacao-bancaria/internal/infrastructure/http/middleware"
+	"conciliacao-bancaria/internal/infrastructure/importer"
+	"conciliacao-bancaria/internal/infrastructure/siem"
 )
 
 // SetupRouter configura todas as rotas da API e retorna o router
 func SetupRouter(
 	billetHandler *handler.BilletHandler,
 	paymentHandler *handler.PaymentHandler,
-	reconciliationHandler *handler.ReconciliationHandler) *gin.Engine {
+	reconciliationHandler *handler.ReconciliationHandler,
+	subscriptionHandler *handler.SubscriptionHandler,
+	rankerHandler *handler.RankerHandler,
+	apiKeyHandler *handler.APIKeyHandler,
+	importHandler *handler.ImportHandler,
+	outboxHandler *handler.OutboxHandler,
+	statsHandler *handler.StatsHandler,
+	reportHandler *handler.ReportHandler,
+	closingHandler *handler.ClosingHandler,
+	pendingReviewHandler *handler.PendingReviewHandler,
+	timelineHandler *handler.TimelineHandler,
+	lookupHandler *handler.LookupHandler,
+	bankAccountHandler *handler.BankAccountHandler,
+	unappliedCreditHandler *handler.UnappliedCreditHandler,
+	exportHandler *handler.ExportHandler,
+	ruleSetHandler *handler.RuleSetHandler,
+	notificationTemplateHandler *handler.NotificationTemplateHandler,
+	workingPaperHandler *handler.WorkingPaperHandler,
+	selfTestHandler *handler.SelfTestHandler,
+	erpDriftHandler *handler.ERPDriftHandler,
+	erpSyncHandler *handler.ERPSyncHandler,
+	maintenanceHandler *handler.MaintenanceHandler,
+	apiKeyAuthenticator middleware.APIKeyAuthenticator) *gin.Engine {
 
-	// Inicializa o router Gin com o modo definido
-	r := gin.Default()
+	// Inicializa o router Gin
+	r := gin.New()
 
 	// Middleware para logging de requisições
 	r.Use(middleware.Logger())
@@ -25,6 +51,31 @@ func SetupRouter(
 	// Middleware para recuperação de pânico
 	r.Use(gin.Recovery())
 
+	// Política de CORS do frontend web e do dashboard, habilitada quando CORS_ALLOWED_ORIGINS estiver configurado
+	if cors := middleware.LoadCORSConfigFromEnv(); cors.Enabled() {
+		r.Use(middleware.CORS(cors))
+	}
+
+	// Identificação de parceiros pelo certificado de cliente no listener mTLS
+	if identities := middleware.LoadClientIdentitiesFromEnv(); len(identities) > 0 {
+		r.Use(middleware.RequireClientCertificate(identities))
+	}
+
+	// Tenant no contexto para o roteamento dos repositórios ao shard do tenant
+	r.Use(middleware.TenantContext())
+
+	// Ambiente da requisição (produção ou sandbox) no contexto, para isolar os dados de teste dos integradores
+	r.Use(middleware.EnvironmentContext())
+
+	// Formato dos valores monetários nas respostas (decimal, string ou centavos) escolhido pelo cliente
+	r.Use(middleware.FormatMoneyResponses())
+
+	// Quotas de importação e de conciliações simultâneas por tenant
+	quotas := middleware.NewQuotaLimiterFromEnv()
+
+	// Limites de tamanho, normalização de encoding e arquivamento dos payloads de importação
+	importPayload := middleware.LimitImportPayload(importer.LimitsFromEnv(), importer.EncodingFromEnv(), importer.ArchiveFromEnv())
+
 	// Rota básica para verificação de saúde da API
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -34,49 +85,295 @@ func SetupRouter(
 
 	// Configuração da versão da API
 	v1 := r.Group("/api/v1")
+
+	// Encaminhamento de auditoria ao SIEM, habilitado quando SIEM_TRANSPORT estiver configurado.
+	// Registrado antes da autenticação para também auditar as API keys recusadas
+	if exporter := siem.NewExporterFromEnv(); exporter != nil {
+		v1.Use(middleware.Audit(exporter))
+	}
+
+	// Autenticação por API key com escopos de permissão e de contas, habilitada quando há um autenticador
+	if apiKeyAuthenticator != nil {
+		v1.Use(middleware.RequireAPIKey(apiKeyAuthenticator))
+	}
+
 	{
 		// Rotas para boletos
 		billets := v1.Group("/billets")
 		{
-			billets.POST("", billetHandler.CreateBillet)
-			billets.POST("/batch", billetHandler.CreateBilletBatch)
+			billets.POST("", importPayload, quotas.LimitImportRows(), billetHandler.CreateBillet)
+			billets.POST("/batch", importPayload, quotas.LimitImportRows(), billetHandler.CreateBilletBatch)
 			billets.GET("", billetHandler.ListBillets)
+			billets.POST("/lookup", lookupHandler.LookupBillets)
 			billets.GET("/:id", billetHandler.GetBillet)
 			billets.PUT("/:id", billetHandler.UpdateBillet)
 			billets.DELETE("/:id", billetHandler.DeleteBillet)
+
+			// Rota para refazer o matching de um único boleto
+			billets.POST("/:id/rematch", quotas.LimitConcurrentReconciliations(), reconciliationHandler.RematchBillet)
+
+			// Rota para reavaliar as conciliações com valor diferente após a correção do valor do boleto
+			billets.POST("/:id/reevaluate", reconciliationHandler.ReevaluateBillet)
+
+			// Rotas para bloqueio de boletos em investigação
+			billets.POST("/:id/claim", reconciliationHandler.ClaimBillet)
+			billets.GET("/:id/claim", reconciliationHandler.GetBilletClaim)
+			billets.DELETE("/:id/claim", reconciliationHandler.ReleaseBilletClaim)
+
+			// Rotas para a linha do tempo unificada do boleto e os comentários do suporte
+			billets.GET("/:id/timeline", timelineHandler.GetBilletTimeline)
+			billets.POST("/:id/comments", timelineHandler.AddBilletComment)
+		}
+
+		// Rotas para contratos
+		contracts := v1.Group("/contracts")
+		{
+			contracts.GET("/:id/billets", billetHandler.ListContractBillets)
+			contracts.GET("/:id/statistics", billetHandler.GetContractStatistics)
 		}
 
 		// Rotas para pagamentos
 		payments := v1.Group("/payments")
 		{
-			payments.POST("", paymentHandler.CreatePayment)
-			payments.POST("/batch", paymentHandler.CreatePaymentBatch)
+			payments.POST("", importPayload, quotas.LimitImportRows(), paymentHandler.CreatePayment)
+			payments.POST("/batch", importPayload, quotas.LimitImportRows(), paymentHandler.CreatePaymentBatch)
 			payments.GET("", paymentHandler.ListPayments)
+			payments.POST("/lookup", lookupHandler.LookupPayments)
 			payments.GET("/:id", paymentHandler.GetPayment)
 			payments.PUT("/:id", paymentHandler.UpdatePayment)
 			payments.DELETE("/:id", paymentHandler.DeletePayment)
+
+			// Rotas para a linha do tempo unificada do pagamento e os comentários do suporte
+			payments.GET("/:id/timeline", timelineHandler.GetPaymentTimeline)
+			payments.POST("/:id/comments", timelineHandler.AddPaymentComment)
+		}
+
+		// Rotas para importação de arquivos bancários (CNAB)
+		imports := v1.Group("/imports")
+		{
+			imports.POST("", importPayload, quotas.MeterImportRows(), importHandler.ImportCNAB)
+			imports.POST("/statements", importPayload, quotas.MeterImportRows(), importHandler.ImportStatement)
+			imports.GET("", importHandler.ListImportFiles)
+
+			// Rota para consultar as lacunas de numeração sequencial por convênio (arquivos perdidos)
+			imports.GET("/gaps", importHandler.ListGaps)
 		}
 
 		// Rotas para conciliação
 		reconciliations := v1.Group("/reconciliations")
 		{
 			// Rota para iniciar uma nova conciliação
-			reconciliations.POST("", reconciliationHandler.CreateReconciliation)
+			reconciliations.POST("", quotas.LimitConcurrentReconciliations(), reconciliationHandler.RunReconciliation)
+
+			// Rota para simular a conciliação com várias tolerâncias, sem persistir nada
+			reconciliations.POST("/what-if", quotas.LimitConcurrentReconciliations(), reconciliationHandler.SimulateTolerances)
+
+			// Rota para comparar o saldo de contas a receber do razão contábil com as conciliações do período
+			reconciliations.POST("/ledger-comparison", reconciliationHandler.CompareLedger)
 
 			// Rota para conciliar boletos e pagamentos específicos
-			reconciliations.POST("/specific", reconciliationHandler.ReconcileSpecific)
+			reconciliations.POST("/specific", quotas.LimitConcurrentReconciliations(), reconciliationHandler.ReconcileSpecific)
+
+			// Rota para conciliar manualmente um boleto com um pagamento
+			reconciliations.POST("/manual", reconciliationHandler.ManualMatch)
 
 			// Rota para listar todas as conciliações
 			reconciliations.GET("", reconciliationHandler.ListReconciliations)
 
+			// Rotas para consultar as execuções de conciliação e as conciliações gravadas em cada uma
+			reconciliations.GET("/runs", reconciliationHandler.ListRuns)
+			reconciliations.GET("/runs/:id", reconciliationHandler.GetRunItems)
+
 			// Rota para obter detalhes de uma conciliação específica
 			reconciliations.GET("/:id", reconciliationHandler.GetReconciliation)
 
+			// Rota para desfazer uma conciliação, devolvendo o boleto e o pagamento à situação de não conciliados
+			reconciliations.DELETE("/:id/match", reconciliationHandler.UndoMatch)
+
+			// Rotas para aprovar ou rejeitar um pareamento que aguarda aprovação: sugerido pela estratégia
+			// conta/valor/data ou com diferença de valor
+			reconciliations.POST("/:id/approve", reconciliationHandler.ApproveReconciliation)
+			reconciliations.POST("/:id/reject", reconciliationHandler.RejectReconciliation)
+
 			// Rota para obter histórico de conciliações de um boleto
 			reconciliations.GET("/billet/:id", reconciliationHandler.GetBilletReconciliationHistory)
 
+			// Rota para obter o histórico de mudanças de status das conciliações de um boleto
+			reconciliations.GET("/billet/:id/status-changes", reconciliationHandler.GetBilletStatusChanges)
+
+			// Rota para obter as aprovações e rejeições dos pareamentos de um boleto
+			reconciliations.GET("/billet/:id/approvals", reconciliationHandler.GetBilletApprovals)
+
 			// Rota para obter histórico de conciliações de um pagamento
 			reconciliations.GET("/payment/:id", reconciliationHandler.GetPaymentReconciliationHistory)
+
+			// Rota para consultar os lançamentos de débito excluídos da conciliação
+			reconciliations.GET("/excluded-payments", reconciliationHandler.ListExcludedPayments)
+
+			// Rotas para revisão dos pagamentos com valor suspeito (outliers)
+			reconciliations.GET("/suspicious-payments", reconciliationHandler.ListSuspiciousPayments)
+			reconciliations.POST("/suspicious-payments/:id/approve", reconciliationHandler.ApproveSuspiciousPayment)
+
+			// Rotas para os pagamentos ignorados por estarem abaixo do valor mínimo da conciliação automática
+			reconciliations.GET("/ignored-payments", reconciliationHandler.ListIgnoredPayments)
+			reconciliations.POST("/ignored-payments/:id/force", reconciliationHandler.ForceIgnoredPayment)
+		}
+
+		// Rota para consultar uma execução de conciliação com as medições usadas no planejamento de capacidade
+		runs := v1.Group("/reconciliation-runs")
+		{
+			runs.GET("/:id", reconciliationHandler.GetRun)
+
+			// Rota para o relatório de divergência entre o motor de produção e o motor shadow na execução
+			runs.GET("/:id/shadow", reconciliationHandler.GetShadowReport)
+		}
+
+		// Rotas para os créditos não aplicados: sobras de pagamentos divididos entre boletos do mesmo pagador
+		credits := v1.Group("/credits")
+		{
+			credits.GET("", unappliedCreditHandler.ListCredits)
+			credits.GET("/:id", unappliedCreditHandler.GetCredit)
+			credits.POST("/:id/apply", unappliedCreditHandler.ApplyCredit)
+		}
+
+		// Rotas para os arquivos de resultado das execuções enviados ao ERP, com reenvio manual
+		exports := v1.Group("/exports")
+		{
+			exports.POST("", exportHandler.ExportRun)
+			exports.GET("", exportHandler.ListExports)
+			exports.GET("/:id", exportHandler.GetExport)
+			exports.GET("/:id/file", exportHandler.DownloadExport)
+			exports.POST("/:id/resend", exportHandler.ResendExport)
+		}
+
+		// Rotas para o fechamento diário, que congela os resultados do dia ao ser confirmado
+		closings := v1.Group("/closings")
+		{
+			closings.POST("", closingHandler.OpenClosing)
+			closings.GET("", closingHandler.ListClosings)
+			closings.GET("/:id", closingHandler.GetClosing)
+			closings.GET("/:id/report", closingHandler.ExportClosingReport)
+			closings.POST("/:id/confirm", closingHandler.ConfirmClosing)
+		}
+
+		// Rotas para assinaturas de eventos (webhooks)
+		subscriptions := v1.Group("/subscriptions")
+		{
+			subscriptions.POST("", subscriptionHandler.CreateSubscription)
+			subscriptions.GET("", subscriptionHandler.ListSubscriptions)
+			subscriptions.GET("/:id", subscriptionHandler.GetSubscription)
+			subscriptions.DELETE("/:id", subscriptionHandler.DeleteSubscription)
+		}
+
+		// Rotas para o ranker de candidatos do tenant da requisição
+		ranker := v1.Group("/ranker")
+		{
+			ranker.GET("", rankerHandler.GetRanker)
+			ranker.POST("/train", rankerHandler.TrainRanker)
+			ranker.PUT("/status", rankerHandler.SetRankerStatus)
+
+			// Rotas para o dataset de revisões manuais de matches
+			ranker.POST("/reviews", rankerHandler.RecordReview)
+			ranker.GET("/reviews", rankerHandler.ListReviews)
+			ranker.GET("/reviews/export", rankerHandler.ExportReviews)
+		}
+
+		// Rotas de administração das API keys
+		admin := v1.Group("/admin")
+		{
+			admin.POST("/api-keys", apiKeyHandler.CreateAPIKey)
+			admin.GET("/api-keys", apiKeyHandler.ListAPIKeys)
+			admin.GET("/api-keys/:id", apiKeyHandler.GetAPIKey)
+			admin.POST("/api-keys/:id/rotate", apiKeyHandler.RotateAPIKey)
+			admin.DELETE("/api-keys/:id", apiKeyHandler.RevokeAPIKey)
+
+			// Rotas de operação do outbox de entregas de eventos
+			admin.GET("/outbox", outboxHandler.ListDeliveries)
+			admin.GET("/outbox/metrics", outboxHandler.GetMetrics)
+			admin.GET("/outbox/:id", outboxHandler.GetDelivery)
+			admin.POST("/outbox/requeue", outboxHandler.RequeueDeliveries)
+			admin.POST("/outbox/:id/discard", outboxHandler.DiscardDelivery)
+
+			// Rotas do cadastro de contas bancárias; contas inativas ficam fora da conciliação
+			admin.GET("/bank-accounts", bankAccountHandler.ListAccounts)
+			admin.POST("/bank-accounts/:account/deactivate", bankAccountHandler.DeactivateAccount)
+			admin.POST("/bank-accounts/:account/activate", bankAccountHandler.ActivateAccount)
+
+			// Rotas dos conjuntos de regras de matching do tenant, gerais ou por conta
+			admin.GET("/rule-sets", ruleSetHandler.ListRuleSets)
+			admin.PUT("/rule-sets", ruleSetHandler.SaveRuleSet)
+			admin.DELETE("/rule-sets", ruleSetHandler.DeleteRuleSet)
+
+			// Rotas dos templates de notificação do tenant; cada alteração grava uma nova versão
+			admin.GET("/notification-templates", notificationTemplateHandler.ListTemplates)
+			admin.PUT("/notification-templates/:event_type", notificationTemplateHandler.SaveTemplate)
+			admin.GET("/notification-templates/:event_type/versions", notificationTemplateHandler.ListVersions)
+			admin.POST("/notification-templates/:event_type/versions/:version/restore", notificationTemplateHandler.RestoreVersion)
+
+			// Rota do selftest do motor de matching contra o dataset dourado
+			admin.GET("/selftest", selfTestHandler.RunSelfTest)
+
+			// Rotas da verificação de divergências entre os boletos em aberto do ERP e a base local
+			admin.POST("/erp-drift/check", erpDriftHandler.CheckDrift)
+			admin.GET("/erp-drift/reports", erpDriftHandler.ListReports)
+			admin.GET("/erp-drift/reports/latest", erpDriftHandler.GetLatestReport)
+
+			// Rotas da importação delta de boletos do ERP: sincronização sob demanda e estado do cursor
+			admin.POST("/erp-sync/run", erpSyncHandler.Sync)
+			admin.GET("/erp-sync", erpSyncHandler.GetState)
+
+			// Rota dos relatórios da rotina de manutenção (expurgos e anonimização)
+			admin.GET("/maintenance/reports", maintenanceHandler.ListReports)
+		}
+
+		// Rota para consultar o consumo de quota do tenant da requisição
+		v1.GET("/quotas/usage", func(c *gin.Context) {
+			c.JSON(http.StatusOK, quotas.Usage(middleware.TenantID(c)))
+		})
+
+		// Rotas para estatísticas
+		statistics := v1.Group("/statistics")
+		{
+			// Rota para obter os percentis do tempo até a conciliação por conta e período
+			statistics.GET("/time-to-reconcile", reconciliationHandler.GetTimeToReconcileStatistics)
+
+			// Rota para obter a taxa de conciliação por dia e versão do motor de matching
+			statistics.GET("/engine-versions", reconciliationHandler.GetEngineVersionStatistics)
+
+			// Rota para exportar a conciliação diária por conta no layout do relatório regulatório (CSV)
+			statistics.GET("/regulatory-report", reportHandler.ExportRegulatoryReport)
+
+			// Rota para obter a série diária de pendências gravada pelo job noturno
+			statistics.GET("/pending-series", pendingReviewHandler.GetPendingSeries)
+		}
+
+		// Rotas para relatórios gerenciais
+		reports := v1.Group("/reports")
+		{
+			// Rota para o ranking das contas por pendências, em JSON ou CSV (format=csv)
+			reports.GET("/accounts-ranking", reportHandler.GetAccountsRanking)
+
+			// Rota para o papel de trabalho das auditorias externas (CSV assinado pelo usuário da requisição)
+			reports.GET("/audit-working-paper", workingPaperHandler.ExportWorkingPaper)
+		}
+
+		// Rota WebSocket com os contadores em tempo real para os painéis do time financeiro
+		v1.GET("/ws/stats", statsHandler.StreamStats)
+	}
+
+	// Rotas de profiling (pprof), habilitadas apenas quando PPROF_TOKEN estiver configurado
+	if token := os.Getenv("PPROF_TOKEN"); token != "" {
+		profiling := r.Group("/debug/pprof", middleware.RequireToken(token))
+		{
+			profiling.GET("/", gin.WrapF(pprof.Index))
+			profiling.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+			profiling.GET("/profile", gin.WrapF(pprof.Profile))
+			profiling.GET("/symbol", gin.WrapF(pprof.Symbol))
+			profiling.POST("/symbol", gin.WrapF(pprof.Symbol))
+			profiling.GET("/trace", gin.WrapF(pprof.Trace))
+			profiling.GET("/:profile", func(c *gin.Context) {
+				pprof.Handler(c.Param("profile")).ServeHTTP(c.Writer, c.Request)
+			})
 		}
 	}
 
@@ -0,0 +1,379 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/service"
+	"conciliacao-bancaria/internal/infrastructure/http/dto/response"
+	"conciliacao-bancaria/internal/infrastructure/http/handler"
+	"conciliacao-bancaria/internal/infrastructure/http/middleware"
+	"conciliacao-bancaria/internal/infrastructure/report"
+	"conciliacao-bancaria/internal/infrastructure/selftest"
+)
+
+// testRouter reúne o router da API e os repositórios em memória por trás dele
+type testRouter struct {
+	engine   *gin.Engine
+	billets  *memoryBilletRepository
+	payments *memoryPaymentRepository
+}
+
+// newTestRouter monta o router da API sobre repositórios em memória, com os handlers de boletos,
+// pagamentos, conjuntos de regras, templates de notificação, papel de trabalho, selftest e do ERP (sem
+// API do ERP configurada); os demais handlers não são usados pelos testes
+func newTestRouter(t *testing.T) *testRouter {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	billets := newMemoryBilletRepository()
+	payments := newMemoryPaymentRepository()
+	timeline := &memoryTimelineRepository{}
+
+	signer, err := report.NewSigner([]byte("papel-de-trabalho-testes-router1"))
+	if err != nil {
+		t.Fatalf("NewSigner: %v", err)
+	}
+
+	engine := SetupRouter(
+		handler.NewBilletHandler(usecase.NewBilletUseCase(billets, timeline)),
+		handler.NewPaymentHandler(usecase.NewPaymentUseCase(payments, timeline)),
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		handler.NewRuleSetHandler(usecase.NewRuleSetUseCase(newMemoryRuleSetRepository())),
+		handler.NewNotificationTemplateHandler(usecase.NewNotificationTemplateUseCase(&memoryNotificationTemplateRepository{})),
+		handler.NewWorkingPaperHandler(usecase.NewWorkingPaperUseCase(billets, payments, nil), report.DefaultWorkingPaperTemplate(), signer),
+		handler.NewSelfTestHandler(),
+		handler.NewERPDriftHandler(usecase.NewERPDriftUseCase(nil, billets, nil, nil)),
+		handler.NewERPSyncHandler(usecase.NewERPSyncUseCase(nil, billets, timeline, nil)),
+		nil,
+		nil,
+	)
+
+	return &testRouter{engine: engine, billets: billets, payments: payments}
+}
+
+// serve executa uma requisição no router, com os headers informados, e retorna a resposta gravada
+func (r *testRouter) serve(method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	recorder := httptest.NewRecorder()
+	r.engine.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// expectStatus interrompe o teste quando o status HTTP da resposta não é o esperado
+func expectStatus(t *testing.T, operation string, recorder *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if recorder.Code != want {
+		t.Fatalf("%s: esperado status %d, obtido %d: %s", operation, want, recorder.Code, recorder.Body.String())
+	}
+}
+
+// decode lê o corpo JSON da resposta, interrompendo o teste quando ele é inválido
+func decode(t *testing.T, operation string, recorder *httptest.ResponseRecorder, target interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(recorder.Body.Bytes(), target); err != nil {
+		t.Fatalf("%s: resposta inválida: %v: %s", operation, err, recorder.Body.String())
+	}
+}
+
+func TestPaymentLifecycle(t *testing.T) {
+	router := newTestRouter(t)
+
+	payment := `{"transaction_id":"p1","bank_account":"conta-1","amount":10,"payment_date":"2024-01-02T00:00:00Z","entry_type":"credito"}`
+	expectStatus(t, "POST /payments", router.serve(http.MethodPost, "/api/v1/payments", payment), http.StatusCreated)
+	expectStatus(t, "POST /payments repetido", router.serve(http.MethodPost, "/api/v1/payments", payment), http.StatusConflict)
+
+	// O ID vem da URL; o corpo sem transaction_id é aceito
+	update := `{"bank_account":"conta-1","amount":12.5,"payment_date":"2024-01-02T00:00:00Z","entry_type":"credito"}`
+	expectStatus(t, "PUT /payments/p1", router.serve(http.MethodPut, "/api/v1/payments/p1", update), http.StatusOK)
+
+	recorder := router.serve(http.MethodGet, "/api/v1/payments/p1", "")
+	expectStatus(t, "GET /payments/p1", recorder, http.StatusOK)
+
+	var stored struct {
+		TransactionID string  `json:"transaction_id"`
+		Amount        float64 `json:"amount"`
+	}
+	decode(t, "GET /payments/p1", recorder, &stored)
+	if stored.TransactionID != "p1" || stored.Amount != 12.5 {
+		t.Fatalf("GET /payments/p1: pagamento lido difere do atualizado: %+v", stored)
+	}
+
+	expectStatus(t, "DELETE /payments/p1", router.serve(http.MethodDelete, "/api/v1/payments/p1", ""), http.StatusNoContent)
+
+	// Os erros chegam em JSON, no mesmo formato dos middlewares
+	recorder = router.serve(http.MethodGet, "/api/v1/payments/p1", "")
+	expectStatus(t, "GET /payments/p1 após exclusão", recorder, http.StatusNotFound)
+
+	var failure struct {
+		Error string `json:"error"`
+	}
+	decode(t, "GET /payments/p1 após exclusão", recorder, &failure)
+	if failure.Error == "" {
+		t.Fatalf("GET /payments/p1 após exclusão: resposta sem a mensagem de erro: %s", recorder.Body.String())
+	}
+}
+
+func TestPaymentBatch(t *testing.T) {
+	router := newTestRouter(t)
+
+	// O segundo item não tem conta bancária: no modo best_effort apenas ele é recusado
+	batch := `[
+		{"transaction_id":"p1","bank_account":"conta-1","amount":10,"payment_date":"2024-01-02T00:00:00Z"},
+		{"transaction_id":"p2","amount":20,"payment_date":"2024-01-03T00:00:00Z"}
+	]`
+	recorder := router.serve(http.MethodPost, "/api/v1/payments/batch", batch)
+	expectStatus(t, "POST /payments/batch", recorder, http.StatusMultiStatus)
+
+	var result usecase.ImportResult
+	decode(t, "POST /payments/batch", recorder, &result)
+	if result.Imported != 1 || result.Failed != 1 {
+		t.Fatalf("POST /payments/batch: esperados 1 importado e 1 recusado, obtidos %+v", result)
+	}
+	if len(router.payments.payments) != 1 {
+		t.Fatalf("POST /payments/batch: esperado 1 pagamento gravado, obtidos %d", len(router.payments.payments))
+	}
+
+	// No modo all_or_nothing, o item inválido aborta o lote inteiro
+	recorder = router.serve(http.MethodPost, "/api/v1/payments/batch?mode=all_or_nothing", batch)
+	expectStatus(t, "POST /payments/batch all_or_nothing", recorder, http.StatusUnprocessableEntity)
+}
+
+func TestBilletUpdateRejectsDifferentID(t *testing.T) {
+	router := newTestRouter(t)
+
+	billet := `{"billet_id":"b1","bank_account":"conta-1","amount":10,"issuance_date":"2024-01-01T00:00:00Z"}`
+	expectStatus(t, "POST /billets", router.serve(http.MethodPost, "/api/v1/billets", billet), http.StatusCreated)
+
+	update := `{"billet_id":"b2","bank_account":"conta-1","amount":15,"issuance_date":"2024-01-01T00:00:00Z"}`
+	expectStatus(t, "PUT /billets/b1", router.serve(http.MethodPut, "/api/v1/billets/b1", update), http.StatusBadRequest)
+
+	if stored := router.billets.billets["b1"]; stored.Amount != 10 {
+		t.Fatalf("PUT /billets/b1: boleto alterado apesar da recusa: %+v", stored)
+	}
+}
+
+func TestPaymentValidationErrors(t *testing.T) {
+	router := newTestRouter(t)
+
+	// Todos os campos inválidos são relatados na mesma resposta
+	recorder := router.serve(http.MethodPost, "/api/v1/payments", `{"amount":10,"entry_type":"estorno"}`)
+	expectStatus(t, "POST /payments inválido", recorder, http.StatusUnprocessableEntity)
+
+	var resp response.ValidationErrorResponse
+	decode(t, "POST /payments inválido", recorder, &resp)
+
+	fields := make(map[string]bool, len(resp.Errors))
+	for _, fieldError := range resp.Errors {
+		fields[fieldError.Field] = true
+	}
+	if len(resp.Errors) != 4 || !fields["transaction_id"] || !fields["bank_account"] || !fields["payment_date"] || !fields["entry_type"] {
+		t.Fatalf("POST /payments inválido: esperados erros de transaction_id, bank_account, payment_date e entry_type, obtidos %+v", resp.Errors)
+	}
+
+	expectStatus(t, "POST /payments malformado", router.serve(http.MethodPost, "/api/v1/payments", `{"amount":`), http.StatusBadRequest)
+}
+
+func TestPaymentDateFormats(t *testing.T) {
+	router := newTestRouter(t)
+
+	// A data simples, o epoch em segundos e o RFC3339 com fuso chegam ao mesmo instante em UTC
+	bodies := map[string]string{
+		"p1": `{"transaction_id":"p1","bank_account":"conta-1","amount":10,"payment_date":"2024-03-05"}`,
+		"p2": `{"transaction_id":"p2","bank_account":"conta-1","amount":10,"payment_date":1709596800}`,
+		"p3": `{"transaction_id":"p3","bank_account":"conta-1","amount":10,"payment_date":"2024-03-04T21:00:00-03:00"}`,
+	}
+	want := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	for id, body := range bodies {
+		expectStatus(t, "POST /payments "+id, router.serve(http.MethodPost, "/api/v1/payments", body), http.StatusCreated)
+
+		if stored := router.payments.payments[id]; !stored.PaymentDate.Equal(want) {
+			t.Fatalf("POST /payments %s: esperada data %s, obtida %s", id, want, stored.PaymentDate)
+		}
+	}
+
+	// Dia e mês não podem ser distinguidos em 05/03/2024
+	ambiguous := `{"transaction_id":"p4","bank_account":"conta-1","amount":10,"payment_date":"05/03/2024"}`
+	recorder := router.serve(http.MethodPost, "/api/v1/payments", ambiguous)
+	expectStatus(t, "POST /payments com data ambígua", recorder, http.StatusBadRequest)
+	if !strings.Contains(recorder.Body.String(), "ambígua") {
+		t.Fatalf("POST /payments com data ambígua: mensagem sem o motivo: %s", recorder.Body.String())
+	}
+}
+
+func TestRuleSets(t *testing.T) {
+	router := newTestRouter(t)
+
+	// Um conjunto sem estratégias nem parâmetros é recusado
+	recorder := router.serve(http.MethodPut, "/api/v1/admin/rule-sets", `{"bank_account":"conta-1"}`)
+	expectStatus(t, "PUT /admin/rule-sets sem estratégias", recorder, http.StatusBadRequest)
+
+	// A janela de datas só vale para as estratégias que comparam datas
+	recorder = router.serve(http.MethodPut, "/api/v1/admin/rule-sets",
+		`{"strategies":["reference_id"],"strategy_params":{"reference_id":{"max_days_diff":5}}}`)
+	expectStatus(t, "PUT /admin/rule-sets com janela de datas em reference_id", recorder, http.StatusBadRequest)
+
+	recorder = router.serve(http.MethodPut, "/api/v1/admin/rule-sets", `{"strategies":["reference_id"]}`)
+	expectStatus(t, "PUT /admin/rule-sets geral", recorder, http.StatusOK)
+	recorder = router.serve(http.MethodPut, "/api/v1/admin/rule-sets",
+		`{"bank_account":"conta-1","strategies":["conta_valor_data"],"strategy_params":{"conta_valor_data":{"tolerance":1,"max_days_diff":30}}}`)
+	expectStatus(t, "PUT /admin/rule-sets da conta", recorder, http.StatusOK)
+
+	// Os conjuntos são do tenant da requisição
+	recorder = router.serve(http.MethodGet, "/api/v1/admin/rule-sets", "", middleware.TenantHeader, "outro-tenant")
+	expectStatus(t, "GET /admin/rule-sets de outro tenant", recorder, http.StatusOK)
+	var others model.RuleSets
+	decode(t, "GET /admin/rule-sets de outro tenant", recorder, &others)
+	if len(others) != 0 {
+		t.Fatalf("GET /admin/rule-sets de outro tenant: esperada lista vazia, obtido %+v", others)
+	}
+
+	recorder = router.serve(http.MethodGet, "/api/v1/admin/rule-sets", "")
+	expectStatus(t, "GET /admin/rule-sets", recorder, http.StatusOK)
+	var ruleSets model.RuleSets
+	decode(t, "GET /admin/rule-sets", recorder, &ruleSets)
+	if len(ruleSets) != 2 || ruleSets.For("conta-1").Strategies[0].Strategy != model.StrategyAccountAmountDate {
+		t.Fatalf("GET /admin/rule-sets: esperados o conjunto geral e o da conta-1, obtido %+v", ruleSets)
+	}
+
+	recorder = router.serve(http.MethodDelete, "/api/v1/admin/rule-sets?bank_account=conta-1", "")
+	expectStatus(t, "DELETE /admin/rule-sets", recorder, http.StatusNoContent)
+	recorder = router.serve(http.MethodDelete, "/api/v1/admin/rule-sets?bank_account=conta-1", "")
+	expectStatus(t, "DELETE /admin/rule-sets repetido", recorder, http.StatusNotFound)
+}
+
+func TestNotificationTemplates(t *testing.T) {
+	router := newTestRouter(t)
+	path := "/api/v1/admin/notification-templates/" + string(model.EventReconciliationSummary)
+
+	// Templates com variáveis inexistentes e tipos de evento desconhecidos são recusados
+	recorder := router.serve(http.MethodPut, path, `{"subject":"Resumo","body":"{{.Summary.Inexistente}}"}`)
+	expectStatus(t, "PUT /admin/notification-templates com variável inexistente", recorder, http.StatusBadRequest)
+	recorder = router.serve(http.MethodPut, "/api/v1/admin/notification-templates/inexistente", `{"subject":"Resumo","body":"corpo"}`)
+	expectStatus(t, "PUT /admin/notification-templates com tipo desconhecido", recorder, http.StatusBadRequest)
+
+	first := `{"subject":"Conciliação {{.Tenant}}","body":"{{.Summary.Totals.Reconciled}} conciliados ({{.Summary.MatchRate}}%) {{.Links.Run}}"}`
+	expectStatus(t, "PUT /admin/notification-templates v1", router.serve(http.MethodPut, path, first), http.StatusCreated)
+	second := `{"subject":"Resumo","body":"{{.Event.Description}}"}`
+	expectStatus(t, "PUT /admin/notification-templates v2", router.serve(http.MethodPut, path, second), http.StatusCreated)
+
+	// A restauração da versão 1 grava a versão 3 com o mesmo conteúdo
+	recorder = router.serve(http.MethodPost, path+"/versions/1/restore", "")
+	expectStatus(t, "POST /admin/notification-templates/:event_type/versions/1/restore", recorder, http.StatusCreated)
+	recorder = router.serve(http.MethodPost, path+"/versions/9/restore", "")
+	expectStatus(t, "POST /admin/notification-templates/:event_type/versions/9/restore", recorder, http.StatusNotFound)
+	recorder = router.serve(http.MethodPost, path+"/versions/um/restore", "")
+	expectStatus(t, "POST /admin/notification-templates/:event_type/versions/um/restore", recorder, http.StatusBadRequest)
+
+	recorder = router.serve(http.MethodGet, path+"/versions", "")
+	expectStatus(t, "GET /admin/notification-templates/:event_type/versions", recorder, http.StatusOK)
+	var versions []*model.NotificationTemplate
+	decode(t, "GET /admin/notification-templates/:event_type/versions", recorder, &versions)
+	if len(versions) != 3 || versions[0].Version != 3 || versions[0].Subject != "Conciliação {{.Tenant}}" {
+		t.Fatalf("GET /admin/notification-templates/:event_type/versions: esperadas 3 versões, a última restaurada da 1, obtido %+v", versions)
+	}
+
+	recorder = router.serve(http.MethodGet, "/api/v1/admin/notification-templates", "")
+	expectStatus(t, "GET /admin/notification-templates", recorder, http.StatusOK)
+	var current []*model.NotificationTemplate
+	decode(t, "GET /admin/notification-templates", recorder, &current)
+	if len(current) != 1 || current[0].Version != 3 {
+		t.Fatalf("GET /admin/notification-templates: esperada apenas a versão 3, obtido %+v", current)
+	}
+
+	// A versão aplicada substitui o assunto e a mensagem padrão do resumo da execução
+	run := model.NewReconciliationRun("", "default")
+	run.Complete(&model.ReconciliationResult{
+		ReconciledBillets:    []model.ReconciledBillet{{BilletID: "b1", ConciliationStatus: model.StatusSuccessful}},
+		NonReconciledBillets: []model.Billet{{ID: "b2"}},
+	})
+	event := model.NewEvent(model.EventReconciliationSummary, "", 0)
+	event.Summary = run.Summary()
+
+	notification := model.NewNotification("default", event)
+	if err := current[0].Render(notification, model.NotificationLinks{Run: "https://api/runs/" + run.ID}); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if notification.Subject != "Conciliação default" || notification.Message != "1 conciliados (50%) https://api/runs/"+run.ID {
+		t.Fatalf("Render: assunto ou mensagem inesperados: %q, %q", notification.Subject, notification.Message)
+	}
+}
+
+func TestAuditWorkingPaperValidation(t *testing.T) {
+	router := newTestRouter(t)
+
+	path := "/api/v1/reports/audit-working-paper?date_field=payment&start_date=2024-01-01&end_date=2024-01-31"
+	expectStatus(t, "GET /reports/audit-working-paper sem responsável", router.serve(http.MethodGet, path, ""), http.StatusBadRequest)
+	expectStatus(t, "GET /reports/audit-working-paper sem período",
+		router.serve(http.MethodGet, "/api/v1/reports/audit-working-paper", "", "X-User-ID", "auditor"), http.StatusBadRequest)
+}
+
+func TestSelfTest(t *testing.T) {
+	recorder := newTestRouter(t).serve(http.MethodGet, "/api/v1/admin/selftest", "")
+	expectStatus(t, "GET /admin/selftest", recorder, http.StatusOK)
+
+	var report selftest.Report
+	decode(t, "GET /admin/selftest", recorder, &report)
+	if !report.Passed || len(report.Scenarios) == 0 || report.EngineVersion != service.EngineVersion {
+		t.Fatalf("GET /admin/selftest: esperado o dataset dourado aprovado, obtido %+v", report)
+	}
+}
+
+func TestERPWithoutAPI(t *testing.T) {
+	router := newTestRouter(t)
+
+	// Sem a API do ERP configurada, a verificação e a sincronização sob demanda ficam indisponíveis
+	expectStatus(t, "POST /admin/erp-drift/check sem API do ERP",
+		router.serve(http.MethodPost, "/api/v1/admin/erp-drift/check", ""), http.StatusServiceUnavailable)
+	expectStatus(t, "POST /admin/erp-sync/run sem API do ERP",
+		router.serve(http.MethodPost, "/api/v1/admin/erp-sync/run", ""), http.StatusServiceUnavailable)
+}
+
+func TestCORS(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://dashboard.example.com")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+
+	router := newTestRouter(t)
+
+	preflight := func(origin string) *httptest.ResponseRecorder {
+		return router.serve(http.MethodOptions, "/api/v1/payments", "",
+			"Origin", origin, "Access-Control-Request-Method", http.MethodPost)
+	}
+
+	recorder := preflight("https://dashboard.example.com")
+	expectStatus(t, "OPTIONS /payments da origem permitida", recorder, http.StatusNoContent)
+	if recorder.Header().Get("Access-Control-Allow-Origin") != "https://dashboard.example.com" ||
+		recorder.Header().Get("Access-Control-Allow-Credentials") != "true" ||
+		!strings.Contains(recorder.Header().Get("Access-Control-Allow-Methods"), http.MethodPost) {
+		t.Fatalf("OPTIONS /payments: headers de CORS inesperados: %v", recorder.Header())
+	}
+
+	expectStatus(t, "OPTIONS /payments de origem não permitida", preflight("https://outro.example.com"), http.StatusForbidden)
+
+	// Na requisição em si, a origem permitida recebe os headers expostos
+	recorder = router.serve(http.MethodGet, "/api/v1/payments", "", "Origin", "https://dashboard.example.com")
+	expectStatus(t, "GET /payments com origem", recorder, http.StatusOK)
+	if recorder.Header().Get("Access-Control-Allow-Origin") != "https://dashboard.example.com" ||
+		!strings.Contains(recorder.Header().Get("Access-Control-Expose-Headers"), "X-Reconciliation-Run-ID") {
+		t.Fatalf("GET /payments: headers de CORS inesperados: %v", recorder.Header())
+	}
+}
+
+func TestUnknownEnvironment(t *testing.T) {
+	recorder := newTestRouter(t).serve(http.MethodGet, "/api/v1/payments", "", middleware.EnvironmentHeader, "homologacao")
+	expectStatus(t, "GET /payments em ambiente desconhecido", recorder, http.StatusBadRequest)
+}
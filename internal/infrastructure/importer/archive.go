@@ -0,0 +1,99 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Archive guarda uma cópia byte a byte de cada entrada importada, antes de qualquer conversão,
+// para auditoria
+type Archive struct {
+	dir string
+}
+
+// NewArchive cria uma nova instância de Archive no diretório informado
+func NewArchive(dir string) *Archive {
+	return &Archive{
+		dir: dir,
+	}
+}
+
+// ArchiveFromEnv cria o arquivamento a partir da variável IMPORT_ARCHIVE_DIR, ou nil quando não configurado
+func ArchiveFromEnv() *Archive {
+	dir := os.Getenv("IMPORT_ARCHIVE_DIR")
+	if dir == "" {
+		return nil
+	}
+	return NewArchive(dir)
+}
+
+// Tee retorna um leitor que copia para o arquivo de auditoria tudo o que for lido da entrada.
+// A função retornada fecha a cópia e informa o caminho gravado
+func (a *Archive) Tee(name string, reader io.Reader) (io.Reader, func() (string, error), error) {
+	file, path, err := a.create(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	finish := func() (string, error) {
+		if err := file.Close(); err != nil {
+			return "", fmt.Errorf("erro ao gravar cópia original da importação: %w", err)
+		}
+		return path, nil
+	}
+
+	return io.TeeReader(reader, file), finish, nil
+}
+
+// Save grava o conteúdo original completo de uma entrada, retornando o caminho gravado
+func (a *Archive) Save(name string, data []byte) (string, error) {
+	file, path, err := a.create(name)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("erro ao gravar cópia original da importação: %w", err)
+	}
+
+	return path, nil
+}
+
+// create cria o arquivo da cópia original, sem sobrescrever cópias anteriores
+func (a *Archive) create(name string) (*os.File, string, error) {
+	if err := os.MkdirAll(a.dir, 0o750); err != nil {
+		return nil, "", fmt.Errorf("erro ao criar diretório de arquivamento: %w", err)
+	}
+
+	path := filepath.Join(a.dir, archiveFileName(name))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao criar cópia original da importação: %w", err)
+	}
+
+	return file, path, nil
+}
+
+// archiveFileName gera um nome único e seguro para a cópia, preservando o nome de origem
+func archiveFileName(name string) string {
+	base := filepath.Base(name)
+	base = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r < ' ' {
+			return '_'
+		}
+		return r
+	}, base)
+	if base == "." || base == "" {
+		base = "entrada"
+	}
+
+	return fmt.Sprintf("%s-%s", time.Now().UTC().Format("20060102T150405.000000000Z"), base)
+}
@@ -0,0 +1,302 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// cnab240LineLength define o tamanho fixo dos registros CNAB 240
+const cnab240LineLength = 240
+
+// Tipos de registro do layout CNAB 240 (posição 8)
+const (
+	cnabRecordFileHeader  = '0'
+	cnabRecordBatchHeader = '1'
+	cnabRecordDetail      = '3'
+	cnabRecordBatchFooter = '5'
+	cnabRecordFileFooter  = '9'
+)
+
+// Direções do arquivo CNAB (posição 143 do header de arquivo)
+const (
+	CNABDirectionRemessa = "remessa"
+	CNABDirectionRetorno = "retorno"
+)
+
+// cnabSettlementMovements lista os códigos de movimento de retorno que representam liquidação do título
+var cnabSettlementMovements = map[string]bool{
+	"06": true, // Liquidação
+	"17": true, // Liquidação após baixa ou título não registrado
+}
+
+// CNAB240Header representa o header de arquivo CNAB 240
+type CNAB240Header struct {
+	BankCode    string
+	Agreement   string // Código do convênio no banco
+	Agency      string
+	Account     string // Conta no formato conta-dígito usado nos pagamentos
+	Direction   string
+	GeneratedAt time.Time
+	Sequence    int // Número sequencial do arquivo (NSA)
+}
+
+// CNAB240Settlement representa a liquidação de um título (segmentos T e U do retorno de cobrança)
+type CNAB240Settlement struct {
+	Batch          int
+	Sequence       int
+	OurNumber      string // Nosso número
+	DocumentNumber string // Número do documento (seu número)
+	PayerDocument  string // CPF ou CNPJ do pagador, apenas dígitos
+	PayerName      string // Nome do pagador
+	NominalAmount  float64
+	PaidAmount     float64
+	PaymentDate    time.Time
+	Line           int
+}
+
+// CNAB240File representa um arquivo CNAB 240 de retorno de cobrança
+type CNAB240File struct {
+	Header      CNAB240Header
+	Settlements []CNAB240Settlement
+	Records     int // Quantidade de registros lidos, incluindo headers e trailers
+
+	// Divergências entre os totais dos trailers de lote/arquivo e os registros lidos
+	Inconsistencies []string
+}
+
+// ParseCNAB240 lê um arquivo CNAB 240 de retorno de cobrança (padrão FEBRABAN), respeitando os limites
+// de importação. Apenas os títulos liquidados (movimentos 06 e 17) são retornados. Divergências com os
+// totais dos trailers não interrompem a leitura e são retornadas em Inconsistencies
+func ParseCNAB240(reader io.Reader, limits Limits) (*CNAB240File, error) {
+	if limits.MaxLineLength > cnab240LineLength+2 {
+		limits.MaxLineLength = cnab240LineLength + 2
+	}
+
+	file := &CNAB240File{}
+	totals := &cnab240Totals{}
+	var headerFound bool
+	var pending *CNAB240Settlement
+
+	err := ScanLines(reader, limits, func(lineNumber int, raw []byte) error {
+		line := []rune(strings.TrimRight(string(raw), "\r"))
+		if len(line) != cnab240LineLength {
+			return fmt.Errorf("linha %d: registro com %d posições, esperado %d", lineNumber, len(line), cnab240LineLength)
+		}
+		file.Records++
+
+		if err := totals.add(line); err != nil {
+			return fmt.Errorf("linha %d: %w", lineNumber, err)
+		}
+
+		switch line[7] {
+		case cnabRecordFileHeader:
+			if headerFound {
+				return fmt.Errorf("linha %d: header de arquivo duplicado", lineNumber)
+			}
+			header, err := parseCNAB240Header(line)
+			if err != nil {
+				return fmt.Errorf("linha %d: %w", lineNumber, err)
+			}
+			file.Header = header
+			headerFound = true
+		case cnabRecordDetail:
+			if !headerFound {
+				return fmt.Errorf("linha %d: detalhe antes do header de arquivo", lineNumber)
+			}
+			settlement, err := parseCNAB240Segment(line, pending, lineNumber)
+			if err != nil {
+				return fmt.Errorf("linha %d: %w", lineNumber, err)
+			}
+			pending = settlement
+			if pending != nil && !pending.PaymentDate.IsZero() {
+				file.Settlements = append(file.Settlements, *pending)
+				pending = nil
+			}
+		case cnabRecordBatchHeader, cnabRecordBatchFooter, cnabRecordFileFooter:
+			// Headers e trailers de lote/arquivo não geram lançamentos
+		default:
+			return fmt.Errorf("linha %d: tipo de registro desconhecido %q", lineNumber, line[7])
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !headerFound {
+		return nil, fmt.Errorf("arquivo sem header CNAB 240")
+	}
+
+	file.Inconsistencies = totals.finish()
+
+	return file, nil
+}
+
+// ImportFile converte o header no registro do arquivo importado, usado no controle da numeração sequencial
+func (f *CNAB240File) ImportFile(fileName string) *model.ImportFile {
+	file := model.NewImportFile(f.Header.BankCode, f.Header.Agreement, f.Header.Direction, f.Header.Sequence, f.Header.GeneratedAt)
+	file.FileName = fileName
+	file.BankAccount = f.Header.Account
+	file.Records = f.Records
+	file.Payments = len(f.Settlements)
+	file.Inconsistencies = f.Inconsistencies
+	return file
+}
+
+// Payments converte as liquidações do arquivo em pagamentos da conta do header. O ID é derivado do
+// convênio, do número sequencial do arquivo e da posição do registro, de forma que reimportar o mesmo
+// arquivo não duplique pagamentos
+func (f *CNAB240File) Payments() []*model.Payment {
+	payments := make([]*model.Payment, 0, len(f.Settlements))
+	for _, settlement := range f.Settlements {
+		id := fmt.Sprintf("%s-%d-%d-%d", f.Header.Agreement, f.Header.Sequence, settlement.Batch, settlement.Sequence)
+
+		reference := settlement.DocumentNumber
+		if reference == "" {
+			reference = settlement.OurNumber
+		}
+
+		var referenceID *string
+		if reference != "" {
+			referenceID = &reference
+		}
+
+		payment := model.NewPayment(id, f.Header.Account, settlement.PaidAmount, settlement.PaymentDate, referenceID)
+		payment.PayerDocument = settlement.PayerDocument
+		payment.Description = settlement.PayerName
+		payment.PaymentMethod = model.PaymentMethodBillet
+		payments = append(payments, payment)
+	}
+	return payments
+}
+
+// parseCNAB240Header lê o header de arquivo
+func parseCNAB240Header(line []rune) (CNAB240Header, error) {
+	header := CNAB240Header{
+		BankCode:  field(line, 1, 3),
+		Agreement: field(line, 33, 52),
+		Agency:    strings.TrimLeft(field(line, 53, 57), "0"),
+		Account:   strings.TrimLeft(field(line, 59, 70), "0") + "-" + field(line, 71, 71),
+	}
+
+	switch field(line, 143, 143) {
+	case "1":
+		header.Direction = CNABDirectionRemessa
+	case "2":
+		header.Direction = CNABDirectionRetorno
+	default:
+		return header, fmt.Errorf("código remessa/retorno inválido: %q", field(line, 143, 143))
+	}
+
+	generatedAt, err := time.Parse("02012006", field(line, 144, 151))
+	if err != nil {
+		return header, fmt.Errorf("data de geração inválida: %w", err)
+	}
+	header.GeneratedAt = generatedAt
+
+	sequence, err := strconv.Atoi(field(line, 158, 163))
+	if err != nil {
+		return header, fmt.Errorf("número sequencial do arquivo inválido: %w", err)
+	}
+	header.Sequence = sequence
+
+	return header, nil
+}
+
+// parseCNAB240Segment lê os segmentos T (dados do título) e U (valores e datas do pagamento).
+// O segmento U completa a liquidação iniciada pelo segmento T imediatamente anterior
+func parseCNAB240Segment(line []rune, pending *CNAB240Settlement, lineNumber int) (*CNAB240Settlement, error) {
+	switch field(line, 14, 14) {
+	case "T":
+		if !cnabSettlementMovements[field(line, 16, 17)] {
+			return nil, nil
+		}
+
+		nominalAmount, err := cnabAmount(field(line, 82, 96))
+		if err != nil {
+			return nil, fmt.Errorf("valor nominal inválido: %w", err)
+		}
+
+		batch, _ := strconv.Atoi(field(line, 4, 7))
+		sequence, _ := strconv.Atoi(field(line, 9, 13))
+
+		return &CNAB240Settlement{
+			Batch:          batch,
+			Sequence:       sequence,
+			OurNumber:      field(line, 38, 57),
+			DocumentNumber: field(line, 59, 73),
+			PayerDocument:  cnabPayerDocument(field(line, 133, 133), field(line, 134, 148)),
+			PayerName:      field(line, 149, 188),
+			NominalAmount:  nominalAmount,
+			Line:           lineNumber,
+		}, nil
+	case "U":
+		if pending == nil {
+			return nil, nil
+		}
+
+		paidAmount, err := cnabAmount(field(line, 78, 92))
+		if err != nil {
+			return nil, fmt.Errorf("valor pago inválido: %w", err)
+		}
+
+		paymentDate, err := time.Parse("02012006", field(line, 138, 145))
+		if err != nil {
+			return nil, fmt.Errorf("data de ocorrência inválida: %w", err)
+		}
+
+		pending.PaidAmount = paidAmount
+		pending.PaymentDate = paymentDate
+		return pending, nil
+	default:
+		// Outros segmentos (ex.: Y, W) não afetam a liquidação
+		return pending, nil
+	}
+}
+
+// field retorna o conteúdo das posições [start, end] (base 1, inclusivas) sem espaços nas bordas
+func field(line []rune, start, end int) string {
+	return strings.TrimSpace(string(line[start-1 : end]))
+}
+
+// cnabPayerDocument extrai o CPF (tipo 1) ou o CNPJ (tipo 2) do número de inscrição do pagador, que o
+// arquivo completa com zeros à esquerda; outros tipos de inscrição não identificam o pagador
+func cnabPayerDocument(kind, number string) string {
+	switch kind {
+	case "1":
+		if len(number) >= model.CPFLength {
+			return number[len(number)-model.CPFLength:]
+		}
+	case "2":
+		if len(number) >= model.CNPJLength {
+			return number[len(number)-model.CNPJLength:]
+		}
+	}
+	return ""
+}
+
+// cnabAmount converte um valor numérico com duas casas decimais implícitas
+func cnabAmount(value string) (float64, error) {
+	cents, err := cnabCents(value)
+	if err != nil {
+		return 0, err
+	}
+	return float64(cents) / 100, nil
+}
+
+// cnabCents converte um valor numérico com duas casas decimais implícitas em centavos. Os campos
+// numéricos do CNAB só têm dígitos: sinais e espaços internos são recusados
+func cnabCents(value string) (int64, error) {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("valor não numérico %q", value)
+		}
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
@@ -0,0 +1,56 @@
+package importer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// cnab240Record monta um registro CNAB 240 em branco com os campos informados, indexados pela posição
+// inicial (base 1)
+func cnab240Record(fields map[int]string) string {
+	line := []rune(strings.Repeat(" ", cnab240LineLength))
+	for start, value := range fields {
+		copy(line[start-1:], []rune(value))
+	}
+	return string(line)
+}
+
+// cnab240Sample monta um retorno de cobrança com um título liquidado e os trailers conferindo
+func cnab240Sample() string {
+	records := []string{
+		cnab240Record(map[int]string{1: "0010000", 8: "0", 33: "CONVENIO123", 53: "01234", 59: "000000012345", 71: "6", 143: "2", 144: "05032024", 158: "000001"}),
+		cnab240Record(map[int]string{1: "0010001", 8: "1"}),
+		cnab240Record(map[int]string{1: "0010001", 8: "3", 9: "00001", 14: "T", 16: "06", 38: "NN0001", 59: "DOC0001", 82: "000000000010000", 133: "1", 134: "000012345678901", 149: "FULANO DE TAL"}),
+		cnab240Record(map[int]string{1: "0010001", 8: "3", 9: "00002", 14: "U", 16: "06", 78: "000000000010000", 138: "06032024"}),
+		cnab240Record(map[int]string{1: "0010001", 8: "5", 18: "000004", 24: "000001", 30: "00000000000010000"}),
+		cnab240Record(map[int]string{1: "0019999", 8: "9", 18: "000001", 24: "000006"}),
+	}
+	return strings.Join(records, "\r\n") + "\r\n"
+}
+
+// FuzzParseCNAB240 garante que nenhum arquivo, por mais malformado, derrube o parser ou produza
+// pagamentos com valores negativos
+func FuzzParseCNAB240(f *testing.F) {
+	sample := cnab240Sample()
+	f.Add([]byte(sample))
+	f.Add([]byte(strings.ReplaceAll(sample, "\r\n", "\n")))
+	f.Add([]byte(sample[:cnab240LineLength]))
+	f.Add([]byte(strings.Repeat("9", cnab240LineLength)))
+	f.Add([]byte{})
+
+	limits := Limits{MaxBytes: 1 << 20, MaxLineLength: 1024, MaxLines: 1000}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		file, err := ParseCNAB240(bytes.NewReader(data), limits)
+		if err != nil {
+			return
+		}
+
+		for _, payment := range file.Payments() {
+			if payment.Amount < 0 {
+				t.Fatalf("pagamento %s com valor negativo: %v", payment.ID, payment.Amount)
+			}
+		}
+	})
+}
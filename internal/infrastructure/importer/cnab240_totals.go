@@ -0,0 +1,171 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// TotalsPolicyFromEnv lê da variável IMPORT_TOTALS_POLICY (rejeitar ou sinalizar) o tratamento dos
+// arquivos com totais do trailer divergentes. O padrão é rejeitar o arquivo
+func TotalsPolicyFromEnv() model.TotalsPolicy {
+	if model.TotalsPolicy(strings.ToLower(strings.TrimSpace(os.Getenv("IMPORT_TOTALS_POLICY")))) == model.TotalsPolicyFlag {
+		return model.TotalsPolicyFlag
+	}
+	return model.TotalsPolicyReject
+}
+
+// cnab240BatchTotals acumula os totais de um lote para conferência com o trailer de lote
+type cnab240BatchTotals struct {
+	number  string
+	records int
+	titles  int
+	cents   int64 // Soma do valor nominal dos títulos (segmento T)
+}
+
+// cnab240Totals confere a contagem de registros e a soma de valores lidas com as informadas nos
+// trailers de lote (registro 5) e de arquivo (registro 9), acumulando as divergências encontradas
+type cnab240Totals struct {
+	records         int
+	batches         int
+	batch           *cnab240BatchTotals
+	trailerFound    bool
+	inconsistencies []string
+}
+
+// add contabiliza um registro e, nos trailers, confere os totais acumulados
+func (t *cnab240Totals) add(line []rune) error {
+	t.records++
+	if t.batch != nil {
+		t.batch.records++
+	}
+
+	switch line[7] {
+	case cnabRecordBatchHeader:
+		if t.batch != nil {
+			t.inconsistency("lote %s sem trailer de lote", t.batch.number)
+		}
+		t.batches++
+		t.batch = &cnab240BatchTotals{number: field(line, 4, 7), records: 1}
+	case cnabRecordDetail:
+		if t.batch == nil || field(line, 14, 14) != "T" {
+			return nil
+		}
+		cents, err := cnabCents(field(line, 82, 96))
+		if err != nil {
+			return fmt.Errorf("valor nominal inválido: %w", err)
+		}
+		t.batch.titles++
+		t.batch.cents += cents
+	case cnabRecordBatchFooter:
+		if t.batch == nil {
+			t.inconsistency("trailer do lote %s sem header de lote", field(line, 4, 7))
+			return nil
+		}
+		if err := t.checkBatch(line); err != nil {
+			return err
+		}
+		t.batch = nil
+	case cnabRecordFileFooter:
+		if t.batch != nil {
+			t.inconsistency("lote %s sem trailer de lote", t.batch.number)
+			t.batch = nil
+		}
+		t.trailerFound = true
+		return t.checkFile(line)
+	}
+
+	return nil
+}
+
+// checkBatch confere o trailer de lote: quantidade de registros do lote e, quando preenchidos, a
+// quantidade e o valor total dos títulos das carteiras simples, vinculada, caucionada e descontada
+func (t *cnab240Totals) checkBatch(line []rune) error {
+	records, err := strconv.Atoi(field(line, 18, 23))
+	if err != nil {
+		return fmt.Errorf("quantidade de registros do lote inválida: %w", err)
+	}
+	if records != t.batch.records {
+		t.inconsistency("lote %s: trailer informa %d registros, lidos %d", t.batch.number, records, t.batch.records)
+	}
+
+	var titles int
+	var cents int64
+	for _, start := range []int{24, 47, 70, 93} {
+		quantity, err := strconv.Atoi(field(line, start, start+5))
+		if err != nil {
+			return fmt.Errorf("quantidade de títulos do lote inválida: %w", err)
+		}
+		amount, err := cnabCents(field(line, start+6, start+22))
+		if err != nil {
+			return fmt.Errorf("valor total dos títulos do lote inválido: %w", err)
+		}
+		titles += quantity
+		cents += amount
+	}
+
+	// Bancos que não preenchem a totalização de cobrança enviam os campos zerados
+	if titles == 0 && cents == 0 {
+		return nil
+	}
+
+	if titles != t.batch.titles {
+		t.inconsistency("lote %s: trailer informa %d títulos, lidos %d", t.batch.number, titles, t.batch.titles)
+	}
+	if cents != t.batch.cents {
+		t.inconsistency("lote %s: trailer informa valor total %s, soma lida %s",
+			t.batch.number, formatCents(cents), formatCents(t.batch.cents))
+	}
+
+	return nil
+}
+
+// checkFile confere o trailer de arquivo: quantidade de lotes e de registros, incluindo o próprio trailer
+func (t *cnab240Totals) checkFile(line []rune) error {
+	batches, err := strconv.Atoi(field(line, 18, 23))
+	if err != nil {
+		return fmt.Errorf("quantidade de lotes do arquivo inválida: %w", err)
+	}
+	if batches != t.batches {
+		t.inconsistency("trailer de arquivo informa %d lotes, lidos %d", batches, t.batches)
+	}
+
+	records, err := strconv.Atoi(field(line, 24, 29))
+	if err != nil {
+		return fmt.Errorf("quantidade de registros do arquivo inválida: %w", err)
+	}
+	if records != t.records {
+		t.inconsistency("trailer de arquivo informa %d registros, lidos %d", records, t.records)
+	}
+
+	return nil
+}
+
+// finish registra as estruturas não encerradas ao fim do arquivo e retorna as divergências
+func (t *cnab240Totals) finish() []string {
+	if t.batch != nil {
+		t.inconsistency("lote %s sem trailer de lote", t.batch.number)
+	}
+	if !t.trailerFound {
+		t.inconsistency("arquivo sem trailer de arquivo")
+	}
+	return t.inconsistencies
+}
+
+// inconsistency registra uma divergência entre os totais informados e os registros lidos
+func (t *cnab240Totals) inconsistency(format string, args ...interface{}) {
+	t.inconsistencies = append(t.inconsistencies, fmt.Sprintf(format, args...))
+}
+
+// formatCents formata um valor em centavos com duas casas decimais
+func formatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
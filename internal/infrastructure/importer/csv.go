@@ -0,0 +1,264 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// Colunas do extrato em CSV. As obrigatórias identificam o lançamento; as demais são opcionais e podem
+// vir em qualquer ordem
+const (
+	csvColumnID            = "transaction_id"
+	csvColumnBankAccount   = "bank_account"
+	csvColumnAmount        = "amount"
+	csvColumnPaymentDate   = "payment_date"
+	csvColumnReferenceID   = "reference_id"
+	csvColumnBankCode      = "bank_code"
+	csvColumnPayerDocument = "payer_document"
+	csvColumnDescription   = "description"
+	csvColumnPaymentMethod = "payment_method"
+)
+
+// csvRequiredColumns lista as colunas sem as quais um lançamento não pode ser importado
+var csvRequiredColumns = []string{csvColumnID, csvColumnBankAccount, csvColumnAmount, csvColumnPaymentDate}
+
+// csvDateLayouts lista os formatos de data aceitos na coluna payment_date
+var csvDateLayouts = []string{"2006-01-02", "02/01/2006", time.RFC3339}
+
+// CSVFile representa um extrato bancário em CSV
+type CSVFile struct {
+	Records int // Quantidade de lançamentos lidos, sem o cabeçalho
+
+	payments []*model.Payment
+}
+
+// ParseCSV lê um extrato bancário em CSV, respeitando os limites de importação. A primeira linha traz
+// os nomes das colunas; o separador é o ponto e vírgula quando o cabeçalho o usa, senão a vírgula.
+// Valores aceitam o ponto ou, com separador de milhar, a vírgula decimal (1.234,56); valores negativos
+// são lançamentos a débito
+func ParseCSV(reader io.Reader, limits Limits) (*CSVFile, error) {
+	buffered := bufio.NewReader(&countingReader{reader: reader, limit: limits.MaxBytes})
+
+	header, err := readCSVHeader(buffered, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	separator := ','
+	if strings.Contains(header, ";") {
+		separator = ';'
+	}
+
+	csvReader := csv.NewReader(io.MultiReader(strings.NewReader(header+"\n"), buffered))
+	csvReader.Comma = separator
+	csvReader.ReuseRecord = true
+
+	names, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("cabeçalho do CSV: %w", err)
+	}
+
+	columns := make(map[string]int, len(names))
+	for i, name := range names {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range csvRequiredColumns {
+		if _, found := columns[required]; !found {
+			return nil, fmt.Errorf("cabeçalho do CSV sem a coluna %s", required)
+		}
+	}
+
+	file := &CSVFile{}
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("CSV: %w", err)
+		}
+
+		line, _ := csvReader.FieldPos(0)
+		if limits.MaxLines > 0 && line > limits.MaxLines {
+			return nil, fmt.Errorf("%w (%d)", ErrTooManyLines, limits.MaxLines)
+		}
+		if err := checkCSVRecord(record, limits); err != nil {
+			return nil, fmt.Errorf("linha %d: %w", line, err)
+		}
+
+		payment, err := parseCSVPayment(record, columns)
+		if err != nil {
+			return nil, fmt.Errorf("linha %d: %w", line, err)
+		}
+
+		file.Records++
+		file.payments = append(file.payments, payment)
+	}
+
+	return file, nil
+}
+
+// ImportFile converte o extrato no registro do arquivo importado. Extratos não têm convênio nem número
+// sequencial; a conta é a dos lançamentos quando todos são da mesma conta
+func (f *CSVFile) ImportFile(fileName string) *model.ImportFile {
+	file := model.NewImportFile("", "", CNABDirectionRetorno, 0, time.Now())
+	file.FileName = fileName
+	file.BankAccount = commonBankAccount(f.payments)
+	file.Records = f.Records
+	file.Payments = len(f.payments)
+	return file
+}
+
+// Payments retorna os lançamentos do extrato
+func (f *CSVFile) Payments() []*model.Payment {
+	return f.payments
+}
+
+// readCSVHeader lê a primeira linha do CSV respeitando o tamanho máximo de linha, para detectar o
+// separador; o restante da entrada fica no leitor
+func readCSVHeader(reader *bufio.Reader, limits Limits) (string, error) {
+	var header []byte
+	for {
+		b, err := reader.ReadByte()
+		if errors.Is(err, io.EOF) || b == '\n' {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		header = append(header, b)
+		if limits.MaxLineLength > 0 && len(header) > limits.MaxLineLength {
+			return "", fmt.Errorf("linha 1: %w (%d bytes)", ErrLineTooLong, limits.MaxLineLength)
+		}
+	}
+
+	line := strings.TrimPrefix(strings.TrimRight(string(header), "\r"), "\ufeff")
+	if strings.TrimSpace(line) == "" {
+		return "", fmt.Errorf("CSV sem cabeçalho")
+	}
+	if !utf8.ValidString(line) {
+		return "", fmt.Errorf("linha 1: %w", ErrInvalidEncoding)
+	}
+
+	return line, nil
+}
+
+// checkCSVRecord aplica ao registro o tamanho máximo de linha e rejeita bytes inválidos para UTF-8
+func checkCSVRecord(record []string, limits Limits) error {
+	size := 0
+	for _, value := range record {
+		size += len(value) + 1
+		if !utf8.ValidString(value) {
+			return ErrInvalidEncoding
+		}
+	}
+	if limits.MaxLineLength > 0 && size > limits.MaxLineLength {
+		return fmt.Errorf("%w (%d bytes)", ErrLineTooLong, limits.MaxLineLength)
+	}
+	return nil
+}
+
+// parseCSVPayment converte um registro do CSV em lançamento
+func parseCSVPayment(record []string, columns map[string]int) (*model.Payment, error) {
+	value := func(column string) string {
+		if i, found := columns[column]; found && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	id := value(csvColumnID)
+	if id == "" {
+		return nil, fmt.Errorf("coluna %s vazia", csvColumnID)
+	}
+
+	bankAccount := value(csvColumnBankAccount)
+	if bankAccount == "" {
+		return nil, fmt.Errorf("coluna %s vazia", csvColumnBankAccount)
+	}
+
+	amount, err := parseStatementAmount(value(csvColumnAmount))
+	if err != nil {
+		return nil, fmt.Errorf("coluna %s: %w", csvColumnAmount, err)
+	}
+	if amount == 0 {
+		return nil, fmt.Errorf("coluna %s: valor zerado", csvColumnAmount)
+	}
+
+	paymentDate, err := parseCSVDate(value(csvColumnPaymentDate))
+	if err != nil {
+		return nil, fmt.Errorf("coluna %s: %w", csvColumnPaymentDate, err)
+	}
+
+	var referenceID *string
+	if reference := value(csvColumnReferenceID); reference != "" {
+		referenceID = &reference
+	}
+
+	payment := model.NewPayment(id, bankAccount, math.Abs(amount), paymentDate, referenceID)
+	if amount < 0 {
+		payment.EntryType = model.EntryTypeDebit
+	}
+	payment.BankCode = value(csvColumnBankCode)
+	payment.PayerDocument = value(csvColumnPayerDocument)
+	payment.Description = value(csvColumnDescription)
+	payment.PaymentMethod = model.PaymentMethod(strings.ToLower(value(csvColumnPaymentMethod)))
+	if !payment.PaymentMethod.IsValid() {
+		return nil, fmt.Errorf("coluna %s: meio de pagamento desconhecido %q", csvColumnPaymentMethod, payment.PaymentMethod)
+	}
+
+	return payment, nil
+}
+
+// parseStatementAmount lê um valor monetário de extrato com ponto decimal (1234.56) ou, no formato
+// brasileiro, com vírgula decimal e ponto de milhar (1.234,56)
+func parseStatementAmount(value string) (float64, error) {
+	if value == "" {
+		return 0, fmt.Errorf("valor vazio")
+	}
+	if strings.Contains(value, ",") {
+		value = strings.ReplaceAll(value, ".", "")
+		value = strings.Replace(value, ",", ".", 1)
+	}
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, fmt.Errorf("valor inválido %q", value)
+	}
+	return amount, nil
+}
+
+// parseCSVDate lê a data do lançamento em um dos formatos aceitos
+func parseCSVDate(value string) (time.Time, error) {
+	for _, layout := range csvDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("data inválida %q", value)
+}
+
+// commonBankAccount retorna a conta dos lançamentos quando todos são da mesma conta, ou vazio
+func commonBankAccount(payments []*model.Payment) string {
+	if len(payments) == 0 {
+		return ""
+	}
+	account := payments[0].BankAccount
+	for _, payment := range payments[1:] {
+		if payment.BankAccount != account {
+			return ""
+		}
+	}
+	return account
+}
@@ -0,0 +1,44 @@
+package importer
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// FuzzParseCSV garante que nenhum CSV, por mais malformado, derrube o parser ou produza lançamentos
+// sem identificação ou com valores inválidos
+func FuzzParseCSV(f *testing.F) {
+	f.Add([]byte("transaction_id,bank_account,amount,payment_date,reference_id\nT1,12345-6,100.50,2024-03-05,REF1\nT2,12345-6,-20,2024-03-06,\n"))
+	f.Add([]byte("transaction_id;bank_account;amount;payment_date;description\r\nT1;12345-6;1.234,56;05/03/2024;\"PIX; FULANO\"\r\n"))
+	f.Add([]byte("\ufefftransaction_id,bank_account,amount,payment_date\n\"T1\nT2\",1,1,2024-01-01\n"))
+	f.Add([]byte("transaction_id,bank_account,amount,payment_date\nT1,1,NaN,2024-01-01\n"))
+	f.Add([]byte("amount\n1\n"))
+	f.Add([]byte{})
+
+	limits := Limits{MaxBytes: 1 << 20, MaxLineLength: 1024, MaxLines: 1000}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		file, err := ParseCSV(bytes.NewReader(data), limits)
+		if err != nil {
+			return
+		}
+
+		if file.Records != len(file.Payments()) {
+			t.Fatalf("%d registros lidos e %d lançamentos", file.Records, len(file.Payments()))
+		}
+		for _, payment := range file.Payments() {
+			if payment.ID == "" || payment.BankAccount == "" {
+				t.Fatalf("lançamento sem identificação: %+v", payment)
+			}
+			if payment.Amount < 0 || math.IsNaN(payment.Amount) || math.IsInf(payment.Amount, 0) {
+				t.Fatalf("lançamento %s com valor inválido: %v", payment.ID, payment.Amount)
+			}
+			if payment.EntryType != model.EntryTypeCredit && payment.EntryType != model.EntryTypeDebit {
+				t.Fatalf("lançamento %s com tipo inválido: %q", payment.ID, payment.EntryType)
+			}
+		}
+	})
+}
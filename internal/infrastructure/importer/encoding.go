@@ -0,0 +1,138 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Encodings suportados na importação
+const (
+	EncodingAuto   = "auto"
+	EncodingUTF8   = "utf-8"
+	EncodingLatin1 = "iso-8859-1"
+)
+
+// detectionSampleSize define quantos bytes iniciais são analisados na detecção automática
+const detectionSampleSize = 64 * 1024
+
+// EncodingFromEnv lê o encoding dos arquivos de importação da variável IMPORT_ENCODING
+// (auto, utf-8 ou iso-8859-1). O padrão é a detecção automática
+func EncodingFromEnv() string {
+	encoding, err := ParseEncoding(os.Getenv("IMPORT_ENCODING"))
+	if err != nil {
+		return EncodingAuto
+	}
+	return encoding
+}
+
+// ParseEncoding normaliza o nome de um encoding, aceitando os apelidos mais comuns
+func ParseEncoding(name string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", EncodingAuto:
+		return EncodingAuto, nil
+	case EncodingUTF8, "utf8":
+		return EncodingUTF8, nil
+	case EncodingLatin1, "iso8859-1", "iso_8859-1", "latin1", "latin-1":
+		return EncodingLatin1, nil
+	default:
+		return "", fmt.Errorf("encoding não suportado: %s", name)
+	}
+}
+
+// DetectEncoding identifica o encoding de uma amostra: UTF-8 quando válida, senão ISO-8859-1,
+// em que qualquer sequência de bytes é válida. Uma sequência UTF-8 truncada no fim da amostra é ignorada
+func DetectEncoding(sample []byte) string {
+	for i := 0; i < len(sample); {
+		r, size := utf8.DecodeRune(sample[i:])
+		if r == utf8.RuneError && size <= 1 {
+			if !utf8.FullRune(sample[i:]) {
+				break
+			}
+			return EncodingLatin1
+		}
+		i += size
+	}
+	return EncodingUTF8
+}
+
+// NewUTF8Reader converte a entrada para UTF-8 a partir do encoding informado (ou detectado,
+// com EncodingAuto), retornando também o encoding de origem efetivamente usado
+func NewUTF8Reader(reader io.Reader, encoding string) (io.Reader, string, error) {
+	encoding, err := ParseEncoding(encoding)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if encoding == EncodingAuto {
+		buffered := bufio.NewReaderSize(reader, detectionSampleSize)
+		sample, err := buffered.Peek(detectionSampleSize)
+		if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+			return nil, "", err
+		}
+		encoding = DetectEncoding(sample)
+		reader = buffered
+	}
+
+	if encoding == EncodingLatin1 {
+		return &latin1Reader{reader: reader}, encoding, nil
+	}
+
+	return reader, encoding, nil
+}
+
+// ToUTF8 converte um conteúdo completo para UTF-8, retornando o encoding de origem usado
+func ToUTF8(data []byte, encoding string) ([]byte, string, error) {
+	encoding, err := ParseEncoding(encoding)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if encoding == EncodingAuto {
+		encoding = DetectEncoding(data)
+	}
+
+	if encoding == EncodingLatin1 {
+		return latin1ToUTF8(data), encoding, nil
+	}
+
+	if !utf8.Valid(data) {
+		return nil, "", ErrInvalidEncoding
+	}
+
+	return data, encoding, nil
+}
+
+// latin1ToUTF8 converte bytes ISO-8859-1, em que cada byte corresponde ao code point de mesmo valor
+func latin1ToUTF8(data []byte) []byte {
+	converted := make([]byte, 0, len(data)+len(data)/4)
+	for _, b := range data {
+		converted = utf8.AppendRune(converted, rune(b))
+	}
+	return converted
+}
+
+// latin1Reader converte em fluxo uma entrada ISO-8859-1 para UTF-8
+type latin1Reader struct {
+	reader  io.Reader
+	pending []byte
+	buffer  [4096]byte
+}
+
+// Read entrega os bytes já convertidos, lendo mais da entrada quando necessário
+func (r *latin1Reader) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 {
+		n, err := r.reader.Read(r.buffer[:])
+		if n == 0 {
+			return 0, err
+		}
+		r.pending = latin1ToUTF8(r.buffer[:n])
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
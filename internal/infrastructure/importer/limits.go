@@ -0,0 +1,135 @@
+package importer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Limites padrão aplicados à leitura de arquivos e payloads de importação
+const (
+	DefaultMaxBytes      = 50 * 1024 * 1024 // 50 MiB
+	DefaultMaxLineLength = 1024 * 1024      // 1 MiB
+	DefaultMaxLines      = 1000000
+	DefaultMaxXMLDepth   = 32
+)
+
+// Erros retornados quando a entrada excede os limites ou tem encoding inválido
+var (
+	ErrInputTooLarge   = errors.New("entrada excede o tamanho máximo permitido")
+	ErrLineTooLong     = errors.New("linha excede o tamanho máximo permitido")
+	ErrTooManyLines    = errors.New("entrada excede a quantidade máxima de linhas")
+	ErrInvalidEncoding = errors.New("entrada contém bytes inválidos para UTF-8")
+	ErrXMLTooDeep      = errors.New("XML excede a profundidade máxima de elementos")
+)
+
+// Limits define os limites de leitura de uma entrada de importação, protegendo o serviço de
+// arquivos malformados ou maliciosos que consumiriam memória sem limite
+type Limits struct {
+	MaxBytes      int64
+	MaxLineLength int
+	MaxLines      int
+	MaxXMLDepth   int
+}
+
+// DefaultLimits retorna os limites padrão de importação
+func DefaultLimits() Limits {
+	return Limits{
+		MaxBytes:      DefaultMaxBytes,
+		MaxLineLength: DefaultMaxLineLength,
+		MaxLines:      DefaultMaxLines,
+		MaxXMLDepth:   DefaultMaxXMLDepth,
+	}
+}
+
+// LimitsFromEnv lê os limites das variáveis IMPORT_MAX_BYTES, IMPORT_MAX_LINE_LENGTH, IMPORT_MAX_LINES
+// e IMPORT_MAX_XML_DEPTH, usando o padrão para as ausentes ou inválidas
+func LimitsFromEnv() Limits {
+	limits := DefaultLimits()
+
+	if value, err := strconv.ParseInt(os.Getenv("IMPORT_MAX_BYTES"), 10, 64); err == nil && value > 0 {
+		limits.MaxBytes = value
+	}
+	if value, err := strconv.Atoi(os.Getenv("IMPORT_MAX_LINE_LENGTH")); err == nil && value > 0 {
+		limits.MaxLineLength = value
+	}
+	if value, err := strconv.Atoi(os.Getenv("IMPORT_MAX_LINES")); err == nil && value > 0 {
+		limits.MaxLines = value
+	}
+	if value, err := strconv.Atoi(os.Getenv("IMPORT_MAX_XML_DEPTH")); err == nil && value > 0 {
+		limits.MaxXMLDepth = value
+	}
+
+	return limits
+}
+
+// ScanLines percorre as linhas não vazias da entrada aplicando os limites de tamanho total,
+// de tamanho de linha e de quantidade de linhas, e rejeitando bytes inválidos para UTF-8.
+// A leitura é interrompida no primeiro limite excedido, sem carregar o restante da entrada
+func ScanLines(reader io.Reader, limits Limits, fn func(lineNumber int, line []byte) error) error {
+	counter := &countingReader{reader: reader, limit: limits.MaxBytes}
+
+	scanner := bufio.NewScanner(counter)
+	// O tamanho máximo da linha é o maior entre a capacidade inicial do buffer e o limite
+	initialSize := 64 * 1024
+	if limits.MaxLineLength < initialSize {
+		initialSize = limits.MaxLineLength
+	}
+	scanner.Buffer(make([]byte, 0, initialSize), limits.MaxLineLength)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		if limits.MaxLines > 0 && lineNumber > limits.MaxLines {
+			return fmt.Errorf("%w (%d)", ErrTooManyLines, limits.MaxLines)
+		}
+
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		if !utf8.Valid(line) {
+			return fmt.Errorf("linha %d: %w", lineNumber, ErrInvalidEncoding)
+		}
+
+		if err := fn(lineNumber, line); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return fmt.Errorf("linha %d: %w (%d bytes)", lineNumber+1, ErrLineTooLong, limits.MaxLineLength)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ReadAll lê a entrada inteira respeitando o tamanho máximo. O encoding é validado na conversão (ToUTF8)
+func ReadAll(reader io.Reader, limits Limits) ([]byte, error) {
+	return io.ReadAll(&countingReader{reader: reader, limit: limits.MaxBytes})
+}
+
+// countingReader interrompe a leitura com ErrInputTooLarge ao ultrapassar o limite de bytes
+type countingReader struct {
+	reader io.Reader
+	limit  int64
+	read   int64
+}
+
+// Read lê da entrada contabilizando os bytes lidos
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	if r.limit > 0 && r.read > r.limit {
+		return 0, fmt.Errorf("%w (%d bytes)", ErrInputTooLarge, r.limit)
+	}
+	return n, err
+}
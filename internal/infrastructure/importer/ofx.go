@@ -0,0 +1,325 @@
+package importer
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// Elementos do extrato OFX lidos pelo parser
+const (
+	ofxElementTransaction = "STMTTRN"
+	ofxElementAccount     = "BANKACCTFROM"
+	ofxElementLedger      = "LEDGERBAL"
+)
+
+// OFXTransaction representa um lançamento do extrato OFX (STMTTRN)
+type OFXTransaction struct {
+	Type        string // TRNTYPE (CREDIT, DEBIT, DEP, XFER...)
+	PostedAt    time.Time
+	Amount      float64 // Negativo nos débitos
+	FITID       string  // Identificador único do lançamento na conta
+	CheckNumber string
+	RefNumber   string
+	Name        string
+	Memo        string
+}
+
+// OFXFile representa um extrato bancário OFX de uma conta
+type OFXFile struct {
+	BankCode     string // BANKID
+	Branch       string // BRANCHID
+	Account      string // ACCTID
+	Currency     string
+	GeneratedAt  time.Time // DTSERVER
+	Start        time.Time // DTSTART da lista de lançamentos
+	End          time.Time // DTEND da lista de lançamentos
+	Transactions []OFXTransaction
+
+	// LedgerBalance é o saldo do extrato (LEDGERBAL), quando informado
+	LedgerBalance   *float64
+	LedgerBalanceAt time.Time
+
+	// Divergências entre o extrato e os lançamentos lidos
+	Inconsistencies []string
+}
+
+// ParseOFX lê um extrato OFX, tanto o 2.x (XML) quanto o 1.x (SGML, com os elementos simples sem
+// fechamento), respeitando os limites de importação: o tamanho total e a profundidade de elementos são
+// limitados e bytes inválidos para UTF-8 são rejeitados. Apenas extratos de uma única conta são aceitos.
+// Divergências na conferência do extrato não interrompem a leitura e são retornadas em Inconsistencies
+func ParseOFX(reader io.Reader, limits Limits) (*OFXFile, error) {
+	decoder := xml.NewDecoder(&countingReader{reader: reader, limit: limits.MaxBytes})
+	// O OFX 1.x não fecha os elementos simples: no modo não estrito, o fechamento do agregado fecha os
+	// elementos abertos dentro dele
+	decoder.Strict = false
+	decoder.AutoClose = nil
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		// A entrada já chega convertida para UTF-8 pelo middleware de importação
+		return input, nil
+	}
+
+	file := &OFXFile{}
+	var stack []string
+	var transaction *OFXTransaction
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("OFX: %w", err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			name := strings.ToUpper(element.Name.Local)
+			stack = append(stack, name)
+			if limits.MaxXMLDepth > 0 && len(stack) > limits.MaxXMLDepth {
+				return nil, fmt.Errorf("%w (%d)", ErrXMLTooDeep, limits.MaxXMLDepth)
+			}
+
+			if name == ofxElementTransaction {
+				if transaction != nil {
+					return nil, fmt.Errorf("OFX: lançamento dentro de outro lançamento")
+				}
+				transaction = &OFXTransaction{}
+			}
+
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("OFX: fechamento de %s sem abertura", element.Name.Local)
+			}
+			name := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if name == ofxElementTransaction && transaction != nil {
+				if transaction.FITID == "" {
+					return nil, fmt.Errorf("OFX: lançamento %d sem FITID", len(file.Transactions)+1)
+				}
+				if transaction.PostedAt.IsZero() {
+					return nil, fmt.Errorf("OFX: lançamento %s sem DTPOSTED", transaction.FITID)
+				}
+				file.Transactions = append(file.Transactions, *transaction)
+				transaction = nil
+			}
+
+		case xml.CharData:
+			value := strings.TrimSpace(string(element))
+			if value == "" || len(stack) == 0 {
+				continue
+			}
+			if !utf8.ValidString(value) {
+				return nil, fmt.Errorf("OFX: %w", ErrInvalidEncoding)
+			}
+			if err := file.set(stack, transaction, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if file.Account == "" {
+		return nil, fmt.Errorf("OFX sem a conta do extrato (BANKACCTFROM)")
+	}
+
+	file.Inconsistencies = checkOFXTotals(file)
+
+	return file, nil
+}
+
+// set grava o valor do elemento simples no topo da pilha no extrato ou no lançamento em leitura
+func (f *OFXFile) set(stack []string, transaction *OFXTransaction, value string) error {
+	name := stack[len(stack)-1]
+
+	if transaction != nil {
+		return transaction.set(name, value)
+	}
+
+	var err error
+	switch {
+	case name == "BANKID" && ofxWithin(stack, ofxElementAccount):
+		f.BankCode = value
+	case name == "BRANCHID" && ofxWithin(stack, ofxElementAccount):
+		f.Branch = value
+	case name == "ACCTID" && ofxWithin(stack, ofxElementAccount):
+		if f.Account != "" && f.Account != value {
+			return fmt.Errorf("OFX com extratos de mais de uma conta (%s e %s)", f.Account, value)
+		}
+		f.Account = value
+	case name == "CURDEF":
+		f.Currency = value
+	case name == "DTSERVER":
+		f.GeneratedAt, err = parseOFXDate(value)
+	case name == "DTSTART":
+		f.Start, err = parseOFXDate(value)
+	case name == "DTEND":
+		f.End, err = parseOFXDate(value)
+	case name == "BALAMT" && ofxWithin(stack, ofxElementLedger):
+		var balance float64
+		if balance, err = parseStatementAmount(value); err == nil {
+			f.LedgerBalance = &balance
+		}
+	case name == "DTASOF" && ofxWithin(stack, ofxElementLedger):
+		f.LedgerBalanceAt, err = parseOFXDate(value)
+	}
+	if err != nil {
+		return fmt.Errorf("OFX: %s: %w", name, err)
+	}
+	return nil
+}
+
+// set grava o valor de um elemento simples do lançamento
+func (t *OFXTransaction) set(name, value string) error {
+	var err error
+	switch name {
+	case "TRNTYPE":
+		t.Type = strings.ToUpper(value)
+	case "DTPOSTED":
+		t.PostedAt, err = parseOFXDate(value)
+	case "TRNAMT":
+		t.Amount, err = parseStatementAmount(value)
+	case "FITID":
+		t.FITID = value
+	case "CHECKNUM":
+		t.CheckNumber = value
+	case "REFNUM":
+		t.RefNumber = value
+	case "NAME":
+		t.Name = value
+	case "MEMO":
+		t.Memo = value
+	}
+	if err != nil {
+		return fmt.Errorf("OFX: lançamento %s: %s: %w", t.FITID, name, err)
+	}
+	return nil
+}
+
+// ImportFile converte o extrato no registro do arquivo importado. Extratos não têm convênio nem número
+// sequencial
+func (f *OFXFile) ImportFile(fileName string) *model.ImportFile {
+	generatedAt := f.GeneratedAt
+	if generatedAt.IsZero() {
+		generatedAt = f.End
+	}
+
+	file := model.NewImportFile(f.BankCode, "", CNABDirectionRetorno, 0, generatedAt)
+	file.FileName = fileName
+	file.BankAccount = f.Account
+	file.Records = len(f.Transactions)
+	file.Payments = len(f.Payments())
+	file.Inconsistencies = f.Inconsistencies
+	return file
+}
+
+// Payments converte os lançamentos do extrato em pagamentos da conta; os de valor negativo são débitos
+// e os zerados, apenas informativos, são ignorados. O ID é derivado do banco, da conta e do FITID, de
+// forma que reimportar o mesmo extrato não duplique pagamentos
+func (f *OFXFile) Payments() []*model.Payment {
+	payments := make([]*model.Payment, 0, len(f.Transactions))
+	for _, transaction := range f.Transactions {
+		if transaction.Amount == 0 {
+			continue
+		}
+
+		id := fmt.Sprintf("%s-%s-%s", f.BankCode, f.Account, transaction.FITID)
+
+		reference := transaction.RefNumber
+		if reference == "" {
+			reference = transaction.CheckNumber
+		}
+
+		var referenceID *string
+		if reference != "" {
+			referenceID = &reference
+		}
+
+		payment := model.NewPayment(id, f.Account, math.Abs(transaction.Amount), transaction.PostedAt, referenceID)
+		if transaction.Amount < 0 {
+			payment.EntryType = model.EntryTypeDebit
+		}
+		payment.BankCode = f.BankCode
+		payment.Description = strings.TrimSpace(transaction.Name + " " + transaction.Memo)
+		payment.PaymentMethod = ofxPaymentMethod(transaction)
+		payments = append(payments, payment)
+	}
+	return payments
+}
+
+// ofxPaymentMethod deduz o meio de pagamento do tipo do lançamento e do histórico; o OFX não tem um
+// tipo próprio para o PIX, que os bancos identificam no histórico
+func ofxPaymentMethod(transaction OFXTransaction) model.PaymentMethod {
+	if strings.Contains(strings.ToUpper(transaction.Name+" "+transaction.Memo), "PIX") {
+		return model.PaymentMethodPix
+	}
+
+	switch transaction.Type {
+	case "DEP", "CASH", "DIRECTDEP":
+		return model.PaymentMethodDeposit
+	case "XFER":
+		return model.PaymentMethodTransfer
+	}
+	return ""
+}
+
+// ofxWithin verifica se o elemento está dentro do agregado informado
+func ofxWithin(stack []string, aggregate string) bool {
+	for _, name := range stack[:len(stack)-1] {
+		if name == aggregate {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOFXDate lê uma data OFX no formato AAAAMMDD[HHMMSS[.XXX]][[deslocamento:fuso]]; sem
+// deslocamento, o horário é tratado como UTC
+func parseOFXDate(value string) (time.Time, error) {
+	location := time.UTC
+	if i := strings.IndexByte(value, '['); i >= 0 {
+		zone := strings.TrimSuffix(value[i+1:], "]")
+		value = value[:i]
+
+		offset := zone
+		if j := strings.IndexByte(zone, ':'); j >= 0 {
+			offset = zone[:j]
+		}
+		hours, err := strconv.ParseFloat(offset, 64)
+		if err != nil || math.Abs(hours) > 14 {
+			return time.Time{}, fmt.Errorf("fuso inválido %q", zone)
+		}
+		location = time.FixedZone(zone, int(hours*3600))
+	}
+
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		value = value[:i]
+	}
+
+	var layout string
+	switch len(value) {
+	case 8:
+		layout = "20060102"
+	case 12:
+		layout = "200601021504"
+	case 14:
+		layout = "20060102150405"
+	default:
+		return time.Time{}, fmt.Errorf("data inválida %q", value)
+	}
+
+	date, err := time.ParseInLocation(layout, value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("data inválida %q", value)
+	}
+	return date, nil
+}
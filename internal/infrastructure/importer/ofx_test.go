@@ -0,0 +1,151 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// ofxSGMLSample é um extrato OFX 1.x, com os elementos simples sem fechamento
+const ofxSGMLSample = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+CHARSET:1252
+
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0<SEVERITY>INFO</STATUS><DTSERVER>20240310120000[-3:BRT]<LANGUAGE>POR</SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1><STMTTRNRS><TRNUID>1<STATUS><CODE>0<SEVERITY>INFO</STATUS>
+<STMTRS><CURDEF>BRL<BANKACCTFROM><BANKID>001<BRANCHID>1234<ACCTID>12345-6<ACCTTYPE>CHECKING</BANKACCTFROM>
+<BANKTRANLIST><DTSTART>20240301<DTEND>20240310
+<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20240305<TRNAMT>150,00<FITID>F1<REFNUM>REF1<MEMO>PIX RECEBIDO FULANO</STMTTRN>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20240306<TRNAMT>-20.00<FITID>F2<MEMO>TARIFA</STMTTRN>
+</BANKTRANLIST><LEDGERBAL><BALAMT>130.00<DTASOF>20240310</LEDGERBAL></STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+// ofxXMLSample é um extrato OFX 2.x, em XML
+const ofxXMLSample = `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="211" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>BRL</CURDEF>
+<BANKACCTFROM><BANKID>341</BANKID><ACCTID>98765-4</ACCTID></BANKACCTFROM>
+<BANKTRANLIST><DTSTART>20240301</DTSTART><DTEND>20240310</DTEND>
+<STMTTRN><TRNTYPE>DEP</TRNTYPE><DTPOSTED>20240302100000</DTPOSTED><TRNAMT>99.90</TRNAMT><FITID>X1</FITID><CHECKNUM>DOC1</CHECKNUM></STMTTRN>
+</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>
+`
+
+// TestParseOFXRejectsDeepNesting garante que a profundidade de elementos é limitada antes de o
+// documento ser lido por inteiro
+func TestParseOFXRejectsDeepNesting(t *testing.T) {
+	limits := DefaultLimits()
+	document := strings.Repeat("<A>", limits.MaxXMLDepth+1) + strings.Repeat("</A>", limits.MaxXMLDepth+1)
+
+	_, err := ParseOFX(strings.NewReader(document), limits)
+	if !errors.Is(err, ErrXMLTooDeep) {
+		t.Fatalf("esperado ErrXMLTooDeep, obtido %v", err)
+	}
+}
+
+// TestParseOFX confere a leitura dos extratos OFX 1.x e 2.x
+func TestParseOFX(t *testing.T) {
+	file, err := ParseOFX(strings.NewReader(ofxSGMLSample), DefaultLimits())
+	if err != nil {
+		t.Fatalf("OFX 1.x: %v", err)
+	}
+	if file.BankCode != "001" || file.Account != "12345-6" || len(file.Transactions) != 2 {
+		t.Fatalf("OFX 1.x: extrato lido incorretamente: %+v", file)
+	}
+	payments := file.Payments()
+	if payments[0].Amount != 150 || payments[0].PaymentMethod != "pix" || !payments[1].IsDebit() {
+		t.Fatalf("OFX 1.x: lançamentos lidos incorretamente: %+v %+v", payments[0], payments[1])
+	}
+
+	file, err = ParseOFX(strings.NewReader(ofxXMLSample), DefaultLimits())
+	if err != nil {
+		t.Fatalf("OFX 2.x: %v", err)
+	}
+	payments = file.Payments()
+	if len(payments) != 1 || payments[0].ID != "341-98765-4-X1" || *payments[0].ReferenceID != "DOC1" {
+		t.Fatalf("OFX 2.x: lançamentos lidos incorretamente: %+v", payments)
+	}
+}
+
+// TestParseOFXInconsistencies confere as divergências apontadas na conferência do extrato, que não
+// interrompem a leitura
+func TestParseOFXInconsistencies(t *testing.T) {
+	for _, sample := range []string{ofxSGMLSample, ofxXMLSample} {
+		file, err := ParseOFX(strings.NewReader(sample), DefaultLimits())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(file.Inconsistencies) != 0 {
+			t.Fatalf("extrato consistente com divergências: %v", file.Inconsistencies)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		document string
+		expected string
+	}{
+		{
+			name:     "FITID repetido",
+			document: strings.Replace(ofxSGMLSample, "<FITID>F2", "<FITID>F1", 1),
+			expected: "FITID F1 repetido em 2 lançamentos",
+		},
+		{
+			name:     "fora do período",
+			document: strings.Replace(ofxSGMLSample, "<DTPOSTED>20240306", "<DTPOSTED>20240311", 1),
+			expected: "lançamento F2 de 11/03/2024 fora do período do extrato",
+		},
+		{
+			name:     "sem período",
+			document: strings.Replace(ofxXMLSample, "<DTEND>20240310</DTEND>", "", 1),
+			expected: "sem o período da lista de lançamentos",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := ParseOFX(strings.NewReader(tt.document), DefaultLimits())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(file.Inconsistencies) != 1 || !strings.Contains(file.Inconsistencies[0], tt.expected) {
+				t.Fatalf("esperada a divergência %q, obtidas %v", tt.expected, file.Inconsistencies)
+			}
+			if file.ImportFile("extrato.ofx").IsConsistent() {
+				t.Fatal("arquivo importado sem as divergências do extrato")
+			}
+		})
+	}
+}
+
+// FuzzParseOFX garante que nenhum extrato, por mais malformado, derrube o parser ou produza
+// lançamentos sem identificação ou com valores inválidos
+func FuzzParseOFX(f *testing.F) {
+	f.Add([]byte(ofxSGMLSample))
+	f.Add([]byte(ofxXMLSample))
+	f.Add([]byte(strings.Repeat("<STMTTRN>", 40)))
+	f.Add([]byte("<OFX><BANKACCTFROM><ACCTID>1</ACCTID></BANKACCTFROM><STMTTRN><FITID>1<TRNAMT>1e309</STMTTRN></OFX>"))
+	f.Add([]byte{})
+
+	limits := Limits{MaxBytes: 1 << 20, MaxXMLDepth: 32}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		file, err := ParseOFX(bytes.NewReader(data), limits)
+		if err != nil {
+			return
+		}
+
+		for _, payment := range file.Payments() {
+			if payment.BankAccount == "" {
+				t.Fatalf("lançamento %s sem conta", payment.ID)
+			}
+			if payment.Amount < 0 || math.IsNaN(payment.Amount) || math.IsInf(payment.Amount, 0) {
+				t.Fatalf("lançamento %s com valor inválido: %v", payment.ID, payment.Amount)
+			}
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ofxDateLayout formata as datas citadas nas divergências do extrato
+const ofxDateLayout = "02/01/2006"
+
+// checkOFXTotals confere a consistência do extrato com os lançamentos lidos. O OFX não tem trailer com
+// a quantidade de registros e a soma dos valores como o CNAB; no lugar, são conferidos o período da lista
+// de lançamentos, os lançamentos fora desse período e os FITIDs repetidos, que identificariam dois
+// lançamentos como o mesmo pagamento. Arquivos truncados já são rejeitados pelo decoder, que não aceita
+// elementos abertos no fim da entrada
+func checkOFXTotals(file *OFXFile) []string {
+	var inconsistencies []string
+	inconsistency := func(format string, args ...interface{}) {
+		inconsistencies = append(inconsistencies, fmt.Sprintf(format, args...))
+	}
+
+	hasPeriod := !file.Start.IsZero() && !file.End.IsZero()
+	if !hasPeriod {
+		inconsistency("extrato sem o período da lista de lançamentos (DTSTART e DTEND)")
+	}
+
+	occurrences := make(map[string]int, len(file.Transactions))
+	for _, transaction := range file.Transactions {
+		occurrences[transaction.FITID]++
+
+		if hasPeriod && (ofxDay(transaction.PostedAt).Before(ofxDay(file.Start)) || ofxDay(transaction.PostedAt).After(ofxDay(file.End))) {
+			inconsistency("lançamento %s de %s fora do período do extrato (%s a %s)", transaction.FITID,
+				transaction.PostedAt.Format(ofxDateLayout), file.Start.Format(ofxDateLayout), file.End.Format(ofxDateLayout))
+		}
+	}
+
+	var repeated []string
+	for fitID, count := range occurrences {
+		if count > 1 {
+			repeated = append(repeated, fitID)
+		}
+	}
+	sort.Strings(repeated)
+	for _, fitID := range repeated {
+		inconsistency("FITID %s repetido em %d lançamentos", fitID, occurrences[fitID])
+	}
+
+	return inconsistencies
+}
+
+// ofxDay descarta o horário e o fuso de uma data do extrato, comparando apenas o dia informado
+func ofxDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
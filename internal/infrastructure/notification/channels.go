@@ -0,0 +1,215 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/service"
+)
+
+// Tipos de canal suportados
+const (
+	ChannelEmail   = "email"
+	ChannelSlack   = "slack"
+	ChannelWebhook = "webhook"
+	ChannelSMS     = "sms"
+)
+
+// sendTimeout define o tempo máximo de cada envio de notificação
+const sendTimeout = 10 * time.Second
+
+// twilioAPIURL define a URL base da API de mensagens da Twilio
+const twilioAPIURL = "https://api.twilio.com/2010-04-01"
+
+// Garantir que os canais implementam a interface Notifier
+var (
+	_ service.Notifier = (*EmailNotifier)(nil)
+	_ service.Notifier = (*SlackNotifier)(nil)
+	_ service.Notifier = (*WebhookNotifier)(nil)
+	_ service.Notifier = (*TwilioNotifier)(nil)
+)
+
+// EmailNotifier envia notificações por e-mail via SMTP
+type EmailNotifier struct {
+	name     string
+	address  string
+	from     string
+	to       []string
+	username string
+	password string
+}
+
+// NewEmailNotifier cria uma nova instância de EmailNotifier para o servidor SMTP (host:porta) informado
+func NewEmailNotifier(name, address, from string, to []string, username, password string) *EmailNotifier {
+	return &EmailNotifier{name: name, address: address, from: from, to: to, username: username, password: password}
+}
+
+// Channel retorna o nome do canal
+func (n *EmailNotifier) Channel() string {
+	return n.name
+}
+
+// Notify envia a notificação aos destinatários configurados
+func (n *EmailNotifier) Notify(ctx context.Context, notification *model.Notification) error {
+	var auth smtp.Auth
+	if n.username != "" {
+		host := n.address
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+		auth = smtp.PlainAuth("", n.username, n.password, host)
+	}
+
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		n.from, strings.Join(n.to, ", "), notification.Subject, notification.Message)
+
+	if err := smtp.SendMail(n.address, auth, n.from, n.to, []byte(message)); err != nil {
+		return fmt.Errorf("erro ao enviar e-mail: %w", err)
+	}
+	return nil
+}
+
+// SlackNotifier envia notificações a um canal do Slack via incoming webhook
+type SlackNotifier struct {
+	name       string
+	webhookURL string
+	client     *http.Client
+}
+
+// NewSlackNotifier cria uma nova instância de SlackNotifier
+func NewSlackNotifier(name, webhookURL string) *SlackNotifier {
+	return &SlackNotifier{name: name, webhookURL: webhookURL, client: &http.Client{Timeout: sendTimeout}}
+}
+
+// Channel retorna o nome do canal
+func (n *SlackNotifier) Channel() string {
+	return n.name
+}
+
+// Notify publica a notificação no canal do Slack
+func (n *SlackNotifier) Notify(ctx context.Context, notification *model.Notification) error {
+	body, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("*%s*\n%s", notification.Subject, notification.Message),
+	})
+	if err != nil {
+		return err
+	}
+
+	return postJSON(ctx, n.client, n.webhookURL, "", body)
+}
+
+// WebhookNotifier envia a notificação em JSON a um endpoint HTTP
+type WebhookNotifier struct {
+	name   string
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewWebhookNotifier cria uma nova instância de WebhookNotifier. O token, quando informado, é enviado como Bearer
+func NewWebhookNotifier(name, url, token string) *WebhookNotifier {
+	return &WebhookNotifier{name: name, url: url, token: token, client: &http.Client{Timeout: sendTimeout}}
+}
+
+// Channel retorna o nome do canal
+func (n *WebhookNotifier) Channel() string {
+	return n.name
+}
+
+// Notify envia a notificação ao endpoint
+func (n *WebhookNotifier) Notify(ctx context.Context, notification *model.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	return postJSON(ctx, n.client, n.url, n.token, body)
+}
+
+// TwilioNotifier envia notificações por SMS pela API da Twilio
+type TwilioNotifier struct {
+	name       string
+	accountSID string
+	authToken  string
+	from       string
+	to         []string
+	baseURL    string
+	client     *http.Client
+}
+
+// NewTwilioNotifier cria uma nova instância de TwilioNotifier
+func NewTwilioNotifier(name, accountSID, authToken, from string, to []string) *TwilioNotifier {
+	return &TwilioNotifier{
+		name:       name,
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+		to:         to,
+		baseURL:    twilioAPIURL,
+		client:     &http.Client{Timeout: sendTimeout},
+	}
+}
+
+// Channel retorna o nome do canal
+func (n *TwilioNotifier) Channel() string {
+	return n.name
+}
+
+// Notify envia um SMS a cada destinatário configurado
+func (n *TwilioNotifier) Notify(ctx context.Context, notification *model.Notification) error {
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", n.baseURL, n.accountSID)
+	text := notification.Subject + ": " + notification.Message
+
+	for _, to := range n.to {
+		form := url.Values{"From": {n.from}, "To": {to}, "Body": {text}}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+		if err != nil {
+			return err
+		}
+		req.SetBasicAuth(n.accountSID, n.authToken)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		if err := do(n.client, req); err != nil {
+			return fmt.Errorf("erro ao enviar SMS para %s: %w", to, err)
+		}
+	}
+
+	return nil
+}
+
+// postJSON envia o corpo JSON ao endpoint, com o token Bearer quando informado
+func postJSON(ctx context.Context, client *http.Client, endpoint, token string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return do(client, req)
+}
+
+// do executa a requisição e trata respostas fora da faixa 2xx como erro
+func do(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("resposta inesperada do canal: %s", resp.Status)
+	}
+	return nil
+}
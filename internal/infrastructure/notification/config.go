@@ -0,0 +1,106 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/domain/service"
+)
+
+// ChannelConfig representa a configuração de um canal de notificação. Os campos usados dependem do tipo
+type ChannelConfig struct {
+	Type string `json:"type"` // email, slack, webhook ou sms
+
+	// E-mail (SMTP)
+	SMTPAddress  string   `json:"smtp_address,omitempty"`
+	SMTPUsername string   `json:"smtp_username,omitempty"`
+	SMTPPassword string   `json:"smtp_password,omitempty"`
+	From         string   `json:"from,omitempty"` // Remetente do e-mail ou número de origem do SMS
+	To           []string `json:"to,omitempty"`   // Destinatários do e-mail ou números do SMS
+
+	// Slack e webhook
+	URL   string `json:"url,omitempty"`
+	Token string `json:"token,omitempty"`
+
+	// SMS via Twilio
+	AccountSID string `json:"account_sid,omitempty"`
+	AuthToken  string `json:"auth_token,omitempty"`
+}
+
+// Config representa os canais de notificação, indexados pelo nome, e as rotas de cada tenant.
+// BaseURL é a URL pública da API, usada nos links dos templates de notificação
+type Config struct {
+	Channels map[string]ChannelConfig             `json:"channels"`
+	Routes   map[string][]model.NotificationRoute `json:"routes"`
+	BaseURL  string                               `json:"base_url,omitempty"`
+}
+
+// NewRouterFromConfig cria os canais configurados e o roteador de notificações, com os templates do
+// repositório informado
+func NewRouterFromConfig(config Config, templates repository.NotificationTemplateRepository) (*Router, error) {
+	notifiers := make([]service.Notifier, 0, len(config.Channels))
+	for name, channel := range config.Channels {
+		notifier, err := newNotifier(name, channel)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, notifier)
+	}
+
+	return NewRouter(notifiers, config.Routes, templates, config.BaseURL)
+}
+
+// NewRouterFromEnv cria o roteador de notificações a partir da configuração JSON em NOTIFICATIONS.
+// Retorna nil quando as notificações não estão configuradas ou a configuração é inválida
+func NewRouterFromEnv(templates repository.NotificationTemplateRepository) *Router {
+	raw := os.Getenv("NOTIFICATIONS")
+	if raw == "" {
+		return nil
+	}
+
+	var config Config
+	if err := json.Unmarshal([]byte(raw), &config); err != nil {
+		log.Printf("configuração de notificações inválida, notificações desabilitadas: %v", err)
+		return nil
+	}
+
+	router, err := NewRouterFromConfig(config, templates)
+	if err != nil {
+		log.Printf("configuração de notificações inválida, notificações desabilitadas: %v", err)
+		return nil
+	}
+
+	return router
+}
+
+// newNotifier cria o canal de notificação conforme o tipo configurado
+func newNotifier(name string, config ChannelConfig) (service.Notifier, error) {
+	switch config.Type {
+	case ChannelEmail:
+		if config.SMTPAddress == "" || config.From == "" || len(config.To) == 0 {
+			return nil, fmt.Errorf("canal %s: smtp_address, from e to são obrigatórios", name)
+		}
+		return NewEmailNotifier(name, config.SMTPAddress, config.From, config.To, config.SMTPUsername, config.SMTPPassword), nil
+	case ChannelSlack:
+		if config.URL == "" {
+			return nil, fmt.Errorf("canal %s: url do webhook do Slack é obrigatória", name)
+		}
+		return NewSlackNotifier(name, config.URL), nil
+	case ChannelWebhook:
+		if config.URL == "" {
+			return nil, fmt.Errorf("canal %s: url é obrigatória", name)
+		}
+		return NewWebhookNotifier(name, config.URL, config.Token), nil
+	case ChannelSMS:
+		if config.AccountSID == "" || config.AuthToken == "" || config.From == "" || len(config.To) == 0 {
+			return nil, fmt.Errorf("canal %s: account_sid, auth_token, from e to são obrigatórios", name)
+		}
+		return NewTwilioNotifier(name, config.AccountSID, config.AuthToken, config.From, config.To), nil
+	default:
+		return nil, fmt.Errorf("canal %s: tipo de canal inválido: %s", name, config.Type)
+	}
+}
@@ -0,0 +1,198 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/domain/service"
+)
+
+// DefaultTenant identifica as rotas aplicadas aos tenants sem rotas próprias
+const DefaultTenant = "*"
+
+// defaultBufferSize define quantas notificações aguardam envio antes de novas serem descartadas
+const defaultBufferSize = 1000
+
+// Garantir que Router implementa a interface EventPublisher
+var _ service.EventPublisher = (*Router)(nil)
+
+// delivery representa o envio de uma notificação a um canal
+type delivery struct {
+	notifier     service.Notifier
+	notification *model.Notification
+}
+
+// Router converte os eventos de negócio em notificações e as envia, em segundo plano, aos canais
+// das rotas do tenant que aceitam o tipo de evento e a gravidade. O assunto e a mensagem seguem o
+// template do tenant para o tipo de evento, quando houver
+type Router struct {
+	notifiers  map[string]service.Notifier
+	routes     map[string][]model.NotificationRoute
+	templates  repository.NotificationTemplateRepository
+	baseURL    string
+	deliveries chan delivery
+	done       chan struct{}
+}
+
+// NewRouter cria uma nova instância de Router e inicia o envio em segundo plano. As rotas são
+// indexadas pelo tenant; as rotas de DefaultTenant valem para os tenants sem rotas próprias. Sem
+// repositório de templates, as notificações usam sempre o assunto e a mensagem padrão; baseURL é a
+// URL pública da API usada nos links dos templates
+func NewRouter(notifiers []service.Notifier, routes map[string][]model.NotificationRoute, templates repository.NotificationTemplateRepository, baseURL string) (*Router, error) {
+	router := &Router{
+		notifiers:  make(map[string]service.Notifier, len(notifiers)),
+		routes:     routes,
+		templates:  templates,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		deliveries: make(chan delivery, defaultBufferSize),
+		done:       make(chan struct{}),
+	}
+
+	for _, notifier := range notifiers {
+		if _, exists := router.notifiers[notifier.Channel()]; exists {
+			return nil, fmt.Errorf("canal de notificação duplicado: %s", notifier.Channel())
+		}
+		router.notifiers[notifier.Channel()] = notifier
+	}
+
+	for tenant, tenantRoutes := range routes {
+		for _, route := range tenantRoutes {
+			if route.MinSeverity != "" && !route.MinSeverity.IsValid() {
+				return nil, fmt.Errorf("gravidade mínima inválida na rota do tenant %s: %s", tenant, route.MinSeverity)
+			}
+			for _, channel := range route.Channels {
+				if _, ok := router.notifiers[channel]; !ok {
+					return nil, fmt.Errorf("canal de notificação desconhecido na rota do tenant %s: %s", tenant, channel)
+				}
+			}
+		}
+	}
+
+	go router.run()
+
+	return router, nil
+}
+
+// Publish enfileira as notificações dos eventos para os canais das rotas do tenant do contexto.
+// Eventos internos não geram notificações; com a fila cheia a notificação é descartada e registrada no log
+func (r *Router) Publish(ctx context.Context, events []*model.Event) error {
+	tenant := model.TenantFromContext(ctx)
+	routes, ok := r.routes[tenant]
+	if !ok {
+		routes = r.routes[DefaultTenant]
+	}
+
+	// Os templates do tenant são carregados uma vez por lote, apenas quando algum evento é notificado
+	var templates map[model.EventType]*model.NotificationTemplate
+
+	for _, event := range events {
+		if !model.IsKnownEventType(event.Type) {
+			continue
+		}
+
+		notification := model.NewNotification(tenant, event)
+		channels := routeChannels(routes, notification)
+		if len(channels) == 0 {
+			continue
+		}
+
+		if templates == nil {
+			templates = r.tenantTemplates(ctx, tenant)
+		}
+		if notificationTemplate, found := templates[event.Type]; found {
+			if err := notificationTemplate.Render(notification, r.links(event)); err != nil {
+				log.Printf("erro ao aplicar a versão %d do template de %s do tenant %s, enviada a mensagem padrão: %v",
+					notificationTemplate.Version, event.Type, tenant, err)
+			}
+		}
+
+		for _, channel := range channels {
+			select {
+			case r.deliveries <- delivery{notifier: r.notifiers[channel], notification: notification}:
+			default:
+				log.Printf("fila de notificações cheia, evento %s (%s) descartado para o canal %s", event.ID, event.Type, channel)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close encerra o envio após esvaziar a fila
+func (r *Router) Close() {
+	close(r.deliveries)
+	<-r.done
+}
+
+// tenantTemplates retorna a versão aplicada dos templates do tenant, indexada pelo tipo de evento. Sem
+// repositório ou com falha na consulta, as notificações seguem com o assunto e a mensagem padrão
+func (r *Router) tenantTemplates(ctx context.Context, tenant string) map[model.EventType]*model.NotificationTemplate {
+	templates := make(map[model.EventType]*model.NotificationTemplate)
+	if r.templates == nil {
+		return templates
+	}
+
+	current, err := r.templates.GetCurrent(ctx, tenant)
+	if err != nil {
+		log.Printf("erro ao buscar templates de notificação do tenant %s, enviada a mensagem padrão: %v", tenant, err)
+		return templates
+	}
+
+	for _, notificationTemplate := range current {
+		templates[notificationTemplate.EventType] = notificationTemplate
+	}
+	return templates
+}
+
+// links monta os links da API relacionados ao evento, disponíveis nos templates
+func (r *Router) links(event *model.Event) model.NotificationLinks {
+	var links model.NotificationLinks
+	if r.baseURL == "" {
+		return links
+	}
+
+	if event.Summary != nil {
+		links.Run = r.baseURL + "/api/v1/reconciliation-runs/" + event.Summary.RunID
+	}
+	if event.BilletID != "" {
+		links.Billet = r.baseURL + "/api/v1/billets/" + event.BilletID
+	}
+	return links
+}
+
+// run envia as notificações enfileiradas
+func (r *Router) run() {
+	defer close(r.done)
+
+	for d := range r.deliveries {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		if err := d.notifier.Notify(ctx, d.notification); err != nil {
+			log.Printf("erro ao notificar evento %s pelo canal %s: %v", d.notification.Event.ID, d.notifier.Channel(), err)
+		}
+		cancel()
+	}
+}
+
+// routeChannels retorna os canais, sem repetição, das rotas que aceitam a notificação
+func routeChannels(routes []model.NotificationRoute, notification *model.Notification) []string {
+	seen := make(map[string]bool)
+	var channels []string
+
+	for _, route := range routes {
+		if !route.Matches(notification) {
+			continue
+		}
+		for _, channel := range route.Channels {
+			if !seen[channel] {
+				seen[channel] = true
+				channels = append(channels, channel)
+			}
+		}
+	}
+
+	return channels
+}
@@ -0,0 +1,210 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/service"
+)
+
+// dateLayout define o formato do dia dos contadores de importação
+const dateLayout = "2006-01-02"
+
+// Garantir que StatsHub implementa a interface EventPublisher
+var _ service.EventPublisher = (*StatsHub)(nil)
+
+// importCounters acumula os pagamentos importados de uma conta no dia
+type importCounters struct {
+	payments int
+	amount   float64
+}
+
+// tenantStats guarda os contadores de um tenant separados por conta bancária, para que cada
+// inscrito receba apenas os totais das contas do seu escopo de acesso
+type tenantStats struct {
+	date      string
+	imported  map[string]*importCounters
+	lastRunAt time.Time
+	lastRun   map[string]*model.ReconciliationRunStats
+	updatedAt time.Time
+}
+
+// StatsSubscription recebe os contadores atualizados de um tenant
+type StatsSubscription struct {
+	tenant  string
+	scope   *model.AccessScope
+	updates chan model.LiveStats
+}
+
+// Updates retorna o canal com os contadores mais recentes. Atualizações não lidas são
+// substituídas pela mais nova, de forma que um inscrito lento não atrase os demais
+func (s *StatsSubscription) Updates() <-chan model.LiveStats {
+	return s.updates
+}
+
+// StatsHub agrega os eventos internos (pagamentos importados e execuções da conciliação) em
+// contadores por tenant e os transmite aos inscritos a cada atualização
+type StatsHub struct {
+	mu            sync.Mutex
+	tenants       map[string]*tenantStats
+	subscriptions map[*StatsSubscription]struct{}
+}
+
+// NewStatsHub cria uma nova instância de StatsHub
+func NewStatsHub() *StatsHub {
+	return &StatsHub{
+		tenants:       make(map[string]*tenantStats),
+		subscriptions: make(map[*StatsSubscription]struct{}),
+	}
+}
+
+// Publish atualiza os contadores do tenant do contexto. Os eventos de uma execução da conciliação
+// chegam no mesmo lote do evento conciliacao_executada, que substitui os contadores da execução anterior
+func (h *StatsHub) Publish(ctx context.Context, events []*model.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tenant := model.TenantFromContext(ctx)
+	now := time.Now()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stats := h.tenant(tenant, now)
+
+	var run map[string]*model.ReconciliationRunStats
+	for _, event := range events {
+		if event.Type == model.EventReconciliationRun {
+			run = make(map[string]*model.ReconciliationRunStats)
+			stats.lastRunAt = event.OccurredAt
+			stats.lastRun = run
+		}
+	}
+
+	changed := run != nil
+	for _, event := range events {
+		switch event.Type {
+		case model.EventPaymentImported:
+			counters, exists := stats.imported[event.BankAccount]
+			if !exists {
+				counters = &importCounters{}
+				stats.imported[event.BankAccount] = counters
+			}
+			counters.payments++
+			counters.amount += event.Amount
+			changed = true
+		case model.EventBilletReconciled, model.EventOrphanBillet, model.EventOrphanPayment, model.EventAmbiguousReference:
+			// Eventos fora de uma execução (ex.: rematch de um boleto) não alteram a última execução
+			if run == nil {
+				continue
+			}
+			counters, exists := run[event.BankAccount]
+			if !exists {
+				counters = &model.ReconciliationRunStats{}
+				run[event.BankAccount] = counters
+			}
+			counters.Count(event)
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	stats.updatedAt = now
+	for subscription := range h.subscriptions {
+		if subscription.tenant == tenant {
+			deliver(subscription, h.snapshot(subscription.tenant, subscription.scope, now))
+		}
+	}
+
+	return nil
+}
+
+// Subscribe inscreve o tenant e o escopo de acesso do contexto e retorna os contadores atuais
+func (h *StatsHub) Subscribe(ctx context.Context) (*StatsSubscription, model.LiveStats) {
+	subscription := &StatsSubscription{
+		tenant:  model.TenantFromContext(ctx),
+		scope:   model.AccessScopeFromContext(ctx),
+		updates: make(chan model.LiveStats, 1),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.subscriptions[subscription] = struct{}{}
+	return subscription, h.snapshot(subscription.tenant, subscription.scope, time.Now())
+}
+
+// Unsubscribe cancela a inscrição
+func (h *StatsHub) Unsubscribe(subscription *StatsSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.subscriptions, subscription)
+}
+
+// Snapshot retorna os contadores atuais do tenant e do escopo de acesso do contexto
+func (h *StatsHub) Snapshot(ctx context.Context) model.LiveStats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.snapshot(model.TenantFromContext(ctx), model.AccessScopeFromContext(ctx), time.Now())
+}
+
+// tenant retorna os contadores do tenant, zerando as importações na virada do dia
+func (h *StatsHub) tenant(tenant string, now time.Time) *tenantStats {
+	stats, exists := h.tenants[tenant]
+	if !exists {
+		stats = &tenantStats{}
+		h.tenants[tenant] = stats
+	}
+
+	if today := now.Format(dateLayout); stats.date != today {
+		stats.date = today
+		stats.imported = make(map[string]*importCounters)
+	}
+
+	return stats
+}
+
+// snapshot soma os contadores das contas do escopo de acesso
+func (h *StatsHub) snapshot(tenant string, scope *model.AccessScope, now time.Time) model.LiveStats {
+	stats := h.tenant(tenant, now)
+
+	live := model.LiveStats{
+		Tenant:    tenant,
+		Date:      stats.date,
+		UpdatedAt: stats.updatedAt,
+	}
+
+	for account, counters := range stats.imported {
+		if scope.AllowsAccount(account) {
+			live.PaymentsImportedToday += counters.payments
+			live.AmountImportedToday += counters.amount
+		}
+	}
+
+	if stats.lastRun != nil {
+		live.LastRun = &model.ReconciliationRunStats{ExecutedAt: stats.lastRunAt}
+		for account, counters := range stats.lastRun {
+			if scope.AllowsAccount(account) {
+				live.LastRun.Add(counters)
+			}
+		}
+	}
+
+	return live
+}
+
+// deliver substitui a atualização pendente do inscrito pela mais recente
+func deliver(subscription *StatsSubscription, stats model.LiveStats) {
+	select {
+	case <-subscription.updates:
+	default:
+	}
+	subscription.updates <- stats
+}
@@ -0,0 +1,106 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"unicode/utf8"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// DefaultSeparator define o separador padrão das colunas do relatório regulatório
+const DefaultSeparator = ';'
+
+// Template define o layout do relatório regulatório: as colunas, na ordem exigida pela auditoria, e o separador
+type Template struct {
+	Columns   []model.ReportColumn
+	Separator rune
+}
+
+// DefaultTemplate retorna o layout padrão do relatório de conciliação diária por conta
+func DefaultTemplate() Template {
+	return Template{
+		Columns:   model.DefaultRegulatoryReportColumns,
+		Separator: DefaultSeparator,
+	}
+}
+
+// Validate verifica se o template tem colunas e se todas referenciam campos existentes
+func (t Template) Validate() error {
+	if len(t.Columns) == 0 {
+		return fmt.Errorf("template do relatório regulatório sem colunas")
+	}
+
+	for _, column := range t.Columns {
+		if column.Header == "" {
+			return fmt.Errorf("coluna do campo %s sem cabeçalho", column.Field)
+		}
+		if !model.IsValidReportField(column.Field) {
+			return fmt.Errorf("campo inválido na coluna %s: %s", column.Header, column.Field)
+		}
+	}
+
+	return nil
+}
+
+// TemplateFromEnv lê as colunas do relatório de REGULATORY_REPORT_COLUMNS (lista JSON de
+// {"header", "field"}) e o separador de REGULATORY_REPORT_SEPARATOR, usando o layout padrão
+// quando ausentes ou inválidos
+func TemplateFromEnv() Template {
+	template := DefaultTemplate()
+
+	if raw := os.Getenv("REGULATORY_REPORT_COLUMNS"); raw != "" {
+		configured := Template{Separator: template.Separator}
+		if err := json.Unmarshal([]byte(raw), &configured.Columns); err != nil {
+			log.Printf("REGULATORY_REPORT_COLUMNS inválido, usando o layout padrão: %v", err)
+		} else if err := configured.Validate(); err != nil {
+			log.Printf("REGULATORY_REPORT_COLUMNS inválido, usando o layout padrão: %v", err)
+		} else {
+			template.Columns = configured.Columns
+		}
+	}
+
+	if separator := os.Getenv("REGULATORY_REPORT_SEPARATOR"); separator != "" {
+		if r, size := utf8.DecodeRuneInString(separator); size == len(separator) && r != '"' && r != '\n' {
+			template.Separator = r
+		} else {
+			log.Printf("REGULATORY_REPORT_SEPARATOR inválido, usando %q", template.Separator)
+		}
+	}
+
+	return template
+}
+
+// WriteCSV escreve as estatísticas diárias no layout do template, com uma linha de cabeçalho
+func WriteCSV(w io.Writer, template Template, statistics []*model.DailyReconciliationStatistics) error {
+	writer := csv.NewWriter(w)
+	if template.Separator != 0 {
+		writer.Comma = template.Separator
+	}
+	writer.UseCRLF = true
+
+	header := make([]string, len(template.Columns))
+	for i, column := range template.Columns {
+		header[i] = column.Header
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, stats := range statistics {
+		record := make([]string, len(template.Columns))
+		for i, column := range template.Columns {
+			record[i], _ = stats.Field(column.Field)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,232 @@
+package report
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// SignatureAlgorithm identifica o algoritmo da assinatura eletrônica dos papéis de trabalho
+const SignatureAlgorithm = "Ed25519"
+
+// WorkingPaperTemplate define o layout do papel de trabalho da auditoria externa: as colunas das
+// conciliações, na ordem exigida pela auditoria, e o separador
+type WorkingPaperTemplate struct {
+	Columns   []model.ReportColumn
+	Separator rune
+}
+
+// DefaultWorkingPaperTemplate retorna o layout padrão do papel de trabalho
+func DefaultWorkingPaperTemplate() WorkingPaperTemplate {
+	return WorkingPaperTemplate{
+		Columns:   model.DefaultWorkingPaperColumns,
+		Separator: DefaultSeparator,
+	}
+}
+
+// Validate verifica se o template tem colunas e se todas referenciam campos existentes
+func (t WorkingPaperTemplate) Validate() error {
+	if len(t.Columns) == 0 {
+		return fmt.Errorf("template do papel de trabalho sem colunas")
+	}
+
+	for _, column := range t.Columns {
+		if column.Header == "" {
+			return fmt.Errorf("coluna do campo %s sem cabeçalho", column.Field)
+		}
+		if !model.IsValidWorkingPaperField(column.Field) {
+			return fmt.Errorf("campo inválido na coluna %s: %s", column.Header, column.Field)
+		}
+	}
+
+	return nil
+}
+
+// WorkingPaperTemplateFromEnv lê as colunas do papel de trabalho de AUDIT_WORKING_PAPER_COLUMNS (lista
+// JSON de {"header", "field"}) e o separador de AUDIT_WORKING_PAPER_SEPARATOR, usando o layout padrão
+// quando ausentes ou inválidos
+func WorkingPaperTemplateFromEnv() WorkingPaperTemplate {
+	template := DefaultWorkingPaperTemplate()
+
+	if raw := os.Getenv("AUDIT_WORKING_PAPER_COLUMNS"); raw != "" {
+		configured := WorkingPaperTemplate{Separator: template.Separator}
+		if err := json.Unmarshal([]byte(raw), &configured.Columns); err != nil {
+			log.Printf("AUDIT_WORKING_PAPER_COLUMNS inválido, usando o layout padrão: %v", err)
+		} else if err := configured.Validate(); err != nil {
+			log.Printf("AUDIT_WORKING_PAPER_COLUMNS inválido, usando o layout padrão: %v", err)
+		} else {
+			template.Columns = configured.Columns
+		}
+	}
+
+	if separator := os.Getenv("AUDIT_WORKING_PAPER_SEPARATOR"); separator != "" {
+		if r, size := utf8.DecodeRuneInString(separator); size == len(separator) && r != '"' && r != '\n' {
+			template.Separator = r
+		} else {
+			log.Printf("AUDIT_WORKING_PAPER_SEPARATOR inválido, usando %q", template.Separator)
+		}
+	}
+
+	return template
+}
+
+// Signer assina eletronicamente os papéis de trabalho com a chave Ed25519 da empresa. A assinatura
+// cobre o hash SHA-256 do conteúdo, o responsável e o momento da assinatura, e pode ser verificada
+// pelos auditores com a chave pública publicada no próprio arquivo
+type Signer struct {
+	privateKey ed25519.PrivateKey
+}
+
+// NewSigner cria o assinante a partir da semente de 32 bytes da chave Ed25519
+func NewSigner(seed []byte) (*Signer, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("semente da chave de assinatura deve ter %d bytes, obtido %d", ed25519.SeedSize, len(seed))
+	}
+	return &Signer{privateKey: ed25519.NewKeyFromSeed(seed)}, nil
+}
+
+// SignerFromEnv cria o assinante a partir da semente em base64 de AUDIT_SIGNING_KEY. Retorna nil quando
+// a chave não está configurada ou é inválida, e os papéis de trabalho não podem ser gerados
+func SignerFromEnv() *Signer {
+	raw := os.Getenv("AUDIT_SIGNING_KEY")
+	if raw == "" {
+		return nil
+	}
+
+	seed, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		log.Printf("AUDIT_SIGNING_KEY inválida, papéis de trabalho desabilitados: %v", err)
+		return nil
+	}
+
+	signer, err := NewSigner(seed)
+	if err != nil {
+		log.Printf("AUDIT_SIGNING_KEY inválida, papéis de trabalho desabilitados: %v", err)
+		return nil
+	}
+	return signer
+}
+
+// WorkingPaperSignature representa a assinatura eletrônica do papel de trabalho pelo responsável
+type WorkingPaperSignature struct {
+	Signer      string
+	SignedAt    time.Time
+	ContentHash string
+	Signature   string
+	PublicKey   string
+}
+
+// Sign assina o conteúdo em nome do responsável. A mensagem assinada é "hash|responsável|data", com o
+// hash SHA-256 do conteúdo em hexadecimal e a data em RFC 3339
+func (s *Signer) Sign(content []byte, signer string, signedAt time.Time) WorkingPaperSignature {
+	hash := sha256.Sum256(content)
+	contentHash := hex.EncodeToString(hash[:])
+	signedAt = signedAt.UTC()
+
+	message := contentHash + "|" + signer + "|" + signedAt.Format(time.RFC3339)
+	return WorkingPaperSignature{
+		Signer:      signer,
+		SignedAt:    signedAt,
+		ContentHash: contentHash,
+		Signature:   base64.StdEncoding.EncodeToString(ed25519.Sign(s.privateKey, []byte(message))),
+		PublicKey:   base64.StdEncoding.EncodeToString(s.privateKey.Public().(ed25519.PublicKey)),
+	}
+}
+
+// WriteWorkingPaper escreve o papel de trabalho em CSV: o cabeçalho com o período e o responsável, as
+// conciliações no layout do template, os totais por conta com o total geral e, por fim, o bloco da
+// assinatura eletrônica, que cobre todo o conteúdo anterior a ele
+func WriteWorkingPaper(w io.Writer, template WorkingPaperTemplate, paper *model.WorkingPaper, signer *Signer) error {
+	var content bytes.Buffer
+	writer := newWorkingPaperWriter(&content, template.Separator)
+
+	period := paper.StartDate.Format("2006-01-02") + " a " + paper.EndDate.Format("2006-01-02")
+	writer.Write([]string{"PAPEL DE TRABALHO - CONCILIACAO BANCARIA"})
+	writer.Write([]string{"PERIODO", period})
+	if paper.BankAccount != "" {
+		writer.Write([]string{"CONTA", paper.BankAccount})
+	}
+	writer.Write([]string{"PREPARADO_POR", paper.PreparedBy})
+	writer.Write([]string{"GERADO_EM", paper.GeneratedAt.UTC().Format(time.RFC3339)})
+	writer.Write(nil)
+
+	header := make([]string, len(template.Columns))
+	for i, column := range template.Columns {
+		header[i] = column.Header
+	}
+	writer.Write(header)
+
+	for _, line := range paper.Lines {
+		record := make([]string, len(template.Columns))
+		for i, column := range template.Columns {
+			record[i], _ = line.Field(column.Field)
+		}
+		writer.Write(record)
+	}
+
+	writer.Write(nil)
+	writer.Write([]string{"TOTAIS POR CONTA"})
+	writer.Write([]string{"CONTA", "QTD_CONCILIACOES", "VLR_BOLETOS", "VLR_PAGO", "VLR_DIFERENCA"})
+	for _, totals := range paper.Totals {
+		writer.Write(totalsRecord(totals.BankAccount, totals))
+	}
+	writer.Write(totalsRecord("TOTAL GERAL", &paper.GrandTotal))
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+
+	signature := signer.Sign(content.Bytes(), paper.PreparedBy, paper.GeneratedAt)
+
+	signatureWriter := newWorkingPaperWriter(&content, template.Separator)
+	signatureWriter.Write(nil)
+	signatureWriter.Write([]string{"ASSINATURA ELETRONICA"})
+	signatureWriter.Write([]string{"RESPONSAVEL", signature.Signer})
+	signatureWriter.Write([]string{"DATA_ASSINATURA", signature.SignedAt.Format(time.RFC3339)})
+	signatureWriter.Write([]string{"ALGORITMO", SignatureAlgorithm})
+	signatureWriter.Write([]string{"HASH_SHA256", signature.ContentHash})
+	signatureWriter.Write([]string{"ASSINATURA", signature.Signature})
+	signatureWriter.Write([]string{"CHAVE_PUBLICA", signature.PublicKey})
+	signatureWriter.Flush()
+	if err := signatureWriter.Error(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(content.Bytes())
+	return err
+}
+
+// newWorkingPaperWriter cria o escritor CSV do papel de trabalho com o separador do template
+func newWorkingPaperWriter(w io.Writer, separator rune) *csv.Writer {
+	writer := csv.NewWriter(w)
+	if separator != 0 {
+		writer.Comma = separator
+	}
+	writer.UseCRLF = true
+	return writer
+}
+
+// totalsRecord formata os totais de uma conta, ou o total geral, para o papel de trabalho
+func totalsRecord(label string, totals *model.WorkingPaperAccountTotals) []string {
+	return []string{
+		label,
+		strconv.Itoa(totals.Lines),
+		strconv.FormatFloat(totals.BilletAmount, 'f', 2, 64),
+		strconv.FormatFloat(totals.PaidAmount, 'f', 2, 64),
+		strconv.FormatFloat(totals.AmountDiff, 'f', 2, 64),
+	}
+}
@@ -0,0 +1,320 @@
+package selftest
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/service"
+)
+
+// goldenDataset traz os cenários conhecidos do motor de matching: matches exatos, por código de barras,
+// por documento e por nome aproximado do pagador, PIX no mesmo dia, com diferença de valor, N:1, 1:N e
+// órfãos, com o resultado esperado de cada um
+//
+//go:embed golden_dataset.json
+var goldenDataset []byte
+
+// dataset representa um conjunto de cenários do selftest
+type dataset struct {
+	Scenarios []scenario `json:"scenarios"`
+}
+
+// scenario representa boletos e pagamentos conciliados isoladamente e o resultado esperado. Sem
+// estratégias, o cenário usa a ordem padrão
+type scenario struct {
+	Name              string                       `json:"name"`
+	Description       string                       `json:"description"`
+	Strategies        []model.ConciliationStrategy `json:"strategies,omitempty"`
+	Billets           []billetFixture              `json:"billets"`
+	Payments          []paymentFixture             `json:"payments"`
+	Expected          []outcome                    `json:"expected"`
+	UnmatchedPayments []string                     `json:"unmatched_payments,omitempty"`
+}
+
+// billetFixture representa um boleto do cenário
+type billetFixture struct {
+	BilletID      string  `json:"billet_id"`
+	BankAccount   string  `json:"bank_account"`
+	Amount        float64 `json:"amount"`
+	IssuanceDate  string  `json:"issuance_date"`
+	ReferenceID   *string `json:"reference_id,omitempty"`
+	CustomerID    *string `json:"customer_id,omitempty"`
+	BarCode       string  `json:"bar_code,omitempty"`
+	DigitableLine string  `json:"digitable_line,omitempty"`
+	PayerDocument string  `json:"payer_document,omitempty"`
+	PayerName     string  `json:"payer_name,omitempty"`
+	DueDate       string  `json:"due_date,omitempty"`
+	InterestRate  float64 `json:"interest_rate,omitempty"`
+	FinePercent   float64 `json:"fine_percent,omitempty"`
+}
+
+// paymentFixture representa um pagamento do cenário
+type paymentFixture struct {
+	TransactionID string  `json:"transaction_id"`
+	BankAccount   string  `json:"bank_account"`
+	Amount        float64 `json:"amount"`
+	PaymentDate   string  `json:"payment_date"`
+	ReferenceID   *string `json:"reference_id,omitempty"`
+	BarCode       string  `json:"bar_code,omitempty"`
+	PayerDocument string  `json:"payer_document,omitempty"`
+	Description   string  `json:"description,omitempty"`
+	PaymentMethod string  `json:"payment_method,omitempty"`
+}
+
+// outcome representa o resultado de um boleto: os pagamentos pareados, o status, a estratégia e a
+// diferença de valor
+type outcome struct {
+	BilletID             string                     `json:"billet_id"`
+	TransactionIDs       []string                   `json:"transaction_ids,omitempty"`
+	ConciliationStatus   model.ConciliationStatus   `json:"conciliation_status"`
+	ConciliationStrategy model.ConciliationStrategy `json:"conciliation_strategy,omitempty"`
+	AmountDiff           float64                    `json:"amount_diff,omitempty"`
+}
+
+// String descreve o resultado nas divergências do relatório
+func (o outcome) String() string {
+	description := string(o.ConciliationStatus)
+	if o.ConciliationStrategy != "" {
+		description += " por " + string(o.ConciliationStrategy)
+	}
+	if len(o.TransactionIDs) > 0 {
+		description += " com " + strings.Join(o.TransactionIDs, ",")
+	}
+	if o.AmountDiff != 0 {
+		description += " (diferença " + strconv.FormatFloat(o.AmountDiff, 'f', 2, 64) + ")"
+	}
+	return description
+}
+
+// equal compara dois resultados, com a diferença de valor em centavos
+func (o outcome) equal(other outcome) bool {
+	return o.ConciliationStatus == other.ConciliationStatus &&
+		o.ConciliationStrategy == other.ConciliationStrategy &&
+		strings.Join(o.TransactionIDs, ",") == strings.Join(other.TransactionIDs, ",") &&
+		roundCents(o.AmountDiff) == roundCents(other.AmountDiff)
+}
+
+// Report representa o resultado do selftest: aprovado quando todos os cenários produzem o resultado esperado
+type Report struct {
+	EngineVersion string           `json:"engine_version"`
+	Passed        bool             `json:"passed"`
+	DurationMs    int64            `json:"duration_ms"`
+	Scenarios     []ScenarioResult `json:"scenarios"`
+}
+
+// Failed conta os cenários com divergências
+func (r *Report) Failed() int {
+	failed := 0
+	for _, scenario := range r.Scenarios {
+		if !scenario.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// ScenarioResult representa o resultado de um cenário e as divergências em relação ao esperado
+type ScenarioResult struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Passed      bool     `json:"passed"`
+	Differences []string `json:"differences,omitempty"`
+}
+
+// Run executa o motor de matching, com os parâmetros padrão e em memória, contra o dataset dourado
+func Run(ctx context.Context) (*Report, error) {
+	return RunDataset(ctx, goldenDataset)
+}
+
+// RunDataset executa o motor de matching contra os cenários do dataset em JSON, no formato do dataset
+// dourado. Cada cenário é conciliado isoladamente, sem banco de dados
+func RunDataset(ctx context.Context, data []byte) (*Report, error) {
+	var scenarios dataset
+	if err := json.Unmarshal(data, &scenarios); err != nil {
+		return nil, fmt.Errorf("dataset inválido: %w", err)
+	}
+	if len(scenarios.Scenarios) == 0 {
+		return nil, fmt.Errorf("dataset sem cenários")
+	}
+
+	started := time.Now()
+	report := &Report{
+		EngineVersion: service.EngineVersion,
+		Passed:        true,
+		Scenarios:     make([]ScenarioResult, 0, len(scenarios.Scenarios)),
+	}
+
+	for _, scenario := range scenarios.Scenarios {
+		result, err := runScenario(ctx, scenario)
+		if err != nil {
+			return nil, fmt.Errorf("cenário %s: %w", scenario.Name, err)
+		}
+		report.Passed = report.Passed && result.Passed
+		report.Scenarios = append(report.Scenarios, *result)
+	}
+
+	report.DurationMs = time.Since(started).Milliseconds()
+	return report, nil
+}
+
+// runScenario concilia os boletos e pagamentos do cenário e compara o resultado de cada boleto e os
+// pagamentos não utilizados com os esperados
+func runScenario(ctx context.Context, scenario scenario) (*ScenarioResult, error) {
+	billets, payments, err := scenario.fixtures()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(scenario.Strategies) > 0 {
+		configs := make([]model.StrategyConfig, 0, len(scenario.Strategies))
+		for _, strategy := range scenario.Strategies {
+			configs = append(configs, model.StrategyConfig{Strategy: strategy})
+		}
+		ctx = service.WithStrategies(ctx, configs)
+	}
+
+	result, err := service.NewReconciliationService().ReconcileBilletsWithPayments(ctx, billets, payments)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, used := outcomes(result)
+	var differences []string
+
+	expected := make(map[string]bool, len(scenario.Expected))
+	for _, want := range scenario.Expected {
+		expected[want.BilletID] = true
+		sort.Strings(want.TransactionIDs)
+
+		got, found := actual[want.BilletID]
+		if !found {
+			differences = append(differences, fmt.Sprintf("%s: esperado %s, obtido nenhum resultado", want.BilletID, want))
+		} else if !want.equal(got) {
+			differences = append(differences, fmt.Sprintf("%s: esperado %s, obtido %s", want.BilletID, want, got))
+		}
+	}
+
+	for _, billet := range billets {
+		if got, found := actual[billet.ID]; found && !expected[billet.ID] {
+			differences = append(differences, fmt.Sprintf("%s: resultado inesperado %s", billet.ID, got))
+		}
+	}
+
+	var unmatched []string
+	for _, payment := range payments {
+		if !used[payment.ID] {
+			unmatched = append(unmatched, payment.ID)
+		}
+	}
+	wantUnmatched := append([]string(nil), scenario.UnmatchedPayments...)
+	sort.Strings(wantUnmatched)
+	sort.Strings(unmatched)
+	if strings.Join(wantUnmatched, ",") != strings.Join(unmatched, ",") {
+		differences = append(differences, fmt.Sprintf("pagamentos sem uso: esperado [%s], obtido [%s]",
+			strings.Join(wantUnmatched, ","), strings.Join(unmatched, ",")))
+	}
+
+	return &ScenarioResult{
+		Name:        scenario.Name,
+		Description: scenario.Description,
+		Passed:      len(differences) == 0,
+		Differences: differences,
+	}, nil
+}
+
+// fixtures converte os boletos e pagamentos do cenário para o modelo de domínio
+func (s scenario) fixtures() ([]*model.Billet, []*model.Payment, error) {
+	billets := make([]*model.Billet, 0, len(s.Billets))
+	for _, fixture := range s.Billets {
+		issuanceDate, err := time.Parse("2006-01-02", fixture.IssuanceDate)
+		if err != nil {
+			return nil, nil, fmt.Errorf("issuance_date inválida do boleto %s: %w", fixture.BilletID, err)
+		}
+
+		billet := model.NewBillet(fixture.BilletID, fixture.BankAccount, fixture.Amount, issuanceDate, fixture.ReferenceID)
+		billet.CustomerID = fixture.CustomerID
+		billet.BarCode = fixture.BarCode
+		billet.DigitableLine = fixture.DigitableLine
+		billet.PayerDocument = fixture.PayerDocument
+		billet.PayerName = fixture.PayerName
+		billet.InterestRate = fixture.InterestRate
+		billet.FinePercent = fixture.FinePercent
+
+		if fixture.DueDate != "" {
+			dueDate, err := time.Parse("2006-01-02", fixture.DueDate)
+			if err != nil {
+				return nil, nil, fmt.Errorf("due_date inválida do boleto %s: %w", fixture.BilletID, err)
+			}
+			billet.DueDate = &dueDate
+		}
+
+		billets = append(billets, billet)
+	}
+
+	payments := make([]*model.Payment, 0, len(s.Payments))
+	for _, fixture := range s.Payments {
+		paymentDate, err := time.Parse("2006-01-02", fixture.PaymentDate)
+		if err != nil {
+			return nil, nil, fmt.Errorf("payment_date inválida do pagamento %s: %w", fixture.TransactionID, err)
+		}
+
+		payment := model.NewPayment(fixture.TransactionID, fixture.BankAccount, fixture.Amount, paymentDate, fixture.ReferenceID)
+		payment.BarCode = fixture.BarCode
+		payment.PayerDocument = fixture.PayerDocument
+		payment.Description = fixture.Description
+		payment.PaymentMethod = model.PaymentMethod(fixture.PaymentMethod)
+		payments = append(payments, payment)
+	}
+
+	return billets, payments, nil
+}
+
+// outcomes retorna o resultado de cada boleto da conciliação, indexado pelo boleto, e os pagamentos utilizados
+func outcomes(result *model.ReconciliationResult) (map[string]outcome, map[string]bool) {
+	actual := make(map[string]outcome)
+	used := make(map[string]bool)
+
+	for _, reconciled := range result.ReconciledBillets {
+		transactionIDs := append([]string(nil), reconciled.TransactionIDs...)
+		if len(transactionIDs) == 0 && reconciled.TransactionID != "" {
+			transactionIDs = []string{reconciled.TransactionID}
+		}
+		sort.Strings(transactionIDs)
+
+		for _, transactionID := range transactionIDs {
+			used[transactionID] = true
+		}
+		actual[reconciled.BilletID] = outcome{
+			BilletID:             reconciled.BilletID,
+			TransactionIDs:       transactionIDs,
+			ConciliationStatus:   reconciled.ConciliationStatus,
+			ConciliationStrategy: reconciled.ConciliationStrategy,
+			AmountDiff:           reconciled.AmountDiff,
+		}
+	}
+
+	for _, ambiguous := range result.AmbiguousReferences {
+		for _, billetID := range ambiguous.BilletIDs {
+			actual[billetID] = outcome{BilletID: billetID, ConciliationStatus: ambiguous.ConciliationStatus}
+		}
+	}
+
+	for _, billet := range result.NonReconciledBillets {
+		actual[billet.ID] = outcome{BilletID: billet.ID, ConciliationStatus: model.StatusNotReconciled}
+	}
+
+	return actual, used
+}
+
+// roundCents arredonda um valor monetário para centavos
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,181 @@
+package siem
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/service"
+)
+
+// defaultBufferSize define quantos eventos aguardam envio antes de novos eventos serem descartados
+const defaultBufferSize = 1000
+
+// Garantir que Exporter implementa a interface AuditLogger
+var _ service.AuditLogger = (*Exporter)(nil)
+
+// Config representa a configuração do encaminhamento de auditoria ao SIEM
+type Config struct {
+	Transport          string // syslog-tcp, syslog-tls ou http
+	Address            string // host:porta do coletor syslog ou URL do endpoint HTTP
+	Format             string // json ou cef
+	Token              string // Bearer token do endpoint HTTP
+	CAFile             string // CA usada para validar o coletor TLS/HTTPS
+	InsecureSkipVerify bool
+	BufferSize         int
+}
+
+// Exporter encaminha eventos de auditoria ao SIEM em segundo plano, sem bloquear as requisições
+type Exporter struct {
+	format    string
+	transport transport
+	events    chan *model.AuditEvent
+	done      chan struct{}
+}
+
+// NewExporter cria uma nova instância de Exporter e inicia o envio em segundo plano
+func NewExporter(config Config) (*Exporter, error) {
+	if config.Format == "" {
+		config.Format = FormatJSON
+	}
+	if config.Format != FormatJSON && config.Format != FormatCEF {
+		return nil, fmt.Errorf("formato de auditoria inválido: %s", config.Format)
+	}
+	if config.Address == "" {
+		return nil, fmt.Errorf("endereço do SIEM não informado")
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaultBufferSize
+	}
+
+	tlsConfig, err := buildTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var t transport
+	switch config.Transport {
+	case TransportSyslogTCP:
+		t = &syslogTransport{address: config.Address}
+	case TransportSyslogTLS:
+		t = &syslogTransport{address: config.Address, tlsConfig: tlsConfig}
+	case TransportHTTP:
+		contentType := "application/json"
+		if config.Format == FormatCEF {
+			contentType = "text/plain; charset=utf-8"
+		}
+		t = &httpTransport{
+			url:         config.Address,
+			token:       config.Token,
+			contentType: contentType,
+			client: &http.Client{
+				Timeout:   sendTimeout,
+				Transport: &http.Transport{TLSClientConfig: tlsConfig},
+			},
+		}
+	default:
+		return nil, fmt.Errorf("transporte de auditoria inválido: %s", config.Transport)
+	}
+
+	exporter := &Exporter{
+		format:    config.Format,
+		transport: t,
+		events:    make(chan *model.AuditEvent, config.BufferSize),
+		done:      make(chan struct{}),
+	}
+	go exporter.run()
+
+	return exporter, nil
+}
+
+// NewExporterFromEnv cria o exporter a partir das variáveis SIEM_TRANSPORT, SIEM_ADDRESS, SIEM_FORMAT,
+// SIEM_TOKEN, SIEM_CA_FILE e SIEM_INSECURE_SKIP_VERIFY. Retorna nil quando o SIEM não está configurado
+func NewExporterFromEnv() *Exporter {
+	transport := os.Getenv("SIEM_TRANSPORT")
+	if transport == "" {
+		return nil
+	}
+
+	insecure, _ := strconv.ParseBool(os.Getenv("SIEM_INSECURE_SKIP_VERIFY"))
+
+	exporter, err := NewExporter(Config{
+		Transport:          transport,
+		Address:            os.Getenv("SIEM_ADDRESS"),
+		Format:             os.Getenv("SIEM_FORMAT"),
+		Token:              os.Getenv("SIEM_TOKEN"),
+		CAFile:             os.Getenv("SIEM_CA_FILE"),
+		InsecureSkipVerify: insecure,
+	})
+	if err != nil {
+		log.Printf("configuração do SIEM inválida, exportação de auditoria desabilitada: %v", err)
+		return nil
+	}
+
+	return exporter
+}
+
+// Record enfileira o evento para envio; com a fila cheia o evento é descartado e registrado no log
+func (e *Exporter) Record(ctx context.Context, event *model.AuditEvent) {
+	select {
+	case e.events <- event:
+	default:
+		log.Printf("fila de auditoria cheia, evento %s (%s) descartado", event.ID, event.Action)
+	}
+}
+
+// Close encerra o envio após esvaziar a fila
+func (e *Exporter) Close() error {
+	close(e.events)
+	<-e.done
+	return e.transport.close()
+}
+
+// run envia os eventos enfileirados, com uma nova tentativa em caso de falha
+func (e *Exporter) run() {
+	defer close(e.done)
+
+	for event := range e.events {
+		payload, err := formatEvent(e.format, event)
+		if err != nil {
+			log.Printf("erro ao serializar evento de auditoria %s: %v", event.ID, err)
+			continue
+		}
+
+		if err := e.transport.send(event, payload); err != nil {
+			if err := e.transport.send(event, payload); err != nil {
+				log.Printf("erro ao encaminhar evento de auditoria %s ao SIEM: %v", event.ID, err)
+			}
+		}
+	}
+}
+
+// buildTLSConfig monta a configuração TLS a partir da CA informada
+func buildTLSConfig(config Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	if config.CAFile == "" {
+		return tlsConfig, nil
+	}
+
+	caPEM, err := os.ReadFile(config.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler CA do SIEM: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("CA do SIEM inválida: %s", config.CAFile)
+	}
+	tlsConfig.RootCAs = pool
+
+	return tlsConfig, nil
+}
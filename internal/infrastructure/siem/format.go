@@ -0,0 +1,122 @@
+package siem
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// Formatos de mensagem suportados
+const (
+	FormatJSON = "json"
+	FormatCEF  = "cef"
+)
+
+// Identificação do produto nas mensagens CEF e syslog
+const (
+	cefVendor  = "ConciliacaoBancaria"
+	cefProduct = "conciliacao-bancaria"
+	cefVersion = "1.0"
+	appName    = "conciliacao-bancaria"
+)
+
+// syslogFacility é a facility "log audit" (13) definida na RFC 5424
+const syslogFacility = 13
+
+// Severidades syslog usadas para as ações bem-sucedidas e para as falhas
+const (
+	syslogSeverityInfo    = 6
+	syslogSeverityWarning = 4
+)
+
+// formatEvent serializa o evento no formato configurado
+func formatEvent(format string, event *model.AuditEvent) ([]byte, error) {
+	if format == FormatCEF {
+		return []byte(formatCEF(event)), nil
+	}
+	return json.Marshal(event)
+}
+
+// formatCEF serializa o evento no Common Event Format (ArcSight)
+func formatCEF(event *model.AuditEvent) string {
+	severity := 3
+	if event.Outcome == model.AuditOutcomeFailure {
+		severity = 7
+	}
+
+	extensions := []string{
+		"rt=" + cefExtension(fmt.Sprintf("%d", event.OccurredAt.UnixMilli())),
+		"cat=" + cefExtension(string(event.Category)),
+		"outcome=" + cefExtension(string(event.Outcome)),
+	}
+
+	if event.Subject != "" {
+		extensions = append(extensions, "suser="+cefExtension(event.Subject))
+	}
+	if event.SourceIP != "" {
+		extensions = append(extensions, "src="+cefExtension(event.SourceIP))
+	}
+	if event.Method != "" {
+		extensions = append(extensions, "requestMethod="+cefExtension(event.Method))
+	}
+	if event.Path != "" {
+		extensions = append(extensions, "request="+cefExtension(event.Path))
+	}
+	if event.StatusCode != 0 {
+		extensions = append(extensions, "cn1Label=statusCode", fmt.Sprintf("cn1=%d", event.StatusCode))
+	}
+	if event.Tenant != "" {
+		extensions = append(extensions, "cs1Label=tenant", "cs1="+cefExtension(event.Tenant))
+	}
+	extensions = append(extensions, "externalId="+cefExtension(event.ID))
+
+	return fmt.Sprintf("CEF:0|%s|%s|%s|%s|%s|%d|%s",
+		cefHeader(cefVendor),
+		cefHeader(cefProduct),
+		cefHeader(cefVersion),
+		cefHeader(string(event.Category)+":"+event.Action),
+		cefHeader(event.Action),
+		severity,
+		strings.Join(extensions, " "),
+	)
+}
+
+// cefHeader escapa os caracteres reservados de um campo do cabeçalho CEF
+func cefHeader(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "\n", " ", "\r", " ").Replace(value)
+}
+
+// cefExtension escapa os caracteres reservados de um valor de extensão CEF
+func cefExtension(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "=", `\=`, "\n", `\n`, "\r", `\r`).Replace(value)
+}
+
+// syslogFrame monta a mensagem RFC 5424 com enquadramento por contagem de octetos (RFC 6587),
+// como exigido no transporte TCP/TLS
+func syslogFrame(event *model.AuditEvent, payload []byte) []byte {
+	severity := syslogSeverityInfo
+	if event.Outcome == model.AuditOutcomeFailure {
+		severity = syslogSeverityWarning
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	message := fmt.Sprintf("<%d>1 %s %s %s %d %s - %s",
+		syslogFacility*8+severity,
+		event.OccurredAt.UTC().Format(time.RFC3339Nano),
+		hostname,
+		appName,
+		os.Getpid(),
+		string(event.Category),
+		payload,
+	)
+
+	return []byte(fmt.Sprintf("%d %s", len(message), message))
+}
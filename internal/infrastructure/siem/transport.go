@@ -0,0 +1,115 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// Transportes suportados para o envio ao SIEM
+const (
+	TransportSyslogTCP = "syslog-tcp"
+	TransportSyslogTLS = "syslog-tls"
+	TransportHTTP      = "http"
+)
+
+// sendTimeout define o tempo máximo de cada envio ao SIEM
+const sendTimeout = 10 * time.Second
+
+// transport entrega uma mensagem já formatada ao SIEM
+type transport interface {
+	send(event *model.AuditEvent, payload []byte) error
+	close() error
+}
+
+// syslogTransport mantém uma conexão TCP (opcionalmente TLS) com o coletor syslog,
+// reconectando após falhas
+type syslogTransport struct {
+	address   string
+	tlsConfig *tls.Config
+	conn      net.Conn
+}
+
+// send escreve a mensagem enquadrada, descartando a conexão em caso de erro
+func (t *syslogTransport) send(event *model.AuditEvent, payload []byte) error {
+	if t.conn == nil {
+		conn, err := t.dial()
+		if err != nil {
+			return fmt.Errorf("erro ao conectar no coletor syslog %s: %w", t.address, err)
+		}
+		t.conn = conn
+	}
+
+	t.conn.SetWriteDeadline(time.Now().Add(sendTimeout))
+	if _, err := t.conn.Write(syslogFrame(event, payload)); err != nil {
+		t.conn.Close()
+		t.conn = nil
+		return fmt.Errorf("erro ao enviar mensagem syslog: %w", err)
+	}
+
+	return nil
+}
+
+// dial abre a conexão com o coletor
+func (t *syslogTransport) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: sendTimeout}
+	if t.tlsConfig != nil {
+		return tls.DialWithDialer(dialer, "tcp", t.address, t.tlsConfig)
+	}
+	return dialer.Dial("tcp", t.address)
+}
+
+// close encerra a conexão com o coletor
+func (t *syslogTransport) close() error {
+	if t.conn == nil {
+		return nil
+	}
+	return t.conn.Close()
+}
+
+// httpTransport envia cada mensagem em um POST para o endpoint de ingestão do SIEM
+type httpTransport struct {
+	url         string
+	token       string
+	contentType string
+	client      *http.Client
+}
+
+// send realiza o POST da mensagem
+func (t *httpTransport) send(event *model.AuditEvent, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", t.contentType)
+	if t.token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao enviar evento ao SIEM: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("SIEM respondeu com status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// close não mantém recursos abertos
+func (t *httpTransport) close() error {
+	return nil
+}
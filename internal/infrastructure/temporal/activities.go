@@ -0,0 +1,96 @@
+package temporal
+
+import (
+	"context"
+
+	"go.temporal.io/sdk/temporal"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/pkg/errors"
+)
+
+// Importer carrega boletos e pagamentos de uma origem externa (arquivo, ERP, banco) para o repositório
+type Importer interface {
+	Import(ctx context.Context, source string) (*ImportSummary, error)
+}
+
+// Exporter publica o resultado de uma conciliação em um destino externo
+type Exporter interface {
+	Export(ctx context.Context, destination string, result *model.ReconciliationResult) error
+}
+
+// ImportSummary resume a quantidade de registros importados
+type ImportSummary struct {
+	Billets  int `json:"billets"`
+	Payments int `json:"payments"`
+}
+
+// Activities agrupa as activities do fluxo importar→conciliar→exportar
+type Activities struct {
+	importer              Importer
+	exporter              Exporter
+	reconciliationUseCase *usecase.ReconciliationUseCase
+}
+
+// NewActivities cria uma nova instância de Activities. Importer e Exporter são opcionais:
+// quando nulos, as etapas correspondentes são ignoradas
+func NewActivities(importer Importer, exporter Exporter, reconciliationUseCase *usecase.ReconciliationUseCase) *Activities {
+	return &Activities{
+		importer:              importer,
+		exporter:              exporter,
+		reconciliationUseCase: reconciliationUseCase,
+	}
+}
+
+// Import importa os dados da origem informada
+func (a *Activities) Import(ctx context.Context, source string) (*ImportSummary, error) {
+	if a.importer == nil || source == "" {
+		return &ImportSummary{}, nil
+	}
+
+	summary, err := a.importer.Import(ctx, source)
+	if err != nil {
+		return nil, activityError(err)
+	}
+
+	return summary, nil
+}
+
+// Reconcile executa a conciliação dos boletos e pagamentos pendentes
+func (a *Activities) Reconcile(ctx context.Context, params usecase.ReconciliationParams) (*model.ReconciliationResult, error) {
+	result, err := a.reconciliationUseCase.RunReconciliation(ctx, params)
+	if err != nil {
+		return nil, activityError(err)
+	}
+
+	return result, nil
+}
+
+// Export publica o resultado da conciliação no destino informado
+func (a *Activities) Export(ctx context.Context, destination string, result *model.ReconciliationResult) error {
+	if a.exporter == nil || destination == "" {
+		return nil
+	}
+
+	if err := a.exporter.Export(ctx, destination, result); err != nil {
+		return activityError(err)
+	}
+
+	return nil
+}
+
+// activityError marca erros de validação e conflito como não retentáveis,
+// deixando o Temporal repetir apenas falhas transitórias (banco, rede)
+func activityError(err error) error {
+	switch {
+	case errors.IsValidationError(err):
+		return temporal.NewNonRetryableApplicationError(err.Error(), "ValidationError", err)
+	case errors.IsConflictError(err):
+		return temporal.NewNonRetryableApplicationError(err.Error(), "ConflictError", err)
+	case errors.IsNotFoundError(err):
+		return temporal.NewNonRetryableApplicationError(err.Error(), "NotFoundError", err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,82 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// ERPDriftWorkflowID identifica a execução agendada da verificação de divergências com o ERP
+const ERPDriftWorkflowID = "divergencias-erp"
+
+// DefaultERPDriftCron agenda a verificação de divergências com o ERP para a cada 6 horas
+const DefaultERPDriftCron = "0 */6 * * *"
+
+// ERPDriftActivities agrupa as activities da verificação de divergências com o ERP
+type ERPDriftActivities struct {
+	erpDriftUseCase *usecase.ERPDriftUseCase
+}
+
+// NewERPDriftActivities cria uma nova instância de ERPDriftActivities
+func NewERPDriftActivities(erpDriftUseCase *usecase.ERPDriftUseCase) *ERPDriftActivities {
+	return &ERPDriftActivities{
+		erpDriftUseCase: erpDriftUseCase,
+	}
+}
+
+// CheckERPDrift compara os boletos em aberto no ERP com os pendentes da base local e grava o relatório
+func (a *ERPDriftActivities) CheckERPDrift(ctx context.Context) (*model.ERPDriftReport, error) {
+	report, err := a.erpDriftUseCase.CheckDrift(ctx)
+	if err != nil {
+		return nil, activityError(err)
+	}
+
+	return report, nil
+}
+
+// ERPDriftWorkflow executa a verificação de divergências com o ERP da execução agendada
+func ERPDriftWorkflow(ctx workflow.Context) (*model.ERPDriftReport, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    time.Minute,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    15 * time.Minute,
+			MaximumAttempts:    5,
+		},
+	})
+
+	// A instância nula é usada apenas para referenciar os métodos registrados no worker
+	var activities *ERPDriftActivities
+
+	var report model.ERPDriftReport
+	if err := workflow.ExecuteActivity(ctx, activities.CheckERPDrift).Get(ctx, &report); err != nil {
+		return nil, err
+	}
+	workflow.GetLogger(ctx).Info("verificação de divergências com o ERP concluída",
+		"erp_open", report.ERPOpenCount, "local_open", report.LocalOpenCount, "drifts", len(report.Drifts))
+
+	return &report, nil
+}
+
+// scheduleERPDrift inicia a execução cron da verificação de divergências com o agendamento de
+// ERP_DRIFT_CRON. Com a execução já em andamento, o Temporal mantém a existente
+func scheduleERPDrift(c client.Client, taskQueue string) error {
+	_, err := c.ExecuteWorkflow(context.Background(), client.StartWorkflowOptions{
+		ID:           ERPDriftWorkflowID,
+		TaskQueue:    taskQueue,
+		CronSchedule: getEnv("ERP_DRIFT_CRON", DefaultERPDriftCron),
+	}, ERPDriftWorkflow)
+	if err != nil {
+		return fmt.Errorf("erro ao agendar verificação de divergências com o ERP: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// MaintenanceWorkflowID identifica a execução agendada da rotina de manutenção
+const MaintenanceWorkflowID = "manutencao-expurgos"
+
+// DefaultMaintenanceCron agenda a manutenção para todos os dias, às 03:00, após a revisão das pendências
+const DefaultMaintenanceCron = "0 3 * * *"
+
+// MaintenanceActivities agrupa as activities da rotina de manutenção
+type MaintenanceActivities struct {
+	maintenanceUseCase *usecase.MaintenanceUseCase
+}
+
+// NewMaintenanceActivities cria uma nova instância de MaintenanceActivities
+func NewMaintenanceActivities(maintenanceUseCase *usecase.MaintenanceUseCase) *MaintenanceActivities {
+	return &MaintenanceActivities{
+		maintenanceUseCase: maintenanceUseCase,
+	}
+}
+
+// RunMaintenance expurga os registros vencidos no instante informado
+func (a *MaintenanceActivities) RunMaintenance(ctx context.Context, now time.Time) (*model.MaintenanceReport, error) {
+	report, err := a.maintenanceUseCase.RunMaintenance(ctx, now)
+	if err != nil {
+		return nil, activityError(err)
+	}
+
+	return report, nil
+}
+
+// MaintenanceWorkflow executa a rotina de manutenção no instante da execução agendada
+func MaintenanceWorkflow(ctx workflow.Context) (*model.MaintenanceReport, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Hour,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    time.Minute,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    30 * time.Minute,
+			MaximumAttempts:    5,
+		},
+	})
+
+	now := workflow.Now(ctx)
+
+	// A instância nula é usada apenas para referenciar os métodos registrados no worker
+	var activities *MaintenanceActivities
+
+	var report model.MaintenanceReport
+	if err := workflow.ExecuteActivity(ctx, activities.RunMaintenance, now).Get(ctx, &report); err != nil {
+		return nil, err
+	}
+	workflow.GetLogger(ctx).Info("manutenção concluída",
+		"runs", report.Removed.Runs, "idempotency_keys", report.Removed.IdempotencyKeys,
+		"event_deliveries", report.Removed.EventDeliveries, "pending_snapshots", report.Removed.PendingSnapshots,
+		"expired_claims", report.Removed.ExpiredClaims, "processed_messages", report.Removed.ProcessedMessages,
+		"audit_records", report.Removed.AuditRecords, "anonymized_billets", report.Removed.AnonymizedBillets,
+		"anonymized_payments", report.Removed.AnonymizedPayments)
+
+	return &report, nil
+}
+
+// scheduleMaintenance inicia a execução cron da rotina de manutenção com o agendamento de
+// MAINTENANCE_CRON. Com a execução já em andamento, o Temporal mantém a existente
+func scheduleMaintenance(c client.Client, taskQueue string) error {
+	_, err := c.ExecuteWorkflow(context.Background(), client.StartWorkflowOptions{
+		ID:           MaintenanceWorkflowID,
+		TaskQueue:    taskQueue,
+		CronSchedule: getEnv("MAINTENANCE_CRON", DefaultMaintenanceCron),
+	}, MaintenanceWorkflow)
+	if err != nil {
+		return fmt.Errorf("erro ao agendar rotina de manutenção: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// PendingReviewWorkflowID identifica a execução agendada da revisão noturna das pendências
+const PendingReviewWorkflowID = "revisao-noturna-pendencias"
+
+// DefaultPendingReviewCron agenda a revisão das pendências para todos os dias, às 02:00
+const DefaultPendingReviewCron = "0 2 * * *"
+
+// PendingReviewActivities agrupa as activities da revisão noturna das pendências
+type PendingReviewActivities struct {
+	pendingReviewUseCase *usecase.PendingReviewUseCase
+}
+
+// NewPendingReviewActivities cria uma nova instância de PendingReviewActivities
+func NewPendingReviewActivities(pendingReviewUseCase *usecase.PendingReviewUseCase) *PendingReviewActivities {
+	return &PendingReviewActivities{
+		pendingReviewUseCase: pendingReviewUseCase,
+	}
+}
+
+// ReviewPending reexecuta o matching das pendências e grava o retrato do dia (AAAA-MM-DD)
+func (a *PendingReviewActivities) ReviewPending(ctx context.Context, date string) (*model.PendingSnapshot, error) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil, temporal.NewNonRetryableApplicationError(err.Error(), "ValidationError", err)
+	}
+
+	snapshot, err := a.pendingReviewUseCase.RunNightlyReview(ctx, day)
+	if err != nil {
+		return nil, activityError(err)
+	}
+
+	return snapshot, nil
+}
+
+// PendingReviewWorkflow executa a revisão das pendências do dia da execução agendada
+func PendingReviewWorkflow(ctx workflow.Context) (*model.PendingSnapshot, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Hour,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    time.Minute,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    30 * time.Minute,
+			MaximumAttempts:    5,
+		},
+	})
+
+	date := workflow.Now(ctx).Format("2006-01-02")
+
+	// A instância nula é usada apenas para referenciar os métodos registrados no worker
+	var activities *PendingReviewActivities
+
+	var snapshot model.PendingSnapshot
+	if err := workflow.ExecuteActivity(ctx, activities.ReviewPending, date).Get(ctx, &snapshot); err != nil {
+		return nil, err
+	}
+	workflow.GetLogger(ctx).Info("revisão noturna das pendências concluída",
+		"date", date, "exited", snapshot.ExitedCount, "entered", snapshot.EnteredCount, "pending", snapshot.PendingCount)
+
+	return &snapshot, nil
+}
+
+// schedulePendingReview inicia a execução cron da revisão noturna com o agendamento de
+// PENDING_REVIEW_CRON. Com a execução já em andamento, o Temporal mantém a existente
+func schedulePendingReview(c client.Client, taskQueue string) error {
+	_, err := c.ExecuteWorkflow(context.Background(), client.StartWorkflowOptions{
+		ID:           PendingReviewWorkflowID,
+		TaskQueue:    taskQueue,
+		CronSchedule: getEnv("PENDING_REVIEW_CRON", DefaultPendingReviewCron),
+	}, PendingReviewWorkflow)
+	if err != nil {
+		return fmt.Errorf("erro ao agendar revisão noturna das pendências: %w", err)
+	}
+
+	return nil
+}
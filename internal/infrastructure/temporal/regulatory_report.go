@@ -0,0 +1,111 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/infrastructure/report"
+)
+
+// RegulatoryReportWorkflowID identifica a execução agendada do relatório regulatório mensal
+const RegulatoryReportWorkflowID = "relatorio-regulatorio-mensal"
+
+// DefaultRegulatoryReportCron agenda o relatório para o primeiro dia de cada mês, às 06:00
+const DefaultRegulatoryReportCron = "0 6 1 * *"
+
+// ReportActivities agrupa as activities de geração dos relatórios de conciliação
+type ReportActivities struct {
+	reconciliationUseCase *usecase.ReconciliationUseCase
+	template              report.Template
+	directory             string
+}
+
+// NewReportActivities cria uma nova instância de ReportActivities que grava os relatórios no diretório informado
+func NewReportActivities(reconciliationUseCase *usecase.ReconciliationUseCase, template report.Template, directory string) *ReportActivities {
+	return &ReportActivities{
+		reconciliationUseCase: reconciliationUseCase,
+		template:              template,
+		directory:             directory,
+	}
+}
+
+// GenerateRegulatoryReport gera o relatório regulatório do mês (AAAA-MM) e retorna o caminho do arquivo gravado
+func (a *ReportActivities) GenerateRegulatoryReport(ctx context.Context, month string) (string, error) {
+	statistics, err := a.reconciliationUseCase.GetDailyStatistics(ctx, map[string]string{"month": month})
+	if err != nil {
+		return "", activityError(err)
+	}
+
+	path := filepath.Join(a.directory, fmt.Sprintf("relatorio_regulatorio_%s.csv", month))
+
+	// Grava em arquivo temporário e renomeia, para que o arquivo final nunca fique incompleto
+	file, err := os.CreateTemp(a.directory, ".relatorio_regulatorio_*.csv")
+	if err != nil {
+		return "", fmt.Errorf("erro ao criar arquivo do relatório: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := report.WriteCSV(file, a.template, statistics); err != nil {
+		file.Close()
+		return "", fmt.Errorf("erro ao escrever relatório: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("erro ao gravar relatório: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		return "", fmt.Errorf("erro ao gravar relatório: %w", err)
+	}
+
+	return path, nil
+}
+
+// RegulatoryReportWorkflow gera o relatório regulatório do mês anterior ao da execução agendada
+func RegulatoryReportWorkflow(ctx workflow.Context) (string, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    time.Minute,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    time.Hour,
+			MaximumAttempts:    10,
+		},
+	})
+
+	now := workflow.Now(ctx)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0).Format("2006-01")
+
+	// A instância nula é usada apenas para referenciar os métodos registrados no worker
+	var activities *ReportActivities
+
+	var path string
+	if err := workflow.ExecuteActivity(ctx, activities.GenerateRegulatoryReport, month).Get(ctx, &path); err != nil {
+		return "", err
+	}
+	workflow.GetLogger(ctx).Info("relatório regulatório gerado", "month", month, "path", path)
+
+	return path, nil
+}
+
+// scheduleRegulatoryReport inicia a execução cron do relatório regulatório com o agendamento de
+// REGULATORY_REPORT_CRON. Com a execução já em andamento, o Temporal mantém a existente
+func scheduleRegulatoryReport(c client.Client, taskQueue string) error {
+	_, err := c.ExecuteWorkflow(context.Background(), client.StartWorkflowOptions{
+		ID:           RegulatoryReportWorkflowID,
+		TaskQueue:    taskQueue,
+		CronSchedule: getEnv("REGULATORY_REPORT_CRON", DefaultRegulatoryReportCron),
+	}, RegulatoryReportWorkflow)
+	if err != nil {
+		return fmt.Errorf("erro ao agendar relatório regulatório: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package temporal
+
+import (
+	"fmt"
+	"os"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/worker"
+)
+
+// RunWorker conecta ao Temporal e processa workflows de conciliação até receber um sinal de interrupção.
+// O endereço e o namespace são lidos de TEMPORAL_HOST_PORT e TEMPORAL_NAMESPACE. Com reportActivities,
+// o worker também agenda e gera o relatório regulatório mensal; com pendingActivities, agenda a revisão
+// noturna das pendências; com maintenanceActivities, agenda a rotina de manutenção; com erpDriftActivities,
+// agenda a verificação de divergências entre os boletos em aberto do ERP e a base local; com
+// erpSyncActivities, agenda a importação delta dos boletos criados ou alterados no ERP
+func RunWorker(
+	activities *Activities,
+	reportActivities *ReportActivities,
+	pendingActivities *PendingReviewActivities,
+	maintenanceActivities *MaintenanceActivities,
+	erpDriftActivities *ERPDriftActivities,
+	erpSyncActivities *ERPSyncActivities,
+) error {
+	c, err := client.Dial(client.Options{
+		HostPort:  getEnv("TEMPORAL_HOST_PORT", client.DefaultHostPort),
+		Namespace: getEnv("TEMPORAL_NAMESPACE", client.DefaultNamespace),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao conectar no Temporal: %w", err)
+	}
+	defer c.Close()
+
+	taskQueue := getEnv("TEMPORAL_TASK_QUEUE", TaskQueue)
+
+	w := worker.New(c, taskQueue, worker.Options{})
+	w.RegisterWorkflow(ReconciliationWorkflow)
+	w.RegisterActivity(activities)
+
+	if reportActivities != nil {
+		w.RegisterWorkflow(RegulatoryReportWorkflow)
+		w.RegisterActivity(reportActivities)
+
+		if err := scheduleRegulatoryReport(c, taskQueue); err != nil {
+			return err
+		}
+	}
+
+	if pendingActivities != nil {
+		w.RegisterWorkflow(PendingReviewWorkflow)
+		w.RegisterActivity(pendingActivities)
+
+		if err := schedulePendingReview(c, taskQueue); err != nil {
+			return err
+		}
+	}
+
+	if maintenanceActivities != nil {
+		w.RegisterWorkflow(MaintenanceWorkflow)
+		w.RegisterActivity(maintenanceActivities)
+
+		if err := scheduleMaintenance(c, taskQueue); err != nil {
+			return err
+		}
+	}
+
+	if erpDriftActivities != nil {
+		w.RegisterWorkflow(ERPDriftWorkflow)
+		w.RegisterActivity(erpDriftActivities)
+
+		if err := scheduleERPDrift(c, taskQueue); err != nil {
+			return err
+		}
+	}
+
+	if erpSyncActivities != nil {
+		w.RegisterWorkflow(ERPSyncWorkflow)
+		w.RegisterActivity(erpSyncActivities)
+
+		if err := scheduleERPSync(c, taskQueue); err != nil {
+			return err
+		}
+	}
+
+	return w.Run(worker.InterruptCh())
+}
+
+// getEnv obtém uma variável de ambiente ou retorna o valor padrão
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,125 @@
+package temporal
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
+)
+
+// TaskQueue define a fila padrão usada pelo worker de conciliação
+const TaskQueue = "conciliacao-bancaria"
+
+// WorkflowInput representa os parâmetros de uma execução do workflow de conciliação
+type WorkflowInput struct {
+	Source      string                       `json:"source,omitempty"`
+	Destination string                       `json:"destination,omitempty"`
+	Params      usecase.ReconciliationParams `json:"params"`
+
+	// Partition define o particionamento de janelas grandes em sub-janelas mensais; sem ela, é usada
+	// model.DefaultPartitionPolicy
+	Partition *model.PartitionPolicy `json:"partition,omitempty"`
+}
+
+// WorkflowOutput resume o resultado de uma execução do workflow
+type WorkflowOutput struct {
+	ImportedBillets      int `json:"imported_billets"`
+	ImportedPayments     int `json:"imported_payments"`
+	ReconciledBillets    int `json:"reconciled_billets"`
+	NonReconciledBillets int `json:"non_reconciled_billets"`
+	AmbiguousReferences  int `json:"ambiguous_references"`
+	Partitions           int `json:"partitions"`
+}
+
+// ReconciliationWorkflow orquestra o fluxo importar→conciliar→exportar como workflow durável. Janelas
+// de datas grandes são conciliadas por sub-janela mensal, cada uma em uma activity própria
+func ReconciliationWorkflow(ctx workflow.Context, input WorkflowInput) (*WorkflowOutput, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    5 * time.Minute,
+			MaximumAttempts:    10,
+		},
+	})
+
+	logger := workflow.GetLogger(ctx)
+
+	// A instância nula é usada apenas para referenciar os métodos registrados no worker
+	var activities *Activities
+
+	var imported ImportSummary
+	if err := workflow.ExecuteActivity(ctx, activities.Import, input.Source).Get(ctx, &imported); err != nil {
+		return nil, err
+	}
+	logger.Info("importação concluída", "billets", imported.Billets, "payments", imported.Payments)
+
+	policy := model.DefaultPartitionPolicy
+	if input.Partition != nil {
+		policy = *input.Partition
+	}
+
+	windows := policy.Split(input.Params.StartDate, input.Params.EndDate)
+	results, err := reconcileWindows(ctx, input.Params, windows, policy.Parallelism())
+	if err != nil {
+		return nil, err
+	}
+
+	var result model.ReconciliationResult
+	for _, partial := range results {
+		result.Merge(partial)
+	}
+	logger.Info("conciliação concluída", "conciliados", len(result.ReconciledBillets), "particoes", len(windows))
+
+	if err := workflow.ExecuteActivity(ctx, activities.Export, input.Destination, &result).Get(ctx, nil); err != nil {
+		return nil, err
+	}
+
+	return &WorkflowOutput{
+		ImportedBillets:      imported.Billets,
+		ImportedPayments:     imported.Payments,
+		ReconciledBillets:    len(result.ReconciledBillets),
+		NonReconciledBillets: len(result.NonReconciledBillets),
+		AmbiguousReferences:  len(result.AmbiguousReferences),
+		Partitions:           len(windows),
+	}, nil
+}
+
+// reconcileWindows concilia cada sub-janela em uma activity, com no máximo concurrency activities em
+// andamento. Cada sub-janela é uma execução idempotente: após uma falha, o workflow repetido reaproveita
+// o resultado das sub-janelas já concluídas
+func reconcileWindows(ctx workflow.Context, params usecase.ReconciliationParams, windows []model.DateWindow, concurrency int) ([]*model.ReconciliationResult, error) {
+	var activities *Activities
+
+	results := make([]*model.ReconciliationResult, len(windows))
+	futures := make([]workflow.Future, len(windows))
+	for i, window := range windows {
+		// Atingido o limite, aguarda a sub-janela mais antiga em andamento antes de iniciar a próxima
+		if i >= concurrency {
+			if err := futures[i-concurrency].Get(ctx, &results[i-concurrency]); err != nil {
+				return nil, err
+			}
+		}
+
+		windowParams := params
+		windowParams.StartDate = window.Start
+		windowParams.EndDate = window.End
+		futures[i] = workflow.ExecuteActivity(ctx, activities.Reconcile, windowParams)
+	}
+
+	pending := len(windows) - concurrency
+	if pending < 0 {
+		pending = 0
+	}
+	for i := pending; i < len(windows); i++ {
+		if err := futures[i].Get(ctx, &results[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
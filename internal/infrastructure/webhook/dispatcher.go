@@ -0,0 +1,220 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/domain/service"
+)
+
+// SignatureHeader define o header com a assinatura HMAC-SHA256 do corpo da entrega
+const SignatureHeader = "X-Signature"
+
+// maxDeliveryAttempts define quantas vezes uma entrega é tentada antes de ficar com falha no outbox
+const maxDeliveryAttempts = 3
+
+// deliveryTimeout define o tempo máximo de cada tentativa de entrega
+const deliveryTimeout = 10 * time.Second
+
+// retryBackoff define a espera antes da segunda tentativa, dobrada a cada nova falha
+const retryBackoff = 10 * time.Second
+
+// pollInterval define o intervalo entre as leituras de entregas pendentes no outbox
+const pollInterval = 5 * time.Second
+
+// claimBatchSize limita quantas entregas são reservadas a cada leitura do outbox
+const claimBatchSize = 100
+
+// claimLease define por quanto tempo uma entrega reservada fica indisponível para outras instâncias
+const claimLease = 2 * deliveryTimeout
+
+// Garantir que Dispatcher implementa a interface EventPublisher
+var _ service.EventPublisher = (*Dispatcher)(nil)
+
+// Delivery representa o corpo enviado a um assinante
+type Delivery struct {
+	DeliveryID     string         `json:"delivery_id"`
+	SubscriptionID string         `json:"subscription_id"`
+	Events         []*model.Event `json:"events"`
+}
+
+// Dispatcher entrega eventos via webhook aos assinantes cujos filtros os aceitam. As entregas são
+// gravadas no outbox e enviadas por Run, de forma que falhas possam ser reprocessadas
+type Dispatcher struct {
+	subscriptionRepository repository.SubscriptionRepository
+	deliveryRepository     repository.EventDeliveryRepository
+	client                 *http.Client
+}
+
+// NewDispatcher cria uma nova instância de Dispatcher
+func NewDispatcher(subscriptionRepo repository.SubscriptionRepository, deliveryRepo repository.EventDeliveryRepository) *Dispatcher {
+	return &Dispatcher{
+		subscriptionRepository: subscriptionRepo,
+		deliveryRepository:     deliveryRepo,
+		client:                 &http.Client{Timeout: deliveryTimeout},
+	}
+}
+
+// Publish seleciona os eventos de cada assinatura ativa e grava as entregas no outbox,
+// sem bloquear a requisição que os originou com o envio
+func (d *Dispatcher) Publish(ctx context.Context, events []*model.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	subscriptions, err := d.subscriptionRepository.FindActive(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar assinaturas ativas: %w", err)
+	}
+
+	var deliveries []*model.EventDelivery
+	for _, subscription := range subscriptions {
+		matched := filterEvents(subscription, events)
+		if len(matched) == 0 {
+			continue
+		}
+
+		deliveries = append(deliveries, model.NewEventDelivery(subscription.ID, matched))
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := d.deliveryRepository.CreateMany(ctx, deliveries); err != nil {
+		return fmt.Errorf("erro ao gravar entregas no outbox: %w", err)
+	}
+
+	return nil
+}
+
+// Run envia as entregas pendentes do outbox até o contexto ser cancelado
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverDue reserva as entregas pendentes vencidas e tenta enviá-las
+func (d *Dispatcher) deliverDue(ctx context.Context) {
+	deliveries, err := d.deliveryRepository.ClaimDue(ctx, time.Now(), claimLease, claimBatchSize)
+	if err != nil {
+		log.Printf("erro ao ler entregas pendentes do outbox: %v", err)
+		return
+	}
+
+	for _, delivery := range deliveries {
+		d.deliver(ctx, delivery)
+	}
+}
+
+// deliver realiza uma tentativa de entrega e registra o resultado no outbox. Após a última
+// tentativa a entrega fica com falha, aguardando reprocessamento ou descarte pela operação
+func (d *Dispatcher) deliver(ctx context.Context, delivery *model.EventDelivery) {
+	attempts := delivery.Attempts + 1
+
+	err := d.send(ctx, delivery)
+	if err == nil {
+		if err := d.deliveryRepository.MarkDelivered(ctx, delivery.ID, attempts, time.Now()); err != nil {
+			log.Printf("erro ao registrar entrega %s: %v", delivery.ID, err)
+		}
+		return
+	}
+
+	log.Printf("falha na entrega %s para a assinatura %s (tentativa %d/%d): %v",
+		delivery.ID, delivery.SubscriptionID, attempts, maxDeliveryAttempts, err)
+
+	status := model.DeliveryPending
+	if attempts >= maxDeliveryAttempts {
+		status = model.DeliveryFailed
+	}
+	nextAttemptAt := time.Now().Add(retryBackoff << (attempts - 1))
+
+	if err := d.deliveryRepository.MarkAttemptFailed(ctx, delivery.ID, attempts, err.Error(), status, nextAttemptAt); err != nil {
+		log.Printf("erro ao registrar falha da entrega %s: %v", delivery.ID, err)
+	}
+}
+
+// send serializa a entrega e a envia ao endereço da assinatura
+func (d *Dispatcher) send(ctx context.Context, delivery *model.EventDelivery) error {
+	subscription, err := d.subscriptionRepository.GetByID(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar assinatura: %w", err)
+	}
+
+	body, err := json.Marshal(Delivery{
+		DeliveryID:     delivery.ID,
+		SubscriptionID: subscription.ID,
+		Events:         delivery.Events,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao serializar eventos: %w", err)
+	}
+
+	return d.post(subscription, body)
+}
+
+// post realiza uma tentativa de entrega, assinando o corpo quando a assinatura tem segredo
+func (d *Dispatcher) post(subscription *model.Subscription, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if subscription.Secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(subscription.Secret, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("assinante respondeu com status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Sign calcula a assinatura HMAC-SHA256 (hexadecimal) do corpo com o segredo da assinatura
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// filterEvents retorna os eventos aceitos pelos filtros da assinatura
+func filterEvents(subscription *model.Subscription, events []*model.Event) []*model.Event {
+	var matched []*model.Event
+	for _, event := range events {
+		if subscription.Matches(event) {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,357 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"conciliacao-bancaria/internal/domain/model"
+	pkgErrors "conciliacao-bancaria/pkg/errors"
+)
+
+// TestBilletRepository verifica todos os métodos do repositório de boletos
+func TestBilletRepository(t *testing.T) {
+	runChecks(t, []Check{
+		{Name: "CreateAndGetByID", Run: checkBilletCreateAndGet},
+		{Name: "CreateManyRollsBackOnDuplicate", Run: checkBilletCreateManyRollback},
+		{Name: "Filters", Run: checkBilletFilters},
+		{Name: "UpdateAndDelete", Run: checkBilletUpdateAndDelete},
+		{Name: "MissingRecord", Run: checkBilletMissing},
+		{Name: "FindNonReconciled", Run: checkBilletFindNonReconciled},
+		{Name: "ContractStatistics", Run: checkBilletContractStatistics},
+		{Name: "AccountPerformances", Run: checkBilletAccountPerformances},
+		{Name: "OpenAmount", Run: checkBilletOpenAmount},
+		{Name: "Barcode", Run: checkBilletBarcode},
+		{Name: "PayerDocument", Run: checkBilletPayerDocument},
+		{Name: "LateCharges", Run: checkBilletLateCharges},
+	})
+}
+
+// newContractBillet cria um boleto de contrato para os cenários
+func newContractBillet(id, bankAccount string, amount float64, contractID string) *model.Billet {
+	billet := model.NewBillet(id, bankAccount, amount, day(1), stringPtr("REF-"+id))
+	billet.ContractID = stringPtr(contractID)
+	billet.CustomerID = stringPtr("cliente-1")
+	return billet
+}
+
+func checkBilletCreateAndGet(ctx context.Context, env *Env) error {
+	billet := newContractBillet("b1", "conta-1", 150.75, "contrato-1")
+	installment := 2
+	billet.InstallmentNumber = &installment
+
+	if _, err := env.Billets.Create(ctx, billet); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+
+	stored, err := env.Billets.GetByID(ctx, "b1")
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+
+	return expect(stored.BankAccount == "conta-1" && stored.Amount == 150.75 &&
+		stored.IssuanceDate.Equal(day(1)) && stored.ReferenceID != nil && *stored.ReferenceID == "REF-b1" &&
+		stored.InstallmentNumber != nil && *stored.InstallmentNumber == 2 &&
+		stored.ContractID != nil && *stored.ContractID == "contrato-1",
+		"GetByID: boleto lido difere do gravado: %+v", stored)
+}
+
+func checkBilletCreateManyRollback(ctx context.Context, env *Env) error {
+	batch := []*model.Billet{
+		newContractBillet("b1", "conta-1", 10, "contrato-1"),
+		newContractBillet("b2", "conta-1", 20, "contrato-1"),
+	}
+	if err := env.Billets.CreateMany(ctx, batch); err != nil {
+		return fmt.Errorf("CreateMany: %w", err)
+	}
+
+	// O segundo lote repete b2: nenhum boleto do lote deve ser gravado
+	duplicated := []*model.Billet{
+		newContractBillet("b3", "conta-1", 30, "contrato-1"),
+		newContractBillet("b2", "conta-1", 20, "contrato-1"),
+	}
+	if err := env.Billets.CreateMany(ctx, duplicated); err == nil {
+		return fmt.Errorf("CreateMany: lote com ID duplicado deveria falhar")
+	}
+
+	billets, err := env.Billets.GetAll(ctx)
+	return expectCount("GetAll após rollback", len(billets), 2, err)
+}
+
+func checkBilletFilters(ctx context.Context, env *Env) error {
+	batch := []*model.Billet{
+		newContractBillet("b1", "conta-1", 10, "contrato-1"),
+		newContractBillet("b2", "conta-1", 20, "contrato-2"),
+		newContractBillet("b3", "conta-2", 30, "contrato-2"),
+	}
+	if err := env.Billets.CreateMany(ctx, batch); err != nil {
+		return fmt.Errorf("CreateMany: %w", err)
+	}
+
+	byAccount, err := env.Billets.GetByBankAccount(ctx, "conta-1")
+	if err := expectCount("GetByBankAccount", len(byAccount), 2, err); err != nil {
+		return err
+	}
+
+	byReference, err := env.Billets.GetByReferenceID(ctx, "REF-b3")
+	if err := expectCount("GetByReferenceID", len(byReference), 1, err); err != nil {
+		return err
+	}
+
+	byContract, err := env.Billets.GetByContractID(ctx, "contrato-2")
+	if err := expectCount("GetByContractID", len(byContract), 2, err); err != nil {
+		return err
+	}
+
+	none, err := env.Billets.GetByBankAccount(ctx, "conta-inexistente")
+	if err := expectCount("GetByBankAccount sem resultados", len(none), 0, err); err != nil {
+		return err
+	}
+
+	maxAmount := 25.0
+	listed, err := env.Billets.List(ctx, model.BilletFilter{ContractID: "contrato-2", MaxAmount: &maxAmount})
+	if err := expectCount("List por contrato e valor máximo", len(listed), 1, err); err != nil {
+		return err
+	}
+
+	return expect(listed[0].ID == "b2", "List: esperado o boleto b2, obtido %+v", listed[0])
+}
+
+func checkBilletUpdateAndDelete(ctx context.Context, env *Env) error {
+	billet := newContractBillet("b1", "conta-1", 10, "contrato-1")
+	if _, err := env.Billets.Create(ctx, billet); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+
+	billet.Amount = 99.9
+	billet.ReferenceID = nil
+	if _, err := env.Billets.Update(ctx, billet); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	stored, err := env.Billets.GetByID(ctx, "b1")
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+	if err := expect(stored.Amount == 99.9 && stored.ReferenceID == nil, "Update: alteração não persistida: %+v", stored); err != nil {
+		return err
+	}
+
+	if err := env.Billets.Delete(ctx, "b1"); err != nil {
+		return fmt.Errorf("Delete: %w", err)
+	}
+
+	billets, err := env.Billets.GetAll(ctx)
+	return expectCount("GetAll após Delete", len(billets), 0, err)
+}
+
+func checkBilletMissing(ctx context.Context, env *Env) error {
+	if _, err := env.Billets.GetByID(ctx, "inexistente"); !pkgErrors.IsNotFoundError(err) {
+		return fmt.Errorf("GetByID: esperado erro de não encontrado, obtido %v", err)
+	}
+
+	if _, err := env.Billets.Update(ctx, newContractBillet("inexistente", "conta-1", 1, "contrato-1")); !pkgErrors.IsNotFoundError(err) {
+		return fmt.Errorf("Update: esperado erro de não encontrado, obtido %v", err)
+	}
+
+	if err := env.Billets.Delete(ctx, "inexistente"); !pkgErrors.IsNotFoundError(err) {
+		return fmt.Errorf("Delete: esperado erro de não encontrado, obtido %v", err)
+	}
+
+	if _, err := env.Billets.Create(ctx, newContractBillet("b1", "conta-1", 1, "contrato-1")); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	if _, err := env.Billets.Create(ctx, newContractBillet("b1", "conta-1", 1, "contrato-1")); !pkgErrors.IsConflictError(err) {
+		return fmt.Errorf("Create: esperado erro de conflito para ID duplicado, obtido %v", err)
+	}
+
+	return nil
+}
+
+func checkBilletFindNonReconciled(ctx context.Context, env *Env) error {
+	if err := env.Billets.CreateMany(ctx, []*model.Billet{
+		newContractBillet("b1", "conta-1", 10, "contrato-1"),
+		newContractBillet("b2", "conta-1", 20, "contrato-1"),
+		newContractBillet("b3", "conta-2", 30, "contrato-2"),
+	}); err != nil {
+		return fmt.Errorf("CreateMany: %w", err)
+	}
+	if _, err := env.Payments.Create(ctx, model.NewPayment("p1", "conta-1", 10, day(2), stringPtr("REF-b1"))); err != nil {
+		return fmt.Errorf("Payments.Create: %w", err)
+	}
+	if err := insertReconciliation(ctx, env.DB, "b1", "p1", "conta-1", string(model.StatusSuccessful)); err != nil {
+		return err
+	}
+
+	billets, err := env.Billets.FindNonReconciled(ctx)
+	if err := expectCount("FindNonReconciled", len(billets), 2, err); err != nil {
+		return err
+	}
+
+	// O filtro limita o período e as contas na própria consulta
+	billets, err = env.Billets.FindNonReconciledByFilter(ctx, model.PendingFilter{
+		StartDate:    day(1),
+		EndDate:      day(31),
+		BankAccounts: []string{"conta-1"},
+	})
+	if err := expectCount("FindNonReconciledByFilter", len(billets), 1, err); err != nil {
+		return err
+	}
+	return expect(billets[0].ID == "b2", "FindNonReconciledByFilter: esperado b2, obtido %s", billets[0].ID)
+}
+
+func checkBilletContractStatistics(ctx context.Context, env *Env) error {
+	if err := env.Billets.CreateMany(ctx, []*model.Billet{
+		newContractBillet("b1", "conta-1", 100, "contrato-1"),
+		newContractBillet("b2", "conta-1", 50, "contrato-1"),
+	}); err != nil {
+		return fmt.Errorf("CreateMany: %w", err)
+	}
+	if _, err := env.Payments.Create(ctx, model.NewPayment("p1", "conta-1", 98, day(2), stringPtr("REF-b1"))); err != nil {
+		return fmt.Errorf("Payments.Create: %w", err)
+	}
+	if err := insertReconciliation(ctx, env.DB, "b1", "p1", "conta-1", string(model.StatusDifferentValue)); err != nil {
+		return err
+	}
+
+	stats, err := env.Billets.GetContractStatistics(ctx, "contrato-1")
+	if err != nil {
+		return fmt.Errorf("GetContractStatistics: %w", err)
+	}
+
+	return expect(stats.TotalBillets == 2 && stats.ReconciledBillets == 1 && stats.OpenAmount == 50 && stats.PaidAmount == 98,
+		"GetContractStatistics: estatísticas inesperadas: %+v", stats)
+}
+
+func checkBilletAccountPerformances(ctx context.Context, env *Env) error {
+	if err := env.Billets.CreateMany(ctx, []*model.Billet{
+		newContractBillet("b1", "conta-1", 100, "contrato-1"),
+		newContractBillet("b2", "conta-1", 50, "contrato-1"),
+		newContractBillet("b3", "conta-2", 30, "contrato-2"),
+	}); err != nil {
+		return fmt.Errorf("CreateMany: %w", err)
+	}
+	if _, err := env.Payments.Create(ctx, model.NewPayment("p1", "conta-1", 100, day(2), stringPtr("REF-b1"))); err != nil {
+		return fmt.Errorf("Payments.Create: %w", err)
+	}
+	if err := insertReconciliation(ctx, env.DB, "b1", "p1", "conta-1", string(model.StatusSuccessful)); err != nil {
+		return err
+	}
+
+	// Os boletos são emitidos em day(1): na data de referência, as pendências têm 10 dias
+	performances, err := env.Billets.GetAccountPerformances(ctx, model.AccountRankingFilter{AsOf: day(11)})
+	if err := expectCount("GetAccountPerformances", len(performances), 2, err); err != nil {
+		return err
+	}
+
+	first, second := performances[0], performances[1]
+	if err := expect(first.BankAccount == "conta-1" && first.TotalBillets == 2 && first.ReconciledBillets == 1 &&
+		first.PendingBillets == 1 && first.PendingAmount == 50 && first.AvgPendingAgeDays == 10,
+		"GetAccountPerformances: indicadores de conta-1 inesperados: %+v", first); err != nil {
+		return err
+	}
+	return expect(second.BankAccount == "conta-2" && second.PendingAmount == 30 && second.MaxPendingAgeDays == 10,
+		"GetAccountPerformances: indicadores de conta-2 inesperados: %+v", second)
+}
+
+func checkBilletOpenAmount(ctx context.Context, env *Env) error {
+	billet := model.NewBillet("b1", "conta-1", 0, day(1), stringPtr("DEP-1"))
+	billet.OpenAmount = true
+	if _, err := env.Billets.Create(ctx, billet); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+
+	// O valor pago passa a ser o valor do título, sem perder a marcação de valor aberto
+	billet.Amount = 321.45
+	if _, err := env.Billets.Update(ctx, billet); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	stored, err := env.Billets.GetByID(ctx, "b1")
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+
+	return expect(stored.OpenAmount && stored.Amount == 321.45,
+		"GetByID: boleto de valor aberto lido difere do gravado: %+v", stored)
+}
+
+func checkBilletBarcode(ctx context.Context, env *Env) error {
+	billet := model.NewBillet("b1", "conta-1", 150, day(1), stringPtr("REF-b1"))
+	billet.DigitableLine = "34191090080001234567489012345677297010000015000"
+	if _, err := env.Billets.Create(ctx, billet); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+
+	payment := model.NewPayment("p1", "conta-1", 150, day(3), stringPtr("RET-p1"))
+	payment.BarCode = "34192970100000150001090000012345678901234567"
+	if _, err := env.Payments.Create(ctx, payment); err != nil {
+		return fmt.Errorf("Create pagamento: %w", err)
+	}
+
+	storedBillet, err := env.Billets.GetByID(ctx, "b1")
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+	storedPayment, err := env.Payments.GetByID(ctx, "p1")
+	if err != nil {
+		return fmt.Errorf("GetByID pagamento: %w", err)
+	}
+
+	// A linha digitável do boleto e o código de barras do retorno identificam o mesmo título
+	return expect(storedBillet.DigitableLine == billet.DigitableLine && storedBillet.Barcode() == storedPayment.Barcode() &&
+		storedPayment.Barcode() == payment.BarCode,
+		"GetByID: código de barras lido difere do gravado: %+v / %+v", storedBillet, storedPayment)
+}
+
+func checkBilletPayerDocument(ctx context.Context, env *Env) error {
+	billet := model.NewBillet("b1", "conta-1", 80, day(1), nil)
+	billet.PayerDocument = "11222333000181"
+	if _, err := env.Billets.Create(ctx, billet); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+
+	payment := model.NewPayment("p1", "conta-1", 80, day(3), nil)
+	payment.PayerDocument = "11222333000181"
+	if _, err := env.Payments.Create(ctx, payment); err != nil {
+		return fmt.Errorf("Create pagamento: %w", err)
+	}
+
+	storedBillet, err := env.Billets.GetByID(ctx, "b1")
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+	storedPayment, err := env.Payments.GetByID(ctx, "p1")
+	if err != nil {
+		return fmt.Errorf("GetByID pagamento: %w", err)
+	}
+
+	return expect(storedBillet.PayerDocument == billet.PayerDocument && storedPayment.PayerDocument == payment.PayerDocument,
+		"GetByID: documento do pagador lido difere do gravado: %+v / %+v", storedBillet, storedPayment)
+}
+
+func checkBilletLateCharges(ctx context.Context, env *Env) error {
+	dueDate := day(10)
+	billet := model.NewBillet("b1", "conta-1", 1000, day(1), nil)
+	billet.DueDate = &dueDate
+	billet.InterestRate = 1
+	billet.FinePercent = 2
+	if _, err := env.Billets.Create(ctx, billet); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+
+	billet.FinePercent = 2.5
+	if _, err := env.Billets.Update(ctx, billet); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	stored, err := env.Billets.GetByID(ctx, "b1")
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+
+	return expect(stored.DueDate != nil && stored.DueDate.Equal(dueDate) && stored.InterestRate == 1 && stored.FinePercent == 2.5,
+		"GetByID: vencimento e encargos lidos diferem dos gravados: %+v", stored)
+}
@@ -0,0 +1,136 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/service"
+	"conciliacao-bancaria/internal/infrastructure/database/repository"
+	pkgErrors "conciliacao-bancaria/pkg/errors"
+)
+
+// TestReconciliationFaults verifica o caso de uso de conciliação sob falhas parciais injetadas nos repositórios
+func TestReconciliationFaults(t *testing.T) {
+	runChecks(t, []Check{
+		{Name: "ReconciliationRollsBackOnPartialFailure", Run: checkFaultReconciliationRollback},
+		{Name: "ReadErrorAbortsReconciliation", Run: checkFaultReadError},
+		{Name: "LatencyRespectsDeadline", Run: checkFaultLatency},
+	})
+}
+
+// newFaultyUseCase cria o caso de uso de conciliação sobre os repositórios com as falhas informadas
+func newFaultyUseCase(env *Env, rules map[string]repository.FaultRule) *usecase.ReconciliationUseCase {
+	return newReconciliationUseCase(env, rules, nil)
+}
+
+// newReconciliationUseCase cria o caso de uso de conciliação com as falhas informadas e o motor shadow,
+// que fica desabilitado quando nil
+func newReconciliationUseCase(env *Env, rules map[string]repository.FaultRule, shadowEngine service.ReconciliationService) *usecase.ReconciliationUseCase {
+	faults := repository.NewFaultInjector(rules)
+	return usecase.NewReconciliationUseCase(
+		repository.NewFaultyBilletRepository(env.Billets, faults),
+		repository.NewFaultyPaymentRepository(env.Payments, faults),
+		repository.NewFaultyReconciliationRepository(env.Reconciliations, faults),
+		repository.NewBilletClaimRepository(env.Shards),
+		repository.NewDailyClosingRepository(env.Shards),
+		repository.NewReconciliationRunRepository(env.Shards),
+		repository.NewTimelineRepository(env.Shards),
+		repository.NewBankAccountRepository(env.Shards),
+		repository.NewUnappliedCreditRepository(env.Shards),
+		repository.NewRankerRepository(env.Shards),
+		repository.NewRuleSetRepository(env.Shards),
+		service.NewReconciliationService(),
+		nil,
+		nil,
+		nil,
+		usecase.NewShadowUseCase(repository.NewShadowReconciliationRepository(env.Shards), shadowEngine, "", nil),
+	)
+}
+
+func checkFaultReconciliationRollback(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	// O lote de conciliações falha dentro da transação: nada pode ser persistido
+	faulty := newFaultyUseCase(env, map[string]repository.FaultRule{
+		"reconciliations.CreateMany": {Rollback: true},
+	})
+	if _, err := faulty.RunReconciliation(ctx, usecase.ReconciliationParams{}); !pkgErrors.IsDatabaseError(err) {
+		return fmt.Errorf("RunReconciliation: esperado erro de banco, obtido %v", err)
+	}
+
+	reconciliations, err := env.Reconciliations.GetAll(ctx)
+	if err := expectCount("GetAll após rollback", len(reconciliations), 0, err); err != nil {
+		return err
+	}
+
+	billets, err := env.Billets.FindNonReconciled(ctx)
+	if err := expectCount("FindNonReconciled após rollback", len(billets), 2, err); err != nil {
+		return err
+	}
+
+	// A execução com falha é mantida e libera os parâmetros para a nova tentativa
+	runs, err := repository.NewReconciliationRunRepository(env.Shards).List(ctx, model.ReconciliationRunFilter{
+		Tenants: []string{""},
+		Status:  model.RunFailed,
+	})
+	if err := expectCount("execuções com falha", len(runs), 1, err); err != nil {
+		return err
+	}
+	if runs[0].ParamsHash != "" {
+		return fmt.Errorf("execução com falha manteve o hash de parâmetros %s", runs[0].ParamsHash)
+	}
+
+	// Sem falhas, a nova execução concilia os mesmos boletos
+	result, err := newFaultyUseCase(env, nil).RunReconciliation(ctx, usecase.ReconciliationParams{})
+	if err != nil {
+		return fmt.Errorf("RunReconciliation sem falhas: %w", err)
+	}
+
+	// As pendências da execução também são gravadas, com status nao_conciliado
+	reconciliations, err = env.Reconciliations.GetAll(ctx)
+	return expectCount("GetAll após nova execução", len(reconciliations),
+		len(result.ReconciledBillets)+len(result.NonReconciledBillets), err)
+}
+
+func checkFaultReadError(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	faulty := newFaultyUseCase(env, map[string]repository.FaultRule{
+		"payments.StreamNonReconciled": {ErrorRate: 1},
+	})
+	_, err := faulty.RunReconciliation(ctx, usecase.ReconciliationParams{})
+	if !pkgErrors.IsDatabaseError(err) || !errors.Is(err, repository.ErrInjectedFault) {
+		return fmt.Errorf("RunReconciliation: esperado erro de banco com a falha injetada, obtido %v", err)
+	}
+
+	reconciliations, err := env.Reconciliations.GetAll(ctx)
+	return expectCount("GetAll após falha de leitura", len(reconciliations), 0, err)
+}
+
+func checkFaultLatency(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	faulty := newFaultyUseCase(env, map[string]repository.FaultRule{
+		"billets.*": {LatencyMS: 500},
+	})
+
+	deadline, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	_, err := faulty.RunReconciliation(deadline, usecase.ReconciliationParams{})
+	return expect(errors.Is(err, context.DeadlineExceeded),
+		"RunReconciliation: esperado estouro do prazo, obtido %v", err)
+}
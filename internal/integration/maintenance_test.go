@@ -0,0 +1,178 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database/repository"
+)
+
+// TestMaintenance verifica os expurgos da rotina de manutenção
+func TestMaintenance(t *testing.T) {
+	runChecks(t, []Check{
+		{Name: "PurgesExpiredRecords", Run: checkMaintenancePurge},
+		{Name: "AuditTrailAndPayerData", Run: checkMaintenanceAuditAndPayerData},
+	})
+}
+
+func checkMaintenancePurge(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	now := time.Date(2024, time.June, 1, 3, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, 0, -120)
+	recent := now.AddDate(0, 0, -2)
+
+	// Execuções: concluída antiga (expurgada), concluída recente (só perde a chave) e em andamento antiga (mantida)
+	_, err := env.DB.ExecContext(ctx, `
+		INSERT INTO bank_reconciliation.reconciliation_runs (id, params_hash, status, started_at, finished_at)
+		VALUES ('run-antiga', 'hash-antiga', 'concluida', $1, $1),
+			('run-recente', 'hash-recente', 'concluida', $2, $2),
+			('run-andamento', 'hash-andamento', 'em_andamento', $1, NULL)
+	`, old, recent)
+	if err != nil {
+		return fmt.Errorf("inserir execuções: %w", err)
+	}
+
+	// Entregas: entregue antiga (expurgada), com falha antiga (mantida para reenvio) e entregue recente (mantida)
+	_, err = env.DB.ExecContext(ctx, `
+		INSERT INTO bank_reconciliation.event_deliveries (id, subscription_id, events, status, next_attempt_at, delivered_at, created_at, updated_at)
+		VALUES ('d-antiga', 's1', '[]', 'entregue', $1, $1, $1, $1),
+			('d-falha', 's1', '[]', 'falha', $1, NULL, $1, $1),
+			('d-recente', 's1', '[]', 'entregue', $2, $2, $2, $2)
+	`, old, recent)
+	if err != nil {
+		return fmt.Errorf("inserir entregas: %w", err)
+	}
+
+	_, err = env.DB.ExecContext(ctx, `
+		INSERT INTO bank_reconciliation.pending_snapshots
+			(id, snapshot_date, pending_count, pending_amount, exited_count, entered_count, pending_billet_ids, created_at)
+		VALUES ('snap-antigo', '2022-01-01', 0, 0, 0, 0, '[]', $1),
+			('snap-recente', $2, 0, 0, 0, 0, '[]', $2)
+	`, old, recent)
+	if err != nil {
+		return fmt.Errorf("inserir retratos: %w", err)
+	}
+
+	_, err = env.DB.ExecContext(ctx, `
+		INSERT INTO bank_reconciliation.billet_claims (billet_id, claimed_by, claimed_at, expires_at)
+		VALUES ('b1', 'analista', $1, $1), ('b2', 'analista', $2, $3)
+	`, recent, now.Add(-time.Minute), now.Add(time.Hour))
+	if err != nil {
+		return fmt.Errorf("inserir bloqueios: %w", err)
+	}
+
+	uc := usecase.NewMaintenanceUseCase(map[string]domainRepo.MaintenanceRepository{
+		"default": repository.NewMaintenanceRepository(env.Shards),
+	}, nil, model.DefaultRetentionPolicy())
+
+	report, err := uc.RunMaintenance(ctx, now)
+	if err != nil {
+		return fmt.Errorf("RunMaintenance: %w", err)
+	}
+
+	want := model.PurgeCounts{Runs: 1, IdempotencyKeys: 0, EventDeliveries: 1, PendingSnapshots: 1, ExpiredClaims: 1}
+	if err := expect(report.Removed == want, "RunMaintenance: esperado %+v, obtido %+v", want, report.Removed); err != nil {
+		return err
+	}
+
+	// Execução recente fora da validade da chave de idempotência
+	report, err = usecase.NewMaintenanceUseCase(map[string]domainRepo.MaintenanceRepository{
+		"default": repository.NewMaintenanceRepository(env.Shards),
+	}, nil, model.RetentionPolicy{IdempotencyKeys: 24 * time.Hour}).RunMaintenance(ctx, now)
+	if err != nil {
+		return fmt.Errorf("RunMaintenance: %w", err)
+	}
+	if err := expect(report.Removed.IdempotencyKeys == 1 && report.Removed.Runs == 0,
+		"RunMaintenance: esperada uma chave expirada, obtido %+v", report.Removed); err != nil {
+		return err
+	}
+
+	var remaining int
+	if err := env.DB.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM bank_reconciliation.reconciliation_runs WHERE params_hash IS NOT NULL
+	`).Scan(&remaining); err != nil {
+		return fmt.Errorf("contar chaves: %w", err)
+	}
+	return expect(remaining == 1, "RunMaintenance: apenas a execução em andamento deveria manter a chave, restam %d", remaining)
+}
+
+func checkMaintenanceAuditAndPayerData(ctx context.Context, env *Env) error {
+	now := time.Date(2024, time.June, 1, 3, 0, 0, 0, time.UTC)
+	old := now.AddDate(-6, 0, 0)
+
+	// b1 foi quitado há mais de dois anos e b2 recentemente; b3 segue em aberto
+	for _, id := range []string{"b1", "b2", "b3"} {
+		billet := model.NewBillet(id, "conta-1", 10, day(1), stringPtr("REF-"+id))
+		billet.PayerDocument = "11222333000181"
+		if _, err := env.Billets.Create(ctx, billet); err != nil {
+			return fmt.Errorf("Create: %w", err)
+		}
+	}
+	for _, id := range []string{"p1", "p2"} {
+		payment := model.NewPayment(id, "conta-1", 10, day(2), nil)
+		payment.PayerDocument = "11222333000181"
+		if _, err := env.Payments.Create(ctx, payment); err != nil {
+			return fmt.Errorf("Create pagamento: %w", err)
+		}
+	}
+
+	settledLongAgo := newMatch("b1", "p1", model.StatusSuccessful, 0)
+	settledLongAgo.ReconciliationDate = now.AddDate(-3, 0, 0)
+	settledRecently := newMatch("b2", "p2", model.StatusSuccessful, 0)
+	settledRecently.ReconciliationDate = now.AddDate(0, -1, 0)
+	if err := env.Reconciliations.CreateMany(ctx, []*model.Reconciliation{settledLongAgo, settledRecently}); err != nil {
+		return fmt.Errorf("CreateMany: %w", err)
+	}
+
+	// Trilha de auditoria: entrada da linha do tempo antiga (expurgada) e recente (mantida)
+	_, err := env.DB.ExecContext(ctx, `
+		INSERT INTO bank_reconciliation.timeline_entries (id, entity_type, entity_id, kind, description, occurred_at)
+		VALUES ('t-antiga', 'boleto', 'b1', 'comentario', 'antiga', $1),
+			('t-recente', 'boleto', 'b1', 'comentario', 'recente', $2)
+	`, old, now.AddDate(0, 0, -1))
+	if err != nil {
+		return fmt.Errorf("inserir linha do tempo: %w", err)
+	}
+
+	uc := usecase.NewMaintenanceUseCase(map[string]domainRepo.MaintenanceRepository{
+		"default": repository.NewMaintenanceRepository(env.Shards),
+	}, repository.NewMaintenanceReportRepository(env.Shards.Default()), model.DefaultRetentionPolicy())
+
+	report, err := uc.RunMaintenance(ctx, now)
+	if err != nil {
+		return fmt.Errorf("RunMaintenance: %w", err)
+	}
+	if err := expect(report.Removed.AuditRecords == 1 && report.Removed.AnonymizedBillets == 1 && report.Removed.AnonymizedPayments == 1,
+		"RunMaintenance: esperados 1 registro de auditoria, 1 boleto e 1 pagamento, obtido %+v", report.Removed); err != nil {
+		return err
+	}
+
+	billets, err := env.Billets.GetByIDs(ctx, []string{"b1", "b2", "b3"})
+	if err != nil {
+		return fmt.Errorf("GetByIDs: %w", err)
+	}
+	for _, billet := range billets {
+		if anonymized := billet.PayerDocument == ""; anonymized != (billet.ID == "b1") {
+			return fmt.Errorf("RunMaintenance: documento do pagador do boleto %s: %q", billet.ID, billet.PayerDocument)
+		}
+	}
+
+	// O relatório gravado registra a política aplicada e o que foi anonimizado
+	reports, err := uc.ListReports(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("ListReports: %w", err)
+	}
+	return expect(len(reports) == 1 && reports[0].ID == report.ID && reports[0].Removed == report.Removed &&
+		reports[0].RetentionDays["payer_data"] == 730,
+		"ListReports: relatório gravado difere do retornado: %+v", reports)
+}
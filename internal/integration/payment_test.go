@@ -0,0 +1,294 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/infrastructure/database/repository"
+	pkgErrors "conciliacao-bancaria/pkg/errors"
+)
+
+// TestPaymentRepository verifica todos os métodos do repositório de pagamentos
+func TestPaymentRepository(t *testing.T) {
+	runChecks(t, []Check{
+		{Name: "CreateAndGetByID", Run: checkPaymentCreateAndGet},
+		{Name: "CreateManyRollsBackOnDuplicate", Run: checkPaymentCreateManyRollback},
+		{Name: "Filters", Run: checkPaymentFilters},
+		{Name: "FindByBankAccountAndAmount", Run: checkPaymentFindByAmount},
+		{Name: "UpdateAndDelete", Run: checkPaymentUpdateAndDelete},
+		{Name: "MissingRecord", Run: checkPaymentMissing},
+		{Name: "FindNonReconciledAndReconciledAmounts", Run: checkPaymentReconciledState},
+		{Name: "ReviewStatus", Run: checkPaymentReviewStatus},
+		{Name: "MessageRedeliveryDiscarded", Run: checkPaymentMessageRedelivery},
+	})
+}
+
+// newDebit cria um lançamento de débito para os cenários
+func newDebit(id, bankAccount string, amount float64) *model.Payment {
+	payment := model.NewPayment(id, bankAccount, amount, day(3), nil)
+	payment.EntryType = model.EntryTypeDebit
+	return payment
+}
+
+func checkPaymentCreateAndGet(ctx context.Context, env *Env) error {
+	payment := model.NewPayment("p1", "conta-1", 42.5, day(2), stringPtr("REF-1"))
+	payment.PaymentMethod = model.PaymentMethodPix
+	if _, err := env.Payments.Create(ctx, payment); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+
+	stored, err := env.Payments.GetByID(ctx, "p1")
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+
+	return expect(stored != nil && stored.BankAccount == "conta-1" && stored.Amount == 42.5 &&
+		stored.PaymentDate.Equal(day(2)) && stored.ReferenceID != nil && *stored.ReferenceID == "REF-1" &&
+		stored.EntryType == model.EntryTypeCredit && stored.ReviewStatus == model.ReviewStatusNone &&
+		stored.PaymentMethod == model.PaymentMethodPix,
+		"GetByID: pagamento lido difere do gravado: %+v", stored)
+}
+
+func checkPaymentCreateManyRollback(ctx context.Context, env *Env) error {
+	if err := env.Payments.CreateMany(ctx, []*model.Payment{
+		model.NewPayment("p1", "conta-1", 10, day(2), nil),
+		model.NewPayment("p2", "conta-1", 20, day(2), nil),
+	}); err != nil {
+		return fmt.Errorf("CreateMany: %w", err)
+	}
+
+	// O segundo lote repete p2: nenhum pagamento do lote deve ser gravado
+	if err := env.Payments.CreateMany(ctx, []*model.Payment{
+		model.NewPayment("p3", "conta-1", 30, day(2), nil),
+		model.NewPayment("p2", "conta-1", 20, day(2), nil),
+	}); err == nil {
+		return fmt.Errorf("CreateMany: lote com ID duplicado deveria falhar")
+	}
+
+	payments, err := env.Payments.GetAll(ctx)
+	return expectCount("GetAll após rollback", len(payments), 2, err)
+}
+
+func checkPaymentFilters(ctx context.Context, env *Env) error {
+	if err := env.Payments.CreateMany(ctx, []*model.Payment{
+		model.NewPayment("p1", "conta-1", 10, day(2), stringPtr("REF-1")),
+		model.NewPayment("p2", "conta-2", 20, day(2), stringPtr("REF-1")),
+		newDebit("p3", "conta-1", 5),
+	}); err != nil {
+		return fmt.Errorf("CreateMany: %w", err)
+	}
+
+	byAccount, err := env.Payments.GetByBankAccount(ctx, "conta-1")
+	if err := expectCount("GetByBankAccount", len(byAccount), 2, err); err != nil {
+		return err
+	}
+
+	byReference, err := env.Payments.GetByReferenceID(ctx, "REF-1")
+	if err := expectCount("GetByReferenceID", len(byReference), 2, err); err != nil {
+		return err
+	}
+
+	debits, err := env.Payments.GetByEntryType(ctx, model.EntryTypeDebit)
+	if err := expectCount("GetByEntryType", len(debits), 1, err); err != nil {
+		return err
+	}
+
+	if err := expect(debits[0].ID == "p3" && debits[0].IsDebit(), "GetByEntryType: esperado o débito p3, obtido %+v", debits[0]); err != nil {
+		return err
+	}
+
+	minAmount := 6.0
+	listed, err := env.Payments.List(ctx, model.PaymentFilter{BankAccount: "conta-1", MinAmount: &minAmount})
+	if err := expectCount("List por conta e valor mínimo", len(listed), 1, err); err != nil {
+		return err
+	}
+
+	paged, err := env.Payments.List(ctx, model.PaymentFilter{Limit: 2, Offset: 2})
+	return expectCount("List paginado", len(paged), 1, err)
+}
+
+func checkPaymentFindByAmount(ctx context.Context, env *Env) error {
+	if err := env.Payments.CreateMany(ctx, []*model.Payment{
+		model.NewPayment("p1", "conta-1", 98, day(2), nil),
+		model.NewPayment("p2", "conta-1", 103, day(3), nil),
+		model.NewPayment("p3", "conta-1", 110, day(4), nil),
+		model.NewPayment("p4", "conta-2", 100, day(2), nil),
+	}); err != nil {
+		return fmt.Errorf("CreateMany: %w", err)
+	}
+
+	// Tolerância percentual de 5% sobre 100: apenas p1 e p2 da conta-1
+	payments, err := env.Payments.FindByBankAccountAndAmount(ctx, "conta-1", 100, 5)
+	if err := expectCount("FindByBankAccountAndAmount", len(payments), 2, err); err != nil {
+		return err
+	}
+
+	return expect(payments[0].ID == "p1" && payments[1].ID == "p2",
+		"FindByBankAccountAndAmount: esperados p1 e p2 ordenados por data, obtidos %s e %s", payments[0].ID, payments[1].ID)
+}
+
+func checkPaymentUpdateAndDelete(ctx context.Context, env *Env) error {
+	payment := model.NewPayment("p1", "conta-1", 10, day(2), stringPtr("REF-1"))
+	if _, err := env.Payments.Create(ctx, payment); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+
+	payment.Amount = 11
+	payment.EntryType = model.EntryTypeDebit
+	if _, err := env.Payments.Update(ctx, payment); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	stored, err := env.Payments.GetByID(ctx, "p1")
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+	if err := expect(stored != nil && stored.Amount == 11 && stored.IsDebit(), "Update: alteração não persistida: %+v", stored); err != nil {
+		return err
+	}
+
+	if err := env.Payments.Delete(ctx, "p1"); err != nil {
+		return fmt.Errorf("Delete: %w", err)
+	}
+
+	payments, err := env.Payments.GetAll(ctx)
+	return expectCount("GetAll após Delete", len(payments), 0, err)
+}
+
+func checkPaymentMissing(ctx context.Context, env *Env) error {
+	if _, err := env.Payments.GetByID(ctx, "inexistente"); !pkgErrors.IsNotFoundError(err) {
+		return fmt.Errorf("GetByID: esperado erro de não encontrado, obtido %v", err)
+	}
+
+	if _, err := env.Payments.Update(ctx, model.NewPayment("inexistente", "conta-1", 1, day(2), nil)); !pkgErrors.IsNotFoundError(err) {
+		return fmt.Errorf("Update: esperado erro de não encontrado, obtido %v", err)
+	}
+
+	if err := env.Payments.Delete(ctx, "inexistente"); !pkgErrors.IsNotFoundError(err) {
+		return fmt.Errorf("Delete: esperado erro de não encontrado, obtido %v", err)
+	}
+
+	if _, err := env.Payments.Create(ctx, model.NewPayment("p1", "conta-1", 1, day(2), nil)); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+	if _, err := env.Payments.Create(ctx, model.NewPayment("p1", "conta-1", 1, day(2), nil)); !pkgErrors.IsConflictError(err) {
+		return fmt.Errorf("Create: esperado erro de conflito para ID duplicado, obtido %v", err)
+	}
+
+	return nil
+}
+
+func checkPaymentReconciledState(ctx context.Context, env *Env) error {
+	if _, err := env.Billets.Create(ctx, newContractBillet("b1", "conta-1", 10, "contrato-1")); err != nil {
+		return fmt.Errorf("Billets.Create: %w", err)
+	}
+	if err := env.Payments.CreateMany(ctx, []*model.Payment{
+		model.NewPayment("p1", "conta-1", 10, day(2), nil),
+		model.NewPayment("p2", "conta-1", 20, day(3), nil),
+	}); err != nil {
+		return fmt.Errorf("CreateMany: %w", err)
+	}
+	if err := insertReconciliation(ctx, env.DB, "b1", "p1", "conta-1", string(model.StatusSuccessful)); err != nil {
+		return err
+	}
+
+	nonReconciled, err := env.Payments.FindNonReconciled(ctx)
+	if err := expectCount("FindNonReconciled", len(nonReconciled), 1, err); err != nil {
+		return err
+	}
+	if err := expect(nonReconciled[0].ID == "p2", "FindNonReconciled: esperado p2, obtido %s", nonReconciled[0].ID); err != nil {
+		return err
+	}
+
+	// p2 é de 03/01: fora do período do filtro
+	filtered, err := env.Payments.FindNonReconciledByFilter(ctx, model.PendingFilter{
+		StartDate:    day(1),
+		EndDate:      day(2),
+		BankAccounts: []string{"conta-1"},
+	})
+	if err := expectCount("FindNonReconciledByFilter", len(filtered), 0, err); err != nil {
+		return err
+	}
+
+	amounts, err := env.Payments.GetReconciledAmounts(ctx, "conta-1", 10)
+	if err := expectCount("GetReconciledAmounts", len(amounts), 1, err); err != nil {
+		return err
+	}
+	return expect(amounts[0] == 10, "GetReconciledAmounts: esperado 10, obtido %v", amounts[0])
+}
+
+func checkPaymentReviewStatus(ctx context.Context, env *Env) error {
+	if err := env.Payments.CreateMany(ctx, []*model.Payment{
+		model.NewPayment("p1", "conta-1", 10, day(2), nil),
+		model.NewPayment("p2", "conta-1", 20, day(2), nil),
+	}); err != nil {
+		return fmt.Errorf("CreateMany: %w", err)
+	}
+
+	if err := env.Payments.UpdateReviewStatus(ctx, "p2", model.ReviewStatusSuspicious, stringPtr("valor atípico")); err != nil {
+		return fmt.Errorf("UpdateReviewStatus: %w", err)
+	}
+
+	suspicious, err := env.Payments.GetByReviewStatus(ctx, model.ReviewStatusSuspicious)
+	if err := expectCount("GetByReviewStatus", len(suspicious), 1, err); err != nil {
+		return err
+	}
+
+	return expect(suspicious[0].ID == "p2" && suspicious[0].ReviewReason != nil && *suspicious[0].ReviewReason == "valor atípico",
+		"GetByReviewStatus: revisão não persistida: %+v", suspicious[0])
+}
+
+func checkPaymentMessageRedelivery(ctx context.Context, env *Env) error {
+	// O consumo passa pelas mesmas regras de gravação da API, como a recusa de contas inativas
+	accounts := repository.NewBankAccountRepository(env.Shards)
+	uc := usecase.NewPaymentUseCase(repository.NewActiveAccountPaymentRepository(env.Payments, accounts), repository.NewTimelineRepository(env.Shards))
+
+	outcome, err := uc.ConsumePayment(ctx, "pagamentos", "msg-1", model.NewPayment("p1", "conta-1", 10, day(2), nil))
+	if err != nil {
+		return fmt.Errorf("ConsumePayment: %w", err)
+	}
+	if err := expect(outcome == model.MessageProcessed, "ConsumePayment: esperado %q, obtido %q", model.MessageProcessed, outcome); err != nil {
+		return err
+	}
+
+	// A reentrega da mesma mensagem é descartada, mesmo com outro pagamento no corpo
+	outcome, err = uc.ConsumePayment(ctx, "pagamentos", "msg-1", model.NewPayment("p2", "conta-1", 10, day(2), nil))
+	if err != nil {
+		return fmt.Errorf("ConsumePayment da reentrega: %w", err)
+	}
+	if err := expect(outcome == model.MessageDuplicate, "ConsumePayment da reentrega: esperado %q, obtido %q", model.MessageDuplicate, outcome); err != nil {
+		return err
+	}
+
+	// Uma mensagem nova com pagamento já cadastrado é recusada sem ser registrada
+	_, err = uc.ConsumePayment(ctx, "pagamentos", "msg-2", model.NewPayment("p1", "conta-1", 10, day(2), nil))
+	if err := expect(pkgErrors.IsConflictError(err), "ConsumePayment com pagamento repetido: esperado conflito, obtido %v", err); err != nil {
+		return err
+	}
+
+	message, err := repository.NewProcessedMessageRepository(env.Shards).GetByID(ctx, "pagamentos", "msg-2")
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+	if err := expect(message == nil, "GetByID: mensagem recusada não deveria ser registrada: %+v", message); err != nil {
+		return err
+	}
+
+	inactive := model.NewBankAccount("conta-2")
+	inactive.Deactivate("operador", "conta encerrada")
+	if err := accounts.Save(ctx, inactive); err != nil {
+		return fmt.Errorf("Save: %w", err)
+	}
+	_, err = uc.ConsumePayment(ctx, "pagamentos", "msg-3", model.NewPayment("p3", "conta-2", 10, day(2), nil))
+	if err := expect(pkgErrors.IsValidationError(err), "ConsumePayment em conta inativa: esperado erro de validação, obtido %v", err); err != nil {
+		return err
+	}
+
+	payments, err := env.Payments.GetAll(ctx)
+	return expectCount("GetAll", len(payments), 1, err)
+}
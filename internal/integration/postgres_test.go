@@ -0,0 +1,79 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"conciliacao-bancaria/internal/infrastructure/database"
+)
+
+// DefaultPostgresImage define a imagem do Postgres efêmero usado nas verificações
+const DefaultPostgresImage = "postgres:16-alpine"
+
+// Postgres representa um banco Postgres efêmero em container, com o schema já aplicado
+type Postgres struct {
+	container *postgres.PostgresContainer
+	Conn      *database.Connection
+}
+
+// StartPostgres sobe um Postgres efêmero via testcontainers e aplica o script de schema
+func StartPostgres(ctx context.Context, image, schemaFile string) (*Postgres, error) {
+	container, err := postgres.Run(ctx, image,
+		postgres.WithDatabase("conciliacao"),
+		postgres.WithUsername("postgres"),
+		postgres.WithPassword("postgres"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(time.Minute),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar container do Postgres: %w", err)
+	}
+
+	pg := &Postgres{container: container}
+
+	// O search_path resolve as tabelas que alguns repositórios referenciam sem schema
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable", "search_path="+database.DefaultSchema)
+	if err != nil {
+		pg.Close(ctx)
+		return nil, fmt.Errorf("erro ao obter string de conexão: %w", err)
+	}
+
+	pg.Conn, err = database.Open(dsn)
+	if err != nil {
+		pg.Close(ctx)
+		return nil, err
+	}
+
+	// O schema sandbox é migrado junto com o padrão
+	shards := database.NewShardRouter(pg.Conn)
+	if err := shards.EnableSandbox(); err != nil {
+		pg.Close(ctx)
+		return nil, err
+	}
+	defer shards.Close()
+
+	if _, err := database.MigrateShardsFromFile(ctx, shards, schemaFile); err != nil {
+		pg.Close(ctx)
+		return nil, err
+	}
+
+	return pg, nil
+}
+
+// Close encerra a conexão e remove o container
+func (p *Postgres) Close(ctx context.Context) error {
+	if p.Conn != nil {
+		p.Conn.Close()
+	}
+	return p.container.Terminate(ctx)
+}
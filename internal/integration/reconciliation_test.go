@@ -0,0 +1,437 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/service"
+)
+
+// TestReconciliationRepository verifica todos os métodos do repositório de conciliações
+func TestReconciliationRepository(t *testing.T) {
+	runChecks(t, []Check{
+		{Name: "CreateAndGetByID", Run: checkReconciliationCreateAndGet},
+		{Name: "CreateManyRollsBackOnInvalidBillet", Run: checkReconciliationCreateManyRollback},
+		{Name: "Filters", Run: checkReconciliationFilters},
+		{Name: "UpdateAndDelete", Run: checkReconciliationUpdateAndDelete},
+		{Name: "LinksBilletAndPayment", Run: checkReconciliationLinks},
+		{Name: "PartialPayments", Run: checkReconciliationPartialPayments},
+		{Name: "AggregateGroup", Run: checkReconciliationAggregateGroup},
+		{Name: "MissingRecord", Run: checkReconciliationMissing},
+		{Name: "TimeToReconcileStatistics", Run: checkReconciliationStatistics},
+		{Name: "StatusChanges", Run: checkReconciliationStatusChanges},
+		{Name: "ShadowDivergenceReport", Run: checkReconciliationShadowReport},
+		{Name: "AmbiguousReferences", Run: checkReconciliationAmbiguousReferences},
+	})
+}
+
+// seedReconciliationFixtures grava os boletos e pagamentos referenciados pelas conciliações
+func seedReconciliationFixtures(ctx context.Context, env *Env) error {
+	if err := env.Billets.CreateMany(ctx, []*model.Billet{
+		newContractBillet("b1", "conta-1", 10, "contrato-1"),
+		newContractBillet("b2", "conta-1", 20, "contrato-1"),
+	}); err != nil {
+		return fmt.Errorf("Billets.CreateMany: %w", err)
+	}
+
+	if err := env.Payments.CreateMany(ctx, []*model.Payment{
+		model.NewPayment("p1", "conta-1", 10, day(2), stringPtr("REF-b1")),
+		model.NewPayment("p2", "conta-1", 19.5, day(3), stringPtr("REF-b2")),
+	}); err != nil {
+		return fmt.Errorf("Payments.CreateMany: %w", err)
+	}
+
+	return nil
+}
+
+// newMatch cria uma conciliação entre boleto e pagamento para os cenários
+func newMatch(billetID, transactionID string, status model.ConciliationStatus, amountDiff float64) *model.Reconciliation {
+	return model.NewReconciliation(billetID, stringPtr(transactionID), "conta-1", status,
+		model.StrategyReferenceID, amountDiff, stringPtr("REF-"+billetID))
+}
+
+func checkReconciliationCreateAndGet(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	reconciliation := newMatch("b1", "p1", model.StatusSuccessful, 0)
+	if err := env.Reconciliations.Create(ctx, reconciliation); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+
+	stored, err := env.Reconciliations.GetByID(ctx, reconciliation.ID)
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+
+	return expect(stored.BilletID == "b1" && stored.TransactionID != nil && *stored.TransactionID == "p1" &&
+		stored.ConciliationStatus == model.StatusSuccessful && stored.ConciliationStrategy == model.StrategyReferenceID,
+		"GetByID: conciliação lida difere da gravada: %+v", stored)
+}
+
+func checkReconciliationCreateManyRollback(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	// A segunda conciliação referencia um boleto inexistente: nenhuma deve ser gravada
+	if err := env.Reconciliations.CreateMany(ctx, []*model.Reconciliation{
+		newMatch("b1", "p1", model.StatusSuccessful, 0),
+		newMatch("inexistente", "p2", model.StatusSuccessful, 0),
+	}); err == nil {
+		return fmt.Errorf("CreateMany: lote com boleto inexistente deveria falhar")
+	}
+
+	reconciliations, err := env.Reconciliations.GetAll(ctx)
+	return expectCount("GetAll após rollback", len(reconciliations), 0, err)
+}
+
+func checkReconciliationFilters(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	if err := env.Reconciliations.CreateMany(ctx, []*model.Reconciliation{
+		newMatch("b1", "p1", model.StatusSuccessful, 0),
+		newMatch("b2", "p2", model.StatusDifferentValue, 0.5),
+	}); err != nil {
+		return fmt.Errorf("CreateMany: %w", err)
+	}
+
+	byBillet, err := env.Reconciliations.GetByBilletID(ctx, "b2")
+	if err := expectCount("GetByBilletID", len(byBillet), 1, err); err != nil {
+		return err
+	}
+
+	byTransaction, err := env.Reconciliations.GetByTransactionID(ctx, "p1")
+	if err := expectCount("GetByTransactionID", len(byTransaction), 1, err); err != nil {
+		return err
+	}
+
+	history, err := env.Reconciliations.GetReconciliationHistory(ctx, "b1")
+	return expectCount("GetReconciliationHistory", len(history), 1, err)
+}
+
+func checkReconciliationUpdateAndDelete(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	reconciliation := newMatch("b2", "p2", model.StatusDifferentValue, 0.5)
+	if err := env.Reconciliations.Create(ctx, reconciliation); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+
+	reconciliation.ConciliationStatus = model.StatusSuccessful
+	reconciliation.AmountDiff = 0
+	if err := env.Reconciliations.Update(ctx, reconciliation); err != nil {
+		return fmt.Errorf("Update: %w", err)
+	}
+
+	stored, err := env.Reconciliations.GetByID(ctx, reconciliation.ID)
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+	if err := expect(stored.ConciliationStatus == model.StatusSuccessful && stored.AmountDiff == 0,
+		"Update: alteração não persistida: %+v", stored); err != nil {
+		return err
+	}
+
+	if err := env.Reconciliations.Delete(ctx, reconciliation.ID); err != nil {
+		return fmt.Errorf("Delete: %w", err)
+	}
+
+	reconciliations, err := env.Reconciliations.GetAll(ctx)
+	return expectCount("GetAll após Delete", len(reconciliations), 0, err)
+}
+
+func checkReconciliationPartialPayments(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	// p2 e p3 somam o valor de b2
+	if _, err := env.Payments.Create(ctx, model.NewPayment("p3", "conta-1", 0.5, day(4), stringPtr("REF-b2"))); err != nil {
+		return fmt.Errorf("Payments.Create: %w", err)
+	}
+
+	reconciliation := model.NewReconciliation("b2", stringPtr("p2"), "conta-1", model.StatusPartiallyReconciled,
+		model.StrategyPartialPayment, 0, stringPtr("REF-b2"))
+	reconciliation.TransactionIDs = []string{"p2", "p3"}
+	if err := env.Reconciliations.Create(ctx, reconciliation); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+
+	byTransaction, err := env.Reconciliations.GetByTransactionID(ctx, "p3")
+	if err := expectCount("GetByTransactionID da segunda parte", len(byTransaction), 1, err); err != nil {
+		return err
+	}
+	if err := expect(len(byTransaction[0].TransactionIDs) == 2,
+		"GetByTransactionID: partes da conciliação não persistidas: %+v", byTransaction[0]); err != nil {
+		return err
+	}
+
+	payment, err := env.Payments.GetByID(ctx, "p3")
+	if err != nil {
+		return fmt.Errorf("Payments.GetByID: %w", err)
+	}
+	if err := expect(payment.ReconciliationID == reconciliation.ID && payment.IsReconciled(),
+		"Payments.GetByID: segunda parte sem vínculo com a conciliação: %+v", payment); err != nil {
+		return err
+	}
+
+	// Apenas p1 continua pendente
+	pending, err := env.Payments.FindNonReconciled(ctx)
+	return expectCount("Payments.FindNonReconciled", len(pending), 1, err)
+}
+
+func checkReconciliationAggregateGroup(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	// p3 quita b1 e b2 de uma só vez
+	if _, err := env.Payments.Create(ctx, model.NewPayment("p3", "conta-1", 30, day(4), nil)); err != nil {
+		return fmt.Errorf("Payments.Create: %w", err)
+	}
+
+	groupID := model.NewReconciliationGroupID()
+	var group []*model.Reconciliation
+	for _, billetID := range []string{"b1", "b2"} {
+		reconciliation := newMatch(billetID, "p3", model.StatusSuccessful, 0)
+		reconciliation.ConciliationStrategy = model.StrategyAggregate
+		reconciliation.GroupID = &groupID
+		group = append(group, reconciliation)
+	}
+	if err := env.Reconciliations.CreateMany(ctx, group); err != nil {
+		return fmt.Errorf("CreateMany: %w", err)
+	}
+
+	byTransaction, err := env.Reconciliations.GetByTransactionID(ctx, "p3")
+	if err := expectCount("GetByTransactionID do pagamento do grupo", len(byTransaction), 2, err); err != nil {
+		return err
+	}
+	for _, reconciliation := range byTransaction {
+		if err := expect(reconciliation.GroupID != nil && *reconciliation.GroupID == groupID,
+			"GetByTransactionID: grupo da conciliação não persistido: %+v", reconciliation); err != nil {
+			return err
+		}
+	}
+
+	// Os dois boletos do grupo deixam de estar pendentes
+	pending, err := env.Billets.FindNonReconciled(ctx)
+	return expectCount("Billets.FindNonReconciled", len(pending), 0, err)
+}
+
+func checkReconciliationLinks(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	// O registro nao_conciliado de b2 não cria vínculo
+	reconciliation := newMatch("b1", "p1", model.StatusSuccessful, 0)
+	if err := env.Reconciliations.CreateMany(ctx, []*model.Reconciliation{
+		reconciliation,
+		model.NewNonReconciled(*newContractBillet("b2", "conta-1", 20, "contrato-1"), "execucao-1"),
+	}); err != nil {
+		return fmt.Errorf("CreateMany: %w", err)
+	}
+
+	billet, err := env.Billets.GetByID(ctx, "b1")
+	if err != nil {
+		return fmt.Errorf("Billets.GetByID: %w", err)
+	}
+	if err := expect(billet.ReconciliationID == reconciliation.ID && billet.Status == model.BilletStatusReconciled &&
+		billet.TransactionID != nil && *billet.TransactionID == "p1",
+		"Billets.GetByID: boleto b1 sem vínculo com a conciliação: %+v", billet); err != nil {
+		return err
+	}
+
+	payment, err := env.Payments.GetByID(ctx, "p1")
+	if err != nil {
+		return fmt.Errorf("Payments.GetByID: %w", err)
+	}
+	if err := expect(payment.ReconciliationID == reconciliation.ID && payment.Status == model.PaymentStatusReconciled &&
+		payment.BilletID != nil && *payment.BilletID == "b1",
+		"Payments.GetByID: pagamento p1 sem vínculo com a conciliação: %+v", payment); err != nil {
+		return err
+	}
+
+	pending, err := env.Billets.GetByID(ctx, "b2")
+	if err != nil {
+		return fmt.Errorf("Billets.GetByID: %w", err)
+	}
+	if err := expect(!pending.IsReconciled() && pending.Status == model.BilletStatusIssued,
+		"Billets.GetByID: boleto b2 não conciliado recebeu vínculo: %+v", pending); err != nil {
+		return err
+	}
+
+	// Excluída a conciliação, o boleto volta a ficar sem vínculo
+	if err := env.Reconciliations.Delete(ctx, reconciliation.ID); err != nil {
+		return fmt.Errorf("Delete: %w", err)
+	}
+
+	billet, err = env.Billets.GetByID(ctx, "b1")
+	if err != nil {
+		return fmt.Errorf("Billets.GetByID: %w", err)
+	}
+	return expect(!billet.IsReconciled() && billet.Status == model.BilletStatusIssued && billet.TransactionID == nil,
+		"Billets.GetByID: vínculo de b1 mantido após Delete: %+v", billet)
+}
+
+func checkReconciliationMissing(ctx context.Context, env *Env) error {
+	if reconciliation, err := env.Reconciliations.GetByID(ctx, "inexistente"); err == nil && reconciliation != nil {
+		return fmt.Errorf("GetByID: conciliação inexistente retornada")
+	}
+
+	missing := newMatch("b1", "p1", model.StatusSuccessful, 0)
+	if err := env.Reconciliations.Update(ctx, missing); err == nil {
+		return fmt.Errorf("Update: conciliação inexistente deveria falhar")
+	}
+
+	if err := env.Reconciliations.Delete(ctx, missing.ID); err == nil {
+		return fmt.Errorf("Delete: conciliação inexistente deveria falhar")
+	}
+
+	return nil
+}
+
+func checkReconciliationStatistics(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	reconciliation := newMatch("b1", "p1", model.StatusSuccessful, 0)
+	reconciliation.SetTimeToReconcile(reconciliation.ReconciliationDate.Add(-2 * time.Hour))
+	if err := env.Reconciliations.Create(ctx, reconciliation); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+
+	statistics, err := env.Reconciliations.GetTimeToReconcileStatistics(ctx, model.TimeToReconcileFilter{BankAccount: "conta-1"})
+	if err := expectCount("GetTimeToReconcileStatistics", len(statistics), 1, err); err != nil {
+		return err
+	}
+
+	return expect(statistics[0].Count == 1 && statistics[0].P50Seconds == 7200,
+		"GetTimeToReconcileStatistics: percentis inesperados: %+v", statistics[0])
+}
+
+func checkReconciliationStatusChanges(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	reconciliation := newMatch("b2", "p2", model.StatusDifferentValue, 0.5)
+	if err := env.Reconciliations.Create(ctx, reconciliation); err != nil {
+		return fmt.Errorf("Create: %w", err)
+	}
+
+	change := model.NewReconciliationStatusChange(reconciliation, model.StatusSuccessful, 0, "valor corrigido", "analista")
+	if err := env.Reconciliations.UpdateStatus(ctx, change); err != nil {
+		return fmt.Errorf("UpdateStatus: %w", err)
+	}
+
+	// Repetir a mudança falha: o status anterior já não confere
+	if err := env.Reconciliations.UpdateStatus(ctx, change); err == nil {
+		return fmt.Errorf("UpdateStatus: mudança sobre status desatualizado deveria falhar")
+	}
+
+	stored, err := env.Reconciliations.GetByID(ctx, reconciliation.ID)
+	if err != nil {
+		return fmt.Errorf("GetByID: %w", err)
+	}
+	if err := expect(stored.ConciliationStatus == model.StatusSuccessful && stored.AmountDiff == 0,
+		"UpdateStatus: status não persistido: %+v", stored); err != nil {
+		return err
+	}
+
+	changes, err := env.Reconciliations.GetStatusChanges(ctx, "b2")
+	if err := expectCount("GetStatusChanges", len(changes), 1, err); err != nil {
+		return err
+	}
+
+	return expect(changes[0].PreviousStatus == model.StatusDifferentValue && changes[0].NewStatus == model.StatusSuccessful &&
+		changes[0].PreviousAmountDiff == 0.5 && changes[0].ChangedBy == "analista",
+		"GetStatusChanges: mudança lida difere da gravada: %+v", changes[0])
+}
+
+func checkReconciliationShadowReport(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	// Com tolerância de 1%, o motor shadow não aceita a diferença de 2,5% entre b2 e p2
+	reconciliationUseCase := newReconciliationUseCase(env, nil, service.NewReconciliationServiceWithTolerance(1))
+	result, err := reconciliationUseCase.RunReconciliation(ctx, usecase.ReconciliationParams{})
+	if err != nil {
+		return fmt.Errorf("RunReconciliation: %w", err)
+	}
+	if err := expectCount("boletos conciliados em produção", len(result.ReconciledBillets), 2, nil); err != nil {
+		return err
+	}
+
+	report, err := reconciliationUseCase.GetShadowReport(ctx, result.RunID)
+	if err != nil {
+		return fmt.Errorf("GetShadowReport: %w", err)
+	}
+
+	if err := expect(report.PrimaryReconciled == 2 && report.ShadowReconciled == 1 && report.Agreements == 1 &&
+		report.ByDivergence[model.ShadowOnlyPrimary] == 1,
+		"GetShadowReport: contagens inesperadas: %+v", report); err != nil {
+		return err
+	}
+
+	if err := expect(len(report.Divergences) == 1 && report.Divergences[0].BilletID == "b2" &&
+		report.Divergences[0].ShadowTransactionID == nil && report.Divergences[0].EngineVersion == usecase.DefaultShadowEngineVersion,
+		"GetShadowReport: divergência inesperada: %+v", report.Divergences); err != nil {
+		return err
+	}
+
+	// O resultado shadow não pode alterar a produção: as duas conciliações continuam gravadas
+	reconciliations, err := env.Reconciliations.GetAll(ctx)
+	return expectCount("GetAll após shadow mode", len(reconciliations), 2, err)
+}
+
+func checkReconciliationAmbiguousReferences(ctx context.Context, env *Env) error {
+	// Dois boletos com a mesma referência e um pagamento que não confere com nenhum deles
+	first := model.NewBillet("b1", "conta-1", 10, day(1), stringPtr("REF-dup"))
+	second := model.NewBillet("b2", "conta-1", 10, day(1), stringPtr("REF-dup"))
+	if err := env.Billets.CreateMany(ctx, []*model.Billet{first, second}); err != nil {
+		return fmt.Errorf("Billets.CreateMany: %w", err)
+	}
+	if _, err := env.Payments.Create(ctx, model.NewPayment("p1", "conta-1", 15, day(2), stringPtr("REF-dup"))); err != nil {
+		return fmt.Errorf("Payments.Create: %w", err)
+	}
+
+	result, err := newReconciliationUseCase(env, nil, nil).RunReconciliation(ctx, usecase.ReconciliationParams{})
+	if err != nil {
+		return fmt.Errorf("RunReconciliation: %w", err)
+	}
+	if err := expectCount("referências ambíguas", len(result.AmbiguousReferences), 1, nil); err != nil {
+		return err
+	}
+
+	// Os boletos ambíguos ficam registrados na execução, como as demais pendências
+	reconciliations, err := env.Reconciliations.GetAll(ctx)
+	if err := expectCount("GetAll", len(reconciliations), 2, err); err != nil {
+		return err
+	}
+	for _, reconciliation := range reconciliations {
+		if err := expect(reconciliation.ConciliationStatus == model.StatusAmbiguousRef && reconciliation.RunID != nil &&
+			*reconciliation.RunID == result.RunID && reconciliation.TransactionID == nil,
+			"GetAll: registro de referência ambígua inesperado: %+v", reconciliation); err != nil {
+			return err
+		}
+	}
+
+	// O registro é só histórico: os boletos continuam pendentes para a próxima execução
+	billets, err := env.Billets.FindNonReconciled(ctx)
+	return expectCount("FindNonReconciled após referência ambígua", len(billets), 2, err)
+}
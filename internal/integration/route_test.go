@@ -0,0 +1,1078 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"conciliacao-bancaria/internal/application/usecase"
+	"conciliacao-bancaria/internal/domain/model"
+	"conciliacao-bancaria/internal/domain/service"
+	"conciliacao-bancaria/internal/infrastructure/database/repository"
+	"conciliacao-bancaria/internal/infrastructure/erp"
+	httpapi "conciliacao-bancaria/internal/infrastructure/http"
+	"conciliacao-bancaria/internal/infrastructure/http/dto/response"
+	"conciliacao-bancaria/internal/infrastructure/http/handler"
+	"conciliacao-bancaria/internal/infrastructure/http/middleware"
+	"conciliacao-bancaria/internal/infrastructure/report"
+)
+
+// TestReconciliationRoutes verifica, pela API, os fluxos de conciliação que dependem dos repositórios
+// reais; as demais rotas são testadas sem banco junto ao router, em internal/infrastructure/http
+func TestReconciliationRoutes(t *testing.T) {
+	runChecks(t, []Check{
+		{Name: "ReconcileSpecific", Run: checkRouteReconcileSpecific},
+		{Name: "ReconcileSpecificTolerance", Run: checkRouteReconcileSpecificTolerance},
+		{Name: "ReconcileTolerance", Run: checkRouteReconcileTolerance},
+		{Name: "ReconcileAccountTolerance", Run: checkRouteReconcileAccountTolerance},
+		{Name: "RuleSet", Run: checkRouteRuleSet},
+		{Name: "AuditWorkingPaper", Run: checkRouteAuditWorkingPaper},
+		{Name: "MaxDaysDiff", Run: checkRouteMaxDaysDiff},
+		{Name: "ERPDrift", Run: checkRouteERPDrift},
+		{Name: "ERPSync", Run: checkRouteERPSync},
+		{Name: "ReconciliationRunMetrics", Run: checkRouteReconciliationRunMetrics},
+		{Name: "ReconciliationRuns", Run: checkRouteReconciliationRuns},
+		{Name: "EngineVersionStatistics", Run: checkRouteEngineVersionStatistics},
+		{Name: "ReconcileDryRun", Run: checkRouteReconcileDryRun},
+		{Name: "ManualMatch", Run: checkRouteManualMatch},
+		{Name: "UndoMatch", Run: checkRouteUndoMatch},
+		{Name: "ApproveSuggestedMatch", Run: checkRouteApproveSuggestedMatch},
+		{Name: "ApproveDifferentValue", Run: checkRouteApproveDifferentValue},
+		{Name: "ApproveWithLateCharges", Run: checkRouteApproveWithLateCharges},
+		{Name: "RejectPendingApproval", Run: checkRouteRejectPendingApproval},
+		{Name: "SandboxIsolation", Run: checkRouteSandboxIsolation},
+	})
+}
+
+// auditSigningSeed é a semente da chave Ed25519 que assina os papéis de trabalho nas verificações
+var auditSigningSeed = []byte("papel-de-trabalho-verificacoes-1")
+
+// newRouter monta o router da API com os handlers de boletos, pagamentos, conciliação, conjuntos de
+// regras, papel de trabalho e relatórios de divergência do ERP; os demais handlers não são usados pelas
+// verificações
+func newRouter(env *Env) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	billetUseCase := usecase.NewBilletUseCase(env.Billets, repository.NewTimelineRepository(env.Shards))
+	paymentUseCase := usecase.NewPaymentUseCase(env.Payments, repository.NewTimelineRepository(env.Shards))
+	workingPaperUseCase := usecase.NewWorkingPaperUseCase(env.Billets, env.Payments, env.Reconciliations)
+	signer, _ := report.NewSigner(auditSigningSeed)
+
+	return httpapi.SetupRouter(
+		handler.NewBilletHandler(billetUseCase),
+		handler.NewPaymentHandler(paymentUseCase),
+		handler.NewReconciliationHandler(newFaultyUseCase(env, nil)),
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		handler.NewRuleSetHandler(usecase.NewRuleSetUseCase(repository.NewRuleSetRepository(env.Shards))),
+		nil,
+		handler.NewWorkingPaperHandler(workingPaperUseCase, report.DefaultWorkingPaperTemplate(), signer),
+		nil,
+		handler.NewERPDriftHandler(usecase.NewERPDriftUseCase(nil, env.Billets, repository.NewERPDriftRepository(env.Shards), nil)),
+		nil,
+		nil,
+		nil,
+	)
+}
+
+// serve executa uma requisição no router e retorna a resposta gravada
+func serve(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// serveAs executa uma requisição no router identificando o usuário pelo header X-User-ID
+func serveAs(router *gin.Engine, method, path, body, user string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", user)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// expectStatus verifica o status HTTP de uma resposta
+func expectStatus(operation string, recorder *httptest.ResponseRecorder, want int) error {
+	return expect(recorder.Code == want, "%s: esperado status %d, obtido %d: %s",
+		operation, want, recorder.Code, recorder.Body.String())
+}
+
+func checkRouteReconcileSpecific(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	// Apenas b1 e p1 são informados: b2 e p2 continuam pendentes
+	recorder := serve(newRouter(env), http.MethodPost, "/api/v1/reconciliations/specific",
+		`{"billet_ids":["b1"],"transaction_ids":["p1"]}`)
+	if err := expectStatus("POST /reconciliations/specific", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	var result model.ReconciliationResult
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		return fmt.Errorf("POST /reconciliations/specific: %w", err)
+	}
+	if err := expect(len(result.ReconciledBillets) == 1 && result.ReconciledBillets[0].BilletID == "b1",
+		"POST /reconciliations/specific: esperado apenas b1 conciliado, obtido %+v", result.ReconciledBillets); err != nil {
+		return err
+	}
+
+	billets, err := env.Billets.FindNonReconciled(ctx)
+	return expectCount("FindNonReconciled após conciliação específica", len(billets), 1, err)
+}
+
+func checkRouteReconcileSpecificTolerance(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	// p2 difere 2,5% de b2: fora da tolerância de 1% pedida, dentro da padrão
+	router := newRouter(env)
+	recorder := serve(router, http.MethodPost, "/api/v1/reconciliations/specific",
+		`{"billet_ids":["b2"],"transaction_ids":["p2"],"tolerance":1}`)
+	if err := expectStatus("POST /reconciliations/specific com tolerância de 1%", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	var result model.ReconciliationResult
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		return fmt.Errorf("POST /reconciliations/specific: %w", err)
+	}
+	if err := expect(len(result.ReconciledBillets) == 0 && len(result.NonReconciledBillets) == 1,
+		"POST /reconciliations/specific: b2 não deveria ser conciliado com tolerância de 1%%: %+v", result); err != nil {
+		return err
+	}
+
+	// A execução própria fica registrada com o resultado, e a pendência de b2 é gravada nela
+	run, err := repository.NewReconciliationRunRepository(env.Shards).GetByID(ctx, recorder.Header().Get("X-Reconciliation-Run-ID"))
+	if err != nil {
+		return fmt.Errorf("GetByID da execução: %w", err)
+	}
+	if err := expect(run != nil && run.IsCompleted(), "execução da conciliação específica não registrada: %+v", run); err != nil {
+		return err
+	}
+
+	history, err := env.Reconciliations.GetReconciliationHistory(ctx, "b2")
+	if err := expectCount("GetReconciliationHistory de b2", len(history), 1, err); err != nil {
+		return err
+	}
+	if err := expect(history[0].RunID != nil && *history[0].RunID == run.ID,
+		"GetReconciliationHistory de b2: pendência gravada fora da execução %s", run.ID); err != nil {
+		return err
+	}
+
+	// Sem tolerância informada, a padrão pareia o par com valor diferente, que aguarda aprovação
+	recorder = serve(router, http.MethodPost, "/api/v1/reconciliations/specific",
+		`{"billet_ids":["b2"],"transaction_ids":["p2"]}`)
+	if err := expectStatus("POST /reconciliations/specific com tolerância padrão", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	result = model.ReconciliationResult{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		return fmt.Errorf("POST /reconciliations/specific: %w", err)
+	}
+	return expect(len(result.ReconciledBillets) == 1 && result.ReconciledBillets[0].ConciliationStatus == model.StatusAwaitingApproval,
+		"POST /reconciliations/specific: esperado b2 aguardando aprovação, obtido %+v", result.ReconciledBillets)
+}
+
+func checkRouteReconcileTolerance(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	// p2 difere 2,5% de b2: fora da tolerância de 1% pedida
+	recorder := serve(newRouter(env), http.MethodPost, "/api/v1/reconciliations", `{"tolerance":1}`)
+	if err := expectStatus("POST /reconciliations com tolerância de 1%", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	var result model.ReconciliationResult
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		return fmt.Errorf("POST /reconciliations: %w", err)
+	}
+	if err := expect(len(result.ReconciledBillets) == 1 && result.ReconciledBillets[0].BilletID == "b1" && result.Tolerance == 1,
+		"POST /reconciliations: esperado apenas b1 conciliado com tolerância de 1%%, obtido %+v", result); err != nil {
+		return err
+	}
+
+	// A tolerância aplicada fica registrada na execução
+	run, err := repository.NewReconciliationRunRepository(env.Shards).GetByID(ctx, result.RunID)
+	if err != nil {
+		return fmt.Errorf("GetByID da execução: %w", err)
+	}
+	return expect(run != nil && run.Tolerance == 1, "execução registrada sem a tolerância pedida: %+v", run)
+}
+
+func checkRouteReconcileAccountTolerance(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	router := newRouter(env)
+
+	// A tolerância de uma conta fora das contas da execução é recusada
+	recorder := serve(router, http.MethodPost, "/api/v1/reconciliations",
+		`{"filter_accounts":["conta-2"],"account_tolerances":{"conta-1":1}}`)
+	if err := expectStatus("POST /reconciliations com tolerância de conta fora da execução", recorder, http.StatusBadRequest); err != nil {
+		return err
+	}
+
+	// A tolerância de 1% da conta prevalece sobre a de 5% da execução: p2 difere 2,5% de b2
+	recorder = serve(router, http.MethodPost, "/api/v1/reconciliations", `{"tolerance":5,"account_tolerances":{"conta-1":1}}`)
+	if err := expectStatus("POST /reconciliations com tolerância por conta", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	var result model.ReconciliationResult
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		return fmt.Errorf("POST /reconciliations: %w", err)
+	}
+	return expect(len(result.ReconciledBillets) == 1 && result.ReconciledBillets[0].BilletID == "b1",
+		"POST /reconciliations: esperado apenas b1 conciliado com a tolerância da conta, obtido %+v", result)
+}
+
+func checkRouteRuleSet(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	router := newRouter(env)
+
+	// O conjunto geral aplicaria reference_id, mas o da conta-1 prevalece: apenas conta/valor/data com
+	// tolerância de 1%, que concilia p1 com b1 e deixa p2, 2,5% abaixo de b2, sem par
+	recorder := serve(router, http.MethodPut, "/api/v1/admin/rule-sets", `{"strategies":["reference_id"]}`)
+	if err := expectStatus("PUT /admin/rule-sets geral", recorder, http.StatusOK); err != nil {
+		return err
+	}
+	recorder = serve(router, http.MethodPut, "/api/v1/admin/rule-sets",
+		`{"bank_account":"conta-1","strategies":["conta_valor_data"],"strategy_params":{"conta_valor_data":{"tolerance":1,"max_days_diff":30}}}`)
+	if err := expectStatus("PUT /admin/rule-sets da conta", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	recorder = serve(router, http.MethodPost, "/api/v1/reconciliations", `{}`)
+	if err := expectStatus("POST /reconciliations com conjunto de regras", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	var result model.ReconciliationResult
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		return fmt.Errorf("POST /reconciliations: %w", err)
+	}
+	return expect(len(result.ReconciledBillets) == 1 && result.ReconciledBillets[0].BilletID == "b1" &&
+		result.ReconciledBillets[0].ConciliationStrategy == model.StrategyAccountAmountDate,
+		"POST /reconciliations: esperado apenas b1 conciliado por conta/valor/data, obtido %+v", result)
+}
+
+func checkRouteMaxDaysDiff(ctx context.Context, env *Env) error {
+	// Boleto e pagamento sem referência, de mesmo valor, com cinco meses entre a emissão e o pagamento
+	if _, err := env.Billets.Create(ctx, model.NewBillet("b-antigo", "conta-1", 80, day(1), nil)); err != nil {
+		return fmt.Errorf("Billets.Create: %w", err)
+	}
+	paidAt := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := env.Payments.Create(ctx, model.NewPayment("p-antigo", "conta-1", 80, paidAt, nil)); err != nil {
+		return fmt.Errorf("Payments.Create: %w", err)
+	}
+
+	router := newRouter(env)
+
+	recorder := serve(router, http.MethodPost, "/api/v1/reconciliations", `{"max_days_diff":0}`)
+	if err := expectStatus("POST /reconciliations com janela de datas zerada", recorder, http.StatusUnprocessableEntity); err != nil {
+		return err
+	}
+
+	// Fora da janela de 30 dias, o boleto não é candidato do pagamento
+	recorder = serve(router, http.MethodPost, "/api/v1/reconciliations", `{"max_days_diff":30}`)
+	if err := expectStatus("POST /reconciliations com janela de datas", recorder, http.StatusOK); err != nil {
+		return err
+	}
+	var result model.ReconciliationResult
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		return fmt.Errorf("POST /reconciliations: %w", err)
+	}
+	if err := expect(len(result.ReconciledBillets) == 0 && len(result.NonReconciledBillets) == 1,
+		"POST /reconciliations: esperado b-antigo sem conciliação na janela de 30 dias, obtido %+v", result); err != nil {
+		return err
+	}
+
+	// Sem janela, o valor basta para conciliar
+	recorder = serve(router, http.MethodPost, "/api/v1/reconciliations", `{}`)
+	if err := expectStatus("POST /reconciliations sem janela de datas", recorder, http.StatusOK); err != nil {
+		return err
+	}
+	result = model.ReconciliationResult{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		return fmt.Errorf("POST /reconciliations: %w", err)
+	}
+	return expect(len(result.ReconciledBillets) == 1 && result.ReconciledBillets[0].BilletID == "b-antigo" &&
+		result.ReconciledBillets[0].ConciliationStrategy == model.StrategyAccountAmountDate,
+		"POST /reconciliations: esperado b-antigo conciliado por conta/valor/data, obtido %+v", result)
+}
+
+func checkRouteERPDrift(ctx context.Context, env *Env) error {
+	// Base local: b-igual e b-valor pendentes nos dois lados, b-local pendente apenas na base e
+	// b-conciliado já conciliado localmente
+	if err := env.Billets.CreateMany(ctx, []*model.Billet{
+		model.NewBillet("b-igual", "conta-1", 100, day(1), nil),
+		model.NewBillet("b-valor", "conta-1", 50, day(1), nil),
+		model.NewBillet("b-local", "conta-1", 30, day(1), nil),
+		model.NewBillet("b-conciliado", "conta-1", 20, day(1), nil),
+	}); err != nil {
+		return fmt.Errorf("CreateMany: %w", err)
+	}
+	if _, err := env.Payments.Create(ctx, model.NewPayment("p-conciliado", "conta-1", 20, day(2), nil)); err != nil {
+		return fmt.Errorf("Payments.Create: %w", err)
+	}
+	if err := insertReconciliation(ctx, env.DB, "b-conciliado", "p-conciliado", "conta-1", string(model.StatusSuccessful)); err != nil {
+		return err
+	}
+
+	// API do ERP paginada, com b-novo ausente da base local
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-erp" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		pages := map[string]string{
+			"1": `[{"billet_id":"b-igual","bank_account":"conta-1","amount":100,"issuance_date":"2024-01-01"},
+				{"billet_id":"b-valor","bank_account":"conta-1","amount":55,"issuance_date":"2024-01-01"}]`,
+			"2": `[{"billet_id":"b-conciliado","bank_account":"conta-1","amount":20,"issuance_date":"2024-01-01T00:00:00Z"},
+				{"billet_id":"b-novo","bank_account":"conta-1","amount":70}]`,
+		}
+		body, found := pages[r.URL.Query().Get("page")]
+		if !found {
+			body = `[]`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+	defer api.Close()
+
+	source, err := erp.NewClient(erp.Config{URL: api.URL + "/boletos-em-aberto", Token: "token-erp", PageSize: 2})
+	if err != nil {
+		return fmt.Errorf("erp.NewClient: %w", err)
+	}
+
+	erpDriftUseCase := usecase.NewERPDriftUseCase(source, env.Billets, repository.NewERPDriftRepository(env.Shards), nil)
+	checked, err := erpDriftUseCase.CheckDrift(ctx)
+	if err != nil {
+		return fmt.Errorf("CheckDrift: %w", err)
+	}
+
+	kinds := make(map[string]model.ERPDriftKind)
+	for _, drift := range checked.Drifts {
+		kinds[drift.BilletID] = drift.Kind
+	}
+	if err := expect(checked.ERPOpenCount == 4 && checked.LocalOpenCount == 3 && len(kinds) == 4 &&
+		kinds["b-novo"] == model.DriftMissingLocally && kinds["b-conciliado"] == model.DriftReconciledLocally &&
+		kinds["b-local"] == model.DriftMissingInERP && kinds["b-valor"] == model.DriftAmountMismatch,
+		"CheckDrift: esperada uma divergência de cada tipo, obtido %+v", checked); err != nil {
+		return err
+	}
+
+	router := newRouter(env)
+
+	recorder := serve(router, http.MethodGet, "/api/v1/admin/erp-drift/reports/latest", "")
+	if err := expectStatus("GET /admin/erp-drift/reports/latest", recorder, http.StatusOK); err != nil {
+		return err
+	}
+	var latest model.ERPDriftReport
+	if err := json.Unmarshal(recorder.Body.Bytes(), &latest); err != nil {
+		return fmt.Errorf("GET /admin/erp-drift/reports/latest: %w", err)
+	}
+	return expect(latest.ID == checked.ID && latest.AmountMismatchCount == 1 && len(latest.Drifts) == 4,
+		"GET /admin/erp-drift/reports/latest: esperado o relatório %s, obtido %+v", checked.ID, latest)
+}
+
+func checkRouteERPSync(ctx context.Context, env *Env) error {
+	// Base local: b-alterado pendente, que muda de valor no ERP, e b-conciliado já conciliado localmente
+	if err := env.Billets.CreateMany(ctx, []*model.Billet{
+		model.NewBillet("b-alterado", "conta-1", 100, day(1), nil),
+		model.NewBillet("b-conciliado", "conta-1", 20, day(1), nil),
+	}); err != nil {
+		return fmt.Errorf("CreateMany: %w", err)
+	}
+	if _, err := env.Payments.Create(ctx, model.NewPayment("p-conciliado", "conta-1", 20, day(2), nil)); err != nil {
+		return fmt.Errorf("Payments.Create: %w", err)
+	}
+	if err := insertReconciliation(ctx, env.DB, "b-conciliado", "p-conciliado", "conta-1", string(model.StatusSuccessful)); err != nil {
+		return err
+	}
+
+	// API do ERP: sem cursor, devolve todos os boletos alterados; a partir do cursor, apenas b-novo-2
+	var cursors []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		since := r.URL.Query().Get("updated_since")
+		cursors = append(cursors, since)
+
+		body := `[{"billet_id":"b-novo-2","bank_account":"conta-1","amount":40,"issuance_date":"2024-01-03","updated_at":"2024-01-03T10:00:00-03:00"}]`
+		if since == "" {
+			body = `[{"billet_id":"b-alterado","bank_account":"conta-1","amount":110,"issuance_date":"2024-01-01","updated_at":"2024-01-02T12:00:00Z"},
+				{"billet_id":"b-novo","bank_account":"conta-1","amount":70,"issuance_date":"2024-01-02","payer_name":"Maria Oliveira","updated_at":"2024-01-02T11:00:00Z"},
+				{"billet_id":"b-conciliado","bank_account":"conta-1","amount":25,"issuance_date":"2024-01-01","updated_at":"2024-01-02T09:00:00Z"},
+				{"billet_id":"b-invalido","bank_account":"conta-1","amount":-5,"issuance_date":"2024-01-02","updated_at":"2024-01-02T08:00:00Z"}]`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+	defer api.Close()
+
+	feed, err := erp.NewClient(erp.Config{URL: api.URL + "/boletos-alterados"})
+	if err != nil {
+		return fmt.Errorf("erp.NewClient: %w", err)
+	}
+	erpSyncUseCase := usecase.NewERPSyncUseCase(feed, env.Billets, repository.NewTimelineRepository(env.Shards),
+		repository.NewERPSyncRepository(env.Shards))
+
+	first, err := erpSyncUseCase.Sync(ctx)
+	if err != nil {
+		return fmt.Errorf("Sync: %w", err)
+	}
+	if err := expect(first.CreatedCount == 1 && first.UpdatedCount == 1 && first.SkippedCount == 1 && first.FailedCount == 1 &&
+		first.Cursor != nil && first.Cursor.Equal(time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)),
+		"Sync: esperado um boleto de cada resultado e o cursor na última alteração, obtido %+v", first); err != nil {
+		return err
+	}
+
+	billets, err := env.Billets.GetByIDs(ctx, []string{"b-alterado", "b-novo", "b-conciliado"})
+	if err != nil {
+		return fmt.Errorf("GetByIDs: %w", err)
+	}
+	amounts := make(map[string]float64)
+	for _, billet := range billets {
+		amounts[billet.ID] = billet.Amount
+	}
+	if err := expect(len(amounts) == 3 && amounts["b-alterado"] == 110 && amounts["b-novo"] == 70 && amounts["b-conciliado"] == 20,
+		"Sync: esperado b-novo criado, b-alterado atualizado e b-conciliado mantido, obtido %v", amounts); err != nil {
+		return err
+	}
+
+	// A segunda sincronização parte do cursor gravado
+	second, err := erpSyncUseCase.Sync(ctx)
+	if err != nil {
+		return fmt.Errorf("Sync: %w", err)
+	}
+	return expect(len(cursors) == 2 && cursors[1] == "2024-01-02T12:00:00Z" && second.CreatedCount == 1 &&
+		second.Cursor != nil && second.Cursor.Equal(time.Date(2024, 1, 3, 13, 0, 0, 0, time.UTC)),
+		"Sync: esperada a consulta a partir do cursor, obtido %v e %+v", cursors, second)
+}
+
+func checkRouteAuditWorkingPaper(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	router := newRouter(env)
+
+	// b1 e b2 são conciliados por reference_id; p2 paga 0,50 a menos que b2
+	if err := expectStatus("POST /reconciliations", serve(router, http.MethodPost, "/api/v1/reconciliations", `{}`), http.StatusOK); err != nil {
+		return err
+	}
+
+	path := "/api/v1/reports/audit-working-paper?date_field=payment&start_date=2024-01-01&end_date=2024-01-31"
+	recorder := serveAs(router, http.MethodGet, path, "", "auditor")
+	if err := expectStatus("GET /reports/audit-working-paper", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	body := recorder.Body.String()
+	if err := expect(strings.Contains(body, "\r\nconta-1;2;30.00;29.50;") && strings.Contains(body, "\r\nTOTAL GERAL;2;30.00;29.50;"),
+		"GET /reports/audit-working-paper: totais por conta inesperados: %s", body); err != nil {
+		return err
+	}
+
+	return verifyWorkingPaperSignature(body, "auditor")
+}
+
+// verifyWorkingPaperSignature confere o hash do conteúdo do papel de trabalho e a assinatura Ed25519 do
+// responsável com a chave pública publicada no bloco de assinatura
+func verifyWorkingPaperSignature(body, signer string) error {
+	index := strings.Index(body, "\r\nASSINATURA ELETRONICA\r\n")
+	if index < 0 {
+		return fmt.Errorf("papel de trabalho sem bloco de assinatura: %s", body)
+	}
+	content := body[:index]
+
+	fields := make(map[string]string)
+	for _, line := range strings.Split(body[index:], "\r\n") {
+		if key, value, found := strings.Cut(line, ";"); found {
+			fields[key] = value
+		}
+	}
+
+	hash := sha256.Sum256([]byte(content))
+	if err := expect(fields["HASH_SHA256"] == hex.EncodeToString(hash[:]) && fields["RESPONSAVEL"] == signer,
+		"papel de trabalho: hash ou responsável divergentes: %+v", fields); err != nil {
+		return err
+	}
+
+	publicKey, err := base64.StdEncoding.DecodeString(fields["CHAVE_PUBLICA"])
+	if err != nil {
+		return fmt.Errorf("papel de trabalho: chave pública inválida: %w", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(fields["ASSINATURA"])
+	if err != nil {
+		return fmt.Errorf("papel de trabalho: assinatura inválida: %w", err)
+	}
+
+	message := fields["HASH_SHA256"] + "|" + fields["RESPONSAVEL"] + "|" + fields["DATA_ASSINATURA"]
+	return expect(len(publicKey) == ed25519.PublicKeySize && ed25519.Verify(publicKey, []byte(message), signature),
+		"papel de trabalho: assinatura não confere com a chave pública")
+}
+
+func checkRouteReconciliationRunMetrics(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	router := newRouter(env)
+
+	recorder := serve(router, http.MethodPost, "/api/v1/reconciliations", `{}`)
+	if err := expectStatus("POST /reconciliations", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	var result model.ReconciliationResult
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		return fmt.Errorf("POST /reconciliations: %w", err)
+	}
+
+	recorder = serve(router, http.MethodGet, "/api/v1/reconciliation-runs/"+result.RunID, "")
+	if err := expectStatus("GET /reconciliation-runs/:id", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	var run model.ReconciliationRun
+	if err := json.Unmarshal(recorder.Body.Bytes(), &run); err != nil {
+		return fmt.Errorf("GET /reconciliation-runs/:id: %w", err)
+	}
+	if err := expect(run.Metrics != nil && run.Metrics.CandidatesEvaluated > 0 && run.Metrics.PeakMemoryBytes > 0,
+		"GET /reconciliation-runs/:id: execução sem medições: %+v", run.Metrics); err != nil {
+		return err
+	}
+
+	// b1 e b2 são conciliados por reference_id
+	byReference := run.Metrics.Strategies[model.StrategyReferenceID]
+	if err := expect(byReference != nil && byReference.Matches == 2,
+		"GET /reconciliation-runs/:id: esperadas 2 conciliações por reference_id, obtido %+v", byReference); err != nil {
+		return err
+	}
+
+	recorder = serve(router, http.MethodGet, "/api/v1/reconciliation-runs/inexistente", "")
+	return expectStatus("GET /reconciliation-runs/:id inexistente", recorder, http.StatusNotFound)
+}
+
+func checkRouteEngineVersionStatistics(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	router := newRouter(env)
+
+	// Com tolerância de 1%, b1 é conciliado e b2 fica pendente
+	recorder := serve(router, http.MethodPost, "/api/v1/reconciliations", `{"tolerance":1}`)
+	if err := expectStatus("POST /reconciliations", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	recorder = serve(router, http.MethodGet, "/api/v1/reconciliations/runs?engine_version="+service.EngineVersion, "")
+	if err := expectStatus("GET /reconciliations/runs por versão", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	var runs []model.ReconciliationRun
+	if err := json.Unmarshal(recorder.Body.Bytes(), &runs); err != nil {
+		return fmt.Errorf("GET /reconciliations/runs por versão: %w", err)
+	}
+	if err := expectCount("GET /reconciliations/runs por versão", len(runs), 1, nil); err != nil {
+		return err
+	}
+
+	recorder = serve(router, http.MethodGet, "/api/v1/statistics/engine-versions", "")
+	if err := expectStatus("GET /statistics/engine-versions", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	var statistics []model.EngineVersionStatistics
+	if err := json.Unmarshal(recorder.Body.Bytes(), &statistics); err != nil {
+		return fmt.Errorf("GET /statistics/engine-versions: %w", err)
+	}
+	if err := expectCount("GET /statistics/engine-versions", len(statistics), 1, nil); err != nil {
+		return err
+	}
+	if err := expect(statistics[0].EngineVersion == service.EngineVersion && statistics[0].Total == 2 &&
+		statistics[0].Matched == 1 && statistics[0].NotReconciled == 1 && statistics[0].MatchRate == 50,
+		"GET /statistics/engine-versions: totais inesperados: %+v", statistics[0]); err != nil {
+		return err
+	}
+
+	// Outra versão não tem registros
+	recorder = serve(router, http.MethodGet, "/api/v1/statistics/engine-versions?engine_version=inexistente", "")
+	if err := expectStatus("GET /statistics/engine-versions de outra versão", recorder, http.StatusOK); err != nil {
+		return err
+	}
+	statistics = nil
+	if err := json.Unmarshal(recorder.Body.Bytes(), &statistics); err != nil {
+		return fmt.Errorf("GET /statistics/engine-versions de outra versão: %w", err)
+	}
+	return expectCount("GET /statistics/engine-versions de outra versão", len(statistics), 0, nil)
+}
+
+func checkRouteReconciliationRuns(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	router := newRouter(env)
+
+	recorder := serve(router, http.MethodPost, "/api/v1/reconciliations", `{"filter_accounts":["conta-1"]}`)
+	if err := expectStatus("POST /reconciliations", recorder, http.StatusOK); err != nil {
+		return err
+	}
+	runID := recorder.Header().Get("X-Reconciliation-Run-ID")
+
+	recorder = serve(router, http.MethodGet, "/api/v1/reconciliations/runs?status=concluida", "")
+	if err := expectStatus("GET /reconciliations/runs", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	var runs []model.ReconciliationRun
+	if err := json.Unmarshal(recorder.Body.Bytes(), &runs); err != nil {
+		return fmt.Errorf("GET /reconciliations/runs: %w", err)
+	}
+	if err := expectCount("GET /reconciliations/runs", len(runs), 1, nil); err != nil {
+		return err
+	}
+	if err := expect(runs[0].ID == runID && runs[0].Result == nil,
+		"GET /reconciliations/runs: esperada a execução %s sem o resultado, obtido %+v", runID, runs[0]); err != nil {
+		return err
+	}
+	if err := expect(runs[0].Totals != nil && runs[0].Totals.Reconciled == 1 && runs[0].Totals.PendingApproval == 1,
+		"GET /reconciliations/runs: totais inesperados: %+v", runs[0].Totals); err != nil {
+		return err
+	}
+	if err := expect(runs[0].Params != nil && len(runs[0].Params.BankAccounts) == 1,
+		"GET /reconciliations/runs: parâmetros inesperados: %+v", runs[0].Params); err != nil {
+		return err
+	}
+
+	recorder = serve(router, http.MethodGet, "/api/v1/reconciliations/runs/"+runID, "")
+	if err := expectStatus("GET /reconciliations/runs/:id", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	var detail response.ReconciliationRunItemsResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &detail); err != nil {
+		return fmt.Errorf("GET /reconciliations/runs/:id: %w", err)
+	}
+	if err := expectCount("GET /reconciliations/runs/:id", len(detail.Items), 2, nil); err != nil {
+		return err
+	}
+
+	recorder = serve(router, http.MethodGet, "/api/v1/reconciliations/runs/inexistente", "")
+	return expectStatus("GET /reconciliations/runs/:id inexistente", recorder, http.StatusNotFound)
+}
+
+func checkRouteReconcileDryRun(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	router := newRouter(env)
+
+	recorder := serve(router, http.MethodPost, "/api/v1/reconciliations", `{"dry_run":true,"tolerance":1}`)
+	if err := expectStatus("POST /reconciliations dry_run", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	var result model.ReconciliationResult
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		return fmt.Errorf("POST /reconciliations dry_run: %w", err)
+	}
+	// Com 1% de tolerância, b2 (20) não é conciliado com p2 (19,5)
+	if err := expect(result.DryRun && result.RunID == "" && len(result.ReconciledBillets) == 1,
+		"POST /reconciliations dry_run: resultado inesperado: %+v", result); err != nil {
+		return err
+	}
+	if err := expect(recorder.Header().Get("X-Reconciliation-Run-ID") == "",
+		"POST /reconciliations dry_run: simulação não deveria registrar execução"); err != nil {
+		return err
+	}
+
+	reconciliations, err := env.Reconciliations.GetAll(ctx)
+	if err := expectCount("GetAll após dry_run", len(reconciliations), 0, err); err != nil {
+		return err
+	}
+
+	// A simulação não ocupa os parâmetros: a execução real com a mesma janela concilia normalmente
+	recorder = serve(router, http.MethodPost, "/api/v1/reconciliations", `{"tolerance":1}`)
+	if err := expectStatus("POST /reconciliations", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	// b1 conciliado e b2 registrado como não conciliado
+	reconciliations, err = env.Reconciliations.GetAll(ctx)
+	return expectCount("GetAll após execução", len(reconciliations), 2, err)
+}
+
+func checkRouteManualMatch(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	router := newRouter(env)
+
+	// O operador vincula b1 ao pagamento p2, de outra referência: a diferença de valor é registrada
+	recorder := serve(router, http.MethodPost, "/api/v1/reconciliations/manual", `{"billet_id":"b1","transaction_id":"p2"}`)
+	if err := expectStatus("POST /reconciliations/manual", recorder, http.StatusCreated); err != nil {
+		return err
+	}
+
+	var reconciliation model.Reconciliation
+	if err := json.Unmarshal(recorder.Body.Bytes(), &reconciliation); err != nil {
+		return fmt.Errorf("POST /reconciliations/manual: %w", err)
+	}
+	if err := expect(reconciliation.ConciliationStrategy == model.StrategyManual &&
+		reconciliation.ConciliationStatus == model.StatusDifferentValue && reconciliation.AmountDiff == 9.5,
+		"POST /reconciliations/manual: conciliação inesperada: %+v", reconciliation); err != nil {
+		return err
+	}
+
+	// O pagamento já conciliado não pode ser vinculado a outro boleto
+	recorder = serve(router, http.MethodPost, "/api/v1/reconciliations/manual", `{"billet_id":"b2","transaction_id":"p2"}`)
+	if err := expectStatus("POST /reconciliations/manual com pagamento conciliado", recorder, http.StatusConflict); err != nil {
+		return err
+	}
+
+	recorder = serve(router, http.MethodPost, "/api/v1/reconciliations/manual", `{"billet_id":"b1"}`)
+	return expectStatus("POST /reconciliations/manual sem pagamento", recorder, http.StatusUnprocessableEntity)
+}
+
+func checkRouteUndoMatch(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	router := newRouter(env)
+
+	recorder := serve(router, http.MethodPost, "/api/v1/reconciliations/manual", `{"billet_id":"b1","transaction_id":"p1"}`)
+	if err := expectStatus("POST /reconciliations/manual", recorder, http.StatusCreated); err != nil {
+		return err
+	}
+
+	var reconciliation model.Reconciliation
+	if err := json.Unmarshal(recorder.Body.Bytes(), &reconciliation); err != nil {
+		return fmt.Errorf("POST /reconciliations/manual: %w", err)
+	}
+	path := "/api/v1/reconciliations/" + reconciliation.ID + "/match"
+
+	// O motivo é obrigatório
+	if err := expectStatus("DELETE /reconciliations/:id/match sem motivo", serve(router, http.MethodDelete, path, `{}`), http.StatusUnprocessableEntity); err != nil {
+		return err
+	}
+
+	recorder = serve(router, http.MethodDelete, path, `{"reason":"pagamento de outro cliente"}`)
+	if err := expectStatus("DELETE /reconciliations/:id/match", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	var undos []model.ReconciliationUndo
+	if err := json.Unmarshal(recorder.Body.Bytes(), &undos); err != nil {
+		return fmt.Errorf("DELETE /reconciliations/:id/match: %w", err)
+	}
+	if err := expect(len(undos) == 1 && undos[0].BilletID == "b1" && undos[0].Reason == "pagamento de outro cliente",
+		"DELETE /reconciliations/:id/match: registro de auditoria inesperado: %+v", undos); err != nil {
+		return err
+	}
+
+	// Boleto e pagamento voltam a ficar pendentes
+	billets, err := env.Billets.FindNonReconciled(ctx)
+	if err := expectCount("Billets.FindNonReconciled após desfazer", len(billets), 2, err); err != nil {
+		return err
+	}
+	payments, err := env.Payments.FindNonReconciled(ctx)
+	if err := expectCount("Payments.FindNonReconciled após desfazer", len(payments), 2, err); err != nil {
+		return err
+	}
+
+	recorder = serve(router, http.MethodDelete, path, `{"reason":"repetido"}`)
+	return expectStatus("DELETE /reconciliations/:id/match já desfeita", recorder, http.StatusNotFound)
+}
+
+func checkRouteApproveSuggestedMatch(ctx context.Context, env *Env) error {
+	// Dois boletos de mesmo valor e sem referência disputam o pagamento, recebido semanas depois
+	if err := env.Billets.CreateMany(ctx, []*model.Billet{
+		model.NewBillet("s1", "conta-2", 30, day(1), nil),
+		model.NewBillet("s2", "conta-2", 30, day(2), nil),
+	}); err != nil {
+		return fmt.Errorf("Billets.CreateMany: %w", err)
+	}
+	if _, err := env.Payments.Create(ctx, model.NewPayment("ps", "conta-2", 30, day(20), nil)); err != nil {
+		return fmt.Errorf("Payments.Create: %w", err)
+	}
+
+	router := newRouter(env)
+
+	recorder := serve(router, http.MethodPost, "/api/v1/reconciliations", `{}`)
+	if err := expectStatus("POST /reconciliations", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	// O pareamento de baixa confiança fica sugerido e já reserva o pagamento
+	history, err := env.Reconciliations.GetReconciliationHistory(ctx, "s2")
+	if err := expectCount("GetReconciliationHistory de s2", len(history), 1, err); err != nil {
+		return err
+	}
+	suggested := history[0]
+	if err := expect(suggested.ConciliationStatus == model.StatusSuggested && suggested.Confidence != nil &&
+		*suggested.Confidence < 0.8, "pareamento de s2 deveria ficar sugerido: %+v", suggested); err != nil {
+		return err
+	}
+
+	payments, err := env.Payments.FindNonReconciled(ctx)
+	if err := expectCount("Payments.FindNonReconciled com pareamento sugerido", len(payments), 0, err); err != nil {
+		return err
+	}
+
+	// Sem a identificação do aprovador, a aprovação é recusada
+	path := "/api/v1/reconciliations/" + suggested.ID + "/approve"
+	recorder = serve(router, http.MethodPost, path, ``)
+	if err := expectStatus("POST /reconciliations/:id/approve sem aprovador", recorder, http.StatusBadRequest); err != nil {
+		return err
+	}
+
+	recorder = serveAs(router, http.MethodPost, path, ``, "analista-1")
+	if err := expectStatus("POST /reconciliations/:id/approve", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	var approvals []model.ReconciliationApproval
+	if err := json.Unmarshal(recorder.Body.Bytes(), &approvals); err != nil {
+		return fmt.Errorf("POST /reconciliations/:id/approve: %w", err)
+	}
+	if err := expect(len(approvals) == 1 && approvals[0].NewStatus == model.StatusSuccessful && approvals[0].Approver == "analista-1",
+		"POST /reconciliations/:id/approve: aprovação inesperada: %+v", approvals); err != nil {
+		return err
+	}
+
+	// A aprovação vincula o boleto ao pagamento
+	billet, err := env.Billets.GetByID(ctx, "s2")
+	if err != nil {
+		return fmt.Errorf("GetByID de s2: %w", err)
+	}
+	if err := expect(billet.Status == model.BilletStatusReconciled && billet.ReconciliationID == suggested.ID,
+		"boleto s2 não vinculado após a aprovação: %+v", billet); err != nil {
+		return err
+	}
+
+	recorder = serveAs(router, http.MethodPost, path, ``, "analista-1")
+	return expectStatus("POST /reconciliations/:id/approve já aprovada", recorder, http.StatusConflict)
+}
+
+func checkRouteApproveWithLateCharges(ctx context.Context, env *Env) error {
+	// Boleto vencido pago com a multa e os juros do atraso, num pareamento que aguarda aprovação
+	dueDate := day(10)
+	billet := model.NewBillet("j1", "conta-1", 1000, day(1), nil)
+	billet.DueDate = &dueDate
+	billet.InterestRate = 1
+	billet.FinePercent = 2
+	if _, err := env.Billets.Create(ctx, billet); err != nil {
+		return fmt.Errorf("Billets.Create: %w", err)
+	}
+	if _, err := env.Payments.Create(ctx, model.NewPayment("pj", "conta-1", billet.ExpectedAmount(day(20)), day(20), nil)); err != nil {
+		return fmt.Errorf("Payments.Create: %w", err)
+	}
+
+	pending := model.NewReconciliation("j1", stringPtr("pj"), "conta-1", model.StatusSuggested, model.StrategyAccountAmountDate, 0, nil)
+	if err := env.Reconciliations.Create(ctx, pending); err != nil {
+		return fmt.Errorf("Reconciliations.Create: %w", err)
+	}
+
+	recorder := serveAs(newRouter(env), http.MethodPost, "/api/v1/reconciliations/"+pending.ID+"/approve", ``, "analista-1")
+	if err := expectStatus("POST /reconciliations/:id/approve", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	// Sem diferença contra o valor com encargos, a aprovação torna a conciliação conciliado_com_juros
+	reconciliation, err := env.Reconciliations.GetByID(ctx, pending.ID)
+	if err != nil {
+		return fmt.Errorf("GetByID da conciliação aprovada: %w", err)
+	}
+	return expect(reconciliation.ConciliationStatus == model.StatusPaidWithInterest,
+		"conciliação aprovada com status inesperado: %s", reconciliation.ConciliationStatus)
+}
+
+func checkRouteApproveDifferentValue(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	router := newRouter(env)
+
+	recorder := serve(router, http.MethodPost, "/api/v1/reconciliations", `{}`)
+	if err := expectStatus("POST /reconciliations", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	// O pareamento de b2 com diferença de valor aguarda aprovação, sem vincular o boleto
+	history, err := env.Reconciliations.GetReconciliationHistory(ctx, "b2")
+	if err := expectCount("GetReconciliationHistory de b2", len(history), 1, err); err != nil {
+		return err
+	}
+	pending := history[0]
+	if err := expect(pending.ConciliationStatus == model.StatusAwaitingApproval && pending.AmountDiff == 0.5,
+		"pareamento de b2 deveria aguardar aprovação: %+v", pending); err != nil {
+		return err
+	}
+
+	billet, err := env.Billets.GetByID(ctx, "b2")
+	if err != nil {
+		return fmt.Errorf("GetByID de b2: %w", err)
+	}
+	if err := expect(billet.Status != model.BilletStatusReconciled && billet.ReconciliationID == "",
+		"boleto b2 vinculado antes da aprovação: %+v", billet); err != nil {
+		return err
+	}
+
+	recorder = serveAs(router, http.MethodPost, "/api/v1/reconciliations/"+pending.ID+"/approve",
+		`{"reason":"desconto negociado"}`, "analista-1")
+	if err := expectStatus("POST /reconciliations/:id/approve", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	// A aprovação torna a conciliação valor_diferente e vincula o boleto
+	reconciliation, err := env.Reconciliations.GetByID(ctx, pending.ID)
+	if err != nil {
+		return fmt.Errorf("GetByID da conciliação aprovada: %w", err)
+	}
+	if err := expect(reconciliation.ConciliationStatus == model.StatusDifferentValue,
+		"conciliação aprovada com status inesperado: %s", reconciliation.ConciliationStatus); err != nil {
+		return err
+	}
+
+	billet, err = env.Billets.GetByID(ctx, "b2")
+	if err != nil {
+		return fmt.Errorf("GetByID de b2: %w", err)
+	}
+	if err := expect(billet.Status == model.BilletStatusReconciled && billet.ReconciliationID == pending.ID,
+		"boleto b2 não vinculado após a aprovação: %+v", billet); err != nil {
+		return err
+	}
+
+	recorder = serve(router, http.MethodGet, "/api/v1/reconciliations/billet/b2/approvals", "")
+	if err := expectStatus("GET /reconciliations/billet/:id/approvals", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	var approvals []model.ReconciliationApproval
+	if err := json.Unmarshal(recorder.Body.Bytes(), &approvals); err != nil {
+		return fmt.Errorf("GET /reconciliations/billet/:id/approvals: %w", err)
+	}
+	return expect(len(approvals) == 1 && approvals[0].Decision == model.ApprovalApproved &&
+		approvals[0].PreviousStatus == model.StatusAwaitingApproval && approvals[0].NewStatus == model.StatusDifferentValue &&
+		approvals[0].Approver == "analista-1" && approvals[0].Reason == "desconto negociado",
+		"GET /reconciliations/billet/:id/approvals: aprovações inesperadas: %+v", approvals)
+}
+
+func checkRouteRejectPendingApproval(ctx context.Context, env *Env) error {
+	if err := seedReconciliationFixtures(ctx, env); err != nil {
+		return err
+	}
+
+	router := newRouter(env)
+
+	recorder := serve(router, http.MethodPost, "/api/v1/reconciliations", `{}`)
+	if err := expectStatus("POST /reconciliations", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	history, err := env.Reconciliations.GetReconciliationHistory(ctx, "b2")
+	if err := expectCount("GetReconciliationHistory de b2", len(history), 1, err); err != nil {
+		return err
+	}
+	path := "/api/v1/reconciliations/" + history[0].ID + "/reject"
+
+	// O motivo é obrigatório
+	if err := expectStatus("POST /reconciliations/:id/reject sem motivo",
+		serveAs(router, http.MethodPost, path, `{}`, "analista-1"), http.StatusUnprocessableEntity); err != nil {
+		return err
+	}
+
+	recorder = serveAs(router, http.MethodPost, path, `{"reason":"pagamento de outro boleto"}`, "analista-1")
+	if err := expectStatus("POST /reconciliations/:id/reject", recorder, http.StatusOK); err != nil {
+		return err
+	}
+
+	// O pagamento volta a ficar em aberto e a rejeição fica registrada
+	payments, err := env.Payments.FindNonReconciled(ctx)
+	if err := expectCount("Payments.FindNonReconciled após rejeitar", len(payments), 1, err); err != nil {
+		return err
+	}
+
+	approvals, err := env.Reconciliations.GetApprovals(ctx, "b2")
+	if err := expectCount("GetApprovals de b2", len(approvals), 1, err); err != nil {
+		return err
+	}
+	if err := expect(approvals[0].Decision == model.ApprovalRejected && approvals[0].Approver == "analista-1",
+		"GetApprovals de b2: rejeição inesperada: %+v", approvals[0]); err != nil {
+		return err
+	}
+
+	// Um pareamento já conciliado não aguarda decisão
+	history, err = env.Reconciliations.GetReconciliationHistory(ctx, "b1")
+	if err := expectCount("GetReconciliationHistory de b1", len(history), 1, err); err != nil {
+		return err
+	}
+	recorder = serveAs(router, http.MethodPost, "/api/v1/reconciliations/"+history[0].ID+"/reject",
+		`{"reason":"engano"}`, "analista-1")
+	return expectStatus("POST /reconciliations/:id/reject de conciliação definitiva", recorder, http.StatusConflict)
+}
+
+func checkRouteSandboxIsolation(ctx context.Context, env *Env) error {
+	router := newRouter(env)
+
+	serveIn := func(environment, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(middleware.EnvironmentHeader, environment)
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	payment := `{"transaction_id":"p-sandbox","bank_account":"conta-1","amount":10,"payment_date":"2024-01-02T00:00:00Z","entry_type":"credito"}`
+	if err := expectStatus("POST /payments no sandbox", serveIn("sandbox", http.MethodPost, "/api/v1/payments", payment), http.StatusCreated); err != nil {
+		return err
+	}
+
+	// O pagamento gravado no sandbox não aparece em produção
+	if err := expectStatus("GET /payments/p-sandbox em produção", serve(router, http.MethodGet, "/api/v1/payments/p-sandbox", ""), http.StatusNotFound); err != nil {
+		return err
+	}
+	if err := expectStatus("GET /payments/p-sandbox no sandbox", serveIn("sandbox", http.MethodGet, "/api/v1/payments/p-sandbox", ""), http.StatusOK); err != nil {
+		return err
+	}
+
+	// O reset das verificações limpa apenas as tabelas de produção
+	return expectStatus("DELETE /payments/p-sandbox no sandbox", serveIn("sandbox", http.MethodDelete, "/api/v1/payments/p-sandbox", ""), http.StatusNoContent)
+}
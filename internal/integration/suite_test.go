@@ -0,0 +1,155 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domainRepo "conciliacao-bancaria/internal/domain/repository"
+	"conciliacao-bancaria/internal/infrastructure/database"
+	"conciliacao-bancaria/internal/infrastructure/database/repository"
+)
+
+// Env reúne os repositórios verificados e o acesso direto ao banco para montar cenários
+type Env struct {
+	DB              *sql.DB
+	Shards          *database.ShardRouter
+	Billets         domainRepo.BilletRepository
+	Payments        domainRepo.PaymentRepository
+	Reconciliations domainRepo.ReconciliationRepository
+}
+
+// Check descreve uma verificação de integração de um método de repositório
+type Check struct {
+	Name string
+	Run  func(ctx context.Context, env *Env) error
+}
+
+// Flags da suíte, informadas após -args no go test
+var (
+	postgresImage = flag.String("postgres-image", DefaultPostgresImage, "imagem do Postgres usada no container")
+	schemaFile    = flag.String("schema", filepath.Join("..", "..", database.DefaultSchemaFile), "caminho do script de schema")
+)
+
+// testEnv é o ambiente compartilhado pelas verificações, montado em TestMain
+var testEnv *Env
+
+// TestMain sobe um Postgres efêmero para a suíte de integração (requer Docker).
+//
+// Uso:
+//
+//	go test -tags integration ./internal/integration -run TestBilletRepository
+func TestMain(m *testing.M) {
+	flag.Parse()
+	ctx := context.Background()
+
+	pg, err := StartPostgres(ctx, *postgresImage, *schemaFile)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	shards := database.NewShardRouter(pg.Conn)
+	if err := shards.EnableSandbox(); err != nil {
+		pg.Close(ctx)
+		log.Fatalf("%v", err)
+	}
+	testEnv = &Env{
+		DB:              pg.Conn.DB,
+		Shards:          shards,
+		Billets:         repository.NewBilletRepository(shards),
+		Payments:        repository.NewPaymentRepository(shards),
+		Reconciliations: repository.NewReconciliationRepository(shards),
+	}
+
+	code := m.Run()
+
+	shards.Close()
+	if err := pg.Close(ctx); err != nil {
+		log.Printf("erro ao encerrar o Postgres: %v", err)
+	}
+	os.Exit(code)
+}
+
+// runChecks executa cada verificação como um subteste, sobre tabelas vazias
+func runChecks(t *testing.T, checks []Check) {
+	for _, check := range checks {
+		t.Run(check.Name, func(t *testing.T) {
+			ctx := context.Background()
+			if err := reset(ctx, testEnv.DB); err != nil {
+				t.Fatal(err)
+			}
+			if err := check.Run(ctx, testEnv); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// reset esvazia as tabelas usadas pelas verificações
+func reset(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		TRUNCATE bank_reconciliation.reconciliations, bank_reconciliation.reconciliation_undos,
+			bank_reconciliation.billet_claims, bank_reconciliation.reconciliation_runs,
+			bank_reconciliation.daily_closings, bank_reconciliation.event_deliveries,
+			bank_reconciliation.pending_snapshots, bank_reconciliation.processed_messages,
+			bank_reconciliation.erp_drift_reports, bank_reconciliation.erp_sync_states,
+			bank_reconciliation.maintenance_reports,
+			bank_reconciliation.shadow_reconciliations, bank_reconciliation.reconciliation_approvals,
+			bank_reconciliation.rule_sets, bank_reconciliation.notification_templates,
+			bank_reconciliation.timeline_entries, bank_reconciliation.bank_accounts,
+			bank_reconciliation.unapplied_credit_applications, bank_reconciliation.unapplied_credits,
+			bank_reconciliation.payments, bank_reconciliation.billets CASCADE
+	`)
+	if err != nil {
+		return fmt.Errorf("erro ao limpar tabelas: %w", err)
+	}
+	return nil
+}
+
+// insertReconciliation registra uma conciliação diretamente no banco, sem depender do repositório de conciliações
+func insertReconciliation(ctx context.Context, db *sql.DB, billetID, transactionID, bankAccount, status string) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO bank_reconciliation.reconciliations (
+			id, billet_id, transaction_id, bank_account, conciliation_status, conciliation_strategy,
+			amount_diff, reconciliation_date
+		) VALUES ($1, $2, $3, $4, $5, 'reference_id', 0, $6)
+	`, "rec-"+billetID, billetID, transactionID, bankAccount, status, time.Now())
+	if err != nil {
+		return fmt.Errorf("erro ao preparar conciliação: %w", err)
+	}
+	return nil
+}
+
+// expect retorna um erro descritivo quando a condição não é atendida
+func expect(condition bool, format string, args ...interface{}) error {
+	if condition {
+		return nil
+	}
+	return fmt.Errorf(format, args...)
+}
+
+// expectCount verifica a quantidade de registros retornada por uma consulta
+func expectCount(operation string, got, want int, err error) error {
+	if err != nil {
+		return fmt.Errorf("%s: %w", operation, err)
+	}
+	return expect(got == want, "%s: esperados %d registros, obtidos %d", operation, want, got)
+}
+
+// stringPtr retorna um ponteiro para a string informada
+func stringPtr(value string) *string {
+	return &value
+}
+
+// day retorna a data do dia informado de janeiro de 2024, usada nos cenários
+func day(n int) time.Time {
+	return time.Date(2024, time.January, n, 0, 0, 0, 0, time.UTC)
+}
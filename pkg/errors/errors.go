@@ -3,6 +3,7 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // Erros básicos para reutilização
@@ -39,6 +40,19 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("erro de validação: %s", e.Message)
 }
 
+// ValidationErrors agrega os erros de validação de todos os campos de uma requisição
+type ValidationErrors struct {
+	Errors []*ValidationError
+}
+
+func (e *ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
 // ConflictError representa erro de conflito (recurso já existe, etc)
 type ConflictError struct {
 	Resource string
@@ -63,6 +77,21 @@ func (e *DatabaseError) Error() string {
 	return fmt.Sprintf("erro na operação '%s' do banco de dados: %v", e.Operation, e.Err)
 }
 
+// Unwrap permite inspecionar o erro de origem com errors.Is/errors.As
+func (e *DatabaseError) Unwrap() error {
+	return e.Err
+}
+
+// ForbiddenError representa acesso a um recurso fora do escopo permitido ao usuário/API key
+type ForbiddenError struct {
+	Resource string
+	ID       string
+}
+
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("acesso negado a %s com ID %s", e.Resource, e.ID)
+}
+
 // NewNotFoundError cria um novo erro de recurso não encontrado
 func NewNotFoundError(resource, id string) *NotFoundError {
 	return &NotFoundError{
@@ -79,6 +108,13 @@ func NewValidationError(field, message string) *ValidationError {
 	}
 }
 
+// NewValidationErrors cria um novo erro com os erros de validação dos campos
+func NewValidationErrors(errs []*ValidationError) *ValidationErrors {
+	return &ValidationErrors{
+		Errors: errs,
+	}
+}
+
 // NewConflictError cria um novo erro de conflito
 func NewConflictError(resource, id, reason string) *ConflictError {
 	return &ConflictError{
@@ -96,10 +132,18 @@ func NewDatabaseError(operation string, err error) *DatabaseError {
 	}
 }
 
-// IsNotFoundError verifica se um erro é do tipo NotFoundError
+// NewForbiddenError cria um novo erro de acesso negado
+func NewForbiddenError(resource, id string) *ForbiddenError {
+	return &ForbiddenError{
+		Resource: resource,
+		ID:       id,
+	}
+}
+
+// IsNotFoundError verifica se um erro é (ou encapsula) um NotFoundError
 func IsNotFoundError(err error) bool {
-	_, ok := err.(*NotFoundError)
-	return ok
+	var notFound *NotFoundError
+	return errors.As(err, &notFound)
 }
 
 // IsValidationError verifica se um erro é do tipo ValidationError
@@ -108,18 +152,30 @@ func IsValidationError(err error) bool {
 	return ok
 }
 
-// IsConflictError verifica se um erro é do tipo ConflictError
-func IsConflictError(err error) bool {
-	_, ok := err.(*ConflictError)
+// IsValidationErrors verifica se um erro é do tipo ValidationErrors
+func IsValidationErrors(err error) bool {
+	_, ok := err.(*ValidationErrors)
 	return ok
 }
 
+// IsConflictError verifica se um erro é (ou encapsula) um ConflictError
+func IsConflictError(err error) bool {
+	var conflict *ConflictError
+	return errors.As(err, &conflict)
+}
+
 // IsDatabaseError verifica se um erro é do tipo DatabaseError
 func IsDatabaseError(err error) bool {
 	_, ok := err.(*DatabaseError)
 	return ok
 }
 
+// IsForbiddenError verifica se um erro é (ou encapsula) um ForbiddenError
+func IsForbiddenError(err error) bool {
+	var forbidden *ForbiddenError
+	return errors.As(err, &forbidden)
+}
+
 // Wrap adiciona contexto a um erro existente
 func Wrap(err error, message string) error {
 	return fmt.Errorf("%s: %w", message, err)